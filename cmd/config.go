@@ -4,18 +4,26 @@ import (
 	"cloud.google.com/go/profiler"
 	"fmt"
 	"github.com/jinzhu/gorm"
+	"github.com/leandro-lugaresi/hub"
 	"github.com/spf13/viper"
 	"github.com/traPtitech/traQ/repository"
 	"github.com/traPtitech/traQ/router"
 	"github.com/traPtitech/traQ/router/auth"
+	"github.com/traPtitech/traQ/service/channel"
+	"github.com/traPtitech/traQ/service/cluster"
 	"github.com/traPtitech/traQ/service/counter"
+	"github.com/traPtitech/traQ/service/digest"
 	"github.com/traPtitech/traQ/service/fcm"
 	"github.com/traPtitech/traQ/service/imaging"
+	"github.com/traPtitech/traQ/service/ratelimit"
+	"github.com/traPtitech/traQ/service/search"
 	"github.com/traPtitech/traQ/service/variable"
 	"github.com/traPtitech/traQ/utils/storage"
+	"github.com/traPtitech/traQ/utils/webpush"
 	"go.uber.org/zap"
 	"google.golang.org/api/option"
 	"image"
+	"io/ioutil"
 	"time"
 )
 
@@ -137,6 +145,28 @@ type Config struct {
 		} `mapstructure:"serviceAccount" yaml:"serviceAccount"`
 	} `mapstructure:"firebase" yaml:"firebase"`
 
+	// WebPush Web Push(VAPID)設定
+	WebPush struct {
+		// PrivateKey VAPID用のP-256 ECDSA秘密鍵ファイル (空の場合は無効)
+		PrivateKey string `mapstructure:"privateKey" yaml:"privateKey"`
+		// Subject プッシュサービスに通知する連絡先 (mailto:またはhttps:のURI, default: Origin)
+		Subject string `mapstructure:"subject" yaml:"subject"`
+	} `mapstructure:"webPush" yaml:"webPush"`
+
+	// Search メッセージ検索設定
+	Search struct {
+		// Engine 検索エンジン (default: db)
+		//
+		// 	db: データベースのテーブルをインデックスとして用いる
+		// 	memory: プロセス内の転置インデックスを用いる
+		Engine string `mapstructure:"engine" yaml:"engine"`
+		// Memory memoryエンジン設定
+		Memory struct {
+			// File インデックスの保存先ファイル (default: ./search.idx)
+			File string `mapstructure:"file" yaml:"file"`
+		} `mapstructure:"memory" yaml:"memory"`
+	} `mapstructure:"search" yaml:"search"`
+
 	// OAuth2 OAuth2認可サーバー設定
 	OAuth2 struct {
 		// IsRefreshEnabled リフレッシュトークンを有効にするかどうか (default: false)
@@ -172,6 +202,54 @@ type Config struct {
 		} `mapstructure:"keys" yaml:"keys"`
 	} `mapstructure:"jwt" yaml:"jwt"`
 
+	// RateLimit BOT・Webhookのレート制限設定
+	//
+	// 制限はノード毎に適用されます。
+	RateLimit struct {
+		// Bot BOTユーザーの制限
+		Bot struct {
+			// PostMessage メッセージ投稿 (default: rate=1, burst=10)
+			PostMessage RateLimitConfig `mapstructure:"postMessage" yaml:"postMessage"`
+			// AddStamp スタンプ押下 (default: rate=5, burst=30)
+			AddStamp RateLimitConfig `mapstructure:"addStamp" yaml:"addStamp"`
+			// UploadFile ファイルアップロード (default: rate=0.2, burst=5)
+			UploadFile RateLimitConfig `mapstructure:"uploadFile" yaml:"uploadFile"`
+		} `mapstructure:"bot" yaml:"bot"`
+		// Webhook Webhookの制限
+		Webhook struct {
+			// PostMessage メッセージ投稿 (default: rate=1, burst=10)
+			PostMessage RateLimitConfig `mapstructure:"postMessage" yaml:"postMessage"`
+		} `mapstructure:"webhook" yaml:"webhook"`
+	} `mapstructure:"rateLimit" yaml:"rateLimit"`
+
+	// Cluster 複数ノード構成設定
+	Cluster struct {
+		// Enabled 有効かどうか (default: false)
+		//
+		// 無効の場合は他ノードと状態を共有しません
+		Enabled bool `mapstructure:"enabled" yaml:"enabled"`
+		// ListenAddr ノード間通信の待ち受けアドレス (default: :3010)
+		ListenAddr string `mapstructure:"listenAddr" yaml:"listenAddr"`
+		// Peers 他ノードのノード間通信のベースURL (例: http://traq-1:3010)
+		Peers []string `mapstructure:"peers" yaml:"peers"`
+		// Secret ノード間通信の署名用共有シークレット
+		Secret string `mapstructure:"secret" yaml:"secret"`
+	} `mapstructure:"cluster" yaml:"cluster"`
+
+	// SMTP ダイジェストメール送信用SMTPサーバー設定
+	SMTP struct {
+		// Host ホスト (空の場合はダイジェストメールを送信しない)
+		Host string `mapstructure:"host" yaml:"host"`
+		// Port ポート (default: 587)
+		Port int `mapstructure:"port" yaml:"port"`
+		// Username 認証ユーザー名 (空の場合は認証しない)
+		Username string `mapstructure:"username" yaml:"username"`
+		// Password 認証パスワード
+		Password string `mapstructure:"password" yaml:"password"`
+		// From 送信元メールアドレス
+		From string `mapstructure:"from" yaml:"from"`
+	} `mapstructure:"smtp" yaml:"smtp"`
+
 	// ExternalAuth 外部認証設定
 	ExternalAuth struct {
 		GitHub struct {
@@ -200,6 +278,14 @@ type Config struct {
 	} `mapstructure:"externalAuth" yaml:"externalAuth"`
 }
 
+// RateLimitConfig トークンバケットによるレート制限設定
+type RateLimitConfig struct {
+	// Rate 1秒あたりに補充されるトークン数
+	Rate float64 `mapstructure:"rate" yaml:"rate"`
+	// Burst バケットの容量. 0以下の場合は制限しない
+	Burst int `mapstructure:"burst" yaml:"burst"`
+}
+
 // Configのデフォルト値設定
 func init() {
 	viper.SetDefault("dev", false)
@@ -233,6 +319,8 @@ func init() {
 	viper.SetDefault("gcp.serviceAccount.file", "")
 	viper.SetDefault("gcp.stackdriver.profiler.enabled", false)
 	viper.SetDefault("firebase.serviceAccount.file", "")
+	viper.SetDefault("search.engine", "db")
+	viper.SetDefault("search.memory.file", "./search.idx")
 	viper.SetDefault("oauth2.isRefreshEnabled", false)
 	viper.SetDefault("oauth2.accessTokenExp", 60*60*24*365)
 	viper.SetDefault("externalAuthentication.enabled", false)
@@ -255,8 +343,27 @@ func init() {
 	viper.SetDefault("externalAuth.oidc.clientSecret", "")
 	viper.SetDefault("externalAuth.oidc.scopes", []string{})
 	viper.SetDefault("externalAuth.oidc.allowSignUp", false)
+	viper.SetDefault("webPush.privateKey", "")
+	viper.SetDefault("webPush.subject", "")
 	viper.SetDefault("skyway.secretKey", "")
 	viper.SetDefault("jwt.keys.private", "")
+	viper.SetDefault("rateLimit.bot.postMessage.rate", 1)
+	viper.SetDefault("rateLimit.bot.postMessage.burst", 10)
+	viper.SetDefault("rateLimit.bot.addStamp.rate", 5)
+	viper.SetDefault("rateLimit.bot.addStamp.burst", 30)
+	viper.SetDefault("rateLimit.bot.uploadFile.rate", 0.2)
+	viper.SetDefault("rateLimit.bot.uploadFile.burst", 5)
+	viper.SetDefault("rateLimit.webhook.postMessage.rate", 1)
+	viper.SetDefault("rateLimit.webhook.postMessage.burst", 10)
+	viper.SetDefault("cluster.enabled", false)
+	viper.SetDefault("cluster.listenAddr", ":3010")
+	viper.SetDefault("cluster.peers", []string{})
+	viper.SetDefault("cluster.secret", "")
+	viper.SetDefault("smtp.host", "")
+	viper.SetDefault("smtp.port", 587)
+	viper.SetDefault("smtp.username", "")
+	viper.SetDefault("smtp.password", "")
+	viper.SetDefault("smtp.from", "")
 }
 
 func (c Config) getFileStorage() (storage.FileStorage, error) {
@@ -319,11 +426,85 @@ func initStackdriverProfiler(c *Config) error {
 	}, option.WithCredentialsFile(c.GCP.ServiceAccount.File))
 }
 
-func newFCMClientIfAvailable(repo repository.Repository, logger *zap.Logger, unreadCounter counter.UnreadMessageCounter, file variable.FirebaseCredentialsFilePathString) (fcm.Client, error) {
+func newFCMClientIfAvailable(repo repository.Repository, logger *zap.Logger, unreadCounter counter.UnreadMessageCounter, file variable.FirebaseCredentialsFilePathString, vapid *webpush.VAPID) (fcm.Client, error) {
+	var clients []fcm.Client
 	if len(file) > 0 {
-		return fcm.NewClientWithCredentialsFile(repo, logger, unreadCounter, file)
+		c, err := fcm.NewClientWithCredentialsFile(repo, logger, unreadCounter, file)
+		if err != nil {
+			return nil, err
+		}
+		clients = append(clients, c)
+	}
+	if vapid != nil {
+		clients = append(clients, fcm.NewWebPushClient(repo, logger, unreadCounter, vapid))
+	}
+
+	switch len(clients) {
+	case 0:
+		return fcm.NewNullClient(), nil
+	case 1:
+		return clients[0], nil
+	default:
+		return fcm.NewCompositeClient(clients...), nil
+	}
+}
+
+func newVAPIDIfAvailable(c *Config) (*webpush.VAPID, error) {
+	if len(c.WebPush.PrivateKey) == 0 {
+		return nil, nil
+	}
+	key, err := ioutil.ReadFile(c.WebPush.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	subject := c.WebPush.Subject
+	if len(subject) == 0 {
+		subject = c.Origin
 	}
-	return fcm.NewNullClient(), nil
+	return webpush.NewVAPID(key, subject)
+}
+
+func newSearchEngine(db *gorm.DB, repo repository.Repository, hub *hub.Hub, cm channel.Manager, bus *cluster.Bus, logger *zap.Logger, c *Config) (search.Engine, error) {
+	switch c.Search.Engine {
+	case "db":
+		return search.NewDBEngine(db, repo, hub, logger)
+	case "memory":
+		return search.NewMemoryEngine(c.Search.Memory.File, cm, repo, hub, bus, logger)
+	default:
+		return nil, fmt.Errorf("unknown search engine: %s", c.Search.Engine)
+	}
+}
+
+func newDigestServiceIfAvailable(repo repository.Repository, cm channel.Manager, oc *counter.OnlineCounter, bus *cluster.Bus, origin variable.ServerOriginString, logger *zap.Logger, c *Config) (digest.Service, error) {
+	if len(c.SMTP.Host) == 0 {
+		return digest.NewNullService(), nil
+	}
+	mailer, err := digest.NewSMTPMailer(digest.SMTPConfig{
+		Host:     c.SMTP.Host,
+		Port:     c.SMTP.Port,
+		Username: c.SMTP.Username,
+		Password: c.SMTP.Password,
+		From:     c.SMTP.From,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return digest.NewService(repo, cm, oc, bus, mailer, origin, logger), nil
+}
+
+func newClusterBus(logger *zap.Logger, c *Config) (*cluster.Bus, error) {
+	if !c.Cluster.Enabled {
+		return cluster.NewBus(cluster.NewMemoryNetwork().NewTransport(), logger), nil
+	}
+	t, err := cluster.NewHTTPTransport(cluster.HTTPTransportConfig{
+		ListenAddr: c.Cluster.ListenAddr,
+		Peers:      c.Cluster.Peers,
+		Secret:     c.Cluster.Secret,
+	}, logger)
+	if err != nil {
+		return nil, err
+	}
+	return cluster.NewBus(t, logger), nil
 }
 
 func provideServerOriginString(c *Config) variable.ServerOriginString {
@@ -343,6 +524,19 @@ func provideImageProcessorConfig(c *Config) imaging.Config {
 	}
 }
 
+func provideRateLimitConfig(c *Config) ratelimit.Config {
+	return ratelimit.Config{
+		Bot: map[ratelimit.Action]ratelimit.Limit{
+			ratelimit.PostMessage: ratelimit.Limit(c.RateLimit.Bot.PostMessage),
+			ratelimit.AddStamp:    ratelimit.Limit(c.RateLimit.Bot.AddStamp),
+			ratelimit.UploadFile:  ratelimit.Limit(c.RateLimit.Bot.UploadFile),
+		},
+		Webhook: map[ratelimit.Action]ratelimit.Limit{
+			ratelimit.PostMessage: ratelimit.Limit(c.RateLimit.Webhook.PostMessage),
+		},
+	}
+}
+
 func provideAuthGithubProviderConfig(c *Config) auth.GithubProviderConfig {
 	return auth.GithubProviderConfig{
 		ClientID:               c.ExternalAuth.GitHub.ClientID,
@@ -390,16 +584,22 @@ func provideRouterExternalAuthConfig(c *Config) router.ExternalAuthConfig {
 	}
 }
 
-func provideRouterConfig(c *Config) *router.Config {
+func provideRouterConfig(c *Config, vapid *webpush.VAPID) *router.Config {
+	var webPushPublicKey string
+	if vapid != nil {
+		webPushPublicKey = vapid.PublicKey()
+	}
 	return &router.Config{
 		Development:      c.DevMode,
 		Version:          Version,
 		Revision:         Revision,
+		Origin:           c.Origin,
 		AccessLogging:    c.AccessLog.Enabled,
 		Gzipped:          c.Gzip,
 		AccessTokenExp:   c.OAuth2.AccessTokenExpire,
 		IsRefreshEnabled: c.OAuth2.IsRefreshEnabled,
 		SkyWaySecretKey:  c.SkyWay.SecretKey,
+		WebPushPublicKey: webPushPublicKey,
 		ExternalAuth:     provideRouterExternalAuthConfig(c),
 	}
 }
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/spf13/cobra"
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/service/export"
+	"github.com/traPtitech/traQ/utils/gormzap"
+	"github.com/traPtitech/traQ/utils/optional"
+	"go.uber.org/zap"
+	"os"
+	"time"
+)
+
+// exportCommand データエクスポートコマンド
+func exportCommand() *cobra.Command {
+	var (
+		output       string
+		user         string
+		includeFiles bool
+		since        string
+		until        string
+	)
+
+	cmd := cobra.Command{
+		Use:   "export",
+		Short: "export channels, messages and files to a zip archive",
+		Long:  "export channels, messages (with stamps and edit history) and files metadata to a zip archive of JSON lines. When --user is specified, only the user's DMs and posts are exported.",
+		Run: func(cmd *cobra.Command, args []string) {
+			// Logger
+			logger := getCLILogger()
+			defer logger.Sync()
+
+			// Database
+			db, err := c.getDatabase()
+			if err != nil {
+				logger.Fatal("failed to connect database", zap.Error(err))
+			}
+			db.SetLogger(gormzap.New(logger.Named("gorm")))
+			defer db.Close()
+
+			// FileStorage
+			fs, err := c.getFileStorage()
+			if err != nil {
+				logger.Fatal("failed to setup file storage", zap.Error(err))
+			}
+
+			var opts export.Options
+			opts.IncludeFiles = includeFiles
+			if len(user) > 0 {
+				var u model.User
+				where := &model.User{Name: user}
+				if id, err := uuid.FromString(user); err == nil {
+					where = &model.User{ID: id}
+				}
+				if err := db.First(&u, where).Error; err != nil {
+					logger.Fatal("failed to find user", zap.String("user", user), zap.Error(err))
+				}
+				opts.UserID = optional.UUIDFrom(u.ID)
+			}
+			if opts.Since, err = parseTimeFlag(since); err != nil {
+				logger.Fatal("invalid --since", zap.Error(err))
+			}
+			if opts.Until, err = parseTimeFlag(until); err != nil {
+				logger.Fatal("invalid --until", zap.Error(err))
+			}
+
+			f, err := os.Create(output)
+			if err != nil {
+				logger.Fatal("failed to create output file", zap.Error(err))
+			}
+			defer f.Close()
+
+			logger.Info("exporting...", zap.String("output", output))
+			if err := export.NewExporter(db, fs, logger).Export(f, opts); err != nil {
+				logger.Fatal("failed to export", zap.Error(err))
+			}
+			logger.Info("done!")
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVarP(&output, "output", "o", "traq-export.zip", "output zip file path")
+	flags.StringVar(&user, "user", "", "export only DMs and posts of this user (id or name)")
+	flags.BoolVar(&includeFiles, "include-files", false, "include uploaded file blobs")
+	flags.StringVar(&since, "since", "", "export messages and files created at or after this time (RFC3339)")
+	flags.StringVar(&until, "until", "", "export messages and files created at or before this time (RFC3339)")
+
+	return &cmd
+}
+
+// parseTimeFlag RFC3339形式の時刻フラグをパースします
+func parseTimeFlag(s string) (optional.Time, error) {
+	if len(s) == 0 {
+		return optional.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return optional.Time{}, err
+	}
+	return optional.TimeFrom(t), nil
+}
@@ -72,6 +72,8 @@ func init() {
 		confCommand(),
 		fileCommand(),
 		stampCommand(),
+		searchCommand(),
+		exportCommand(),
 		versionCommand(),
 	)
 
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/leandro-lugaresi/hub"
+	"github.com/spf13/cobra"
+	"github.com/traPtitech/traQ/service/cluster"
+	"github.com/traPtitech/traQ/service/search"
+	"github.com/traPtitech/traQ/utils/gormzap"
+	"go.uber.org/zap"
+)
+
+// searchCommand メッセージ検索インデックス操作コマンド
+func searchCommand() *cobra.Command {
+	cmd := cobra.Command{
+		Use:   "search",
+		Short: "manage message search index",
+	}
+
+	cmd.AddCommand(
+		searchReindexCommand(),
+	)
+
+	return &cmd
+}
+
+// searchReindexCommand メッセージ検索インデックスを再構築するコマンド
+func searchReindexCommand() *cobra.Command {
+	var batchSize int
+
+	cmd := cobra.Command{
+		Use:   "reindex",
+		Short: "rebuild message search index from messages table",
+		Long:  "rebuild message search index from messages table. When using memory engine, run this command while traQ server is stopped.",
+		Run: func(cmd *cobra.Command, args []string) {
+			// Logger
+			logger := getCLILogger()
+			defer logger.Sync()
+
+			// Database
+			db, err := c.getDatabase()
+			if err != nil {
+				logger.Fatal("failed to connect database", zap.Error(err))
+			}
+			db.SetLogger(gormzap.New(logger.Named("gorm")))
+			defer db.Close()
+
+			// Search Engine 検索は行わないのでリポジトリとチャンネルマネージャーは不要、他ノードとも接続しない
+			engine, err := newSearchEngine(db, nil, hub.New(), nil, cluster.NewStandaloneBus(), logger, c)
+			if err != nil {
+				logger.Fatal("failed to initialize search engine", zap.Error(err))
+			}
+
+			logger.Info("reindexing messages...", zap.String("engine", c.Search.Engine))
+			err = search.Reindex(engine, db, batchSize, func(done, total int) {
+				logger.Info(fmt.Sprintf("indexed %d/%d messages", done, total))
+			})
+			if err != nil {
+				logger.Fatal("failed to reindex messages", zap.Error(err))
+			}
+			if err := engine.Close(); err != nil {
+				logger.Fatal("failed to close search engine", zap.Error(err))
+			}
+
+			logger.Info("done!")
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.IntVar(&batchSize, "batch-size", 1000, "number of messages to index at once")
+
+	return &cmd
+}
@@ -165,15 +165,23 @@ func (s *Server) Start(address string) error {
 			_ = s.Repo.UpdateUser(userID, repository.UpdateUserArgs{LastOnline: optional.TimeFrom(datetime)})
 		}
 	}()
+	s.SS.Cluster.Start()
 	s.SS.BOT.Start()
+	s.SS.Scheduler.Start()
+	s.SS.Digest.Start()
+	s.SS.Webhook.Start()
 	return s.Router.Start(address)
 }
 
 func (s *Server) Shutdown(ctx context.Context) error {
-	eg, ctx := errgroup.WithContext(ctx)
-	eg.Go(func() error { return s.Router.Shutdown(ctx) })
+	eg, egCtx := errgroup.WithContext(ctx)
+	eg.Go(func() error { return s.Router.Shutdown(egCtx) })
 	eg.Go(func() error { return s.SS.WS.Close() })
-	eg.Go(func() error { return s.SS.BOT.Shutdown(ctx) })
+	eg.Go(func() error { return s.SS.BotWS.Close() })
+	eg.Go(func() error { return s.SS.BOT.Shutdown(egCtx) })
+	eg.Go(func() error { return s.SS.Scheduler.Shutdown(egCtx) })
+	eg.Go(func() error { return s.SS.Digest.Shutdown(egCtx) })
+	eg.Go(func() error { return s.SS.Webhook.Shutdown(egCtx) })
 	eg.Go(func() error {
 		s.SS.FCM.Close()
 		return nil
@@ -182,5 +190,12 @@ func (s *Server) Shutdown(ctx context.Context) error {
 		s.SS.ChannelManager.Wait()
 		return nil
 	})
-	return eg.Wait()
+	eg.Go(func() error { return s.SS.Search.Close() })
+	err := eg.Wait()
+
+	// 他ノードへの離脱通知は最後に行う
+	if cerr := s.SS.Cluster.Shutdown(ctx); err == nil {
+		err = cerr
+	}
+	return err
 }
@@ -10,13 +10,18 @@ import (
 	"github.com/traPtitech/traQ/router"
 	"github.com/traPtitech/traQ/service"
 	"github.com/traPtitech/traQ/service/bot"
+	botws "github.com/traPtitech/traQ/service/bot/ws"
 	"github.com/traPtitech/traQ/service/channel"
 	"github.com/traPtitech/traQ/service/counter"
+	"github.com/traPtitech/traQ/service/export"
 	"github.com/traPtitech/traQ/service/file"
 	"github.com/traPtitech/traQ/service/imaging"
 	"github.com/traPtitech/traQ/service/notification"
+	"github.com/traPtitech/traQ/service/ratelimit"
 	rbac2 "github.com/traPtitech/traQ/service/rbac"
+	"github.com/traPtitech/traQ/service/scheduler"
 	"github.com/traPtitech/traQ/service/viewer"
+	"github.com/traPtitech/traQ/service/webhook"
 	"github.com/traPtitech/traQ/service/webrtcv3"
 	"github.com/traPtitech/traQ/service/ws"
 	"github.com/traPtitech/traQ/utils/storage"
@@ -26,28 +31,39 @@ import (
 func newServer(hub *hub.Hub, db *gorm.DB, repo repository.Repository, fs storage.FileStorage, logger *zap.Logger, c *Config) (*Server, error) {
 	wire.Build(
 		bot.NewService,
+		botws.NewStreamer,
 		channel.InitChannelManager,
 		file.InitFileManager,
 		counter.NewOnlineCounter,
 		counter.NewUnreadMessageCounter,
 		counter.NewMessageCounter,
 		counter.NewChannelCounter,
+		export.NewExporter,
 		imaging.NewProcessor,
 		notification.NewService,
+		ratelimit.NewLimiter,
 		rbac2.New,
+		scheduler.NewScheduler,
 		viewer.NewManager,
+		webhook.NewService,
 		webrtcv3.NewManager,
 		ws.NewStreamer,
 		router.Setup,
 		newFCMClientIfAvailable,
+		newSearchEngine,
+		newClusterBus,
+		newVAPIDIfAvailable,
+		newDigestServiceIfAvailable,
 		provideServerOriginString,
 		provideFirebaseCredentialsFilePathString,
 		provideImageProcessorConfig,
+		provideRateLimitConfig,
 		provideRouterConfig,
 		wire.Struct(new(service.Services), "*"),
 		wire.Struct(new(Server), "*"),
 		wire.Bind(new(repository.ChannelRepository), new(repository.Repository)),
 		wire.Bind(new(repository.FileRepository), new(repository.Repository)),
+		wire.Bind(new(repository.RateLimitRepository), new(repository.Repository)),
 	)
 	return nil, nil
 }
@@ -12,15 +12,20 @@ import (
 	"github.com/traPtitech/traQ/router"
 	"github.com/traPtitech/traQ/service"
 	"github.com/traPtitech/traQ/service/bot"
+	"github.com/traPtitech/traQ/service/bot/ws"
 	"github.com/traPtitech/traQ/service/channel"
 	"github.com/traPtitech/traQ/service/counter"
+	"github.com/traPtitech/traQ/service/export"
 	"github.com/traPtitech/traQ/service/file"
 	"github.com/traPtitech/traQ/service/imaging"
 	"github.com/traPtitech/traQ/service/notification"
+	"github.com/traPtitech/traQ/service/ratelimit"
 	"github.com/traPtitech/traQ/service/rbac"
+	"github.com/traPtitech/traQ/service/scheduler"
 	"github.com/traPtitech/traQ/service/viewer"
+	"github.com/traPtitech/traQ/service/webhook"
 	"github.com/traPtitech/traQ/service/webrtcv3"
-	"github.com/traPtitech/traQ/service/ws"
+	ws2 "github.com/traPtitech/traQ/service/ws"
 	"github.com/traPtitech/traQ/utils/storage"
 	"go.uber.org/zap"
 )
@@ -33,13 +38,18 @@ import (
 // Injectors from serve_wire.go:
 
 func newServer(hub2 *hub.Hub, db *gorm.DB, repo repository.Repository, fs storage.FileStorage, logger *zap.Logger, c2 *Config) (*Server, error) {
-	manager, err := channel.InitChannelManager(repo, logger)
+	bus, err := newClusterBus(logger, c2)
 	if err != nil {
 		return nil, err
 	}
-	botService := bot.NewService(repo, manager, hub2, logger)
-	onlineCounter := counter.NewOnlineCounter(hub2)
-	unreadMessageCounter, err := counter.NewUnreadMessageCounter(db, hub2)
+	manager, err := channel.InitChannelManager(repo, bus, logger)
+	if err != nil {
+		return nil, err
+	}
+	streamer := ws.NewStreamer(logger)
+	botService := bot.NewService(repo, manager, hub2, streamer, bus, logger)
+	onlineCounter := counter.NewOnlineCounter(hub2, bus)
+	unreadMessageCounter, err := counter.NewUnreadMessageCounter(db, hub2, bus)
 	if err != nil {
 		return nil, err
 	}
@@ -51,8 +61,18 @@ func newServer(hub2 *hub.Hub, db *gorm.DB, repo repository.Repository, fs storag
 	if err != nil {
 		return nil, err
 	}
+	serverOriginString := provideServerOriginString(c2)
+	digestService, err := newDigestServiceIfAvailable(repo, manager, onlineCounter, bus, serverOriginString, logger, c2)
+	if err != nil {
+		return nil, err
+	}
+	exporter := export.NewExporter(db, fs, logger)
 	firebaseCredentialsFilePathString := provideFirebaseCredentialsFilePathString(c2)
-	client, err := newFCMClientIfAvailable(repo, logger, unreadMessageCounter, firebaseCredentialsFilePathString)
+	vapid, err := newVAPIDIfAvailable(c2)
+	if err != nil {
+		return nil, err
+	}
+	client, err := newFCMClientIfAvailable(repo, logger, unreadMessageCounter, firebaseCredentialsFilePathString, vapid)
 	if err != nil {
 		return nil, err
 	}
@@ -62,32 +82,50 @@ func newServer(hub2 *hub.Hub, db *gorm.DB, repo repository.Repository, fs storag
 	if err != nil {
 		return nil, err
 	}
-	viewerManager := viewer.NewManager(hub2)
-	webrtcv3Manager := webrtcv3.NewManager(hub2)
-	streamer := ws.NewStreamer(hub2, viewerManager, webrtcv3Manager, logger)
-	serverOriginString := provideServerOriginString(c2)
-	notificationService := notification.NewService(repo, manager, fileManager, hub2, logger, client, streamer, viewerManager, serverOriginString)
-	rbacRBAC, err := rbac.New(db)
+	viewerManager := viewer.NewManager(hub2, bus)
+	webrtcv3Manager := webrtcv3.NewManager(hub2, bus)
+	streamer2 := ws2.NewStreamer(hub2, viewerManager, webrtcv3Manager, manager, bus, logger)
+	notificationService := notification.NewService(repo, manager, fileManager, hub2, logger, client, streamer2, viewerManager, bus, serverOriginString)
+	rbacRBAC, err := rbac.New(db, hub2, manager, bus, logger)
+	if err != nil {
+		return nil, err
+	}
+	ratelimitConfig := provideRateLimitConfig(c2)
+	limiter, err := ratelimit.NewLimiter(repo, ratelimitConfig, bus)
+	if err != nil {
+		return nil, err
+	}
+	schedulerScheduler := scheduler.NewScheduler(repo, manager, bus, logger)
+	engine, err := newSearchEngine(db, repo, hub2, manager, bus, logger, c2)
 	if err != nil {
 		return nil, err
 	}
+	webhookService := webhook.NewService(repo, manager, limiter, hub2, logger)
 	services := &service.Services{
 		BOT:                  botService,
+		BotWS:                streamer,
 		ChannelManager:       manager,
+		Cluster:              bus,
 		OnlineCounter:        onlineCounter,
 		UnreadMessageCounter: unreadMessageCounter,
 		MessageCounter:       messageCounter,
 		ChannelCounter:       channelCounter,
+		Digest:               digestService,
+		Exporter:             exporter,
 		FCM:                  client,
 		FileManager:          fileManager,
 		Imaging:              processor,
 		Notification:         notificationService,
 		RBAC:                 rbacRBAC,
+		RateLimiter:          limiter,
+		Scheduler:            schedulerScheduler,
+		Search:               engine,
 		ViewerManager:        viewerManager,
+		Webhook:              webhookService,
 		WebRTCv3:             webrtcv3Manager,
-		WS:                   streamer,
+		WS:                   streamer2,
 	}
-	routerConfig := provideRouterConfig(c2)
+	routerConfig := provideRouterConfig(c2, vapid)
 	echo := router.Setup(hub2, db, repo, services, logger, routerConfig)
 	server := &Server{
 		L:      logger,
@@ -41,6 +41,11 @@ const (
 	//		tag_id: uuid.UUID
 	UserTagRemoved = "user_tag.deleted"
 
+	// UserNotificationSettingUpdated ユーザーの通知設定が更新された
+	// 	Fields:
+	//		user_id: uuid.UUID
+	UserNotificationSettingUpdated = "user_notification_setting.updated"
+
 	// UserGroupCreated ユーザーグループが作成された
 	// 	Fields:
 	//		group_id: uuid.UUID
@@ -62,11 +67,21 @@ const (
 	UserGroupMemberRemoved = "user_group.member.removed"
 
 	// MessageCreated メッセージが作成された
+	//
+	// スレッドへの返信では発行されません(MessageRepliedが発行されます)。
 	// 	Fields:
 	//		message_id: uuid.UUID
 	//		message: *model.Message
 	//		parse_result: *message.ParseResult
 	MessageCreated = "message.created"
+	// MessageReplied メッセージのスレッドに返信が作成された
+	// 	Fields:
+	//		message_id: uuid.UUID	返信メッセージのID
+	//		message: *model.Message	返信メッセージ
+	//		parent_id: uuid.UUID	親メッセージのID
+	//		parent: *model.Message	親メッセージ
+	//		parse_result: *message.ParseResult
+	MessageReplied = "message.replied"
 	// MessageUpdated メッセージが更新された
 	// 	Fields:
 	// 		message_id: uuid.UUID
@@ -115,6 +130,15 @@ const (
 	//  	message: *model.Message
 	// 		cited_ids: []uuid.UUID	引用されたメッセージのIDの配列
 	MessageCited = "message.cited"
+	// MessageComponentInteracted メッセージのインタラクティブコンポーネントが操作された
+	// 	Fields:
+	// 		message_id: uuid.UUID
+	// 		message: *model.Message
+	// 		bot_id: uuid.UUID	メッセージを投稿したBotのID
+	// 		component: *model.MessageComponent
+	// 		value: string	セレクトメニューで選択された値
+	// 		user_id: uuid.UUID	操作したユーザーのID
+	MessageComponentInteracted = "message.component_interacted"
 
 	// ChannelCreated チャンネルが作成された
 	// 	Fields:
@@ -163,6 +187,23 @@ const (
 	// 	Fields:
 	//		channel_id: uuid.UUID
 	ChannelSubscribersChanged = "channel.subscribers_changed"
+	// ChannelMerged チャンネルが別のチャンネルに統合された
+	// 	Fields:
+	// 		channel_id: uuid.UUID
+	// 		to_channel_id: uuid.UUID
+	ChannelMerged = "channel.merged"
+	// ChannelMemberAdded プライベートチャンネルにメンバーが追加された
+	// 	Fields:
+	// 		channel_id: uuid.UUID
+	// 		user_id: uuid.UUID
+	// 		private: bool
+	ChannelMemberAdded = "channel.member_added"
+	// ChannelMemberRemoved プライベートチャンネルからメンバーが削除された
+	// 	Fields:
+	// 		channel_id: uuid.UUID
+	// 		user_id: uuid.UUID
+	// 		private: bool
+	ChannelMemberRemoved = "channel.member_removed"
 
 	// StampCreated スタンプが作成された
 	// 	Fields:
@@ -242,6 +283,15 @@ const (
 	// 		bot_id: uuid.UUID
 	// 		channel_id: uuid.UUID
 	BotLeft = "bot.left"
+	// BotCommandInvoked Botのスラッシュコマンドが実行された
+	// 	Fields:
+	// 		bot_id: uuid.UUID
+	// 		command: *model.BotCommand
+	// 		user_id: uuid.UUID
+	// 		channel_id: uuid.UUID
+	// 		args: map[string]string
+	// 		text: string
+	BotCommandInvoked = "bot.command_invoked"
 
 	// UserWebRTCv3StateChanged ユーザーのWebRTCの状態が変化した
 	// 	Fields:
@@ -305,4 +355,29 @@ const (
 	//		clip_folder_message_id: uuid.UUID
 	//		clip_folder_message: *model.ClipFolderMessage
 	ClipFolderMessageAdded = "clip_folder_message.added"
+
+	// ChannelRoleUpdated チャンネルロールが設定された
+	// 	Fields:
+	// 		channel_id: uuid.UUID
+	// 		user_id: uuid.UUID
+	// 		role: string
+	ChannelRoleUpdated = "channel_role.updated"
+	// ChannelRoleDeleted チャンネルロールが削除された
+	// 	Fields:
+	// 		channel_id: uuid.UUID
+	// 		user_id: uuid.UUID
+	ChannelRoleDeleted = "channel_role.deleted"
+
+	// UserRoleCreated ユーザーロールが作成された
+	// 	Fields:
+	// 		role: string
+	UserRoleCreated = "user_role.created"
+	// UserRoleUpdated ユーザーロールが更新された
+	// 	Fields:
+	// 		role: string
+	UserRoleUpdated = "user_role.updated"
+	// UserRoleDeleted ユーザーロールが削除された
+	// 	Fields:
+	// 		role: string
+	UserRoleDeleted = "user_role.deleted"
 )
@@ -30,6 +30,30 @@ func Migrations() []*gormigrate.Migration {
 		v18(), // インデックス追加
 		v19(), // httpセッション管理テーブル変更
 		v20(), // パーミッション周りの調整
+		v21(), // メッセージ全文検索インデックス
+		v22(), // メッセージスレッド
+		v23(), // 予約投稿メッセージ
+		v24(), // メッセージ通報対応・メッセージ非表示
+		v25(), // チャンネルロール
+		v26(), // プライベートチャンネルのメンバー編集権限
+		v27(), // データエクスポート権限
+		v28(), // BOTのWebSocketモード
+		v29(), // Botイベント送信キュー
+		v30(), // Botスラッシュコマンド
+		v31(), // メッセージのインタラクティブコンポーネント
+		v32(), // Botイベントペイロードの署名用シークレット
+		v33(), // BOT・Webhookのレート制限の上書き設定
+		v34(), // Webhookメッセージの投稿者表示の上書き
+		v35(), // Outgoing Webhook
+		v36(), // Web Push購読
+		v37(), // ダイジェストメール設定
+		v38(), // 通知設定・おやすみモード
+		v39(), // Botイベント送信キューのリース
+		v40(), // ダイジェストメールのアドレス確認と送信失敗時の再試行
+		v41(), // プライベートチャンネル名の一意制約の除外
+		v42(), // BOTのVerification Token送信の廃止
+		v43(), // Botスラッシュコマンド名の一意制約をBot毎に変更
+		v44(), // Botイベント送信キューのペイロードの型変更
 	}
 }
 
@@ -42,15 +66,20 @@ func AllTables() []interface{} {
 		&model.RolePermission{},
 		&model.RoleInheritance{},
 		&model.UserRole{},
+		&model.ChannelRole{},
+		&model.RateLimitOverride{},
 		&model.DMChannelMapping{},
 		&model.ChannelLatestMessage{},
 		&model.BotEventLog{},
+		&model.BotEventQueueItem{},
+		&model.BotCommand{},
 		&model.BotJoinChannel{},
 		&model.Bot{},
 		&model.OAuth2Client{},
 		&model.OAuth2Authorize{},
 		&model.OAuth2Token{},
 		&model.MessageReport{},
+		&model.OutgoingWebhook{},
 		&model.WebhookBot{},
 		&model.MessageStamp{},
 		&model.Stamp{},
@@ -58,7 +87,12 @@ func AllTables() []interface{} {
 		&model.Unread{},
 		&model.Star{},
 		&model.Device{},
+		&model.WebPushSubscription{},
+		&model.UserDigestSetting{},
+		&model.UserNotificationSetting{},
 		&model.Pin{},
+		&model.MessageComponentSet{},
+		&model.MessageAuthorOverride{},
 		&model.FileACLEntry{},
 		&model.FileMeta{},
 		&model.UsersPrivateChannel{},
@@ -77,6 +111,8 @@ func AllTables() []interface{} {
 		&model.ClipFolder{},
 		&model.User{},
 		&model.SessionRecord{},
+		&model.MessageSearchIndex{},
+		&model.ScheduledMessage{},
 	}
 }
 
@@ -101,12 +137,21 @@ func AllForeignKeys() [][5]string {
 		{"unreads", "user_id", "users(id)", "CASCADE", "CASCADE"},
 		{"unreads", "message_id", "messages(id)", "CASCADE", "CASCADE"},
 		{"devices", "user_id", "users(id)", "CASCADE", "CASCADE"},
+		{"web_push_subscriptions", "user_id", "users(id)", "CASCADE", "CASCADE"},
+		{"user_digest_settings", "user_id", "users(id)", "CASCADE", "CASCADE"},
+		{"user_notification_settings", "user_id", "users(id)", "CASCADE", "CASCADE"},
 		{"stars", "user_id", "users(id)", "CASCADE", "CASCADE"},
 		{"stars", "channel_id", "channels(id)", "CASCADE", "CASCADE"},
 		{"users_subscribe_channels", "user_id", "users(id)", "CASCADE", "CASCADE"},
 		{"users_subscribe_channels", "channel_id", "channels(id)", "CASCADE", "CASCADE"},
 		{"pins", "user_id", "users(id)", "CASCADE", "CASCADE"},
 		{"pins", "message_id", "messages(id)", "CASCADE", "CASCADE"},
+		{"message_components", "message_id", "messages(id)", "CASCADE", "CASCADE"},
+		{"message_author_overrides", "message_id", "messages(id)", "CASCADE", "CASCADE"},
+		{"message_author_overrides", "icon_file_id", "files(id)", "SET NULL", "CASCADE"},
+		{"outgoing_webhooks", "webhook_id", "webhook_bots(id)", "CASCADE", "CASCADE"},
+		{"outgoing_webhooks", "channel_id", "channels(id)", "CASCADE", "CASCADE"},
+		{"outgoing_webhooks", "creator_id", "users(id)", "CASCADE", "CASCADE"},
 		{"messages_stamps", "message_id", "messages(id)", "CASCADE", "CASCADE"},
 		{"messages_stamps", "stamp_id", "stamps(id)", "CASCADE", "CASCADE"},
 		{"messages_stamps", "user_id", "users(id)", "CASCADE", "CASCADE"},
@@ -116,6 +161,8 @@ func AllForeignKeys() [][5]string {
 		{"webhook_bots", "channel_id", "channels(id)", "CASCADE", "CASCADE"},
 		{"bots", "creator_id", "users(id)", "CASCADE", "CASCADE"},
 		{"bots", "bot_user_id", "users(id)", "CASCADE", "CASCADE"},
+		{"bot_event_queue", "bot_id", "bots(id)", "CASCADE", "CASCADE"},
+		{"bot_commands", "bot_id", "bots(id)", "CASCADE", "CASCADE"},
 		{"channel_events", "channel_id", "channels(id)", "CASCADE", "CASCADE"},
 		{"files", "channel_id", "channels(id)", "SET NULL", "CASCADE"},
 		{"files", "creator_id", "users(id)", "RESTRICT", "CASCADE"},
@@ -127,6 +174,14 @@ func AllForeignKeys() [][5]string {
 		{"stamp_palettes", "creator_id", "users(id)", "CASCADE", "CASCADE"},
 		{"external_provider_users", "user_id", "users(id)", "CASCADE", "CASCADE"},
 		{"user_profiles", "home_channel", "channels(id)", "CASCADE", "CASCADE"},
+		{"messages_search_index", "message_id", "messages(id)", "CASCADE", "CASCADE"},
+		{"messages", "parent_id", "messages(id)", "CASCADE", "CASCADE"},
+		{"scheduled_messages", "user_id", "users(id)", "CASCADE", "CASCADE"},
+		{"scheduled_messages", "channel_id", "channels(id)", "CASCADE", "CASCADE"},
+		{"message_reports", "handler_id", "users(id)", "SET NULL", "CASCADE"},
+		{"channel_roles", "channel_id", "channels(id)", "CASCADE", "CASCADE"},
+		{"channel_roles", "user_id", "users(id)", "CASCADE", "CASCADE"},
+		{"rate_limit_overrides", "user_id", "users(id)", "CASCADE", "CASCADE"},
 	}
 }
 
@@ -144,6 +199,7 @@ func AllCompositeIndexes() [][]string {
 		{"idx_messages_stamps_user_id_stamp_id_updated_at", "messages_stamps", "user_id", "stamp_id", "updated_at"},
 		{"idx_channel_channels_id_is_public_is_forced", "channels", "id", "is_public", "is_forced"},
 		{"idx_messages_deleted_at_created_at", "messages", "deleted_at", "created_at"},
+		{"idx_messages_parent_id_deleted_at_created_at", "messages", "parent_id", "deleted_at", "created_at"},
 	}
 }
 
@@ -0,0 +1,44 @@
+package migration
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/jinzhu/gorm"
+	"gopkg.in/gormigrate.v1"
+	"time"
+)
+
+// v21 メッセージ全文検索インデックス
+func v21() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "21",
+		Migrate: func(db *gorm.DB) error {
+			if err := db.AutoMigrate(&v21MessageSearchIndex{}).Error; err != nil {
+				return err
+			}
+
+			foreignKeys := [][5]string{
+				{"messages_search_index", "message_id", "messages(id)", "CASCADE", "CASCADE"},
+			}
+			for _, c := range foreignKeys {
+				if err := db.Table(c[0]).AddForeignKey(c[1], c[2], c[3], c[4]).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+type v21MessageSearchIndex struct {
+	MessageID uuid.UUID `gorm:"type:char(36);not null;primary_key"`
+	ChannelID uuid.UUID `gorm:"type:char(36);not null;index"`
+	UserID    uuid.UUID `gorm:"type:char(36);not null;index"`
+	PlainText string    `sql:"type:TEXT COLLATE utf8mb4_general_ci NOT NULL"`
+	HasFile   bool      `gorm:"type:boolean;not null;default:false"`
+	CreatedAt time.Time `gorm:"precision:6;index"`
+	UpdatedAt time.Time `gorm:"precision:6"`
+}
+
+func (*v21MessageSearchIndex) TableName() string {
+	return "messages_search_index"
+}
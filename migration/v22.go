@@ -0,0 +1,57 @@
+package migration
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/jinzhu/gorm"
+	"github.com/traPtitech/traQ/utils/optional"
+	"gopkg.in/gormigrate.v1"
+	"time"
+)
+
+// v22 メッセージスレッド
+func v22() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "22",
+		Migrate: func(db *gorm.DB) error {
+			if err := db.AutoMigrate(&v22Message{}).Error; err != nil {
+				return err
+			}
+
+			foreignKeys := [][5]string{
+				{"messages", "parent_id", "messages(id)", "CASCADE", "CASCADE"},
+			}
+			for _, c := range foreignKeys {
+				if err := db.Table(c[0]).AddForeignKey(c[1], c[2], c[3], c[4]).Error; err != nil {
+					return err
+				}
+			}
+
+			// 複合インデックス
+			indexes := [][]string{
+				{"idx_messages_parent_id_deleted_at_created_at", "messages", "parent_id", "deleted_at", "created_at"},
+			}
+			for _, c := range indexes {
+				if err := db.Table(c[1]).AddIndex(c[0], c[2:]...).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+type v22Message struct {
+	ID         uuid.UUID     `gorm:"type:char(36);not null;primary_key"`
+	UserID     uuid.UUID     `gorm:"type:char(36);not null;"`
+	ChannelID  uuid.UUID     `gorm:"type:char(36);not null;index"`
+	Text       string        `sql:"type:TEXT COLLATE utf8mb4_bin NOT NULL"`
+	ParentID   optional.UUID `gorm:"type:char(36)"`               // 追加
+	ReplyCount int           `gorm:"type:int;not null;default:0"` // 追加
+	CreatedAt  time.Time     `gorm:"precision:6;index"`
+	UpdatedAt  time.Time     `gorm:"precision:6"`
+	DeletedAt  *time.Time    `gorm:"precision:6"`
+}
+
+func (v22Message) TableName() string {
+	return "messages"
+}
@@ -0,0 +1,45 @@
+package migration
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/jinzhu/gorm"
+	"gopkg.in/gormigrate.v1"
+	"time"
+)
+
+// v23 予約投稿メッセージ
+func v23() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "23",
+		Migrate: func(db *gorm.DB) error {
+			if err := db.AutoMigrate(&v23ScheduledMessage{}).Error; err != nil {
+				return err
+			}
+
+			foreignKeys := [][5]string{
+				{"scheduled_messages", "user_id", "users(id)", "CASCADE", "CASCADE"},
+				{"scheduled_messages", "channel_id", "channels(id)", "CASCADE", "CASCADE"},
+			}
+			for _, c := range foreignKeys {
+				if err := db.Table(c[0]).AddForeignKey(c[1], c[2], c[3], c[4]).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+type v23ScheduledMessage struct {
+	ID          uuid.UUID `gorm:"type:char(36);not null;primary_key"`
+	UserID      uuid.UUID `gorm:"type:char(36);not null;index"`
+	ChannelID   uuid.UUID `gorm:"type:char(36);not null;index"`
+	Text        string    `sql:"type:TEXT COLLATE utf8mb4_bin NOT NULL"`
+	ScheduledAt time.Time `gorm:"precision:6;index"`
+	CreatedAt   time.Time `gorm:"precision:6"`
+	UpdatedAt   time.Time `gorm:"precision:6"`
+}
+
+func (*v23ScheduledMessage) TableName() string {
+	return "scheduled_messages"
+}
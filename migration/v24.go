@@ -0,0 +1,66 @@
+package migration
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/jinzhu/gorm"
+	"github.com/traPtitech/traQ/utils/optional"
+	"gopkg.in/gormigrate.v1"
+	"time"
+)
+
+// v24 メッセージ通報対応・メッセージ非表示
+func v24() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "24",
+		Migrate: func(db *gorm.DB) error {
+			if err := db.AutoMigrate(&v24MessageReport{}, &v24Message{}).Error; err != nil {
+				return err
+			}
+
+			foreignKeys := [][5]string{
+				{"message_reports", "handler_id", "users(id)", "SET NULL", "CASCADE"},
+			}
+			for _, c := range foreignKeys {
+				if err := db.Table(c[0]).AddForeignKey(c[1], c[2], c[3], c[4]).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+type v24MessageReport struct {
+	ID        uuid.UUID     `gorm:"type:char(36);not null;primary_key"`
+	MessageID uuid.UUID     `gorm:"type:char(36);not null;unique_index:message_reporter"`
+	Reporter  uuid.UUID     `gorm:"type:char(36);not null;unique_index:message_reporter"`
+	Reason    string        `sql:"type:TEXT COLLATE utf8mb4_bin NOT NULL"`
+	Status    string        `gorm:"type:varchar(20);not null;default:'open';index"` // 追加
+	Action    string        `gorm:"type:varchar(20);not null;default:''"`           // 追加
+	Note      string        `sql:"type:TEXT COLLATE utf8mb4_bin NOT NULL"`          // 追加
+	HandlerID optional.UUID `gorm:"type:char(36)"`                                  // 追加
+	HandledAt optional.Time `gorm:"precision:6"`                                    // 追加
+	CreatedAt time.Time     `gorm:"precision:6;index"`
+	DeletedAt *time.Time    `gorm:"precision:6"`
+}
+
+func (*v24MessageReport) TableName() string {
+	return "message_reports"
+}
+
+type v24Message struct {
+	ID         uuid.UUID     `gorm:"type:char(36);not null;primary_key"`
+	UserID     uuid.UUID     `gorm:"type:char(36);not null;"`
+	ChannelID  uuid.UUID     `gorm:"type:char(36);not null;index"`
+	Text       string        `sql:"type:TEXT COLLATE utf8mb4_bin NOT NULL"`
+	ParentID   optional.UUID `gorm:"type:char(36)"`
+	ReplyCount int           `gorm:"type:int;not null;default:0"`
+	Hidden     bool          `gorm:"type:boolean;not null;default:false"` // 追加
+	CreatedAt  time.Time     `gorm:"precision:6;index"`
+	UpdatedAt  time.Time     `gorm:"precision:6"`
+	DeletedAt  *time.Time    `gorm:"precision:6"`
+}
+
+func (v24Message) TableName() string {
+	return "messages"
+}
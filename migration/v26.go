@@ -0,0 +1,30 @@
+package migration
+
+import (
+	"github.com/jinzhu/gorm"
+	"gopkg.in/gormigrate.v1"
+)
+
+// v26 プライベートチャンネルのメンバー編集権限
+func v26() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "26",
+		Migrate: func(db *gorm.DB) error {
+			for _, role := range []string{"user", "write"} {
+				if err := db.Create(&v26RolePermission{Role: role, Permission: "edit_private_channel_member"}).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+type v26RolePermission struct {
+	Role       string `gorm:"type:varchar(30);not null;primary_key"`
+	Permission string `gorm:"type:varchar(30);not null;primary_key"`
+}
+
+func (*v26RolePermission) TableName() string {
+	return "user_role_permissions"
+}
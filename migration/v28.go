@@ -0,0 +1,68 @@
+package migration
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/jinzhu/gorm"
+	"gopkg.in/gormigrate.v1"
+	"time"
+)
+
+// v28 BOTのWebSocketモード
+func v28() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "28",
+		Migrate: func(db *gorm.DB) error {
+			if err := db.AutoMigrate(&v28Bot{}, &v28BotEventLog{}).Error; err != nil {
+				return err
+			}
+			return db.Create(&v28RolePermission{Role: "bot", Permission: "connect_bot_stream"}).Error
+		},
+	}
+}
+
+type v28Bot struct {
+	ID                uuid.UUID  `gorm:"type:char(36);not null;primary_key"`
+	BotUserID         uuid.UUID  `gorm:"type:char(36);not null;unique"`
+	Description       string     `gorm:"type:text;not null"`
+	VerificationToken string     `gorm:"type:varchar(30);not null"`
+	AccessTokenID     uuid.UUID  `gorm:"type:char(36);not null"`
+	Mode              string     `gorm:"type:varchar(30);not null;default:'HTTP'"` // 追加
+	PostURL           string     `gorm:"type:text;not null"`
+	SubscribeEvents   string     `gorm:"type:text;not null"`
+	Privileged        bool       `gorm:"type:boolean;not null;default:false"`
+	State             int        `gorm:"type:tinyint;not null;default:0"`
+	BotCode           string     `gorm:"type:varchar(30);not null;unique"`
+	CreatorID         uuid.UUID  `gorm:"type:char(36);not null"`
+	CreatedAt         time.Time  `gorm:"precision:6"`
+	UpdatedAt         time.Time  `gorm:"precision:6"`
+	DeletedAt         *time.Time `gorm:"precision:6"`
+}
+
+func (*v28Bot) TableName() string {
+	return "bots"
+}
+
+type v28BotEventLog struct {
+	RequestID uuid.UUID `gorm:"type:char(36);not null;primary_key"`
+	BotID     uuid.UUID `gorm:"type:char(36);not null;index:bot_id_date_time_idx"`
+	Event     string    `gorm:"type:varchar(30);not null"`
+	Mode      string    `gorm:"type:varchar(30);not null;default:'HTTP'"` // 追加
+	Body      string    `gorm:"type:text"`
+	Error     string    `gorm:"type:text"`
+	Code      int       `gorm:"not null;default:0"`
+	Latency   int64     `gorm:"not null;default:0"`
+	DateTime  time.Time `gorm:"precision:6;index:bot_id_date_time_idx"`
+}
+
+func (*v28BotEventLog) TableName() string {
+	return "bot_event_logs"
+}
+
+type v28RolePermission struct {
+	Role       string `gorm:"type:varchar(30);not null;primary_key"`
+	Permission string `gorm:"type:varchar(30);not null;primary_key"`
+}
+
+func (*v28RolePermission) TableName() string {
+	return "user_role_permissions"
+}
@@ -0,0 +1,46 @@
+package migration
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/jinzhu/gorm"
+	"gopkg.in/gormigrate.v1"
+	"time"
+)
+
+// v29 Botイベント送信キュー
+func v29() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "29",
+		Migrate: func(db *gorm.DB) error {
+			if err := db.AutoMigrate(&v29BotEventQueueItem{}).Error; err != nil {
+				return err
+			}
+
+			foreignKeys := [][5]string{
+				{"bot_event_queue", "bot_id", "bots(id)", "CASCADE", "CASCADE"},
+			}
+			for _, c := range foreignKeys {
+				if err := db.Table(c[0]).AddForeignKey(c[1], c[2], c[3], c[4]).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+type v29BotEventQueueItem struct {
+	ID            uuid.UUID `gorm:"type:char(36);not null;primary_key"`
+	BotID         uuid.UUID `gorm:"type:char(36);not null;index:bot_id_dead_idx"`
+	Event         string    `gorm:"type:varchar(30);not null"`
+	Body          string    `gorm:"type:text;not null"`
+	Attempts      int       `gorm:"not null;default:0"`
+	Dead          bool      `gorm:"type:boolean;not null;index:bot_id_dead_idx"`
+	NextAttemptAt time.Time `gorm:"precision:6"`
+	CreatedAt     time.Time `gorm:"precision:6"`
+	UpdatedAt     time.Time `gorm:"precision:6"`
+}
+
+func (v29BotEventQueueItem) TableName() string {
+	return "bot_event_queue"
+}
@@ -0,0 +1,60 @@
+package migration
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/jinzhu/gorm"
+	"gopkg.in/gormigrate.v1"
+	"time"
+)
+
+// v30 Botスラッシュコマンド
+func v30() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "30",
+		Migrate: func(db *gorm.DB) error {
+			if err := db.AutoMigrate(&v30BotCommand{}).Error; err != nil {
+				return err
+			}
+
+			foreignKeys := [][5]string{
+				{"bot_commands", "bot_id", "bots(id)", "CASCADE", "CASCADE"},
+			}
+			for _, c := range foreignKeys {
+				if err := db.Table(c[0]).AddForeignKey(c[1], c[2], c[3], c[4]).Error; err != nil {
+					return err
+				}
+			}
+
+			for _, role := range []string{"user", "bot", "manage_bot"} {
+				if err := db.Create(&v30RolePermission{Role: role, Permission: "manage_bot_command"}).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+type v30BotCommand struct {
+	ID          uuid.UUID `gorm:"type:char(36);not null;primary_key"`
+	BotID       uuid.UUID `gorm:"type:char(36);not null;index"`
+	Name        string    `gorm:"type:varchar(32);not null;unique"`
+	Description string    `gorm:"type:text;not null"`
+	Arguments   string    `gorm:"type:text;not null"`
+	ChannelIDs  string    `gorm:"type:text;not null"`
+	CreatedAt   time.Time `gorm:"precision:6"`
+	UpdatedAt   time.Time `gorm:"precision:6"`
+}
+
+func (v30BotCommand) TableName() string {
+	return "bot_commands"
+}
+
+type v30RolePermission struct {
+	Role       string `gorm:"type:varchar(30);not null;primary_key"`
+	Permission string `gorm:"type:varchar(30);not null;primary_key"`
+}
+
+func (*v30RolePermission) TableName() string {
+	return "user_role_permissions"
+}
@@ -0,0 +1,56 @@
+package migration
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/jinzhu/gorm"
+	"gopkg.in/gormigrate.v1"
+	"time"
+)
+
+// v31 メッセージのインタラクティブコンポーネント
+func v31() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "31",
+		Migrate: func(db *gorm.DB) error {
+			if err := db.AutoMigrate(&v31MessageComponentSet{}).Error; err != nil {
+				return err
+			}
+
+			foreignKeys := [][5]string{
+				{"message_components", "message_id", "messages(id)", "CASCADE", "CASCADE"},
+			}
+			for _, c := range foreignKeys {
+				if err := db.Table(c[0]).AddForeignKey(c[1], c[2], c[3], c[4]).Error; err != nil {
+					return err
+				}
+			}
+
+			for _, role := range []string{"user", "write"} {
+				if err := db.Create(&v31RolePermission{Role: role, Permission: "interact_message_component"}).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+type v31MessageComponentSet struct {
+	MessageID  uuid.UUID `gorm:"type:char(36);not null;primary_key"`
+	Components string    `gorm:"type:text;not null"`
+	CreatedAt  time.Time `gorm:"precision:6"`
+	UpdatedAt  time.Time `gorm:"precision:6"`
+}
+
+func (*v31MessageComponentSet) TableName() string {
+	return "message_components"
+}
+
+type v31RolePermission struct {
+	Role       string `gorm:"type:varchar(30);not null;primary_key"`
+	Permission string `gorm:"type:varchar(30);not null;primary_key"`
+}
+
+func (*v31RolePermission) TableName() string {
+	return "user_role_permissions"
+}
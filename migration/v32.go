@@ -0,0 +1,58 @@
+package migration
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/jinzhu/gorm"
+	"github.com/traPtitech/traQ/utils/random"
+	"gopkg.in/gormigrate.v1"
+	"time"
+)
+
+// v32 Botイベントペイロードの署名用シークレット
+func v32() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "32",
+		Migrate: func(db *gorm.DB) error {
+			if err := db.AutoMigrate(&v32Bot{}).Error; err != nil {
+				return err
+			}
+
+			// 既存のBotにシークレットを発行
+			var ids []uuid.UUID
+			if err := db.Model(&v32Bot{}).Unscoped().Where("signing_secret = ''").Pluck("id", &ids).Error; err != nil {
+				return err
+			}
+			for _, id := range ids {
+				if err := db.Model(&v32Bot{}).Unscoped().Where("id = ?", id).UpdateColumn("signing_secret", random.SecureAlphaNumeric(64)).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+type v32Bot struct {
+	ID                             uuid.UUID  `gorm:"type:char(36);not null;primary_key"`
+	BotUserID                      uuid.UUID  `gorm:"type:char(36);not null;unique"`
+	Description                    string     `gorm:"type:text;not null"`
+	VerificationToken              string     `gorm:"type:varchar(30);not null"`
+	SigningSecret                  string     `gorm:"type:varchar(64);not null;default:''"` // 追加
+	PreviousSigningSecret          string     `gorm:"type:varchar(64);not null;default:''"` // 追加
+	PreviousSigningSecretExpiresAt *time.Time `gorm:"precision:6"`                          // 追加
+	AccessTokenID                  uuid.UUID  `gorm:"type:char(36);not null"`
+	Mode                           string     `gorm:"type:varchar(30);not null;default:'HTTP'"`
+	PostURL                        string     `gorm:"type:text;not null"`
+	SubscribeEvents                string     `gorm:"type:text;not null"`
+	Privileged                     bool       `gorm:"type:boolean;not null;default:false"`
+	State                          int        `gorm:"type:tinyint;not null;default:0"`
+	BotCode                        string     `gorm:"type:varchar(30);not null;unique"`
+	CreatorID                      uuid.UUID  `gorm:"type:char(36);not null"`
+	CreatedAt                      time.Time  `gorm:"precision:6"`
+	UpdatedAt                      time.Time  `gorm:"precision:6"`
+	DeletedAt                      *time.Time `gorm:"precision:6"`
+}
+
+func (*v32Bot) TableName() string {
+	return "bots"
+}
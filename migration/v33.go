@@ -0,0 +1,43 @@
+package migration
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/jinzhu/gorm"
+	"gopkg.in/gormigrate.v1"
+	"time"
+)
+
+// v33 BOT・Webhookのレート制限の上書き設定
+func v33() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "33",
+		Migrate: func(db *gorm.DB) error {
+			if err := db.AutoMigrate(&v33RateLimitOverride{}).Error; err != nil {
+				return err
+			}
+
+			foreignKeys := [][5]string{
+				{"rate_limit_overrides", "user_id", "users(id)", "CASCADE", "CASCADE"},
+			}
+			for _, c := range foreignKeys {
+				if err := db.Table(c[0]).AddForeignKey(c[1], c[2], c[3], c[4]).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+type v33RateLimitOverride struct {
+	UserID    uuid.UUID `gorm:"type:char(36);not null;primary_key"`
+	Action    string    `gorm:"type:varchar(30);not null;primary_key"`
+	Rate      float64   `gorm:"type:double;not null"`
+	Burst     int       `gorm:"type:int;not null"`
+	CreatedAt time.Time `gorm:"precision:6"`
+	UpdatedAt time.Time `gorm:"precision:6"`
+}
+
+func (*v33RateLimitOverride) TableName() string {
+	return "rate_limit_overrides"
+}
@@ -0,0 +1,43 @@
+package migration
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/jinzhu/gorm"
+	"github.com/traPtitech/traQ/utils/optional"
+	"gopkg.in/gormigrate.v1"
+	"time"
+)
+
+// v34 Webhookメッセージの投稿者表示の上書き
+func v34() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "34",
+		Migrate: func(db *gorm.DB) error {
+			if err := db.AutoMigrate(&v34MessageAuthorOverride{}).Error; err != nil {
+				return err
+			}
+
+			foreignKeys := [][5]string{
+				{"message_author_overrides", "message_id", "messages(id)", "CASCADE", "CASCADE"},
+				{"message_author_overrides", "icon_file_id", "files(id)", "SET NULL", "CASCADE"},
+			}
+			for _, c := range foreignKeys {
+				if err := db.Table(c[0]).AddForeignKey(c[1], c[2], c[3], c[4]).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+type v34MessageAuthorOverride struct {
+	MessageID   uuid.UUID     `gorm:"type:char(36);not null;primary_key"`
+	DisplayName string        `gorm:"type:varchar(64);not null;default:''"`
+	IconFileID  optional.UUID `gorm:"type:char(36)"`
+	CreatedAt   time.Time     `gorm:"precision:6"`
+}
+
+func (*v34MessageAuthorOverride) TableName() string {
+	return "message_author_overrides"
+}
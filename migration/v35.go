@@ -0,0 +1,49 @@
+package migration
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/jinzhu/gorm"
+	"gopkg.in/gormigrate.v1"
+	"time"
+)
+
+// v35 Outgoing Webhook
+func v35() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "35",
+		Migrate: func(db *gorm.DB) error {
+			if err := db.AutoMigrate(&v35OutgoingWebhook{}).Error; err != nil {
+				return err
+			}
+
+			foreignKeys := [][5]string{
+				{"outgoing_webhooks", "webhook_id", "webhook_bots(id)", "CASCADE", "CASCADE"},
+				{"outgoing_webhooks", "channel_id", "channels(id)", "CASCADE", "CASCADE"},
+				{"outgoing_webhooks", "creator_id", "users(id)", "CASCADE", "CASCADE"},
+			}
+			for _, c := range foreignKeys {
+				if err := db.Table(c[0]).AddForeignKey(c[1], c[2], c[3], c[4]).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+type v35OutgoingWebhook struct {
+	ID          uuid.UUID `gorm:"type:char(36);not null;primary_key"`
+	WebhookID   uuid.UUID `gorm:"type:char(36);not null;index"`
+	ChannelID   uuid.UUID `gorm:"type:char(36);not null;index"`
+	TriggerType string    `gorm:"type:varchar(10);not null"`
+	Trigger     string    `gorm:"type:varchar(200);not null"`
+	URL         string    `gorm:"type:text;not null"`
+	Secret      string    `gorm:"type:varchar(64);not null"`
+	CreatorID   uuid.UUID `gorm:"type:char(36);not null"`
+	CreatedAt   time.Time `gorm:"precision:6"`
+	UpdatedAt   time.Time `gorm:"precision:6"`
+}
+
+func (*v35OutgoingWebhook) TableName() string {
+	return "outgoing_webhooks"
+}
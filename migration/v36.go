@@ -0,0 +1,35 @@
+package migration
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/jinzhu/gorm"
+	"gopkg.in/gormigrate.v1"
+	"time"
+)
+
+// v36 Web Push購読
+func v36() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "36",
+		Migrate: func(db *gorm.DB) error {
+			if err := db.AutoMigrate(&v36WebPushSubscription{}).Error; err != nil {
+				return err
+			}
+			return db.Table("web_push_subscriptions").AddForeignKey("user_id", "users(id)", "CASCADE", "CASCADE").Error
+		},
+	}
+}
+
+type v36WebPushSubscription struct {
+	ID        uuid.UUID `gorm:"type:char(36);not null;primary_key"`
+	UserID    uuid.UUID `gorm:"type:char(36);not null;index"`
+	Endpoint  string    `gorm:"type:text;not null"`
+	P256dh    string    `gorm:"type:varchar(100);not null"`
+	Auth      string    `gorm:"type:varchar(50);not null"`
+	CreatedAt time.Time `gorm:"precision:6"`
+	UpdatedAt time.Time `gorm:"precision:6"`
+}
+
+func (*v36WebPushSubscription) TableName() string {
+	return "web_push_subscriptions"
+}
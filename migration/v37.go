@@ -0,0 +1,35 @@
+package migration
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/jinzhu/gorm"
+	"gopkg.in/gormigrate.v1"
+	"time"
+)
+
+// v37 ダイジェストメール設定
+func v37() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "37",
+		Migrate: func(db *gorm.DB) error {
+			if err := db.AutoMigrate(&v37UserDigestSetting{}).Error; err != nil {
+				return err
+			}
+			return db.Table("user_digest_settings").AddForeignKey("user_id", "users(id)", "CASCADE", "CASCADE").Error
+		},
+	}
+}
+
+type v37UserDigestSetting struct {
+	UserID           uuid.UUID  `gorm:"type:char(36);not null;primary_key"`
+	Email            string     `gorm:"type:varchar(254);not null"`
+	Frequency        string     `gorm:"type:varchar(10);not null;index"`
+	UnsubscribeToken string     `gorm:"type:varchar(50);not null;unique"`
+	LastSentAt       *time.Time `gorm:"precision:6"`
+	CreatedAt        time.Time  `gorm:"precision:6"`
+	UpdatedAt        time.Time  `gorm:"precision:6"`
+}
+
+func (*v37UserDigestSetting) TableName() string {
+	return "user_digest_settings"
+}
@@ -0,0 +1,38 @@
+package migration
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/jinzhu/gorm"
+	"gopkg.in/gormigrate.v1"
+	"time"
+)
+
+// v38 通知設定・おやすみモード
+func v38() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "38",
+		Migrate: func(db *gorm.DB) error {
+			if err := db.AutoMigrate(&v38UserNotificationSetting{}).Error; err != nil {
+				return err
+			}
+			return db.Table("user_notification_settings").AddForeignKey("user_id", "users(id)", "CASCADE", "CASCADE").Error
+		},
+	}
+}
+
+type v38UserNotificationSetting struct {
+	UserID            uuid.UUID  `gorm:"type:char(36);not null;primary_key"`
+	QuietHoursEnabled bool       `gorm:"type:boolean;not null;default:false"`
+	QuietHoursStart   string     `gorm:"type:char(5);not null;default:'00:00'"`
+	QuietHoursEnd     string     `gorm:"type:char(5);not null;default:'00:00'"`
+	Timezone          string     `gorm:"type:varchar(64);not null;default:'UTC'"`
+	SnoozeUntil       *time.Time `gorm:"precision:6"`
+	Keywords          string     `gorm:"type:text;not null"`
+	MutedGroupIDs     string     `gorm:"type:text;not null"`
+	CreatedAt         time.Time  `gorm:"precision:6"`
+	UpdatedAt         time.Time  `gorm:"precision:6"`
+}
+
+func (*v38UserNotificationSetting) TableName() string {
+	return "user_notification_settings"
+}
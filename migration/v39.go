@@ -0,0 +1,35 @@
+package migration
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/jinzhu/gorm"
+	"gopkg.in/gormigrate.v1"
+	"time"
+)
+
+// v39 Botイベント送信キューのリース
+func v39() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "39",
+		Migrate: func(db *gorm.DB) error {
+			return db.AutoMigrate(&v39BotEventQueueItem{}).Error
+		},
+	}
+}
+
+type v39BotEventQueueItem struct {
+	ID            uuid.UUID  `gorm:"type:char(36);not null;primary_key"`
+	BotID         uuid.UUID  `gorm:"type:char(36);not null;index:bot_id_dead_idx"`
+	Event         string     `gorm:"type:varchar(30);not null"`
+	Body          string     `gorm:"type:text;not null"`
+	Attempts      int        `gorm:"not null;default:0"`
+	Dead          bool       `gorm:"type:boolean;not null;index:bot_id_dead_idx"`
+	NextAttemptAt time.Time  `gorm:"precision:6"`
+	LeasedUntil   *time.Time `gorm:"precision:6"` // 追加
+	CreatedAt     time.Time  `gorm:"precision:6"`
+	UpdatedAt     time.Time  `gorm:"precision:6"`
+}
+
+func (v39BotEventQueueItem) TableName() string {
+	return "bot_event_queue"
+}
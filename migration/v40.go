@@ -0,0 +1,36 @@
+package migration
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/jinzhu/gorm"
+	"gopkg.in/gormigrate.v1"
+	"time"
+)
+
+// v40 ダイジェストメールのアドレス確認と送信失敗時の再試行
+func v40() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "40",
+		Migrate: func(db *gorm.DB) error {
+			return db.AutoMigrate(&v40UserDigestSetting{}).Error
+		},
+	}
+}
+
+type v40UserDigestSetting struct {
+	UserID            uuid.UUID  `gorm:"type:char(36);not null;primary_key"`
+	Email             string     `gorm:"type:varchar(254);not null"`
+	Frequency         string     `gorm:"type:varchar(10);not null;index"`
+	UnsubscribeToken  string     `gorm:"type:varchar(50);not null;unique"`
+	EmailVerifiedAt   *time.Time `gorm:"precision:6"`                     // 追加
+	VerificationToken string     `gorm:"type:varchar(50);not null;index"` // 追加
+	FailedAttempts    int        `gorm:"not null;default:0"`              // 追加
+	RetryAt           *time.Time `gorm:"precision:6"`                     // 追加
+	LastSentAt        *time.Time `gorm:"precision:6"`
+	CreatedAt         time.Time  `gorm:"precision:6"`
+	UpdatedAt         time.Time  `gorm:"precision:6"`
+}
+
+func (*v40UserDigestSetting) TableName() string {
+	return "user_digest_settings"
+}
@@ -0,0 +1,51 @@
+package migration
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/jinzhu/gorm"
+	"github.com/traPtitech/traQ/model"
+	"gopkg.in/gormigrate.v1"
+	"time"
+)
+
+// v41 プライベートチャンネル名の一意制約の除外
+func v41() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "41",
+		Migrate: func(db *gorm.DB) error {
+			if err := db.AutoMigrate(&v41Channel{}).Error; err != nil {
+				return err
+			}
+			if err := db.Model(&v41Channel{}).Unscoped().
+				Where("parent_id = ?", model.PrivateChannelRootID).
+				UpdateColumn("name_scope", gorm.Expr("id")).
+				Error; err != nil {
+				return err
+			}
+			if err := db.Model(&v41Channel{}).RemoveIndex("name_parent").Error; err != nil {
+				return err
+			}
+			return db.Model(&v41Channel{}).AddUniqueIndex("name_parent", "name", "parent_id", "name_scope").Error
+		},
+	}
+}
+
+type v41Channel struct {
+	ID        uuid.UUID  `gorm:"type:char(36);not null;primary_key"`
+	Name      string     `gorm:"type:varchar(20);not null"`
+	ParentID  uuid.UUID  `gorm:"type:char(36);not null"`
+	NameScope string     `gorm:"type:char(36);not null;default:''"` // 追加
+	Topic     string     `sql:"type:TEXT COLLATE utf8mb4_bin NOT NULL"`
+	IsForced  bool       `gorm:"type:boolean;not null;default:false"`
+	IsPublic  bool       `gorm:"type:boolean;not null;default:false"`
+	IsVisible bool       `gorm:"type:boolean;not null;default:false"`
+	CreatorID uuid.UUID  `gorm:"type:char(36);not null"`
+	UpdaterID uuid.UUID  `gorm:"type:char(36);not null"`
+	CreatedAt time.Time  `gorm:"precision:6"`
+	UpdatedAt time.Time  `gorm:"precision:6"`
+	DeletedAt *time.Time `gorm:"precision:6"`
+}
+
+func (v41Channel) TableName() string {
+	return "channels"
+}
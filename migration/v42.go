@@ -0,0 +1,48 @@
+package migration
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/jinzhu/gorm"
+	"gopkg.in/gormigrate.v1"
+	"time"
+)
+
+// v42 BOTのVerification Token送信の廃止
+func v42() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "42",
+		Migrate: func(db *gorm.DB) error {
+			if err := db.AutoMigrate(&v42Bot{}).Error; err != nil {
+				return err
+			}
+			// 既存のBotは署名の検証に移行するまでVerification Tokenを送信し続ける
+			return db.Model(&v42Bot{}).Unscoped().UpdateColumn("legacy_verification_token", true).Error
+		},
+	}
+}
+
+type v42Bot struct {
+	ID                             uuid.UUID  `gorm:"type:char(36);not null;primary_key"`
+	BotUserID                      uuid.UUID  `gorm:"type:char(36);not null;unique"`
+	Description                    string     `gorm:"type:text;not null"`
+	VerificationToken              string     `gorm:"type:varchar(30);not null"`
+	SigningSecret                  string     `gorm:"type:varchar(64);not null;default:''"`
+	PreviousSigningSecret          string     `gorm:"type:varchar(64);not null;default:''"`
+	PreviousSigningSecretExpiresAt *time.Time `gorm:"precision:6"`
+	LegacyVerificationToken        bool       `gorm:"type:boolean;not null;default:false"` // 追加
+	AccessTokenID                  uuid.UUID  `gorm:"type:char(36);not null"`
+	Mode                           string     `gorm:"type:varchar(30);not null;default:'HTTP'"`
+	PostURL                        string     `gorm:"type:text;not null"`
+	SubscribeEvents                string     `gorm:"type:text;not null"`
+	Privileged                     bool       `gorm:"type:boolean;not null;default:false"`
+	State                          int        `gorm:"type:tinyint;not null;default:0"`
+	BotCode                        string     `gorm:"type:varchar(30);not null;unique"`
+	CreatorID                      uuid.UUID  `gorm:"type:char(36);not null"`
+	CreatedAt                      time.Time  `gorm:"precision:6"`
+	UpdatedAt                      time.Time  `gorm:"precision:6"`
+	DeletedAt                      *time.Time `gorm:"precision:6"`
+}
+
+func (*v42Bot) TableName() string {
+	return "bots"
+}
@@ -0,0 +1,36 @@
+package migration
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/jinzhu/gorm"
+	"gopkg.in/gormigrate.v1"
+	"time"
+)
+
+// v43 Botスラッシュコマンド名の一意制約をBot毎に変更
+func v43() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "43",
+		Migrate: func(db *gorm.DB) error {
+			if err := db.Model(&v43BotCommand{}).RemoveIndex("name").Error; err != nil {
+				return err
+			}
+			return db.AutoMigrate(&v43BotCommand{}).Error
+		},
+	}
+}
+
+type v43BotCommand struct {
+	ID          uuid.UUID `gorm:"type:char(36);not null;primary_key"`
+	BotID       uuid.UUID `gorm:"type:char(36);not null;index;unique_index:bot_id_name"` // 変更
+	Name        string    `gorm:"type:varchar(32);not null;unique_index:bot_id_name"`    // 変更
+	Description string    `gorm:"type:text;not null"`
+	Arguments   string    `gorm:"type:text;not null"`
+	ChannelIDs  string    `gorm:"type:text;not null"`
+	CreatedAt   time.Time `gorm:"precision:6"`
+	UpdatedAt   time.Time `gorm:"precision:6"`
+}
+
+func (v43BotCommand) TableName() string {
+	return "bot_commands"
+}
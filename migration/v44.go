@@ -0,0 +1,17 @@
+package migration
+
+import (
+	"github.com/jinzhu/gorm"
+	"gopkg.in/gormigrate.v1"
+)
+
+// v44 Botイベント送信キューのペイロードの型をmediumtextに変更
+func v44() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "44",
+		Migrate: func(db *gorm.DB) error {
+			// textは64KiBまでのため、大きなペイロードを保存できるようにする
+			return db.Table("bot_event_queue").ModifyColumn("body", "mediumtext not null").Error
+		},
+	}
+}
@@ -3,6 +3,7 @@ package model
 import (
 	"database/sql/driver"
 	"errors"
+	"fmt"
 	"github.com/gofrs/uuid"
 	"github.com/json-iterator/go"
 	"strings"
@@ -21,22 +22,46 @@ const (
 	BotPaused BotState = 2
 )
 
+// BotMode Botのイベント配送モード
+type BotMode string
+
+const (
+	// BotModeHTTP BOTのエンドポイントにHTTP POSTでイベントを配送する
+	BotModeHTTP BotMode = "HTTP"
+	// BotModeWebSocket BOTが接続したWebSocketでイベントを配送する
+	BotModeWebSocket BotMode = "WebSocket"
+)
+
+// Valid 有効なモードかどうか
+func (m BotMode) Valid() bool {
+	return m == BotModeHTTP || m == BotModeWebSocket
+}
+
+func (m BotMode) String() string {
+	return string(m)
+}
+
 // Bot Bot構造体
 type Bot struct {
-	ID                uuid.UUID     `gorm:"type:char(36);not null;primary_key"`
-	BotUserID         uuid.UUID     `gorm:"type:char(36);not null;unique"`
-	Description       string        `gorm:"type:text;not null"`
-	VerificationToken string        `gorm:"type:varchar(30);not null"`
-	AccessTokenID     uuid.UUID     `gorm:"type:char(36);not null"`
-	PostURL           string        `gorm:"type:text;not null"`
-	SubscribeEvents   BotEventTypes `gorm:"type:text;not null"`
-	Privileged        bool          `gorm:"type:boolean;not null;default:false"`
-	State             BotState      `gorm:"type:tinyint;not null;default:0"`
-	BotCode           string        `gorm:"type:varchar(30);not null;unique"`
-	CreatorID         uuid.UUID     `gorm:"type:char(36);not null"`
-	CreatedAt         time.Time     `gorm:"precision:6"`
-	UpdatedAt         time.Time     `gorm:"precision:6"`
-	DeletedAt         *time.Time    `gorm:"precision:6"`
+	ID                             uuid.UUID     `gorm:"type:char(36);not null;primary_key"`
+	BotUserID                      uuid.UUID     `gorm:"type:char(36);not null;unique"`
+	Description                    string        `gorm:"type:text;not null"`
+	VerificationToken              string        `gorm:"type:varchar(30);not null"`
+	SigningSecret                  string        `gorm:"type:varchar(64);not null;default:''"`
+	PreviousSigningSecret          string        `gorm:"type:varchar(64);not null;default:''"`
+	PreviousSigningSecretExpiresAt *time.Time    `gorm:"precision:6"`
+	LegacyVerificationToken        bool          `gorm:"type:boolean;not null;default:false"`
+	AccessTokenID                  uuid.UUID     `gorm:"type:char(36);not null"`
+	Mode                           BotMode       `gorm:"type:varchar(30);not null;default:'HTTP'"`
+	PostURL                        string        `gorm:"type:text;not null"`
+	SubscribeEvents                BotEventTypes `gorm:"type:text;not null"`
+	Privileged                     bool          `gorm:"type:boolean;not null;default:false"`
+	State                          BotState      `gorm:"type:tinyint;not null;default:0"`
+	BotCode                        string        `gorm:"type:varchar(30);not null;unique"`
+	CreatorID                      uuid.UUID     `gorm:"type:char(36);not null"`
+	CreatedAt                      time.Time     `gorm:"precision:6"`
+	UpdatedAt                      time.Time     `gorm:"precision:6"`
+	DeletedAt                      *time.Time    `gorm:"precision:6"`
 }
 
 // TableName Botのテーブル名
@@ -44,6 +69,17 @@ func (*Bot) TableName() string {
 	return "bots"
 }
 
+// SigningSecrets 時刻nowにおいて有効な署名用シークレットを返します
+//
+// 1つ目は現在のシークレットで、ローテーション後の猶予期間中は2つ目に以前のシークレットが含まれます。
+func (b *Bot) SigningSecrets(now time.Time) []string {
+	secrets := []string{b.SigningSecret}
+	if len(b.PreviousSigningSecret) > 0 && b.PreviousSigningSecretExpiresAt != nil && now.Before(*b.PreviousSigningSecretExpiresAt) {
+		secrets = append(secrets, b.PreviousSigningSecret)
+	}
+	return secrets
+}
+
 // BotJoinChannel Bot参加チャンネル構造体
 type BotJoinChannel struct {
 	ChannelID uuid.UUID `gorm:"type:char(36);not null;primary_key"`
@@ -60,6 +96,7 @@ type BotEventLog struct {
 	RequestID uuid.UUID    `gorm:"type:char(36);not null;primary_key"                json:"requestId"`
 	BotID     uuid.UUID    `gorm:"type:char(36);not null;index:bot_id_date_time_idx" json:"botId"`
 	Event     BotEventType `gorm:"type:varchar(30);not null"                         json:"event"`
+	Mode      BotMode      `gorm:"type:varchar(30);not null;default:'HTTP'"          json:"mode"`
 	Body      string       `gorm:"type:text"                                         json:"-"`
 	Error     string       `gorm:"type:text"                                         json:"-"`
 	Code      int          `gorm:"not null;default:0"                                json:"code"`
@@ -72,6 +109,130 @@ func (*BotEventLog) TableName() string {
 	return "bot_event_logs"
 }
 
+// BotEventQueueItem Botイベント送信キューの要素
+//
+// 送信に成功するまでBOT毎に作成順で再送されます。
+// 再送回数の上限に達した要素はDeadとなり、再送されなくなります。
+// 送信中の要素はLeasedUntilまでリースされ、他のノードからは送信されません。
+type BotEventQueueItem struct {
+	ID            uuid.UUID    `gorm:"type:char(36);not null;primary_key"           json:"id"`
+	BotID         uuid.UUID    `gorm:"type:char(36);not null;index:bot_id_dead_idx" json:"botId"`
+	Event         BotEventType `gorm:"type:varchar(30);not null"                    json:"event"`
+	Body          string       `gorm:"type:mediumtext;not null"                     json:"-"`
+	Attempts      int          `gorm:"not null;default:0"                           json:"attempts"`
+	Dead          bool         `gorm:"type:boolean;not null;index:bot_id_dead_idx"  json:"-"`
+	NextAttemptAt time.Time    `gorm:"precision:6"                                  json:"-"`
+	LeasedUntil   *time.Time   `gorm:"precision:6"                                  json:"-"`
+	CreatedAt     time.Time    `gorm:"precision:6"                                  json:"createdAt"`
+	UpdatedAt     time.Time    `gorm:"precision:6"                                  json:"updatedAt"`
+}
+
+// TableName BotEventQueueItemのテーブル名
+func (*BotEventQueueItem) TableName() string {
+	return "bot_event_queue"
+}
+
+// BotCommand Botのスラッシュコマンド
+type BotCommand struct {
+	ID          uuid.UUID           `gorm:"type:char(36);not null;primary_key"                   json:"id"`
+	BotID       uuid.UUID           `gorm:"type:char(36);not null;index;unique_index:bot_id_name" json:"botId"`
+	Name        string              `gorm:"type:varchar(32);not null;unique_index:bot_id_name"   json:"name"`
+	Description string              `gorm:"type:text;not null"                                   json:"description"`
+	Arguments   BotCommandArguments `gorm:"type:text;not null"                                   json:"arguments"`
+	ChannelIDs  UUIDs               `gorm:"type:text;not null"                                   json:"channelIds"`
+	CreatedAt   time.Time           `gorm:"precision:6"                                          json:"createdAt"`
+	UpdatedAt   time.Time           `gorm:"precision:6"                                          json:"updatedAt"`
+}
+
+// TableName BotCommandのテーブル名
+func (*BotCommand) TableName() string {
+	return "bot_commands"
+}
+
+// AvailableIn 指定したチャンネルでコマンドが使用可能かどうか
+//
+// ChannelIDsが空の場合は全てのチャンネルで使用可能です。
+func (c *BotCommand) AvailableIn(channelID uuid.UUID) bool {
+	if len(c.ChannelIDs) == 0 {
+		return true
+	}
+	for _, id := range c.ChannelIDs {
+		if id == channelID {
+			return true
+		}
+	}
+	return false
+}
+
+// Overlaps 使用可能なチャンネルが指定したコマンドと重複しているかどうか
+func (c *BotCommand) Overlaps(o *BotCommand) bool {
+	if len(c.ChannelIDs) == 0 || len(o.ChannelIDs) == 0 {
+		return true
+	}
+	for _, id := range o.ChannelIDs {
+		if c.AvailableIn(id) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseArguments コマンドの引数文字列を引数定義に従って解釈します
+//
+// 引数は空白区切りで定義順に割り当てられ、最後の引数には残り全てが割り当てられます。
+// 必須引数が不足している場合はエラーを返します。
+func (c *BotCommand) ParseArguments(s string) (map[string]string, error) {
+	args := make(map[string]string, len(c.Arguments))
+	fields := strings.Fields(s)
+	for i, arg := range c.Arguments {
+		if i >= len(fields) {
+			if arg.Required {
+				return nil, fmt.Errorf("argument '%s' is required", arg.Name)
+			}
+			continue
+		}
+		if i == len(c.Arguments)-1 {
+			args[arg.Name] = strings.Join(fields[i:], " ")
+		} else {
+			args[arg.Name] = fields[i]
+		}
+	}
+	return args, nil
+}
+
+// BotCommandArgument Botのスラッシュコマンドの引数定義
+type BotCommandArgument struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Required    bool   `json:"required"`
+}
+
+// BotCommandArguments Botのスラッシュコマンドの引数定義の配列
+type BotCommandArguments []BotCommandArgument
+
+// Value database/sql/driver.Valuer 実装
+func (args BotCommandArguments) Value() (driver.Value, error) {
+	if args == nil {
+		return "[]", nil
+	}
+	return json.MarshalToString(args)
+}
+
+// Scan database/sql.Scanner 実装
+func (args *BotCommandArguments) Scan(src interface{}) error {
+	*args = BotCommandArguments{}
+	switch s := src.(type) {
+	case nil:
+		return nil
+	case string:
+		return json.Unmarshal([]byte(s), args)
+	case []byte:
+		return json.Unmarshal(s, args)
+	default:
+		return errors.New("failed to scan BotCommandArguments")
+	}
+}
+
 // BotEventType Botイベントタイプ
 type BotEventType string
 
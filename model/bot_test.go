@@ -1,9 +1,11 @@
 package model
 
 import (
+	"github.com/gofrs/uuid"
 	"github.com/stretchr/testify/assert"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestBot_TableName(t *testing.T) {
@@ -11,6 +13,19 @@ func TestBot_TableName(t *testing.T) {
 	assert.Equal(t, "bots", (&Bot{}).TableName())
 }
 
+func TestBot_SigningSecrets(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Minute)
+
+	assert.Equal(t, []string{"a"}, (&Bot{SigningSecret: "a"}).SigningSecrets(now))
+	assert.Equal(t, []string{"a"}, (&Bot{SigningSecret: "a", PreviousSigningSecret: "b", PreviousSigningSecretExpiresAt: &past}).SigningSecrets(now))
+	assert.Equal(t, []string{"a", "b"}, (&Bot{SigningSecret: "a", PreviousSigningSecret: "b", PreviousSigningSecretExpiresAt: &future}).SigningSecrets(now))
+	assert.Equal(t, []string{"a"}, (&Bot{SigningSecret: "a", PreviousSigningSecretExpiresAt: &future}).SigningSecrets(now))
+}
+
 func TestBotJoinChannel_TableName(t *testing.T) {
 	t.Parallel()
 	assert.Equal(t, "bot_join_channels", (&BotJoinChannel{}).TableName())
@@ -21,6 +36,85 @@ func TestBotEventLog_TableName(t *testing.T) {
 	assert.Equal(t, "bot_event_logs", (&BotEventLog{}).TableName())
 }
 
+func TestBotCommand_TableName(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "bot_commands", (&BotCommand{}).TableName())
+}
+
+func TestBotCommand_AvailableIn(t *testing.T) {
+	t.Parallel()
+
+	ch1 := uuid.NewV3(uuid.Nil, "c1")
+	ch2 := uuid.NewV3(uuid.Nil, "c2")
+	assert.True(t, (&BotCommand{}).AvailableIn(ch1))
+	assert.True(t, (&BotCommand{ChannelIDs: UUIDs{ch1}}).AvailableIn(ch1))
+	assert.False(t, (&BotCommand{ChannelIDs: UUIDs{ch1}}).AvailableIn(ch2))
+}
+
+func TestBotCommand_Overlaps(t *testing.T) {
+	t.Parallel()
+
+	ch1 := uuid.NewV3(uuid.Nil, "c1")
+	ch2 := uuid.NewV3(uuid.Nil, "c2")
+	ch3 := uuid.NewV3(uuid.Nil, "c3")
+	assert.True(t, (&BotCommand{}).Overlaps(&BotCommand{ChannelIDs: UUIDs{ch1}}))
+	assert.True(t, (&BotCommand{ChannelIDs: UUIDs{ch1}}).Overlaps(&BotCommand{}))
+	assert.True(t, (&BotCommand{ChannelIDs: UUIDs{ch1, ch2}}).Overlaps(&BotCommand{ChannelIDs: UUIDs{ch2, ch3}}))
+	assert.False(t, (&BotCommand{ChannelIDs: UUIDs{ch1}}).Overlaps(&BotCommand{ChannelIDs: UUIDs{ch2, ch3}}))
+}
+
+func TestBotCommand_ParseArguments(t *testing.T) {
+	t.Parallel()
+
+	c := &BotCommand{
+		Arguments: BotCommandArguments{
+			{Name: "env", Required: true},
+			{Name: "comment"},
+		},
+	}
+
+	t.Run("all", func(t *testing.T) {
+		t.Parallel()
+		args, err := c.ParseArguments("  production  release v1.0 ")
+		if assert.NoError(t, err) {
+			assert.Equal(t, map[string]string{"env": "production", "comment": "release v1.0"}, args)
+		}
+	})
+
+	t.Run("optional omitted", func(t *testing.T) {
+		t.Parallel()
+		args, err := c.ParseArguments("staging")
+		if assert.NoError(t, err) {
+			assert.Equal(t, map[string]string{"env": "staging"}, args)
+		}
+	})
+
+	t.Run("required missing", func(t *testing.T) {
+		t.Parallel()
+		_, err := c.ParseArguments("")
+		assert.Error(t, err)
+	})
+}
+
+func TestBotCommandArguments_Value(t *testing.T) {
+	t.Parallel()
+
+	v, err := BotCommandArguments(nil).Value()
+	if assert.NoError(t, err) {
+		assert.Equal(t, "[]", v)
+	}
+}
+
+func TestBotCommandArguments_Scan(t *testing.T) {
+	t.Parallel()
+
+	var args BotCommandArguments
+	if assert.NoError(t, args.Scan(`[{"name":"env","description":"","required":true}]`)) {
+		assert.Equal(t, BotCommandArguments{{Name: "env", Required: true}}, args)
+	}
+	assert.Error(t, args.Scan(1))
+}
+
 func TestBotEventType_String(t *testing.T) {
 	t.Parallel()
 	assert.Equal(t, "event", BotEventType("event").String())
@@ -93,3 +187,12 @@ func TestBotEventTypes_MarshalJSON(t *testing.T) {
 	assert.NoError(t, err)
 	assert.ElementsMatch(t, []string{`"PING"`, `"PONG"`}, strings.Split(strings.Trim(string(b), "[]"), ","))
 }
+
+func TestBotMode_Valid(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, BotModeHTTP.Valid())
+	assert.True(t, BotModeWebSocket.Valid())
+	assert.False(t, BotMode("").Valid())
+	assert.False(t, BotMode("http").Valid())
+}
@@ -10,17 +10,26 @@ import (
 const (
 	// DirectMessageChannelRootID ダイレクトメッセージチャンネルの親チャンネルID
 	DirectMessageChannelRootID = "aaaaaaaa-aaaa-4aaa-aaaa-aaaaaaaaaaaa"
+	// PrivateChannelRootID プライベートチャンネルの親チャンネルID
+	PrivateChannelRootID = "bbbbbbbb-bbbb-4bbb-bbbb-bbbbbbbbbbbb"
 	// MaxChannelDepth チャンネルの深さの最大
 	MaxChannelDepth = 5
 )
 
-var dmChannelRootUUID = uuid.Must(uuid.FromString(DirectMessageChannelRootID))
+var (
+	dmChannelRootUUID      = uuid.Must(uuid.FromString(DirectMessageChannelRootID))
+	privateChannelRootUUID = uuid.Must(uuid.FromString(PrivateChannelRootID))
+)
 
 // Channel チャンネルの構造体
+//
+// チャンネル名は (Name, ParentID, NameScope) で一意です。NameScope はプライベートチャンネルでは自身のID、それ以外では空です。
+// プライベートチャンネル同士は名前が衝突しないため、作成の可否から他人のプライベートチャンネルの存在が分かることはありません。
 type Channel struct {
 	ID        uuid.UUID  `gorm:"type:char(36);not null;primary_key"`
 	Name      string     `gorm:"type:varchar(20);not null;unique_index:name_parent"`
 	ParentID  uuid.UUID  `gorm:"type:char(36);not null;unique_index:name_parent"`
+	NameScope string     `gorm:"type:char(36);not null;default:'';unique_index:name_parent"`
 	Topic     string     `sql:"type:TEXT COLLATE utf8mb4_bin NOT NULL"`
 	IsForced  bool       `gorm:"type:boolean;not null;default:false"`
 	IsPublic  bool       `gorm:"type:boolean;not null;default:false"`
@@ -44,6 +53,11 @@ func (ch *Channel) IsDMChannel() bool {
 	return ch.ParentID == dmChannelRootUUID
 }
 
+// IsPrivateChannel DM以外のプライベートチャンネルかどうかを返します
+func (ch *Channel) IsPrivateChannel() bool {
+	return ch.ParentID == privateChannelRootUUID
+}
+
 // IsArchived アーカイブされているチャンネルかどうか
 func (ch *Channel) IsArchived() bool {
 	return !ch.IsVisible
@@ -171,6 +185,28 @@ const (
 	// 	userId    作成者UUID
 	// 	channelId チャンネルUUID
 	ChannelEventChildCreated = ChannelEventType("ChildCreated")
+	// ChannelEventMemberAdded チャンネルイベント プライベートチャンネルメンバー追加
+	//
+	// 	userId   変更者UUID
+	// 	memberId 追加されたユーザーのUUID
+	ChannelEventMemberAdded = ChannelEventType("MemberAdded")
+	// ChannelEventMemberRemoved チャンネルイベント プライベートチャンネルメンバー削除
+	//
+	// 	userId   変更者UUID
+	// 	memberId 削除されたユーザーのUUID
+	ChannelEventMemberRemoved = ChannelEventType("MemberRemoved")
+	// ChannelEventMerged チャンネルイベント チャンネル統合
+	//
+	// 	userId    実行者UUID
+	// 	channelId 統合元チャンネルUUID
+	// 	name      統合元チャンネルのパス
+	ChannelEventMerged = ChannelEventType("Merged")
+	// ChannelEventChildDeleted チャンネルイベント 子チャンネル削除
+	//
+	// 	userId    実行者UUID
+	// 	channelId 削除されたチャンネルUUID
+	// 	name      削除されたチャンネル名
+	ChannelEventChildDeleted = ChannelEventType("ChildDeleted")
 )
 
 // ChannelEventDetail チャンネルイベント詳細
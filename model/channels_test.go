@@ -17,6 +17,13 @@ func TestChannel_IsDMChannel(t *testing.T) {
 	assert.True(t, (&Channel{ParentID: dmChannelRootUUID}).IsDMChannel())
 }
 
+func TestChannel_IsPrivateChannel(t *testing.T) {
+	t.Parallel()
+	assert.False(t, (&Channel{ParentID: uuid.Nil}).IsPrivateChannel())
+	assert.False(t, (&Channel{ParentID: dmChannelRootUUID}).IsPrivateChannel())
+	assert.True(t, (&Channel{ParentID: privateChannelRootUUID}).IsPrivateChannel())
+}
+
 func TestUsersPrivateChannel_TableName(t *testing.T) {
 	t.Parallel()
 	assert.Equal(t, "users_private_channels", (&UsersPrivateChannel{}).TableName())
@@ -0,0 +1,20 @@
+package model
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/traPtitech/traQ/utils/optional"
+	"time"
+)
+
+// MessageAuthorOverride Webhookによって投稿されたメッセージの投稿者表示の上書き設定構造体
+type MessageAuthorOverride struct {
+	MessageID   uuid.UUID     `gorm:"type:char(36);not null;primary_key"`
+	DisplayName string        `gorm:"type:varchar(64);not null;default:''"`
+	IconFileID  optional.UUID `gorm:"type:char(36)"`
+	CreatedAt   time.Time     `gorm:"precision:6"`
+}
+
+// TableName MessageAuthorOverride構造体のテーブル名
+func (*MessageAuthorOverride) TableName() string {
+	return "message_author_overrides"
+}
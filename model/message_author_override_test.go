@@ -0,0 +1,11 @@
+package model
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestMessageAuthorOverride_TableName(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "message_author_overrides", (&MessageAuthorOverride{}).TableName())
+}
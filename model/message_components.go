@@ -0,0 +1,106 @@
+package model
+
+import (
+	"database/sql/driver"
+	"errors"
+	"github.com/gofrs/uuid"
+	"time"
+)
+
+// MessageComponentType メッセージコンポーネントの種類
+type MessageComponentType string
+
+const (
+	// MessageComponentTypeButton ボタン
+	MessageComponentTypeButton MessageComponentType = "button"
+	// MessageComponentTypeSelect セレクトメニュー
+	MessageComponentTypeSelect MessageComponentType = "select"
+)
+
+// Valid 有効な種類かどうか
+func (t MessageComponentType) Valid() bool {
+	return t == MessageComponentTypeButton || t == MessageComponentTypeSelect
+}
+
+// MessageComponentSet メッセージに添付されたインタラクティブコンポーネント
+type MessageComponentSet struct {
+	MessageID  uuid.UUID         `gorm:"type:char(36);not null;primary_key"`
+	Components MessageComponents `gorm:"type:text;not null"`
+	CreatedAt  time.Time         `gorm:"precision:6"`
+	UpdatedAt  time.Time         `gorm:"precision:6"`
+}
+
+// TableName MessageComponentSetのテーブル名
+func (*MessageComponentSet) TableName() string {
+	return "message_components"
+}
+
+// MessageComponent メッセージのインタラクティブコンポーネント
+type MessageComponent struct {
+	ID      string                   `json:"id"`
+	Type    MessageComponentType     `json:"type"`
+	Label   string                   `json:"label"`
+	Style   string                   `json:"style,omitempty"`
+	Options []MessageComponentOption `json:"options,omitempty"`
+	Confirm *MessageComponentConfirm `json:"confirm,omitempty"`
+}
+
+// HasOption 指定した値の選択肢を持っているかどうか
+func (c *MessageComponent) HasOption(value string) bool {
+	for _, o := range c.Options {
+		if o.Value == value {
+			return true
+		}
+	}
+	return false
+}
+
+// MessageComponentOption セレクトメニューの選択肢
+type MessageComponentOption struct {
+	Label string `json:"label"`
+	Value string `json:"value"`
+}
+
+// MessageComponentConfirm コンポーネント操作時の確認ダイアログ
+type MessageComponentConfirm struct {
+	Title  string `json:"title"`
+	Text   string `json:"text"`
+	OK     string `json:"ok"`
+	Cancel string `json:"cancel"`
+}
+
+// MessageComponents メッセージのインタラクティブコンポーネントの配列
+type MessageComponents []MessageComponent
+
+// Find 指定したIDのコンポーネントを返します。存在しない場合はnilを返します
+func (cs MessageComponents) Find(id string) *MessageComponent {
+	for i := range cs {
+		if cs[i].ID == id {
+			return &cs[i]
+		}
+	}
+	return nil
+}
+
+// Value database/sql/driver.Valuer 実装
+func (cs MessageComponents) Value() (driver.Value, error) {
+	if cs == nil {
+		return "[]", nil
+	}
+	return json.MarshalToString(cs)
+}
+
+// Scan database/sql.Scanner 実装
+func (cs *MessageComponents) Scan(src interface{}) error {
+	*cs = MessageComponents{}
+	switch s := src.(type) {
+	case nil:
+		return nil
+	case string:
+		return json.Unmarshal([]byte(s), cs)
+	case []byte:
+		return json.Unmarshal(s, cs)
+	default:
+		return errors.New("failed to scan MessageComponents")
+	}
+}
@@ -0,0 +1,57 @@
+package model
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestMessageComponentSet_TableName(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "message_components", (&MessageComponentSet{}).TableName())
+}
+
+func TestMessageComponentType_Valid(t *testing.T) {
+	t.Parallel()
+	assert.True(t, MessageComponentTypeButton.Valid())
+	assert.True(t, MessageComponentTypeSelect.Valid())
+	assert.False(t, MessageComponentType("link").Valid())
+}
+
+func TestMessageComponents_Find(t *testing.T) {
+	t.Parallel()
+
+	cs := MessageComponents{
+		{ID: "approve", Type: MessageComponentTypeButton},
+		{ID: "env", Type: MessageComponentTypeSelect},
+	}
+	if c := cs.Find("env"); assert.NotNil(t, c) {
+		assert.Equal(t, MessageComponentTypeSelect, c.Type)
+	}
+	assert.Nil(t, cs.Find("reject"))
+}
+
+func TestMessageComponent_HasOption(t *testing.T) {
+	t.Parallel()
+
+	c := &MessageComponent{
+		Type:    MessageComponentTypeSelect,
+		Options: []MessageComponentOption{{Label: "本番", Value: "production"}},
+	}
+	assert.True(t, c.HasOption("production"))
+	assert.False(t, c.HasOption("staging"))
+}
+
+func TestMessageComponents_Scan(t *testing.T) {
+	t.Parallel()
+
+	var cs MessageComponents
+	if assert.NoError(t, cs.Scan(`[{"id":"ok","type":"button","label":"OK"}]`)) {
+		assert.Equal(t, MessageComponents{{ID: "ok", Type: MessageComponentTypeButton, Label: "OK"}}, cs)
+	}
+	assert.Error(t, cs.Scan(1))
+
+	v, err := MessageComponents(nil).Value()
+	if assert.NoError(t, err) {
+		assert.Equal(t, "[]", v)
+	}
+}
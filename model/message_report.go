@@ -2,20 +2,49 @@ package model
 
 import (
 	"github.com/gofrs/uuid"
+	"github.com/traPtitech/traQ/utils/optional"
 	"time"
 )
 
+const (
+	// MessageReportStatusOpen 未対応の通報
+	MessageReportStatusOpen = "open"
+	// MessageReportStatusResolved 対応済みの通報
+	MessageReportStatusResolved = "resolved"
+	// MessageReportStatusDismissed 却下された通報
+	MessageReportStatusDismissed = "dismissed"
+)
+
+const (
+	// MessageReportActionNone メッセージに対して何もしない
+	MessageReportActionNone = "none"
+	// MessageReportActionHide メッセージを非表示にする
+	MessageReportActionHide = "hide"
+	// MessageReportActionDelete メッセージを削除する
+	MessageReportActionDelete = "delete"
+)
+
 // MessageReport メッセージレポート構造体
 type MessageReport struct {
-	ID        uuid.UUID  `gorm:"type:char(36);not null;primary_key"                   json:"id"`
-	MessageID uuid.UUID  `gorm:"type:char(36);not null;unique_index:message_reporter" json:"messageId"`
-	Reporter  uuid.UUID  `gorm:"type:char(36);not null;unique_index:message_reporter" json:"reporter"`
-	Reason    string     `sql:"type:TEXT COLLATE utf8mb4_bin NOT NULL"                json:"reason"`
-	CreatedAt time.Time  `gorm:"precision:6;index"                                    json:"createdAt"`
-	DeletedAt *time.Time `gorm:"precision:6"                                          json:"-"`
+	ID        uuid.UUID     `gorm:"type:char(36);not null;primary_key"                   json:"id"`
+	MessageID uuid.UUID     `gorm:"type:char(36);not null;unique_index:message_reporter" json:"messageId"`
+	Reporter  uuid.UUID     `gorm:"type:char(36);not null;unique_index:message_reporter" json:"reporter"`
+	Reason    string        `sql:"type:TEXT COLLATE utf8mb4_bin NOT NULL"                json:"reason"`
+	Status    string        `gorm:"type:varchar(20);not null;default:'open';index"       json:"status"`
+	Action    string        `gorm:"type:varchar(20);not null;default:''"                 json:"action"`
+	Note      string        `sql:"type:TEXT COLLATE utf8mb4_bin NOT NULL"                json:"note"`
+	HandlerID optional.UUID `gorm:"type:char(36)"                                         json:"handlerId"`
+	HandledAt optional.Time `gorm:"precision:6"                                          json:"handledAt"`
+	CreatedAt time.Time     `gorm:"precision:6;index"                                    json:"createdAt"`
+	DeletedAt *time.Time    `gorm:"precision:6"                                          json:"-"`
 }
 
 // TableName MessageReport構造体のテーブル名
 func (*MessageReport) TableName() string {
 	return "message_reports"
 }
+
+// IsOpen 未対応の通報かどうか
+func (r *MessageReport) IsOpen() bool {
+	return r.Status == MessageReportStatusOpen
+}
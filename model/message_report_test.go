@@ -9,3 +9,10 @@ func TestMessageReport_TableName(t *testing.T) {
 	t.Parallel()
 	assert.Equal(t, "message_reports", (&MessageReport{}).TableName())
 }
+
+func TestMessageReport_IsOpen(t *testing.T) {
+	t.Parallel()
+	assert.True(t, (&MessageReport{Status: MessageReportStatusOpen}).IsOpen())
+	assert.False(t, (&MessageReport{Status: MessageReportStatusResolved}).IsOpen())
+	assert.False(t, (&MessageReport{Status: MessageReportStatusDismissed}).IsOpen())
+}
@@ -0,0 +1,22 @@
+package model
+
+import (
+	"github.com/gofrs/uuid"
+	"time"
+)
+
+// MessageSearchIndex メッセージ全文検索用インデックスの構造体
+type MessageSearchIndex struct {
+	MessageID uuid.UUID `gorm:"type:char(36);not null;primary_key"`
+	ChannelID uuid.UUID `gorm:"type:char(36);not null;index"`
+	UserID    uuid.UUID `gorm:"type:char(36);not null;index"`
+	PlainText string    `sql:"type:TEXT COLLATE utf8mb4_general_ci NOT NULL"`
+	HasFile   bool      `gorm:"type:boolean;not null;default:false"`
+	CreatedAt time.Time `gorm:"precision:6;index"`
+	UpdatedAt time.Time `gorm:"precision:6"`
+}
+
+// TableName MessageSearchIndex構造体のテーブル名
+func (*MessageSearchIndex) TableName() string {
+	return "messages_search_index"
+}
@@ -0,0 +1,11 @@
+package model
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestMessageSearchIndex_TableName(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "messages_search_index", (&MessageSearchIndex{}).TableName())
+}
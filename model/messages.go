@@ -2,21 +2,27 @@ package model
 
 import (
 	"github.com/gofrs/uuid"
+	"github.com/traPtitech/traQ/utils/optional"
 	"time"
 )
 
 // Message データベースに格納するmessageの構造体
 type Message struct {
-	ID        uuid.UUID  `gorm:"type:char(36);not null;primary_key"`
-	UserID    uuid.UUID  `gorm:"type:char(36);not null;"`
-	ChannelID uuid.UUID  `gorm:"type:char(36);not null;index"`
-	Text      string     `sql:"type:TEXT COLLATE utf8mb4_bin NOT NULL"`
-	CreatedAt time.Time  `gorm:"precision:6;index"`
-	UpdatedAt time.Time  `gorm:"precision:6"`
-	DeletedAt *time.Time `gorm:"precision:6"`
+	ID         uuid.UUID     `gorm:"type:char(36);not null;primary_key"`
+	UserID     uuid.UUID     `gorm:"type:char(36);not null;"`
+	ChannelID  uuid.UUID     `gorm:"type:char(36);not null;index"`
+	Text       string        `sql:"type:TEXT COLLATE utf8mb4_bin NOT NULL"`
+	ParentID   optional.UUID `gorm:"type:char(36)"`
+	ReplyCount int           `gorm:"type:int;not null;default:0"`
+	Hidden     bool          `gorm:"type:boolean;not null;default:false"`
+	CreatedAt  time.Time     `gorm:"precision:6;index"`
+	UpdatedAt  time.Time     `gorm:"precision:6"`
+	DeletedAt  *time.Time    `gorm:"precision:6"`
 
-	Stamps []MessageStamp `gorm:"association_autoupdate:false;association_autocreate:false;preload:false;foreignkey:MessageID"`
-	Pin    *Pin           `gorm:"association_autoupdate:false;association_autocreate:false;preload:false;foreignkey:MessageID"`
+	Stamps         []MessageStamp         `gorm:"association_autoupdate:false;association_autocreate:false;preload:false;foreignkey:MessageID"`
+	Pin            *Pin                   `gorm:"association_autoupdate:false;association_autocreate:false;preload:false;foreignkey:MessageID"`
+	Components     *MessageComponentSet   `gorm:"association_autoupdate:false;association_autocreate:false;preload:false;foreignkey:MessageID"`
+	AuthorOverride *MessageAuthorOverride `gorm:"association_autoupdate:false;association_autocreate:false;preload:false;foreignkey:MessageID"`
 }
 
 // TableName DBの名前を指定するメソッド
@@ -24,6 +30,11 @@ func (m Message) TableName() string {
 	return "messages"
 }
 
+// IsReply スレッドへの返信かどうか
+func (m *Message) IsReply() bool {
+	return m.ParentID.Valid
+}
+
 // ChannelLatestMessage チャンネル別最新メッセージ
 type ChannelLatestMessage struct {
 	ChannelID uuid.UUID `gorm:"type:char(36);not null;primary_key"`
@@ -0,0 +1,76 @@
+package model
+
+import (
+	"github.com/gofrs/uuid"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// OutgoingWebhookTriggerType Outgoing Webhookのトリガー種別
+type OutgoingWebhookTriggerType string
+
+const (
+	// OutgoingWebhookTriggerKeyword キーワードを含むメッセージにマッチ
+	OutgoingWebhookTriggerKeyword OutgoingWebhookTriggerType = "keyword"
+	// OutgoingWebhookTriggerRegex 正規表現にマッチするメッセージにマッチ
+	OutgoingWebhookTriggerRegex OutgoingWebhookTriggerType = "regex"
+)
+
+// String string型にキャストします
+func (t OutgoingWebhookTriggerType) String() string {
+	return string(t)
+}
+
+// Valid 有効なトリガー種別かどうか
+func (t OutgoingWebhookTriggerType) Valid() bool {
+	switch t {
+	case OutgoingWebhookTriggerKeyword, OutgoingWebhookTriggerRegex:
+		return true
+	default:
+		return false
+	}
+}
+
+// OutgoingWebhook Outgoing Webhook構造体
+//
+// 指定したチャンネルにトリガーにマッチするメッセージが投稿された際に、URLにメッセージを送信します。
+// レスポンスの本文は WebhookID のWebhookユーザーとして返信されます。
+type OutgoingWebhook struct {
+	ID          uuid.UUID                  `gorm:"type:char(36);not null;primary_key"`
+	WebhookID   uuid.UUID                  `gorm:"type:char(36);not null;index"`
+	ChannelID   uuid.UUID                  `gorm:"type:char(36);not null;index"`
+	TriggerType OutgoingWebhookTriggerType `gorm:"type:varchar(10);not null"`
+	Trigger     string                     `gorm:"type:varchar(200);not null"`
+	URL         string                     `gorm:"type:text;not null"`
+	Secret      string                     `gorm:"type:varchar(64);not null"`
+	CreatorID   uuid.UUID                  `gorm:"type:char(36);not null"`
+	CreatedAt   time.Time                  `gorm:"precision:6"`
+	UpdatedAt   time.Time                  `gorm:"precision:6"`
+}
+
+// TableName OutgoingWebhook構造体のテーブル名
+func (*OutgoingWebhook) TableName() string {
+	return "outgoing_webhooks"
+}
+
+// Match メッセージ本文がトリガーにマッチするかどうか
+//
+// 正規表現トリガーの場合、reにはTriggerをコンパイルした正規表現を指定します。reがnilの場合はマッチしません。
+// マッチした場合は、マッチした部分文字列とtrueを返します。
+func (w *OutgoingWebhook) Match(re *regexp.Regexp, text string) (string, bool) {
+	switch w.TriggerType {
+	case OutgoingWebhookTriggerKeyword:
+		if len(w.Trigger) > 0 && strings.Contains(text, w.Trigger) {
+			return w.Trigger, true
+		}
+	case OutgoingWebhookTriggerRegex:
+		if re == nil {
+			return "", false
+		}
+		if loc := re.FindStringIndex(text); loc != nil {
+			return text[loc[0]:loc[1]], true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,52 @@
+package model
+
+import (
+	"github.com/stretchr/testify/assert"
+	"regexp"
+	"testing"
+)
+
+func TestOutgoingWebhook_TableName(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "outgoing_webhooks", (&OutgoingWebhook{}).TableName())
+}
+
+func TestOutgoingWebhookTriggerType_Valid(t *testing.T) {
+	t.Parallel()
+	assert.True(t, OutgoingWebhookTriggerKeyword.Valid())
+	assert.True(t, OutgoingWebhookTriggerRegex.Valid())
+	assert.False(t, OutgoingWebhookTriggerType("").Valid())
+	assert.False(t, OutgoingWebhookTriggerType("prefix").Valid())
+}
+
+func TestOutgoingWebhook_Match(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name        string
+		triggerType OutgoingWebhookTriggerType
+		trigger     string
+		text        string
+		matched     string
+		ok          bool
+	}{
+		{"keyword match", OutgoingWebhookTriggerKeyword, "!deploy", "please !deploy prod", "!deploy", true},
+		{"keyword no match", OutgoingWebhookTriggerKeyword, "!deploy", "deploy prod", "", false},
+		{"empty keyword", OutgoingWebhookTriggerKeyword, "", "anything", "", false},
+		{"regex match", OutgoingWebhookTriggerRegex, `#\d+`, "see issue #123 please", "#123", true},
+		{"regex no match", OutgoingWebhookTriggerRegex, `^!ping$`, "!ping pong", "", false},
+		{"invalid regex", OutgoingWebhookTriggerRegex, `(`, "(", "", false},
+		{"unknown type", OutgoingWebhookTriggerType("prefix"), "a", "a", "", false},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			w := &OutgoingWebhook{TriggerType: c.triggerType, Trigger: c.trigger}
+			re, _ := regexp.Compile(c.trigger)
+			matched, ok := w.Match(re, c.text)
+			assert.Equal(t, c.ok, ok)
+			assert.Equal(t, c.matched, matched)
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package model
+
+import (
+	"github.com/gofrs/uuid"
+	"time"
+)
+
+// RateLimitOverride BOT・Webhookのレート制限の上書き設定構造体
+type RateLimitOverride struct {
+	UserID    uuid.UUID `gorm:"type:char(36);not null;primary_key"`
+	Action    string    `gorm:"type:varchar(30);not null;primary_key"`
+	Rate      float64   `gorm:"type:double;not null"`
+	Burst     int       `gorm:"type:int;not null"`
+	CreatedAt time.Time `gorm:"precision:6"`
+	UpdatedAt time.Time `gorm:"precision:6"`
+}
+
+// TableName RateLimitOverride構造体のテーブル名
+func (*RateLimitOverride) TableName() string {
+	return "rate_limit_overrides"
+}
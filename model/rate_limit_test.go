@@ -0,0 +1,11 @@
+package model
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRateLimitOverride_TableName(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "rate_limit_overrides", (&RateLimitOverride{}).TableName())
+}
@@ -1,5 +1,10 @@
 package model
 
+import (
+	"github.com/gofrs/uuid"
+	"time"
+)
+
 // UserRole ユーザーロール構造体
 type UserRole struct {
 	Name         string            `gorm:"type:varchar(30);not null;primary_key"`
@@ -35,3 +40,19 @@ type RolePermission struct {
 func (*RolePermission) TableName() string {
 	return "user_role_permissions"
 }
+
+// ChannelRole チャンネルロール構造体
+//
+// チャンネルロールは指定したチャンネルとその子孫チャンネルで有効です。
+type ChannelRole struct {
+	ChannelID uuid.UUID `gorm:"type:char(36);not null;primary_key"`
+	UserID    uuid.UUID `gorm:"type:char(36);not null;primary_key;index"`
+	Role      string    `gorm:"type:varchar(30);not null"`
+	CreatedAt time.Time `gorm:"precision:6"`
+	UpdatedAt time.Time `gorm:"precision:6"`
+}
+
+// TableName ChannelRole構造体のテーブル名
+func (*ChannelRole) TableName() string {
+	return "channel_roles"
+}
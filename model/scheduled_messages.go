@@ -0,0 +1,22 @@
+package model
+
+import (
+	"github.com/gofrs/uuid"
+	"time"
+)
+
+// ScheduledMessage 予約投稿メッセージの構造体
+type ScheduledMessage struct {
+	ID          uuid.UUID `gorm:"type:char(36);not null;primary_key"`
+	UserID      uuid.UUID `gorm:"type:char(36);not null;index"`
+	ChannelID   uuid.UUID `gorm:"type:char(36);not null;index"`
+	Text        string    `sql:"type:TEXT COLLATE utf8mb4_bin NOT NULL"`
+	ScheduledAt time.Time `gorm:"precision:6;index"`
+	CreatedAt   time.Time `gorm:"precision:6"`
+	UpdatedAt   time.Time `gorm:"precision:6"`
+}
+
+// TableName ScheduledMessage構造体のテーブル名
+func (*ScheduledMessage) TableName() string {
+	return "scheduled_messages"
+}
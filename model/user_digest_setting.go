@@ -0,0 +1,78 @@
+package model
+
+import (
+	"github.com/gofrs/uuid"
+	"time"
+)
+
+// DigestFrequency ダイジェストメールの配信頻度
+type DigestFrequency string
+
+const (
+	// DigestFrequencyNone 配信しない
+	DigestFrequencyNone DigestFrequency = "none"
+	// DigestFrequencyDaily 1日1回配信する
+	DigestFrequencyDaily DigestFrequency = "daily"
+	// DigestFrequencyWeekly 1週間に1回配信する
+	DigestFrequencyWeekly DigestFrequency = "weekly"
+)
+
+// String string型にキャストします
+func (f DigestFrequency) String() string {
+	return string(f)
+}
+
+// Valid 有効な配信頻度かどうか
+func (f DigestFrequency) Valid() bool {
+	switch f {
+	case DigestFrequencyNone, DigestFrequencyDaily, DigestFrequencyWeekly:
+		return true
+	default:
+		return false
+	}
+}
+
+// Interval 配信間隔を返します
+//
+// 配信しない場合は0を返します。
+func (f DigestFrequency) Interval() time.Duration {
+	switch f {
+	case DigestFrequencyDaily:
+		return 24 * time.Hour
+	case DigestFrequencyWeekly:
+		return 7 * 24 * time.Hour
+	default:
+		return 0
+	}
+}
+
+// UserDigestSetting ユーザーのダイジェストメール設定構造体
+//
+// 未読の通知対象メッセージの要約を Frequency の間隔で Email に送信します。
+// UnsubscribeToken はメール内の配信停止リンクに使用され、ログインせずに配信を停止できます。
+// ダイジェストは EmailVerifiedAt が設定された(確認済みの)アドレスにのみ送信されます。
+// VerificationToken はアドレス確認リンクに使用され、確認後は空になります。
+// 送信に失敗した場合は FailedAttempts に応じて RetryAt まで再試行を待ちます。
+type UserDigestSetting struct {
+	UserID            uuid.UUID       `gorm:"type:char(36);not null;primary_key"`
+	Email             string          `gorm:"type:varchar(254);not null"`
+	Frequency         DigestFrequency `gorm:"type:varchar(10);not null;index"`
+	UnsubscribeToken  string          `gorm:"type:varchar(50);not null;unique"`
+	EmailVerifiedAt   *time.Time      `gorm:"precision:6"`
+	VerificationToken string          `gorm:"type:varchar(50);not null;index"`
+	FailedAttempts    int             `gorm:"not null;default:0"`
+	RetryAt           *time.Time      `gorm:"precision:6"`
+	LastSentAt        *time.Time      `gorm:"precision:6"`
+	CreatedAt         time.Time       `gorm:"precision:6"`
+	UpdatedAt         time.Time       `gorm:"precision:6"`
+}
+
+// IsEmailVerified メールアドレスが確認済みかどうか
+func (s *UserDigestSetting) IsEmailVerified() bool {
+	return s.EmailVerifiedAt != nil
+}
+
+// TableName UserDigestSetting構造体のテーブル名
+func (*UserDigestSetting) TableName() string {
+	return "user_digest_settings"
+}
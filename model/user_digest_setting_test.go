@@ -0,0 +1,28 @@
+package model
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestUserDigestSetting_TableName(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "user_digest_settings", (&UserDigestSetting{}).TableName())
+}
+
+func TestDigestFrequency_Valid(t *testing.T) {
+	t.Parallel()
+	assert.True(t, DigestFrequencyNone.Valid())
+	assert.True(t, DigestFrequencyDaily.Valid())
+	assert.True(t, DigestFrequencyWeekly.Valid())
+	assert.False(t, DigestFrequency("").Valid())
+	assert.False(t, DigestFrequency("hourly").Valid())
+}
+
+func TestDigestFrequency_Interval(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, time.Duration(0), DigestFrequencyNone.Interval())
+	assert.Equal(t, 24*time.Hour, DigestFrequencyDaily.Interval())
+	assert.Equal(t, 7*24*time.Hour, DigestFrequencyWeekly.Interval())
+}
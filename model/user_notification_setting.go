@@ -0,0 +1,128 @@
+package model
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"github.com/gofrs/uuid"
+	"strings"
+	"time"
+)
+
+// NotificationKeywords キーワード通知のキーワードの配列
+type NotificationKeywords []string
+
+// Value database/sql/driver.Valuer 実装
+func (arr NotificationKeywords) Value() (driver.Value, error) {
+	return strings.Join(arr, "\n"), nil
+}
+
+// Scan database/sql.Scanner 実装
+func (arr *NotificationKeywords) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case nil:
+		*arr = NotificationKeywords{}
+	case string:
+		*arr = notificationKeywordsFromString(s)
+	case []byte:
+		*arr = notificationKeywordsFromString(string(s))
+	default:
+		return errors.New("failed to scan NotificationKeywords")
+	}
+	return nil
+}
+
+func notificationKeywordsFromString(s string) NotificationKeywords {
+	arr := NotificationKeywords{}
+	for _, v := range strings.Split(s, "\n") {
+		if len(v) > 0 {
+			arr = append(arr, v)
+		}
+	}
+	return arr
+}
+
+// Match 文字列がいずれかのキーワードを含むかどうか
+//
+// 大文字小文字は区別しません。
+func (arr NotificationKeywords) Match(s string) bool {
+	s = strings.ToLower(s)
+	for _, v := range arr {
+		if len(v) > 0 && strings.Contains(s, strings.ToLower(v)) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseClock "HH:MM"形式の時刻を0時からの経過分に変換します
+func ParseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil || len(s) != 5 {
+		return 0, fmt.Errorf("invalid clock: %s", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// UserNotificationSetting ユーザーの通知設定構造体
+//
+// おやすみモード(QuietHours)中、及びSnoozeUntilまでの間はプッシュ通知を送信しません。未読は通常通り追加されます。
+// QuietHoursStart, QuietHoursEnd は Timezone における"HH:MM"形式の時刻で、日付を跨ぐ指定も可能です。
+type UserNotificationSetting struct {
+	UserID            uuid.UUID            `gorm:"type:char(36);not null;primary_key"`
+	QuietHoursEnabled bool                 `gorm:"type:boolean;not null;default:false"`
+	QuietHoursStart   string               `gorm:"type:char(5);not null;default:'00:00'"`
+	QuietHoursEnd     string               `gorm:"type:char(5);not null;default:'00:00'"`
+	Timezone          string               `gorm:"type:varchar(64);not null;default:'UTC'"`
+	SnoozeUntil       *time.Time           `gorm:"precision:6"`
+	Keywords          NotificationKeywords `gorm:"type:text;not null"`
+	MutedGroupIDs     UUIDs                `gorm:"type:text;not null"`
+	CreatedAt         time.Time            `gorm:"precision:6"`
+	UpdatedAt         time.Time            `gorm:"precision:6"`
+}
+
+// TableName UserNotificationSetting構造体のテーブル名
+func (*UserNotificationSetting) TableName() string {
+	return "user_notification_settings"
+}
+
+// IsDoNotDisturb 指定した日時にプッシュ通知を抑制するかどうか
+func (s *UserNotificationSetting) IsDoNotDisturb(t time.Time) bool {
+	if s.SnoozeUntil != nil && t.Before(*s.SnoozeUntil) {
+		return true
+	}
+	if !s.QuietHoursEnabled {
+		return false
+	}
+
+	start, err := ParseClock(s.QuietHoursStart)
+	if err != nil {
+		return false
+	}
+	end, err := ParseClock(s.QuietHoursEnd)
+	if err != nil {
+		return false
+	}
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	lt := t.In(loc)
+	now := lt.Hour()*60 + lt.Minute()
+
+	if start <= end {
+		return start <= now && now < end
+	}
+	// 日付を跨ぐ場合
+	return start <= now || now < end
+}
+
+// IsGroupMentionMuted 指定したグループへのメンションをミュートしているかどうか
+func (s *UserNotificationSetting) IsGroupMentionMuted(groupID uuid.UUID) bool {
+	for _, id := range s.MutedGroupIDs {
+		if id == groupID {
+			return true
+		}
+	}
+	return false
+}
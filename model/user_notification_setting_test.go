@@ -0,0 +1,124 @@
+package model
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestUserNotificationSetting_TableName(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "user_notification_settings", (&UserNotificationSetting{}).TableName())
+}
+
+func TestNotificationKeywords_Value(t *testing.T) {
+	t.Parallel()
+	v, err := NotificationKeywords{"traQ", "部内"}.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "traQ\n部内", v)
+}
+
+func TestNotificationKeywords_Scan(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil", func(t *testing.T) {
+		t.Parallel()
+		var arr NotificationKeywords
+		assert.NoError(t, arr.Scan(nil))
+		assert.Empty(t, arr)
+	})
+
+	t.Run("string", func(t *testing.T) {
+		t.Parallel()
+		var arr NotificationKeywords
+		assert.NoError(t, arr.Scan("traQ\n部内"))
+		assert.EqualValues(t, []string{"traQ", "部内"}, arr)
+	})
+
+	t.Run("[]byte", func(t *testing.T) {
+		t.Parallel()
+		var arr NotificationKeywords
+		assert.NoError(t, arr.Scan([]byte("")))
+		assert.Empty(t, arr)
+	})
+
+	t.Run("other", func(t *testing.T) {
+		t.Parallel()
+		var arr NotificationKeywords
+		assert.Error(t, arr.Scan(1))
+	})
+}
+
+func TestNotificationKeywords_Match(t *testing.T) {
+	t.Parallel()
+	arr := NotificationKeywords{"traQ", "部内"}
+	assert.True(t, arr.Match("TRAQの話"))
+	assert.True(t, arr.Match("部内システム"))
+	assert.False(t, arr.Match("関係ない話"))
+	assert.False(t, NotificationKeywords{}.Match("traQ"))
+}
+
+func TestParseClock(t *testing.T) {
+	t.Parallel()
+	cases := map[string]int{"00:00": 0, "07:30": 450, "23:59": 1439}
+	for s, expected := range cases {
+		m, err := ParseClock(s)
+		if assert.NoError(t, err, s) {
+			assert.Equal(t, expected, m, s)
+		}
+	}
+	for _, s := range []string{"", "7:30", "24:00", "12:60", "1230"} {
+		_, err := ParseClock(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestUserNotificationSetting_IsDoNotDisturb(t *testing.T) {
+	t.Parallel()
+	at := func(clock string) time.Time {
+		t, _ := time.Parse(time.RFC3339, "2020-01-01T"+clock+":00Z")
+		return t
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Parallel()
+		s := &UserNotificationSetting{QuietHoursStart: "00:00", QuietHoursEnd: "23:59", Timezone: "UTC"}
+		assert.False(t, s.IsDoNotDisturb(at("12:00")))
+	})
+
+	t.Run("snooze", func(t *testing.T) {
+		t.Parallel()
+		until := at("12:00")
+		s := &UserNotificationSetting{SnoozeUntil: &until}
+		assert.True(t, s.IsDoNotDisturb(at("11:59")))
+		assert.False(t, s.IsDoNotDisturb(at("12:00")))
+	})
+
+	t.Run("quiet hours", func(t *testing.T) {
+		t.Parallel()
+		s := &UserNotificationSetting{QuietHoursEnabled: true, QuietHoursStart: "09:00", QuietHoursEnd: "17:00", Timezone: "UTC"}
+		assert.False(t, s.IsDoNotDisturb(at("08:59")))
+		assert.True(t, s.IsDoNotDisturb(at("09:00")))
+		assert.True(t, s.IsDoNotDisturb(at("16:59")))
+		assert.False(t, s.IsDoNotDisturb(at("17:00")))
+	})
+
+	t.Run("quiet hours over midnight with timezone", func(t *testing.T) {
+		t.Parallel()
+		// Asia/Tokyo (UTC+9) の 23:00-07:00 は UTC の 14:00-22:00
+		s := &UserNotificationSetting{QuietHoursEnabled: true, QuietHoursStart: "23:00", QuietHoursEnd: "07:00", Timezone: "Asia/Tokyo"}
+		assert.False(t, s.IsDoNotDisturb(at("13:59")))
+		assert.True(t, s.IsDoNotDisturb(at("14:00")))
+		assert.True(t, s.IsDoNotDisturb(at("21:59")))
+		assert.False(t, s.IsDoNotDisturb(at("22:00")))
+	})
+}
+
+func TestUserNotificationSetting_IsGroupMentionMuted(t *testing.T) {
+	t.Parallel()
+	gid := uuid.Must(uuid.NewV4())
+	s := &UserNotificationSetting{MutedGroupIDs: UUIDs{gid}}
+	assert.True(t, s.IsGroupMentionMuted(gid))
+	assert.False(t, s.IsGroupMentionMuted(uuid.Must(uuid.NewV4())))
+}
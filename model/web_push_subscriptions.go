@@ -0,0 +1,22 @@
+package model
+
+import (
+	"github.com/gofrs/uuid"
+	"time"
+)
+
+// WebPushSubscription Web Push購読の構造体
+type WebPushSubscription struct {
+	ID        uuid.UUID `gorm:"type:char(36);not null;primary_key"`
+	UserID    uuid.UUID `gorm:"type:char(36);not null;index"`
+	Endpoint  string    `gorm:"type:text;not null"`
+	P256dh    string    `gorm:"type:varchar(100);not null"`
+	Auth      string    `gorm:"type:varchar(50);not null"`
+	CreatedAt time.Time `gorm:"precision:6"`
+	UpdatedAt time.Time `gorm:"precision:6"`
+}
+
+// TableName WebPushSubscription構造体のテーブル名
+func (*WebPushSubscription) TableName() string {
+	return "web_push_subscriptions"
+}
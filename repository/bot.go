@@ -5,16 +5,19 @@ import (
 	"github.com/gofrs/uuid"
 	"github.com/traPtitech/traQ/model"
 	"github.com/traPtitech/traQ/utils/optional"
+	"time"
 )
 
 // UpdateBotArgs Bot情報更新引数
 type UpdateBotArgs struct {
-	DisplayName     optional.String
-	Description     optional.String
-	WebhookURL      optional.String
-	Privileged      optional.Bool
-	CreatorID       optional.UUID
-	SubscribeEvents model.BotEventTypes
+	DisplayName             optional.String
+	Description             optional.String
+	WebhookURL              optional.String
+	Mode                    model.BotMode
+	Privileged              optional.Bool
+	CreatorID               optional.UUID
+	SubscribeEvents         model.BotEventTypes
+	LegacyVerificationToken optional.Bool
 }
 
 // BotsQuery Bot情報取得用クエリ
@@ -84,8 +87,9 @@ type BotRepository interface {
 	// 成功した場合、Botとnilを返します。
 	// 引数に問題がある場合、ArgumentErrorを返します。
 	// nameが既に使われている場合、ErrAlreadyExistsを返します。
+	// modeがBotModeWebSocketの場合、webhookURLは空でも構いません。
 	// DBによるエラーを返すことがあります。
-	CreateBot(name, displayName, description string, iconFileID, creatorID uuid.UUID, webhookURL string) (*model.Bot, error)
+	CreateBot(name, displayName, description string, iconFileID, creatorID uuid.UUID, mode model.BotMode, webhookURL string) (*model.Bot, error)
 	// UpdateBot 指定したBotの情報を更新します
 	//
 	// 成功した場合、nilを返します。
@@ -131,6 +135,14 @@ type BotRepository interface {
 	// 引数にuuid.Nilを指定した場合、ErrNilIDを返します。
 	// DBによるエラーを返すことがあります。
 	ReissueBotTokens(id uuid.UUID) (*model.Bot, error)
+	// RotateBotSigningSecret 指定したBotのイベントペイロード署名用シークレットを再発行します
+	//
+	// 以前のシークレットはgracePeriodの間、引き続き署名に使用されます。
+	// 成功した場合、Botとnilを返します。
+	// 存在しないBotを指定した場合、ErrNotFoundを返します。
+	// 引数にuuid.Nilを指定した場合、ErrNilIDを返します。
+	// DBによるエラーを返すことがあります。
+	RotateBotSigningSecret(id uuid.UUID, gracePeriod time.Duration) (*model.Bot, error)
 	// DeleteBot 指定したBotを削除します
 	//
 	// 成功した場合、nilを返します。
@@ -167,4 +179,71 @@ type BotRepository interface {
 	// 存在しないBotを指定した場合、空配列とnilを返します。
 	// DBによるエラーを返すことがあります。
 	GetBotEventLogs(botID uuid.UUID, limit, offset int) ([]*model.BotEventLog, error)
+	// EnqueueBotEvents Botイベントを送信キューに追加します
+	//
+	// 成功した場合、nilを返します。
+	// DBによるエラーを返すことがあります。
+	EnqueueBotEvents(items []*model.BotEventQueueItem) error
+	// GetBotIDsWithQueuedEvents 送信キューに送信可能なイベントがある有効なBotのIDを取得します
+	//
+	// 成功した場合、BotのUUIDの配列とnilを返します。
+	// DBによるエラーを返すことがあります。
+	GetBotIDsWithQueuedEvents() ([]uuid.UUID, error)
+	// LeaseBotEventQueueHead 指定したBotの送信キューの先頭のイベントをuntilまでリースして取得します
+	//
+	// リース中のイベントは、期限が切れるか送信試行回数が更新されるまで他から取得できません。
+	// 成功した場合、イベントとnilを返します。
+	// 送信キューが空の場合、先頭のイベントが再送待ちの場合、または他にリースされている場合、ErrNotFoundを返します。
+	// DBによるエラーを返すことがあります。
+	LeaseBotEventQueueHead(botID uuid.UUID, until time.Time) (*model.BotEventQueueItem, error)
+	// DeleteBotEventQueueItem 送信キューからイベントを削除します
+	//
+	// 成功した場合、nilを返します。
+	// 引数にuuid.Nilを指定した場合、ErrNilIDを返します。
+	// DBによるエラーを返すことがあります。
+	DeleteBotEventQueueItem(id uuid.UUID) error
+	// UpdateBotEventQueueItemAttempts 送信キューのイベントの送信試行回数と次回送信日時を更新します
+	//
+	// イベントのリースは解除されます。deadがtrueの場合、イベントは以降再送されません。
+	// 成功した場合、nilを返します。
+	// 引数にuuid.Nilを指定した場合、ErrNilIDを返します。
+	// DBによるエラーを返すことがあります。
+	UpdateBotEventQueueItemAttempts(id uuid.UUID, attempts int, nextAttemptAt time.Time, dead bool) error
+	// GetDeadBotEvents 指定したBotの再送を諦めたイベントを取得します
+	//
+	// 成功した場合、イベントの配列とnilを返します。負のoffset, limitは無視されます。
+	// 存在しないBotを指定した場合、空配列とnilを返します。
+	// DBによるエラーを返すことがあります。
+	GetDeadBotEvents(botID uuid.UUID, limit, offset int) ([]*model.BotEventQueueItem, error)
+	// ReplayDeadBotEvents 指定したBotの再送を諦めたイベントを送信キューに戻します
+	//
+	// idsが空の場合は全てのイベントを戻します。
+	// 成功した場合、戻したイベントの数とnilを返します。
+	// 引数にuuid.Nilを指定した場合、ErrNilIDを返します。
+	// DBによるエラーを返すことがあります。
+	ReplayDeadBotEvents(botID uuid.UUID, ids []uuid.UUID) (int, error)
+	// SetBotCommands 指定したBotのスラッシュコマンドを置き換えます
+	//
+	// 既存のコマンドは全て削除され、commandsで置き換えられます。
+	// 成功した場合、登録したコマンドの配列とnilを返します。
+	// 他のBotが使用可能なチャンネルの重複する同名のコマンドを登録している場合、ErrAlreadyExistsを返します。
+	// 引数にuuid.Nilを指定した場合、ErrNilIDを返します。
+	// DBによるエラーを返すことがあります。
+	SetBotCommands(botID uuid.UUID, commands []*model.BotCommand) ([]*model.BotCommand, error)
+	// GetBotCommands 指定したBotのスラッシュコマンドを取得します
+	//
+	// 成功した場合、コマンドの配列とnilを返します。
+	// 存在しないBotを指定した場合、空配列とnilを返します。
+	// DBによるエラーを返すことがあります。
+	GetBotCommands(botID uuid.UUID) ([]*model.BotCommand, error)
+	// GetAllBotCommands 有効なBotの全てのスラッシュコマンドを取得します
+	//
+	// 成功した場合、コマンドの配列とnilを返します。
+	// DBによるエラーを返すことがあります。
+	GetAllBotCommands() ([]*model.BotCommand, error)
+	// GetBotCommandsByName 有効なBotの指定した名前のスラッシュコマンドを取得します
+	//
+	// 成功した場合、コマンドの配列とnilを返します。
+	// DBによるエラーを返すことがあります。
+	GetBotCommandsByName(name string) ([]*model.BotCommand, error)
 }
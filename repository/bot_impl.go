@@ -19,15 +19,20 @@ import (
 )
 
 // CreateBot implements BotRepository interface.
-func (repo *GormRepository) CreateBot(name, displayName, description string, iconFileID, creatorID uuid.UUID, webhookURL string) (*model.Bot, error) {
+func (repo *GormRepository) CreateBot(name, displayName, description string, iconFileID, creatorID uuid.UUID, mode model.BotMode, webhookURL string) (*model.Bot, error) {
 	if err := vd.Validate(name, validator.BotUserNameRuleRequired...); err != nil {
 		return nil, ArgError("name", "invalid name")
 	}
 	if len(displayName) == 0 || utf8.RuneCountInString(displayName) > 32 {
 		return nil, ArgError("displayName", "DisplayName must be non-empty and shorter than 33 characters")
 	}
-	if err := vd.Validate(webhookURL, vd.Required, is.URL, validator.NotInternalURL); err != nil || !strings.HasPrefix(webhookURL, "http") {
-		return nil, ArgError("webhookURL", "invalid webhookURL")
+	if !mode.Valid() {
+		return nil, ArgError("mode", "invalid mode")
+	}
+	if mode == model.BotModeHTTP || len(webhookURL) > 0 {
+		if err := vd.Validate(webhookURL, vd.Required, is.URL, validator.NotInternalURL); err != nil || !strings.HasPrefix(webhookURL, "http") {
+			return nil, ArgError("webhookURL", "invalid webhookURL")
+		}
 	}
 	if creatorID == uuid.Nil {
 		return nil, ArgError("creatorID", "CreatorID is required")
@@ -56,8 +61,10 @@ func (repo *GormRepository) CreateBot(name, displayName, description string, ico
 		BotUserID:         uid,
 		Description:       description,
 		VerificationToken: random.SecureAlphaNumeric(30),
+		SigningSecret:     random.SecureAlphaNumeric(64),
 		PostURL:           webhookURL,
 		AccessTokenID:     tid,
+		Mode:              mode,
 		SubscribeEvents:   model.BotEventTypes{},
 		Privileged:        false,
 		State:             model.BotInactive,
@@ -134,6 +141,17 @@ func (repo *GormRepository) UpdateBot(id uuid.UUID, args UpdateBotArgs) error {
 			changes["post_url"] = w
 			changes["state"] = model.BotPaused
 		}
+		if len(args.Mode) > 0 && args.Mode != b.Mode {
+			if !args.Mode.Valid() {
+				return ArgError("args.Mode", "invalid mode")
+			}
+			// HTTPモードにはエンドポイントが必須
+			if args.Mode == model.BotModeHTTP && !args.WebhookURL.Valid && len(b.PostURL) == 0 {
+				return ArgError("args.Mode", "webhookURL is required for HTTP mode")
+			}
+			changes["mode"] = args.Mode
+			changes["state"] = model.BotPaused
+		}
 		if args.CreatorID.Valid {
 			// 作成者検証
 			user, err := getUser(tx, false, "id = ?", args.CreatorID.UUID)
@@ -152,6 +170,9 @@ func (repo *GormRepository) UpdateBot(id uuid.UUID, args UpdateBotArgs) error {
 		if args.SubscribeEvents != nil {
 			changes["subscribe_events"] = args.SubscribeEvents
 		}
+		if args.LegacyVerificationToken.Valid {
+			changes["legacy_verification_token"] = args.LegacyVerificationToken.Bool
+		}
 
 		if len(changes) > 0 {
 			if err := tx.Model(&b).Updates(changes).Error; err != nil {
@@ -361,6 +382,29 @@ func (repo *GormRepository) ReissueBotTokens(id uuid.UUID) (*model.Bot, error) {
 	return &bot, nil
 }
 
+// RotateBotSigningSecret implements BotRepository interface.
+func (repo *GormRepository) RotateBotSigningSecret(id uuid.UUID, gracePeriod time.Duration) (*model.Bot, error) {
+	if id == uuid.Nil {
+		return nil, ErrNilID
+	}
+	var bot model.Bot
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&bot, &model.Bot{ID: id}).Error; err != nil {
+			return convertError(err)
+		}
+
+		expiresAt := time.Now().Add(gracePeriod)
+		bot.PreviousSigningSecret = bot.SigningSecret
+		bot.PreviousSigningSecretExpiresAt = &expiresAt
+		bot.SigningSecret = random.SecureAlphaNumeric(64)
+		return tx.Save(&bot).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &bot, nil
+}
+
 // DeleteBot implements BotRepository interface.
 func (repo *GormRepository) DeleteBot(id uuid.UUID) error {
 	if id == uuid.Nil {
@@ -371,6 +415,12 @@ func (repo *GormRepository) DeleteBot(id uuid.UUID) error {
 		if err := tx.First(&b, &model.Bot{ID: id}).Error; err != nil {
 			return convertError(err)
 		}
+		if err := tx.Where("bot_id = ?", id).Delete(&model.BotEventQueueItem{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("bot_id = ?", id).Delete(&model.BotCommand{}).Error; err != nil {
+			return err
+		}
 
 		errs := tx.Model(&model.User{ID: b.BotUserID}).Update("status", model.UserAccountStatusDeactivated).New().
 			Delete(&model.BotJoinChannel{BotID: id}).
@@ -464,3 +514,193 @@ func (repo *GormRepository) GetBotEventLogs(botID uuid.UUID, limit, offset int)
 		Find(&logs).
 		Error
 }
+
+// EnqueueBotEvents implements BotRepository interface.
+func (repo *GormRepository) EnqueueBotEvents(items []*model.BotEventQueueItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		for _, item := range items {
+			if err := tx.Create(item).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetBotIDsWithQueuedEvents implements BotRepository interface.
+func (repo *GormRepository) GetBotIDsWithQueuedEvents() ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, 0)
+	return ids, repo.db.
+		Table("bot_event_queue").
+		Joins("INNER JOIN bots ON bots.id = bot_event_queue.bot_id AND bots.state = ? AND bots.deleted_at IS NULL", model.BotActive).
+		Where("bot_event_queue.dead = FALSE").
+		Pluck("DISTINCT bot_event_queue.bot_id", &ids).
+		Error
+}
+
+// LeaseBotEventQueueHead implements BotRepository interface.
+func (repo *GormRepository) LeaseBotEventQueueHead(botID uuid.UUID, until time.Time) (*model.BotEventQueueItem, error) {
+	if botID == uuid.Nil {
+		return nil, ErrNotFound
+	}
+	var item model.BotEventQueueItem
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.
+			Set("gorm:query_option", "FOR UPDATE").
+			Where("bot_id = ? AND dead = FALSE", botID).
+			Order("created_at, id").
+			Take(&item).
+			Error; err != nil {
+			return convertError(err)
+		}
+
+		now := time.Now()
+		if item.NextAttemptAt.After(now) || (item.LeasedUntil != nil && item.LeasedUntil.After(now)) {
+			// 再送待ちか、他で送信中
+			return ErrNotFound
+		}
+		item.LeasedUntil = &until
+		return tx.Model(&item).UpdateColumn("leased_until", until).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// DeleteBotEventQueueItem implements BotRepository interface.
+func (repo *GormRepository) DeleteBotEventQueueItem(id uuid.UUID) error {
+	if id == uuid.Nil {
+		return ErrNilID
+	}
+	return repo.db.Delete(&model.BotEventQueueItem{ID: id}).Error
+}
+
+// UpdateBotEventQueueItemAttempts implements BotRepository interface.
+func (repo *GormRepository) UpdateBotEventQueueItemAttempts(id uuid.UUID, attempts int, nextAttemptAt time.Time, dead bool) error {
+	if id == uuid.Nil {
+		return ErrNilID
+	}
+	return repo.db.Model(&model.BotEventQueueItem{ID: id}).Updates(map[string]interface{}{
+		"attempts":        attempts,
+		"next_attempt_at": nextAttemptAt,
+		"dead":            dead,
+		"leased_until":    nil,
+	}).Error
+}
+
+// GetDeadBotEvents implements BotRepository interface.
+func (repo *GormRepository) GetDeadBotEvents(botID uuid.UUID, limit, offset int) ([]*model.BotEventQueueItem, error) {
+	items := make([]*model.BotEventQueueItem, 0)
+	if botID == uuid.Nil {
+		return items, nil
+	}
+	return items, repo.db.
+		Where("bot_id = ? AND dead = TRUE", botID).
+		Order("created_at DESC").
+		Scopes(gormutil.LimitAndOffset(limit, offset)).
+		Find(&items).
+		Error
+}
+
+// ReplayDeadBotEvents implements BotRepository interface.
+func (repo *GormRepository) ReplayDeadBotEvents(botID uuid.UUID, ids []uuid.UUID) (int, error) {
+	if botID == uuid.Nil {
+		return 0, ErrNilID
+	}
+	tx := repo.db.Model(&model.BotEventQueueItem{}).Where("bot_id = ? AND dead = TRUE", botID)
+	if len(ids) > 0 {
+		tx = tx.Where("id IN (?)", ids)
+	}
+	// 作成日時順に再送されるため、後続のイベントより先に送信される
+	result := tx.Updates(map[string]interface{}{
+		"attempts":        0,
+		"next_attempt_at": time.Now(),
+		"dead":            false,
+		"leased_until":    nil,
+	})
+	return int(result.RowsAffected), result.Error
+}
+
+// SetBotCommands implements BotRepository interface.
+func (repo *GormRepository) SetBotCommands(botID uuid.UUID, commands []*model.BotCommand) ([]*model.BotCommand, error) {
+	if botID == uuid.Nil {
+		return nil, ErrNilID
+	}
+	names := make([]string, len(commands))
+	for i, c := range commands {
+		names[i] = c.Name
+	}
+
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		if len(names) > 0 {
+			// 同じチャンネルで使用可能な同名のコマンドは登録できない
+			var others []*model.BotCommand
+			if err := tx.Where("name IN (?) AND bot_id <> ?", names, botID).Find(&others).Error; err != nil {
+				return err
+			}
+			for _, o := range others {
+				for _, c := range commands {
+					if c.Name == o.Name && c.Overlaps(o) {
+						return ErrAlreadyExists
+					}
+				}
+			}
+		}
+
+		if err := tx.Where("bot_id = ?", botID).Delete(&model.BotCommand{}).Error; err != nil {
+			return err
+		}
+		for _, c := range commands {
+			c.ID = uuid.Must(uuid.NewV4())
+			c.BotID = botID
+			if err := tx.Create(c).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return commands, nil
+}
+
+// GetBotCommands implements BotRepository interface.
+func (repo *GormRepository) GetBotCommands(botID uuid.UUID) ([]*model.BotCommand, error) {
+	commands := make([]*model.BotCommand, 0)
+	if botID == uuid.Nil {
+		return commands, nil
+	}
+	return commands, repo.db.
+		Where("bot_id = ?", botID).
+		Order("name").
+		Find(&commands).
+		Error
+}
+
+// GetAllBotCommands implements BotRepository interface.
+func (repo *GormRepository) GetAllBotCommands() ([]*model.BotCommand, error) {
+	commands := make([]*model.BotCommand, 0)
+	return commands, repo.db.
+		Joins("INNER JOIN bots ON bots.id = bot_commands.bot_id AND bots.state = ? AND bots.deleted_at IS NULL", model.BotActive).
+		Order("bot_commands.name").
+		Find(&commands).
+		Error
+}
+
+// GetBotCommandsByName implements BotRepository interface.
+func (repo *GormRepository) GetBotCommandsByName(name string) ([]*model.BotCommand, error) {
+	commands := make([]*model.BotCommand, 0)
+	if len(name) == 0 {
+		return commands, nil
+	}
+	return commands, repo.db.
+		Joins("INNER JOIN bots ON bots.id = bot_commands.bot_id AND bots.state = ? AND bots.deleted_at IS NULL", model.BotActive).
+		Where("bot_commands.name = ?", name).
+		Find(&commands).
+		Error
+}
@@ -71,11 +71,29 @@ type ChannelRepository interface {
 	// CreateChannel チャンネルを作成します
 	//
 	// dmがtrueの場合、privateMembersに1人または2人のユーザーが入っている必要があります。
+	// 同じ親チャンネルに同名のチャンネルが存在する場合、ErrAlreadyExistsを返します。
 	CreateChannel(ch model.Channel, privateMembers set.UUID, dm bool) (*model.Channel, error)
 	// UpdateChannel 指定したチャンネルの情報を変更します
 	//
 	// 存在しないチャンネルを指定した場合、ErrNotFoundを返します。
+	// 変更後のチャンネル名が重複する場合、ErrAlreadyExistsを返します。
 	UpdateChannel(channelID uuid.UUID, args UpdateChannelArgs) (*model.Channel, error)
+	// MergeChannel 指定したチャンネルを別のチャンネルに統合します
+	//
+	// fromのメッセージ・購読・スター・BOT参加・Webhook投稿先・チャンネルロール・予約投稿・子チャンネルをtoに移し、fromを物理削除します。
+	// 成功した場合、nilを返します。
+	// 引数にuuid.Nilを指定した場合、ErrNilIDを返します。
+	// 存在しないチャンネルを指定した場合、ErrNotFoundを返します。
+	// DBによるエラーを返すことがあります。
+	MergeChannel(fromID, toID uuid.UUID) error
+	// DeleteChannel 指定したチャンネルを物理削除します
+	//
+	// 成功した場合、nilを返します。
+	// 引数にuuid.Nilを指定した場合、ErrNilIDを返します。
+	// 存在しないチャンネルを指定した場合、ErrNotFoundを返します。
+	// メッセージまたは子チャンネルが存在するチャンネルを指定した場合、ErrForbiddenを返します。
+	// DBによるエラーを返すことがあります。
+	DeleteChannel(channelID uuid.UUID) error
 	// GetChannel 指定したチャンネルを取得します
 	//
 	// 存在しないチャンネルを指定した場合、ErrNotFoundを返します。
@@ -88,6 +106,20 @@ type ChannelRepository interface {
 	GetDirectMessageChannelMapping(userID uuid.UUID) ([]*model.DMChannelMapping, error)
 	// GetPrivateChannelMemberIDs 指定したプライベートチャンネルのメンバーのUUIDを取得します
 	GetPrivateChannelMemberIDs(channelID uuid.UUID) ([]uuid.UUID, error)
+	// GetPrivateChannelsByUser 指定したユーザーがメンバーになっているプライベートチャンネル(DMを除く)を全て取得します
+	//
+	// 存在しないユーザーを指定した場合は空配列とnilを返します。
+	GetPrivateChannelsByUser(userID uuid.UUID) ([]*model.Channel, error)
+	// AddPrivateChannelMember 指定したプライベートチャンネルにメンバーを追加します
+	//
+	// 既にメンバーである場合、ErrAlreadyExistsを返します。
+	// 引数にuuid.Nilを指定するとErrNilIDを返します。
+	AddPrivateChannelMember(channelID, userID uuid.UUID) error
+	// RemovePrivateChannelMember 指定したプライベートチャンネルからメンバーを削除します
+	//
+	// メンバーでない場合、ErrNotFoundを返します。
+	// 引数にuuid.Nilを指定するとErrNilIDを返します。
+	RemovePrivateChannelMember(channelID, userID uuid.UUID) error
 	// ChangeChannelSubscription ユーザーのチャンネルの購読を変更します
 	//
 	// channelIDにuuid.Nilを指定した場合、ErrNilIDを返します。
@@ -34,6 +34,11 @@ func (repo *GormRepository) CreateChannel(ch model.Channel, privateMembers set.U
 		}
 	}
 
+	if ch.IsPrivateChannel() {
+		// プライベートチャンネル同士では名前の重複を許す
+		ch.NameScope = ch.ID.String()
+	}
+
 	if dm {
 		ch.ParentID = dmChannelRootUUID
 		ch.IsPublic = false
@@ -74,6 +79,9 @@ func (repo *GormRepository) CreateChannel(ch model.Channel, privateMembers set.U
 		return nil
 	})
 	if err != nil {
+		if gormutil.IsMySQLDuplicatedRecordErr(err) {
+			return nil, ErrAlreadyExists
+		}
 		return nil, err
 	}
 	repo.hub.Publish(hub.Message{
@@ -117,6 +125,9 @@ func (repo *GormRepository) UpdateChannel(channelID uuid.UUID, args UpdateChanne
 		}
 
 		if err := tx.Model(&ch).Updates(data).Error; err != nil {
+			if gormutil.IsMySQLDuplicatedRecordErr(err) {
+				return ErrAlreadyExists
+			}
 			return err
 		}
 		if err := tx.First(&ch, &model.Channel{ID: channelID}).Error; err != nil {
@@ -148,6 +159,169 @@ func (repo *GormRepository) UpdateChannel(channelID uuid.UUID, args UpdateChanne
 	return &ch, nil
 }
 
+// MergeChannel implements ChannelRepository interface.
+func (repo *GormRepository) MergeChannel(fromID, toID uuid.UUID) error {
+	if fromID == uuid.Nil || toID == uuid.Nil {
+		return ErrNilID
+	}
+	if fromID == toID {
+		return ArgError("toID", "cannot merge a channel into itself")
+	}
+
+	var from model.Channel
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		var to model.Channel
+		if err := tx.Set("gorm:query_option", "FOR UPDATE").First(&from, &model.Channel{ID: fromID}).Error; err != nil {
+			return convertError(err)
+		}
+		if err := tx.Set("gorm:query_option", "FOR UPDATE").First(&to, &model.Channel{ID: toID}).Error; err != nil {
+			return convertError(err)
+		}
+
+		// チャンネルIDを付け替えるだけのテーブル
+		moves := []struct {
+			table  string
+			column string
+		}{
+			{"messages", "channel_id"},
+			{"messages_search_index", "channel_id"},
+			{"webhook_bots", "channel_id"},
+			{"scheduled_messages", "channel_id"},
+			{"outgoing_webhooks", "channel_id"},
+			{"files", "channel_id"},
+			{"user_profiles", "home_channel"},
+			{"channels", "parent_id"},
+		}
+		for _, v := range moves {
+			if err := tx.Table(v.table).Where(v.column+" = ?", fromID).UpdateColumn(v.column, toID).Error; err != nil {
+				return err
+			}
+		}
+
+		// スラッシュコマンドの使用可能チャンネル
+		var commands []*model.BotCommand
+		if err := tx.Where("channel_ids LIKE ?", "%"+fromID.String()+"%").Find(&commands).Error; err != nil {
+			return err
+		}
+		for _, c := range commands {
+			ids := make(model.UUIDs, 0, len(c.ChannelIDs))
+			added := make(map[uuid.UUID]bool, len(c.ChannelIDs))
+			for _, id := range c.ChannelIDs {
+				if id == fromID {
+					id = toID
+				}
+				if !added[id] {
+					added[id] = true
+					ids = append(ids, id)
+				}
+			}
+			if err := tx.Model(&model.BotCommand{ID: c.ID}).UpdateColumn("channel_ids", ids).Error; err != nil {
+				return err
+			}
+		}
+
+		// 統合先に既に存在する場合は統合先を優先するテーブル
+		// 購読は強い方のレベルに揃える
+		if err := tx.Exec("UPDATE users_subscribe_channels t INNER JOIN users_subscribe_channels f ON f.user_id = t.user_id AND f.channel_id = ? SET t.mark = t.mark OR f.mark, t.notify = t.notify OR f.notify WHERE t.channel_id = ?", fromID, toID).Error; err != nil {
+			return err
+		}
+		merges := []struct {
+			table  string
+			column string
+		}{
+			{"users_subscribe_channels", "user_id"},
+			{"stars", "user_id"},
+			{"bot_join_channels", "bot_id"},
+			{"channel_roles", "user_id"},
+		}
+		for _, v := range merges {
+			if err := tx.Exec("UPDATE IGNORE "+v.table+" SET channel_id = ? WHERE channel_id = ?", toID, fromID).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec("DELETE FROM "+v.table+" WHERE channel_id = ?", fromID).Error; err != nil {
+				return err
+			}
+		}
+
+		// 最新メッセージ
+		var latest []*model.ChannelLatestMessage
+		if err := tx.Where("channel_id IN (?)", []uuid.UUID{fromID, toID}).Order("date_time DESC").Find(&latest).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(model.ChannelLatestMessage{}, "channel_id IN (?)", []uuid.UUID{fromID, toID}).Error; err != nil {
+			return err
+		}
+		if len(latest) > 0 {
+			if err := tx.Create(&model.ChannelLatestMessage{ChannelID: toID, MessageID: latest[0].MessageID, DateTime: latest[0].DateTime}).Error; err != nil {
+				return err
+			}
+		}
+
+		// 残りのチャンネル依存データ(イベント履歴など)は外部キー制約により削除される
+		return tx.Unscoped().Delete(&model.Channel{ID: fromID}).Error
+	})
+	if err != nil {
+		return err
+	}
+	repo.hub.Publish(hub.Message{
+		Name: event.ChannelMerged,
+		Fields: hub.Fields{
+			"channel_id":    fromID,
+			"to_channel_id": toID,
+		},
+	})
+	repo.hub.Publish(hub.Message{
+		Name: event.ChannelDeleted,
+		Fields: hub.Fields{
+			"channel_id": fromID,
+			"private":    !from.IsPublic,
+		},
+	})
+	return nil
+}
+
+// DeleteChannel implements ChannelRepository interface.
+func (repo *GormRepository) DeleteChannel(channelID uuid.UUID) error {
+	if channelID == uuid.Nil {
+		return ErrNilID
+	}
+
+	var ch model.Channel
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Set("gorm:query_option", "FOR UPDATE").First(&ch, &model.Channel{ID: channelID}).Error; err != nil {
+			return convertError(err)
+		}
+
+		if exists, err := gormutil.RecordExists(tx, &model.Message{ChannelID: channelID}); err != nil {
+			return err
+		} else if exists {
+			return ErrForbidden
+		}
+		if exists, err := gormutil.RecordExists(tx, &model.Channel{ParentID: channelID}); err != nil {
+			return err
+		} else if exists {
+			return ErrForbidden
+		}
+
+		// ホームチャンネルの外部キーはCASCADEなので先に外す
+		if err := tx.Table("user_profiles").Where("home_channel = ?", channelID).UpdateColumn("home_channel", nil).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&model.Channel{ID: channelID}).Error
+	})
+	if err != nil {
+		return err
+	}
+	repo.hub.Publish(hub.Message{
+		Name: event.ChannelDeleted,
+		Fields: hub.Fields{
+			"channel_id": channelID,
+			"private":    !ch.IsPublic,
+		},
+	})
+	return nil
+}
+
 // GetChannel implements ChannelRepository interface.
 func (repo *GormRepository) GetChannel(channelID uuid.UUID) (*model.Channel, error) {
 	if channelID == uuid.Nil {
@@ -215,6 +389,65 @@ func (repo *GormRepository) GetPrivateChannelMemberIDs(channelID uuid.UUID) (use
 		Error
 }
 
+// GetPrivateChannelsByUser implements ChannelRepository interface.
+func (repo *GormRepository) GetPrivateChannelsByUser(userID uuid.UUID) ([]*model.Channel, error) {
+	channels := make([]*model.Channel, 0)
+	if userID == uuid.Nil {
+		return channels, nil
+	}
+	return channels, repo.db.
+		Joins("INNER JOIN users_private_channels upc ON upc.channel_id = channels.id").
+		Where("upc.user_id = ? AND channels.parent_id = ?", userID, model.PrivateChannelRootID).
+		Order("channels.created_at").
+		Find(&channels).
+		Error
+}
+
+// AddPrivateChannelMember implements ChannelRepository interface.
+func (repo *GormRepository) AddPrivateChannelMember(channelID, userID uuid.UUID) error {
+	if channelID == uuid.Nil || userID == uuid.Nil {
+		return ErrNilID
+	}
+	if err := repo.db.Create(&model.UsersPrivateChannel{UserID: userID, ChannelID: channelID}).Error; err != nil {
+		if gormutil.IsMySQLDuplicatedRecordErr(err) {
+			return ErrAlreadyExists
+		}
+		return err
+	}
+	repo.hub.Publish(hub.Message{
+		Name: event.ChannelMemberAdded,
+		Fields: hub.Fields{
+			"channel_id": channelID,
+			"user_id":    userID,
+			"private":    true,
+		},
+	})
+	return nil
+}
+
+// RemovePrivateChannelMember implements ChannelRepository interface.
+func (repo *GormRepository) RemovePrivateChannelMember(channelID, userID uuid.UUID) error {
+	if channelID == uuid.Nil || userID == uuid.Nil {
+		return ErrNilID
+	}
+	result := repo.db.Delete(&model.UsersPrivateChannel{UserID: userID, ChannelID: channelID})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	repo.hub.Publish(hub.Message{
+		Name: event.ChannelMemberRemoved,
+		Fields: hub.Fields{
+			"channel_id": channelID,
+			"user_id":    userID,
+			"private":    true,
+		},
+	})
+	return nil
+}
+
 // ChangeChannelSubscription implements ChannelRepository interface.
 func (repo *GormRepository) ChangeChannelSubscription(channelID uuid.UUID, args ChangeChannelSubscriptionArgs) (on []uuid.UUID, off []uuid.UUID, err error) {
 	if channelID == uuid.Nil {
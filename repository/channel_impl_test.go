@@ -7,6 +7,8 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/traPtitech/traQ/model"
 	"github.com/traPtitech/traQ/utils/optional"
+	"github.com/traPtitech/traQ/utils/random"
+	"github.com/traPtitech/traQ/utils/set"
 	"testing"
 )
 
@@ -160,3 +162,186 @@ func TestGormRepository_GetChannelStats(t *testing.T) {
 		}
 	})
 }
+
+func mustMakePrivateChannel(t *testing.T, repo Repository, name string, members []uuid.UUID) *model.Channel {
+	t.Helper()
+	if name == rand {
+		name = random.AlphaNumeric(20)
+	}
+	ch, err := repo.CreateChannel(model.Channel{
+		Name:      name,
+		ParentID:  uuid.Must(uuid.FromString(model.PrivateChannelRootID)),
+		IsVisible: true,
+	}, set.UUIDSetFromArray(members), false)
+	require.NoError(t, err)
+	return ch
+}
+
+func TestGormRepository_CreateChannel_Private(t *testing.T) {
+	t.Parallel()
+	repo, assert, _, user := setupWithUser(t, common3)
+
+	ch := mustMakePrivateChannel(t, repo, rand, []uuid.UUID{user.GetID()})
+	assert.False(ch.IsPublic)
+	assert.True(ch.IsPrivateChannel())
+
+	assert.Equal(ch.ID.String(), ch.NameScope)
+
+	// 同名のプライベートチャンネルも作成できる
+	other, err := repo.CreateChannel(model.Channel{
+		Name:      ch.Name,
+		ParentID:  ch.ParentID,
+		IsVisible: true,
+	}, set.UUIDSetFromArray([]uuid.UUID{user.GetID()}), false)
+	if assert.NoError(err) {
+		assert.NotEqual(ch.ID, other.ID)
+	}
+}
+
+func TestGormRepository_PrivateChannelMembers(t *testing.T) {
+	t.Parallel()
+	repo, _, _, user := setupWithUser(t, common3)
+
+	t.Run("nil id", func(t *testing.T) {
+		t.Parallel()
+
+		assert.EqualError(t, repo.AddPrivateChannelMember(uuid.Nil, user.GetID()), ErrNilID.Error())
+		assert.EqualError(t, repo.RemovePrivateChannelMember(uuid.Nil, user.GetID()), ErrNilID.Error())
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		owner := mustMakeUser(t, repo, rand)
+		member := mustMakeUser(t, repo, rand)
+		ch := mustMakePrivateChannel(t, repo, rand, []uuid.UUID{owner.GetID()})
+
+		if assert.NoError(repo.AddPrivateChannelMember(ch.ID, member.GetID())) {
+			ids, err := repo.GetPrivateChannelMemberIDs(ch.ID)
+			if assert.NoError(err) {
+				assert.ElementsMatch([]uuid.UUID{owner.GetID(), member.GetID()}, ids)
+			}
+		}
+		assert.EqualError(repo.AddPrivateChannelMember(ch.ID, member.GetID()), ErrAlreadyExists.Error())
+
+		channels, err := repo.GetPrivateChannelsByUser(member.GetID())
+		if assert.NoError(err) && assert.Len(channels, 1) {
+			assert.Equal(ch.ID, channels[0].ID)
+		}
+
+		if assert.NoError(repo.RemovePrivateChannelMember(ch.ID, member.GetID())) {
+			channels, err := repo.GetPrivateChannelsByUser(member.GetID())
+			if assert.NoError(err) {
+				assert.Len(channels, 0)
+			}
+		}
+		assert.EqualError(repo.RemovePrivateChannelMember(ch.ID, member.GetID()), ErrNotFound.Error())
+	})
+}
+
+func TestGormRepository_MergeChannel(t *testing.T) {
+	t.Parallel()
+	repo, _, _, user := setupWithUser(t, common3)
+
+	t.Run("nil id", func(t *testing.T) {
+		t.Parallel()
+
+		assert.EqualError(t, repo.MergeChannel(uuid.Nil, uuid.Nil), ErrNilID.Error())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+
+		ch := mustMakeChannel(t, repo, rand)
+		assert.EqualError(t, repo.MergeChannel(ch.ID, uuid.Must(uuid.NewV4())), ErrNotFound.Error())
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		assert, require := assertAndRequire(t)
+
+		from := mustMakeChannel(t, repo, rand)
+		to := mustMakeChannel(t, repo, rand)
+		child, err := repo.CreateChannel(model.Channel{Name: random.AlphaNumeric(20), ParentID: from.ID, IsVisible: true}, nil, false)
+		require.NoError(err)
+		m := mustMakeMessage(t, repo, user.GetID(), from.ID)
+		w := mustMakeWebhook(t, repo, rand, from.ID, user.GetID(), "")
+		ow := mustMakeOutgoingWebhook(t, repo, w.GetID(), from.ID, user.GetID())
+		mustChangeChannelSubscription(t, repo, from.ID, user.GetID())
+		require.NoError(repo.AddStar(user.GetID(), from.ID))
+		other := mustMakeChannel(t, repo, rand)
+		b, err := repo.CreateBot(random.AlphaNumeric(16), "bot", "desc", uuid.Must(uuid.NewV4()), user.GetID(), model.BotModeHTTP, "https://example.com")
+		require.NoError(err)
+		_, err = repo.SetBotCommands(b.ID, []*model.BotCommand{
+			{Name: "a", Arguments: model.BotCommandArguments{}, ChannelIDs: model.UUIDs{from.ID, other.ID}},
+			{Name: "b", Arguments: model.BotCommandArguments{}, ChannelIDs: model.UUIDs{to.ID, from.ID}},
+		})
+		require.NoError(err)
+
+		require.NoError(repo.MergeChannel(from.ID, to.ID))
+
+		_, err = repo.GetChannel(from.ID)
+		assert.EqualError(err, ErrNotFound.Error())
+
+		if m, err := repo.GetMessageByID(m.ID); assert.NoError(err) {
+			assert.Equal(to.ID, m.ChannelID)
+		}
+		if w, err := repo.GetWebhook(w.GetID()); assert.NoError(err) {
+			assert.Equal(to.ID, w.GetChannelID())
+		}
+		if ow, err := repo.GetOutgoingWebhook(ow.ID); assert.NoError(err) {
+			assert.Equal(to.ID, ow.ChannelID)
+		}
+		if ch, err := repo.GetChannel(child.ID); assert.NoError(err) {
+			assert.Equal(to.ID, ch.ParentID)
+		}
+		if subs, err := repo.GetChannelSubscriptions(ChannelSubscriptionQuery{}.SetChannel(to.ID)); assert.NoError(err) && assert.Len(subs, 1) {
+			assert.Equal(user.GetID(), subs[0].UserID)
+		}
+		if stars, err := repo.GetStaredChannels(user.GetID()); assert.NoError(err) {
+			assert.Contains(stars, to.ID)
+			assert.NotContains(stars, from.ID)
+		}
+		if commands, err := repo.GetBotCommands(b.ID); assert.NoError(err) && assert.Len(commands, 2) {
+			assert.Equal(model.UUIDs{to.ID, other.ID}, commands[0].ChannelIDs)
+			assert.Equal(model.UUIDs{to.ID}, commands[1].ChannelIDs)
+		}
+	})
+}
+
+func TestGormRepository_DeleteChannel(t *testing.T) {
+	t.Parallel()
+	repo, _, _, user := setupWithUser(t, common3)
+
+	t.Run("nil id", func(t *testing.T) {
+		t.Parallel()
+
+		assert.EqualError(t, repo.DeleteChannel(uuid.Nil), ErrNilID.Error())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+
+		assert.EqualError(t, repo.DeleteChannel(uuid.Must(uuid.NewV4())), ErrNotFound.Error())
+	})
+
+	t.Run("has messages", func(t *testing.T) {
+		t.Parallel()
+
+		ch := mustMakeChannel(t, repo, rand)
+		mustMakeMessage(t, repo, user.GetID(), ch.ID)
+		assert.EqualError(t, repo.DeleteChannel(ch.ID), ErrForbidden.Error())
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		ch := mustMakeChannel(t, repo, rand)
+		if assert.NoError(repo.DeleteChannel(ch.ID)) {
+			_, err := repo.GetChannel(ch.ID)
+			assert.EqualError(err, ErrNotFound.Error())
+		}
+	})
+}
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/traPtitech/traQ/model"
+)
+
+// ChannelRoleRepository チャンネルロールリポジトリ
+type ChannelRoleRepository interface {
+	// SetChannelRole 指定したチャンネルでのユーザーのチャンネルロールを設定します
+	//
+	// 成功した場合、nilを返します。既にロールが設定されている場合は上書きします。
+	// 引数にuuid.Nilを指定するとErrNilIDを返します。
+	// DBによるエラーを返すことがあります。
+	SetChannelRole(channelID, userID uuid.UUID, role string) error
+	// DeleteChannelRole 指定したチャンネルでのユーザーのチャンネルロールを削除します
+	//
+	// 成功した場合、nilを返します。
+	// ロールが設定されていなかった場合、ErrNotFoundを返します。
+	// 引数にuuid.Nilを指定するとErrNilIDを返します。
+	// DBによるエラーを返すことがあります。
+	DeleteChannelRole(channelID, userID uuid.UUID) error
+	// GetChannelRoles 指定したチャンネルに直接設定されているチャンネルロールを全て取得します
+	//
+	// 成功した場合、チャンネルロールの配列とnilを返します。
+	// 存在しないチャンネルを指定した場合は空配列とnilを返します。
+	// DBによるエラーを返すことがあります。
+	GetChannelRoles(channelID uuid.UUID) ([]*model.ChannelRole, error)
+}
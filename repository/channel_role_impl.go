@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/leandro-lugaresi/hub"
+	"github.com/traPtitech/traQ/event"
+	"github.com/traPtitech/traQ/model"
+)
+
+// SetChannelRole implements ChannelRoleRepository interface.
+func (repo *GormRepository) SetChannelRole(channelID, userID uuid.UUID, role string) error {
+	if channelID == uuid.Nil || userID == uuid.Nil {
+		return ErrNilID
+	}
+	if len(role) == 0 {
+		return ArgError("role", "Role is empty")
+	}
+
+	var r model.ChannelRole
+	if err := repo.db.
+		Where(&model.ChannelRole{ChannelID: channelID, UserID: userID}).
+		Assign(&model.ChannelRole{Role: role}).
+		FirstOrCreate(&r).
+		Error; err != nil {
+		return err
+	}
+	repo.hub.Publish(hub.Message{
+		Name: event.ChannelRoleUpdated,
+		Fields: hub.Fields{
+			"channel_id": channelID,
+			"user_id":    userID,
+			"role":       role,
+		},
+	})
+	return nil
+}
+
+// DeleteChannelRole implements ChannelRoleRepository interface.
+func (repo *GormRepository) DeleteChannelRole(channelID, userID uuid.UUID) error {
+	if channelID == uuid.Nil || userID == uuid.Nil {
+		return ErrNilID
+	}
+	result := repo.db.Where(&model.ChannelRole{ChannelID: channelID, UserID: userID}).Delete(&model.ChannelRole{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	repo.hub.Publish(hub.Message{
+		Name: event.ChannelRoleDeleted,
+		Fields: hub.Fields{
+			"channel_id": channelID,
+			"user_id":    userID,
+		},
+	})
+	return nil
+}
+
+// GetChannelRoles implements ChannelRoleRepository interface.
+func (repo *GormRepository) GetChannelRoles(channelID uuid.UUID) ([]*model.ChannelRole, error) {
+	roles := make([]*model.ChannelRole, 0)
+	if channelID == uuid.Nil {
+		return roles, nil
+	}
+	return roles, repo.db.Where(&model.ChannelRole{ChannelID: channelID}).Order("created_at").Find(&roles).Error
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/traPtitech/traQ/service/rbac/role"
+	"testing"
+)
+
+func TestRepositoryImpl_SetChannelRole(t *testing.T) {
+	t.Parallel()
+	repo, _, _, user, channel := setupWithUserAndChannel(t, common3)
+
+	t.Run("nil id", func(t *testing.T) {
+		t.Parallel()
+
+		assert.EqualError(t, repo.SetChannelRole(uuid.Nil, user.GetID(), role.ChannelOwner), ErrNilID.Error())
+		assert.EqualError(t, repo.SetChannelRole(channel.ID, uuid.Nil, role.ChannelOwner), ErrNilID.Error())
+	})
+
+	t.Run("empty role", func(t *testing.T) {
+		t.Parallel()
+
+		assert.True(t, IsArgError(repo.SetChannelRole(channel.ID, user.GetID(), "")))
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		ch := mustMakeChannel(t, repo, rand)
+		u := mustMakeUser(t, repo, rand)
+
+		if assert.NoError(repo.SetChannelRole(ch.ID, u.GetID(), role.ChannelModerator)) {
+			roles, err := repo.GetChannelRoles(ch.ID)
+			if assert.NoError(err) && assert.Len(roles, 1) {
+				assert.Equal(u.GetID(), roles[0].UserID)
+				assert.Equal(role.ChannelModerator, roles[0].Role)
+			}
+		}
+
+		// 上書き
+		if assert.NoError(repo.SetChannelRole(ch.ID, u.GetID(), role.ChannelOwner)) {
+			roles, err := repo.GetChannelRoles(ch.ID)
+			if assert.NoError(err) && assert.Len(roles, 1) {
+				assert.Equal(role.ChannelOwner, roles[0].Role)
+			}
+		}
+	})
+}
+
+func TestRepositoryImpl_DeleteChannelRole(t *testing.T) {
+	t.Parallel()
+	repo, _, _, user, channel := setupWithUserAndChannel(t, common3)
+
+	t.Run("nil id", func(t *testing.T) {
+		t.Parallel()
+
+		assert.EqualError(t, repo.DeleteChannelRole(uuid.Nil, user.GetID()), ErrNilID.Error())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+
+		assert.EqualError(t, repo.DeleteChannelRole(channel.ID, uuid.Must(uuid.NewV4())), ErrNotFound.Error())
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		ch := mustMakeChannel(t, repo, rand)
+		u := mustMakeUser(t, repo, rand)
+		mustSetChannelRole(t, repo, ch.ID, u.GetID(), role.ChannelOwner)
+
+		if assert.NoError(repo.DeleteChannelRole(ch.ID, u.GetID())) {
+			roles, err := repo.GetChannelRoles(ch.ID)
+			if assert.NoError(err) {
+				assert.Len(roles, 0)
+			}
+		}
+	})
+}
+
+func TestRepositoryImpl_GetChannelRoles(t *testing.T) {
+	t.Parallel()
+	repo, _, _ := setup(t, common3)
+
+	t.Run("nil id", func(t *testing.T) {
+		t.Parallel()
+
+		roles, err := repo.GetChannelRoles(uuid.Nil)
+		if assert.NoError(t, err) {
+			assert.Len(t, roles, 0)
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		ch := mustMakeChannel(t, repo, rand)
+		mustSetChannelRole(t, repo, ch.ID, mustMakeUser(t, repo, rand).GetID(), role.ChannelOwner)
+		mustSetChannelRole(t, repo, ch.ID, mustMakeUser(t, repo, rand).GetID(), role.ChannelModerator)
+
+		roles, err := repo.GetChannelRoles(ch.ID)
+		if assert.NoError(err) {
+			assert.Len(roles, 2)
+		}
+	})
+}
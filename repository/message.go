@@ -1,3 +1,4 @@
+//go:generate mockgen -source=$GOFILE -destination=mock_$GOPACKAGE/mock_$GOFILE
 package repository
 
 import (
@@ -12,6 +13,8 @@ import (
 type MessagesQuery struct {
 	User    uuid.UUID
 	Channel uuid.UUID
+	// Parent 指定したメッセージのスレッドへの返信を指定
+	Parent uuid.UUID
 	// ChannelsSubscribedByUser 指定したユーザーが購読しているチャンネルのメッセージを指定
 	ChannelsSubscribedByUser uuid.UUID
 	Since                    optional.Time
@@ -22,6 +25,8 @@ type MessagesQuery struct {
 	Asc                      bool
 	ExcludeDMs               bool
 	DisablePreload           bool
+	// ExcludeReplies スレッドへの返信を除外する
+	ExcludeReplies bool
 }
 
 // MessageRepository メッセージリポジトリ
@@ -32,6 +37,16 @@ type MessageRepository interface {
 	// 引数にuuid.Nilを指定するとErrNilIDを返します。
 	// DBによるエラーを返すことがあります。
 	CreateMessage(userID, channelID uuid.UUID, text string) (*model.Message, error)
+	// CreateReply 指定したメッセージのスレッドに返信を作成します
+	//
+	// 成功した場合、メッセージとnilを返します。
+	// 返信は親メッセージと同じチャンネルに作成されます。
+	// 親メッセージ自体がスレッドへの返信の場合は、その親メッセージのスレッドへの返信になります。
+	// MessageCreatedイベントは発行されず、MessageRepliedイベントが発行されます。
+	// 存在しない親メッセージを指定した場合、ErrNotFoundを返します。
+	// 引数にuuid.Nilを指定するとErrNilIDを返します。
+	// DBによるエラーを返すことがあります。
+	CreateReply(userID, parentID uuid.UUID, text string) (*model.Message, error)
 	// UpdateMessage 指定したメッセージを更新します
 	//
 	// 成功した場合、nilを返します。
@@ -39,9 +54,30 @@ type MessageRepository interface {
 	// 引数にuuid.Nilを指定するとErrNilIDを返します。
 	// DBによるエラーを返すことがあります。
 	UpdateMessage(messageID uuid.UUID, text string) error
+	// SetMessageHidden 指定したメッセージの非表示状態を設定します
+	//
+	// 成功した場合、nilを返します。非表示のメッセージは本文が表示されなくなります。
+	// 存在しないメッセージを指定した場合、ErrNotFoundを返します。
+	// 引数にuuid.Nilを指定するとErrNilIDを返します。
+	// DBによるエラーを返すことがあります。
+	SetMessageHidden(messageID uuid.UUID, hidden bool) error
+	// SetMessageComponents 指定したメッセージのインタラクティブコンポーネントを設定します
+	//
+	// 成功した場合、nilを返します。componentsが空の場合はコンポーネントを削除します。
+	// 存在しないメッセージを指定した場合、ErrNotFoundを返します。
+	// 引数にuuid.Nilを指定するとErrNilIDを返します。
+	// DBによるエラーを返すことがあります。
+	SetMessageComponents(messageID uuid.UUID, components model.MessageComponents) error
+	// SetMessageAuthorOverride 指定したメッセージの投稿者の表示名・アイコンを上書きします
+	//
+	// 成功した場合、nilを返します。既に上書き設定が存在する場合は置き換えます。
+	// 存在しないメッセージを指定した場合、ErrNotFoundを返します。
+	// 引数にuuid.Nilを指定するとErrNilIDを返します。
+	// DBによるエラーを返すことがあります。
+	SetMessageAuthorOverride(messageID uuid.UUID, displayName string, iconFileID optional.UUID) error
 	// DeleteMessage 指定したメッセージを削除します
 	//
-	// 成功した場合、nilを返します。
+	// 成功した場合、nilを返します。スレッドの親メッセージを削除した場合、その返信も全て削除します。
 	// 存在しないメッセージを指定した場合、ErrNotFoundを返します。
 	// 引数にuuid.Nilを指定するとErrNilIDを返します。
 	// DBによるエラーを返すことがあります。
@@ -52,6 +88,12 @@ type MessageRepository interface {
 	// 存在しないメッセージを指定した場合、ErrNotFoundを返します。
 	// DBによるエラーを返すことがあります。
 	GetMessageByID(messageID uuid.UUID) (*model.Message, error)
+	// GetMessagesByIDs 指定したメッセージを全て取得します
+	//
+	// 成功した場合、メッセージの配列とnilを返します。順番は保証されません。
+	// 存在しないメッセージは配列に含まれません。
+	// DBによるエラーを返すことがあります。
+	GetMessagesByIDs(messageIDs []uuid.UUID) ([]*model.Message, error)
 	// GetMessages 指定したクエリでメッセージを取得します
 	//
 	// 成功した場合、メッセージの配列を返します。負のoffset, limitは無視されます。
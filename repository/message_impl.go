@@ -8,6 +8,7 @@ import (
 	"github.com/traPtitech/traQ/event"
 	"github.com/traPtitech/traQ/model"
 	"github.com/traPtitech/traQ/utils/message"
+	"github.com/traPtitech/traQ/utils/optional"
 	"strings"
 	"time"
 )
@@ -26,31 +27,107 @@ func (repo *GormRepository) CreateMessage(userID, channelID uuid.UUID, text stri
 		Stamps:    []model.MessageStamp{},
 	}
 	err := repo.db.Transaction(func(tx *gorm.DB) error {
-		if err := tx.Create(m).Error; err != nil {
-			return err
+		return createMessage(tx, m)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	repo.publishMessageCreated(m)
+	return m, nil
+}
+
+// createMessage メッセージを作成し、チャンネルの最新メッセージを更新します
+func createMessage(tx *gorm.DB, m *model.Message) error {
+	if err := tx.Create(m).Error; err != nil {
+		return err
+	}
+
+	clm := &model.ChannelLatestMessage{
+		ChannelID: m.ChannelID,
+		MessageID: m.ID,
+		DateTime:  m.CreatedAt,
+	}
+
+	return tx.
+		Set("gorm:insert_option", fmt.Sprintf("ON DUPLICATE KEY UPDATE message_id = '%s', date_time = '%s'", clm.MessageID, clm.DateTime.In(time.UTC).Format("2006-01-02 15:04:05.999999"))).
+		Create(clm).
+		Error
+}
+
+// publishMessageCreated メッセージ作成イベントを発行します
+func (repo *GormRepository) publishMessageCreated(m *model.Message) {
+	parseResult := message.Parse(m.Text)
+	repo.hub.Publish(hub.Message{
+		Name: event.MessageCreated,
+		Fields: hub.Fields{
+			"message_id":   m.ID,
+			"message":      m,
+			"parse_result": parseResult,
+		},
+	})
+	if len(parseResult.Citation) > 0 {
+		repo.hub.Publish(hub.Message{
+			Name: event.MessageCited,
+			Fields: hub.Fields{
+				"message_id": m.ID,
+				"message":    m,
+				"cited_ids":  parseResult.Citation,
+			},
+		})
+	}
+}
+
+// CreateReply implements MessageRepository interface.
+func (repo *GormRepository) CreateReply(userID, parentID uuid.UUID, text string) (*model.Message, error) {
+	if userID == uuid.Nil || parentID == uuid.Nil {
+		return nil, ErrNilID
+	}
+
+	var (
+		parent model.Message
+		m      *model.Message
+	)
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Set("gorm:query_option", "FOR UPDATE").Where(&model.Message{ID: parentID}).First(&parent).Error; err != nil {
+			return convertError(err)
+		}
+		if parent.IsReply() {
+			// スレッドは一階層のみ
+			if err := tx.Set("gorm:query_option", "FOR UPDATE").Where(&model.Message{ID: parent.ParentID.UUID}).First(&parent).Error; err != nil {
+				return convertError(err)
+			}
 		}
 
-		clm := &model.ChannelLatestMessage{
-			ChannelID: m.ChannelID,
-			MessageID: m.ID,
-			DateTime:  m.CreatedAt,
+		m = &model.Message{
+			ID:        uuid.Must(uuid.NewV4()),
+			UserID:    userID,
+			ChannelID: parent.ChannelID,
+			ParentID:  optional.UUIDFrom(parent.ID),
+			Text:      text,
+			Stamps:    []model.MessageStamp{},
+		}
+		if err := createMessage(tx, m); err != nil {
+			return err
 		}
 
-		return tx.
-			Set("gorm:insert_option", fmt.Sprintf("ON DUPLICATE KEY UPDATE message_id = '%s', date_time = '%s'", clm.MessageID, clm.DateTime.In(time.UTC).Format("2006-01-02 15:04:05.999999"))).
-			Create(clm).
-			Error
+		// 返信数をインクリメント(更新日時は変更しない)
+		parent.ReplyCount++
+		return tx.Model(&model.Message{ID: parent.ID}).UpdateColumn("reply_count", gorm.Expr("reply_count + 1")).Error
 	})
 	if err != nil {
 		return nil, err
 	}
 
+	// 返信はチャンネルへの投稿ではないため、MessageCreatedは発行しない
 	parseResult := message.Parse(text)
 	repo.hub.Publish(hub.Message{
-		Name: event.MessageCreated,
+		Name: event.MessageReplied,
 		Fields: hub.Fields{
 			"message_id":   m.ID,
 			"message":      m,
+			"parent_id":    parent.ID,
+			"parent":       &parent,
 			"parse_result": parseResult,
 		},
 	})
@@ -118,6 +195,118 @@ func (repo *GormRepository) UpdateMessage(messageID uuid.UUID, text string) erro
 	return nil
 }
 
+// SetMessageHidden implements MessageRepository interface.
+func (repo *GormRepository) SetMessageHidden(messageID uuid.UUID, hidden bool) error {
+	if messageID == uuid.Nil {
+		return ErrNilID
+	}
+
+	var (
+		old model.Message
+		new model.Message
+		ok  bool
+	)
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&old, &model.Message{ID: messageID}).Error; err != nil {
+			return convertError(err)
+		}
+		if old.Hidden == hidden {
+			return nil
+		}
+
+		// 更新日時は変更しない
+		if err := tx.Model(&model.Message{ID: messageID}).UpdateColumn("hidden", hidden).Error; err != nil {
+			return err
+		}
+
+		ok = true
+		return tx.Where(&model.Message{ID: messageID}).First(&new).Error
+	})
+	if err != nil {
+		return err
+	}
+	if ok {
+		repo.hub.Publish(hub.Message{
+			Name: event.MessageUpdated,
+			Fields: hub.Fields{
+				"message_id":  messageID,
+				"old_message": &old,
+				"message":     &new,
+			},
+		})
+	}
+	return nil
+}
+
+// SetMessageComponents implements MessageRepository interface.
+func (repo *GormRepository) SetMessageComponents(messageID uuid.UUID, components model.MessageComponents) error {
+	if messageID == uuid.Nil {
+		return ErrNilID
+	}
+
+	var m model.Message
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&m, &model.Message{ID: messageID}).Error; err != nil {
+			return convertError(err)
+		}
+
+		if len(components) == 0 {
+			return tx.Delete(&model.MessageComponentSet{MessageID: messageID}).Error
+		}
+		var n int
+		if err := tx.Model(&model.MessageComponentSet{}).Where(&model.MessageComponentSet{MessageID: messageID}).Count(&n).Error; err != nil {
+			return err
+		}
+		if n > 0 {
+			return tx.Model(&model.MessageComponentSet{MessageID: messageID}).Update("components", components).Error
+		}
+		return tx.Create(&model.MessageComponentSet{MessageID: messageID, Components: components}).Error
+	})
+	if err != nil {
+		return err
+	}
+	repo.hub.Publish(hub.Message{
+		Name: event.MessageUpdated,
+		Fields: hub.Fields{
+			"message_id":  messageID,
+			"old_message": &m,
+			"message":     &m,
+		},
+	})
+	return nil
+}
+
+// SetMessageAuthorOverride implements MessageRepository interface.
+func (repo *GormRepository) SetMessageAuthorOverride(messageID uuid.UUID, displayName string, iconFileID optional.UUID) error {
+	if messageID == uuid.Nil {
+		return ErrNilID
+	}
+
+	var m model.Message
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&m, &model.Message{ID: messageID}).Error; err != nil {
+			return convertError(err)
+		}
+
+		if err := tx.Delete(&model.MessageAuthorOverride{MessageID: messageID}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&model.MessageAuthorOverride{MessageID: messageID, DisplayName: displayName, IconFileID: iconFileID}).Error
+	})
+	if err != nil {
+		return err
+	}
+	repo.hub.Publish(hub.Message{
+		Name: event.MessageUpdated,
+		Fields: hub.Fields{
+			"message_id":  messageID,
+			"old_message": &m,
+			"message":     &m,
+		},
+	})
+	return nil
+}
+
 // DeleteMessage implements MessageRepository interface.
 func (repo *GormRepository) DeleteMessage(messageID uuid.UUID) error {
 	if messageID == uuid.Nil {
@@ -126,7 +315,8 @@ func (repo *GormRepository) DeleteMessage(messageID uuid.UUID) error {
 
 	var (
 		m       model.Message
-		unreads []*model.Unread
+		replies []*model.Message
+		unreads = map[uuid.UUID][]*model.Unread{}
 		ok      bool
 	)
 	err := repo.db.Transaction(func(tx *gorm.DB) error {
@@ -134,15 +324,34 @@ func (repo *GormRepository) DeleteMessage(messageID uuid.UUID) error {
 			return convertError(err)
 		}
 
-		if err := tx.Find(&unreads, &model.Unread{MessageID: messageID}).Error; err != nil {
+		if m.IsReply() {
+			// 親メッセージの返信数をデクリメント(更新日時は変更しない)
+			if err := tx.Model(&model.Message{ID: m.ParentID.UUID}).UpdateColumn("reply_count", gorm.Expr("GREATEST(reply_count - 1, 0)")).Error; err != nil {
+				return err
+			}
+		} else if err := tx.Where("parent_id = ?", messageID).Find(&replies).Error; err != nil {
+			// 親メッセージを削除する場合は、スレッドの返信も削除する
 			return err
 		}
 
+		ids := []uuid.UUID{messageID}
+		for _, r := range replies {
+			ids = append(ids, r.ID)
+		}
+
+		var us []*model.Unread
+		if err := tx.Where("message_id IN (?)", ids).Find(&us).Error; err != nil {
+			return err
+		}
+		for _, u := range us {
+			unreads[u.MessageID] = append(unreads[u.MessageID], u)
+		}
+
 		errs := tx.
-			Delete(&m).
-			Delete(model.Unread{}, &model.Unread{MessageID: messageID}).
-			Delete(model.Pin{}, &model.Pin{MessageID: messageID}).
-			Delete(model.ClipFolderMessage{}, &model.ClipFolderMessage{MessageID: messageID}).
+			Delete(model.Message{}, "id IN (?)", ids).
+			Delete(model.Unread{}, "message_id IN (?)", ids).
+			Delete(model.Pin{}, "message_id IN (?)", ids).
+			Delete(model.ClipFolderMessage{}, "message_id IN (?)", ids).
 			GetErrors()
 		if len(errs) > 0 {
 			return errs[0]
@@ -154,14 +363,16 @@ func (repo *GormRepository) DeleteMessage(messageID uuid.UUID) error {
 		return err
 	}
 	if ok {
-		repo.hub.Publish(hub.Message{
-			Name: event.MessageDeleted,
-			Fields: hub.Fields{
-				"message_id":      messageID,
-				"message":         &m,
-				"deleted_unreads": unreads,
-			},
-		})
+		for _, r := range append(replies, &m) {
+			repo.hub.Publish(hub.Message{
+				Name: event.MessageDeleted,
+				Fields: hub.Fields{
+					"message_id":      r.ID,
+					"message":         r,
+					"deleted_unreads": unreads[r.ID],
+				},
+			})
+		}
 	}
 	return nil
 }
@@ -178,6 +389,15 @@ func (repo *GormRepository) GetMessageByID(messageID uuid.UUID) (*model.Message,
 	return message, nil
 }
 
+// GetMessagesByIDs implements MessageRepository interface.
+func (repo *GormRepository) GetMessagesByIDs(messageIDs []uuid.UUID) ([]*model.Message, error) {
+	messages := make([]*model.Message, 0, len(messageIDs))
+	if len(messageIDs) == 0 {
+		return messages, nil
+	}
+	return messages, repo.db.Scopes(messagePreloads).Where("id IN (?)", messageIDs).Find(&messages).Error
+}
+
 // GetMessages implements MessageRepository interface.
 func (repo *GormRepository) GetMessages(query MessagesQuery) (messages []*model.Message, more bool, err error) {
 	messages = make([]*model.Message, 0)
@@ -197,7 +417,7 @@ func (repo *GormRepository) GetMessages(query MessagesQuery) (messages []*model.
 		tx = tx.Offset(query.Offset)
 	}
 
-	if query.ExcludeDMs && query.Channel == uuid.Nil && query.User == uuid.Nil && query.ChannelsSubscribedByUser == uuid.Nil && !query.Since.Valid && !query.Until.Valid && query.Limit > 0 {
+	if query.ExcludeDMs && query.Channel == uuid.Nil && query.User == uuid.Nil && query.Parent == uuid.Nil && query.ChannelsSubscribedByUser == uuid.Nil && !query.ExcludeReplies && !query.Since.Valid && !query.Until.Valid && query.Limit > 0 {
 		// アクティビティ用にUSE INDEX指定でクエリ発行
 		// TODO 綺麗じゃない
 		err = tx.
@@ -221,6 +441,12 @@ func (repo *GormRepository) GetMessages(query MessagesQuery) (messages []*model.
 	if query.User != uuid.Nil {
 		tx = tx.Where("messages.user_id = ?", query.User)
 	}
+	if query.Parent != uuid.Nil {
+		tx = tx.Where("messages.parent_id = ?", query.Parent)
+	}
+	if query.ExcludeReplies {
+		tx = tx.Where("messages.parent_id IS NULL")
+	}
 	if query.ChannelsSubscribedByUser != uuid.Nil {
 		tx = tx.Where("channels.is_forced = TRUE OR channels.id IN (SELECT s.channel_id FROM users_subscribe_channels s WHERE s.user_id = ?)", query.ChannelsSubscribedByUser)
 	}
@@ -427,5 +653,7 @@ func messagePreloads(db *gorm.DB) *gorm.DB {
 		Preload("Stamps", func(db *gorm.DB) *gorm.DB {
 			return db.Order("updated_at")
 		}).
-		Preload("Pin")
+		Preload("Pin").
+		Preload("Components").
+		Preload("AuthorOverride")
 }
@@ -4,6 +4,7 @@ import (
 	"github.com/gofrs/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/utils/optional"
 	"testing"
 )
 
@@ -69,6 +70,68 @@ func TestRepositoryImpl_CreateMessage(t *testing.T) {
 	})
 }
 
+func TestRepositoryImpl_CreateReply(t *testing.T) {
+	t.Parallel()
+	repo, assert, _, user, channel := setupWithUserAndChannel(t, common3)
+
+	parent := mustMakeMessage(t, repo, user.GetID(), channel.ID)
+
+	_, err := repo.CreateReply(uuid.Nil, parent.ID, "a")
+	assert.EqualError(err, ErrNilID.Error())
+	_, err = repo.CreateReply(user.GetID(), uuid.Nil, "a")
+	assert.EqualError(err, ErrNilID.Error())
+	_, err = repo.CreateReply(user.GetID(), uuid.Must(uuid.NewV4()), "a")
+	assert.EqualError(err, ErrNotFound.Error())
+
+	r1, err := repo.CreateReply(user.GetID(), parent.ID, "reply")
+	if assert.NoError(err) {
+		assert.Equal(channel.ID, r1.ChannelID)
+		assert.Equal(parent.ID, r1.ParentID.UUID)
+		assert.Equal("reply", r1.Text)
+	}
+
+	// 返信への返信は親メッセージのスレッドへの返信になる
+	r2, err := repo.CreateReply(user.GetID(), r1.ID, "reply to reply")
+	if assert.NoError(err) {
+		assert.Equal(parent.ID, r2.ParentID.UUID)
+	}
+
+	// チャンネルの最新メッセージは返信で更新される
+	var clm model.ChannelLatestMessage
+	if assert.NoError(getDB(repo).Where(&model.ChannelLatestMessage{ChannelID: channel.ID}).First(&clm).Error) {
+		assert.Equal(r2.ID, clm.MessageID)
+	}
+
+	p, err := repo.GetMessageByID(parent.ID)
+	if assert.NoError(err) {
+		assert.Equal(2, p.ReplyCount)
+	}
+
+	replies, _, err := repo.GetMessages(MessagesQuery{Parent: parent.ID, Asc: true})
+	if assert.NoError(err) && assert.Len(replies, 2) {
+		assert.Equal(r1.ID, replies[0].ID)
+		assert.Equal(r2.ID, replies[1].ID)
+	}
+
+	messages, _, err := repo.GetMessages(MessagesQuery{Channel: channel.ID, ExcludeReplies: true})
+	if assert.NoError(err) && assert.Len(messages, 1) {
+		assert.Equal(parent.ID, messages[0].ID)
+	}
+
+	if assert.NoError(repo.DeleteMessage(r2.ID)) {
+		p, err := repo.GetMessageByID(parent.ID)
+		if assert.NoError(err) {
+			assert.Equal(1, p.ReplyCount)
+		}
+	}
+
+	// 親メッセージを削除すると返信も削除される
+	if assert.NoError(repo.DeleteMessage(parent.ID)) {
+		_, err := repo.GetMessageByID(r1.ID)
+		assert.EqualError(err, ErrNotFound.Error())
+	}
+}
+
 func TestRepositoryImpl_UpdateMessage(t *testing.T) {
 	t.Parallel()
 	repo, assert, _, user, channel := setupWithUserAndChannel(t, common3)
@@ -120,6 +183,30 @@ func TestRepositoryImpl_GetMessageByID(t *testing.T) {
 	assert.Error(err)
 }
 
+func TestRepositoryImpl_GetMessagesByIDs(t *testing.T) {
+	t.Parallel()
+	repo, assert, _, user, channel := setupWithUserAndChannel(t, common3)
+
+	m1 := mustMakeMessage(t, repo, user.GetID(), channel.ID)
+	m2 := mustMakeMessage(t, repo, user.GetID(), channel.ID)
+	m3 := mustMakeMessage(t, repo, user.GetID(), channel.ID)
+	assert.NoError(repo.DeleteMessage(m3.ID))
+
+	messages, err := repo.GetMessagesByIDs(nil)
+	if assert.NoError(err) {
+		assert.Empty(messages)
+	}
+
+	messages, err = repo.GetMessagesByIDs([]uuid.UUID{m1.ID, m2.ID, m3.ID, uuid.Must(uuid.NewV4())})
+	if assert.NoError(err) {
+		ids := make([]uuid.UUID, len(messages))
+		for i, m := range messages {
+			ids[i] = m.ID
+		}
+		assert.ElementsMatch([]uuid.UUID{m1.ID, m2.ID}, ids)
+	}
+}
+
 func TestRepositoryImpl_SetMessageUnread(t *testing.T) {
 	t.Parallel()
 	repo, assert, _, user, channel := setupWithUserAndChannel(t, common3)
@@ -355,3 +442,84 @@ func TestRepositoryImpl_RemoveStampFromMessage(t *testing.T) {
 		}
 	})
 }
+
+func TestRepositoryImpl_SetMessageHidden(t *testing.T) {
+	t.Parallel()
+	repo, assert, require, user, channel := setupWithUserAndChannel(t, common3)
+
+	m := mustMakeMessage(t, repo, user.GetID(), channel.ID)
+
+	assert.EqualError(repo.SetMessageHidden(uuid.Nil, true), ErrNilID.Error())
+	assert.EqualError(repo.SetMessageHidden(uuid.Must(uuid.NewV4()), true), ErrNotFound.Error())
+
+	if assert.NoError(repo.SetMessageHidden(m.ID, true)) {
+		m, err := repo.GetMessageByID(m.ID)
+		require.NoError(err)
+		assert.True(m.Hidden)
+	}
+	if assert.NoError(repo.SetMessageHidden(m.ID, false)) {
+		m, err := repo.GetMessageByID(m.ID)
+		require.NoError(err)
+		assert.False(m.Hidden)
+	}
+}
+
+func TestRepositoryImpl_SetMessageComponents(t *testing.T) {
+	t.Parallel()
+	repo, assert, require, user, channel := setupWithUserAndChannel(t, common3)
+
+	m := mustMakeMessage(t, repo, user.GetID(), channel.ID)
+	components := model.MessageComponents{
+		{ID: "approve", Type: model.MessageComponentTypeButton, Label: "承認"},
+	}
+
+	assert.EqualError(repo.SetMessageComponents(uuid.Nil, components), ErrNilID.Error())
+	assert.EqualError(repo.SetMessageComponents(uuid.Must(uuid.NewV4()), components), ErrNotFound.Error())
+
+	if assert.NoError(repo.SetMessageComponents(m.ID, components)) {
+		m, err := repo.GetMessageByID(m.ID)
+		require.NoError(err)
+		if assert.NotNil(m.Components) {
+			assert.Equal(components, m.Components.Components)
+		}
+	}
+	components = append(components, model.MessageComponent{ID: "reject", Type: model.MessageComponentTypeButton, Label: "却下"})
+	if assert.NoError(repo.SetMessageComponents(m.ID, components)) {
+		m, err := repo.GetMessageByID(m.ID)
+		require.NoError(err)
+		if assert.NotNil(m.Components) {
+			assert.Equal(components, m.Components.Components)
+		}
+	}
+	if assert.NoError(repo.SetMessageComponents(m.ID, nil)) {
+		m, err := repo.GetMessageByID(m.ID)
+		require.NoError(err)
+		assert.Nil(m.Components)
+	}
+}
+
+func TestRepositoryImpl_SetMessageAuthorOverride(t *testing.T) {
+	t.Parallel()
+	repo, assert, require, user, channel := setupWithUserAndChannel(t, common3)
+
+	m := mustMakeMessage(t, repo, user.GetID(), channel.ID)
+
+	assert.EqualError(repo.SetMessageAuthorOverride(uuid.Nil, "name", optional.UUID{}), ErrNilID.Error())
+	assert.EqualError(repo.SetMessageAuthorOverride(uuid.Must(uuid.NewV4()), "name", optional.UUID{}), ErrNotFound.Error())
+
+	if assert.NoError(repo.SetMessageAuthorOverride(m.ID, "name", optional.UUID{})) {
+		m, err := repo.GetMessageByID(m.ID)
+		require.NoError(err)
+		if assert.NotNil(m.AuthorOverride) {
+			assert.Equal("name", m.AuthorOverride.DisplayName)
+			assert.False(m.AuthorOverride.IconFileID.Valid)
+		}
+	}
+	if assert.NoError(repo.SetMessageAuthorOverride(m.ID, "name2", optional.UUID{})) {
+		m, err := repo.GetMessageByID(m.ID)
+		require.NoError(err)
+		if assert.NotNil(m.AuthorOverride) {
+			assert.Equal("name2", m.AuthorOverride.DisplayName)
+		}
+	}
+}
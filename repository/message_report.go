@@ -3,8 +3,34 @@ package repository
 import (
 	"github.com/gofrs/uuid"
 	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/utils/optional"
 )
 
+// MessageReportsQuery GetMessageReports用クエリ
+type MessageReportsQuery struct {
+	Message   uuid.UUID
+	Reporter  uuid.UUID
+	Status    string
+	Since     optional.Time
+	Until     optional.Time
+	Inclusive bool
+	Limit     int
+	Offset    int
+	Asc       bool
+}
+
+// HandleMessageReportArgs メッセージ通報対応引数
+type HandleMessageReportArgs struct {
+	// HandlerID 対応者のユーザーID
+	HandlerID uuid.UUID
+	// Status 対応後の状態 model.MessageReportStatusResolvedかmodel.MessageReportStatusDismissed
+	Status string
+	// Action メッセージに対して行った操作
+	Action string
+	// Note 対応メモ
+	Note string
+}
+
 // MessageReportRepository メッセージ通報リポジトリ
 type MessageReportRepository interface {
 	// CreateMessageReport 指定したユーザーによる指定したメッセージの通報を登録します
@@ -14,11 +40,18 @@ type MessageReportRepository interface {
 	// 引数にuuid.Nilを指定するとErrNilIDを返します。
 	// DBによるエラーを返すことがあります。
 	CreateMessageReport(messageID, reporterID uuid.UUID, reason string) error
-	// GetMessageReports メッセージ通報を通報日時の昇順で取得します
+	// GetMessageReport 指定したメッセージ通報を取得します
 	//
-	// 成功した場合、メッセージ通報の配列とnilを返します。負のoffset, limitは無視されます。
+	// 成功した場合、メッセージ通報とnilを返します。
+	// 存在しなかった場合、ErrNotFoundを返します。
 	// DBによるエラーを返すことがあります。
-	GetMessageReports(offset, limit int) ([]*model.MessageReport, error)
+	GetMessageReport(reportID uuid.UUID) (*model.MessageReport, error)
+	// GetMessageReports 指定したクエリでメッセージ通報を取得します
+	//
+	// 成功した場合、メッセージ通報の配列と、更に取得できるメッセージ通報が存在するかどうかとnilを返します。
+	// 負のoffset, limitは無視されます。
+	// DBによるエラーを返すことがあります。
+	GetMessageReports(query MessageReportsQuery) (reports []*model.MessageReport, more bool, err error)
 	// GetMessageReportsByMessageID 指定したメッセージのメッセージ通報を全て取得します
 	//
 	// 成功した場合、メッセージ通報の配列とnilを返します。
@@ -31,4 +64,13 @@ type MessageReportRepository interface {
 	// 存在しないユーザーを指定した場合は空配列とnilを返します。
 	// DBによるエラーを返すことがあります。
 	GetMessageReportsByReporterID(reporterID uuid.UUID) ([]*model.MessageReport, error)
+	// HandleMessageReport 指定したメッセージ通報を対応済みにします
+	//
+	// 成功した場合、nilを返します。対応者と対応日時が記録されます。
+	// 存在しなかった場合、ErrNotFoundを返します。
+	// 既に対応済みの場合、ErrForbiddenを返します。
+	// 引数にuuid.Nilを指定するとErrNilIDを返します。
+	// 引数に問題がある場合、ArgumentErrorを返します。
+	// DBによるエラーを返すことがあります。
+	HandleMessageReport(reportID uuid.UUID, args HandleMessageReportArgs) error
 }
@@ -2,8 +2,10 @@ package repository
 
 import (
 	"github.com/gofrs/uuid"
+	"github.com/jinzhu/gorm"
 	"github.com/traPtitech/traQ/model"
 	"github.com/traPtitech/traQ/utils/gormutil"
+	"time"
 )
 
 // CreateMessageReport implements MessageReportRepository interface.
@@ -19,6 +21,7 @@ func (repo *GormRepository) CreateMessageReport(messageID, reporterID uuid.UUID,
 		MessageID: messageID,
 		Reporter:  reporterID,
 		Reason:    reason,
+		Status:    model.MessageReportStatusOpen,
 	}
 	if err := repo.db.Create(r).Error; err != nil {
 		if gormutil.IsMySQLDuplicatedRecordErr(err) {
@@ -29,11 +32,67 @@ func (repo *GormRepository) CreateMessageReport(messageID, reporterID uuid.UUID,
 	return nil
 }
 
+// GetMessageReport implements MessageReportRepository interface.
+func (repo *GormRepository) GetMessageReport(reportID uuid.UUID) (*model.MessageReport, error) {
+	if reportID == uuid.Nil {
+		return nil, ErrNotFound
+	}
+	r := &model.MessageReport{}
+	if err := repo.db.Take(r, &model.MessageReport{ID: reportID}).Error; err != nil {
+		return nil, convertError(err)
+	}
+	return r, nil
+}
+
 // GetMessageReports implements MessageReportRepository interface.
-func (repo *GormRepository) GetMessageReports(offset, limit int) (arr []*model.MessageReport, err error) {
-	arr = make([]*model.MessageReport, 0)
-	err = repo.db.Scopes(gormutil.LimitAndOffset(limit, offset)).Order("created_at").Find(&arr).Error
-	return arr, err
+func (repo *GormRepository) GetMessageReports(query MessageReportsQuery) (reports []*model.MessageReport, more bool, err error) {
+	reports = make([]*model.MessageReport, 0)
+
+	tx := repo.db
+	if query.Asc {
+		tx = tx.Order("created_at")
+	} else {
+		tx = tx.Order("created_at DESC")
+	}
+
+	if query.Message != uuid.Nil {
+		tx = tx.Where("message_id = ?", query.Message)
+	}
+	if query.Reporter != uuid.Nil {
+		tx = tx.Where("reporter = ?", query.Reporter)
+	}
+	if len(query.Status) > 0 {
+		tx = tx.Where("status = ?", query.Status)
+	}
+
+	if query.Inclusive {
+		if query.Since.Valid {
+			tx = tx.Where("created_at >= ?", query.Since.Time)
+		}
+		if query.Until.Valid {
+			tx = tx.Where("created_at <= ?", query.Until.Time)
+		}
+	} else {
+		if query.Since.Valid {
+			tx = tx.Where("created_at > ?", query.Since.Time)
+		}
+		if query.Until.Valid {
+			tx = tx.Where("created_at < ?", query.Until.Time)
+		}
+	}
+
+	if query.Offset > 0 {
+		tx = tx.Offset(query.Offset)
+	}
+	if query.Limit > 0 {
+		err = tx.Limit(query.Limit + 1).Find(&reports).Error
+		if len(reports) > query.Limit {
+			return reports[:len(reports)-1], true, err
+		}
+	} else {
+		err = tx.Find(&reports).Error
+	}
+	return reports, false, err
 }
 
 // GetMessageReportsByMessageID implements MessageReportRepository interface.
@@ -55,3 +114,42 @@ func (repo *GormRepository) GetMessageReportsByReporterID(reporterID uuid.UUID)
 	err = repo.db.Where(&model.MessageReport{Reporter: reporterID}).Order("created_at").Find(&arr).Error
 	return arr, err
 }
+
+// HandleMessageReport implements MessageReportRepository interface.
+func (repo *GormRepository) HandleMessageReport(reportID uuid.UUID, args HandleMessageReportArgs) error {
+	if reportID == uuid.Nil || args.HandlerID == uuid.Nil {
+		return ErrNilID
+	}
+	switch args.Status {
+	case model.MessageReportStatusResolved:
+		switch args.Action {
+		case model.MessageReportActionNone, model.MessageReportActionHide, model.MessageReportActionDelete:
+		default:
+			return ArgError("args.Action", "invalid action")
+		}
+	case model.MessageReportStatusDismissed:
+		if args.Action != model.MessageReportActionNone {
+			return ArgError("args.Action", "dismissed report cannot have action")
+		}
+	default:
+		return ArgError("args.Status", "invalid status")
+	}
+
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		var r model.MessageReport
+		if err := tx.Set("gorm:query_option", "FOR UPDATE").First(&r, &model.MessageReport{ID: reportID}).Error; err != nil {
+			return convertError(err)
+		}
+		if !r.IsOpen() {
+			return ErrForbidden
+		}
+
+		return tx.Model(&r).Updates(map[string]interface{}{
+			"status":     args.Status,
+			"action":     args.Action,
+			"note":       args.Note,
+			"handler_id": args.HandlerID,
+			"handled_at": time.Now(),
+		}).Error
+	})
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/traPtitech/traQ/model"
+	"testing"
+)
+
+func TestRepositoryImpl_GetMessageReports(t *testing.T) {
+	t.Parallel()
+	repo, _, require, user, channel := setupWithUserAndChannel(t, common3)
+
+	m1 := mustMakeMessage(t, repo, user.GetID(), channel.ID)
+	m2 := mustMakeMessage(t, repo, user.GetID(), channel.ID)
+	require.NoError(repo.CreateMessageReport(m1.ID, user.GetID(), "a"))
+	require.NoError(repo.CreateMessageReport(m2.ID, user.GetID(), "b"))
+
+	t.Run("by message", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		reports, more, err := repo.GetMessageReports(MessageReportsQuery{Message: m1.ID})
+		if assert.NoError(err) && assert.Len(reports, 1) {
+			assert.False(more)
+			assert.Equal(m1.ID, reports[0].MessageID)
+			assert.Equal(model.MessageReportStatusOpen, reports[0].Status)
+		}
+	})
+
+	t.Run("by reporter with limit", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		reports, more, err := repo.GetMessageReports(MessageReportsQuery{Reporter: user.GetID(), Limit: 1, Asc: true})
+		if assert.NoError(err) && assert.Len(reports, 1) {
+			assert.True(more)
+			assert.Equal(m1.ID, reports[0].MessageID)
+		}
+	})
+}
+
+func TestRepositoryImpl_HandleMessageReport(t *testing.T) {
+	t.Parallel()
+	repo, _, require, user, channel := setupWithUserAndChannel(t, common3)
+
+	t.Run("nil id", func(t *testing.T) {
+		t.Parallel()
+
+		assert.EqualError(t, repo.HandleMessageReport(uuid.Nil, HandleMessageReportArgs{HandlerID: user.GetID()}), ErrNilID.Error())
+	})
+
+	t.Run("invalid args", func(t *testing.T) {
+		t.Parallel()
+
+		err := repo.HandleMessageReport(uuid.Must(uuid.NewV4()), HandleMessageReportArgs{
+			HandlerID: user.GetID(),
+			Status:    model.MessageReportStatusDismissed,
+			Action:    model.MessageReportActionDelete,
+		})
+		assert.True(t, IsArgError(err))
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+
+		err := repo.HandleMessageReport(uuid.Must(uuid.NewV4()), HandleMessageReportArgs{
+			HandlerID: user.GetID(),
+			Status:    model.MessageReportStatusResolved,
+			Action:    model.MessageReportActionNone,
+		})
+		assert.EqualError(t, err, ErrNotFound.Error())
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		m := mustMakeMessage(t, repo, user.GetID(), channel.ID)
+		require.NoError(repo.CreateMessageReport(m.ID, user.GetID(), "spam"))
+		reports, err := repo.GetMessageReportsByMessageID(m.ID)
+		require.NoError(err)
+		require.Len(reports, 1)
+
+		args := HandleMessageReportArgs{
+			HandlerID: user.GetID(),
+			Status:    model.MessageReportStatusResolved,
+			Action:    model.MessageReportActionHide,
+			Note:      "hidden",
+		}
+		if assert.NoError(repo.HandleMessageReport(reports[0].ID, args)) {
+			r, err := repo.GetMessageReport(reports[0].ID)
+			require.NoError(err)
+			assert.Equal(model.MessageReportStatusResolved, r.Status)
+			assert.Equal(model.MessageReportActionHide, r.Action)
+			assert.Equal("hidden", r.Note)
+			assert.Equal(user.GetID(), r.HandlerID.UUID)
+			assert.True(r.HandledAt.Valid)
+		}
+		assert.EqualError(repo.HandleMessageReport(reports[0].ID, args), ErrForbidden.Error())
+	})
+}
@@ -10,6 +10,7 @@ import (
 	model "github.com/traPtitech/traQ/model"
 	repository "github.com/traPtitech/traQ/repository"
 	reflect "reflect"
+	time "time"
 )
 
 // MockBotRepository is a mock of BotRepository interface
@@ -36,18 +37,18 @@ func (m *MockBotRepository) EXPECT() *MockBotRepositoryMockRecorder {
 }
 
 // CreateBot mocks base method
-func (m *MockBotRepository) CreateBot(name, displayName, description string, iconFileID, creatorID uuid.UUID, webhookURL string) (*model.Bot, error) {
+func (m *MockBotRepository) CreateBot(name, displayName, description string, iconFileID, creatorID uuid.UUID, mode model.BotMode, webhookURL string) (*model.Bot, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateBot", name, displayName, description, iconFileID, creatorID, webhookURL)
+	ret := m.ctrl.Call(m, "CreateBot", name, displayName, description, iconFileID, creatorID, mode, webhookURL)
 	ret0, _ := ret[0].(*model.Bot)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateBot indicates an expected call of CreateBot
-func (mr *MockBotRepositoryMockRecorder) CreateBot(name, displayName, description, iconFileID, creatorID, webhookURL interface{}) *gomock.Call {
+func (mr *MockBotRepositoryMockRecorder) CreateBot(name, displayName, description, iconFileID, creatorID, mode, webhookURL interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBot", reflect.TypeOf((*MockBotRepository)(nil).CreateBot), name, displayName, description, iconFileID, creatorID, webhookURL)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBot", reflect.TypeOf((*MockBotRepository)(nil).CreateBot), name, displayName, description, iconFileID, creatorID, mode, webhookURL)
 }
 
 // UpdateBot mocks base method
@@ -153,6 +154,21 @@ func (mr *MockBotRepositoryMockRecorder) ReissueBotTokens(id interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReissueBotTokens", reflect.TypeOf((*MockBotRepository)(nil).ReissueBotTokens), id)
 }
 
+// RotateBotSigningSecret mocks base method
+func (m *MockBotRepository) RotateBotSigningSecret(id uuid.UUID, gracePeriod time.Duration) (*model.Bot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateBotSigningSecret", id, gracePeriod)
+	ret0, _ := ret[0].(*model.Bot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RotateBotSigningSecret indicates an expected call of RotateBotSigningSecret
+func (mr *MockBotRepositoryMockRecorder) RotateBotSigningSecret(id, gracePeriod interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateBotSigningSecret", reflect.TypeOf((*MockBotRepository)(nil).RotateBotSigningSecret), id, gracePeriod)
+}
+
 // DeleteBot mocks base method
 func (m *MockBotRepository) DeleteBot(id uuid.UUID) error {
 	m.ctrl.T.Helper()
@@ -238,3 +254,165 @@ func (mr *MockBotRepositoryMockRecorder) GetBotEventLogs(botID, limit, offset in
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBotEventLogs", reflect.TypeOf((*MockBotRepository)(nil).GetBotEventLogs), botID, limit, offset)
 }
+
+// EnqueueBotEvents mocks base method
+func (m *MockBotRepository) EnqueueBotEvents(items []*model.BotEventQueueItem) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnqueueBotEvents", items)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnqueueBotEvents indicates an expected call of EnqueueBotEvents
+func (mr *MockBotRepositoryMockRecorder) EnqueueBotEvents(items interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueBotEvents", reflect.TypeOf((*MockBotRepository)(nil).EnqueueBotEvents), items)
+}
+
+// GetBotIDsWithQueuedEvents mocks base method
+func (m *MockBotRepository) GetBotIDsWithQueuedEvents() ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBotIDsWithQueuedEvents")
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBotIDsWithQueuedEvents indicates an expected call of GetBotIDsWithQueuedEvents
+func (mr *MockBotRepositoryMockRecorder) GetBotIDsWithQueuedEvents() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBotIDsWithQueuedEvents", reflect.TypeOf((*MockBotRepository)(nil).GetBotIDsWithQueuedEvents))
+}
+
+// LeaseBotEventQueueHead mocks base method
+func (m *MockBotRepository) LeaseBotEventQueueHead(botID uuid.UUID, until time.Time) (*model.BotEventQueueItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LeaseBotEventQueueHead", botID, until)
+	ret0, _ := ret[0].(*model.BotEventQueueItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LeaseBotEventQueueHead indicates an expected call of LeaseBotEventQueueHead
+func (mr *MockBotRepositoryMockRecorder) LeaseBotEventQueueHead(botID, until interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LeaseBotEventQueueHead", reflect.TypeOf((*MockBotRepository)(nil).LeaseBotEventQueueHead), botID, until)
+}
+
+// DeleteBotEventQueueItem mocks base method
+func (m *MockBotRepository) DeleteBotEventQueueItem(id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBotEventQueueItem", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteBotEventQueueItem indicates an expected call of DeleteBotEventQueueItem
+func (mr *MockBotRepositoryMockRecorder) DeleteBotEventQueueItem(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBotEventQueueItem", reflect.TypeOf((*MockBotRepository)(nil).DeleteBotEventQueueItem), id)
+}
+
+// UpdateBotEventQueueItemAttempts mocks base method
+func (m *MockBotRepository) UpdateBotEventQueueItemAttempts(id uuid.UUID, attempts int, nextAttemptAt time.Time, dead bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBotEventQueueItemAttempts", id, attempts, nextAttemptAt, dead)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateBotEventQueueItemAttempts indicates an expected call of UpdateBotEventQueueItemAttempts
+func (mr *MockBotRepositoryMockRecorder) UpdateBotEventQueueItemAttempts(id, attempts, nextAttemptAt, dead interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBotEventQueueItemAttempts", reflect.TypeOf((*MockBotRepository)(nil).UpdateBotEventQueueItemAttempts), id, attempts, nextAttemptAt, dead)
+}
+
+// GetDeadBotEvents mocks base method
+func (m *MockBotRepository) GetDeadBotEvents(botID uuid.UUID, limit, offset int) ([]*model.BotEventQueueItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeadBotEvents", botID, limit, offset)
+	ret0, _ := ret[0].([]*model.BotEventQueueItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeadBotEvents indicates an expected call of GetDeadBotEvents
+func (mr *MockBotRepositoryMockRecorder) GetDeadBotEvents(botID, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeadBotEvents", reflect.TypeOf((*MockBotRepository)(nil).GetDeadBotEvents), botID, limit, offset)
+}
+
+// ReplayDeadBotEvents mocks base method
+func (m *MockBotRepository) ReplayDeadBotEvents(botID uuid.UUID, ids []uuid.UUID) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplayDeadBotEvents", botID, ids)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReplayDeadBotEvents indicates an expected call of ReplayDeadBotEvents
+func (mr *MockBotRepositoryMockRecorder) ReplayDeadBotEvents(botID, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplayDeadBotEvents", reflect.TypeOf((*MockBotRepository)(nil).ReplayDeadBotEvents), botID, ids)
+}
+
+// SetBotCommands mocks base method
+func (m *MockBotRepository) SetBotCommands(botID uuid.UUID, commands []*model.BotCommand) ([]*model.BotCommand, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetBotCommands", botID, commands)
+	ret0, _ := ret[0].([]*model.BotCommand)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetBotCommands indicates an expected call of SetBotCommands
+func (mr *MockBotRepositoryMockRecorder) SetBotCommands(botID, commands interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBotCommands", reflect.TypeOf((*MockBotRepository)(nil).SetBotCommands), botID, commands)
+}
+
+// GetBotCommands mocks base method
+func (m *MockBotRepository) GetBotCommands(botID uuid.UUID) ([]*model.BotCommand, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBotCommands", botID)
+	ret0, _ := ret[0].([]*model.BotCommand)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBotCommands indicates an expected call of GetBotCommands
+func (mr *MockBotRepositoryMockRecorder) GetBotCommands(botID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBotCommands", reflect.TypeOf((*MockBotRepository)(nil).GetBotCommands), botID)
+}
+
+// GetAllBotCommands mocks base method
+func (m *MockBotRepository) GetAllBotCommands() ([]*model.BotCommand, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllBotCommands")
+	ret0, _ := ret[0].([]*model.BotCommand)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllBotCommands indicates an expected call of GetAllBotCommands
+func (mr *MockBotRepositoryMockRecorder) GetAllBotCommands() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllBotCommands", reflect.TypeOf((*MockBotRepository)(nil).GetAllBotCommands))
+}
+
+// GetBotCommandsByName mocks base method
+func (m *MockBotRepository) GetBotCommandsByName(name string) ([]*model.BotCommand, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBotCommandsByName", name)
+	ret0, _ := ret[0].([]*model.BotCommand)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBotCommandsByName indicates an expected call of GetBotCommandsByName
+func (mr *MockBotRepositoryMockRecorder) GetBotCommandsByName(name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBotCommandsByName", reflect.TypeOf((*MockBotRepository)(nil).GetBotCommandsByName), name)
+}
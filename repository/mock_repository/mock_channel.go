@@ -82,6 +82,34 @@ func (mr *MockChannelRepositoryMockRecorder) UpdateChannel(channelID, args inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateChannel", reflect.TypeOf((*MockChannelRepository)(nil).UpdateChannel), channelID, args)
 }
 
+// MergeChannel mocks base method
+func (m *MockChannelRepository) MergeChannel(fromID, toID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeChannel", fromID, toID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MergeChannel indicates an expected call of MergeChannel
+func (mr *MockChannelRepositoryMockRecorder) MergeChannel(fromID, toID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeChannel", reflect.TypeOf((*MockChannelRepository)(nil).MergeChannel), fromID, toID)
+}
+
+// DeleteChannel mocks base method
+func (m *MockChannelRepository) DeleteChannel(channelID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteChannel", channelID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteChannel indicates an expected call of DeleteChannel
+func (mr *MockChannelRepositoryMockRecorder) DeleteChannel(channelID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteChannel", reflect.TypeOf((*MockChannelRepository)(nil).DeleteChannel), channelID)
+}
+
 // GetChannel mocks base method
 func (m *MockChannelRepository) GetChannel(channelID uuid.UUID) (*model.Channel, error) {
 	m.ctrl.T.Helper()
@@ -142,6 +170,49 @@ func (mr *MockChannelRepositoryMockRecorder) GetPrivateChannelMemberIDs(channelI
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPrivateChannelMemberIDs", reflect.TypeOf((*MockChannelRepository)(nil).GetPrivateChannelMemberIDs), channelID)
 }
 
+// GetPrivateChannelsByUser mocks base method
+func (m *MockChannelRepository) GetPrivateChannelsByUser(userID uuid.UUID) ([]*model.Channel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPrivateChannelsByUser", userID)
+	ret0, _ := ret[0].([]*model.Channel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPrivateChannelsByUser indicates an expected call of GetPrivateChannelsByUser
+func (mr *MockChannelRepositoryMockRecorder) GetPrivateChannelsByUser(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPrivateChannelsByUser", reflect.TypeOf((*MockChannelRepository)(nil).GetPrivateChannelsByUser), userID)
+}
+
+// AddPrivateChannelMember mocks base method
+func (m *MockChannelRepository) AddPrivateChannelMember(channelID, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddPrivateChannelMember", channelID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddPrivateChannelMember indicates an expected call of AddPrivateChannelMember
+func (mr *MockChannelRepositoryMockRecorder) AddPrivateChannelMember(channelID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddPrivateChannelMember", reflect.TypeOf((*MockChannelRepository)(nil).AddPrivateChannelMember), channelID, userID)
+}
+
+// RemovePrivateChannelMember mocks base method
+func (m *MockChannelRepository) RemovePrivateChannelMember(channelID, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemovePrivateChannelMember", channelID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemovePrivateChannelMember indicates an expected call of RemovePrivateChannelMember
+func (mr *MockChannelRepositoryMockRecorder) RemovePrivateChannelMember(channelID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemovePrivateChannelMember", reflect.TypeOf((*MockChannelRepository)(nil).RemovePrivateChannelMember), channelID, userID)
+}
+
 // ChangeChannelSubscription mocks base method
 func (m *MockChannelRepository) ChangeChannelSubscription(channelID uuid.UUID, args repository.ChangeChannelSubscriptionArgs) ([]uuid.UUID, []uuid.UUID, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,124 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: outgoing_webhook.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	uuid "github.com/gofrs/uuid"
+	gomock "github.com/golang/mock/gomock"
+	model "github.com/traPtitech/traQ/model"
+	repository "github.com/traPtitech/traQ/repository"
+	reflect "reflect"
+)
+
+// MockOutgoingWebhookRepository is a mock of OutgoingWebhookRepository interface
+type MockOutgoingWebhookRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockOutgoingWebhookRepositoryMockRecorder
+}
+
+// MockOutgoingWebhookRepositoryMockRecorder is the mock recorder for MockOutgoingWebhookRepository
+type MockOutgoingWebhookRepositoryMockRecorder struct {
+	mock *MockOutgoingWebhookRepository
+}
+
+// NewMockOutgoingWebhookRepository creates a new mock instance
+func NewMockOutgoingWebhookRepository(ctrl *gomock.Controller) *MockOutgoingWebhookRepository {
+	mock := &MockOutgoingWebhookRepository{ctrl: ctrl}
+	mock.recorder = &MockOutgoingWebhookRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockOutgoingWebhookRepository) EXPECT() *MockOutgoingWebhookRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateOutgoingWebhook mocks base method
+func (m *MockOutgoingWebhookRepository) CreateOutgoingWebhook(webhookID, channelID uuid.UUID, triggerType model.OutgoingWebhookTriggerType, trigger, url string, creatorID uuid.UUID) (*model.OutgoingWebhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOutgoingWebhook", webhookID, channelID, triggerType, trigger, url, creatorID)
+	ret0, _ := ret[0].(*model.OutgoingWebhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOutgoingWebhook indicates an expected call of CreateOutgoingWebhook
+func (mr *MockOutgoingWebhookRepositoryMockRecorder) CreateOutgoingWebhook(webhookID, channelID, triggerType, trigger, url, creatorID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOutgoingWebhook", reflect.TypeOf((*MockOutgoingWebhookRepository)(nil).CreateOutgoingWebhook), webhookID, channelID, triggerType, trigger, url, creatorID)
+}
+
+// UpdateOutgoingWebhook mocks base method
+func (m *MockOutgoingWebhookRepository) UpdateOutgoingWebhook(id uuid.UUID, args repository.UpdateOutgoingWebhookArgs) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOutgoingWebhook", id, args)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateOutgoingWebhook indicates an expected call of UpdateOutgoingWebhook
+func (mr *MockOutgoingWebhookRepositoryMockRecorder) UpdateOutgoingWebhook(id, args interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOutgoingWebhook", reflect.TypeOf((*MockOutgoingWebhookRepository)(nil).UpdateOutgoingWebhook), id, args)
+}
+
+// DeleteOutgoingWebhook mocks base method
+func (m *MockOutgoingWebhookRepository) DeleteOutgoingWebhook(id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOutgoingWebhook", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOutgoingWebhook indicates an expected call of DeleteOutgoingWebhook
+func (mr *MockOutgoingWebhookRepositoryMockRecorder) DeleteOutgoingWebhook(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOutgoingWebhook", reflect.TypeOf((*MockOutgoingWebhookRepository)(nil).DeleteOutgoingWebhook), id)
+}
+
+// GetOutgoingWebhook mocks base method
+func (m *MockOutgoingWebhookRepository) GetOutgoingWebhook(id uuid.UUID) (*model.OutgoingWebhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOutgoingWebhook", id)
+	ret0, _ := ret[0].(*model.OutgoingWebhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOutgoingWebhook indicates an expected call of GetOutgoingWebhook
+func (mr *MockOutgoingWebhookRepositoryMockRecorder) GetOutgoingWebhook(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOutgoingWebhook", reflect.TypeOf((*MockOutgoingWebhookRepository)(nil).GetOutgoingWebhook), id)
+}
+
+// GetOutgoingWebhooksByWebhookID mocks base method
+func (m *MockOutgoingWebhookRepository) GetOutgoingWebhooksByWebhookID(webhookID uuid.UUID) ([]*model.OutgoingWebhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOutgoingWebhooksByWebhookID", webhookID)
+	ret0, _ := ret[0].([]*model.OutgoingWebhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOutgoingWebhooksByWebhookID indicates an expected call of GetOutgoingWebhooksByWebhookID
+func (mr *MockOutgoingWebhookRepositoryMockRecorder) GetOutgoingWebhooksByWebhookID(webhookID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOutgoingWebhooksByWebhookID", reflect.TypeOf((*MockOutgoingWebhookRepository)(nil).GetOutgoingWebhooksByWebhookID), webhookID)
+}
+
+// GetOutgoingWebhooksByChannelID mocks base method
+func (m *MockOutgoingWebhookRepository) GetOutgoingWebhooksByChannelID(channelID uuid.UUID) ([]*model.OutgoingWebhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOutgoingWebhooksByChannelID", channelID)
+	ret0, _ := ret[0].([]*model.OutgoingWebhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOutgoingWebhooksByChannelID indicates an expected call of GetOutgoingWebhooksByChannelID
+func (mr *MockOutgoingWebhookRepositoryMockRecorder) GetOutgoingWebhooksByChannelID(channelID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOutgoingWebhooksByChannelID", reflect.TypeOf((*MockOutgoingWebhookRepository)(nil).GetOutgoingWebhooksByChannelID), channelID)
+}
@@ -0,0 +1,78 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: rate_limit.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	uuid "github.com/gofrs/uuid"
+	gomock "github.com/golang/mock/gomock"
+	model "github.com/traPtitech/traQ/model"
+	reflect "reflect"
+)
+
+// MockRateLimitRepository is a mock of RateLimitRepository interface
+type MockRateLimitRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRateLimitRepositoryMockRecorder
+}
+
+// MockRateLimitRepositoryMockRecorder is the mock recorder for MockRateLimitRepository
+type MockRateLimitRepositoryMockRecorder struct {
+	mock *MockRateLimitRepository
+}
+
+// NewMockRateLimitRepository creates a new mock instance
+func NewMockRateLimitRepository(ctrl *gomock.Controller) *MockRateLimitRepository {
+	mock := &MockRateLimitRepository{ctrl: ctrl}
+	mock.recorder = &MockRateLimitRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockRateLimitRepository) EXPECT() *MockRateLimitRepositoryMockRecorder {
+	return m.recorder
+}
+
+// SetRateLimitOverride mocks base method
+func (m *MockRateLimitRepository) SetRateLimitOverride(userID uuid.UUID, action string, rate float64, burst int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRateLimitOverride", userID, action, rate, burst)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetRateLimitOverride indicates an expected call of SetRateLimitOverride
+func (mr *MockRateLimitRepositoryMockRecorder) SetRateLimitOverride(userID, action, rate, burst interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRateLimitOverride", reflect.TypeOf((*MockRateLimitRepository)(nil).SetRateLimitOverride), userID, action, rate, burst)
+}
+
+// DeleteRateLimitOverride mocks base method
+func (m *MockRateLimitRepository) DeleteRateLimitOverride(userID uuid.UUID, action string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRateLimitOverride", userID, action)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRateLimitOverride indicates an expected call of DeleteRateLimitOverride
+func (mr *MockRateLimitRepositoryMockRecorder) DeleteRateLimitOverride(userID, action interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRateLimitOverride", reflect.TypeOf((*MockRateLimitRepository)(nil).DeleteRateLimitOverride), userID, action)
+}
+
+// GetRateLimitOverrides mocks base method
+func (m *MockRateLimitRepository) GetRateLimitOverrides() ([]*model.RateLimitOverride, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRateLimitOverrides")
+	ret0, _ := ret[0].([]*model.RateLimitOverride)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRateLimitOverrides indicates an expected call of GetRateLimitOverrides
+func (mr *MockRateLimitRepositoryMockRecorder) GetRateLimitOverrides() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRateLimitOverrides", reflect.TypeOf((*MockRateLimitRepository)(nil).GetRateLimitOverrides))
+}
@@ -0,0 +1,58 @@
+//go:generate mockgen -source=$GOFILE -destination=mock_$GOPACKAGE/mock_$GOFILE
+package repository
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/utils/optional"
+)
+
+// UpdateOutgoingWebhookArgs Outgoing Webhook更新引数
+type UpdateOutgoingWebhookArgs struct {
+	ChannelID   optional.UUID
+	TriggerType optional.String
+	Trigger     optional.String
+	URL         optional.String
+}
+
+// OutgoingWebhookRepository Outgoing Webhookリポジトリ
+type OutgoingWebhookRepository interface {
+	// CreateOutgoingWebhook Outgoing Webhookを作成します
+	//
+	// 成功した場合、Outgoing Webhookとnilを返します。署名用のシークレットは自動生成されます。
+	// 引数に問題がある場合、ArgumentErrorを返します。
+	// 引数にuuid.Nilを指定するとErrNilIDを返します。
+	// DBによるエラーを返すことがあります。
+	CreateOutgoingWebhook(webhookID, channelID uuid.UUID, triggerType model.OutgoingWebhookTriggerType, trigger, url string, creatorID uuid.UUID) (*model.OutgoingWebhook, error)
+	// UpdateOutgoingWebhook 指定したOutgoing Webhookを更新します
+	//
+	// 成功した場合、nilを返します。
+	// 存在しないOutgoing Webhookを指定した場合、ErrNotFoundを返します。
+	// 更新内容に問題がある場合、ArgumentErrorを返します。
+	// 引数にuuid.Nilを指定するとErrNilIDを返します。
+	// DBによるエラーを返すことがあります。
+	UpdateOutgoingWebhook(id uuid.UUID, args UpdateOutgoingWebhookArgs) error
+	// DeleteOutgoingWebhook 指定したOutgoing Webhookを削除します
+	//
+	// 成功した場合、nilを返します。
+	// 存在しないOutgoing Webhookを指定した場合、ErrNotFoundを返します。
+	// 引数にuuid.Nilを指定するとErrNilIDを返します。
+	// DBによるエラーを返すことがあります。
+	DeleteOutgoingWebhook(id uuid.UUID) error
+	// GetOutgoingWebhook 指定したOutgoing Webhookを取得します
+	//
+	// 成功した場合、Outgoing Webhookとnilを返します。
+	// 存在しないOutgoing Webhookを指定した場合、ErrNotFoundを返します。
+	// DBによるエラーを返すことがあります。
+	GetOutgoingWebhook(id uuid.UUID) (*model.OutgoingWebhook, error)
+	// GetOutgoingWebhooksByWebhookID 指定したWebhookのOutgoing Webhookを全て取得します
+	//
+	// 成功した場合、作成日時の昇順でOutgoing Webhookの配列とnilを返します。
+	// DBによるエラーを返すことがあります。
+	GetOutgoingWebhooksByWebhookID(webhookID uuid.UUID) ([]*model.OutgoingWebhook, error)
+	// GetOutgoingWebhooksByChannelID 指定したチャンネルのOutgoing Webhookを全て取得します
+	//
+	// 成功した場合、作成日時の昇順でOutgoing Webhookの配列とnilを返します。
+	// DBによるエラーを返すことがあります。
+	GetOutgoingWebhooksByChannelID(channelID uuid.UUID) ([]*model.OutgoingWebhook, error)
+}
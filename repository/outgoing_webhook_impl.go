@@ -0,0 +1,182 @@
+package repository
+
+import (
+	vd "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/gofrs/uuid"
+	"github.com/jinzhu/gorm"
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/utils/random"
+	"github.com/traPtitech/traQ/utils/validator"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// CreateOutgoingWebhook implements OutgoingWebhookRepository interface.
+func (repo *GormRepository) CreateOutgoingWebhook(webhookID, channelID uuid.UUID, triggerType model.OutgoingWebhookTriggerType, trigger, url string, creatorID uuid.UUID) (*model.OutgoingWebhook, error) {
+	if webhookID == uuid.Nil || channelID == uuid.Nil || creatorID == uuid.Nil {
+		return nil, ErrNilID
+	}
+	if err := validateOutgoingWebhookTrigger(triggerType, trigger); err != nil {
+		return nil, err
+	}
+	if err := validateOutgoingWebhookURL(url); err != nil {
+		return nil, err
+	}
+
+	w := &model.OutgoingWebhook{
+		ID:          uuid.Must(uuid.NewV4()),
+		WebhookID:   webhookID,
+		ChannelID:   channelID,
+		TriggerType: triggerType,
+		Trigger:     trigger,
+		URL:         url,
+		Secret:      random.SecureAlphaNumeric(64),
+		CreatorID:   creatorID,
+	}
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&model.WebhookBot{}, &model.WebhookBot{ID: webhookID}).Error; err != nil {
+			if gorm.IsRecordNotFoundError(err) {
+				return ArgError("webhookID", "the Webhook is not found")
+			}
+			return err
+		}
+		if err := validateOutgoingWebhookChannel(tx, channelID); err != nil {
+			return err
+		}
+		return tx.Create(w).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// UpdateOutgoingWebhook implements OutgoingWebhookRepository interface.
+func (repo *GormRepository) UpdateOutgoingWebhook(id uuid.UUID, args UpdateOutgoingWebhookArgs) error {
+	if id == uuid.Nil {
+		return ErrNilID
+	}
+
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		var w model.OutgoingWebhook
+		if err := tx.First(&w, &model.OutgoingWebhook{ID: id}).Error; err != nil {
+			return convertError(err)
+		}
+
+		changes := map[string]interface{}{}
+		if args.ChannelID.Valid {
+			if err := validateOutgoingWebhookChannel(tx, args.ChannelID.UUID); err != nil {
+				return err
+			}
+			changes["channel_id"] = args.ChannelID.UUID
+		}
+		if args.TriggerType.Valid || args.Trigger.Valid {
+			triggerType, trigger := w.TriggerType, w.Trigger
+			if args.TriggerType.Valid {
+				triggerType = model.OutgoingWebhookTriggerType(args.TriggerType.String)
+			}
+			if args.Trigger.Valid {
+				trigger = args.Trigger.String
+			}
+			if err := validateOutgoingWebhookTrigger(triggerType, trigger); err != nil {
+				return err
+			}
+			changes["trigger_type"] = triggerType
+			changes["trigger"] = trigger
+		}
+		if args.URL.Valid {
+			if err := validateOutgoingWebhookURL(args.URL.String); err != nil {
+				return err
+			}
+			changes["url"] = args.URL.String
+		}
+
+		if len(changes) > 0 {
+			return tx.Model(&w).Updates(changes).Error
+		}
+		return nil
+	})
+}
+
+// DeleteOutgoingWebhook implements OutgoingWebhookRepository interface.
+func (repo *GormRepository) DeleteOutgoingWebhook(id uuid.UUID) error {
+	if id == uuid.Nil {
+		return ErrNilID
+	}
+	result := repo.db.Delete(&model.OutgoingWebhook{ID: id})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// GetOutgoingWebhook implements OutgoingWebhookRepository interface.
+func (repo *GormRepository) GetOutgoingWebhook(id uuid.UUID) (*model.OutgoingWebhook, error) {
+	if id == uuid.Nil {
+		return nil, ErrNotFound
+	}
+	w := &model.OutgoingWebhook{}
+	if err := repo.db.Take(w, &model.OutgoingWebhook{ID: id}).Error; err != nil {
+		return nil, convertError(err)
+	}
+	return w, nil
+}
+
+// GetOutgoingWebhooksByWebhookID implements OutgoingWebhookRepository interface.
+func (repo *GormRepository) GetOutgoingWebhooksByWebhookID(webhookID uuid.UUID) ([]*model.OutgoingWebhook, error) {
+	hooks := make([]*model.OutgoingWebhook, 0)
+	if webhookID == uuid.Nil {
+		return hooks, nil
+	}
+	return hooks, repo.db.Where(&model.OutgoingWebhook{WebhookID: webhookID}).Order("created_at").Find(&hooks).Error
+}
+
+// GetOutgoingWebhooksByChannelID implements OutgoingWebhookRepository interface.
+func (repo *GormRepository) GetOutgoingWebhooksByChannelID(channelID uuid.UUID) ([]*model.OutgoingWebhook, error) {
+	hooks := make([]*model.OutgoingWebhook, 0)
+	if channelID == uuid.Nil {
+		return hooks, nil
+	}
+	return hooks, repo.db.Where(&model.OutgoingWebhook{ChannelID: channelID}).Order("created_at").Find(&hooks).Error
+}
+
+func validateOutgoingWebhookChannel(tx *gorm.DB, channelID uuid.UUID) error {
+	var ch model.Channel
+	if err := tx.First(&ch, &model.Channel{ID: channelID}).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return ArgError("channelID", "the Channel is not found")
+		}
+		return err
+	}
+	if !ch.IsPublic {
+		return ArgError("channelID", "private channels are not allowed")
+	}
+	return nil
+}
+
+func validateOutgoingWebhookTrigger(triggerType model.OutgoingWebhookTriggerType, trigger string) error {
+	if !triggerType.Valid() {
+		return ArgError("triggerType", "invalid triggerType")
+	}
+	if len(trigger) == 0 || utf8.RuneCountInString(trigger) > 200 {
+		return ArgError("trigger", "Trigger must be non-empty and shorter than 201 characters")
+	}
+	if triggerType == model.OutgoingWebhookTriggerRegex {
+		if _, err := regexp.Compile(trigger); err != nil {
+			return ArgError("trigger", "invalid regular expression")
+		}
+	}
+	return nil
+}
+
+func validateOutgoingWebhookURL(url string) error {
+	if err := vd.Validate(url, vd.Required, is.URL, validator.NotInternalURL); err != nil || !strings.HasPrefix(url, "http") {
+		return ArgError("url", "invalid url")
+	}
+	return nil
+}
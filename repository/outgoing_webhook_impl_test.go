@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/utils/optional"
+	"testing"
+)
+
+func mustMakeOutgoingWebhook(t *testing.T, repo Repository, webhookID, channelID, creatorID uuid.UUID) *model.OutgoingWebhook {
+	t.Helper()
+	w, err := repo.CreateOutgoingWebhook(webhookID, channelID, model.OutgoingWebhookTriggerKeyword, "!ping", "https://example.com/hook", creatorID)
+	require.NoError(t, err)
+	return w
+}
+
+func TestRepositoryImpl_CreateOutgoingWebhook(t *testing.T) {
+	t.Parallel()
+	repo, _, _, user, channel := setupWithUserAndChannel(t, common3)
+	wh := mustMakeWebhook(t, repo, rand, channel.ID, user.GetID(), "")
+
+	t.Run("nil id", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := repo.CreateOutgoingWebhook(uuid.Nil, channel.ID, model.OutgoingWebhookTriggerKeyword, "a", "https://example.com", user.GetID())
+		assert.EqualError(t, err, ErrNilID.Error())
+	})
+
+	t.Run("invalid trigger", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := repo.CreateOutgoingWebhook(wh.GetID(), channel.ID, model.OutgoingWebhookTriggerRegex, "(", "https://example.com", user.GetID())
+		assert.True(t, IsArgError(err))
+		_, err = repo.CreateOutgoingWebhook(wh.GetID(), channel.ID, "prefix", "a", "https://example.com", user.GetID())
+		assert.True(t, IsArgError(err))
+	})
+
+	t.Run("invalid url", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := repo.CreateOutgoingWebhook(wh.GetID(), channel.ID, model.OutgoingWebhookTriggerKeyword, "a", "http://localhost/hook", user.GetID())
+		assert.True(t, IsArgError(err))
+	})
+
+	t.Run("unknown webhook", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := repo.CreateOutgoingWebhook(uuid.Must(uuid.NewV4()), channel.ID, model.OutgoingWebhookTriggerKeyword, "a", "https://example.com", user.GetID())
+		assert.True(t, IsArgError(err))
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		w, err := repo.CreateOutgoingWebhook(wh.GetID(), channel.ID, model.OutgoingWebhookTriggerRegex, `^!deploy\s`, "https://example.com/deploy", user.GetID())
+		if assert.NoError(err) {
+			assert.NotEqual(uuid.Nil, w.ID)
+			assert.Equal(wh.GetID(), w.WebhookID)
+			assert.Equal(channel.ID, w.ChannelID)
+			assert.Len(w.Secret, 64)
+		}
+	})
+}
+
+func TestRepositoryImpl_UpdateOutgoingWebhook(t *testing.T) {
+	t.Parallel()
+	repo, _, _, user, channel := setupWithUserAndChannel(t, common3)
+	wh := mustMakeWebhook(t, repo, rand, channel.ID, user.GetID(), "")
+
+	t.Run("nil id", func(t *testing.T) {
+		t.Parallel()
+
+		assert.EqualError(t, repo.UpdateOutgoingWebhook(uuid.Nil, UpdateOutgoingWebhookArgs{}), ErrNilID.Error())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+
+		assert.EqualError(t, repo.UpdateOutgoingWebhook(uuid.Must(uuid.NewV4()), UpdateOutgoingWebhookArgs{}), ErrNotFound.Error())
+	})
+
+	t.Run("invalid regex", func(t *testing.T) {
+		t.Parallel()
+
+		w := mustMakeOutgoingWebhook(t, repo, wh.GetID(), channel.ID, user.GetID())
+		assert.True(t, IsArgError(repo.UpdateOutgoingWebhook(w.ID, UpdateOutgoingWebhookArgs{TriggerType: optional.StringFrom(model.OutgoingWebhookTriggerRegex.String()), Trigger: optional.StringFrom("[")})))
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		w := mustMakeOutgoingWebhook(t, repo, wh.GetID(), channel.ID, user.GetID())
+		if assert.NoError(repo.UpdateOutgoingWebhook(w.ID, UpdateOutgoingWebhookArgs{Trigger: optional.StringFrom("!pong"), URL: optional.StringFrom("https://example.com/pong")})) {
+			w, err := repo.GetOutgoingWebhook(w.ID)
+			require.NoError(t, err)
+			assert.Equal(model.OutgoingWebhookTriggerKeyword, w.TriggerType)
+			assert.Equal("!pong", w.Trigger)
+			assert.Equal("https://example.com/pong", w.URL)
+		}
+	})
+}
+
+func TestRepositoryImpl_DeleteOutgoingWebhook(t *testing.T) {
+	t.Parallel()
+	repo, _, _, user, channel := setupWithUserAndChannel(t, common3)
+	wh := mustMakeWebhook(t, repo, rand, channel.ID, user.GetID(), "")
+
+	t.Run("nil id", func(t *testing.T) {
+		t.Parallel()
+
+		assert.EqualError(t, repo.DeleteOutgoingWebhook(uuid.Nil), ErrNilID.Error())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+
+		assert.EqualError(t, repo.DeleteOutgoingWebhook(uuid.Must(uuid.NewV4())), ErrNotFound.Error())
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		w := mustMakeOutgoingWebhook(t, repo, wh.GetID(), channel.ID, user.GetID())
+		if assert.NoError(t, repo.DeleteOutgoingWebhook(w.ID)) {
+			_, err := repo.GetOutgoingWebhook(w.ID)
+			assert.EqualError(t, err, ErrNotFound.Error())
+		}
+	})
+}
+
+func TestRepositoryImpl_GetOutgoingWebhooks(t *testing.T) {
+	t.Parallel()
+	repo, _, _, user, channel := setupWithUserAndChannel(t, common3)
+	wh := mustMakeWebhook(t, repo, rand, channel.ID, user.GetID(), "")
+	ch := mustMakeChannel(t, repo, rand)
+
+	w1 := mustMakeOutgoingWebhook(t, repo, wh.GetID(), channel.ID, user.GetID())
+	w2 := mustMakeOutgoingWebhook(t, repo, wh.GetID(), ch.ID, user.GetID())
+
+	t.Run("by webhook", func(t *testing.T) {
+		t.Parallel()
+
+		hooks, err := repo.GetOutgoingWebhooksByWebhookID(wh.GetID())
+		if assert.NoError(t, err) && assert.Len(t, hooks, 2) {
+			assert.Equal(t, w1.ID, hooks[0].ID)
+			assert.Equal(t, w2.ID, hooks[1].ID)
+		}
+	})
+
+	t.Run("by channel", func(t *testing.T) {
+		t.Parallel()
+
+		hooks, err := repo.GetOutgoingWebhooksByChannelID(ch.ID)
+		if assert.NoError(t, err) && assert.Len(t, hooks, 1) {
+			assert.Equal(t, w2.ID, hooks[0].ID)
+		}
+	})
+}
@@ -0,0 +1,31 @@
+//go:generate mockgen -source=$GOFILE -destination=mock_$GOPACKAGE/mock_$GOFILE
+package repository
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/traPtitech/traQ/model"
+)
+
+// RateLimitRepository レート制限リポジトリ
+type RateLimitRepository interface {
+	// SetRateLimitOverride 指定したユーザーの指定した操作のレート制限を上書きします
+	//
+	// 成功した場合、nilを返します。既に上書き設定が存在する場合は更新します。
+	// 引数にuuid.Nilを指定するとErrNilIDを返します。
+	// actionが空の場合、ArgumentErrorを返します。
+	// DBによるエラーを返すことがあります。
+	SetRateLimitOverride(userID uuid.UUID, action string, rate float64, burst int) error
+	// DeleteRateLimitOverride 指定したユーザーの指定した操作のレート制限の上書き設定を削除します
+	//
+	// 成功した場合、nilを返します。
+	// 上書き設定が存在しなかった場合、ErrNotFoundを返します。
+	// 引数にuuid.Nilを指定するとErrNilIDを返します。
+	// actionが空の場合、ArgumentErrorを返します。
+	// DBによるエラーを返すことがあります。
+	DeleteRateLimitOverride(userID uuid.UUID, action string) error
+	// GetRateLimitOverrides 全てのレート制限の上書き設定を取得します
+	//
+	// 成功した場合、上書き設定の配列とnilを返します。
+	// DBによるエラーを返すことがあります。
+	GetRateLimitOverrides() ([]*model.RateLimitOverride, error)
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/traPtitech/traQ/model"
+)
+
+// SetRateLimitOverride implements RateLimitRepository interface.
+func (repo *GormRepository) SetRateLimitOverride(userID uuid.UUID, action string, rate float64, burst int) error {
+	if userID == uuid.Nil {
+		return ErrNilID
+	}
+	if len(action) == 0 {
+		return ArgError("action", "Action is empty")
+	}
+
+	var o model.RateLimitOverride
+	return repo.db.
+		Where(&model.RateLimitOverride{UserID: userID, Action: action}).
+		Assign(map[string]interface{}{"rate": rate, "burst": burst}).
+		FirstOrCreate(&o).
+		Error
+}
+
+// DeleteRateLimitOverride implements RateLimitRepository interface.
+func (repo *GormRepository) DeleteRateLimitOverride(userID uuid.UUID, action string) error {
+	if userID == uuid.Nil {
+		return ErrNilID
+	}
+	if len(action) == 0 {
+		return ArgError("action", "Action is empty")
+	}
+	result := repo.db.Where(&model.RateLimitOverride{UserID: userID, Action: action}).Delete(&model.RateLimitOverride{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// GetRateLimitOverrides implements RateLimitRepository interface.
+func (repo *GormRepository) GetRateLimitOverrides() ([]*model.RateLimitOverride, error) {
+	overrides := make([]*model.RateLimitOverride, 0)
+	return overrides, repo.db.Find(&overrides).Error
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/traPtitech/traQ/model"
+	"testing"
+)
+
+func findRateLimitOverrides(t *testing.T, repo Repository, userID uuid.UUID) []*model.RateLimitOverride {
+	t.Helper()
+	overrides, err := repo.GetRateLimitOverrides()
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	res := make([]*model.RateLimitOverride, 0)
+	for _, o := range overrides {
+		if o.UserID == userID {
+			res = append(res, o)
+		}
+	}
+	return res
+}
+
+func TestRepositoryImpl_SetRateLimitOverride(t *testing.T) {
+	t.Parallel()
+	repo, _, _, user := setupWithUser(t, common3)
+
+	t.Run("nil id", func(t *testing.T) {
+		t.Parallel()
+
+		assert.EqualError(t, repo.SetRateLimitOverride(uuid.Nil, "post_message", 1, 1), ErrNilID.Error())
+	})
+
+	t.Run("empty action", func(t *testing.T) {
+		t.Parallel()
+
+		assert.True(t, IsArgError(repo.SetRateLimitOverride(user.GetID(), "", 1, 1)))
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		u := mustMakeUser(t, repo, rand)
+
+		if assert.NoError(repo.SetRateLimitOverride(u.GetID(), "post_message", 2, 20)) {
+			overrides := findRateLimitOverrides(t, repo, u.GetID())
+			if assert.Len(overrides, 1) {
+				assert.Equal("post_message", overrides[0].Action)
+				assert.EqualValues(2, overrides[0].Rate)
+				assert.Equal(20, overrides[0].Burst)
+			}
+		}
+
+		// 上書き
+		if assert.NoError(repo.SetRateLimitOverride(u.GetID(), "post_message", 0, 0)) {
+			overrides := findRateLimitOverrides(t, repo, u.GetID())
+			if assert.Len(overrides, 1) {
+				assert.EqualValues(0, overrides[0].Rate)
+				assert.Equal(0, overrides[0].Burst)
+			}
+		}
+	})
+}
+
+func TestRepositoryImpl_DeleteRateLimitOverride(t *testing.T) {
+	t.Parallel()
+	repo, _, _, user := setupWithUser(t, common3)
+
+	t.Run("nil id", func(t *testing.T) {
+		t.Parallel()
+
+		assert.EqualError(t, repo.DeleteRateLimitOverride(uuid.Nil, "post_message"), ErrNilID.Error())
+	})
+
+	t.Run("empty action", func(t *testing.T) {
+		t.Parallel()
+
+		assert.True(t, IsArgError(repo.DeleteRateLimitOverride(user.GetID(), "")))
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+
+		assert.EqualError(t, repo.DeleteRateLimitOverride(user.GetID(), "upload_file"), ErrNotFound.Error())
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		u := mustMakeUser(t, repo, rand)
+		assert.NoError(repo.SetRateLimitOverride(u.GetID(), "post_message", 1, 1))
+		assert.NoError(repo.SetRateLimitOverride(u.GetID(), "add_stamp", 1, 1))
+
+		if assert.NoError(repo.DeleteRateLimitOverride(u.GetID(), "post_message")) {
+			overrides := findRateLimitOverrides(t, repo, u.GetID())
+			if assert.Len(overrides, 1) {
+				assert.Equal("add_stamp", overrides[0].Action)
+			}
+		}
+	})
+}
@@ -23,4 +23,12 @@ type Repository interface {
 	OAuth2Repository
 	BotRepository
 	ClipRepository
+	ScheduledMessageRepository
+	UserRoleRepository
+	ChannelRoleRepository
+	RateLimitRepository
+	OutgoingWebhookRepository
+	WebPushSubscriptionRepository
+	UserDigestSettingRepository
+	UserNotificationSettingRepository
 }
@@ -177,6 +177,11 @@ func mustMakePin(t *testing.T, repo Repository, messageID, userID uuid.UUID) {
 	require.NoError(t, err)
 }
 
+func mustSetChannelRole(t *testing.T, repo Repository, channelID, userID uuid.UUID, role string) {
+	t.Helper()
+	require.NoError(t, repo.SetChannelRole(channelID, userID, role))
+}
+
 func mustMakeUserGroup(t *testing.T, repo Repository, name string, adminID uuid.UUID) *model.UserGroup {
 	t.Helper()
 	if name == rand {
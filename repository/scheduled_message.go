@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/utils/optional"
+	"time"
+)
+
+// UpdateScheduledMessageArgs 予約投稿メッセージ更新引数
+type UpdateScheduledMessageArgs struct {
+	Text        optional.String
+	ScheduledAt optional.Time
+}
+
+// ScheduledMessagesQuery GetScheduledMessages用クエリ
+type ScheduledMessagesQuery struct {
+	User    uuid.UUID
+	Channel uuid.UUID
+	// Until 指定した日時以前に投稿予定のもののみ
+	Until optional.Time
+	Limit int
+}
+
+// ScheduledMessageRepository 予約投稿メッセージリポジトリ
+type ScheduledMessageRepository interface {
+	// CreateScheduledMessage 予約投稿メッセージを作成します
+	//
+	// 成功した場合、予約投稿メッセージとnilを返します。
+	// 引数にuuid.Nilを指定するとErrNilIDを返します。
+	// DBによるエラーを返すことがあります。
+	CreateScheduledMessage(userID, channelID uuid.UUID, text string, scheduledAt time.Time) (*model.ScheduledMessage, error)
+	// UpdateScheduledMessage 指定した予約投稿メッセージを更新します
+	//
+	// 成功した場合、nilを返します。
+	// 存在しない予約投稿メッセージを指定した場合、ErrNotFoundを返します。
+	// 引数にuuid.Nilを指定するとErrNilIDを返します。
+	// DBによるエラーを返すことがあります。
+	UpdateScheduledMessage(id uuid.UUID, args UpdateScheduledMessageArgs) error
+	// GetScheduledMessage 指定した予約投稿メッセージを取得します
+	//
+	// 成功した場合、予約投稿メッセージとnilを返します。
+	// 存在しない予約投稿メッセージを指定した場合、ErrNotFoundを返します。
+	// DBによるエラーを返すことがあります。
+	GetScheduledMessage(id uuid.UUID) (*model.ScheduledMessage, error)
+	// GetScheduledMessages 指定したクエリで予約投稿メッセージを取得します
+	//
+	// 成功した場合、投稿予定日時の昇順で予約投稿メッセージの配列とnilを返します。
+	// DBによるエラーを返すことがあります。
+	GetScheduledMessages(query ScheduledMessagesQuery) ([]*model.ScheduledMessage, error)
+	// DeleteScheduledMessage 指定した予約投稿メッセージを削除します
+	//
+	// 成功した場合、nilを返します。
+	// 存在しない予約投稿メッセージを指定した場合、ErrNotFoundを返します。
+	// 引数にuuid.Nilを指定するとErrNilIDを返します。
+	// DBによるエラーを返すことがあります。
+	DeleteScheduledMessage(id uuid.UUID) error
+	// DeliverScheduledMessage 指定した予約投稿メッセージを削除し、その内容でメッセージを投稿します
+	//
+	// 削除と投稿は同一トランザクションで行われるため、同じ予約投稿メッセージが複数回投稿されることはありません。
+	// 成功した場合、投稿したメッセージとnilを返します。
+	// 存在しない(既に配信された)予約投稿メッセージを指定した場合、ErrNotFoundを返します。
+	// 引数にuuid.Nilを指定するとErrNilIDを返します。
+	// DBによるエラーを返すことがあります。
+	DeliverScheduledMessage(id uuid.UUID) (*model.Message, error)
+}
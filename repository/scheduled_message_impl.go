@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/jinzhu/gorm"
+	"github.com/traPtitech/traQ/model"
+	"time"
+)
+
+// CreateScheduledMessage implements ScheduledMessageRepository interface.
+func (repo *GormRepository) CreateScheduledMessage(userID, channelID uuid.UUID, text string, scheduledAt time.Time) (*model.ScheduledMessage, error) {
+	if userID == uuid.Nil || channelID == uuid.Nil {
+		return nil, ErrNilID
+	}
+
+	m := &model.ScheduledMessage{
+		ID:          uuid.Must(uuid.NewV4()),
+		UserID:      userID,
+		ChannelID:   channelID,
+		Text:        text,
+		ScheduledAt: scheduledAt,
+	}
+	if err := repo.db.Create(m).Error; err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// UpdateScheduledMessage implements ScheduledMessageRepository interface.
+func (repo *GormRepository) UpdateScheduledMessage(id uuid.UUID, args UpdateScheduledMessageArgs) error {
+	if id == uuid.Nil {
+		return ErrNilID
+	}
+
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		var m model.ScheduledMessage
+		if err := tx.First(&m, &model.ScheduledMessage{ID: id}).Error; err != nil {
+			return convertError(err)
+		}
+
+		changes := map[string]interface{}{}
+		if args.Text.Valid {
+			changes["text"] = args.Text.String
+		}
+		if args.ScheduledAt.Valid {
+			changes["scheduled_at"] = args.ScheduledAt.Time
+		}
+		if len(changes) > 0 {
+			return tx.Model(&m).Updates(changes).Error
+		}
+		return nil
+	})
+}
+
+// GetScheduledMessage implements ScheduledMessageRepository interface.
+func (repo *GormRepository) GetScheduledMessage(id uuid.UUID) (*model.ScheduledMessage, error) {
+	if id == uuid.Nil {
+		return nil, ErrNotFound
+	}
+	m := &model.ScheduledMessage{}
+	if err := repo.db.Take(m, &model.ScheduledMessage{ID: id}).Error; err != nil {
+		return nil, convertError(err)
+	}
+	return m, nil
+}
+
+// GetScheduledMessages implements ScheduledMessageRepository interface.
+func (repo *GormRepository) GetScheduledMessages(query ScheduledMessagesQuery) ([]*model.ScheduledMessage, error) {
+	messages := make([]*model.ScheduledMessage, 0)
+	tx := repo.db.Order("scheduled_at")
+	if query.User != uuid.Nil {
+		tx = tx.Where("user_id = ?", query.User)
+	}
+	if query.Channel != uuid.Nil {
+		tx = tx.Where("channel_id = ?", query.Channel)
+	}
+	if query.Until.Valid {
+		tx = tx.Where("scheduled_at <= ?", query.Until.Time)
+	}
+	if query.Limit > 0 {
+		tx = tx.Limit(query.Limit)
+	}
+	return messages, tx.Find(&messages).Error
+}
+
+// DeleteScheduledMessage implements ScheduledMessageRepository interface.
+func (repo *GormRepository) DeleteScheduledMessage(id uuid.UUID) error {
+	if id == uuid.Nil {
+		return ErrNilID
+	}
+	result := repo.db.Delete(&model.ScheduledMessage{ID: id})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// DeliverScheduledMessage implements ScheduledMessageRepository interface.
+func (repo *GormRepository) DeliverScheduledMessage(id uuid.UUID) (*model.Message, error) {
+	if id == uuid.Nil {
+		return nil, ErrNilID
+	}
+
+	var m *model.Message
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		var sm model.ScheduledMessage
+		if err := tx.Set("gorm:query_option", "FOR UPDATE").First(&sm, &model.ScheduledMessage{ID: id}).Error; err != nil {
+			return convertError(err)
+		}
+		if err := tx.Delete(&sm).Error; err != nil {
+			return err
+		}
+
+		m = &model.Message{
+			ID:        uuid.Must(uuid.NewV4()),
+			UserID:    sm.UserID,
+			ChannelID: sm.ChannelID,
+			Text:      sm.Text,
+			Stamps:    []model.MessageStamp{},
+		}
+		return createMessage(tx, m)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	repo.publishMessageCreated(m)
+	return m, nil
+}
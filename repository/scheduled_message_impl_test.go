@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traPtitech/traQ/utils/optional"
+	"testing"
+	"time"
+)
+
+func TestRepositoryImpl_CreateScheduledMessage(t *testing.T) {
+	t.Parallel()
+	repo, _, _, user, channel := setupWithUserAndChannel(t, common3)
+
+	t.Run("nil id", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := repo.CreateScheduledMessage(uuid.Nil, channel.ID, "a", time.Now())
+		assert.EqualError(t, err, ErrNilID.Error())
+		_, err = repo.CreateScheduledMessage(user.GetID(), uuid.Nil, "a", time.Now())
+		assert.EqualError(t, err, ErrNilID.Error())
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		at := time.Now().Add(time.Hour)
+		m, err := repo.CreateScheduledMessage(user.GetID(), channel.ID, "test", at)
+		if assert.NoError(err) {
+			assert.NotZero(m.ID)
+			assert.Equal(user.GetID(), m.UserID)
+			assert.Equal(channel.ID, m.ChannelID)
+			assert.Equal("test", m.Text)
+			assert.WithinDuration(at, m.ScheduledAt, time.Millisecond)
+		}
+	})
+}
+
+func TestRepositoryImpl_UpdateScheduledMessage(t *testing.T) {
+	t.Parallel()
+	repo, _, _, user, channel := setupWithUserAndChannel(t, common3)
+
+	t.Run("nil id", func(t *testing.T) {
+		t.Parallel()
+
+		assert.EqualError(t, repo.UpdateScheduledMessage(uuid.Nil, UpdateScheduledMessageArgs{}), ErrNilID.Error())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+
+		assert.EqualError(t, repo.UpdateScheduledMessage(uuid.Must(uuid.NewV4()), UpdateScheduledMessageArgs{}), ErrNotFound.Error())
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		m, err := repo.CreateScheduledMessage(user.GetID(), channel.ID, "test", time.Now().Add(time.Hour))
+		require.NoError(t, err)
+
+		at := time.Now().Add(2 * time.Hour)
+		if assert.NoError(repo.UpdateScheduledMessage(m.ID, UpdateScheduledMessageArgs{
+			Text:        optional.StringFrom("updated"),
+			ScheduledAt: optional.TimeFrom(at),
+		})) {
+			m, err := repo.GetScheduledMessage(m.ID)
+			require.NoError(t, err)
+			assert.Equal("updated", m.Text)
+			assert.WithinDuration(at, m.ScheduledAt, time.Millisecond)
+		}
+	})
+}
+
+func TestRepositoryImpl_GetScheduledMessages(t *testing.T) {
+	t.Parallel()
+	repo, _, _, user, channel := setupWithUserAndChannel(t, common3)
+
+	now := time.Now()
+	m1, err := repo.CreateScheduledMessage(user.GetID(), channel.ID, "1", now.Add(2*time.Hour))
+	require.NoError(t, err)
+	m2, err := repo.CreateScheduledMessage(user.GetID(), channel.ID, "2", now.Add(-time.Minute))
+	require.NoError(t, err)
+
+	t.Run("by channel", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		ms, err := repo.GetScheduledMessages(ScheduledMessagesQuery{User: user.GetID(), Channel: channel.ID})
+		if assert.NoError(err) && assert.Len(ms, 2) {
+			assert.Equal(m2.ID, ms[0].ID)
+			assert.Equal(m1.ID, ms[1].ID)
+		}
+	})
+
+	t.Run("until", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		ms, err := repo.GetScheduledMessages(ScheduledMessagesQuery{Channel: channel.ID, Until: optional.TimeFrom(now)})
+		if assert.NoError(err) && assert.Len(ms, 1) {
+			assert.Equal(m2.ID, ms[0].ID)
+		}
+	})
+}
+
+func TestRepositoryImpl_DeleteScheduledMessage(t *testing.T) {
+	t.Parallel()
+	repo, _, _, user, channel := setupWithUserAndChannel(t, common3)
+
+	t.Run("nil id", func(t *testing.T) {
+		t.Parallel()
+
+		assert.EqualError(t, repo.DeleteScheduledMessage(uuid.Nil), ErrNilID.Error())
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		m, err := repo.CreateScheduledMessage(user.GetID(), channel.ID, "test", time.Now())
+		require.NoError(t, err)
+		if assert.NoError(repo.DeleteScheduledMessage(m.ID)) {
+			_, err := repo.GetScheduledMessage(m.ID)
+			assert.EqualError(err, ErrNotFound.Error())
+			assert.EqualError(repo.DeleteScheduledMessage(m.ID), ErrNotFound.Error())
+		}
+	})
+}
+
+func TestRepositoryImpl_DeliverScheduledMessage(t *testing.T) {
+	t.Parallel()
+	repo, _, _, user, channel := setupWithUserAndChannel(t, common3)
+
+	t.Run("nil id", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := repo.DeliverScheduledMessage(uuid.Nil)
+		assert.EqualError(t, err, ErrNilID.Error())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := repo.DeliverScheduledMessage(uuid.Must(uuid.NewV4()))
+		assert.EqualError(t, err, ErrNotFound.Error())
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		sm, err := repo.CreateScheduledMessage(user.GetID(), channel.ID, "test", time.Now())
+		require.NoError(t, err)
+
+		m, err := repo.DeliverScheduledMessage(sm.ID)
+		if assert.NoError(err) {
+			assert.Equal(user.GetID(), m.UserID)
+			assert.Equal(channel.ID, m.ChannelID)
+			assert.Equal("test", m.Text)
+
+			_, err := repo.GetScheduledMessage(sm.ID)
+			assert.EqualError(err, ErrNotFound.Error())
+			_, err = repo.GetMessageByID(m.ID)
+			assert.NoError(err)
+		}
+
+		// 2回目は配信されない
+		_, err = repo.DeliverScheduledMessage(sm.ID)
+		assert.EqualError(err, ErrNotFound.Error())
+	})
+}
@@ -48,6 +48,7 @@ type UsersQuery struct {
 	IsGMemberOf                 optional.UUID
 	IsSubscriberAtMarkLevelOf   optional.UUID
 	IsSubscriberAtNotifyLevelOf optional.UUID
+	IsThreadParticipantOf       optional.UUID
 	EnableProfileLoading        bool
 }
 
@@ -87,6 +88,12 @@ func (q UsersQuery) SubscriberAtNotifyLevelOf(channelID uuid.UUID) UsersQuery {
 	return q
 }
 
+// ThreadParticipantOf parentIDメッセージのスレッドの参加者(親メッセージ・返信の投稿者)である
+func (q UsersQuery) ThreadParticipantOf(parentID uuid.UUID) UsersQuery {
+	q.IsThreadParticipantOf = optional.UUIDFrom(parentID)
+	return q
+}
+
 // LoadProfile ユーザーの追加プロファイル情報を読み込むかどうか
 func (q UsersQuery) LoadProfile() UsersQuery {
 	q.EnableProfileLoading = true
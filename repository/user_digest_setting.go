@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/utils/optional"
+	"time"
+)
+
+// UpdateUserDigestSettingArgs ダイジェストメール設定更新引数
+type UpdateUserDigestSettingArgs struct {
+	Email     optional.String
+	Frequency optional.String
+}
+
+// UserDigestSettingRepository ダイジェストメール設定リポジトリ
+type UserDigestSettingRepository interface {
+	// GetUserDigestSetting 指定したユーザーのダイジェストメール設定を取得します
+	//
+	// 成功した場合、設定とnilを返します。
+	// 設定が存在しない場合、ErrNotFoundを返します。
+	// 引数にuuid.Nilを指定するとErrNilIDを返します。
+	// DBによるエラーを返すことがあります。
+	GetUserDigestSetting(userID uuid.UUID) (*model.UserDigestSetting, error)
+	// UpdateUserDigestSetting 指定したユーザーのダイジェストメール設定を更新します
+	//
+	// 成功した場合、nilを返します。設定が存在しない場合は作成します。配信停止用のトークンは自動生成されます。
+	// メールアドレスを変更した場合、アドレスは未確認になり、新しい確認用トークンが生成されます。
+	// 配信を有効にした場合、最初のダイジェストは配信間隔が経過した後に送信されます。
+	// 配信を有効にする際にメールアドレスが設定されていない場合、ArgumentErrorを返します。
+	// 引数にuuid.Nilを指定するとErrNilIDを返します。
+	// DBによるエラーを返すことがあります。
+	UpdateUserDigestSetting(userID uuid.UUID, args UpdateUserDigestSettingArgs) error
+	// GetDueUserDigestSettings 配信予定のダイジェストメール設定を取得します
+	//
+	// 成功した場合、指定した配信頻度で、メールアドレスが確認済みかつ最終配信日時がnowから配信間隔以上前で、
+	// 再試行待ちでない設定の配列とnilを返します。
+	// 負のlimitは無視されます。
+	// DBによるエラーを返すことがあります。
+	GetDueUserDigestSettings(frequency model.DigestFrequency, now time.Time, limit int) ([]*model.UserDigestSetting, error)
+	// SetUserDigestSentAt 指定したユーザーのダイジェストメールの最終配信日時を設定します
+	//
+	// 成功した場合、nilを返します。送信失敗の記録はリセットされます。
+	// 設定が存在しない場合、ErrNotFoundを返します。
+	// 引数にuuid.Nilを指定するとErrNilIDを返します。
+	// DBによるエラーを返すことがあります。
+	SetUserDigestSentAt(userID uuid.UUID, sentAt time.Time) error
+	// SetUserDigestRetryAt 指定したユーザーのダイジェストメールの送信失敗を記録します
+	//
+	// 成功した場合、nilを返します。送信失敗回数が1増え、retryAtまで配信予定から除外されます。
+	// 設定が存在しない場合、ErrNotFoundを返します。
+	// 引数にuuid.Nilを指定するとErrNilIDを返します。
+	// DBによるエラーを返すことがあります。
+	SetUserDigestRetryAt(userID uuid.UUID, retryAt time.Time) error
+	// VerifyUserDigestEmail 確認用トークンで指定したダイジェストメール設定のメールアドレスを確認済みにします
+	//
+	// 成功した場合、nilを返します。確認用トークンは無効になります。
+	// 存在しないトークンを指定した場合、ErrNotFoundを返します。
+	// DBによるエラーを返すことがあります。
+	VerifyUserDigestEmail(token string) error
+	// UnsubscribeUserDigest 配信停止用トークンで指定したダイジェストメールの配信を停止します
+	//
+	// 成功した、或いは既に停止されていた場合、nilを返します。
+	// 存在しないトークンを指定した場合、ErrNotFoundを返します。
+	// DBによるエラーを返すことがあります。
+	UnsubscribeUserDigest(token string) error
+}
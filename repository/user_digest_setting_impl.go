@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/jinzhu/gorm"
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/utils/random"
+	"time"
+)
+
+// GetUserDigestSetting implements UserDigestSettingRepository interface.
+func (repo *GormRepository) GetUserDigestSetting(userID uuid.UUID) (*model.UserDigestSetting, error) {
+	if userID == uuid.Nil {
+		return nil, ErrNilID
+	}
+	var s model.UserDigestSetting
+	if err := repo.db.First(&s, &model.UserDigestSetting{UserID: userID}).Error; err != nil {
+		return nil, convertError(err)
+	}
+	return &s, nil
+}
+
+// UpdateUserDigestSetting implements UserDigestSettingRepository interface.
+func (repo *GormRepository) UpdateUserDigestSetting(userID uuid.UUID, args UpdateUserDigestSettingArgs) error {
+	if userID == uuid.Nil {
+		return ErrNilID
+	}
+	if args.Frequency.Valid && !model.DigestFrequency(args.Frequency.String).Valid() {
+		return ArgError("args.Frequency", "invalid frequency")
+	}
+
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		var s model.UserDigestSetting
+		if err := tx.First(&s, &model.UserDigestSetting{UserID: userID}).Error; err != nil {
+			if !gorm.IsRecordNotFoundError(err) {
+				return err
+			}
+			s = model.UserDigestSetting{
+				UserID:           userID,
+				Frequency:        model.DigestFrequencyNone,
+				UnsubscribeToken: random.SecureAlphaNumeric(50),
+			}
+			if err := tx.Create(&s).Error; err != nil {
+				return err
+			}
+		}
+
+		changes := map[string]interface{}{}
+		email := s.Email
+		if args.Email.Valid && args.Email.String != s.Email {
+			email = args.Email.String
+			changes["email"] = email
+			// 新しいアドレスは確認されるまで送信しない
+			changes["email_verified_at"] = gorm.Expr("NULL")
+			changes["verification_token"] = ""
+			if len(email) > 0 {
+				changes["verification_token"] = random.SecureAlphaNumeric(50)
+			}
+		}
+		if args.Frequency.Valid {
+			frequency := model.DigestFrequency(args.Frequency.String)
+			if s.Frequency == model.DigestFrequencyNone && frequency != model.DigestFrequencyNone {
+				// 有効にした直後に過去の未読をまとめて送らない
+				changes["last_sent_at"] = time.Now()
+			}
+			changes["frequency"] = frequency
+			s.Frequency = frequency
+		}
+		if s.Frequency != model.DigestFrequencyNone && len(email) == 0 {
+			return ArgError("args.Email", "email is required to enable digests")
+		}
+
+		if len(changes) > 0 {
+			return tx.Model(&s).Updates(changes).Error
+		}
+		return nil
+	})
+}
+
+// GetDueUserDigestSettings implements UserDigestSettingRepository interface.
+func (repo *GormRepository) GetDueUserDigestSettings(frequency model.DigestFrequency, now time.Time, limit int) ([]*model.UserDigestSetting, error) {
+	settings := make([]*model.UserDigestSetting, 0)
+	tx := repo.db.
+		Where("frequency = ? AND email_verified_at IS NOT NULL", frequency).
+		Where("last_sent_at IS NULL OR last_sent_at <= ?", now.Add(-frequency.Interval())).
+		Where("retry_at IS NULL OR retry_at <= ?", now).
+		Order("last_sent_at")
+	if limit > 0 {
+		tx = tx.Limit(limit)
+	}
+	return settings, tx.Find(&settings).Error
+}
+
+// SetUserDigestSentAt implements UserDigestSettingRepository interface.
+func (repo *GormRepository) SetUserDigestSentAt(userID uuid.UUID, sentAt time.Time) error {
+	if userID == uuid.Nil {
+		return ErrNilID
+	}
+	result := repo.db.Model(&model.UserDigestSetting{}).Where("user_id = ?", userID).Updates(map[string]interface{}{
+		"last_sent_at":    sentAt,
+		"failed_attempts": 0,
+		"retry_at":        gorm.Expr("NULL"),
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// SetUserDigestRetryAt implements UserDigestSettingRepository interface.
+func (repo *GormRepository) SetUserDigestRetryAt(userID uuid.UUID, retryAt time.Time) error {
+	if userID == uuid.Nil {
+		return ErrNilID
+	}
+	result := repo.db.Model(&model.UserDigestSetting{}).Where("user_id = ?", userID).Updates(map[string]interface{}{
+		"failed_attempts": gorm.Expr("failed_attempts + 1"),
+		"retry_at":        retryAt,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// UnsubscribeUserDigest implements UserDigestSettingRepository interface.
+func (repo *GormRepository) UnsubscribeUserDigest(token string) error {
+	if len(token) == 0 {
+		return ErrNotFound
+	}
+	var s model.UserDigestSetting
+	if err := repo.db.First(&s, &model.UserDigestSetting{UnsubscribeToken: token}).Error; err != nil {
+		return convertError(err)
+	}
+	if s.Frequency == model.DigestFrequencyNone {
+		return nil
+	}
+	return repo.db.Model(&s).Update("frequency", model.DigestFrequencyNone).Error
+}
+
+// VerifyUserDigestEmail implements UserDigestSettingRepository interface.
+func (repo *GormRepository) VerifyUserDigestEmail(token string) error {
+	if len(token) == 0 {
+		return ErrNotFound
+	}
+	result := repo.db.Model(&model.UserDigestSetting{}).Where("verification_token = ?", token).Updates(map[string]interface{}{
+		"email_verified_at":  time.Now(),
+		"verification_token": "",
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/utils/optional"
+	"testing"
+	"time"
+)
+
+func TestRepositoryImpl_UpdateUserDigestSetting(t *testing.T) {
+	t.Parallel()
+	repo, _, _ := setup(t, common)
+
+	t.Run("nil id", func(t *testing.T) {
+		t.Parallel()
+		assert.EqualError(t, repo.UpdateUserDigestSetting(uuid.Nil, UpdateUserDigestSettingArgs{}), ErrNilID.Error())
+	})
+
+	t.Run("invalid frequency", func(t *testing.T) {
+		t.Parallel()
+		user := mustMakeUser(t, repo, rand)
+		assert.Error(t, repo.UpdateUserDigestSetting(user.GetID(), UpdateUserDigestSettingArgs{Frequency: optional.StringFrom("hourly")}))
+	})
+
+	t.Run("no email", func(t *testing.T) {
+		t.Parallel()
+		user := mustMakeUser(t, repo, rand)
+		assert.Error(t, repo.UpdateUserDigestSetting(user.GetID(), UpdateUserDigestSettingArgs{Frequency: optional.StringFrom("daily")}))
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		assert, require := assertAndRequire(t)
+		user := mustMakeUser(t, repo, rand)
+
+		_, err := repo.GetUserDigestSetting(user.GetID())
+		assert.EqualError(err, ErrNotFound.Error())
+
+		require.NoError(repo.UpdateUserDigestSetting(user.GetID(), UpdateUserDigestSettingArgs{Email: optional.StringFrom("test@example.com")}))
+		s, err := repo.GetUserDigestSetting(user.GetID())
+		require.NoError(err)
+		assert.Equal("test@example.com", s.Email)
+		assert.Equal(model.DigestFrequencyNone, s.Frequency)
+		assert.NotEmpty(s.UnsubscribeToken)
+		assert.False(s.IsEmailVerified())
+		assert.NotEmpty(s.VerificationToken)
+		assert.Nil(s.LastSentAt)
+
+		require.NoError(repo.UpdateUserDigestSetting(user.GetID(), UpdateUserDigestSettingArgs{Frequency: optional.StringFrom("weekly")}))
+		s2, err := repo.GetUserDigestSetting(user.GetID())
+		require.NoError(err)
+		assert.Equal(model.DigestFrequencyWeekly, s2.Frequency)
+		assert.Equal(s.UnsubscribeToken, s2.UnsubscribeToken)
+		assert.Equal(s.VerificationToken, s2.VerificationToken)
+		assert.NotNil(s2.LastSentAt)
+
+		// アドレスを変更すると再度確認が必要になる
+		require.NoError(repo.VerifyUserDigestEmail(s2.VerificationToken))
+		require.NoError(repo.UpdateUserDigestSetting(user.GetID(), UpdateUserDigestSettingArgs{Email: optional.StringFrom("test2@example.com")}))
+		s3, err := repo.GetUserDigestSetting(user.GetID())
+		require.NoError(err)
+		assert.Equal("test2@example.com", s3.Email)
+		assert.False(s3.IsEmailVerified())
+		assert.NotEmpty(s3.VerificationToken)
+		assert.NotEqual(s2.VerificationToken, s3.VerificationToken)
+	})
+}
+
+func TestRepositoryImpl_GetDueUserDigestSettings(t *testing.T) {
+	t.Parallel()
+	repo, assert, require := setup(t, ex1)
+
+	now := time.Now()
+	daily := mustMakeUser(t, repo, rand).GetID()
+	weekly := mustMakeUser(t, repo, rand).GetID()
+	unverified := mustMakeUser(t, repo, rand).GetID()
+	for _, id := range []uuid.UUID{daily, weekly, unverified} {
+		frequency := "daily"
+		if id == weekly {
+			frequency = "weekly"
+		}
+		require.NoError(repo.UpdateUserDigestSetting(id, UpdateUserDigestSettingArgs{Email: optional.StringFrom("a@example.com"), Frequency: optional.StringFrom(frequency)}))
+		if id != unverified {
+			s, err := repo.GetUserDigestSetting(id)
+			require.NoError(err)
+			require.NoError(repo.VerifyUserDigestEmail(s.VerificationToken))
+		}
+	}
+
+	settings, err := repo.GetDueUserDigestSettings(model.DigestFrequencyDaily, now, 0)
+	require.NoError(err)
+	assert.Len(settings, 0)
+
+	require.NoError(repo.SetUserDigestSentAt(daily, now.Add(-25*time.Hour)))
+	require.NoError(repo.SetUserDigestSentAt(unverified, now.Add(-25*time.Hour)))
+	settings, err = repo.GetDueUserDigestSettings(model.DigestFrequencyDaily, now, 0)
+	require.NoError(err)
+	if assert.Len(settings, 1) {
+		assert.Equal(daily, settings[0].UserID)
+	}
+
+	require.NoError(repo.SetUserDigestSentAt(weekly, now.Add(-8*24*time.Hour)))
+	settings, err = repo.GetDueUserDigestSettings(model.DigestFrequencyWeekly, now, 0)
+	require.NoError(err)
+	if assert.Len(settings, 1) {
+		assert.Equal(weekly, settings[0].UserID)
+	}
+
+	// 送信に失敗したユーザーは再試行日時まで除外される
+	require.NoError(repo.SetUserDigestRetryAt(daily, now.Add(time.Hour)))
+	settings, err = repo.GetDueUserDigestSettings(model.DigestFrequencyDaily, now, 0)
+	require.NoError(err)
+	assert.Len(settings, 0)
+	settings, err = repo.GetDueUserDigestSettings(model.DigestFrequencyDaily, now.Add(time.Hour), 0)
+	require.NoError(err)
+	if assert.Len(settings, 1) {
+		assert.Equal(1, settings[0].FailedAttempts)
+	}
+	require.NoError(repo.SetUserDigestSentAt(daily, now.Add(-25*time.Hour)))
+	if s, err := repo.GetUserDigestSetting(daily); assert.NoError(err) {
+		assert.Equal(0, s.FailedAttempts)
+		assert.Nil(s.RetryAt)
+	}
+
+	assert.EqualError(repo.SetUserDigestSentAt(uuid.Must(uuid.NewV4()), now), ErrNotFound.Error())
+	assert.EqualError(repo.SetUserDigestRetryAt(uuid.Must(uuid.NewV4()), now), ErrNotFound.Error())
+}
+
+func TestRepositoryImpl_VerifyUserDigestEmail(t *testing.T) {
+	t.Parallel()
+	repo, assert, require := setup(t, common)
+
+	user := mustMakeUser(t, repo, rand).GetID()
+	require.NoError(repo.UpdateUserDigestSetting(user, UpdateUserDigestSettingArgs{Email: optional.StringFrom("a@example.com")}))
+	s, err := repo.GetUserDigestSetting(user)
+	require.NoError(err)
+
+	assert.EqualError(repo.VerifyUserDigestEmail(""), ErrNotFound.Error())
+	assert.EqualError(repo.VerifyUserDigestEmail("invalid"), ErrNotFound.Error())
+	assert.NoError(repo.VerifyUserDigestEmail(s.VerificationToken))
+	assert.EqualError(repo.VerifyUserDigestEmail(s.VerificationToken), ErrNotFound.Error())
+
+	s, err = repo.GetUserDigestSetting(user)
+	require.NoError(err)
+	assert.True(s.IsEmailVerified())
+	assert.Empty(s.VerificationToken)
+}
+
+func TestRepositoryImpl_UnsubscribeUserDigest(t *testing.T) {
+	t.Parallel()
+	repo, assert, require := setup(t, common)
+
+	user := mustMakeUser(t, repo, rand).GetID()
+	require.NoError(repo.UpdateUserDigestSetting(user, UpdateUserDigestSettingArgs{Email: optional.StringFrom("a@example.com"), Frequency: optional.StringFrom("daily")}))
+	s, err := repo.GetUserDigestSetting(user)
+	require.NoError(err)
+
+	assert.EqualError(repo.UnsubscribeUserDigest(""), ErrNotFound.Error())
+	assert.EqualError(repo.UnsubscribeUserDigest("invalid"), ErrNotFound.Error())
+	assert.NoError(repo.UnsubscribeUserDigest(s.UnsubscribeToken))
+	assert.NoError(repo.UnsubscribeUserDigest(s.UnsubscribeToken))
+
+	s, err = repo.GetUserDigestSetting(user)
+	require.NoError(err)
+	assert.Equal(model.DigestFrequencyNone, s.Frequency)
+}
@@ -157,6 +157,9 @@ func (repo *GormRepository) makeGetUsersTx(query UsersQuery) *gorm.DB {
 	if query.IsGMemberOf.Valid {
 		tx = tx.Joins("INNER JOIN user_group_members ON user_group_members.user_id = users.id AND user_group_members.group_id = ?", query.IsGMemberOf.UUID)
 	}
+	if query.IsThreadParticipantOf.Valid {
+		tx = tx.Where("users.id IN (SELECT messages.user_id FROM messages WHERE (messages.id = ? OR messages.parent_id = ?) AND messages.deleted_at IS NULL)", query.IsThreadParticipantOf.UUID, query.IsThreadParticipantOf.UUID)
+	}
 	if query.EnableProfileLoading {
 		tx = tx.Preload("Profile")
 	}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/utils/optional"
+	"github.com/traPtitech/traQ/utils/set"
+)
+
+// UpdateUserNotificationSettingArgs 通知設定更新引数
+//
+// Keywords, MutedGroupIDs はnilの場合は変更しません。
+type UpdateUserNotificationSettingArgs struct {
+	QuietHoursEnabled optional.Bool
+	QuietHoursStart   optional.String
+	QuietHoursEnd     optional.String
+	Timezone          optional.String
+	SnoozeUntil       optional.Time
+	Keywords          model.NotificationKeywords
+	MutedGroupIDs     model.UUIDs
+}
+
+// UserNotificationSettingRepository 通知設定リポジトリ
+type UserNotificationSettingRepository interface {
+	// GetUserNotificationSetting 指定したユーザーの通知設定を取得します
+	//
+	// 成功した場合、設定とnilを返します。
+	// 設定が存在しない場合、ErrNotFoundを返します。
+	// 引数にuuid.Nilを指定するとErrNilIDを返します。
+	// DBによるエラーを返すことがあります。
+	GetUserNotificationSetting(userID uuid.UUID) (*model.UserNotificationSetting, error)
+	// GetUserNotificationSettings 指定したユーザーの通知設定を取得します
+	//
+	// 成功した場合、ユーザーIDをキーとする設定のマップとnilを返します。設定が存在しないユーザーは含まれません。
+	// DBによるエラーを返すことがあります。
+	GetUserNotificationSettings(userIDs set.UUID) (map[uuid.UUID]*model.UserNotificationSetting, error)
+	// GetKeywordNotificationSettings キーワード通知を設定している通知設定を全て取得します
+	//
+	// 成功した場合、設定の配列とnilを返します。
+	// DBによるエラーを返すことがあります。
+	GetKeywordNotificationSettings() ([]*model.UserNotificationSetting, error)
+	// UpdateUserNotificationSetting 指定したユーザーの通知設定を更新します
+	//
+	// 成功した場合、nilを返します。設定が存在しない場合は作成します。
+	// 現在より前のSnoozeUntilを指定した場合、スヌーズを解除します。
+	// 時刻、タイムゾーン、キーワードが不正な場合、ArgumentErrorを返します。
+	// 引数にuuid.Nilを指定するとErrNilIDを返します。
+	// DBによるエラーを返すことがあります。
+	UpdateUserNotificationSetting(userID uuid.UUID, args UpdateUserNotificationSettingArgs) error
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/jinzhu/gorm"
+	"github.com/leandro-lugaresi/hub"
+	"github.com/traPtitech/traQ/event"
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/utils/set"
+	"strings"
+	"time"
+)
+
+// GetUserNotificationSetting implements UserNotificationSettingRepository interface.
+func (repo *GormRepository) GetUserNotificationSetting(userID uuid.UUID) (*model.UserNotificationSetting, error) {
+	if userID == uuid.Nil {
+		return nil, ErrNilID
+	}
+	var s model.UserNotificationSetting
+	if err := repo.db.First(&s, &model.UserNotificationSetting{UserID: userID}).Error; err != nil {
+		return nil, convertError(err)
+	}
+	return &s, nil
+}
+
+// GetUserNotificationSettings implements UserNotificationSettingRepository interface.
+func (repo *GormRepository) GetUserNotificationSettings(userIDs set.UUID) (map[uuid.UUID]*model.UserNotificationSetting, error) {
+	settings := make(map[uuid.UUID]*model.UserNotificationSetting, len(userIDs))
+	if len(userIDs) == 0 {
+		return settings, nil
+	}
+
+	var tmp []*model.UserNotificationSetting
+	if err := repo.db.Where("user_id IN (?)", userIDs.StringArray()).Find(&tmp).Error; err != nil {
+		return nil, err
+	}
+	for _, s := range tmp {
+		settings[s.UserID] = s
+	}
+	return settings, nil
+}
+
+// GetKeywordNotificationSettings implements UserNotificationSettingRepository interface.
+func (repo *GormRepository) GetKeywordNotificationSettings() ([]*model.UserNotificationSetting, error) {
+	settings := make([]*model.UserNotificationSetting, 0)
+	return settings, repo.db.Where("keywords <> ''").Find(&settings).Error
+}
+
+// UpdateUserNotificationSetting implements UserNotificationSettingRepository interface.
+func (repo *GormRepository) UpdateUserNotificationSetting(userID uuid.UUID, args UpdateUserNotificationSettingArgs) error {
+	if userID == uuid.Nil {
+		return ErrNilID
+	}
+
+	changes := map[string]interface{}{}
+	if args.QuietHoursEnabled.Valid {
+		changes["quiet_hours_enabled"] = args.QuietHoursEnabled.Bool
+	}
+	if args.QuietHoursStart.Valid {
+		if _, err := model.ParseClock(args.QuietHoursStart.String); err != nil {
+			return ArgError("args.QuietHoursStart", "must be HH:MM")
+		}
+		changes["quiet_hours_start"] = args.QuietHoursStart.String
+	}
+	if args.QuietHoursEnd.Valid {
+		if _, err := model.ParseClock(args.QuietHoursEnd.String); err != nil {
+			return ArgError("args.QuietHoursEnd", "must be HH:MM")
+		}
+		changes["quiet_hours_end"] = args.QuietHoursEnd.String
+	}
+	if args.Timezone.Valid {
+		if _, err := time.LoadLocation(args.Timezone.String); err != nil || len(args.Timezone.String) == 0 || args.Timezone.String == "Local" {
+			return ArgError("args.Timezone", "unknown timezone")
+		}
+		changes["timezone"] = args.Timezone.String
+	}
+	if args.SnoozeUntil.Valid {
+		if args.SnoozeUntil.Time.After(time.Now()) {
+			changes["snooze_until"] = args.SnoozeUntil.Time
+		} else {
+			changes["snooze_until"] = nil
+		}
+	}
+	if args.Keywords != nil {
+		keywords := model.NotificationKeywords{}
+		for _, k := range args.Keywords {
+			if len(k) == 0 || strings.ContainsAny(k, "\r\n") {
+				return ArgError("args.Keywords", "keywords must not be empty or contain line breaks")
+			}
+			keywords = append(keywords, k)
+		}
+		changes["keywords"] = keywords
+	}
+	if args.MutedGroupIDs != nil {
+		changes["muted_group_ids"] = model.UUIDs(set.UUIDSetFromArray(args.MutedGroupIDs).Array())
+	}
+
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		var s model.UserNotificationSetting
+		if err := tx.First(&s, &model.UserNotificationSetting{UserID: userID}).Error; err != nil {
+			if !gorm.IsRecordNotFoundError(err) {
+				return err
+			}
+			s = model.UserNotificationSetting{
+				UserID:          userID,
+				QuietHoursStart: "00:00",
+				QuietHoursEnd:   "00:00",
+				Timezone:        "UTC",
+				Keywords:        model.NotificationKeywords{},
+				MutedGroupIDs:   model.UUIDs{},
+			}
+			if err := tx.Create(&s).Error; err != nil {
+				return err
+			}
+		}
+
+		if len(changes) > 0 {
+			return tx.Model(&s).Updates(changes).Error
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	repo.hub.Publish(hub.Message{
+		Name: event.UserNotificationSettingUpdated,
+		Fields: hub.Fields{
+			"user_id": userID,
+		},
+	})
+	return nil
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/utils/optional"
+	"github.com/traPtitech/traQ/utils/set"
+	"testing"
+	"time"
+)
+
+func TestRepositoryImpl_UpdateUserNotificationSetting(t *testing.T) {
+	t.Parallel()
+	repo, _, _ := setup(t, common)
+
+	t.Run("nil id", func(t *testing.T) {
+		t.Parallel()
+		assert.EqualError(t, repo.UpdateUserNotificationSetting(uuid.Nil, UpdateUserNotificationSettingArgs{}), ErrNilID.Error())
+	})
+
+	t.Run("invalid args", func(t *testing.T) {
+		t.Parallel()
+		user := mustMakeUser(t, repo, rand)
+		assert.Error(t, repo.UpdateUserNotificationSetting(user.GetID(), UpdateUserNotificationSettingArgs{QuietHoursStart: optional.StringFrom("25:00")}))
+		assert.Error(t, repo.UpdateUserNotificationSetting(user.GetID(), UpdateUserNotificationSettingArgs{QuietHoursEnd: optional.StringFrom("7:00")}))
+		assert.Error(t, repo.UpdateUserNotificationSetting(user.GetID(), UpdateUserNotificationSettingArgs{Timezone: optional.StringFrom("Mars/Olympus")}))
+		assert.Error(t, repo.UpdateUserNotificationSetting(user.GetID(), UpdateUserNotificationSettingArgs{Keywords: model.NotificationKeywords{"a\nb"}}))
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		assert, require := assertAndRequire(t)
+		user := mustMakeUser(t, repo, rand)
+		gid := uuid.Must(uuid.NewV4())
+
+		_, err := repo.GetUserNotificationSetting(user.GetID())
+		assert.EqualError(err, ErrNotFound.Error())
+
+		require.NoError(repo.UpdateUserNotificationSetting(user.GetID(), UpdateUserNotificationSettingArgs{
+			QuietHoursEnabled: optional.BoolFrom(true),
+			QuietHoursStart:   optional.StringFrom("23:00"),
+			QuietHoursEnd:     optional.StringFrom("07:00"),
+			Timezone:          optional.StringFrom("Asia/Tokyo"),
+			SnoozeUntil:       optional.TimeFrom(time.Now().Add(time.Hour)),
+			Keywords:          model.NotificationKeywords{"traQ"},
+			MutedGroupIDs:     model.UUIDs{gid, gid},
+		}))
+		s, err := repo.GetUserNotificationSetting(user.GetID())
+		require.NoError(err)
+		assert.True(s.QuietHoursEnabled)
+		assert.Equal("23:00", s.QuietHoursStart)
+		assert.Equal("07:00", s.QuietHoursEnd)
+		assert.Equal("Asia/Tokyo", s.Timezone)
+		assert.NotNil(s.SnoozeUntil)
+		assert.EqualValues([]string{"traQ"}, s.Keywords)
+		assert.EqualValues([]uuid.UUID{gid}, s.MutedGroupIDs)
+
+		require.NoError(repo.UpdateUserNotificationSetting(user.GetID(), UpdateUserNotificationSettingArgs{
+			SnoozeUntil: optional.TimeFrom(time.Now().Add(-time.Hour)),
+			Keywords:    model.NotificationKeywords{},
+		}))
+		s, err = repo.GetUserNotificationSetting(user.GetID())
+		require.NoError(err)
+		assert.True(s.QuietHoursEnabled)
+		assert.Nil(s.SnoozeUntil)
+		assert.Empty(s.Keywords)
+		assert.Len(s.MutedGroupIDs, 1)
+	})
+}
+
+func TestRepositoryImpl_GetUserNotificationSettings(t *testing.T) {
+	t.Parallel()
+	repo, assert, require := setup(t, ex1)
+
+	user1 := mustMakeUser(t, repo, rand)
+	user2 := mustMakeUser(t, repo, rand)
+	user3 := mustMakeUser(t, repo, rand)
+	require.NoError(repo.UpdateUserNotificationSetting(user1.GetID(), UpdateUserNotificationSettingArgs{Keywords: model.NotificationKeywords{"traQ"}}))
+	require.NoError(repo.UpdateUserNotificationSetting(user2.GetID(), UpdateUserNotificationSettingArgs{QuietHoursEnabled: optional.BoolFrom(true)}))
+
+	settings, err := repo.GetUserNotificationSettings(set.UUIDSetFromArray([]uuid.UUID{user1.GetID(), user2.GetID(), user3.GetID()}))
+	require.NoError(err)
+	assert.Len(settings, 2)
+	assert.Contains(settings, user1.GetID())
+	assert.Contains(settings, user2.GetID())
+
+	keywords, err := repo.GetKeywordNotificationSettings()
+	require.NoError(err)
+	if assert.Len(keywords, 1) {
+		assert.Equal(user1.GetID(), keywords[0].UserID)
+	}
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/utils/optional"
+)
+
+// UpdateUserRoleArgs ユーザーロール更新引数
+type UpdateUserRoleArgs struct {
+	// OAuth2Scope OAuth2のスコープとして使用できるかどうか
+	OAuth2Scope optional.Bool
+	// Permissions ロールに与える権限 nilの場合は変更しません
+	Permissions []string
+	// Inheritances 継承するロール nilの場合は変更しません
+	Inheritances []string
+}
+
+// UserRoleRepository ユーザーロールリポジトリ
+type UserRoleRepository interface {
+	// CreateUserRole ユーザーロールを作成します
+	//
+	// 成功した場合、ロールとnilを返します。
+	// 既に同名のロールが存在する場合、ErrAlreadyExistsを返します。
+	// 存在しないロールを継承しようとした場合、ArgumentErrorを返します。
+	// DBによるエラーを返すことがあります。
+	CreateUserRole(name string, oauth2Scope bool, permissions, inheritances []string) (*model.UserRole, error)
+	// GetUserRoles 全てのユーザーロールを取得します
+	//
+	// 成功した場合、継承ロール・権限を含んだロールの配列とnilを返します。
+	// DBによるエラーを返すことがあります。
+	GetUserRoles() ([]*model.UserRole, error)
+	// GetUserRole 指定したユーザーロールを取得します
+	//
+	// 成功した場合、継承ロール・権限を含んだロールとnilを返します。
+	// 存在しないロールを指定した場合、ErrNotFoundを返します。
+	// DBによるエラーを返すことがあります。
+	GetUserRole(name string) (*model.UserRole, error)
+	// UpdateUserRole 指定したユーザーロールを更新します
+	//
+	// 成功した場合、nilを返します。
+	// 存在しないロールを指定した場合、ErrNotFoundを返します。
+	// システムロールを指定した場合、ErrForbiddenを返します。
+	// 存在しないロールを継承しようとした場合や、継承関係が循環する場合、ArgumentErrorを返します。
+	// DBによるエラーを返すことがあります。
+	UpdateUserRole(name string, args UpdateUserRoleArgs) error
+	// DeleteUserRole 指定したユーザーロールを削除します
+	//
+	// 成功した場合、nilを返します。このロールを継承しているロールの継承関係も削除されます。
+	// 存在しないロールを指定した場合、ErrNotFoundを返します。
+	// システムロールや、ユーザーに割り当てられているロールを指定した場合、ErrForbiddenを返します。
+	// DBによるエラーを返すことがあります。
+	DeleteUserRole(name string) error
+}
@@ -0,0 +1,238 @@
+package repository
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/leandro-lugaresi/hub"
+	"github.com/traPtitech/traQ/event"
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/utils/gormutil"
+)
+
+// CreateUserRole implements UserRoleRepository interface.
+func (repo *GormRepository) CreateUserRole(name string, oauth2Scope bool, permissions, inheritances []string) (*model.UserRole, error) {
+	if len(name) == 0 {
+		return nil, ArgError("name", "Name is empty")
+	}
+
+	r := &model.UserRole{
+		Name:        name,
+		Oauth2Scope: oauth2Scope,
+	}
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		if exists, err := gormutil.RecordExists(tx, &model.UserRole{Name: name}); err != nil {
+			return err
+		} else if exists {
+			return ErrAlreadyExists
+		}
+
+		if err := tx.Create(r).Error; err != nil {
+			return err
+		}
+		if err := setUserRolePermissions(tx, name, permissions); err != nil {
+			return err
+		}
+		if err := setUserRoleInheritances(tx, name, inheritances); err != nil {
+			return err
+		}
+		return tx.Scopes(userRolePreloads).Take(r, &model.UserRole{Name: name}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	repo.hub.Publish(hub.Message{
+		Name: event.UserRoleCreated,
+		Fields: hub.Fields{
+			"role": name,
+		},
+	})
+	return r, nil
+}
+
+// GetUserRoles implements UserRoleRepository interface.
+func (repo *GormRepository) GetUserRoles() ([]*model.UserRole, error) {
+	roles := make([]*model.UserRole, 0)
+	return roles, repo.db.Scopes(userRolePreloads).Order("name").Find(&roles).Error
+}
+
+// GetUserRole implements UserRoleRepository interface.
+func (repo *GormRepository) GetUserRole(name string) (*model.UserRole, error) {
+	if len(name) == 0 {
+		return nil, ErrNotFound
+	}
+	r := &model.UserRole{}
+	if err := repo.db.Scopes(userRolePreloads).Take(r, &model.UserRole{Name: name}).Error; err != nil {
+		return nil, convertError(err)
+	}
+	return r, nil
+}
+
+// UpdateUserRole implements UserRoleRepository interface.
+func (repo *GormRepository) UpdateUserRole(name string, args UpdateUserRoleArgs) error {
+	if len(name) == 0 {
+		return ErrNotFound
+	}
+
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		var r model.UserRole
+		if err := tx.Take(&r, &model.UserRole{Name: name}).Error; err != nil {
+			return convertError(err)
+		}
+		if r.System {
+			return ErrForbidden
+		}
+
+		if args.OAuth2Scope.Valid {
+			if err := tx.Model(&r).Update("oauth2_scope", args.OAuth2Scope.Bool).Error; err != nil {
+				return err
+			}
+		}
+		if args.Permissions != nil {
+			if err := setUserRolePermissions(tx, name, args.Permissions); err != nil {
+				return err
+			}
+		}
+		if args.Inheritances != nil {
+			if err := setUserRoleInheritances(tx, name, args.Inheritances); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	repo.hub.Publish(hub.Message{
+		Name: event.UserRoleUpdated,
+		Fields: hub.Fields{
+			"role": name,
+		},
+	})
+	return nil
+}
+
+// DeleteUserRole implements UserRoleRepository interface.
+func (repo *GormRepository) DeleteUserRole(name string) error {
+	if len(name) == 0 {
+		return ErrNotFound
+	}
+
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		var r model.UserRole
+		if err := tx.Take(&r, &model.UserRole{Name: name}).Error; err != nil {
+			return convertError(err)
+		}
+		if r.System {
+			return ErrForbidden
+		}
+
+		// ユーザーに割り当てられているロールは削除できない
+		if exists, err := gormutil.RecordExists(tx, &model.User{Role: name}); err != nil {
+			return err
+		} else if exists {
+			return ErrForbidden
+		}
+
+		errs := tx.
+			Delete(model.RoleInheritance{}, "role = ? OR sub_role = ?", name, name).
+			Delete(model.RolePermission{}, &model.RolePermission{Role: name}).
+			Delete(&r).
+			GetErrors()
+		if len(errs) > 0 {
+			return errs[0]
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	repo.hub.Publish(hub.Message{
+		Name: event.UserRoleDeleted,
+		Fields: hub.Fields{
+			"role": name,
+		},
+	})
+	return nil
+}
+
+func userRolePreloads(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Inheritances").
+		Preload("Permissions")
+}
+
+// setUserRolePermissions ロールの権限を置き換えます
+func setUserRolePermissions(tx *gorm.DB, name string, permissions []string) error {
+	if err := tx.Delete(model.RolePermission{}, &model.RolePermission{Role: name}).Error; err != nil {
+		return err
+	}
+	added := map[string]bool{}
+	for _, p := range permissions {
+		if added[p] {
+			continue
+		}
+		if err := tx.Create(&model.RolePermission{Role: name, Permission: p}).Error; err != nil {
+			return err
+		}
+		added[p] = true
+	}
+	return nil
+}
+
+// setUserRoleInheritances ロールの継承関係を置き換えます
+func setUserRoleInheritances(tx *gorm.DB, name string, inheritances []string) error {
+	if err := tx.Delete(model.RoleInheritance{}, &model.RoleInheritance{Role: name}).Error; err != nil {
+		return err
+	}
+
+	var all []*model.RoleInheritance
+	if err := tx.Find(&all).Error; err != nil {
+		return err
+	}
+	graph := map[string][]string{}
+	for _, v := range all {
+		graph[v.Role] = append(graph[v.Role], v.SubRole)
+	}
+
+	added := map[string]bool{}
+	for _, sub := range inheritances {
+		if added[sub] {
+			continue
+		}
+		if sub == name {
+			return ArgError("inheritances", "the role cannot inherit itself")
+		}
+		if exists, err := gormutil.RecordExists(tx, &model.UserRole{Name: sub}); err != nil {
+			return err
+		} else if !exists {
+			return ArgError("inheritances", "role "+sub+" does not exist")
+		}
+		if reachable(graph, sub, name) {
+			return ArgError("inheritances", "inheritance cycle detected: "+sub)
+		}
+		if err := tx.Create(&model.RoleInheritance{Role: name, SubRole: sub}).Error; err != nil {
+			return err
+		}
+		graph[name] = append(graph[name], sub)
+		added[sub] = true
+	}
+	return nil
+}
+
+// reachable 継承グラフ上でfromからtoに到達できるかどうか
+func reachable(graph map[string][]string, from, to string) bool {
+	visited := map[string]bool{}
+	stack := []string{from}
+	for len(stack) > 0 {
+		v := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if v == to {
+			return true
+		}
+		if visited[v] {
+			continue
+		}
+		visited[v] = true
+		stack = append(stack, graph[v]...)
+	}
+	return false
+}
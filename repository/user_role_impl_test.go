@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/traPtitech/traQ/service/rbac/role"
+	"github.com/traPtitech/traQ/utils/optional"
+	"github.com/traPtitech/traQ/utils/random"
+	"testing"
+)
+
+func TestRepositoryImpl_CreateUserRole(t *testing.T) {
+	t.Parallel()
+	repo, _, _ := setup(t, common3)
+
+	t.Run("empty name", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := repo.CreateUserRole("", false, nil, nil)
+		assert.True(t, IsArgError(err))
+	})
+
+	t.Run("already exists", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := repo.CreateUserRole(role.User, false, nil, nil)
+		assert.EqualError(t, err, ErrAlreadyExists.Error())
+	})
+
+	t.Run("unknown inheritance", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := repo.CreateUserRole(random.AlphaNumeric(20), false, nil, []string{random.AlphaNumeric(20)})
+		assert.True(t, IsArgError(err))
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		name := random.AlphaNumeric(20)
+		r, err := repo.CreateUserRole(name, true, []string{"get_message", "get_message"}, []string{role.Read})
+		if assert.NoError(err) {
+			assert.Equal(name, r.Name)
+			assert.True(r.Oauth2Scope)
+			assert.False(r.System)
+			if assert.Len(r.Permissions, 1) {
+				assert.Equal("get_message", r.Permissions[0].Permission)
+			}
+			if assert.Len(r.Inheritances, 1) {
+				assert.Equal(role.Read, r.Inheritances[0].SubRole)
+			}
+		}
+	})
+}
+
+func TestRepositoryImpl_UpdateUserRole(t *testing.T) {
+	t.Parallel()
+	repo, _, require := setup(t, common3)
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+
+		assert.EqualError(t, repo.UpdateUserRole(random.AlphaNumeric(20), UpdateUserRoleArgs{}), ErrNotFound.Error())
+	})
+
+	t.Run("system role", func(t *testing.T) {
+		t.Parallel()
+
+		assert.EqualError(t, repo.UpdateUserRole(role.User, UpdateUserRoleArgs{}), ErrForbidden.Error())
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		t.Parallel()
+
+		a, err := repo.CreateUserRole(random.AlphaNumeric(20), false, nil, nil)
+		require.NoError(err)
+		b, err := repo.CreateUserRole(random.AlphaNumeric(20), false, nil, []string{a.Name})
+		require.NoError(err)
+
+		assert.True(t, IsArgError(repo.UpdateUserRole(a.Name, UpdateUserRoleArgs{Inheritances: []string{b.Name}})))
+		assert.True(t, IsArgError(repo.UpdateUserRole(a.Name, UpdateUserRoleArgs{Inheritances: []string{a.Name}})))
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		r, err := repo.CreateUserRole(random.AlphaNumeric(20), false, []string{"get_message"}, []string{role.Read})
+		require.NoError(err)
+
+		if assert.NoError(repo.UpdateUserRole(r.Name, UpdateUserRoleArgs{
+			OAuth2Scope: optional.BoolFrom(true),
+			Permissions: []string{"post_message", "edit_message"},
+		})) {
+			r, err := repo.GetUserRole(r.Name)
+			require.NoError(err)
+			assert.True(r.Oauth2Scope)
+			assert.Len(r.Permissions, 2)
+			assert.Len(r.Inheritances, 1)
+		}
+	})
+}
+
+func TestRepositoryImpl_DeleteUserRole(t *testing.T) {
+	t.Parallel()
+	repo, _, require := setup(t, common3)
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+
+		assert.EqualError(t, repo.DeleteUserRole(random.AlphaNumeric(20)), ErrNotFound.Error())
+	})
+
+	t.Run("system role", func(t *testing.T) {
+		t.Parallel()
+
+		assert.EqualError(t, repo.DeleteUserRole(role.User), ErrForbidden.Error())
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		sub, err := repo.CreateUserRole(random.AlphaNumeric(20), false, []string{"get_message"}, nil)
+		require.NoError(err)
+		parent, err := repo.CreateUserRole(random.AlphaNumeric(20), false, nil, []string{sub.Name})
+		require.NoError(err)
+
+		if assert.NoError(repo.DeleteUserRole(sub.Name)) {
+			_, err := repo.GetUserRole(sub.Name)
+			assert.EqualError(err, ErrNotFound.Error())
+
+			parent, err := repo.GetUserRole(parent.Name)
+			require.NoError(err)
+			assert.Len(parent.Inheritances, 0)
+		}
+	})
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/utils/set"
+)
+
+// WebPushSubscriptionRepository Web Push購読リポジトリ
+type WebPushSubscriptionRepository interface {
+	// RegisterWebPushSubscription Web Push購読を登録します
+	//
+	// 成功した、或いは既に登録されていた場合にnilを返します。
+	// 同じユーザーで既に登録されていた場合は鍵を更新します。
+	// 引数にuuid.Nilを指定した場合、ErrNilIDを返します。
+	// endpoint, p256dh, authのいずれかが空文字列の場合、ArgumentErrorを返します。
+	// 登録しようとしたエンドポイントが既に他のユーザーと関連づけられていた場合はArgumentErrorを返します。
+	// DBによるエラーを返すことがあります。
+	RegisterWebPushSubscription(userID uuid.UUID, endpoint, p256dh, auth string) error
+	// GetWebPushSubscriptions 指定したユーザーの全Web Push購読を取得します
+	//
+	// 成功した場合、ユーザーIDをキーとする購読の配列のマップとnilを返します。
+	// DBによるエラーを返すことがあります。
+	GetWebPushSubscriptions(userIDs set.UUID) (map[uuid.UUID][]*model.WebPushSubscription, error)
+	// DeleteWebPushSubscription 指定したユーザーのWeb Push購読を解除します
+	//
+	// 成功した、或いは既に登録解除されていた場合にnilを返します。
+	// 引数にuuid.Nilを指定した場合、ErrNilIDを返します。
+	// DBによるエラーを返すことがあります。
+	DeleteWebPushSubscription(userID uuid.UUID, endpoint string) error
+	// DeleteWebPushSubscriptions Web Push購読を解除します
+	//
+	// 成功した、或いは既に登録解除されていた場合にnilを返します。
+	// DBによるエラーを返すことがあります。
+	DeleteWebPushSubscriptions(endpoints []string) error
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/jinzhu/gorm"
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/utils/set"
+)
+
+// RegisterWebPushSubscription implements WebPushSubscriptionRepository interface.
+func (repo *GormRepository) RegisterWebPushSubscription(userID uuid.UUID, endpoint, p256dh, auth string) error {
+	if userID == uuid.Nil {
+		return ErrNilID
+	}
+	if len(endpoint) == 0 {
+		return ArgError("Endpoint", "endpoint is empty")
+	}
+	if len(p256dh) == 0 {
+		return ArgError("P256dh", "p256dh is empty")
+	}
+	if len(auth) == 0 {
+		return ArgError("Auth", "auth is empty")
+	}
+
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		var s model.WebPushSubscription
+		if err := tx.Where("endpoint = ?", endpoint).First(&s).Error; err == nil {
+			if s.UserID != userID {
+				return ArgError("Endpoint", "the Endpoint has already been associated with other user")
+			}
+			if s.P256dh == p256dh && s.Auth == auth {
+				return nil
+			}
+			return tx.Model(&s).Updates(map[string]interface{}{"p256dh": p256dh, "auth": auth}).Error
+		} else if !gorm.IsRecordNotFoundError(err) {
+			return err
+		}
+
+		return tx.Create(&model.WebPushSubscription{
+			ID:       uuid.Must(uuid.NewV4()),
+			UserID:   userID,
+			Endpoint: endpoint,
+			P256dh:   p256dh,
+			Auth:     auth,
+		}).Error
+	})
+}
+
+// GetWebPushSubscriptions implements WebPushSubscriptionRepository interface.
+func (repo *GormRepository) GetWebPushSubscriptions(userIDs set.UUID) (map[uuid.UUID][]*model.WebPushSubscription, error) {
+	var tmp []*model.WebPushSubscription
+	if err := repo.db.Where("user_id IN (?)", userIDs.StringArray()).Find(&tmp).Error; err != nil {
+		return nil, err
+	}
+
+	subs := make(map[uuid.UUID][]*model.WebPushSubscription, len(userIDs))
+	for _, s := range tmp {
+		subs[s.UserID] = append(subs[s.UserID], s)
+	}
+	return subs, nil
+}
+
+// DeleteWebPushSubscription implements WebPushSubscriptionRepository interface.
+func (repo *GormRepository) DeleteWebPushSubscription(userID uuid.UUID, endpoint string) error {
+	if userID == uuid.Nil {
+		return ErrNilID
+	}
+	return repo.db.Where("user_id = ? AND endpoint = ?", userID, endpoint).Delete(&model.WebPushSubscription{}).Error
+}
+
+// DeleteWebPushSubscriptions implements WebPushSubscriptionRepository interface.
+func (repo *GormRepository) DeleteWebPushSubscriptions(endpoints []string) error {
+	if len(endpoints) == 0 {
+		return nil
+	}
+	return repo.db.Where("endpoint IN (?)", endpoints).Delete(&model.WebPushSubscription{}).Error
+}
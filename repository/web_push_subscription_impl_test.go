@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/traPtitech/traQ/model"
+	random2 "github.com/traPtitech/traQ/utils/random"
+	"github.com/traPtitech/traQ/utils/set"
+	"testing"
+)
+
+func TestRepositoryImpl_RegisterWebPushSubscription(t *testing.T) {
+	t.Parallel()
+	repo, assert, require := setup(t, common)
+
+	id1 := mustMakeUser(t, repo, rand).GetID()
+	id2 := mustMakeUser(t, repo, rand).GetID()
+	endpoint1 := "https://push.example.com/" + random2.AlphaNumeric(20)
+	endpoint2 := "https://push.example.com/" + random2.AlphaNumeric(20)
+
+	cases := []struct {
+		user     uuid.UUID
+		endpoint string
+		p256dh   string
+		auth     string
+		error    bool
+	}{
+		{id1, endpoint1, "key1", "auth1", false},
+		{id2, endpoint2, "key2", "auth2", false},
+		{id2, endpoint2, "key3", "auth3", false},
+		{id1, endpoint2, "key1", "auth1", true},
+		{uuid.Nil, endpoint2, "key1", "auth1", true},
+		{id1, "", "key1", "auth1", true},
+		{id1, endpoint1, "", "auth1", true},
+		{id1, endpoint1, "key1", "", true},
+	}
+
+	for _, v := range cases {
+		err := repo.RegisterWebPushSubscription(v.user, v.endpoint, v.p256dh, v.auth)
+		if v.error {
+			assert.Error(err)
+		} else {
+			assert.NoError(err)
+		}
+	}
+
+	assert.EqualValues(2, count(t, getDB(repo).Model(model.WebPushSubscription{}).Where("user_id IN (?, ?)", id1, id2)))
+
+	subs, err := repo.GetWebPushSubscriptions(set.UUIDSetFromArray([]uuid.UUID{id2}))
+	require.NoError(err)
+	if assert.Len(subs[id2], 1) {
+		assert.Equal("key3", subs[id2][0].P256dh)
+		assert.Equal("auth3", subs[id2][0].Auth)
+	}
+}
+
+func TestRepositoryImpl_GetWebPushSubscriptions(t *testing.T) {
+	t.Parallel()
+	repo, _, require := setup(t, common)
+
+	id1 := mustMakeUser(t, repo, rand).GetID()
+	id2 := mustMakeUser(t, repo, rand).GetID()
+
+	require.NoError(repo.RegisterWebPushSubscription(id1, "https://push.example.com/"+random2.AlphaNumeric(20), "key", "auth"))
+	require.NoError(repo.RegisterWebPushSubscription(id2, "https://push.example.com/"+random2.AlphaNumeric(20), "key", "auth"))
+	require.NoError(repo.RegisterWebPushSubscription(id1, "https://push.example.com/"+random2.AlphaNumeric(20), "key", "auth"))
+
+	cases := []struct {
+		name   string
+		users  []uuid.UUID
+		expect int
+	}{
+		{"id1", []uuid.UUID{id1}, 2},
+		{"id2", []uuid.UUID{id2}, 1},
+		{"id1, id2", []uuid.UUID{id1, id2}, 3},
+		{"nil", []uuid.UUID{}, 0},
+	}
+
+	for _, v := range cases {
+		v := v
+		t.Run(v.name, func(t *testing.T) {
+			t.Parallel()
+			assert := assert.New(t)
+			subs, err := repo.GetWebPushSubscriptions(set.UUIDSetFromArray(v.users))
+			if assert.NoError(err) {
+				n := 0
+				for _, arr := range subs {
+					n += len(arr)
+				}
+				assert.EqualValues(v.expect, n)
+			}
+		})
+	}
+}
+
+func TestRepositoryImpl_DeleteWebPushSubscription(t *testing.T) {
+	t.Parallel()
+	repo, assert, require := setup(t, common)
+
+	id1 := mustMakeUser(t, repo, rand).GetID()
+	id2 := mustMakeUser(t, repo, rand).GetID()
+	endpoint := "https://push.example.com/" + random2.AlphaNumeric(20)
+	require.NoError(repo.RegisterWebPushSubscription(id1, endpoint, "key", "auth"))
+
+	assert.EqualError(repo.DeleteWebPushSubscription(uuid.Nil, endpoint), ErrNilID.Error())
+	assert.NoError(repo.DeleteWebPushSubscription(id2, endpoint))
+	assert.EqualValues(1, count(t, getDB(repo).Model(model.WebPushSubscription{}).Where("user_id = ?", id1)))
+	assert.NoError(repo.DeleteWebPushSubscription(id1, endpoint))
+	assert.EqualValues(0, count(t, getDB(repo).Model(model.WebPushSubscription{}).Where("user_id = ?", id1)))
+}
+
+func TestRepositoryImpl_DeleteWebPushSubscriptions(t *testing.T) {
+	t.Parallel()
+	repo, assert, require := setup(t, common)
+
+	id1 := mustMakeUser(t, repo, rand).GetID()
+	id2 := mustMakeUser(t, repo, rand).GetID()
+	endpoint1 := "https://push.example.com/" + random2.AlphaNumeric(20)
+	endpoint2 := "https://push.example.com/" + random2.AlphaNumeric(20)
+	endpoint3 := "https://push.example.com/" + random2.AlphaNumeric(20)
+	require.NoError(repo.RegisterWebPushSubscription(id1, endpoint1, "key", "auth"))
+	require.NoError(repo.RegisterWebPushSubscription(id2, endpoint2, "key", "auth"))
+	require.NoError(repo.RegisterWebPushSubscription(id1, endpoint3, "key", "auth"))
+
+	cases := []struct {
+		endpoints []string
+		expect    int
+	}{
+		{[]string{endpoint2}, 2},
+		{[]string{}, 2},
+		{[]string{endpoint1, endpoint3, ""}, 0},
+	}
+	for _, v := range cases {
+		assert.NoError(repo.DeleteWebPushSubscriptions(v.endpoints))
+		assert.EqualValues(v.expect, count(t, getDB(repo).Model(model.WebPushSubscription{}).Where("user_id IN (?, ?)", id1, id2)))
+	}
+}
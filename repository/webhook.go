@@ -1,3 +1,4 @@
+//go:generate mockgen -source=$GOFILE -destination=mock_$GOPACKAGE/mock_$GOFILE
 package repository
 
 import (
@@ -33,7 +34,7 @@ type WebhookRepository interface {
 	UpdateWebhook(id uuid.UUID, args UpdateWebhookArgs) error
 	// DeleteWebhook Webhookを削除します
 	//
-	// 成功した場合、nilを返します。WebhookのUserはstatusがdeactivatedになります。
+	// 成功した場合、nilを返します。WebhookのUserはstatusがdeactivatedになり、WebhookのOutgoing Webhookは削除されます。
 	// 既に存在しなかった場合、ErrNotFoundを返します。
 	// idにuuid.Nilを指定した場合、ErrNilIDを返します。
 	// DBによるエラーを返すことがあります。
@@ -187,6 +187,9 @@ func (repo *GormRepository) DeleteWebhook(id uuid.UUID) error {
 		if err := tx.Delete(&model.WebhookBot{ID: id}).Error; err != nil {
 			return err
 		}
+		if err := tx.Where(&model.OutgoingWebhook{WebhookID: id}).Delete(&model.OutgoingWebhook{}).Error; err != nil {
+			return err
+		}
 		return tx.Model(&model.User{}).Where(&model.User{ID: b.BotUserID}).Update("status", model.UserAccountStatusDeactivated).Error
 	})
 	if err != nil {
@@ -14,6 +14,8 @@ type Config struct {
 	Version string
 	// Revision サーバーリビジョン
 	Revision string
+	// Origin サーバーオリジン
+	Origin string
 	// AccessLogging アクセスログを記録するかどうか
 	AccessLogging bool
 	// Gzipped レスポンスをGzip圧縮するかどうか
@@ -24,6 +26,8 @@ type Config struct {
 	IsRefreshEnabled bool
 	// SkyWaySecretKey SkyWayクレデンシャル用シークレットキー
 	SkyWaySecretKey string
+	// WebPushPublicKey Web Push用VAPID公開鍵 (Web Pushが無効の場合は空)
+	WebPushPublicKey string
 	// ExternalAuth 外部認証設定
 	ExternalAuth ExternalAuthConfig
 }
@@ -68,7 +72,9 @@ func provideV3Config(c *Config) v3.Config {
 	return v3.Config{
 		Version:                         c.Version,
 		Revision:                        c.Revision,
+		Origin:                          c.Origin,
 		SkyWaySecretKey:                 c.SkyWaySecretKey,
+		WebPushPublicKey:                c.WebPushPublicKey,
 		EnabledExternalAccountProviders: c.ExternalAuth.ValidProviders(),
 	}
 }
@@ -10,7 +10,9 @@ const (
 	HeaderFileMetaType      = "X-TRAQ-FILE-TYPE"
 	HeaderCacheFile         = "X-TRAQ-FILE-CACHE"
 	HeaderSignature         = "X-TRAQ-Signature"
+	HeaderSignature256      = "X-TRAQ-Signature-256"
 	HeaderChannelID         = "X-TRAQ-Channel-Id"
 	HeaderMore              = "X-TRAQ-More"
 	HeaderVersion           = "X-TRAQ-VERSION"
+	HeaderRetryAfter        = "Retry-After"
 )
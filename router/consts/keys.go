@@ -1,20 +1,24 @@
 package consts
 
 const (
-	KeyUserID             = "userID"
-	KeyUser               = "user"
-	KeyOAuth2AccessScopes = "scopes"
-	KeyParamStamp         = "paramStamp"
-	KeyParamStampPalette  = "paramStampPalette"
-	KeyParamGroup         = "paramGroup"
-	KeyParamUser          = "paramUser"
-	KeyParamClient        = "paramClient"
-	KeyParamBot           = "paramBot"
-	KeyParamWebhook       = "paramWebhook"
-	KeyParamMessage       = "paramMessage"
-	KeyParamChannel       = "paramChannel"
-	KeyParamFile          = "paramFile"
-	KeyParamClipFolder    = "paramClipFolder"
-	KeyRepo               = "_repo"
-	KeyChannelManager     = "_cm"
+	KeyUserID                = "userID"
+	KeyUser                  = "user"
+	KeyOAuth2AccessScopes    = "scopes"
+	KeyParamStamp            = "paramStamp"
+	KeyParamStampPalette     = "paramStampPalette"
+	KeyParamGroup            = "paramGroup"
+	KeyParamUser             = "paramUser"
+	KeyParamClient           = "paramClient"
+	KeyParamBot              = "paramBot"
+	KeyParamWebhook          = "paramWebhook"
+	KeyParamMessage          = "paramMessage"
+	KeyParamChannel          = "paramChannel"
+	KeyParamFile             = "paramFile"
+	KeyParamClipFolder       = "paramClipFolder"
+	KeyParamScheduledMessage = "paramScheduledMessage"
+	KeyParamMessageReport    = "paramMessageReport"
+	KeyParamUserRole         = "paramUserRole"
+	KeyParamOutgoingWebhook  = "paramOutgoingWebhook"
+	KeyRepo                  = "_repo"
+	KeyChannelManager        = "_cm"
 )
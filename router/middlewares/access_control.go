@@ -2,6 +2,7 @@ package middlewares
 
 import (
 	"fmt"
+	"github.com/gofrs/uuid"
 	"github.com/labstack/echo/v4"
 	"github.com/traPtitech/traQ/model"
 	"github.com/traPtitech/traQ/repository"
@@ -32,8 +33,13 @@ func AccessControlMiddlewareGenerator(r rbac.RBAC) func(p ...permission.Permissi
 
 				// ユーザー権限検証
 				user := c.Get(consts.KeyUser).(model.UserInfo)
+				channelID, hasChannelScope := getChannelScope(c)
 				for _, v := range p {
 					if !r.IsGranted(user.GetRole(), v) {
+						// チャンネルロールによる権限
+						if hasChannelScope && r.IsChannelGranted(user.GetID(), channelID, v) {
+							continue
+						}
 						// NG
 						return echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("you are not permitted to request to '%s'", c.Request().URL.Path))
 					}
@@ -45,6 +51,17 @@ func AccessControlMiddlewareGenerator(r rbac.RBAC) func(p ...permission.Permissi
 	}
 }
 
+// getChannelScope リクエスト対象のチャンネルIDを取得します
+func getChannelScope(c echo.Context) (uuid.UUID, bool) {
+	if ch, ok := c.Get(consts.KeyParamChannel).(*model.Channel); ok {
+		return ch.ID, true
+	}
+	if m, ok := c.Get(consts.KeyParamMessage).(*model.Message); ok {
+		return m.ChannelID, true
+	}
+	return uuid.Nil, false
+}
+
 // AdminOnly 管理者ユーザーのみを通すミドルウェア
 func AdminOnly(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
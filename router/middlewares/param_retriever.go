@@ -156,6 +156,34 @@ func (pr *ParamRetriever) StampPalettesID() echo.MiddlewareFunc {
 	})
 }
 
+// ScheduledMessageID リクエストURLの`scheduledMessageID`パラメータからScheduledMessageを取り出す
+func (pr *ParamRetriever) ScheduledMessageID() echo.MiddlewareFunc {
+	return pr.byUUID(consts.ParamScheduledMessageID, consts.KeyParamScheduledMessage, func(c echo.Context, v uuid.UUID) (interface{}, error) {
+		return pr.repo.GetScheduledMessage(v)
+	})
+}
+
+// MessageReportID リクエストURLの`messageReportID`パラメータからMessageReportを取り出す
+func (pr *ParamRetriever) MessageReportID() echo.MiddlewareFunc {
+	return pr.byUUID(consts.ParamMessageReportID, consts.KeyParamMessageReport, func(c echo.Context, v uuid.UUID) (interface{}, error) {
+		return pr.repo.GetMessageReport(v)
+	})
+}
+
+// OutgoingWebhookID リクエストURLの`outgoingWebhookID`パラメータからOutgoingWebhookを取り出す
+func (pr *ParamRetriever) OutgoingWebhookID() echo.MiddlewareFunc {
+	return pr.byUUID(consts.ParamOutgoingWebhookID, consts.KeyParamOutgoingWebhook, func(c echo.Context, v uuid.UUID) (interface{}, error) {
+		return pr.repo.GetOutgoingWebhook(v)
+	})
+}
+
+// RoleName リクエストURLの`roleName`パラメータからUserRoleを取り出す
+func (pr *ParamRetriever) RoleName() echo.MiddlewareFunc {
+	return pr.byString(consts.ParamRoleName, consts.KeyParamUserRole, func(c echo.Context, v string) (interface{}, error) {
+		return pr.repo.GetUserRole(v)
+	})
+}
+
 // UserID リクエストURLの`userID`パラメータからUserを取り出す
 func (pr *ParamRetriever) UserID(checkOnly bool) echo.MiddlewareFunc {
 	if checkOnly {
@@ -0,0 +1,46 @@
+package middlewares
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/router/consts"
+	"github.com/traPtitech/traQ/service/ratelimit"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RateLimitMiddlewareGenerator BOTユーザーによる操作のレート制限を行うミドルウェアのジェネレーターを返します
+//
+// BOT以外のユーザーは制限しません。
+func RateLimitMiddlewareGenerator(l ratelimit.Limiter) func(action ratelimit.Action) echo.MiddlewareFunc {
+	return func(action ratelimit.Action) echo.MiddlewareFunc {
+		return func(next echo.HandlerFunc) echo.HandlerFunc {
+			return func(c echo.Context) error {
+				user := c.Get(consts.KeyUser).(model.UserInfo)
+				if user.IsBot() {
+					if ok, retryAfter := l.Allow(ratelimit.Bot, user.GetID(), action); !ok {
+						return tooManyRequests(c, retryAfter)
+					}
+				}
+				return next(c)
+			}
+		}
+	}
+}
+
+// AllowWebhook Webhookによるメッセージ投稿のレート制限を確認します
+//
+// 署名の確認後にハンドラ内で呼び出してください。署名の無い不正なリクエストでトークンが消費されることはありません。
+func AllowWebhook(c echo.Context, l ratelimit.Limiter, w model.Webhook) error {
+	if ok, retryAfter := l.Allow(ratelimit.Webhook, w.GetBotUserID(), ratelimit.PostMessage); !ok {
+		return tooManyRequests(c, retryAfter)
+	}
+	return nil
+}
+
+func tooManyRequests(c echo.Context, retryAfter time.Duration) error {
+	c.Response().Header().Set(consts.HeaderRetryAfter, strconv.FormatInt(int64(math.Ceil(retryAfter.Seconds())), 10))
+	return echo.NewHTTPError(http.StatusTooManyRequests, "rate limit exceeded")
+}
@@ -141,6 +141,18 @@ var IsUserID = vd.WithContext(func(ctx context.Context, value interface{}) error
 	return nil
 })
 
+// IsValidBotEvent 有効なBOTイベントである
+var IsValidBotEvent = vd.By(func(value interface{}) error {
+	v, ok := value.(model.BotEventType)
+	if !ok || len(v) == 0 {
+		return nil
+	}
+	if !event.Types.Contains(v) {
+		return errors.New("must be valid bot event type")
+	}
+	return nil
+})
+
 // IsValidBotEvents 有効なBOTイベントのセットである
 var IsValidBotEvents = vd.By(func(value interface{}) error {
 	s, ok := value.(model.BotEventTypes)
@@ -70,7 +70,7 @@ func (h *Handlers) PostBots(c echo.Context) error {
 		return herror.InternalServerError(err)
 	}
 
-	b, err := h.Repo.CreateBot(req.Name, req.DisplayName, req.Description, iconFileID, getRequestUserID(c), req.WebhookURL)
+	b, err := h.Repo.CreateBot(req.Name, req.DisplayName, req.Description, iconFileID, getRequestUserID(c), model.BotModeHTTP, req.WebhookURL)
 	if err != nil {
 		switch {
 		case err == repository.ErrAlreadyExists:
@@ -253,7 +253,11 @@ func (h *Handlers) PostMessageReport(c echo.Context) error {
 func (h *Handlers) GetMessageReports(c echo.Context) error {
 	p, _ := strconv.Atoi(c.QueryParam("p"))
 
-	reports, err := h.Repo.GetMessageReports(p*50, 50)
+	reports, _, err := h.Repo.GetMessageReports(repository.MessageReportsQuery{
+		Offset: p * 50,
+		Limit:  50,
+		Asc:    true,
+	})
 	if err != nil {
 		return herror.InternalServerError(err)
 	}
@@ -137,7 +137,7 @@ type messageResponse struct {
 }
 
 func formatMessage(m *model.Message) *messageResponse {
-	return &messageResponse{
+	res := &messageResponse{
 		MessageID:       m.ID,
 		UserID:          m.UserID,
 		ParentChannelID: m.ChannelID,
@@ -147,6 +147,11 @@ func formatMessage(m *model.Message) *messageResponse {
 		UpdatedAt:       m.UpdatedAt,
 		StampList:       m.Stamps,
 	}
+	if m.Hidden {
+		// 非表示のメッセージの本文は返さない
+		res.Content = ""
+	}
+	return res
 }
 
 func formatMessages(ms []*model.Message) []*messageResponse {
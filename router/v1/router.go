@@ -18,6 +18,7 @@ import (
 	"github.com/traPtitech/traQ/service/counter"
 	"github.com/traPtitech/traQ/service/file"
 	imaging2 "github.com/traPtitech/traQ/service/imaging"
+	"github.com/traPtitech/traQ/service/ratelimit"
 	"github.com/traPtitech/traQ/service/rbac"
 	"github.com/traPtitech/traQ/service/rbac/permission"
 	"github.com/traPtitech/traQ/service/viewer"
@@ -51,6 +52,7 @@ type Handlers struct {
 	ChannelManager channel.Manager
 	FileManager    file.Manager
 	Replacer       *message.Replacer
+	RateLimiter    ratelimit.Limiter
 
 	emojiJSONCache     bytes.Buffer `wire:"-"`
 	emojiJSONTime      time.Time    `wire:"-"`
@@ -64,6 +66,7 @@ type Handlers struct {
 func (h *Handlers) Setup(e *echo.Group) {
 	// middleware preparation
 	requires := middlewares.AccessControlMiddlewareGenerator(h.RBAC)
+	rateLimit := middlewares.RateLimitMiddlewareGenerator(h.RateLimiter)
 	bodyLimit := middlewares.RequestBodyLengthLimit
 	retrieve := middlewares.NewParamRetriever(h.Repo, h.ChannelManager, h.FileManager)
 	blockBot := middlewares.BlockBot(h.Repo)
@@ -128,7 +131,7 @@ func (h *Handlers) Setup(e *echo.Group) {
 				apiUsersUID.PUT("/status", h.PutUserStatus, requires(permission.EditOtherUsers))
 				apiUsersUID.PUT("/password", h.PutUserPassword, requires(permission.EditOtherUsers))
 				apiUsersUID.GET("/messages", h.GetDirectMessages, requires(permission.GetMessage))
-				apiUsersUID.POST("/messages", h.PostDirectMessage, bodyLimit(100), requires(permission.PostMessage))
+				apiUsersUID.POST("/messages", h.PostDirectMessage, bodyLimit(100), requires(permission.PostMessage), rateLimit(ratelimit.PostMessage))
 				apiUsersUID.GET("/icon", h.GetUserIcon, requires(permission.DownloadFile))
 				apiUsersUID.PUT("/icon", h.PutUserIcon, requires(permission.EditOtherUsers))
 				apiUsersUID.GET("/notification", h.GetNotificationChannels, requires(permission.GetChannelSubscription))
@@ -172,7 +175,7 @@ func (h *Handlers) Setup(e *echo.Group) {
 				apiChannelsCidMessages := apiChannelsCid.Group("/messages")
 				{
 					apiChannelsCidMessages.GET("", h.GetMessagesByChannelID, requires(permission.GetMessage))
-					apiChannelsCidMessages.POST("", h.PostMessage, bodyLimit(100), requires(permission.PostMessage))
+					apiChannelsCidMessages.POST("", h.PostMessage, bodyLimit(100), requires(permission.PostMessage), rateLimit(ratelimit.PostMessage))
 				}
 				apiChannelsCidNotification := apiChannelsCid.Group("/notification")
 				{
@@ -208,7 +211,7 @@ func (h *Handlers) Setup(e *echo.Group) {
 				apiMessagesMid.GET("/stamps", h.GetMessageStamps, requires(permission.GetMessage))
 				apiMessagesMidStampsSid := apiMessagesMid.Group("/stamps/:stampID", retrieve.StampID(true))
 				{
-					apiMessagesMidStampsSid.POST("", h.PostMessageStamp, requires(permission.AddMessageStamp))
+					apiMessagesMidStampsSid.POST("", h.PostMessageStamp, requires(permission.AddMessageStamp), rateLimit(ratelimit.AddStamp))
 					apiMessagesMidStampsSid.DELETE("", h.DeleteMessageStamp, requires(permission.RemoveMessageStamp))
 				}
 			}
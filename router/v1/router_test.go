@@ -9,9 +9,11 @@ import (
 	"github.com/traPtitech/traQ/router/extension"
 	"github.com/traPtitech/traQ/router/session"
 	"github.com/traPtitech/traQ/service/channel"
+	"github.com/traPtitech/traQ/service/cluster"
 	"github.com/traPtitech/traQ/service/counter"
 	"github.com/traPtitech/traQ/service/file"
 	"github.com/traPtitech/traQ/service/imaging"
+	"github.com/traPtitech/traQ/service/ratelimit"
 	"github.com/traPtitech/traQ/service/rbac"
 	"github.com/traPtitech/traQ/service/viewer"
 	"github.com/traPtitech/traQ/testutils"
@@ -70,7 +72,7 @@ func TestMain(m *testing.M) {
 		env.Hub = hub.New()
 		env.SessStore = session.NewMemorySessionStore()
 		env.RBAC = testutils.NewTestRBAC()
-		env.ChannelManager, _ = channel.InitChannelManager(env.Repository, zap.NewNop())
+		env.ChannelManager, _ = channel.InitChannelManager(env.Repository, cluster.NewStandaloneBus(), zap.NewNop())
 		env.ImageProcessor = imaging.NewProcessor(imaging.Config{
 			MaxPixels:        1000 * 1000,
 			Concurrency:      1,
@@ -79,6 +81,8 @@ func TestMain(m *testing.M) {
 		})
 		env.FileManager, _ = file.InitFileManager(env.Repository, storage.NewInMemoryFileStorage(), env.ImageProcessor, zap.NewNop())
 
+		env.RateLimiter, _ = ratelimit.NewLimiter(env.Repository, ratelimit.Config{}, cluster.NewStandaloneBus())
+
 		e := echo.New()
 		e.HideBanner = true
 		e.HidePort = true
@@ -90,12 +94,13 @@ func TestMain(m *testing.M) {
 			Repo:           env.Repository,
 			Hub:            env.Hub,
 			Logger:         zap.NewNop(),
-			OC:             counter.NewOnlineCounter(env.Hub),
-			VM:             viewer.NewManager(env.Hub),
+			OC:             counter.NewOnlineCounter(env.Hub, cluster.NewStandaloneBus()),
+			VM:             viewer.NewManager(env.Hub, cluster.NewStandaloneBus()),
 			ChannelManager: env.ChannelManager,
 			FileManager:    env.FileManager,
 			SessStore:      env.SessStore,
 			Imaging:        env.ImageProcessor,
+			RateLimiter:    env.RateLimiter,
 		}
 		handlers.Setup(e.Group("/api"))
 		env.Server = httptest.NewServer(e)
@@ -120,6 +125,7 @@ type Env struct {
 	ChannelManager channel.Manager
 	FileManager    file.Manager
 	ImageProcessor imaging.Processor
+	RateLimiter    ratelimit.Limiter
 }
 
 func setup(t *testing.T, server string) (*Env, *assert.Assertions, *require.Assertions, string, string) {
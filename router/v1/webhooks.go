@@ -11,6 +11,7 @@ import (
 	"github.com/traPtitech/traQ/repository"
 	"github.com/traPtitech/traQ/router/consts"
 	"github.com/traPtitech/traQ/router/extension/herror"
+	"github.com/traPtitech/traQ/router/middlewares"
 	"github.com/traPtitech/traQ/router/utils"
 	"github.com/traPtitech/traQ/service/channel"
 	"github.com/traPtitech/traQ/service/file"
@@ -173,6 +174,9 @@ func (h *Handlers) PostWebhook(c echo.Context) error {
 			return herror.Unauthorized()
 		}
 	}
+	if err := middlewares.AllowWebhook(c, h.RateLimiter, w); err != nil {
+		return err
+	}
 
 	// 投稿先チャンネル変更
 	if cid := c.Request().Header.Get(consts.HeaderChannelID); len(cid) > 0 {
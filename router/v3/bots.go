@@ -2,6 +2,8 @@ package v3
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	vd "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/go-ozzo/ozzo-validation/v4/is"
 	"github.com/gofrs/uuid"
@@ -19,6 +21,8 @@ import (
 	"github.com/traPtitech/traQ/utils/optional"
 	"github.com/traPtitech/traQ/utils/validator"
 	"net/http"
+	"strings"
+	"time"
 )
 
 // GetBots GET /bots
@@ -41,15 +45,20 @@ type PostBotRequest struct {
 	Name        string `json:"name"`
 	DisplayName string `json:"displayName"`
 	Description string `json:"description"`
+	Mode        string `json:"mode"`
 	Endpoint    string `json:"endpoint"`
 }
 
-func (r PostBotRequest) Validate() error {
-	return vd.ValidateStruct(&r,
+func (r *PostBotRequest) Validate() error {
+	if len(r.Mode) == 0 {
+		r.Mode = model.BotModeHTTP.String()
+	}
+	return vd.ValidateStruct(r,
 		vd.Field(&r.Name, validator.BotUserNameRuleRequired...),
 		vd.Field(&r.DisplayName, vd.Required, vd.RuneLength(1, 32)),
 		vd.Field(&r.Description, vd.Required, vd.RuneLength(0, 1000)),
-		vd.Field(&r.Endpoint, vd.Required, is.URL, validator.NotInternalURL),
+		vd.Field(&r.Mode, vd.In(model.BotModeHTTP.String(), model.BotModeWebSocket.String())),
+		vd.Field(&r.Endpoint, vd.When(r.Mode == model.BotModeHTTP.String(), vd.Required), is.URL, validator.NotInternalURL),
 	)
 }
 
@@ -65,11 +74,13 @@ func (h *Handlers) CreateBot(c echo.Context) error {
 		return herror.InternalServerError(err)
 	}
 
-	b, err := h.Repo.CreateBot(req.Name, req.DisplayName, req.Description, iconFileID, getRequestUserID(c), req.Endpoint)
+	b, err := h.Repo.CreateBot(req.Name, req.DisplayName, req.Description, iconFileID, getRequestUserID(c), model.BotMode(req.Mode), req.Endpoint)
 	if err != nil {
 		switch {
 		case err == repository.ErrAlreadyExists:
 			return herror.Conflict("this name has already been used")
+		case repository.IsArgError(err):
+			return herror.BadRequest(err)
 		default:
 			return herror.InternalServerError(err)
 		}
@@ -118,18 +129,21 @@ func (h *Handlers) GetBot(c echo.Context) error {
 
 // PatchBotRequest PATCH /bots/:botID リクエストボディ
 type PatchBotRequest struct {
-	DisplayName     optional.String     `json:"displayName"`
-	Description     optional.String     `json:"description"`
-	Endpoint        optional.String     `json:"endpoint"`
-	Privileged      optional.Bool       `json:"privileged"`
-	DeveloperID     optional.UUID       `json:"developerId"`
-	SubscribeEvents model.BotEventTypes `json:"subscribeEvents"`
+	DisplayName             optional.String     `json:"displayName"`
+	Description             optional.String     `json:"description"`
+	Mode                    model.BotMode       `json:"mode"`
+	Endpoint                optional.String     `json:"endpoint"`
+	Privileged              optional.Bool       `json:"privileged"`
+	DeveloperID             optional.UUID       `json:"developerId"`
+	SubscribeEvents         model.BotEventTypes `json:"subscribeEvents"`
+	LegacyVerificationToken optional.Bool       `json:"legacyVerificationToken"`
 }
 
 func (r PatchBotRequest) ValidateWithContext(ctx context.Context) error {
 	return vd.ValidateStructWithContext(ctx, &r,
 		vd.Field(&r.DisplayName, vd.RuneLength(1, 32)),
 		vd.Field(&r.Description, vd.RuneLength(0, 1000)),
+		vd.Field(&r.Mode, vd.In(model.BotModeHTTP, model.BotModeWebSocket)),
 		vd.Field(&r.Endpoint, is.URL, validator.NotInternalURL),
 		vd.Field(&r.DeveloperID, validator.NotNilUUID, utils.IsActiveHumanUserID),
 		vd.Field(&r.SubscribeEvents, utils.IsValidBotEvents),
@@ -150,12 +164,14 @@ func (h *Handlers) EditBot(c echo.Context) error {
 	}
 
 	args := repository.UpdateBotArgs{
-		DisplayName:     req.DisplayName,
-		Description:     req.Description,
-		WebhookURL:      req.Endpoint,
-		Privileged:      req.Privileged,
-		CreatorID:       req.DeveloperID,
-		SubscribeEvents: req.SubscribeEvents,
+		DisplayName:             req.DisplayName,
+		Description:             req.Description,
+		WebhookURL:              req.Endpoint,
+		Mode:                    req.Mode,
+		Privileged:              req.Privileged,
+		CreatorID:               req.DeveloperID,
+		SubscribeEvents:         req.SubscribeEvents,
+		LegacyVerificationToken: req.LegacyVerificationToken,
 	}
 
 	if err := h.Repo.UpdateBot(b.ID, args); err != nil {
@@ -232,6 +248,51 @@ func (h *Handlers) GetBotLogs(c echo.Context) error {
 	return c.JSON(http.StatusOK, logs)
 }
 
+// GetBotDeadEvents GET /bots/:botID/logs/dead
+func (h *Handlers) GetBotDeadEvents(c echo.Context) error {
+	b := getParamBot(c)
+
+	var req GetBotLogsRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	events, err := h.Repo.GetDeadBotEvents(b.ID, req.Limit, req.Offset)
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+
+	return c.JSON(http.StatusOK, events)
+}
+
+// PostBotDeadEventsReplayRequest POST /bots/:botID/logs/dead/replay リクエストボディ
+type PostBotDeadEventsReplayRequest struct {
+	IDs []uuid.UUID `json:"ids"`
+}
+
+func (r PostBotDeadEventsReplayRequest) Validate() error {
+	return vd.ValidateStruct(&r,
+		vd.Field(&r.IDs, vd.Length(0, 200), vd.Each(validator.NotNilUUID)),
+	)
+}
+
+// ReplayBotDeadEvents POST /bots/:botID/logs/dead/replay
+func (h *Handlers) ReplayBotDeadEvents(c echo.Context) error {
+	b := getParamBot(c)
+
+	var req PostBotDeadEventsReplayRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	n, err := h.Repo.ReplayDeadBotEvents(b.ID, req.IDs)
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+
+	return c.JSON(http.StatusOK, echo.Map{"count": n})
+}
+
 // GetChannelBots GET /channels/:channelID/bots
 func (h *Handlers) GetChannelBots(c echo.Context) error {
 	channelID := getParamAsUUID(c, consts.ParamChannelID)
@@ -251,6 +312,182 @@ func (h *Handlers) GetChannelBots(c echo.Context) error {
 	return c.JSON(http.StatusOK, res)
 }
 
+// GetBotCommands GET /bots/:botID/commands
+func (h *Handlers) GetBotCommands(c echo.Context) error {
+	b := getParamBot(c)
+
+	commands, err := h.Repo.GetBotCommands(b.ID)
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+	return c.JSON(http.StatusOK, commands)
+}
+
+// PutBotCommandsRequest PUT /bots/:botID/commands リクエストボディ
+type PutBotCommandsRequest struct {
+	Commands []BotCommandRequest `json:"commands"`
+}
+
+func (r PutBotCommandsRequest) Validate() error {
+	return vd.ValidateStruct(&r,
+		vd.Field(&r.Commands, vd.Length(0, 100)),
+	)
+}
+
+// BotCommandRequest Botのスラッシュコマンド定義
+type BotCommandRequest struct {
+	Name        string                      `json:"name"`
+	Description string                      `json:"description"`
+	Arguments   []BotCommandArgumentRequest `json:"arguments"`
+	ChannelIDs  []uuid.UUID                 `json:"channelIds"`
+}
+
+func (r BotCommandRequest) Validate() error {
+	return vd.ValidateStruct(&r,
+		vd.Field(&r.Name, validator.BotCommandNameRuleRequired...),
+		vd.Field(&r.Description, vd.RuneLength(0, 1000)),
+		vd.Field(&r.Arguments, vd.Length(0, 20), vd.By(func(value interface{}) error {
+			args := value.([]BotCommandArgumentRequest)
+			names := make(map[string]bool, len(args))
+			optional := false
+			for _, arg := range args {
+				if names[arg.Name] {
+					return errors.New("argument names must be unique")
+				}
+				names[arg.Name] = true
+				if arg.Required && optional {
+					return errors.New("required arguments must precede optional ones")
+				}
+				optional = !arg.Required
+			}
+			return nil
+		})),
+		vd.Field(&r.ChannelIDs, vd.Length(0, 100), vd.Each(validator.NotNilUUID)),
+	)
+}
+
+// BotCommandArgumentRequest Botのスラッシュコマンドの引数定義
+type BotCommandArgumentRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Required    bool   `json:"required"`
+}
+
+func (r BotCommandArgumentRequest) Validate() error {
+	return vd.ValidateStruct(&r,
+		vd.Field(&r.Name, validator.BotCommandNameRuleRequired...),
+		vd.Field(&r.Description, vd.RuneLength(0, 1000)),
+	)
+}
+
+// SetBotCommands PUT /bots/:botID/commands
+func (h *Handlers) SetBotCommands(c echo.Context) error {
+	b := getParamBot(c)
+
+	var req PutBotCommandsRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	names := make(map[string]bool, len(req.Commands))
+	commands := make([]*model.BotCommand, len(req.Commands))
+	for i, cmd := range req.Commands {
+		if names[cmd.Name] {
+			return herror.BadRequest(fmt.Sprintf("duplicated command name: %s", cmd.Name))
+		}
+		names[cmd.Name] = true
+
+		for _, id := range cmd.ChannelIDs {
+			ok, err := h.ChannelManager.IsChannelAccessibleToUser(b.BotUserID, id)
+			if err != nil {
+				return herror.InternalServerError(err)
+			}
+			if !ok {
+				return herror.BadRequest(fmt.Sprintf("invalid channel: %s", id))
+			}
+		}
+
+		args := make(model.BotCommandArguments, len(cmd.Arguments))
+		for j, arg := range cmd.Arguments {
+			args[j] = model.BotCommandArgument{
+				Name:        arg.Name,
+				Description: arg.Description,
+				Required:    arg.Required,
+			}
+		}
+		commands[i] = &model.BotCommand{
+			Name:        cmd.Name,
+			Description: cmd.Description,
+			Arguments:   args,
+			ChannelIDs:  cmd.ChannelIDs,
+		}
+	}
+
+	commands, err := h.Repo.SetBotCommands(b.ID, commands)
+	if err != nil {
+		switch err {
+		case repository.ErrAlreadyExists:
+			return herror.Conflict("some command names are already used by other bots in the same channels")
+		default:
+			return herror.InternalServerError(err)
+		}
+	}
+	return c.JSON(http.StatusOK, commands)
+}
+
+// GetChannelCommands GET /channels/:channelID/commands
+func (h *Handlers) GetChannelCommands(c echo.Context) error {
+	ch := getParamChannel(c)
+	prefix := strings.ToLower(c.QueryParam("q"))
+
+	commands, err := h.Repo.GetAllBotCommands()
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+
+	res := make([]*model.BotCommand, 0)
+	for _, cmd := range commands {
+		if !cmd.AvailableIn(ch.ID) || !strings.HasPrefix(strings.ToLower(cmd.Name), prefix) {
+			continue
+		}
+		if !ch.IsPublic {
+			// プライベートチャンネル・DMではBotがメンバーのコマンドのみ
+			b, err := h.Repo.GetBotByID(cmd.BotID)
+			if err != nil {
+				return herror.InternalServerError(err)
+			}
+			ok, err := h.ChannelManager.IsChannelAccessibleToUser(b.BotUserID, ch.ID)
+			if err != nil {
+				return herror.InternalServerError(err)
+			}
+			if !ok {
+				continue
+			}
+		}
+		res = append(res, cmd)
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// ConnectBotWS GET /bots/ws
+func (h *Handlers) ConnectBotWS(c echo.Context) error {
+	b, err := h.Repo.GetBotByBotUserID(getRequestUserID(c))
+	if err != nil {
+		switch err {
+		case repository.ErrNotFound:
+			return herror.Forbidden("you are not a bot")
+		default:
+			return herror.InternalServerError(err)
+		}
+	}
+	if b.Mode != model.BotModeWebSocket {
+		return herror.BadRequest("this bot is not in WebSocket mode")
+	}
+
+	h.BotWS.ServeHTTP(c.Response(), c.Request())
+	return nil
+}
+
 // ActivateBot POST /bots/:botID/actions/activate
 func (h *Handlers) ActivateBot(c echo.Context) error {
 	b := getParamBot(c)
@@ -295,6 +532,77 @@ func (h *Handlers) ReissueBot(c echo.Context) error {
 	})
 }
 
+const (
+	defaultBotSigningSecretGracePeriod = 24 * time.Hour
+	maxBotSigningSecretGracePeriod     = 7 * 24 * time.Hour
+)
+
+// PostBotActionRotateSecretRequest POST /bots/:botID/actions/rotate-secret リクエストボディ
+type PostBotActionRotateSecretRequest struct {
+	GracePeriod optional.Int `json:"gracePeriod"`
+}
+
+func (r PostBotActionRotateSecretRequest) Validate() error {
+	return vd.ValidateStruct(&r,
+		vd.Field(&r.GracePeriod, vd.Min(0), vd.Max(int64(maxBotSigningSecretGracePeriod/time.Second))),
+	)
+}
+
+// RotateBotSigningSecret POST /bots/:botID/actions/rotate-secret
+func (h *Handlers) RotateBotSigningSecret(c echo.Context) error {
+	var req PostBotActionRotateSecretRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	gracePeriod := defaultBotSigningSecretGracePeriod
+	if req.GracePeriod.Valid {
+		gracePeriod = time.Duration(req.GracePeriod.Int64) * time.Second
+	}
+
+	b := getParamBot(c)
+	b, err := h.Repo.RotateBotSigningSecret(b.ID, gracePeriod)
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+
+	return c.JSON(http.StatusOK, echo.Map{
+		"signingSecret":                  b.SigningSecret,
+		"previousSigningSecretExpiresAt": b.PreviousSigningSecretExpiresAt,
+	})
+}
+
+// PostBotActionTestEventRequest POST /bots/:botID/actions/test-event リクエストボディ
+type PostBotActionTestEventRequest struct {
+	Event model.BotEventType `json:"event"`
+}
+
+func (r PostBotActionTestEventRequest) Validate() error {
+	return vd.ValidateStruct(&r,
+		vd.Field(&r.Event, vd.Required, utils.IsValidBotEvent),
+	)
+}
+
+// TestBotEvent POST /bots/:botID/actions/test-event
+func (h *Handlers) TestBotEvent(c echo.Context) error {
+	var req PostBotActionTestEventRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	b := getParamBot(c)
+	if b.Mode == model.BotModeHTTP && len(b.PostURL) == 0 {
+		return herror.BadRequest("this bot has no endpoint")
+	}
+
+	log, resBody, err := h.BOT.TestEvent(b, req.Event, getRequestUser(c))
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+
+	return c.JSON(http.StatusOK, formatBotTestEventResult(log, resBody))
+}
+
 // PostBotActionJoinRequest POST /bots/:botID/actions/join リクエストボディ
 type PostBotActionJoinRequest struct {
 	ChannelID uuid.UUID `json:"channelId"`
@@ -0,0 +1,94 @@
+package v3
+
+import (
+	vd "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/gofrs/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/router/consts"
+	"github.com/traPtitech/traQ/router/extension/herror"
+	"github.com/traPtitech/traQ/service/channel"
+	"net/http"
+)
+
+// GetChannelMembers GET /channels/:channelID/members
+func (h *Handlers) GetChannelMembers(c echo.Context) error {
+	ch := getParamChannel(c)
+
+	if !ch.IsPrivateChannel() {
+		return herror.BadRequest("this channel is not a private channel")
+	}
+
+	members, err := h.ChannelManager.GetPrivateChannelMembers(ch.ID)
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+	return c.JSON(http.StatusOK, members)
+}
+
+// PostChannelMemberRequest POST /channels/:channelID/members リクエストボディ
+type PostChannelMemberRequest struct {
+	UserID uuid.UUID `json:"userId"`
+}
+
+func (r PostChannelMemberRequest) Validate() error {
+	return vd.ValidateStruct(&r,
+		vd.Field(&r.UserID, vd.Required),
+	)
+}
+
+// AddChannelMember POST /channels/:channelID/members
+func (h *Handlers) AddChannelMember(c echo.Context) error {
+	ch := getParamChannel(c)
+
+	var req PostChannelMemberRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	// ユーザー存在確認
+	if _, err := h.Repo.GetUser(req.UserID, false); err != nil {
+		switch err {
+		case repository.ErrNotFound:
+			return herror.BadRequest("this user doesn't exist")
+		default:
+			return herror.InternalServerError(err)
+		}
+	}
+
+	if err := h.ChannelManager.AddPrivateChannelMember(ch.ID, req.UserID, getRequestUserID(c)); err != nil {
+		switch err {
+		case channel.ErrInvalidChannel:
+			return herror.BadRequest("this channel is not a private channel")
+		case channel.ErrAlreadyMember:
+			return herror.Conflict("this user is already a member")
+		default:
+			return herror.InternalServerError(err)
+		}
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// RemoveChannelMember DELETE /channels/:channelID/members/:userID
+func (h *Handlers) RemoveChannelMember(c echo.Context) error {
+	ch := getParamChannel(c)
+	userID := getRequestUserID(c)
+	memberID := getParamAsUUID(c, consts.ParamUserID)
+
+	// 自分自身の退出以外はチャンネル作成者のみ
+	if memberID != userID && ch.CreatorID != userID {
+		return herror.Forbidden("only the channel creator can remove other members")
+	}
+
+	if err := h.ChannelManager.RemovePrivateChannelMember(ch.ID, memberID, userID); err != nil {
+		switch err {
+		case channel.ErrInvalidChannel:
+			return herror.BadRequest("this channel is not a private channel")
+		case channel.ErrNotMember:
+			return herror.NotFound()
+		default:
+			return herror.InternalServerError(err)
+		}
+	}
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,84 @@
+package v3
+
+import (
+	vd "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/labstack/echo/v4"
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/router/consts"
+	"github.com/traPtitech/traQ/router/extension/herror"
+	"github.com/traPtitech/traQ/service/rbac/role"
+	"net/http"
+)
+
+// GetChannelRoles GET /channels/:channelID/roles
+func (h *Handlers) GetChannelRoles(c echo.Context) error {
+	ch := getParamChannel(c)
+
+	roles, err := h.Repo.GetChannelRoles(ch.ID)
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+	return c.JSON(http.StatusOK, formatChannelRoles(roles))
+}
+
+// PutChannelRoleRequest PUT /channels/:channelID/roles/:userID リクエストボディ
+type PutChannelRoleRequest struct {
+	Role string `json:"role"`
+}
+
+func (r PutChannelRoleRequest) Validate() error {
+	return vd.ValidateStruct(&r,
+		vd.Field(&r.Role, vd.Required, vd.In(role.ChannelOwner, role.ChannelModerator)),
+	)
+}
+
+// SetChannelRole PUT /channels/:channelID/roles/:userID
+func (h *Handlers) SetChannelRole(c echo.Context) error {
+	ch := getParamChannel(c)
+	userID := getParamAsUUID(c, consts.ParamUserID)
+
+	var req PutChannelRoleRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	if !ch.IsPublic {
+		return herror.BadRequest("channel roles can be set only in public channels")
+	}
+
+	// ユーザー存在確認
+	if _, err := h.Repo.GetUser(userID, false); err != nil {
+		switch err {
+		case repository.ErrNotFound:
+			return herror.BadRequest("this user doesn't exist")
+		default:
+			return herror.InternalServerError(err)
+		}
+	}
+
+	if err := h.Repo.SetChannelRole(ch.ID, userID, req.Role); err != nil {
+		switch err {
+		case repository.ErrNilID:
+			return herror.BadRequest("this user doesn't exist")
+		default:
+			return herror.InternalServerError(err)
+		}
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// DeleteChannelRole DELETE /channels/:channelID/roles/:userID
+func (h *Handlers) DeleteChannelRole(c echo.Context) error {
+	ch := getParamChannel(c)
+	userID := getParamAsUUID(c, consts.ParamUserID)
+
+	if err := h.Repo.DeleteChannelRole(ch.ID, userID); err != nil {
+		switch err {
+		case repository.ErrNotFound, repository.ErrNilID:
+			return herror.NotFound()
+		default:
+			return herror.InternalServerError(err)
+		}
+	}
+	return c.NoContent(http.StatusNoContent)
+}
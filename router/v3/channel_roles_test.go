@@ -0,0 +1,104 @@
+package v3
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/router/session"
+	"github.com/traPtitech/traQ/service/rbac/permission"
+	"github.com/traPtitech/traQ/service/rbac/role"
+	"github.com/traPtitech/traQ/utils/optional"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// createChannelModerator 読み取り専用ユーザーを作成し、指定したチャンネルのモデレーターにします
+func createChannelModerator(t *testing.T, env *Env, channelID uuid.UUID) model.UserInfo {
+	t.Helper()
+	user := env.CreateUser(t, rand)
+	require.NoError(t, env.Repository.UpdateUser(user.GetID(), repository.UpdateUserArgs{Role: optional.StringFrom(role.Read)}))
+	require.NoError(t, env.Repository.SetChannelRole(channelID, user.GetID(), role.ChannelModerator))
+	require.Eventually(t, func() bool {
+		return env.RBAC.IsChannelGranted(user.GetID(), channelID, permission.EditChannelTopic)
+	}, time.Second, 10*time.Millisecond)
+	return user
+}
+
+func TestHandlers_ChannelModerator(t *testing.T) {
+	t.Parallel()
+	env := Setup(t, common)
+
+	t.Run("edit topic", func(t *testing.T) {
+		t.Parallel()
+		ch := env.CreateChannel(t, rand)
+		other := env.CreateChannel(t, rand)
+		s := env.S(t, createChannelModerator(t, env, ch.ID).GetID())
+		e := env.R(t)
+
+		e.PUT("/api/v3/channels/{channelId}/topic", other.ID).
+			WithCookie(session.CookieName, s).
+			WithJSON(echo.Map{"topic": "topic"}).
+			Expect().
+			Status(http.StatusForbidden)
+		e.PUT("/api/v3/channels/{channelId}/topic", ch.ID).
+			WithCookie(session.CookieName, s).
+			WithJSON(echo.Map{"topic": "topic"}).
+			Expect().
+			Status(http.StatusNoContent)
+	})
+
+	t.Run("pin", func(t *testing.T) {
+		t.Parallel()
+		ch := env.CreateChannel(t, rand)
+		other := env.CreateChannel(t, rand)
+		poster := env.CreateUser(t, rand)
+		s := env.S(t, createChannelModerator(t, env, ch.ID).GetID())
+		e := env.R(t)
+
+		otherMessage, err := env.Repository.CreateMessage(poster.GetID(), other.ID, "message")
+		require.NoError(t, err)
+		e.POST("/api/v3/messages/{messageId}/pin", otherMessage.ID).
+			WithCookie(session.CookieName, s).
+			Expect().
+			Status(http.StatusForbidden)
+
+		m, err := env.Repository.CreateMessage(poster.GetID(), ch.ID, "message")
+		require.NoError(t, err)
+		e.POST("/api/v3/messages/{messageId}/pin", m.ID).
+			WithCookie(session.CookieName, s).
+			Expect().
+			Status(http.StatusCreated)
+		e.DELETE("/api/v3/messages/{messageId}/pin", m.ID).
+			WithCookie(session.CookieName, s).
+			Expect().
+			Status(http.StatusNoContent)
+	})
+
+	t.Run("edit subscribers", func(t *testing.T) {
+		t.Parallel()
+		ch := env.CreateChannel(t, rand)
+		other := env.CreateChannel(t, rand)
+		target := env.CreateUser(t, rand)
+		s := env.S(t, createChannelModerator(t, env, ch.ID).GetID())
+		e := env.R(t)
+
+		e.PATCH("/api/v3/channels/{channelId}/subscribers", other.ID).
+			WithCookie(session.CookieName, s).
+			WithJSON(echo.Map{"on": []uuid.UUID{target.GetID()}}).
+			Expect().
+			Status(http.StatusForbidden)
+		e.PATCH("/api/v3/channels/{channelId}/subscribers", ch.ID).
+			WithCookie(session.CookieName, s).
+			WithJSON(echo.Map{"on": []uuid.UUID{target.GetID()}}).
+			Expect().
+			Status(http.StatusNoContent)
+		e.PUT("/api/v3/channels/{channelId}/subscribers", ch.ID).
+			WithCookie(session.CookieName, s).
+			WithJSON(echo.Map{"on": []uuid.UUID{}}).
+			Expect().
+			Status(http.StatusNoContent)
+	})
+}
@@ -32,18 +32,29 @@ func (h *Handlers) GetChannels(c echo.Context) error {
 		res["dm"] = formatDMChannels(mapping)
 	}
 
+	if isTrue(c.QueryParam("include-private")) {
+		channels, err := h.ChannelManager.GetUserPrivateChannels(getRequestUserID(c))
+		if err != nil {
+			return herror.InternalServerError(err)
+		}
+		res["private"] = formatChannels(channels)
+	}
+
 	return c.JSON(http.StatusOK, res)
 }
 
 // PostChannelRequest POST /channels リクエストボディ
 type PostChannelRequest struct {
-	Name   string        `json:"name"`
-	Parent optional.UUID `json:"parent"`
+	Name    string        `json:"name"`
+	Parent  optional.UUID `json:"parent"`
+	Private bool          `json:"private"`
+	Members []uuid.UUID   `json:"members"`
 }
 
 func (r PostChannelRequest) Validate() error {
 	return vd.ValidateStruct(&r,
 		vd.Field(&r.Name, validator.ChannelNameRuleRequired...),
+		vd.Field(&r.Members, vd.When(!r.Private, vd.Empty.Error("members can be specified only for private channels")), vd.Length(0, 100)),
 	)
 }
 
@@ -56,6 +67,10 @@ func (h *Handlers) CreateChannels(c echo.Context) error {
 		return err
 	}
 
+	if req.Private {
+		return h.createPrivateChannel(c, userID, req)
+	}
+
 	ch, err := h.ChannelManager.CreatePublicChannel(req.Name, req.Parent.UUID, userID)
 	if err != nil {
 		switch err {
@@ -77,6 +92,39 @@ func (h *Handlers) CreateChannels(c echo.Context) error {
 	return c.JSON(http.StatusCreated, formatChannel(ch, make([]uuid.UUID, 0)))
 }
 
+func (h *Handlers) createPrivateChannel(c echo.Context, userID uuid.UUID, req PostChannelRequest) error {
+	if req.Parent.UUID != uuid.Nil {
+		return herror.BadRequest("private channels cannot have parent")
+	}
+
+	// メンバー存在確認
+	members := set.UUIDSetFromArray(req.Members)
+	for id := range members {
+		if _, err := h.Repo.GetUser(id, false); err != nil {
+			switch err {
+			case repository.ErrNotFound:
+				return herror.BadRequest("invalid member: " + id.String())
+			default:
+				return herror.InternalServerError(err)
+			}
+		}
+	}
+
+	ch, err := h.ChannelManager.CreatePrivateChannel(req.Name, userID, members)
+	if err != nil {
+		switch err {
+		case channel.ErrInvalidChannelName:
+			return herror.BadRequest("invalid channel name")
+		case channel.ErrChannelNameConflicts:
+			return herror.Conflict("channel name conflicts")
+		default:
+			return herror.InternalServerError(err)
+		}
+	}
+
+	return c.JSON(http.StatusCreated, formatChannel(ch, make([]uuid.UUID, 0)))
+}
+
 // GetChannel GET /channels/:channelID
 func (h *Handlers) GetChannel(c echo.Context) error {
 	ch := getParamChannel(c)
@@ -121,6 +169,8 @@ func (h *Handlers) EditChannel(c echo.Context) error {
 			return herror.BadRequest("invalid parent channel")
 		case channel.ErrTooDeepChannel:
 			return herror.BadRequest("channel depth limit exceeded")
+		case channel.ErrInvalidChannel:
+			return herror.BadRequest("parent and force cannot be changed for this channel")
 		case channel.ErrChannelNameConflicts:
 			return herror.Conflict("channel name conflicts")
 		default:
@@ -130,6 +180,60 @@ func (h *Handlers) EditChannel(c echo.Context) error {
 	return c.NoContent(http.StatusNoContent)
 }
 
+// DeleteChannel DELETE /channels/:channelID
+func (h *Handlers) DeleteChannel(c echo.Context) error {
+	channelID := getParamAsUUID(c, consts.ParamChannelID)
+
+	if err := h.ChannelManager.DeleteChannel(channelID, getRequestUserID(c)); err != nil {
+		switch err {
+		case channel.ErrChannelNotFound:
+			return herror.BadRequest("this channel cannot be deleted")
+		case channel.ErrChannelNotEmpty:
+			return herror.Conflict("the channel has messages or child channels")
+		default:
+			return herror.InternalServerError(err)
+		}
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// PostChannelMergeRequest POST /channels/:channelID/merge リクエストボディ
+type PostChannelMergeRequest struct {
+	Into uuid.UUID `json:"into"`
+}
+
+func (r PostChannelMergeRequest) Validate() error {
+	return vd.ValidateStruct(&r,
+		vd.Field(&r.Into, vd.Required, validator.NotNilUUID),
+	)
+}
+
+// MergeChannel POST /channels/:channelID/merge
+func (h *Handlers) MergeChannel(c echo.Context) error {
+	channelID := getParamAsUUID(c, consts.ParamChannelID)
+
+	var req PostChannelMergeRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	if err := h.ChannelManager.MergeChannel(channelID, req.Into, getRequestUserID(c)); err != nil {
+		switch err {
+		case channel.ErrChannelNotFound:
+			return herror.BadRequest("only public channels can be merged")
+		case channel.ErrInvalidChannel:
+			return herror.BadRequest("cannot merge a channel into itself or its descendant")
+		case channel.ErrTooDeepChannel:
+			return herror.BadRequest("channel depth limit exceeded")
+		case channel.ErrChannelNameConflicts:
+			return herror.Conflict("child channel name conflicts")
+		default:
+			return herror.InternalServerError(err)
+		}
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
 // GetChannelViewers GET /channels/:channelID/viewers
 func (h *Handlers) GetChannelViewers(c echo.Context) error {
 	channelID := getParamAsUUID(c, consts.ParamChannelID)
@@ -0,0 +1,116 @@
+package v3
+
+import (
+	vd "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/labstack/echo/v4"
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/router/extension/herror"
+	"github.com/traPtitech/traQ/utils/optional"
+	"net/http"
+)
+
+// GetMyDigestSetting GET /users/me/digest-settings
+func (h *Handlers) GetMyDigestSetting(c echo.Context) error {
+	userID := getRequestUserID(c)
+
+	s, err := h.Repo.GetUserDigestSetting(userID)
+	if err != nil {
+		switch err {
+		case repository.ErrNotFound:
+			return c.JSON(http.StatusOK, &DigestSetting{Frequency: model.DigestFrequencyNone})
+		default:
+			return herror.InternalServerError(err)
+		}
+	}
+
+	return c.JSON(http.StatusOK, formatDigestSetting(s))
+}
+
+// PatchMyDigestSettingRequest PATCH /users/me/digest-settings リクエストボディ
+type PatchMyDigestSettingRequest struct {
+	Email     optional.String `json:"email"`
+	Frequency optional.String `json:"frequency"`
+}
+
+func (r PatchMyDigestSettingRequest) Validate() error {
+	return vd.ValidateStruct(&r,
+		vd.Field(&r.Email, vd.RuneLength(0, 254), is.EmailFormat),
+		vd.Field(&r.Frequency, vd.In(
+			model.DigestFrequencyNone.String(),
+			model.DigestFrequencyDaily.String(),
+			model.DigestFrequencyWeekly.String(),
+		)),
+	)
+}
+
+// EditMyDigestSetting PATCH /users/me/digest-settings
+func (h *Handlers) EditMyDigestSetting(c echo.Context) error {
+	var req PatchMyDigestSettingRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	userID := getRequestUserID(c)
+	args := repository.UpdateUserDigestSettingArgs{
+		Email:     req.Email,
+		Frequency: req.Frequency,
+	}
+	if err := h.Repo.UpdateUserDigestSetting(userID, args); err != nil {
+		switch {
+		case repository.IsArgError(err):
+			return herror.BadRequest(err)
+		default:
+			return herror.InternalServerError(err)
+		}
+	}
+
+	// メールアドレスが指定された場合、未確認であれば確認メールを(再)送信する
+	if req.Email.Valid {
+		s, err := h.Repo.GetUserDigestSetting(userID)
+		if err != nil {
+			return herror.InternalServerError(err)
+		}
+		if err := h.Digest.SendVerificationMail(s); err != nil {
+			return herror.InternalServerError(err)
+		}
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// VerifyDigestEmail GET /public/digest/verify
+//
+// ダイジェストメールの送信先アドレスの確認メールに記載されるリンクです。
+func (h *Handlers) VerifyDigestEmail(c echo.Context) error {
+	if err := h.Repo.VerifyUserDigestEmail(c.QueryParam("token")); err != nil {
+		switch err {
+		case repository.ErrNotFound:
+			return herror.NotFound()
+		default:
+			return herror.InternalServerError(err)
+		}
+	}
+
+	return c.String(http.StatusOK, "メールアドレスを確認しました。ダイジェストメールの配信が始まります。")
+}
+
+// UnsubscribeDigest GET, POST /public/digest/unsubscribe
+//
+// ダイジェストメールの配信停止リンク、及びList-Unsubscribe-Post(RFC 8058)のワンクリック配信停止に対応します。
+func (h *Handlers) UnsubscribeDigest(c echo.Context) error {
+	if err := h.Repo.UnsubscribeUserDigest(c.QueryParam("token")); err != nil {
+		switch err {
+		case repository.ErrNotFound:
+			return herror.NotFound()
+		default:
+			return herror.InternalServerError(err)
+		}
+	}
+
+	if c.Request().Method == http.MethodPost {
+		return c.NoContent(http.StatusNoContent)
+	}
+	return c.String(http.StatusOK, "ダイジェストメールの配信を停止しました。")
+}
@@ -0,0 +1,148 @@
+package v3
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/router/session"
+	"github.com/traPtitech/traQ/utils/optional"
+	"net/http"
+	"testing"
+)
+
+func TestHandlers_EditMyDigestSetting(t *testing.T) {
+	t.Parallel()
+	path := "/api/v3/users/me/digest-settings"
+	env := Setup(t, common)
+
+	t.Run("NotLoggedIn", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.PATCH(path).
+			WithJSON(echo.Map{"frequency": "daily"}).
+			Expect().
+			Status(http.StatusUnauthorized)
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		t.Parallel()
+		s := env.S(t, env.CreateUser(t, rand).GetID())
+		e := env.R(t)
+		e.PATCH(path).
+			WithCookie(session.CookieName, s).
+			WithJSON(echo.Map{"email": "invalid", "frequency": "daily"}).
+			Expect().
+			Status(http.StatusBadRequest)
+		e.PATCH(path).
+			WithCookie(session.CookieName, s).
+			WithJSON(echo.Map{"email": "test@example.com", "frequency": "hourly"}).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	t.Run("no email", func(t *testing.T) {
+		t.Parallel()
+		s := env.S(t, env.CreateUser(t, rand).GetID())
+		e := env.R(t)
+		e.PATCH(path).
+			WithCookie(session.CookieName, s).
+			WithJSON(echo.Map{"frequency": "daily"}).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		user := env.CreateUser(t, rand)
+		s := env.S(t, user.GetID())
+		e := env.R(t)
+
+		obj := e.GET(path).
+			WithCookie(session.CookieName, s).
+			Expect().
+			Status(http.StatusOK).
+			JSON().
+			Object()
+		obj.Value("email").String().Empty()
+		obj.Value("frequency").String().Equal("none")
+
+		e.PATCH(path).
+			WithCookie(session.CookieName, s).
+			WithJSON(echo.Map{"email": "test@example.com", "frequency": "weekly"}).
+			Expect().
+			Status(http.StatusNoContent)
+
+		obj = e.GET(path).
+			WithCookie(session.CookieName, s).
+			Expect().
+			Status(http.StatusOK).
+			JSON().
+			Object()
+		obj.Value("email").String().Equal("test@example.com")
+		obj.Value("emailVerified").Boolean().False()
+		obj.Value("frequency").String().Equal("weekly")
+	})
+}
+
+func TestHandlers_VerifyDigestEmail(t *testing.T) {
+	t.Parallel()
+	path := "/api/v3/public/digest/verify"
+	env := Setup(t, common)
+
+	user := env.CreateUser(t, rand)
+	require.NoError(t, env.Repository.UpdateUserDigestSetting(user.GetID(), repository.UpdateUserDigestSettingArgs{
+		Email:     optional.StringFrom("test@example.com"),
+		Frequency: optional.StringFrom("daily"),
+	}))
+	setting, err := env.Repository.GetUserDigestSetting(user.GetID())
+	require.NoError(t, err)
+
+	e := env.R(t)
+	e.GET(path).
+		WithQuery("token", "invalid").
+		Expect().
+		Status(http.StatusNotFound)
+	e.GET(path).
+		WithQuery("token", setting.VerificationToken).
+		Expect().
+		Status(http.StatusOK)
+
+	setting, err = env.Repository.GetUserDigestSetting(user.GetID())
+	require.NoError(t, err)
+	assert.True(t, setting.IsEmailVerified())
+}
+
+func TestHandlers_UnsubscribeDigest(t *testing.T) {
+	t.Parallel()
+	path := "/api/v3/public/digest/unsubscribe"
+	env := Setup(t, common)
+
+	user := env.CreateUser(t, rand)
+	require.NoError(t, env.Repository.UpdateUserDigestSetting(user.GetID(), repository.UpdateUserDigestSettingArgs{
+		Email:     optional.StringFrom("test@example.com"),
+		Frequency: optional.StringFrom("daily"),
+	}))
+	setting, err := env.Repository.GetUserDigestSetting(user.GetID())
+	require.NoError(t, err)
+
+	e := env.R(t)
+	e.GET(path).
+		WithQuery("token", "invalid").
+		Expect().
+		Status(http.StatusNotFound)
+
+	e.POST(path).
+		WithQuery("token", setting.UnsubscribeToken).
+		Expect().
+		Status(http.StatusNoContent)
+	e.GET(path).
+		WithQuery("token", setting.UnsubscribeToken).
+		Expect().
+		Status(http.StatusOK)
+
+	setting, err = env.Repository.GetUserDigestSetting(user.GetID())
+	require.NoError(t, err)
+	assert.Equal(t, model.DigestFrequencyNone, setting.Frequency)
+}
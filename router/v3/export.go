@@ -0,0 +1,77 @@
+package v3
+
+import (
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/router/extension/herror"
+	"github.com/traPtitech/traQ/service/export"
+	"github.com/traPtitech/traQ/utils/optional"
+	"go.uber.org/zap"
+	"net/http"
+	"time"
+)
+
+// exportQuery エクスポート用クエリ
+type exportQuery struct {
+	User         optional.UUID `query:"user"`
+	IncludeFiles bool          `query:"includeFiles"`
+	Since        optional.Time `query:"since"`
+	Until        optional.Time `query:"until"`
+}
+
+func (q *exportQuery) options() export.Options {
+	return export.Options{
+		UserID:       q.User,
+		IncludeFiles: q.IncludeFiles,
+		Since:        q.Since,
+		Until:        q.Until,
+	}
+}
+
+// ExportData GET /export
+func (h *Handlers) ExportData(c echo.Context) error {
+	var q exportQuery
+	if err := bindAndValidate(c, &q); err != nil {
+		return err
+	}
+	if q.User.Valid {
+		if _, err := h.Repo.GetUser(q.User.UUID, false); err != nil {
+			switch err {
+			case repository.ErrNotFound:
+				return herror.BadRequest("invalid user")
+			default:
+				return herror.InternalServerError(err)
+			}
+		}
+	}
+	return h.streamExport(c, q.options())
+}
+
+// ExportMyData GET /users/me/export
+func (h *Handlers) ExportMyData(c echo.Context) error {
+	var q exportQuery
+	if err := bindAndValidate(c, &q); err != nil {
+		return err
+	}
+	opts := q.options()
+	opts.UserID = optional.UUIDFrom(getRequestUserID(c)) // 自分のデータのみ
+	return h.streamExport(c, opts)
+}
+
+func (h *Handlers) streamExport(c echo.Context, opts export.Options) error {
+	if err := opts.Validate(); err != nil {
+		return herror.BadRequest("since must be before until")
+	}
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "application/zip")
+	res.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=traq-export-%s.zip", time.Now().Format("20060102150405")))
+	res.WriteHeader(http.StatusOK)
+
+	// ヘッダー送信後なのでエラーレスポンスは返せない
+	if err := h.Exporter.Export(res, opts); err != nil {
+		h.Logger.Error("failed to export data", zap.Error(err), zap.Stringer("userId", opts.UserID.UUID))
+	}
+	return nil
+}
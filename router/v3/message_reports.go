@@ -0,0 +1,199 @@
+package v3
+
+import (
+	vd "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/gofrs/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/router/consts"
+	"github.com/traPtitech/traQ/router/extension/herror"
+	"github.com/traPtitech/traQ/utils/optional"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// PostMessageReportRequest POST /messages/:messageID/reports リクエストボディ
+type PostMessageReportRequest struct {
+	Reason string `json:"reason"`
+}
+
+func (r PostMessageReportRequest) Validate() error {
+	return vd.ValidateStruct(&r,
+		vd.Field(&r.Reason, vd.Required, vd.RuneLength(1, 1000)),
+	)
+}
+
+// PostMessageReport POST /messages/:messageID/reports
+func (h *Handlers) PostMessageReport(c echo.Context) error {
+	userID := getRequestUserID(c)
+	m := getParamMessage(c)
+
+	var req PostMessageReportRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	if err := h.Repo.CreateMessageReport(m.ID, userID, req.Reason); err != nil {
+		switch err {
+		case repository.ErrAlreadyExists:
+			return herror.Conflict("already reported")
+		default:
+			return herror.InternalServerError(err)
+		}
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GetMessageReportsRequest GET /message-reports 用リクエストクエリ
+type GetMessageReportsRequest struct {
+	Limit      int           `query:"limit"`
+	Offset     int           `query:"offset"`
+	Since      optional.Time `query:"since"`
+	Until      optional.Time `query:"until"`
+	Inclusive  bool          `query:"inclusive"`
+	Order      string        `query:"order"`
+	Status     string        `query:"status"`
+	MessageID  uuid.UUID     `query:"messageId"`
+	ReporterID uuid.UUID     `query:"reporterId"`
+}
+
+func (q *GetMessageReportsRequest) Validate() error {
+	if q.Limit == 0 {
+		q.Limit = 20
+	}
+	return vd.ValidateStruct(q,
+		vd.Field(&q.Limit, vd.Min(1), vd.Max(200)),
+		vd.Field(&q.Offset, vd.Min(0)),
+		vd.Field(&q.Status, vd.In(model.MessageReportStatusOpen, model.MessageReportStatusResolved, model.MessageReportStatusDismissed)),
+	)
+}
+
+// GetMessageReports GET /message-reports
+func (h *Handlers) GetMessageReports(c echo.Context) error {
+	var req GetMessageReportsRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	reports, more, err := h.Repo.GetMessageReports(repository.MessageReportsQuery{
+		Message:   req.MessageID,
+		Reporter:  req.ReporterID,
+		Status:    req.Status,
+		Since:     req.Since,
+		Until:     req.Until,
+		Inclusive: req.Inclusive,
+		Limit:     req.Limit,
+		Offset:    req.Offset,
+		Asc:       strings.ToLower(req.Order) == "asc",
+	})
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+	c.Response().Header().Set(consts.HeaderMore, strconv.FormatBool(more))
+	return c.JSON(http.StatusOK, formatMessageReports(reports))
+}
+
+// GetMessageReport GET /message-reports/:messageReportID
+func (h *Handlers) GetMessageReport(c echo.Context) error {
+	return c.JSON(http.StatusOK, formatMessageReport(getParamMessageReport(c)))
+}
+
+// PostResolveMessageReportRequest POST /message-reports/:messageReportID/resolve リクエストボディ
+type PostResolveMessageReportRequest struct {
+	Action string `json:"action"`
+	Note   string `json:"note"`
+}
+
+func (r PostResolveMessageReportRequest) Validate() error {
+	return vd.ValidateStruct(&r,
+		vd.Field(&r.Action, vd.In(model.MessageReportActionNone, model.MessageReportActionHide, model.MessageReportActionDelete)),
+		vd.Field(&r.Note, vd.RuneLength(0, 1000)),
+	)
+}
+
+// ResolveMessageReport POST /message-reports/:messageReportID/resolve
+func (h *Handlers) ResolveMessageReport(c echo.Context) error {
+	userID := getRequestUserID(c)
+	r := getParamMessageReport(c)
+
+	var req PostResolveMessageReportRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+	if len(req.Action) == 0 {
+		req.Action = model.MessageReportActionNone
+	}
+
+	// 先に通報を対応済みにする。同時に対応された場合や対応済みの場合はここで弾かれ、メッセージは操作されない
+	if err := h.Repo.HandleMessageReport(r.ID, repository.HandleMessageReportArgs{
+		HandlerID: userID,
+		Status:    model.MessageReportStatusResolved,
+		Action:    req.Action,
+		Note:      req.Note,
+	}); err != nil {
+		return handleMessageReportError(err)
+	}
+
+	// メッセージへの操作 既に削除されている場合は無視
+	var err error
+	switch req.Action {
+	case model.MessageReportActionHide:
+		err = h.Repo.SetMessageHidden(r.MessageID, true)
+	case model.MessageReportActionDelete:
+		err = h.Repo.DeleteMessage(r.MessageID)
+	}
+	if err != nil && err != repository.ErrNotFound {
+		return herror.InternalServerError(err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// PostDismissMessageReportRequest POST /message-reports/:messageReportID/dismiss リクエストボディ
+type PostDismissMessageReportRequest struct {
+	Note string `json:"note"`
+}
+
+func (r PostDismissMessageReportRequest) Validate() error {
+	return vd.ValidateStruct(&r,
+		vd.Field(&r.Note, vd.RuneLength(0, 1000)),
+	)
+}
+
+// DismissMessageReport POST /message-reports/:messageReportID/dismiss
+func (h *Handlers) DismissMessageReport(c echo.Context) error {
+	userID := getRequestUserID(c)
+	r := getParamMessageReport(c)
+
+	var req PostDismissMessageReportRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	return h.handleMessageReport(c, r.ID, repository.HandleMessageReportArgs{
+		HandlerID: userID,
+		Status:    model.MessageReportStatusDismissed,
+		Action:    model.MessageReportActionNone,
+		Note:      req.Note,
+	})
+}
+
+func (h *Handlers) handleMessageReport(c echo.Context, reportID uuid.UUID, args repository.HandleMessageReportArgs) error {
+	if err := h.Repo.HandleMessageReport(reportID, args); err != nil {
+		return handleMessageReportError(err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func handleMessageReportError(err error) error {
+	switch err {
+	case repository.ErrNotFound:
+		return herror.NotFound()
+	case repository.ErrForbidden:
+		return herror.Conflict("this report has already been handled")
+	default:
+		return herror.InternalServerError(err)
+	}
+}
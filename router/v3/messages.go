@@ -3,12 +3,20 @@ package v3
 import (
 	"fmt"
 	vd "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/gofrs/uuid"
 	"github.com/labstack/echo/v4"
+	"github.com/leandro-lugaresi/hub"
+	"github.com/traPtitech/traQ/event"
 	"github.com/traPtitech/traQ/model"
 	"github.com/traPtitech/traQ/repository"
 	"github.com/traPtitech/traQ/router/consts"
 	"github.com/traPtitech/traQ/router/extension/herror"
+	"github.com/traPtitech/traQ/service/rbac/permission"
+	"github.com/traPtitech/traQ/service/search"
+	"github.com/traPtitech/traQ/utils/optional"
 	"net/http"
+	"strings"
+	"unicode"
 )
 
 // GetMyUnreadChannels GET /users/me/unread
@@ -42,16 +50,87 @@ func (h *Handlers) GetMessage(c echo.Context) error {
 
 // PostMessageRequest POST /channels/:channelID/messages等リクエストボディ
 type PostMessageRequest struct {
-	Content string `json:"content"`
-	Embed   bool   `json:"embed" query:"embed"`
+	Content    string                  `json:"content"`
+	Embed      bool                    `json:"embed" query:"embed"`
+	Components model.MessageComponents `json:"components"`
 }
 
 func (r PostMessageRequest) Validate() error {
 	return vd.ValidateStruct(&r,
 		vd.Field(&r.Content, vd.Required, vd.RuneLength(1, 10000)),
+		vd.Field(&r.Components, vd.Length(0, 25), vd.By(validateMessageComponents)),
 	)
 }
 
+// validateMessageComponents メッセージのインタラクティブコンポーネントのバリデーション
+func validateMessageComponents(value interface{}) error {
+	components, _ := value.(model.MessageComponents)
+	ids := make(map[string]bool, len(components))
+	for _, c := range components {
+		if err := vd.ValidateStruct(&c,
+			vd.Field(&c.ID, vd.Required, vd.RuneLength(1, 64)),
+			vd.Field(&c.Type, vd.Required, vd.In(model.MessageComponentTypeButton, model.MessageComponentTypeSelect)),
+			vd.Field(&c.Label, vd.Required, vd.RuneLength(1, 80)),
+			vd.Field(&c.Style, vd.In("primary", "danger"), vd.When(c.Type != model.MessageComponentTypeButton, vd.Empty)),
+			vd.Field(&c.Options,
+				vd.When(c.Type == model.MessageComponentTypeSelect, vd.Required, vd.Length(1, 25)).Else(vd.Empty),
+				vd.Each(vd.By(func(value interface{}) error {
+					o := value.(model.MessageComponentOption)
+					return vd.ValidateStruct(&o,
+						vd.Field(&o.Label, vd.Required, vd.RuneLength(1, 80)),
+						vd.Field(&o.Value, vd.Required, vd.RuneLength(1, 100)),
+					)
+				})),
+			),
+		); err != nil {
+			return fmt.Errorf("component %s: %w", c.ID, err)
+		}
+		if ids[c.ID] {
+			return fmt.Errorf("duplicated component id: %s", c.ID)
+		}
+		ids[c.ID] = true
+
+		values := make(map[string]bool, len(c.Options))
+		for _, o := range c.Options {
+			if values[o.Value] {
+				return fmt.Errorf("component %s: duplicated option value: %s", c.ID, o.Value)
+			}
+			values[o.Value] = true
+		}
+		if c.Confirm != nil {
+			if err := vd.ValidateStruct(c.Confirm,
+				vd.Field(&c.Confirm.Title, vd.Required, vd.RuneLength(1, 80)),
+				vd.Field(&c.Confirm.Text, vd.Required, vd.RuneLength(1, 1000)),
+				vd.Field(&c.Confirm.OK, vd.RuneLength(0, 30)),
+				vd.Field(&c.Confirm.Cancel, vd.RuneLength(0, 30)),
+			); err != nil {
+				return fmt.Errorf("component %s: confirm: %w", c.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// checkMessageComponentsAttachable リクエストユーザーがメッセージにコンポーネントを添付できるかどうかを確認します
+func checkMessageComponentsAttachable(c echo.Context, components model.MessageComponents) error {
+	if len(components) > 0 && !getRequestUser(c).IsBot() {
+		return herror.BadRequest("only bots can attach components to messages")
+	}
+	return nil
+}
+
+// attachMessageComponents 投稿したメッセージにコンポーネントを添付します
+func (h *Handlers) attachMessageComponents(m *model.Message, components model.MessageComponents) error {
+	if len(components) == 0 {
+		return nil
+	}
+	if err := h.Repo.SetMessageComponents(m.ID, components); err != nil {
+		return err
+	}
+	m.Components = &model.MessageComponentSet{MessageID: m.ID, Components: components}
+	return nil
+}
+
 // EditMessage PUT /messages/:messageID
 func (h *Handlers) EditMessage(c echo.Context) error {
 	userID := getRequestUserID(c)
@@ -75,6 +154,9 @@ func (h *Handlers) EditMessage(c echo.Context) error {
 	if userID != m.UserID {
 		return herror.Forbidden("This is not your message")
 	}
+	if err := checkMessageComponentsAttachable(c, req.Components); err != nil {
+		return err
+	}
 
 	if req.Embed {
 		req.Content = h.Replacer.Replace(req.Content)
@@ -83,16 +165,26 @@ func (h *Handlers) EditMessage(c echo.Context) error {
 	if err := h.Repo.UpdateMessage(m.ID, req.Content); err != nil {
 		return herror.InternalServerError(err)
 	}
+	// componentsが指定された場合のみ置き換える
+	if req.Components != nil {
+		if err := h.Repo.SetMessageComponents(m.ID, req.Components); err != nil {
+			return herror.InternalServerError(err)
+		}
+	}
 
 	return c.NoContent(http.StatusNoContent)
 }
 
 // DeleteMessage DELETE /messages/:messageID
 func (h *Handlers) DeleteMessage(c echo.Context) error {
-	userID := getRequestUserID(c)
+	user := getRequestUser(c)
+	userID := user.GetID()
 	m := getParamMessage(c)
 
-	if m.UserID != userID {
+	// 他人のメッセージの削除権限 (グローバルロールもしくはチャンネルロール)
+	canDeleteOthers := h.RBAC.IsGranted(user.GetRole(), permission.DeleteOthersMessage) || h.RBAC.IsChannelGranted(userID, m.ChannelID, permission.DeleteOthersMessage)
+
+	if m.UserID != userID && !canDeleteOthers {
 		mUser, err := h.Repo.GetUser(m.UserID, false)
 		if err != nil {
 			return herror.InternalServerError(err)
@@ -278,6 +370,21 @@ func (h *Handlers) PostMessage(c echo.Context) error {
 		return err
 	}
 
+	// スラッシュコマンド
+	if strings.HasPrefix(req.Content, "/") {
+		invoked, err := h.invokeBotCommand(userID, ch, req.Content)
+		if err != nil {
+			return err
+		}
+		if invoked {
+			return c.NoContent(http.StatusAccepted)
+		}
+	}
+
+	if err := checkMessageComponentsAttachable(c, req.Components); err != nil {
+		return err
+	}
+
 	if req.Embed {
 		req.Content = h.Replacer.Replace(req.Content)
 	}
@@ -286,10 +393,193 @@ func (h *Handlers) PostMessage(c echo.Context) error {
 	if err != nil {
 		return herror.InternalServerError(err)
 	}
+	if err := h.attachMessageComponents(m, req.Components); err != nil {
+		return herror.InternalServerError(err)
+	}
+
+	return c.JSON(http.StatusCreated, formatMessage(m))
+}
+
+// invokeBotCommand contentをBotのスラッシュコマンドとして実行します
+//
+// チャンネルで使用可能なコマンドが存在しない場合はfalseを返し、contentは通常のメッセージとして扱われます。
+func (h *Handlers) invokeBotCommand(userID uuid.UUID, ch *model.Channel, content string) (bool, error) {
+	name, text := content[1:], ""
+	if i := strings.IndexFunc(name, unicode.IsSpace); i >= 0 {
+		name, text = name[:i], strings.TrimSpace(name[i:])
+	}
+
+	commands, err := h.Repo.GetBotCommandsByName(name)
+	if err != nil {
+		return false, herror.InternalServerError(err)
+	}
+
+	var (
+		cmd *model.BotCommand
+		b   *model.Bot
+	)
+	for _, c := range commands {
+		if !c.AvailableIn(ch.ID) {
+			continue
+		}
+		cb, err := h.Repo.GetBotByID(c.BotID)
+		if err != nil {
+			return false, herror.InternalServerError(err)
+		}
+		if !ch.IsPublic {
+			// プライベートチャンネル・DMではBotがメンバーの場合のみ実行できる
+			ok, err := h.ChannelManager.IsChannelAccessibleToUser(cb.BotUserID, ch.ID)
+			if err != nil {
+				return false, herror.InternalServerError(err)
+			}
+			if !ok {
+				continue
+			}
+		}
+		if cmd != nil {
+			return false, herror.BadRequest(fmt.Sprintf("command /%s is provided by multiple bots in this channel", name))
+		}
+		cmd, b = c, cb
+	}
+	if cmd == nil {
+		return false, nil
+	}
+
+	args, err := cmd.ParseArguments(text)
+	if err != nil {
+		return false, herror.BadRequest(err)
+	}
+
+	h.Hub.Publish(hub.Message{
+		Name: event.BotCommandInvoked,
+		Fields: hub.Fields{
+			"bot_id":     b.ID,
+			"command":    cmd,
+			"user_id":    userID,
+			"channel_id": ch.ID,
+			"args":       args,
+			"text":       text,
+		},
+	})
+	return true, nil
+}
+
+// GetMessageReplies GET /messages/:messageID/replies
+func (h *Handlers) GetMessageReplies(c echo.Context) error {
+	messageID := getParamAsUUID(c, consts.ParamMessageID)
+
+	var req MessagesQuery
+	if err := req.bind(c); err != nil {
+		return err
+	}
+
+	return serveMessages(c, h.Repo, req.convertP(messageID))
+}
+
+// PostMessageReply POST /messages/:messageID/replies
+func (h *Handlers) PostMessageReply(c echo.Context) error {
+	userID := getRequestUserID(c)
+	parent := getParamMessage(c)
+
+	// 投稿先チャンネル確認
+	ch, err := h.ChannelManager.GetChannel(parent.ChannelID)
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+	if ch.IsArchived() {
+		return herror.BadRequest(fmt.Sprintf("channel #%s has been archived", h.ChannelManager.PublicChannelTree().GetChannelPath(ch.ID)))
+	}
+
+	var req PostMessageRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	if err := checkMessageComponentsAttachable(c, req.Components); err != nil {
+		return err
+	}
+
+	if req.Embed {
+		req.Content = h.Replacer.Replace(req.Content)
+	}
+
+	m, err := h.Repo.CreateReply(userID, parent.ID, req.Content)
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+	if err := h.attachMessageComponents(m, req.Components); err != nil {
+		return herror.InternalServerError(err)
+	}
 
 	return c.JSON(http.StatusCreated, formatMessage(m))
 }
 
+// PostMessageInteractionRequest POST /messages/:messageID/interactions リクエストボディ
+type PostMessageInteractionRequest struct {
+	ComponentID string `json:"componentId"`
+	Value       string `json:"value"`
+}
+
+func (r PostMessageInteractionRequest) Validate() error {
+	return vd.ValidateStruct(&r,
+		vd.Field(&r.ComponentID, vd.Required),
+		vd.Field(&r.Value, vd.RuneLength(0, 100)),
+	)
+}
+
+// PostMessageInteraction POST /messages/:messageID/interactions
+func (h *Handlers) PostMessageInteraction(c echo.Context) error {
+	userID := getRequestUserID(c)
+	m := getParamMessage(c)
+
+	var req PostMessageInteractionRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	if m.Components == nil {
+		return herror.NotFound("component not found")
+	}
+	component := m.Components.Components.Find(req.ComponentID)
+	if component == nil {
+		return herror.NotFound("component not found")
+	}
+	switch component.Type {
+	case model.MessageComponentTypeSelect:
+		if !component.HasOption(req.Value) {
+			return herror.BadRequest("invalid value")
+		}
+	default:
+		req.Value = ""
+	}
+
+	b, err := h.Repo.GetBotByBotUserID(m.UserID)
+	if err != nil {
+		switch err {
+		case repository.ErrNotFound:
+			return herror.BadRequest("this message was not posted by a bot")
+		default:
+			return herror.InternalServerError(err)
+		}
+	}
+	if b.State != model.BotActive {
+		return herror.BadRequest("the bot is not active")
+	}
+
+	h.Hub.Publish(hub.Message{
+		Name: event.MessageComponentInteracted,
+		Fields: hub.Fields{
+			"message_id": m.ID,
+			"message":    m,
+			"bot_id":     b.ID,
+			"component":  component,
+			"value":      req.Value,
+			"user_id":    userID,
+		},
+	})
+	return c.NoContent(http.StatusAccepted)
+}
+
 // GetDirectMessages GET /users/:userId/messages
 func (h *Handlers) GetDirectMessages(c echo.Context) error {
 	myID := getRequestUserID(c)
@@ -319,6 +609,10 @@ func (h *Handlers) PostDirectMessage(c echo.Context) error {
 		return err
 	}
 
+	if err := checkMessageComponentsAttachable(c, req.Components); err != nil {
+		return err
+	}
+
 	// DMチャンネルを取得
 	ch, err := h.ChannelManager.GetDMChannel(myID, targetID)
 	if err != nil {
@@ -333,6 +627,91 @@ func (h *Handlers) PostDirectMessage(c echo.Context) error {
 	if err != nil {
 		return herror.InternalServerError(err)
 	}
+	if err := h.attachMessageComponents(m, req.Components); err != nil {
+		return herror.InternalServerError(err)
+	}
 
 	return c.JSON(http.StatusCreated, formatMessage(m))
 }
+
+// SearchMessagesQuery GET /messages/search クエリパラメータ
+type SearchMessagesQuery struct {
+	Query  string `query:"q"`
+	Limit  int    `query:"limit"`
+	Offset int    `query:"offset"`
+}
+
+func (q *SearchMessagesQuery) Validate() error {
+	if q.Limit == 0 {
+		q.Limit = 20
+	}
+	return vd.ValidateStruct(q,
+		vd.Field(&q.Query, vd.Required, vd.RuneLength(1, 200)),
+		vd.Field(&q.Limit, vd.Min(1), vd.Max(100)),
+		vd.Field(&q.Offset, vd.Min(0)),
+	)
+}
+
+// SearchMessages GET /messages/search
+func (h *Handlers) SearchMessages(c echo.Context) error {
+	userID := getRequestUserID(c)
+
+	var req SearchMessagesQuery
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	parsed, err := search.ParseQuery(req.Query)
+	if err != nil {
+		return herror.BadRequest(err)
+	}
+
+	q := &search.Query{
+		UserID:   userID,
+		Words:    parsed.Words,
+		Before:   parsed.Before,
+		After:    parsed.After,
+		HasFile:  parsed.HasFile,
+		IsPinned: parsed.IsPinned,
+		Limit:    req.Limit,
+		Offset:   req.Offset,
+	}
+	if len(parsed.From) > 0 {
+		u, err := h.Repo.GetUserByName(parsed.From, false)
+		if err != nil {
+			switch err {
+			case repository.ErrNotFound:
+				return herror.BadRequest(fmt.Sprintf("unknown user: %s", parsed.From))
+			default:
+				return herror.InternalServerError(err)
+			}
+		}
+		q.From = optional.UUIDFrom(u.GetID())
+	}
+	if len(parsed.In) > 0 {
+		cid := h.ChannelManager.PublicChannelTree().GetChannelIDFromPath(strings.TrimSuffix(parsed.In, "/"))
+		if cid == uuid.Nil {
+			return herror.BadRequest(fmt.Sprintf("unknown channel: #%s", parsed.In))
+		}
+		q.In = optional.UUIDFrom(cid)
+	}
+	if q.IsEmpty() {
+		return herror.BadRequest("empty query")
+	}
+
+	result, err := h.Search.Do(q)
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+
+	// アクセスできないチャンネル・削除済みのメッセージは検索エンジンで除外済み
+	hits := make([]*model.Message, len(result.Hits))
+	for i, hit := range result.Hits {
+		hits[i] = hit.Message
+	}
+
+	return c.JSON(http.StatusOK, &MessageSearchResult{
+		TotalHits: result.TotalHits,
+		Hits:      formatMessages(hits),
+	})
+}
@@ -0,0 +1,93 @@
+package v3
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/router/session"
+	"github.com/traPtitech/traQ/utils/random"
+	"github.com/traPtitech/traQ/utils/set"
+	"net/http"
+	"testing"
+)
+
+func TestHandlers_PostMessage(t *testing.T) {
+	t.Parallel()
+	path := "/api/v3/channels/{channelId}/messages"
+	env := Setup(t, common)
+
+	t.Run("bot command in private channel", func(t *testing.T) {
+		t.Parallel()
+		user := env.CreateUser(t, rand)
+		b, err := env.Repository.CreateBot(random.AlphaNumeric(16), "bot", "desc", uuid.Must(uuid.NewV4()), user.GetID(), model.BotModeHTTP, "https://example.com")
+		require.NoError(t, err)
+		require.NoError(t, env.Repository.ChangeBotState(b.ID, model.BotActive))
+		name := random.AlphaNumeric(20)
+		_, err = env.Repository.SetBotCommands(b.ID, []*model.BotCommand{{Name: name, Arguments: model.BotCommandArguments{}, ChannelIDs: model.UUIDs{}}})
+		require.NoError(t, err)
+
+		without, err := env.CM.CreatePrivateChannel(random.AlphaNumeric(20), user.GetID(), set.UUIDSetFromArray([]uuid.UUID{user.GetID()}))
+		require.NoError(t, err)
+		with, err := env.CM.CreatePrivateChannel(random.AlphaNumeric(20), user.GetID(), set.UUIDSetFromArray([]uuid.UUID{user.GetID(), b.BotUserID}))
+		require.NoError(t, err)
+
+		s := env.S(t, user.GetID())
+		e := env.R(t)
+		// Botがメンバーでないチャンネルでは通常のメッセージとして投稿される
+		e.POST(path, without.ID).
+			WithCookie(session.CookieName, s).
+			WithJSON(echo.Map{"content": "/" + name}).
+			Expect().
+			Status(http.StatusCreated)
+		e.POST(path, with.ID).
+			WithCookie(session.CookieName, s).
+			WithJSON(echo.Map{"content": "/" + name}).
+			Expect().
+			Status(http.StatusAccepted)
+	})
+
+	t.Run("bot command scoped to channels", func(t *testing.T) {
+		t.Parallel()
+		user := env.CreateUser(t, rand)
+		ch1 := env.CreateChannel(t, rand)
+		ch2 := env.CreateChannel(t, rand)
+		ch3 := env.CreateChannel(t, rand)
+		name := random.AlphaNumeric(20)
+		newBot := func(channelIDs ...uuid.UUID) *model.Bot {
+			b, err := env.Repository.CreateBot(random.AlphaNumeric(16), "bot", "desc", uuid.Must(uuid.NewV4()), user.GetID(), model.BotModeHTTP, "https://example.com")
+			require.NoError(t, err)
+			require.NoError(t, env.Repository.ChangeBotState(b.ID, model.BotActive))
+			_, err = env.Repository.SetBotCommands(b.ID, []*model.BotCommand{{Name: name, Arguments: model.BotCommandArguments{}, ChannelIDs: channelIDs}})
+			require.NoError(t, err)
+			return b
+		}
+		newBot(ch1.ID)
+		newBot(ch2.ID)
+
+		// 使用可能なチャンネルが重複する同名のコマンドは登録できない
+		b, err := env.Repository.CreateBot(random.AlphaNumeric(16), "bot", "desc", uuid.Must(uuid.NewV4()), user.GetID(), model.BotModeHTTP, "https://example.com")
+		require.NoError(t, err)
+		_, err = env.Repository.SetBotCommands(b.ID, []*model.BotCommand{{Name: name, Arguments: model.BotCommandArguments{}, ChannelIDs: model.UUIDs{}}})
+		assert.Equal(t, repository.ErrAlreadyExists, err)
+
+		s := env.S(t, user.GetID())
+		e := env.R(t)
+		for _, ch := range []*model.Channel{ch1, ch2} {
+			e.POST(path, ch.ID).
+				WithCookie(session.CookieName, s).
+				WithJSON(echo.Map{"content": "/" + name}).
+				Expect().
+				Status(http.StatusAccepted)
+		}
+		// コマンドが使用できないチャンネルでは通常のメッセージとして投稿される
+		e.POST(path, ch3.ID).
+			WithCookie(session.CookieName, s).
+			WithJSON(echo.Map{"content": "/" + name + " arg"}).
+			Expect().
+			Status(http.StatusCreated).
+			JSON().Object().Value("content").String().Equal("/" + name + " arg")
+	})
+}
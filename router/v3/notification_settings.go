@@ -0,0 +1,87 @@
+package v3
+
+import (
+	vd "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/gofrs/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/router/extension/herror"
+	"github.com/traPtitech/traQ/utils/optional"
+	"github.com/traPtitech/traQ/utils/validator"
+	"net/http"
+	"regexp"
+)
+
+var clockRegex = regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9]$`)
+
+// GetMyNotificationSetting GET /users/me/notification-settings
+func (h *Handlers) GetMyNotificationSetting(c echo.Context) error {
+	userID := getRequestUserID(c)
+
+	s, err := h.Repo.GetUserNotificationSetting(userID)
+	if err != nil {
+		switch err {
+		case repository.ErrNotFound:
+			return c.JSON(http.StatusOK, formatNotificationSetting(&model.UserNotificationSetting{
+				QuietHoursStart: "00:00",
+				QuietHoursEnd:   "00:00",
+				Timezone:        "UTC",
+			}))
+		default:
+			return herror.InternalServerError(err)
+		}
+	}
+
+	return c.JSON(http.StatusOK, formatNotificationSetting(s))
+}
+
+// PatchMyNotificationSettingRequest PATCH /users/me/notification-settings リクエストボディ
+type PatchMyNotificationSettingRequest struct {
+	QuietHoursEnabled optional.Bool   `json:"quietHoursEnabled"`
+	QuietHoursStart   optional.String `json:"quietHoursStart"`
+	QuietHoursEnd     optional.String `json:"quietHoursEnd"`
+	Timezone          optional.String `json:"timezone"`
+	SnoozeUntil       optional.Time   `json:"snoozeUntil"`
+	Keywords          []string        `json:"keywords"`
+	MutedGroupIDs     []uuid.UUID     `json:"mutedGroupIds"`
+}
+
+func (r PatchMyNotificationSettingRequest) Validate() error {
+	return vd.ValidateStruct(&r,
+		vd.Field(&r.QuietHoursStart, vd.Match(clockRegex).Error("must be HH:MM")),
+		vd.Field(&r.QuietHoursEnd, vd.Match(clockRegex).Error("must be HH:MM")),
+		vd.Field(&r.Timezone, vd.RuneLength(1, 64)),
+		vd.Field(&r.Keywords, vd.Length(0, 50), vd.Each(vd.Required, vd.RuneLength(1, 50))),
+		vd.Field(&r.MutedGroupIDs, vd.Length(0, 100), vd.Each(validator.NotNilUUID)),
+	)
+}
+
+// EditMyNotificationSetting PATCH /users/me/notification-settings
+func (h *Handlers) EditMyNotificationSetting(c echo.Context) error {
+	var req PatchMyNotificationSettingRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	userID := getRequestUserID(c)
+	args := repository.UpdateUserNotificationSettingArgs{
+		QuietHoursEnabled: req.QuietHoursEnabled,
+		QuietHoursStart:   req.QuietHoursStart,
+		QuietHoursEnd:     req.QuietHoursEnd,
+		Timezone:          req.Timezone,
+		SnoozeUntil:       req.SnoozeUntil,
+		Keywords:          req.Keywords,
+		MutedGroupIDs:     req.MutedGroupIDs,
+	}
+	if err := h.Repo.UpdateUserNotificationSetting(userID, args); err != nil {
+		switch {
+		case repository.IsArgError(err):
+			return herror.BadRequest(err)
+		default:
+			return herror.InternalServerError(err)
+		}
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
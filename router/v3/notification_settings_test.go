@@ -0,0 +1,94 @@
+package v3
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/traPtitech/traQ/router/session"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestHandlers_EditMyNotificationSetting(t *testing.T) {
+	t.Parallel()
+	path := "/api/v3/users/me/notification-settings"
+	env := Setup(t, common)
+
+	t.Run("NotLoggedIn", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.PATCH(path).
+			WithJSON(echo.Map{"quietHoursEnabled": true}).
+			Expect().
+			Status(http.StatusUnauthorized)
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		t.Parallel()
+		s := env.S(t, env.CreateUser(t, rand).GetID())
+		e := env.R(t)
+		e.PATCH(path).
+			WithCookie(session.CookieName, s).
+			WithJSON(echo.Map{"quietHoursStart": "25:00"}).
+			Expect().
+			Status(http.StatusBadRequest)
+		e.PATCH(path).
+			WithCookie(session.CookieName, s).
+			WithJSON(echo.Map{"timezone": "Mars/Olympus"}).
+			Expect().
+			Status(http.StatusBadRequest)
+		e.PATCH(path).
+			WithCookie(session.CookieName, s).
+			WithJSON(echo.Map{"keywords": []string{""}}).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		user := env.CreateUser(t, rand)
+		gid := uuid.Must(uuid.NewV4())
+		s := env.S(t, user.GetID())
+		e := env.R(t)
+
+		obj := e.GET(path).
+			WithCookie(session.CookieName, s).
+			Expect().
+			Status(http.StatusOK).
+			JSON().
+			Object()
+		obj.Value("quietHoursEnabled").Boolean().False()
+		obj.Value("timezone").String().Equal("UTC")
+		obj.Value("snoozeUntil").Null()
+		obj.Value("keywords").Array().Empty()
+		obj.Value("mutedGroupIds").Array().Empty()
+
+		e.PATCH(path).
+			WithCookie(session.CookieName, s).
+			WithJSON(echo.Map{
+				"quietHoursEnabled": true,
+				"quietHoursStart":   "23:00",
+				"quietHoursEnd":     "07:00",
+				"timezone":          "Asia/Tokyo",
+				"snoozeUntil":       time.Now().Add(time.Hour),
+				"keywords":          []string{"traQ"},
+				"mutedGroupIds":     []string{gid.String()},
+			}).
+			Expect().
+			Status(http.StatusNoContent)
+
+		obj = e.GET(path).
+			WithCookie(session.CookieName, s).
+			Expect().
+			Status(http.StatusOK).
+			JSON().
+			Object()
+		obj.Value("quietHoursEnabled").Boolean().True()
+		obj.Value("quietHoursStart").String().Equal("23:00")
+		obj.Value("quietHoursEnd").String().Equal("07:00")
+		obj.Value("timezone").String().Equal("Asia/Tokyo")
+		obj.Value("snoozeUntil").String().NotEmpty()
+		obj.Value("keywords").Array().Elements("traQ")
+		obj.Value("mutedGroupIds").Array().Elements(gid.String())
+	})
+}
@@ -0,0 +1,149 @@
+package v3
+
+import (
+	"context"
+	vd "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/gofrs/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/router/extension/herror"
+	"github.com/traPtitech/traQ/router/utils"
+	"github.com/traPtitech/traQ/utils/optional"
+	"github.com/traPtitech/traQ/utils/validator"
+	"net/http"
+)
+
+var outgoingWebhookTriggerTypes = []interface{}{model.OutgoingWebhookTriggerKeyword.String(), model.OutgoingWebhookTriggerRegex.String()}
+
+// GetOutgoingWebhooks GET /webhooks/:webhookID/outgoing-webhooks
+func (h *Handlers) GetOutgoingWebhooks(c echo.Context) error {
+	w := getParamWebhook(c)
+
+	hooks, err := h.Repo.GetOutgoingWebhooksByWebhookID(w.GetID())
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+	return c.JSON(http.StatusOK, formatOutgoingWebhooks(hooks))
+}
+
+// PostOutgoingWebhookRequest POST /webhooks/:webhookID/outgoing-webhooks リクエストボディ
+type PostOutgoingWebhookRequest struct {
+	ChannelID   uuid.UUID `json:"channelId"`
+	TriggerType string    `json:"triggerType"`
+	Trigger     string    `json:"trigger"`
+	URL         string    `json:"url"`
+}
+
+func (r PostOutgoingWebhookRequest) ValidateWithContext(ctx context.Context) error {
+	return vd.ValidateStructWithContext(ctx, &r,
+		vd.Field(&r.ChannelID, vd.Required, validator.NotNilUUID, utils.IsPublicChannelID),
+		vd.Field(&r.TriggerType, vd.Required, vd.In(outgoingWebhookTriggerTypes...)),
+		vd.Field(&r.Trigger, vd.Required, vd.RuneLength(1, 200)),
+		vd.Field(&r.URL, vd.Required, is.URL, validator.NotInternalURL),
+	)
+}
+
+// CreateOutgoingWebhook POST /webhooks/:webhookID/outgoing-webhooks
+func (h *Handlers) CreateOutgoingWebhook(c echo.Context) error {
+	userID := getRequestUserID(c)
+	w := getParamWebhook(c)
+
+	var req PostOutgoingWebhookRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	hook, err := h.Repo.CreateOutgoingWebhook(w.GetID(), req.ChannelID, model.OutgoingWebhookTriggerType(req.TriggerType), req.Trigger, req.URL, userID)
+	if err != nil {
+		switch {
+		case repository.IsArgError(err):
+			return herror.BadRequest(err)
+		default:
+			return herror.InternalServerError(err)
+		}
+	}
+
+	return c.JSON(http.StatusCreated, formatOutgoingWebhook(hook))
+}
+
+// GetOutgoingWebhook GET /webhooks/:webhookID/outgoing-webhooks/:outgoingWebhookID
+func (h *Handlers) GetOutgoingWebhook(c echo.Context) error {
+	hook, err := getParamWebhookOutgoingWebhook(c)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, formatOutgoingWebhook(hook))
+}
+
+// PatchOutgoingWebhookRequest PATCH /webhooks/:webhookID/outgoing-webhooks/:outgoingWebhookID リクエストボディ
+type PatchOutgoingWebhookRequest struct {
+	ChannelID   optional.UUID   `json:"channelId"`
+	TriggerType optional.String `json:"triggerType"`
+	Trigger     optional.String `json:"trigger"`
+	URL         optional.String `json:"url"`
+}
+
+func (r PatchOutgoingWebhookRequest) ValidateWithContext(ctx context.Context) error {
+	return vd.ValidateStructWithContext(ctx, &r,
+		vd.Field(&r.ChannelID, validator.NotNilUUID, utils.IsPublicChannelID),
+		vd.Field(&r.TriggerType, vd.In(outgoingWebhookTriggerTypes...)),
+		vd.Field(&r.Trigger, vd.RuneLength(1, 200)),
+		vd.Field(&r.URL, is.URL, validator.NotInternalURL),
+	)
+}
+
+// EditOutgoingWebhook PATCH /webhooks/:webhookID/outgoing-webhooks/:outgoingWebhookID
+func (h *Handlers) EditOutgoingWebhook(c echo.Context) error {
+	hook, err := getParamWebhookOutgoingWebhook(c)
+	if err != nil {
+		return err
+	}
+
+	var req PatchOutgoingWebhookRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	args := repository.UpdateOutgoingWebhookArgs{
+		ChannelID:   req.ChannelID,
+		TriggerType: req.TriggerType,
+		Trigger:     req.Trigger,
+		URL:         req.URL,
+	}
+	if err := h.Repo.UpdateOutgoingWebhook(hook.ID, args); err != nil {
+		switch {
+		case repository.IsArgError(err):
+			return herror.BadRequest(err)
+		default:
+			return herror.InternalServerError(err)
+		}
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// DeleteOutgoingWebhook DELETE /webhooks/:webhookID/outgoing-webhooks/:outgoingWebhookID
+func (h *Handlers) DeleteOutgoingWebhook(c echo.Context) error {
+	hook, err := getParamWebhookOutgoingWebhook(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.Repo.DeleteOutgoingWebhook(hook.ID); err != nil {
+		return herror.InternalServerError(err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// getParamWebhookOutgoingWebhook URLの:webhookIDのWebhookに属する:outgoingWebhookIDのOutgoingWebhookを取得
+func getParamWebhookOutgoingWebhook(c echo.Context) (*model.OutgoingWebhook, error) {
+	w := getParamWebhook(c)
+	hook := getParamOutgoingWebhook(c)
+	if hook.WebhookID != w.GetID() {
+		return nil, herror.NotFound()
+	}
+	return hook, nil
+}
@@ -20,7 +20,8 @@ func (h *Handlers) GetVersion(c echo.Context) error {
 		"version":  h.Version,
 		"revision": h.Revision,
 		"flags": echo.Map{
-			"externalLogin": extLogins,
+			"externalLogin":    extLogins,
+			"webPushPublicKey": h.WebPushPublicKey,
 		},
 	})
 }
@@ -17,4 +17,5 @@ func TestHandlers_GetVersion(t *testing.T) {
 		Object()
 	obj.Value("version").String().Equal("version")
 	obj.Value("revision").String().Equal("revision")
+	obj.Value("flags").Object().Value("webPushPublicKey").String().Empty()
 }
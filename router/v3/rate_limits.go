@@ -0,0 +1,121 @@
+package v3
+
+import (
+	vd "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/gofrs/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/router/extension/herror"
+	"github.com/traPtitech/traQ/service/ratelimit"
+	"net/http"
+)
+
+// webhookRateLimitActions Webhookでレート制限の対象となる操作
+var webhookRateLimitActions = []ratelimit.Action{ratelimit.PostMessage}
+
+// GetBotRateLimits GET /bots/:botID/rate-limits
+func (h *Handlers) GetBotRateLimits(c echo.Context) error {
+	b := getParamBot(c)
+	return c.JSON(http.StatusOK, h.formatRateLimits(ratelimit.Bot, b.BotUserID, ratelimit.Actions))
+}
+
+// SetBotRateLimit PUT /bots/:botID/rate-limits/:action
+func (h *Handlers) SetBotRateLimit(c echo.Context) error {
+	b := getParamBot(c)
+	return h.setRateLimit(c, b.BotUserID, ratelimit.Actions)
+}
+
+// DeleteBotRateLimit DELETE /bots/:botID/rate-limits/:action
+func (h *Handlers) DeleteBotRateLimit(c echo.Context) error {
+	b := getParamBot(c)
+	return h.deleteRateLimit(c, b.BotUserID, ratelimit.Actions)
+}
+
+// GetWebhookRateLimits GET /webhooks/:webhookID/rate-limits
+func (h *Handlers) GetWebhookRateLimits(c echo.Context) error {
+	w := getParamWebhook(c)
+	return c.JSON(http.StatusOK, h.formatRateLimits(ratelimit.Webhook, w.GetBotUserID(), webhookRateLimitActions))
+}
+
+// SetWebhookRateLimit PUT /webhooks/:webhookID/rate-limits/:action
+func (h *Handlers) SetWebhookRateLimit(c echo.Context) error {
+	w := getParamWebhook(c)
+	return h.setRateLimit(c, w.GetBotUserID(), webhookRateLimitActions)
+}
+
+// DeleteWebhookRateLimit DELETE /webhooks/:webhookID/rate-limits/:action
+func (h *Handlers) DeleteWebhookRateLimit(c echo.Context) error {
+	w := getParamWebhook(c)
+	return h.deleteRateLimit(c, w.GetBotUserID(), webhookRateLimitActions)
+}
+
+// PutRateLimitRequest PUT /bots/:botID/rate-limits/:action, PUT /webhooks/:webhookID/rate-limits/:action リクエストボディ
+type PutRateLimitRequest struct {
+	Rate  float64 `json:"rate"`
+	Burst int     `json:"burst"`
+}
+
+func (r PutRateLimitRequest) Validate() error {
+	return vd.ValidateStruct(&r,
+		vd.Field(&r.Rate, vd.Min(0.0), vd.When(r.Burst > 0, vd.Required)),
+		vd.Field(&r.Burst, vd.Min(0)),
+	)
+}
+
+func (h *Handlers) setRateLimit(c echo.Context, userID uuid.UUID, actions []ratelimit.Action) error {
+	action, err := getRateLimitActionParam(c, actions)
+	if err != nil {
+		return err
+	}
+
+	var req PutRateLimitRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	if err := h.RateLimiter.SetOverride(userID, action, ratelimit.Limit{Rate: req.Rate, Burst: req.Burst}); err != nil {
+		return herror.InternalServerError(err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *Handlers) deleteRateLimit(c echo.Context, userID uuid.UUID, actions []ratelimit.Action) error {
+	action, err := getRateLimitActionParam(c, actions)
+	if err != nil {
+		return err
+	}
+
+	if err := h.RateLimiter.DeleteOverride(userID, action); err != nil {
+		switch err {
+		case repository.ErrNotFound:
+			return herror.NotFound("the rate limit is not overridden")
+		default:
+			return herror.InternalServerError(err)
+		}
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *Handlers) formatRateLimits(subject ratelimit.Subject, userID uuid.UUID, actions []ratelimit.Action) []*RateLimit {
+	res := make([]*RateLimit, len(actions))
+	for i, action := range actions {
+		limit, overridden := h.RateLimiter.GetLimit(subject, userID, action)
+		res[i] = &RateLimit{
+			Action:     action,
+			Rate:       limit.Rate,
+			Burst:      limit.Burst,
+			Overridden: overridden,
+		}
+	}
+	return res
+}
+
+func getRateLimitActionParam(c echo.Context, actions []ratelimit.Action) (ratelimit.Action, error) {
+	action := ratelimit.Action(c.Param("action"))
+	for _, a := range actions {
+		if a == action {
+			return action, nil
+		}
+	}
+	return "", herror.NotFound("unknown action")
+}
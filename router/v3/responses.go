@@ -1,11 +1,14 @@
 package v3
 
 import (
+	"encoding/json"
 	"github.com/traPtitech/traQ/utils/optional"
+	"sort"
 	"time"
 
 	"github.com/gofrs/uuid"
 	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/service/ratelimit"
 )
 
 type Channel struct {
@@ -22,7 +25,7 @@ func formatChannel(channel *model.Channel, childrenID []uuid.UUID) *Channel {
 	return &Channel{
 		ID:       channel.ID,
 		Name:     channel.Name,
-		ParentID: optional.NewUUID(channel.ParentID, channel.ParentID != uuid.Nil),
+		ParentID: optional.NewUUID(channel.ParentID, channel.ParentID != uuid.Nil && !channel.IsPrivateChannel()),
 		Topic:    channel.Topic,
 		Children: childrenID,
 		Archived: channel.IsArchived(),
@@ -30,6 +33,14 @@ func formatChannel(channel *model.Channel, childrenID []uuid.UUID) *Channel {
 	}
 }
 
+func formatChannels(channels []*model.Channel) []*Channel {
+	res := make([]*Channel, len(channels))
+	for i, ch := range channels {
+		res[i] = formatChannel(ch, make([]uuid.UUID, 0))
+	}
+	return res
+}
+
 type DMChannel struct {
 	ID     uuid.UUID `json:"id"`
 	UserID uuid.UUID `json:"userId"`
@@ -165,6 +176,7 @@ type Bot struct {
 	Description     string              `json:"description"`
 	DeveloperID     uuid.UUID           `json:"developerId"`
 	SubscribeEvents model.BotEventTypes `json:"subscribeEvents"`
+	Mode            model.BotMode       `json:"mode"`
 	State           model.BotState      `json:"state"`
 	CreatedAt       time.Time           `json:"createdAt"`
 	UpdatedAt       time.Time           `json:"updatedAt"`
@@ -176,6 +188,7 @@ func formatBot(b *model.Bot) *Bot {
 		BotUserID:       b.BotUserID,
 		Description:     b.Description,
 		SubscribeEvents: b.SubscribeEvents,
+		Mode:            b.Mode,
 		State:           b.State,
 		DeveloperID:     b.CreatorID,
 		CreatedAt:       b.CreatedAt,
@@ -194,21 +207,24 @@ func formatBots(bs []*model.Bot) []*Bot {
 type BotTokens struct {
 	VerificationToken string `json:"verificationToken"`
 	AccessToken       string `json:"accessToken"`
+	SigningSecret     string `json:"signingSecret"`
 }
 
 type BotDetail struct {
-	ID              uuid.UUID           `json:"id"`
-	BotUserID       uuid.UUID           `json:"botUserId"`
-	Description     string              `json:"description"`
-	DeveloperID     uuid.UUID           `json:"developerId"`
-	SubscribeEvents model.BotEventTypes `json:"subscribeEvents"`
-	State           model.BotState      `json:"state"`
-	CreatedAt       time.Time           `json:"createdAt"`
-	UpdatedAt       time.Time           `json:"updatedAt"`
-	Tokens          BotTokens           `json:"tokens"`
-	Endpoint        string              `json:"endpoint"`
-	Privileged      bool                `json:"privileged"`
-	Channels        []uuid.UUID         `json:"channels"`
+	ID                      uuid.UUID           `json:"id"`
+	BotUserID               uuid.UUID           `json:"botUserId"`
+	Description             string              `json:"description"`
+	DeveloperID             uuid.UUID           `json:"developerId"`
+	SubscribeEvents         model.BotEventTypes `json:"subscribeEvents"`
+	Mode                    model.BotMode       `json:"mode"`
+	State                   model.BotState      `json:"state"`
+	CreatedAt               time.Time           `json:"createdAt"`
+	UpdatedAt               time.Time           `json:"updatedAt"`
+	Tokens                  BotTokens           `json:"tokens"`
+	Endpoint                string              `json:"endpoint"`
+	Privileged              bool                `json:"privileged"`
+	Channels                []uuid.UUID         `json:"channels"`
+	LegacyVerificationToken bool                `json:"legacyVerificationToken"`
 }
 
 func formatBotDetail(b *model.Bot, t *model.OAuth2Token, channels []uuid.UUID) *BotDetail {
@@ -217,6 +233,7 @@ func formatBotDetail(b *model.Bot, t *model.OAuth2Token, channels []uuid.UUID) *
 		BotUserID:       b.BotUserID,
 		Description:     b.Description,
 		SubscribeEvents: b.SubscribeEvents,
+		Mode:            b.Mode,
 		State:           b.State,
 		DeveloperID:     b.CreatorID,
 		CreatedAt:       b.CreatedAt,
@@ -224,36 +241,66 @@ func formatBotDetail(b *model.Bot, t *model.OAuth2Token, channels []uuid.UUID) *
 		Tokens: BotTokens{
 			VerificationToken: b.VerificationToken,
 			AccessToken:       t.AccessToken,
+			SigningSecret:     b.SigningSecret,
 		},
-		Endpoint:   b.PostURL,
-		Privileged: b.Privileged,
-		Channels:   channels,
+		Endpoint:                b.PostURL,
+		Privileged:              b.Privileged,
+		Channels:                channels,
+		LegacyVerificationToken: b.LegacyVerificationToken,
 	}
 }
 
 type Message struct {
-	ID        uuid.UUID            `json:"id"`
-	UserID    uuid.UUID            `json:"userId"`
-	ChannelID uuid.UUID            `json:"channelId"`
-	Content   string               `json:"content"`
-	CreatedAt time.Time            `json:"createdAt"`
-	UpdatedAt time.Time            `json:"updatedAt"`
-	Pinned    bool                 `json:"pinned"`
-	Stamps    []model.MessageStamp `json:"stamps"`
-	ThreadID  optional.UUID        `json:"threadId"` // TODO
+	ID         uuid.UUID               `json:"id"`
+	UserID     uuid.UUID               `json:"userId"`
+	ChannelID  uuid.UUID               `json:"channelId"`
+	Content    string                  `json:"content"`
+	CreatedAt  time.Time               `json:"createdAt"`
+	UpdatedAt  time.Time               `json:"updatedAt"`
+	Pinned     bool                    `json:"pinned"`
+	Stamps     []model.MessageStamp    `json:"stamps"`
+	ThreadID   optional.UUID           `json:"threadId"`
+	ReplyCount int                     `json:"replyCount"`
+	Hidden     bool                    `json:"hidden"`
+	Components model.MessageComponents `json:"components"`
+
+	AuthorOverride *MessageAuthorOverride `json:"authorOverride"`
+}
+
+type MessageAuthorOverride struct {
+	DisplayName string        `json:"displayName"`
+	IconFileID  optional.UUID `json:"iconFileId"`
 }
 
 func formatMessage(m *model.Message) *Message {
-	return &Message{
-		ID:        m.ID,
-		UserID:    m.UserID,
-		ChannelID: m.ChannelID,
-		Content:   m.Text,
-		CreatedAt: m.CreatedAt,
-		UpdatedAt: m.UpdatedAt,
-		Pinned:    m.Pin != nil,
-		Stamps:    m.Stamps,
+	res := &Message{
+		ID:         m.ID,
+		UserID:     m.UserID,
+		ChannelID:  m.ChannelID,
+		Content:    m.Text,
+		CreatedAt:  m.CreatedAt,
+		UpdatedAt:  m.UpdatedAt,
+		Pinned:     m.Pin != nil,
+		Stamps:     m.Stamps,
+		ThreadID:   m.ParentID,
+		ReplyCount: m.ReplyCount,
+		Hidden:     m.Hidden,
+		Components: model.MessageComponents{},
+	}
+	if m.Components != nil {
+		res.Components = m.Components.Components
+	}
+	if m.AuthorOverride != nil {
+		res.AuthorOverride = &MessageAuthorOverride{
+			DisplayName: m.AuthorOverride.DisplayName,
+			IconFileID:  m.AuthorOverride.IconFileID,
+		}
+	}
+	if m.Hidden {
+		// 非表示のメッセージの本文は返さない
+		res.Content = ""
 	}
+	return res
 }
 
 func formatMessages(ms []*model.Message) []*Message {
@@ -264,6 +311,11 @@ func formatMessages(ms []*model.Message) []*Message {
 	return res
 }
 
+type MessageSearchResult struct {
+	TotalHits int        `json:"totalHits"`
+	Hits      []*Message `json:"hits"`
+}
+
 type Pin struct {
 	UserID   uuid.UUID `json:"userId"`
 	PinnedAt time.Time `json:"pinnedAt"`
@@ -300,6 +352,167 @@ func formatMessagePin(pin *model.Pin) *Pin {
 	}
 }
 
+type BotTestEventResult struct {
+	RequestID    uuid.UUID          `json:"requestId"`
+	Event        model.BotEventType `json:"event"`
+	Mode         model.BotMode      `json:"mode"`
+	Payload      json.RawMessage    `json:"payload"`
+	StatusCode   int                `json:"statusCode"`
+	Latency      float64            `json:"latency"`
+	ResponseBody string             `json:"responseBody"`
+	Error        string             `json:"error"`
+}
+
+func formatBotTestEventResult(log *model.BotEventLog, resBody string) *BotTestEventResult {
+	return &BotTestEventResult{
+		RequestID:    log.RequestID,
+		Event:        log.Event,
+		Mode:         log.Mode,
+		Payload:      json.RawMessage(log.Body),
+		StatusCode:   log.Code,
+		Latency:      float64(log.Latency) / float64(time.Millisecond),
+		ResponseBody: resBody,
+		Error:        log.Error,
+	}
+}
+
+type OutgoingWebhook struct {
+	ID          uuid.UUID `json:"id"`
+	WebhookID   uuid.UUID `json:"webhookId"`
+	ChannelID   uuid.UUID `json:"channelId"`
+	TriggerType string    `json:"triggerType"`
+	Trigger     string    `json:"trigger"`
+	URL         string    `json:"url"`
+	Secret      string    `json:"secret"`
+	CreatorID   uuid.UUID `json:"creatorId"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+func formatOutgoingWebhook(w *model.OutgoingWebhook) *OutgoingWebhook {
+	return &OutgoingWebhook{
+		ID:          w.ID,
+		WebhookID:   w.WebhookID,
+		ChannelID:   w.ChannelID,
+		TriggerType: w.TriggerType.String(),
+		Trigger:     w.Trigger,
+		URL:         w.URL,
+		Secret:      w.Secret,
+		CreatorID:   w.CreatorID,
+		CreatedAt:   w.CreatedAt,
+		UpdatedAt:   w.UpdatedAt,
+	}
+}
+
+func formatOutgoingWebhooks(ws []*model.OutgoingWebhook) []*OutgoingWebhook {
+	res := make([]*OutgoingWebhook, len(ws))
+	for i, w := range ws {
+		res[i] = formatOutgoingWebhook(w)
+	}
+	return res
+}
+
+type MessageReport struct {
+	ID         uuid.UUID     `json:"id"`
+	MessageID  uuid.UUID     `json:"messageId"`
+	ReporterID uuid.UUID     `json:"reporterId"`
+	Reason     string        `json:"reason"`
+	Status     string        `json:"status"`
+	Action     string        `json:"action"`
+	Note       string        `json:"note"`
+	HandlerID  optional.UUID `json:"handlerId"`
+	HandledAt  optional.Time `json:"handledAt"`
+	CreatedAt  time.Time     `json:"createdAt"`
+}
+
+func formatMessageReport(r *model.MessageReport) *MessageReport {
+	return &MessageReport{
+		ID:         r.ID,
+		MessageID:  r.MessageID,
+		ReporterID: r.Reporter,
+		Reason:     r.Reason,
+		Status:     r.Status,
+		Action:     r.Action,
+		Note:       r.Note,
+		HandlerID:  r.HandlerID,
+		HandledAt:  r.HandledAt,
+		CreatedAt:  r.CreatedAt,
+	}
+}
+
+func formatMessageReports(rs []*model.MessageReport) []*MessageReport {
+	res := make([]*MessageReport, len(rs))
+	for i, r := range rs {
+		res[i] = formatMessageReport(r)
+	}
+	return res
+}
+
+type UserRole struct {
+	Name         string   `json:"name"`
+	OAuth2Scope  bool     `json:"oauth2Scope"`
+	System       bool     `json:"system"`
+	Permissions  []string `json:"permissions"`
+	Inheritances []string `json:"inheritances"`
+}
+
+func formatUserRole(r *model.UserRole) *UserRole {
+	res := &UserRole{
+		Name:         r.Name,
+		OAuth2Scope:  r.Oauth2Scope,
+		System:       r.System,
+		Permissions:  make([]string, len(r.Permissions)),
+		Inheritances: make([]string, len(r.Inheritances)),
+	}
+	for i, p := range r.Permissions {
+		res.Permissions[i] = p.Permission
+	}
+	for i, v := range r.Inheritances {
+		res.Inheritances[i] = v.SubRole
+	}
+	sort.Strings(res.Permissions)
+	sort.Strings(res.Inheritances)
+	return res
+}
+
+func formatUserRoles(rs []*model.UserRole) []*UserRole {
+	res := make([]*UserRole, len(rs))
+	for i, r := range rs {
+		res[i] = formatUserRole(r)
+	}
+	return res
+}
+
+type ScheduledMessage struct {
+	ID          uuid.UUID `json:"id"`
+	UserID      uuid.UUID `json:"userId"`
+	ChannelID   uuid.UUID `json:"channelId"`
+	Content     string    `json:"content"`
+	ScheduledAt time.Time `json:"scheduledAt"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+func formatScheduledMessage(m *model.ScheduledMessage) *ScheduledMessage {
+	return &ScheduledMessage{
+		ID:          m.ID,
+		UserID:      m.UserID,
+		ChannelID:   m.ChannelID,
+		Content:     m.Text,
+		ScheduledAt: m.ScheduledAt,
+		CreatedAt:   m.CreatedAt,
+		UpdatedAt:   m.UpdatedAt,
+	}
+}
+
+func formatScheduledMessages(ms []*model.ScheduledMessage) []*ScheduledMessage {
+	res := make([]*ScheduledMessage, len(ms))
+	for i, m := range ms {
+		res[i] = formatScheduledMessage(m)
+	}
+	return res
+}
+
 type MessageClip struct {
 	FolderID  uuid.UUID `json:"folderId"`
 	ClippedAt time.Time `json:"clippedAt"`
@@ -546,3 +759,79 @@ func formatStampPalettes(cfs []*model.StampPalette) []*StampPalette {
 	}
 	return res
 }
+
+type ChannelRole struct {
+	UserID    uuid.UUID `json:"userId"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+func formatChannelRole(cr *model.ChannelRole) *ChannelRole {
+	return &ChannelRole{
+		UserID:    cr.UserID,
+		Role:      cr.Role,
+		CreatedAt: cr.CreatedAt,
+		UpdatedAt: cr.UpdatedAt,
+	}
+}
+
+func formatChannelRoles(crs []*model.ChannelRole) []*ChannelRole {
+	res := make([]*ChannelRole, len(crs))
+	for i, cr := range crs {
+		res[i] = formatChannelRole(cr)
+	}
+	return res
+}
+
+type RateLimit struct {
+	Action     ratelimit.Action `json:"action"`
+	Rate       float64          `json:"rate"`
+	Burst      int              `json:"burst"`
+	Overridden bool             `json:"overridden"`
+}
+
+type DigestSetting struct {
+	Email         string                `json:"email"`
+	EmailVerified bool                  `json:"emailVerified"`
+	Frequency     model.DigestFrequency `json:"frequency"`
+}
+
+func formatDigestSetting(s *model.UserDigestSetting) *DigestSetting {
+	return &DigestSetting{
+		Email:         s.Email,
+		EmailVerified: s.IsEmailVerified(),
+		Frequency:     s.Frequency,
+	}
+}
+
+type NotificationSetting struct {
+	QuietHoursEnabled bool        `json:"quietHoursEnabled"`
+	QuietHoursStart   string      `json:"quietHoursStart"`
+	QuietHoursEnd     string      `json:"quietHoursEnd"`
+	Timezone          string      `json:"timezone"`
+	SnoozeUntil       *time.Time  `json:"snoozeUntil"`
+	Keywords          []string    `json:"keywords"`
+	MutedGroupIDs     []uuid.UUID `json:"mutedGroupIds"`
+}
+
+func formatNotificationSetting(s *model.UserNotificationSetting) *NotificationSetting {
+	ns := &NotificationSetting{
+		QuietHoursEnabled: s.QuietHoursEnabled,
+		QuietHoursStart:   s.QuietHoursStart,
+		QuietHoursEnd:     s.QuietHoursEnd,
+		Timezone:          s.Timezone,
+		Keywords:          []string{},
+		MutedGroupIDs:     []uuid.UUID{},
+	}
+	if s.SnoozeUntil != nil && s.SnoozeUntil.After(time.Now()) {
+		ns.SnoozeUntil = s.SnoozeUntil
+	}
+	if s.Keywords != nil {
+		ns.Keywords = s.Keywords
+	}
+	if s.MutedGroupIDs != nil {
+		ns.MutedGroupIDs = s.MutedGroupIDs
+	}
+	return ns
+}
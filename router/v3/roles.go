@@ -0,0 +1,136 @@
+package v3
+
+import (
+	vd "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/labstack/echo/v4"
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/router/extension/herror"
+	"github.com/traPtitech/traQ/service/rbac/permission"
+	"github.com/traPtitech/traQ/utils/optional"
+	"github.com/traPtitech/traQ/utils/validator"
+	"net/http"
+)
+
+// permissionNameRule 存在する権限名かどうかのバリデーションルール
+var permissionNameRule = func() vd.Rule {
+	names := make([]interface{}, len(permission.List))
+	for i, p := range permission.List {
+		names[i] = p.Name()
+	}
+	return vd.In(names...).Error("unknown permission")
+}()
+
+// GetUserRoles GET /roles
+func (h *Handlers) GetUserRoles(c echo.Context) error {
+	roles, err := h.Repo.GetUserRoles()
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+	return c.JSON(http.StatusOK, formatUserRoles(roles))
+}
+
+// PostUserRoleRequest POST /roles リクエストボディ
+type PostUserRoleRequest struct {
+	Name         string   `json:"name"`
+	OAuth2Scope  bool     `json:"oauth2Scope"`
+	Permissions  []string `json:"permissions"`
+	Inheritances []string `json:"inheritances"`
+}
+
+func (r PostUserRoleRequest) Validate() error {
+	return vd.ValidateStruct(&r,
+		vd.Field(&r.Name, validator.UserRoleNameRuleRequired...),
+		vd.Field(&r.Permissions, vd.Each(permissionNameRule)),
+		vd.Field(&r.Inheritances, vd.Each(validator.UserRoleNameRuleRequired...)),
+	)
+}
+
+// CreateUserRole POST /roles
+func (h *Handlers) CreateUserRole(c echo.Context) error {
+	var req PostUserRoleRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	r, err := h.Repo.CreateUserRole(req.Name, req.OAuth2Scope, req.Permissions, req.Inheritances)
+	if err != nil {
+		switch {
+		case err == repository.ErrAlreadyExists:
+			return herror.Conflict("name conflicts")
+		case repository.IsArgError(err):
+			return herror.BadRequest(err)
+		default:
+			return herror.InternalServerError(err)
+		}
+	}
+	return c.JSON(http.StatusCreated, formatUserRole(r))
+}
+
+// GetUserRole GET /roles/:roleName
+func (h *Handlers) GetUserRole(c echo.Context) error {
+	return c.JSON(http.StatusOK, formatUserRole(getParamUserRole(c)))
+}
+
+// PatchUserRoleRequest PATCH /roles/:roleName リクエストボディ
+type PatchUserRoleRequest struct {
+	OAuth2Scope  optional.Bool `json:"oauth2Scope"`
+	Permissions  []string      `json:"permissions"`
+	Inheritances []string      `json:"inheritances"`
+}
+
+func (r PatchUserRoleRequest) Validate() error {
+	return vd.ValidateStruct(&r,
+		vd.Field(&r.Permissions, vd.Each(permissionNameRule)),
+		vd.Field(&r.Inheritances, vd.Each(validator.UserRoleNameRuleRequired...)),
+	)
+}
+
+// EditUserRole PATCH /roles/:roleName
+func (h *Handlers) EditUserRole(c echo.Context) error {
+	r := getParamUserRole(c)
+
+	var req PatchUserRoleRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	if r.System {
+		return herror.Forbidden("system roles cannot be modified")
+	}
+
+	args := repository.UpdateUserRoleArgs{
+		OAuth2Scope:  req.OAuth2Scope,
+		Permissions:  req.Permissions,
+		Inheritances: req.Inheritances,
+	}
+	if err := h.Repo.UpdateUserRole(r.Name, args); err != nil {
+		switch {
+		case err == repository.ErrForbidden:
+			return herror.Forbidden("system roles cannot be modified")
+		case repository.IsArgError(err):
+			return herror.BadRequest(err)
+		default:
+			return herror.InternalServerError(err)
+		}
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// DeleteUserRole DELETE /roles/:roleName
+func (h *Handlers) DeleteUserRole(c echo.Context) error {
+	r := getParamUserRole(c)
+
+	if r.System {
+		return herror.Forbidden("system roles cannot be deleted")
+	}
+
+	if err := h.Repo.DeleteUserRole(r.Name); err != nil {
+		switch err {
+		case repository.ErrForbidden:
+			return herror.Conflict("the role is assigned to some users")
+		default:
+			return herror.InternalServerError(err)
+		}
+	}
+	return c.NoContent(http.StatusNoContent)
+}
@@ -7,12 +7,18 @@ import (
 	"github.com/traPtitech/traQ/router/extension"
 	"github.com/traPtitech/traQ/router/middlewares"
 	"github.com/traPtitech/traQ/router/session"
+	"github.com/traPtitech/traQ/service/bot"
+	botws "github.com/traPtitech/traQ/service/bot/ws"
 	"github.com/traPtitech/traQ/service/channel"
 	"github.com/traPtitech/traQ/service/counter"
+	"github.com/traPtitech/traQ/service/digest"
+	"github.com/traPtitech/traQ/service/export"
 	"github.com/traPtitech/traQ/service/file"
 	"github.com/traPtitech/traQ/service/imaging"
+	"github.com/traPtitech/traQ/service/ratelimit"
 	"github.com/traPtitech/traQ/service/rbac"
 	"github.com/traPtitech/traQ/service/rbac/permission"
+	"github.com/traPtitech/traQ/service/search"
 	"github.com/traPtitech/traQ/service/viewer"
 	"github.com/traPtitech/traQ/service/webrtcv3"
 	"github.com/traPtitech/traQ/service/ws"
@@ -22,8 +28,10 @@ import (
 
 type Handlers struct {
 	RBAC           rbac.RBAC
+	BOT            bot.Service
 	Repo           repository.Repository
 	WS             *ws.Streamer
+	BotWS          *botws.Streamer
 	Hub            *hub.Hub
 	Logger         *zap.Logger
 	OC             *counter.OnlineCounter
@@ -34,6 +42,10 @@ type Handlers struct {
 	ChannelManager channel.Manager
 	FileManager    file.Manager
 	Replacer       *message.Replacer
+	Search         search.Engine
+	Exporter       export.Exporter
+	RateLimiter    ratelimit.Limiter
+	Digest         digest.Service
 	Config
 }
 
@@ -41,9 +53,15 @@ type Config struct {
 	Version  string
 	Revision string
 
+	// Origin サーバーオリジン
+	Origin string
+
 	// SkyWaySecretKey SkyWayクレデンシャル用シークレットキー
 	SkyWaySecretKey string
 
+	// WebPushPublicKey Web Push用VAPID公開鍵 (Web Pushが無効の場合は空)
+	WebPushPublicKey string
+
 	// EnabledExternalAccountLink リンク可能な外部認証アカウントのプロバイダ
 	EnabledExternalAccountProviders map[string]bool
 }
@@ -52,9 +70,11 @@ type Config struct {
 func (h *Handlers) Setup(e *echo.Group) {
 	// middleware preparation
 	requires := middlewares.AccessControlMiddlewareGenerator(h.RBAC)
+	rateLimit := middlewares.RateLimitMiddlewareGenerator(h.RateLimiter)
 	bodyLimit := middlewares.RequestBodyLengthLimit
 	retrieve := middlewares.NewParamRetriever(h.Repo, h.ChannelManager, h.FileManager)
 	blockBot := middlewares.BlockBot(h.Repo)
+	adminOnly := middlewares.AdminOnly
 	nologin := middlewares.NoLogin(h.SessStore)
 
 	requiresBotAccessPerm := middlewares.CheckBotAccessPerm(h.RBAC, h.Repo)
@@ -78,7 +98,7 @@ func (h *Handlers) Setup(e *echo.Group) {
 				apiUsersUID.PATCH("", h.EditUser, requires(permission.EditOtherUsers))
 				apiUsersUID.GET("/dm-channel", h.GetUserDMChannel, requires(permission.GetChannel))
 				apiUsersUID.GET("/messages", h.GetDirectMessages, requires(permission.GetMessage))
-				apiUsersUID.POST("/messages", h.PostDirectMessage, bodyLimit(100), requires(permission.PostMessage))
+				apiUsersUID.POST("/messages", h.PostDirectMessage, bodyLimit(100), requires(permission.PostMessage), rateLimit(ratelimit.PostMessage))
 				apiUsersUID.GET("/icon", h.GetUserIcon, requires(permission.DownloadFile))
 				apiUsersUID.PUT("/icon", h.ChangeUserIcon, requires(permission.EditOtherUsers))
 				apiUsersUID.PUT("/password", h.ChangeUserPassword, requires(permission.EditOtherUsers))
@@ -98,11 +118,18 @@ func (h *Handlers) Setup(e *echo.Group) {
 				apiUsersMe.GET("", h.GetMe, requires(permission.GetMe))
 				apiUsersMe.PATCH("", h.EditMe, requires(permission.EditMe))
 				apiUsersMe.GET("/stamp-history", h.GetMyStampHistory, requires(permission.GetMyStampHistory))
+				apiUsersMe.GET("/export", h.ExportMyData, requires(permission.ExportMyData), blockBot)
 				apiUsersMe.GET("/qr-code", h.GetMyQRCode, requires(permission.GetUserQRCode), blockBot)
 				apiUsersMe.GET("/icon", h.GetMyIcon, requires(permission.DownloadFile))
 				apiUsersMe.PUT("/icon", h.ChangeMyIcon, requires(permission.ChangeMyIcon))
 				apiUsersMe.PUT("/password", h.PutMyPassword, requires(permission.ChangeMyPassword), blockBot)
 				apiUsersMe.POST("/fcm-device", h.PostMyFCMDevice, requires(permission.RegisterFCMDevice), blockBot)
+				apiUsersMe.POST("/web-push-subscriptions", h.PostMyWebPushSubscription, requires(permission.RegisterFCMDevice), blockBot)
+				apiUsersMe.DELETE("/web-push-subscriptions", h.DeleteMyWebPushSubscription, requires(permission.RegisterFCMDevice), blockBot)
+				apiUsersMe.GET("/digest-settings", h.GetMyDigestSetting, requires(permission.GetMe), blockBot)
+				apiUsersMe.PATCH("/digest-settings", h.EditMyDigestSetting, requires(permission.EditMe), blockBot)
+				apiUsersMe.GET("/notification-settings", h.GetMyNotificationSetting, requires(permission.GetMe), blockBot)
+				apiUsersMe.PATCH("/notification-settings", h.EditMyNotificationSetting, requires(permission.EditMe), blockBot)
 				apiUsersMeTags := apiUsersMe.Group("/tags")
 				{
 					apiUsersMeTags.GET("", h.GetMyUserTags, requires(permission.GetUserTag))
@@ -151,12 +178,25 @@ func (h *Handlers) Setup(e *echo.Group) {
 		{
 			apiChannels.GET("", h.GetChannels, requires(permission.GetChannel))
 			apiChannels.POST("", h.CreateChannels, requires(permission.CreateChannel))
+			// requiresでチャンネルロールの権限を考慮するため、先にretrieve.ChannelIDでチャンネルを取得する
 			apiChannelsCID := apiChannels.Group("/:channelID", retrieve.ChannelID(), requiresChannelAccessPerm)
 			{
 				apiChannelsCID.GET("", h.GetChannel, requires(permission.GetChannel))
 				apiChannelsCID.PATCH("", h.EditChannel, requires(permission.EditChannel))
+				apiChannelsCID.DELETE("", h.DeleteChannel, requires(permission.DeleteChannel))
+				apiChannelsCID.POST("/merge", h.MergeChannel, requires(permission.MergeChannel))
 				apiChannelsCID.GET("/messages", h.GetMessages, requires(permission.GetMessage))
-				apiChannelsCID.POST("/messages", h.PostMessage, bodyLimit(100), requires(permission.PostMessage))
+				apiChannelsCID.POST("/messages", h.PostMessage, bodyLimit(100), requires(permission.PostMessage), rateLimit(ratelimit.PostMessage))
+				apiChannelsCIDMessagesScheduled := apiChannelsCID.Group("/messages/scheduled")
+				{
+					apiChannelsCIDMessagesScheduled.GET("", h.GetScheduledMessages, requires(permission.GetMessage))
+					apiChannelsCIDMessagesScheduled.POST("", h.CreateScheduledMessage, bodyLimit(100), requires(permission.PostMessage), rateLimit(ratelimit.PostMessage))
+					apiChannelsCIDMessagesScheduledSMID := apiChannelsCIDMessagesScheduled.Group("/:scheduledMessageID", retrieve.ScheduledMessageID())
+					{
+						apiChannelsCIDMessagesScheduledSMID.PATCH("", h.EditScheduledMessage, bodyLimit(100), requires(permission.PostMessage))
+						apiChannelsCIDMessagesScheduledSMID.DELETE("", h.DeleteScheduledMessage, requires(permission.PostMessage))
+					}
+				}
 				apiChannelsCID.GET("/stats", h.GetChannelStats, requires(permission.GetChannel))
 				apiChannelsCID.GET("/topic", h.GetChannelTopic, requires(permission.GetChannel))
 				apiChannelsCID.PUT("/topic", h.EditChannelTopic, requires(permission.EditChannelTopic))
@@ -166,11 +206,26 @@ func (h *Handlers) Setup(e *echo.Group) {
 				apiChannelsCID.PUT("/subscribers", h.SetChannelSubscribers, requires(permission.EditChannelSubscription))
 				apiChannelsCID.PATCH("/subscribers", h.EditChannelSubscribers, requires(permission.EditChannelSubscription))
 				apiChannelsCID.GET("/bots", h.GetChannelBots, requires(permission.GetChannel))
+				apiChannelsCID.GET("/commands", h.GetChannelCommands, requires(permission.GetChannel))
 				apiChannelsCID.GET("/events", h.GetChannelEvents, requires(permission.GetChannel))
+				apiChannelsCIDMembers := apiChannelsCID.Group("/members")
+				{
+					apiChannelsCIDMembers.GET("", h.GetChannelMembers, requires(permission.GetChannel))
+					apiChannelsCIDMembers.POST("", h.AddChannelMember, requires(permission.EditPrivateChannelMember))
+					apiChannelsCIDMembers.DELETE("/:userID", h.RemoveChannelMember, requires(permission.EditPrivateChannelMember))
+				}
+				apiChannelsCIDRoles := apiChannelsCID.Group("/roles", blockBot)
+				{
+					apiChannelsCIDRoles.GET("", h.GetChannelRoles, requires(permission.GetChannel))
+					apiChannelsCIDRoles.PUT("/:userID", h.SetChannelRole, requires(permission.ManageChannelRole))
+					apiChannelsCIDRoles.DELETE("/:userID", h.DeleteChannelRole, requires(permission.ManageChannelRole))
+				}
 			}
 		}
 		apiMessages := api.Group("/messages")
 		{
+			apiMessages.GET("/search", h.SearchMessages, requires(permission.GetMessage))
+			// requiresでメッセージのチャンネルのチャンネルロールの権限を考慮するため、先にretrieve.MessageIDでメッセージを取得する
 			apiMessagesMID := apiMessages.Group("/:messageID", retrieve.MessageID(), requiresMessageAccessPerm)
 			{
 				apiMessagesMID.GET("", h.GetMessage, requires(permission.GetMessage))
@@ -180,21 +235,35 @@ func (h *Handlers) Setup(e *echo.Group) {
 				apiMessagesMID.POST("/pin", h.CreatePin, requires(permission.CreateMessagePin))
 				apiMessagesMID.DELETE("/pin", h.RemovePin, requires(permission.DeleteMessagePin))
 				apiMessagesMID.GET("/clips", h.GetMessageClips, requires(permission.GetClipFolder))
+				apiMessagesMID.GET("/replies", h.GetMessageReplies, requires(permission.GetMessage))
+				apiMessagesMID.POST("/replies", h.PostMessageReply, bodyLimit(100), requires(permission.PostMessage), rateLimit(ratelimit.PostMessage))
+				apiMessagesMID.POST("/reports", h.PostMessageReport, requires(permission.ReportMessage), blockBot)
+				apiMessagesMID.POST("/interactions", h.PostMessageInteraction, requires(permission.InteractMessageComponent))
 				apiMessagesMIDStamps := apiMessagesMID.Group("/stamps")
 				{
 					apiMessagesMIDStamps.GET("", h.GetMessageStamps, requires(permission.GetMessage))
 					apiMessagesMIDStampsSID := apiMessagesMIDStamps.Group("/:stampID", retrieve.StampID(true))
 					{
-						apiMessagesMIDStampsSID.POST("", h.AddMessageStamp, requires(permission.AddMessageStamp))
+						apiMessagesMIDStampsSID.POST("", h.AddMessageStamp, requires(permission.AddMessageStamp), rateLimit(ratelimit.AddStamp))
 						apiMessagesMIDStampsSID.DELETE("", h.RemoveMessageStamp, requires(permission.RemoveMessageStamp))
 					}
 				}
 			}
 		}
+		apiMessageReports := api.Group("/message-reports", blockBot)
+		{
+			apiMessageReports.GET("", h.GetMessageReports, requires(permission.GetMessageReports))
+			apiMessageReportsRID := apiMessageReports.Group("/:messageReportID", retrieve.MessageReportID())
+			{
+				apiMessageReportsRID.GET("", h.GetMessageReport, requires(permission.GetMessageReports))
+				apiMessageReportsRID.POST("/resolve", h.ResolveMessageReport, requires(permission.HandleMessageReports))
+				apiMessageReportsRID.POST("/dismiss", h.DismissMessageReport, requires(permission.HandleMessageReports))
+			}
+		}
 		apiFiles := api.Group("/files")
 		{
 			apiFiles.GET("", h.GetFiles, requires(permission.DownloadFile))
-			apiFiles.POST("", h.PostFile, bodyLimit(30<<10), requires(permission.UploadFile))
+			apiFiles.POST("", h.PostFile, bodyLimit(30<<10), requires(permission.UploadFile), rateLimit(ratelimit.UploadFile))
 			apiFilesFID := apiFiles.Group("/:fileID", retrieve.FileID(), requiresFileAccessPerm)
 			{
 				apiFilesFID.GET("", h.GetFile, requires(permission.DownloadFile))
@@ -246,6 +315,17 @@ func (h *Handlers) Setup(e *echo.Group) {
 				apiWebhooksWID.GET("/icon", h.GetWebhookIcon, requires(permission.GetWebhook))
 				apiWebhooksWID.PUT("/icon", h.ChangeWebhookIcon, requires(permission.EditWebhook))
 				apiWebhooksWID.GET("/messages", h.GetWebhookMessages, requires(permission.GetWebhook))
+				apiWebhooksWID.GET("/rate-limits", h.GetWebhookRateLimits, requires(permission.GetWebhook))
+				apiWebhooksWID.PUT("/rate-limits/:action", h.SetWebhookRateLimit, adminOnly)
+				apiWebhooksWID.DELETE("/rate-limits/:action", h.DeleteWebhookRateLimit, adminOnly)
+				apiWebhooksWID.GET("/outgoing-webhooks", h.GetOutgoingWebhooks, requires(permission.GetWebhook))
+				apiWebhooksWID.POST("/outgoing-webhooks", h.CreateOutgoingWebhook, requires(permission.EditWebhook))
+				apiWebhooksWIDOID := apiWebhooksWID.Group("/outgoing-webhooks/:outgoingWebhookID", retrieve.OutgoingWebhookID())
+				{
+					apiWebhooksWIDOID.GET("", h.GetOutgoingWebhook, requires(permission.GetWebhook))
+					apiWebhooksWIDOID.PATCH("", h.EditOutgoingWebhook, requires(permission.EditWebhook))
+					apiWebhooksWIDOID.DELETE("", h.DeleteOutgoingWebhook, requires(permission.EditWebhook))
+				}
 			}
 		}
 		apiGroups := api.Group("/groups")
@@ -298,6 +378,7 @@ func (h *Handlers) Setup(e *echo.Group) {
 		{
 			apiBots.GET("", h.GetBots, requires(permission.GetBot))
 			apiBots.POST("", h.CreateBot, requires(permission.CreateBot))
+			apiBots.GET("/ws", h.ConnectBotWS, requires(permission.ConnectBotStream))
 			apiBotsBID := apiBots.Group("/:botID", retrieve.BotID())
 			{
 				apiBotsBID.GET("", h.GetBot, requires(permission.GetBot))
@@ -305,12 +386,21 @@ func (h *Handlers) Setup(e *echo.Group) {
 				apiBotsBID.DELETE("", h.DeleteBot, requiresBotAccessPerm, requires(permission.DeleteBot))
 				apiBotsBID.GET("/icon", h.GetBotIcon, requires(permission.GetBot))
 				apiBotsBID.PUT("/icon", h.ChangeBotIcon, requiresBotAccessPerm, requires(permission.EditBot))
+				apiBotsBID.GET("/commands", h.GetBotCommands, requiresBotAccessPerm, requires(permission.ManageBotCommand))
+				apiBotsBID.PUT("/commands", h.SetBotCommands, requiresBotAccessPerm, requires(permission.ManageBotCommand))
 				apiBotsBID.GET("/logs", h.GetBotLogs, requiresBotAccessPerm, requires(permission.GetBot))
+				apiBotsBID.GET("/rate-limits", h.GetBotRateLimits, requiresBotAccessPerm, requires(permission.GetBot))
+				apiBotsBID.PUT("/rate-limits/:action", h.SetBotRateLimit, adminOnly)
+				apiBotsBID.DELETE("/rate-limits/:action", h.DeleteBotRateLimit, adminOnly)
+				apiBotsBID.GET("/logs/dead", h.GetBotDeadEvents, requiresBotAccessPerm, requires(permission.GetBot))
+				apiBotsBID.POST("/logs/dead/replay", h.ReplayBotDeadEvents, requiresBotAccessPerm, requires(permission.EditBot))
 				apiBotsBIDActions := apiBotsBID.Group("/actions", requiresBotAccessPerm)
 				{
 					apiBotsBIDActions.POST("/activate", h.ActivateBot, requires(permission.EditBot))
 					apiBotsBIDActions.POST("/inactivate", h.InactivateBot, requires(permission.EditBot))
 					apiBotsBIDActions.POST("/reissue", h.ReissueBot, requires(permission.EditBot))
+					apiBotsBIDActions.POST("/rotate-secret", h.RotateBotSigningSecret, requires(permission.EditBot))
+					apiBotsBIDActions.POST("/test-event", h.TestBotEvent, requires(permission.EditBot))
 					apiBotsBIDActions.POST("/join", h.LetBotJoinChannel, requires(permission.BotActionJoinChannel))
 					apiBotsBIDActions.POST("/leave", h.LetBotLeaveChannel, requires(permission.BotActionLeaveChannel))
 				}
@@ -338,6 +428,18 @@ func (h *Handlers) Setup(e *echo.Group) {
 				}
 			}
 		}
+		apiRoles := api.Group("/roles", blockBot, adminOnly)
+		{
+			apiRoles.GET("", h.GetUserRoles)
+			apiRoles.POST("", h.CreateUserRole)
+			apiRolesRName := apiRoles.Group("/:roleName", retrieve.RoleName())
+			{
+				apiRolesRName.GET("", h.GetUserRole)
+				apiRolesRName.PATCH("", h.EditUserRole)
+				apiRolesRName.DELETE("", h.DeleteUserRole)
+			}
+		}
+		api.GET("/export", h.ExportData, blockBot, adminOnly)
 		api.GET("/ws", echo.WrapHandler(h.WS), requires(permission.ConnectNotificationStream), blockBot)
 	}
 
@@ -347,9 +449,13 @@ func (h *Handlers) Setup(e *echo.Group) {
 		apiNoAuth.POST("/login", h.Login, nologin)
 		apiNoAuth.POST("/logout", h.Logout)
 		apiNoAuth.POST("/webhooks/:webhookID", h.PostWebhook, retrieve.WebhookID())
+		apiNoAuth.POST("/webhooks/:webhookID/slack", h.PostWebhookSlack, retrieve.WebhookID())
 		apiNoAuthPublic := apiNoAuth.Group("/public")
 		{
 			apiNoAuthPublic.GET("/icon/:username", h.GetPublicUserIcon)
+			apiNoAuthPublic.GET("/digest/unsubscribe", h.UnsubscribeDigest)
+			apiNoAuthPublic.POST("/digest/unsubscribe", h.UnsubscribeDigest)
+			apiNoAuthPublic.GET("/digest/verify", h.VerifyDigestEmail)
 		}
 	}
 }
@@ -14,10 +14,15 @@ import (
 	"github.com/traPtitech/traQ/router/extension"
 	"github.com/traPtitech/traQ/router/session"
 	"github.com/traPtitech/traQ/service/channel"
+	"github.com/traPtitech/traQ/service/cluster"
+	"github.com/traPtitech/traQ/service/digest"
+	"github.com/traPtitech/traQ/service/file"
 	"github.com/traPtitech/traQ/service/imaging"
+	"github.com/traPtitech/traQ/service/ratelimit"
 	"github.com/traPtitech/traQ/service/rbac"
 	"github.com/traPtitech/traQ/service/rbac/role"
 	"github.com/traPtitech/traQ/utils/random"
+	"github.com/traPtitech/traQ/utils/storage"
 	"go.uber.org/zap"
 	"image"
 	"net/http"
@@ -75,7 +80,7 @@ func TestMain(m *testing.M) {
 			panic(err)
 		}
 		env.Repository = repo
-		env.CM, _ = channel.InitChannelManager(repo, zap.NewNop())
+		env.CM, _ = channel.InitChannelManager(repo, cluster.NewStandaloneBus(), zap.NewNop())
 
 		// テスト用サーバー作成
 		e := echo.New()
@@ -84,10 +89,27 @@ func TestMain(m *testing.M) {
 		e.HTTPErrorHandler = extension.ErrorHandler(zap.NewNop())
 		e.Use(extension.Wrap(repo, env.CM))
 
-		r, err := rbac.New(db)
+		r, err := rbac.New(db, env.Hub, env.CM, cluster.NewStandaloneBus(), zap.NewNop())
 		if err != nil {
 			panic(err)
 		}
+		env.RBAC = r
+		limiter, err := ratelimit.NewLimiter(repo, ratelimit.Config{}, cluster.NewStandaloneBus())
+		if err != nil {
+			panic(err)
+		}
+		env.RateLimiter = limiter
+		processor := imaging.NewProcessor(imaging.Config{
+			MaxPixels:        1000 * 1000,
+			Concurrency:      1,
+			ThumbnailMaxSize: image.Pt(360, 480),
+			ImageMagickPath:  "",
+		})
+		fm, err := file.InitFileManager(repo, storage.NewInMemoryFileStorage(), processor, zap.NewNop())
+		if err != nil {
+			panic(err)
+		}
+		env.FileManager = fm
 		handlers := &Handlers{
 			RBAC:           r,
 			Repo:           env.Repository,
@@ -95,15 +117,14 @@ func TestMain(m *testing.M) {
 			SessStore:      env.SessStore,
 			ChannelManager: env.CM,
 			Logger:         zap.NewNop(),
-			Imaging: imaging.NewProcessor(imaging.Config{
-				MaxPixels:        1000 * 1000,
-				Concurrency:      1,
-				ThumbnailMaxSize: image.Pt(360, 480),
-				ImageMagickPath:  "",
-			}),
+			RateLimiter:    limiter,
+			Digest:         digest.NewNullService(),
+			FileManager:    fm,
+			Imaging:        processor,
 			Config: Config{
 				Version:  "version",
 				Revision: "revision",
+				Origin:   "http://test",
 			},
 		}
 		handlers.Setup(e.Group("/api"))
@@ -125,12 +146,15 @@ func TestMain(m *testing.M) {
 }
 
 type Env struct {
-	Server     *httptest.Server
-	DB         *gorm.DB
-	Repository repository.Repository
-	CM         channel.Manager
-	Hub        *hub.Hub
-	SessStore  session.Store
+	Server      *httptest.Server
+	DB          *gorm.DB
+	Repository  repository.Repository
+	CM          channel.Manager
+	RBAC        rbac.RBAC
+	Hub         *hub.Hub
+	SessStore   session.Store
+	RateLimiter ratelimit.Limiter
+	FileManager file.Manager
 }
 
 // Setup テストセットアップ
@@ -182,6 +206,28 @@ func (env *Env) CreateUser(t *testing.T, userName string) model.UserInfo {
 	return u
 }
 
+// CreateChannel チャンネルを必ず作成します
+func (env *Env) CreateChannel(t *testing.T, name string) *model.Channel {
+	t.Helper()
+	if name == rand {
+		name = random.AlphaNumeric(20)
+	}
+	ch, err := env.CM.CreatePublicChannel(name, uuid.Nil, uuid.Nil)
+	require.NoError(t, err)
+	return ch
+}
+
+// CreateWebhook Webhookを必ず作成します
+func (env *Env) CreateWebhook(t *testing.T, name string, creatorID, channelID uuid.UUID, secret string) model.Webhook {
+	t.Helper()
+	if name == rand {
+		name = random.AlphaNumeric(20)
+	}
+	w, err := env.Repository.CreateWebhook(name, "desc", channelID, uuid.Must(uuid.NewV4()), creatorID, secret)
+	require.NoError(t, err)
+	return w
+}
+
 func getEnvOrDefault(env string, def string) string {
 	s := os.Getenv(env)
 	if len(s) == 0 {
@@ -0,0 +1,145 @@
+package v3
+
+import (
+	"fmt"
+	vd "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/labstack/echo/v4"
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/router/extension/herror"
+	"github.com/traPtitech/traQ/utils/optional"
+	"net/http"
+	"time"
+)
+
+// scheduledMessageMaxDuration 予約投稿可能な最大期間
+const scheduledMessageMaxDuration = 365 * 24 * time.Hour
+
+// GetScheduledMessages GET /channels/:channelID/messages/scheduled
+func (h *Handlers) GetScheduledMessages(c echo.Context) error {
+	userID := getRequestUserID(c)
+	ch := getParamChannel(c)
+
+	messages, err := h.Repo.GetScheduledMessages(repository.ScheduledMessagesQuery{User: userID, Channel: ch.ID})
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+	return c.JSON(http.StatusOK, formatScheduledMessages(messages))
+}
+
+// PostScheduledMessageRequest POST /channels/:channelID/messages/scheduled リクエストボディ
+type PostScheduledMessageRequest struct {
+	Content     string    `json:"content"`
+	Embed       bool      `json:"embed"`
+	ScheduledAt time.Time `json:"scheduledAt"`
+}
+
+func (r PostScheduledMessageRequest) Validate() error {
+	now := time.Now()
+	return vd.ValidateStruct(&r,
+		vd.Field(&r.Content, vd.Required, vd.RuneLength(1, 10000)),
+		vd.Field(&r.ScheduledAt, vd.Required, vd.Min(now), vd.Max(now.Add(scheduledMessageMaxDuration))),
+	)
+}
+
+// CreateScheduledMessage POST /channels/:channelID/messages/scheduled
+func (h *Handlers) CreateScheduledMessage(c echo.Context) error {
+	userID := getRequestUserID(c)
+	ch := getParamChannel(c)
+
+	if ch.IsArchived() {
+		return herror.BadRequest(fmt.Sprintf("channel #%s has been archived", h.ChannelManager.PublicChannelTree().GetChannelPath(ch.ID)))
+	}
+
+	var req PostScheduledMessageRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	if req.Embed {
+		req.Content = h.Replacer.Replace(req.Content)
+	}
+
+	m, err := h.Repo.CreateScheduledMessage(userID, ch.ID, req.Content, req.ScheduledAt)
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+	return c.JSON(http.StatusCreated, formatScheduledMessage(m))
+}
+
+// PatchScheduledMessageRequest PATCH /channels/:channelID/messages/scheduled/:scheduledMessageID リクエストボディ
+type PatchScheduledMessageRequest struct {
+	Content     optional.String `json:"content"`
+	Embed       bool            `json:"embed"`
+	ScheduledAt optional.Time   `json:"scheduledAt"`
+}
+
+func (r PatchScheduledMessageRequest) Validate() error {
+	now := time.Now()
+	return vd.ValidateStruct(&r,
+		vd.Field(&r.Content, vd.RuneLength(1, 10000)),
+		vd.Field(&r.ScheduledAt, vd.By(func(value interface{}) error {
+			t := value.(optional.Time)
+			if !t.Valid {
+				return nil
+			}
+			return vd.Validate(t.Time, vd.Min(now), vd.Max(now.Add(scheduledMessageMaxDuration)))
+		})),
+	)
+}
+
+// EditScheduledMessage PATCH /channels/:channelID/messages/scheduled/:scheduledMessageID
+func (h *Handlers) EditScheduledMessage(c echo.Context) error {
+	userID := getRequestUserID(c)
+	ch := getParamChannel(c)
+	m := getParamScheduledMessage(c)
+
+	// 他人の予約投稿は見えない
+	if m.ChannelID != ch.ID || m.UserID != userID {
+		return herror.NotFound()
+	}
+
+	var req PatchScheduledMessageRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	if req.Embed && req.Content.Valid {
+		req.Content.String = h.Replacer.Replace(req.Content.String)
+	}
+
+	args := repository.UpdateScheduledMessageArgs{
+		Text:        req.Content,
+		ScheduledAt: req.ScheduledAt,
+	}
+	if err := h.Repo.UpdateScheduledMessage(m.ID, args); err != nil {
+		switch err {
+		case repository.ErrNotFound:
+			return herror.NotFound() // 既に投稿された
+		default:
+			return herror.InternalServerError(err)
+		}
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// DeleteScheduledMessage DELETE /channels/:channelID/messages/scheduled/:scheduledMessageID
+func (h *Handlers) DeleteScheduledMessage(c echo.Context) error {
+	userID := getRequestUserID(c)
+	ch := getParamChannel(c)
+	m := getParamScheduledMessage(c)
+
+	// 他人の予約投稿は見えない
+	if m.ChannelID != ch.ID || m.UserID != userID {
+		return herror.NotFound()
+	}
+
+	if err := h.Repo.DeleteScheduledMessage(m.ID); err != nil {
+		switch err {
+		case repository.ErrNotFound:
+			return herror.NotFound() // 既に投稿された
+		default:
+			return herror.InternalServerError(err)
+		}
+	}
+	return c.NoContent(http.StatusNoContent)
+}
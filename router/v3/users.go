@@ -2,8 +2,11 @@ package v3
 
 import (
 	"context"
+	"encoding/base64"
+	"errors"
 	"github.com/dgrijalva/jwt-go"
 	vd "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
 	"github.com/gofrs/uuid"
 	"github.com/labstack/echo/v4"
 	"github.com/skip2/go-qrcode"
@@ -19,6 +22,7 @@ import (
 	"github.com/traPtitech/traQ/utils/optional"
 	"github.com/traPtitech/traQ/utils/validator"
 	"net/http"
+	"strings"
 	"time"
 )
 
@@ -296,6 +300,88 @@ func (h *Handlers) PostMyFCMDevice(c echo.Context) error {
 	return c.NoContent(http.StatusNoContent)
 }
 
+// PostMyWebPushSubscriptionRequest POST /users/me/web-push-subscriptions リクエストボディ
+type PostMyWebPushSubscriptionRequest struct {
+	Endpoint string `json:"endpoint"`
+	Keys     struct {
+		P256dh string `json:"p256dh"`
+		Auth   string `json:"auth"`
+	} `json:"keys"`
+}
+
+func (r PostMyWebPushSubscriptionRequest) Validate() error {
+	return vd.ValidateStruct(&r,
+		vd.Field(&r.Endpoint, vd.Required, vd.RuneLength(1, 2000), is.URL, validator.NotInternalURL),
+		vd.Field(&r.Keys, vd.By(func(interface{}) error {
+			return vd.ValidateStruct(&r.Keys,
+				vd.Field(&r.Keys.P256dh, vd.Required, vd.By(webPushKeyRule(65))),
+				vd.Field(&r.Keys.Auth, vd.Required, vd.By(webPushKeyRule(16))),
+			)
+		})),
+	)
+}
+
+// webPushKeyRule Base64URLエンコードされた指定バイト長の鍵かどうか
+func webPushKeyRule(size int) vd.RuleFunc {
+	return func(value interface{}) error {
+		s, _ := value.(string)
+		if len(s) == 0 {
+			return nil
+		}
+		b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+		if err != nil || len(b) != size {
+			return errors.New("must be a valid base64url encoded key")
+		}
+		return nil
+	}
+}
+
+// PostMyWebPushSubscription POST /users/me/web-push-subscriptions
+func (h *Handlers) PostMyWebPushSubscription(c echo.Context) error {
+	var req PostMyWebPushSubscriptionRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	userID := getRequestUserID(c)
+	if err := h.Repo.RegisterWebPushSubscription(userID, req.Endpoint, req.Keys.P256dh, req.Keys.Auth); err != nil {
+		switch {
+		case repository.IsArgError(err):
+			return herror.BadRequest(err)
+		default:
+			return herror.InternalServerError(err)
+		}
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// DeleteMyWebPushSubscriptionRequest DELETE /users/me/web-push-subscriptions リクエストボディ
+type DeleteMyWebPushSubscriptionRequest struct {
+	Endpoint string `json:"endpoint"`
+}
+
+func (r DeleteMyWebPushSubscriptionRequest) Validate() error {
+	return vd.ValidateStruct(&r,
+		vd.Field(&r.Endpoint, vd.Required),
+	)
+}
+
+// DeleteMyWebPushSubscription DELETE /users/me/web-push-subscriptions
+func (h *Handlers) DeleteMyWebPushSubscription(c echo.Context) error {
+	var req DeleteMyWebPushSubscriptionRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	userID := getRequestUserID(c)
+	if err := h.Repo.DeleteWebPushSubscription(userID, req.Endpoint); err != nil {
+		return herror.InternalServerError(err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
 // PutUserPasswordRequest PUT /users/:userID/password リクエストボディ
 type PutUserPasswordRequest struct {
 	NewPassword string `json:"newPassword"`
@@ -1,10 +1,17 @@
 package v3
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	crand "crypto/rand"
+	"encoding/base64"
+	"github.com/gofrs/uuid"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/traPtitech/traQ/router/session"
+	random2 "github.com/traPtitech/traQ/utils/random"
+	"github.com/traPtitech/traQ/utils/set"
 	"net/http"
 	"strings"
 	"testing"
@@ -91,3 +98,85 @@ func TestHandlers_PutMyPassword(t *testing.T) {
 		assert.NoError(t, u.Authenticate(new))
 	})
 }
+
+func TestHandlers_PostMyWebPushSubscription(t *testing.T) {
+	t.Parallel()
+	path := "/api/v3/users/me/web-push-subscriptions"
+	env := Setup(t, common)
+	user := env.CreateUser(t, rand)
+	s := env.S(t, user.GetID())
+
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), crand.Reader)
+	require.NoError(t, err)
+	p256dh := base64.RawURLEncoding.EncodeToString(elliptic.Marshal(elliptic.P256(), priv.X, priv.Y))
+	auth := base64.RawURLEncoding.EncodeToString([]byte(random2.AlphaNumeric(16)))
+	endpoint := "https://1.1.1.1/push/" + random2.AlphaNumeric(20)
+
+	t.Run("NotLoggedIn", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.POST(path).
+			WithJSON(echo.Map{"endpoint": endpoint, "keys": echo.Map{"p256dh": p256dh, "auth": auth}}).
+			Expect().
+			Status(http.StatusUnauthorized)
+	})
+
+	t.Run("internal endpoint", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.POST(path).
+			WithCookie(session.CookieName, s).
+			WithJSON(echo.Map{"endpoint": "http://127.0.0.1/push", "keys": echo.Map{"p256dh": p256dh, "auth": auth}}).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	t.Run("invalid keys", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.POST(path).
+			WithCookie(session.CookieName, s).
+			WithJSON(echo.Map{"endpoint": endpoint, "keys": echo.Map{"p256dh": "invalid", "auth": auth}}).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		user := env.CreateUser(t, rand)
+		s := env.S(t, user.GetID())
+		endpoint := "https://1.1.1.1/push/" + random2.AlphaNumeric(20)
+
+		e := env.R(t)
+		e.POST(path).
+			WithCookie(session.CookieName, s).
+			WithJSON(echo.Map{"endpoint": endpoint, "keys": echo.Map{"p256dh": p256dh, "auth": auth}}).
+			Expect().
+			Status(http.StatusNoContent)
+
+		subs, err := env.Repository.GetWebPushSubscriptions(set.UUIDSetFromArray([]uuid.UUID{user.GetID()}))
+		require.NoError(t, err)
+		if assert.Len(t, subs[user.GetID()], 1) {
+			assert.Equal(t, endpoint, subs[user.GetID()][0].Endpoint)
+		}
+
+		// 他のユーザーの購読は削除できない
+		e.DELETE(path).
+			WithCookie(session.CookieName, env.S(t, env.CreateUser(t, rand).GetID())).
+			WithJSON(echo.Map{"endpoint": endpoint}).
+			Expect().
+			Status(http.StatusNoContent)
+		subs, err = env.Repository.GetWebPushSubscriptions(set.UUIDSetFromArray([]uuid.UUID{user.GetID()}))
+		require.NoError(t, err)
+		assert.Len(t, subs[user.GetID()], 1)
+
+		e.DELETE(path).
+			WithCookie(session.CookieName, s).
+			WithJSON(echo.Map{"endpoint": endpoint}).
+			Expect().
+			Status(http.StatusNoContent)
+		subs, err = env.Repository.GetWebPushSubscriptions(set.UUIDSetFromArray([]uuid.UUID{user.GetID()}))
+		require.NoError(t, err)
+		assert.Len(t, subs[user.GetID()], 0)
+	})
+}
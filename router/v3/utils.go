@@ -82,6 +82,26 @@ func getParamChannel(c echo.Context) *model.Channel {
 	return c.Get(consts.KeyParamChannel).(*model.Channel)
 }
 
+// getParamMessageReport URLの:messageReportIDに対応するMessageReportを取得
+func getParamMessageReport(c echo.Context) *model.MessageReport {
+	return c.Get(consts.KeyParamMessageReport).(*model.MessageReport)
+}
+
+// getParamUserRole URLの:roleNameに対応するUserRoleを取得
+func getParamUserRole(c echo.Context) *model.UserRole {
+	return c.Get(consts.KeyParamUserRole).(*model.UserRole)
+}
+
+// getParamScheduledMessage URLの:scheduledMessageIDに対応するScheduledMessageを取得
+func getParamScheduledMessage(c echo.Context) *model.ScheduledMessage {
+	return c.Get(consts.KeyParamScheduledMessage).(*model.ScheduledMessage)
+}
+
+// getParamOutgoingWebhook URLの:outgoingWebhookIDに対応するOutgoingWebhookを取得
+func getParamOutgoingWebhook(c echo.Context) *model.OutgoingWebhook {
+	return c.Get(consts.KeyParamOutgoingWebhook).(*model.OutgoingWebhook)
+}
+
 // getParamMessage URLの:messageIDに対応するMessageを取得
 func getParamMessage(c echo.Context) *model.Message {
 	return c.Get(consts.KeyParamMessage).(*model.Message)
@@ -139,6 +159,13 @@ func (q *MessagesQuery) convert() repository.MessagesQuery {
 func (q *MessagesQuery) convertC(cid uuid.UUID) repository.MessagesQuery {
 	r := q.convert()
 	r.Channel = cid
+	r.ExcludeReplies = true
+	return r
+}
+
+func (q *MessagesQuery) convertP(pid uuid.UUID) repository.MessagesQuery {
+	r := q.convert()
+	r.Parent = pid
 	return r
 }
 
@@ -7,19 +7,27 @@ import (
 	"fmt"
 	vd "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/gofrs/uuid"
+	jsoniter "github.com/json-iterator/go"
 	"github.com/labstack/echo/v4"
 	"github.com/traPtitech/traQ/model"
 	"github.com/traPtitech/traQ/repository"
 	"github.com/traPtitech/traQ/router/consts"
 	"github.com/traPtitech/traQ/router/extension/herror"
+	"github.com/traPtitech/traQ/router/middlewares"
 	"github.com/traPtitech/traQ/router/utils"
 	"github.com/traPtitech/traQ/service/file"
 	"github.com/traPtitech/traQ/service/rbac/permission"
 	"github.com/traPtitech/traQ/utils/hmac"
 	"github.com/traPtitech/traQ/utils/optional"
+	"github.com/traPtitech/traQ/utils/slack"
 	"github.com/traPtitech/traQ/utils/validator"
+	"hash"
+	"io"
 	"io/ioutil"
+	"mime"
+	"mime/multipart"
 	"net/http"
+	"net/url"
 	"strings"
 )
 
@@ -157,66 +165,349 @@ func (h *Handlers) EditWebhook(c echo.Context) error {
 	return c.NoContent(http.StatusNoContent)
 }
 
+const (
+	// webhookMaxBodySize Webhookのリクエストボディの最大サイズ
+	webhookMaxBodySize = 30 << 20 // 30MB
+	// webhookMaxFormMemory multipart/form-dataの解析時にメモリに保持するファイルの最大サイズ(超えた分は一時ファイルに保存)
+	webhookMaxFormMemory = 1 << 20 // 1MB
+)
+
+// PostWebhookRequest POST /webhooks/:webhookID JSONリクエストボディ
+type PostWebhookRequest struct {
+	Text      string `json:"text"`
+	Username  string `json:"username"`
+	IconStamp string `json:"iconStamp"`
+}
+
+func (r PostWebhookRequest) Validate() error {
+	return vd.ValidateStruct(&r,
+		vd.Field(&r.Username, vd.RuneLength(0, 32)),
+		vd.Field(&r.IconStamp, validator.StampNameRule...),
+	)
+}
+
 // PostWebhook POST /webhooks/:webhookID
 func (h *Handlers) PostWebhook(c echo.Context) error {
 	w := getParamWebhook(c)
-	channelID := w.GetChannelID()
 
-	// text/plainのみ受け付ける
-	switch strings.ToLower(c.Request().Header.Get(echo.HeaderContentType)) {
-	case echo.MIMETextPlain, strings.ToLower(echo.MIMETextPlainCharsetUTF8):
-		break
+	// text/plain, application/json, multipart/form-data を受け付ける
+	var (
+		req   PostWebhookRequest
+		files []*multipart.FileHeader
+	)
+	mediaType, params, _ := mime.ParseMediaType(c.Request().Header.Get(echo.HeaderContentType))
+	if mediaType == echo.MIMEMultipartForm {
+		form, err := readWebhookForm(c, w, params["boundary"])
+		if err != nil {
+			return err
+		}
+		defer form.RemoveAll()
+		req.Text = firstFormValue(form.Value, "text")
+		req.Username = firstFormValue(form.Value, "username")
+		req.IconStamp = firstFormValue(form.Value, "iconStamp")
+		files = form.File["file"]
+	} else {
+		body, err := readWebhookBody(c)
+		if err != nil {
+			return err
+		}
+		if err := verifyWebhookSignature(c, w, body); err != nil {
+			return err
+		}
+		switch mediaType {
+		case echo.MIMETextPlain:
+			req.Text = string(body)
+		case echo.MIMEApplicationJSON:
+			if err := jsoniter.ConfigFastest.Unmarshal(body, &req); err != nil {
+				return herror.BadRequest(err)
+			}
+		default:
+			return echo.NewHTTPError(http.StatusUnsupportedMediaType)
+		}
+	}
+	if err := middlewares.AllowWebhook(c, h.RateLimiter, w); err != nil {
+		return err
+	}
+	if err := req.Validate(); err != nil {
+		return herror.BadRequest(err)
+	}
+	if len(req.Text) == 0 && len(files) == 0 {
+		return herror.BadRequest("empty body")
+	}
+
+	channelID, err := h.getWebhookTargetChannel(c, w)
+	if err != nil {
+		return err
+	}
+
+	// アイコンスタンプ確認
+	var iconFileID optional.UUID
+	if len(req.IconStamp) > 0 {
+		stamp, err := h.Repo.GetStampByName(req.IconStamp)
+		if err != nil {
+			switch err {
+			case repository.ErrNotFound:
+				return herror.BadRequest("unknown iconStamp")
+			default:
+				return herror.InternalServerError(err)
+			}
+		}
+		iconFileID = optional.UUIDFrom(stamp.FileID)
+	}
+
+	// 埋め込み変換
+	text := req.Text
+	if isTrue(c.QueryParam("embed")) {
+		text = h.Replacer.Replace(text)
+	}
+
+	// 添付ファイル保存
+	for _, fh := range files {
+		f, err := h.saveWebhookFile(w, channelID, fh)
+		if err != nil {
+			return herror.InternalServerError(err)
+		}
+		if len(text) > 0 {
+			text += "\n"
+		}
+		text += h.Config.Origin + "/files/" + f.GetID().String()
+	}
+
+	if err := h.postWebhookMessage(w, channelID, text, req.Username, iconFileID); err != nil {
+		return herror.InternalServerError(err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// PostWebhookSlack POST /webhooks/:webhookID/slack
+func (h *Handlers) PostWebhookSlack(c echo.Context) error {
+	w := getParamWebhook(c)
+
+	body, err := readWebhookBody(c)
+	if err != nil {
+		return err
+	}
+	if err := verifyWebhookSignature(c, w, body); err != nil {
+		return err
+	}
+	if err := middlewares.AllowWebhook(c, h.RateLimiter, w); err != nil {
+		return err
+	}
+
+	// application/json, application/x-www-form-urlencoded (payloadフィールド) を受け付ける
+	var payload slack.Payload
+	mediaType, _, _ := mime.ParseMediaType(c.Request().Header.Get(echo.HeaderContentType))
+	switch mediaType {
+	case echo.MIMEApplicationJSON:
+		if err := jsoniter.ConfigFastest.Unmarshal(body, &payload); err != nil {
+			return herror.BadRequest(err)
+		}
+	case echo.MIMEApplicationForm:
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return herror.BadRequest(err)
+		}
+		if err := jsoniter.ConfigFastest.Unmarshal([]byte(values.Get("payload")), &payload); err != nil {
+			return herror.BadRequest("invalid payload")
+		}
 	default:
 		return echo.NewHTTPError(http.StatusUnsupportedMediaType)
 	}
 
-	body, err := ioutil.ReadAll(c.Request().Body)
+	text := payload.Markdown()
+	if len(text) == 0 {
+		return herror.BadRequest("no_text")
+	}
+
+	channelID, err := h.getWebhookTargetChannel(c, w)
 	if err != nil {
+		return err
+	}
+
+	// 表示名は32文字に切り詰める
+	username := []rune(payload.Username)
+	if len(username) > 32 {
+		username = username[:32]
+	}
+
+	// icon_emojiは同名のスタンプがあれば使用し、無ければ無視する
+	var iconFileID optional.UUID
+	if name := payload.IconStampName(); len(name) > 0 {
+		stamp, err := h.Repo.GetStampByName(name)
+		switch err {
+		case nil:
+			iconFileID = optional.UUIDFrom(stamp.FileID)
+		case repository.ErrNotFound:
+			break
+		default:
+			return herror.InternalServerError(err)
+		}
+	}
+
+	if err := h.postWebhookMessage(w, channelID, text, string(username), iconFileID); err != nil {
 		return herror.InternalServerError(err)
 	}
+
+	return c.String(http.StatusOK, "ok")
+}
+
+// readWebhookBody Webhookのリクエストボディを読み込みます
+func readWebhookBody(c echo.Context) ([]byte, error) {
+	body, err := ioutil.ReadAll(io.LimitReader(c.Request().Body, webhookMaxBodySize+1))
+	if err != nil {
+		return nil, herror.InternalServerError(err)
+	}
+	if len(body) > webhookMaxBodySize {
+		return nil, echo.NewHTTPError(http.StatusRequestEntityTooLarge)
+	}
 	if len(body) == 0 {
-		return herror.BadRequest("empty body")
+		return nil, herror.BadRequest("empty body")
 	}
+	return body, nil
+}
 
-	// Webhookシークレット確認
-	if len(w.GetSecret()) > 0 {
-		sig, _ := hex.DecodeString(c.Request().Header.Get(consts.HeaderSignature))
-		if len(sig) == 0 {
-			return herror.BadRequest("missing X-TRAQ-Signature header")
-		}
-		if subtle.ConstantTimeCompare(hmac.SHA1(body, w.GetSecret()), sig) != 1 {
-			return herror.BadRequest("X-TRAQ-Signature is wrong")
+// readWebhookForm multipart/form-dataのリクエストボディを、署名を確認しながら解析します
+//
+// ボディ全体をメモリに読み込まず、webhookMaxFormMemoryを超えるファイルは一時ファイルに保存されます。
+func readWebhookForm(c echo.Context, w model.Webhook, boundary string) (*multipart.Form, error) {
+	v := newWebhookSignatureVerifier(c, w)
+	body := &io.LimitedReader{R: c.Request().Body, N: webhookMaxBodySize + 1}
+	form, err := multipart.NewReader(io.TeeReader(body, v), boundary).ReadForm(webhookMaxFormMemory)
+	if err != nil {
+		if body.N <= 0 {
+			return nil, echo.NewHTTPError(http.StatusRequestEntityTooLarge)
 		}
+		return nil, herror.BadRequest(err)
+	}
+
+	// 終端以降のデータも署名の対象
+	if _, err := io.Copy(v, body); err != nil {
+		_ = form.RemoveAll()
+		return nil, herror.InternalServerError(err)
 	}
+	if body.N <= 0 {
+		_ = form.RemoveAll()
+		return nil, echo.NewHTTPError(http.StatusRequestEntityTooLarge)
+	}
+	if err := v.Verify(); err != nil {
+		_ = form.RemoveAll()
+		return nil, err
+	}
+	return form, nil
+}
+
+// verifyWebhookSignature Webhookシークレットによる署名を確認します
+func verifyWebhookSignature(c echo.Context, w model.Webhook, body []byte) error {
+	v := newWebhookSignatureVerifier(c, w)
+	_, _ = v.Write(body)
+	return v.Verify()
+}
+
+// webhookSignatureVerifier 書き込まれたリクエストボディのWebhookシークレットによる署名を確認します
+type webhookSignatureVerifier struct {
+	header string
+	sig    []byte
+	mac    hash.Hash
+}
+
+// newWebhookSignatureVerifier リクエストのwebhookSignatureVerifierを生成します
+//
+// X-TRAQ-Signature-256 (HMAC-SHA256) が指定されていればそちらを優先し、無ければ X-TRAQ-Signature (HMAC-SHA1) を確認します。
+// シークレットが設定されていないWebhookの場合は、署名を確認しません。
+func newWebhookSignatureVerifier(c echo.Context, w model.Webhook) *webhookSignatureVerifier {
+	if len(w.GetSecret()) == 0 {
+		return &webhookSignatureVerifier{}
+	}
+	if v := c.Request().Header.Get(consts.HeaderSignature256); len(v) > 0 {
+		sig, _ := hex.DecodeString(strings.TrimPrefix(v, "sha256="))
+		return &webhookSignatureVerifier{header: consts.HeaderSignature256, sig: sig, mac: hmac.NewSHA256(w.GetSecret())}
+	}
+	sig, _ := hex.DecodeString(c.Request().Header.Get(consts.HeaderSignature))
+	return &webhookSignatureVerifier{header: consts.HeaderSignature, sig: sig, mac: hmac.NewSHA1(w.GetSecret())}
+}
+
+// Write implements io.Writer interface.
+func (v *webhookSignatureVerifier) Write(p []byte) (int, error) {
+	if v.mac == nil {
+		return len(p), nil
+	}
+	return v.mac.Write(p)
+}
+
+// Verify それまでに書き込まれたボディの署名を確認します
+func (v *webhookSignatureVerifier) Verify() error {
+	if v.mac == nil {
+		return nil
+	}
+	if v.header == consts.HeaderSignature && len(v.sig) == 0 {
+		return herror.BadRequest("missing X-TRAQ-Signature header")
+	}
+	if subtle.ConstantTimeCompare(v.mac.Sum(nil), v.sig) != 1 {
+		return herror.BadRequest(v.header + " is wrong")
+	}
+	return nil
+}
+
+// getWebhookTargetChannel Webhookの投稿先チャンネルを取得します
+func (h *Handlers) getWebhookTargetChannel(c echo.Context, w model.Webhook) (uuid.UUID, error) {
+	channelID := w.GetChannelID()
 
 	// 投稿先チャンネル変更
 	if cid := c.Request().Header.Get(consts.HeaderChannelID); len(cid) > 0 {
 		id, err := uuid.FromString(cid)
 		if err != nil {
-			return herror.BadRequest(fmt.Sprintf("invalid %s header", consts.HeaderChannelID))
+			return uuid.Nil, herror.BadRequest(fmt.Sprintf("invalid %s header", consts.HeaderChannelID))
 		}
 		channelID = id
 	}
 
 	// 投稿先チャンネル確認
 	if !h.ChannelManager.PublicChannelTree().IsChannelPresent(channelID) {
-		return herror.BadRequest("invalid channel")
+		return uuid.Nil, herror.BadRequest("invalid channel")
 	}
 	if h.ChannelManager.PublicChannelTree().IsArchivedChannel(channelID) {
-		return herror.BadRequest(fmt.Sprintf("channel #%s has been archived", h.ChannelManager.PublicChannelTree().GetChannelPath(channelID)))
+		return uuid.Nil, herror.BadRequest(fmt.Sprintf("channel #%s has been archived", h.ChannelManager.PublicChannelTree().GetChannelPath(channelID)))
 	}
+	return channelID, nil
+}
 
-	// 埋め込み変換
-	if isTrue(c.QueryParam("embed")) {
-		body = []byte(h.Replacer.Replace(string(body)))
+// saveWebhookFile Webhookで送信されたファイルを保存します
+func (h *Handlers) saveWebhookFile(w model.Webhook, channelID uuid.UUID, fh *multipart.FileHeader) (model.File, error) {
+	src, err := fh.Open()
+	if err != nil {
+		return nil, err
 	}
+	defer src.Close()
+
+	return h.FileManager.Save(file.SaveArgs{
+		FileName:  fh.Filename,
+		FileSize:  fh.Size,
+		MimeType:  fh.Header.Get(echo.HeaderContentType),
+		FileType:  model.FileTypeUserFile,
+		CreatorID: optional.UUIDFrom(w.GetBotUserID()),
+		ChannelID: optional.UUIDFrom(channelID),
+		Src:       src,
+	})
+}
 
-	// メッセージ投稿
-	if _, err := h.Repo.CreateMessage(w.GetBotUserID(), channelID, string(body)); err != nil {
-		return herror.InternalServerError(err)
+// postWebhookMessage Webhookとしてメッセージを投稿します
+func (h *Handlers) postWebhookMessage(w model.Webhook, channelID uuid.UUID, text string, username string, iconFileID optional.UUID) error {
+	m, err := h.Repo.CreateMessage(w.GetBotUserID(), channelID, text)
+	if err != nil {
+		return err
+	}
+	if len(username) > 0 || iconFileID.Valid {
+		return h.Repo.SetMessageAuthorOverride(m.ID, username, iconFileID)
 	}
+	return nil
+}
 
-	return c.NoContent(http.StatusNoContent)
+func firstFormValue(values map[string][]string, key string) string {
+	if v := values[key]; len(v) > 0 {
+		return v[0]
+	}
+	return ""
 }
 
 // DeleteWebhook DELETE /webhooks/:webhookID
@@ -0,0 +1,251 @@
+package v3
+
+import (
+	"bytes"
+	"encoding/hex"
+	"github.com/gofrs/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/router/consts"
+	"github.com/traPtitech/traQ/service/ratelimit"
+	"github.com/traPtitech/traQ/utils/hmac"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// latestMessage チャンネルの最新のメッセージを取得します
+func (env *Env) latestMessage(t *testing.T, channelID uuid.UUID) *model.Message {
+	t.Helper()
+	messages, _, err := env.Repository.GetMessages(repository.MessagesQuery{Channel: channelID, Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	return messages[0]
+}
+
+func TestHandlers_PostWebhook(t *testing.T) {
+	t.Parallel()
+	path := "/api/v3/webhooks/{webhookID}"
+	env := Setup(t, common)
+	user := env.CreateUser(t, rand)
+
+	t.Run("text/plain", func(t *testing.T) {
+		t.Parallel()
+		ch := env.CreateChannel(t, rand)
+		wh := env.CreateWebhook(t, rand, user.GetID(), ch.ID, "")
+		e := env.R(t)
+		e.POST(path, wh.GetID()).
+			WithText("plain text").
+			Expect().
+			Status(http.StatusNoContent)
+		assert.Equal(t, "plain text", env.latestMessage(t, ch.ID).Text)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		t.Parallel()
+		ch := env.CreateChannel(t, rand)
+		wh := env.CreateWebhook(t, rand, user.GetID(), ch.ID, "")
+		e := env.R(t)
+		e.POST(path, wh.GetID()).
+			WithJSON(echo.Map{"text": "json text", "username": "override"}).
+			Expect().
+			Status(http.StatusNoContent)
+		m := env.latestMessage(t, ch.ID)
+		assert.Equal(t, "json text", m.Text)
+		if assert.NotNil(t, m.AuthorOverride) {
+			assert.Equal(t, "override", m.AuthorOverride.DisplayName)
+		}
+
+		e.POST(path, wh.GetID()).
+			WithHeader(echo.HeaderContentType, echo.MIMEApplicationJSON).
+			WithText("{").
+			Expect().
+			Status(http.StatusBadRequest)
+		e.POST(path, wh.GetID()).
+			WithJSON(echo.Map{"text": ""}).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	t.Run("unsupported media type", func(t *testing.T) {
+		t.Parallel()
+		ch := env.CreateChannel(t, rand)
+		wh := env.CreateWebhook(t, rand, user.GetID(), ch.ID, "")
+		e := env.R(t)
+		e.POST(path, wh.GetID()).
+			WithHeader(echo.HeaderContentType, "application/xml").
+			WithBytes([]byte("<text/>")).
+			Expect().
+			Status(http.StatusUnsupportedMediaType)
+	})
+
+	t.Run("signature", func(t *testing.T) {
+		t.Parallel()
+		ch := env.CreateChannel(t, rand)
+		wh := env.CreateWebhook(t, rand, user.GetID(), ch.ID, "secret")
+		body := "signed"
+		e := env.R(t)
+
+		e.POST(path, wh.GetID()).
+			WithText(body).
+			Expect().
+			Status(http.StatusBadRequest)
+		e.POST(path, wh.GetID()).
+			WithHeader(consts.HeaderSignature, hex.EncodeToString(hmac.SHA1([]byte("other"), "secret"))).
+			WithText(body).
+			Expect().
+			Status(http.StatusBadRequest)
+		e.POST(path, wh.GetID()).
+			WithHeader(consts.HeaderSignature256, "sha256="+hex.EncodeToString(hmac.SHA256([]byte(body), "other"))).
+			WithText(body).
+			Expect().
+			Status(http.StatusBadRequest)
+
+		// X-TRAQ-Signature-256 が優先される
+		e.POST(path, wh.GetID()).
+			WithHeader(consts.HeaderSignature, "invalid").
+			WithHeader(consts.HeaderSignature256, "sha256="+hex.EncodeToString(hmac.SHA256([]byte(body), "secret"))).
+			WithText(body).
+			Expect().
+			Status(http.StatusNoContent)
+		e.POST(path, wh.GetID()).
+			WithHeader(consts.HeaderSignature, hex.EncodeToString(hmac.SHA1([]byte(body), "secret"))).
+			WithText(body).
+			Expect().
+			Status(http.StatusNoContent)
+	})
+
+	t.Run("multipart", func(t *testing.T) {
+		t.Parallel()
+		ch := env.CreateChannel(t, rand)
+		wh := env.CreateWebhook(t, rand, user.GetID(), ch.ID, "secret")
+
+		var buf bytes.Buffer
+		mw := multipart.NewWriter(&buf)
+		require.NoError(t, mw.WriteField("text", "with file"))
+		fw, err := mw.CreateFormFile("file", "test.txt")
+		require.NoError(t, err)
+		_, err = fw.Write([]byte("file content"))
+		require.NoError(t, err)
+		require.NoError(t, mw.Close())
+		body := buf.Bytes()
+		e := env.R(t)
+
+		e.POST(path, wh.GetID()).
+			WithHeader(echo.HeaderContentType, mw.FormDataContentType()).
+			WithHeader(consts.HeaderSignature256, "sha256="+hex.EncodeToString(hmac.SHA256([]byte("other"), "secret"))).
+			WithBytes(body).
+			Expect().
+			Status(http.StatusBadRequest)
+		e.POST(path, wh.GetID()).
+			WithHeader(echo.HeaderContentType, mw.FormDataContentType()).
+			WithHeader(consts.HeaderSignature256, "sha256="+hex.EncodeToString(hmac.SHA256(body, "secret"))).
+			WithBytes(body).
+			Expect().
+			Status(http.StatusNoContent)
+
+		m := env.latestMessage(t, ch.ID)
+		lines := strings.Split(m.Text, "\n")
+		if assert.Len(t, lines, 2) {
+			assert.Equal(t, "with file", lines[0])
+			if assert.True(t, strings.HasPrefix(lines[1], "http://test/files/")) {
+				f, err := env.FileManager.Get(uuid.FromStringOrNil(strings.TrimPrefix(lines[1], "http://test/files/")))
+				if assert.NoError(t, err) {
+					assert.Equal(t, "test.txt", f.GetFileName())
+					assert.EqualValues(t, len("file content"), f.GetFileSize())
+				}
+			}
+		}
+	})
+
+	t.Run("rate limit", func(t *testing.T) {
+		t.Parallel()
+		ch := env.CreateChannel(t, rand)
+		wh := env.CreateWebhook(t, rand, user.GetID(), ch.ID, "secret")
+		require.NoError(t, env.RateLimiter.SetOverride(wh.GetBotUserID(), ratelimit.PostMessage, ratelimit.Limit{Rate: 0.001, Burst: 1}))
+		body := "test"
+		e := env.R(t)
+
+		// 署名が不正なリクエストはトークンを消費しない
+		for i := 0; i < 3; i++ {
+			e.POST(path, wh.GetID()).
+				WithHeader(consts.HeaderSignature256, "sha256=invalid").
+				WithText(body).
+				Expect().
+				Status(http.StatusBadRequest)
+		}
+
+		sig := "sha256=" + hex.EncodeToString(hmac.SHA256([]byte(body), "secret"))
+		e.POST(path, wh.GetID()).
+			WithHeader(consts.HeaderSignature256, sig).
+			WithText(body).
+			Expect().
+			Status(http.StatusNoContent)
+		e.POST(path, wh.GetID()).
+			WithHeader(consts.HeaderSignature256, sig).
+			WithText(body).
+			Expect().
+			Status(http.StatusTooManyRequests).
+			Header(consts.HeaderRetryAfter).NotEmpty()
+	})
+}
+
+func TestHandlers_PostWebhookSlack(t *testing.T) {
+	t.Parallel()
+	path := "/api/v3/webhooks/{webhookID}/slack"
+	env := Setup(t, common)
+	user := env.CreateUser(t, rand)
+
+	t.Run("json", func(t *testing.T) {
+		t.Parallel()
+		ch := env.CreateChannel(t, rand)
+		wh := env.CreateWebhook(t, rand, user.GetID(), ch.ID, "secret")
+		body := `{"text":"slack text","username":"slack"}`
+		e := env.R(t)
+
+		e.POST(path, wh.GetID()).
+			WithHeader(echo.HeaderContentType, echo.MIMEApplicationJSON).
+			WithHeader(consts.HeaderSignature256, "sha256="+hex.EncodeToString(hmac.SHA256([]byte("other"), "secret"))).
+			WithText(body).
+			Expect().
+			Status(http.StatusBadRequest)
+		e.POST(path, wh.GetID()).
+			WithHeader(echo.HeaderContentType, echo.MIMEApplicationJSON).
+			WithHeader(consts.HeaderSignature256, "sha256="+hex.EncodeToString(hmac.SHA256([]byte(body), "secret"))).
+			WithText(body).
+			Expect().
+			Status(http.StatusOK).
+			Text().Equal("ok")
+
+		m := env.latestMessage(t, ch.ID)
+		assert.Equal(t, "slack text", m.Text)
+		if assert.NotNil(t, m.AuthorOverride) {
+			assert.Equal(t, "slack", m.AuthorOverride.DisplayName)
+		}
+	})
+
+	t.Run("form", func(t *testing.T) {
+		t.Parallel()
+		ch := env.CreateChannel(t, rand)
+		wh := env.CreateWebhook(t, rand, user.GetID(), ch.ID, "")
+		e := env.R(t)
+
+		e.POST(path, wh.GetID()).
+			WithHeader(echo.HeaderContentType, echo.MIMEApplicationForm).
+			WithText(url.Values{"payload": {`{"text":"form text"}`}}.Encode()).
+			Expect().
+			Status(http.StatusOK)
+		assert.Equal(t, "form text", env.latestMessage(t, ch.ID).Text)
+
+		e.POST(path, wh.GetID()).
+			WithHeader(echo.HeaderContentType, echo.MIMEApplicationForm).
+			WithText(url.Values{"payload": {`{}`}}.Encode()).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+}
@@ -32,6 +32,7 @@ func newRouter(hub2 *hub.Hub, db *gorm.DB, repo repository.Repository, ss *servi
 	fileManager := ss.FileManager
 	replaceMapper := utils.NewReplaceMapper(repo, manager)
 	replacer := message.NewReplacer(replaceMapper)
+	limiter := ss.RateLimiter
 	handlers := &v1.Handlers{
 		RBAC:           rbac,
 		Repo:           repo,
@@ -44,14 +45,22 @@ func newRouter(hub2 *hub.Hub, db *gorm.DB, repo repository.Repository, ss *servi
 		ChannelManager: manager,
 		FileManager:    fileManager,
 		Replacer:       replacer,
+		RateLimiter:    limiter,
 	}
 	streamer := ss.WS
+	wsStreamer := ss.BotWS
 	webrtcv3Manager := ss.WebRTCv3
+	engine := ss.Search
+	exporter := ss.Exporter
+	digestService := ss.Digest
 	v3Config := provideV3Config(config)
+	botService := ss.BOT
 	v3Handlers := &v3.Handlers{
 		RBAC:           rbac,
+		BOT:            botService,
 		Repo:           repo,
 		WS:             streamer,
+		BotWS:          wsStreamer,
 		Hub:            hub2,
 		Logger:         logger,
 		OC:             onlineCounter,
@@ -62,6 +71,10 @@ func newRouter(hub2 *hub.Hub, db *gorm.DB, repo repository.Repository, ss *servi
 		ChannelManager: manager,
 		FileManager:    fileManager,
 		Replacer:       replacer,
+		Search:         engine,
+		Exporter:       exporter,
+		RateLimiter:    limiter,
+		Digest:         digestService,
 		Config:         v3Config,
 	}
 	oauth2Config := provideOAuth2Config(config)
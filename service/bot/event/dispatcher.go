@@ -12,6 +12,19 @@ import (
 type Dispatcher interface {
 	// Send Botにイベントを送信します
 	Send(b *model.Bot, event model.BotEventType, body []byte) (ok bool)
+	// Test Botにテストイベントを送信し、その結果を返します
+	//
+	// BOTの状態に関わらず送信します。結果はイベントログと同じ形式で返しますが、記録はしません。
+	// HTTPモードの場合は、レスポンスボディのプレビュー(先頭4KiBまで)も返します。
+	Test(b *model.Bot, ev model.BotEventType, body []byte) (log *model.BotEventLog, resBody string)
+}
+
+// WSSender WebSocketモードのBOTへのイベント送信機
+type WSSender interface {
+	// WriteMessage 指定したBOTユーザーの接続にイベントを書き込みます
+	WriteMessage(botUserID uuid.UUID, ev model.BotEventType, reqID uuid.UUID, body []byte) error
+	// IsConnected 指定したBOTユーザーがこのノードに接続しているかどうか
+	IsConnected(botUserID uuid.UUID) bool
 }
 
 // Unicast 単一のBOTにイベントを送信
@@ -50,6 +63,17 @@ func Multicast(d Dispatcher, ev model.BotEventType, payload interface{}, targets
 	return nil
 }
 
+// Test BOTにテストイベントを送信し、その結果を返します
+func Test(d Dispatcher, ev model.BotEventType, payload interface{}, target *model.Bot) (log *model.BotEventLog, resBody string, err error) {
+	buf, release, err := makePayloadJSON(&payload)
+	if err != nil {
+		return nil, "", err
+	}
+	defer release()
+	log, resBody = d.Test(target, ev, buf)
+	return log, resBody, nil
+}
+
 func makePayloadJSON(payload interface{}) (b []byte, releaseFunc func(), err error) {
 	cfg := jsoniter.ConfigFastest
 	stream := cfg.BorrowStream(nil)
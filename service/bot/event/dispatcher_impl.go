@@ -8,17 +8,27 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/traPtitech/traQ/model"
 	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/utils/hmac"
 	"go.uber.org/zap"
+	"io"
+	"io/ioutil"
 	"net/http"
 	"time"
 )
 
 const (
-	headerTRAQBotEvent             = "X-TRAQ-BOT-EVENT"
-	headerTRAQBotRequestID         = "X-TRAQ-BOT-REQUEST-ID"
+	headerTRAQBotEvent     = "X-TRAQ-BOT-EVENT"
+	headerTRAQBotRequestID = "X-TRAQ-BOT-REQUEST-ID"
+	// headerTRAQBotVerificationToken 署名導入前の検証方法のためのヘッダー (非推奨)
+	//
+	// LegacyVerificationTokenが有効なBOTにのみ、署名と併せて送信します。
 	headerTRAQBotVerificationToken = "X-TRAQ-BOT-TOKEN"
+	headerTRAQBotSignature         = "X-TRAQ-BOT-SIGNATURE"
 	headerUserAgent                = "User-Agent"
 	ua                             = "traQ_Bot_Processor/1.0"
+
+	// maxTestResponseBodySize テストイベントで返すレスポンスボディのプレビューの最大サイズ
+	maxTestResponseBodySize = 4 << 10 // 4KiB
 )
 
 var eventSendCounter = promauto.NewCounterVec(prometheus.CounterOpts{
@@ -30,9 +40,10 @@ type dispatcherImpl struct {
 	client http.Client
 	l      *zap.Logger
 	repo   repository.BotRepository
+	ws     WSSender
 }
 
-func NewDispatcher(logger *zap.Logger, repo repository.BotRepository) Dispatcher {
+func NewDispatcher(logger *zap.Logger, repo repository.BotRepository, ws WSSender) Dispatcher {
 	return &dispatcherImpl{
 		client: http.Client{
 			Jar:     nil,
@@ -43,21 +54,18 @@ func NewDispatcher(logger *zap.Logger, repo repository.BotRepository) Dispatcher
 		},
 		l:    logger.Named("bot.dispatcher"),
 		repo: repo,
+		ws:   ws,
 	}
 }
 
 func (d *dispatcherImpl) Send(b *model.Bot, event model.BotEventType, body []byte) (ok bool) {
 	reqID := uuid.Must(uuid.NewV4())
-
-	req, _ := http.NewRequest(http.MethodPost, b.PostURL, bytes.NewReader(body))
-	req.Header.Set(headerUserAgent, ua)
-	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
-	req.Header.Set(headerTRAQBotEvent, event.String())
-	req.Header.Set(headerTRAQBotRequestID, reqID.String())
-	req.Header.Set(headerTRAQBotVerificationToken, b.VerificationToken)
+	if b.Mode == model.BotModeWebSocket {
+		return d.sendWS(b, event, reqID, body)
+	}
 
 	start := time.Now()
-	res, err := d.client.Do(req)
+	res, err := d.client.Do(newRequest(b, event, reqID, body))
 	stop := time.Now()
 
 	if err != nil {
@@ -66,6 +74,7 @@ func (d *dispatcherImpl) Send(b *model.Bot, event model.BotEventType, body []byt
 			RequestID: reqID,
 			BotID:     b.ID,
 			Event:     event,
+			Mode:      model.BotModeHTTP,
 			Body:      string(body),
 			Error:     err.Error(),
 			Code:      -1,
@@ -86,6 +95,7 @@ func (d *dispatcherImpl) Send(b *model.Bot, event model.BotEventType, body []byt
 		RequestID: reqID,
 		BotID:     b.ID,
 		Event:     event,
+		Mode:      model.BotModeHTTP,
 		Body:      string(body),
 		Code:      res.StatusCode,
 		Latency:   stop.Sub(start).Nanoseconds(),
@@ -94,6 +104,96 @@ func (d *dispatcherImpl) Send(b *model.Bot, event model.BotEventType, body []byt
 	return res.StatusCode == http.StatusNoContent
 }
 
+func (d *dispatcherImpl) Test(b *model.Bot, event model.BotEventType, body []byte) (*model.BotEventLog, string) {
+	log := &model.BotEventLog{
+		RequestID: uuid.Must(uuid.NewV4()),
+		BotID:     b.ID,
+		Event:     event,
+		Mode:      b.Mode,
+		Body:      string(body),
+		DateTime:  time.Now(),
+	}
+
+	if b.Mode == model.BotModeWebSocket {
+		start := time.Now()
+		err := d.ws.WriteMessage(b.BotUserID, event, log.RequestID, body)
+		log.Latency = time.Since(start).Nanoseconds()
+		if err != nil {
+			log.Error = err.Error()
+			log.Code = -1
+		}
+		return log, ""
+	}
+
+	start := time.Now()
+	res, err := d.client.Do(newRequest(b, event, log.RequestID, body))
+	if err != nil {
+		log.Latency = time.Since(start).Nanoseconds()
+		log.Error = err.Error()
+		log.Code = -1
+		return log, ""
+	}
+	defer res.Body.Close()
+
+	resBody, err := ioutil.ReadAll(io.LimitReader(res.Body, maxTestResponseBodySize))
+	log.Latency = time.Since(start).Nanoseconds()
+	log.Code = res.StatusCode
+	if err != nil {
+		log.Error = err.Error()
+	}
+	return log, string(resBody)
+}
+
+// newRequest BOTへのHTTPリクエストを生成します
+func newRequest(b *model.Bot, event model.BotEventType, reqID uuid.UUID, body []byte) *http.Request {
+	req, _ := http.NewRequest(http.MethodPost, b.PostURL, bytes.NewReader(body))
+	req.Header.Set(headerUserAgent, ua)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+	req.Header.Set(headerTRAQBotEvent, event.String())
+	req.Header.Set(headerTRAQBotRequestID, reqID.String())
+	if b.LegacyVerificationToken {
+		req.Header.Set(headerTRAQBotVerificationToken, b.VerificationToken)
+	}
+	req.Header.Set(headerTRAQBotSignature, sign(b, time.Now(), body))
+	return req
+}
+
+// sign リクエストボディの署名ヘッダーの値を生成します
+//
+// シークレットのローテーション後の猶予期間中は、以前のシークレットによる署名も v1 として併記します。
+func sign(b *model.Bot, t time.Time, body []byte) string {
+	return hmac.SignTimestamp(t, body, b.SigningSecrets(t)...)
+}
+
+// sendWS WebSocketモードのBOTにイベントを送信します
+//
+// 接続への書き込みが完了した場合のみ送信成功とし、Code 0でログを記録します。
+// 送信バッファに積んだ後に接続が切断された場合は失敗となり、キューから再送されます。
+func (d *dispatcherImpl) sendWS(b *model.Bot, event model.BotEventType, reqID uuid.UUID, body []byte) bool {
+	start := time.Now()
+	err := d.ws.WriteMessage(b.BotUserID, event, reqID, body)
+	stop := time.Now()
+
+	log := &model.BotEventLog{
+		RequestID: reqID,
+		BotID:     b.ID,
+		Event:     event,
+		Mode:      model.BotModeWebSocket,
+		Body:      string(body),
+		Latency:   stop.Sub(start).Nanoseconds(),
+		DateTime:  time.Now(),
+	}
+	if err != nil {
+		eventSendCounter.WithLabelValues(b.ID.String(), "ne").Inc()
+		log.Error = err.Error()
+		log.Code = -1
+	} else {
+		eventSendCounter.WithLabelValues(b.ID.String(), "ok").Inc()
+	}
+	d.writeLog(log)
+	return err == nil
+}
+
 func (d *dispatcherImpl) writeLog(log *model.BotEventLog) {
 	if err := d.repo.WriteBotEventLog(log); err != nil {
 		d.l.Warn("failed to write log", zap.Error(err), zap.Any("eventLog", log))
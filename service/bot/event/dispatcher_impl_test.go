@@ -0,0 +1,257 @@
+package event
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/repository/mock_repository"
+	"github.com/traPtitech/traQ/utils/hmac"
+	"go.uber.org/zap"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeWSSender struct {
+	err  error
+	sent []uuid.UUID
+}
+
+func (s *fakeWSSender) WriteMessage(botUserID uuid.UUID, _ model.BotEventType, _ uuid.UUID, _ []byte) error {
+	s.sent = append(s.sent, botUserID)
+	return s.err
+}
+
+func (s *fakeWSSender) IsConnected(uuid.UUID) bool {
+	return s.err == nil
+}
+
+func TestDispatcherImpl_Send(t *testing.T) {
+	t.Parallel()
+
+	t.Run("http", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockBotRepository(ctrl)
+		d := NewDispatcher(zap.NewNop(), repo, &fakeWSSender{})
+
+		var (
+			header http.Header
+			body   []byte
+		)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header = r.Header
+			body, _ = ioutil.ReadAll(r.Body)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer srv.Close()
+
+		b := &model.Bot{ID: uuid.NewV3(uuid.Nil, "b"), Mode: model.BotModeHTTP, PostURL: srv.URL, VerificationToken: "token", SigningSecret: "secret"}
+		repo.EXPECT().
+			WriteBotEventLog(gomock.Any()).
+			DoAndReturn(func(log *model.BotEventLog) error {
+				assert.Equal(t, model.BotModeHTTP, log.Mode)
+				assert.Equal(t, http.StatusNoContent, log.Code)
+				return nil
+			}).
+			Times(1)
+
+		if assert.True(t, d.Send(b, Ping, []byte(`{"a":"b"}`))) {
+			assert.Equal(t, `{"a":"b"}`, string(body))
+			assert.Equal(t, Ping.String(), header.Get(headerTRAQBotEvent))
+			assert.Empty(t, header.Get(headerTRAQBotVerificationToken))
+			assert.Regexp(t, `^t=\d+,v1=[0-9a-f]{64}$`, header.Get(headerTRAQBotSignature))
+		}
+	})
+
+	t.Run("http (legacy verification token)", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockBotRepository(ctrl)
+		d := NewDispatcher(zap.NewNop(), repo, &fakeWSSender{})
+
+		var (
+			header http.Header
+			body   []byte
+		)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header = r.Header
+			body, _ = ioutil.ReadAll(r.Body)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer srv.Close()
+
+		b := &model.Bot{ID: uuid.NewV3(uuid.Nil, "b"), Mode: model.BotModeHTTP, PostURL: srv.URL, VerificationToken: "token", SigningSecret: "secret", LegacyVerificationToken: true}
+		repo.EXPECT().
+			WriteBotEventLog(gomock.Any()).
+			DoAndReturn(func(log *model.BotEventLog) error {
+				assert.Equal(t, model.BotModeHTTP, log.Mode)
+				assert.Equal(t, http.StatusNoContent, log.Code)
+				return nil
+			}).
+			Times(1)
+
+		if assert.True(t, d.Send(b, Ping, []byte(`{"a":"b"}`))) {
+			assert.Equal(t, `{"a":"b"}`, string(body))
+			assert.Equal(t, Ping.String(), header.Get(headerTRAQBotEvent))
+			assert.Equal(t, "token", header.Get(headerTRAQBotVerificationToken))
+			assert.Regexp(t, `^t=\d+,v1=[0-9a-f]{64}$`, header.Get(headerTRAQBotSignature))
+		}
+	})
+
+	t.Run("websocket", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockBotRepository(ctrl)
+		ws := &fakeWSSender{}
+		d := NewDispatcher(zap.NewNop(), repo, ws)
+
+		b := &model.Bot{ID: uuid.NewV3(uuid.Nil, "b"), BotUserID: uuid.NewV3(uuid.Nil, "bu"), Mode: model.BotModeWebSocket}
+		repo.EXPECT().
+			WriteBotEventLog(gomock.Any()).
+			DoAndReturn(func(log *model.BotEventLog) error {
+				assert.Equal(t, b.ID, log.BotID)
+				assert.Equal(t, model.BotModeWebSocket, log.Mode)
+				assert.Equal(t, 0, log.Code)
+				assert.Empty(t, log.Error)
+				return nil
+			}).
+			Times(1)
+
+		assert.True(t, d.Send(b, Ping, []byte(`{}`)))
+		assert.Equal(t, []uuid.UUID{b.BotUserID}, ws.sent)
+	})
+
+	t.Run("websocket (not connected)", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockBotRepository(ctrl)
+		ws := &fakeWSSender{err: errors.New("bot is not connected")}
+		d := NewDispatcher(zap.NewNop(), repo, ws)
+
+		b := &model.Bot{ID: uuid.NewV3(uuid.Nil, "b"), BotUserID: uuid.NewV3(uuid.Nil, "bu"), Mode: model.BotModeWebSocket}
+		repo.EXPECT().
+			WriteBotEventLog(gomock.Any()).
+			DoAndReturn(func(log *model.BotEventLog) error {
+				assert.Equal(t, model.BotModeWebSocket, log.Mode)
+				assert.Equal(t, -1, log.Code)
+				assert.Equal(t, "bot is not connected", log.Error)
+				return nil
+			}).
+			Times(1)
+
+		assert.False(t, d.Send(b, Ping, []byte(`{}`)))
+	})
+}
+
+func TestDispatcherImpl_Test(t *testing.T) {
+	t.Parallel()
+
+	t.Run("http", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockBotRepository(ctrl) // ログは記録されない
+		d := NewDispatcher(zap.NewNop(), repo, &fakeWSSender{})
+
+		var header http.Header
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header = r.Header
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte("invalid payload"))
+		}))
+		defer srv.Close()
+
+		b := &model.Bot{ID: uuid.NewV3(uuid.Nil, "b"), Mode: model.BotModeHTTP, PostURL: srv.URL, SigningSecret: "secret"}
+		log, resBody := d.Test(b, Ping, []byte(`{"a":"b"}`))
+		assert.Equal(t, http.StatusBadRequest, log.Code)
+		assert.Equal(t, "invalid payload", resBody)
+		assert.Equal(t, `{"a":"b"}`, log.Body)
+		assert.Equal(t, log.RequestID.String(), header.Get(headerTRAQBotRequestID))
+		assert.Regexp(t, `^t=\d+,v1=[0-9a-f]{64}$`, header.Get(headerTRAQBotSignature))
+	})
+
+	t.Run("http (large response)", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockBotRepository(ctrl)
+		d := NewDispatcher(zap.NewNop(), repo, &fakeWSSender{})
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write(bytes.Repeat([]byte("a"), maxTestResponseBodySize*2))
+		}))
+		defer srv.Close()
+
+		b := &model.Bot{ID: uuid.NewV3(uuid.Nil, "b"), Mode: model.BotModeHTTP, PostURL: srv.URL, SigningSecret: "secret"}
+		log, resBody := d.Test(b, Ping, []byte(`{}`))
+		assert.Equal(t, http.StatusOK, log.Code)
+		assert.Equal(t, strings.Repeat("a", maxTestResponseBodySize), resBody)
+	})
+
+	t.Run("http (network error)", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockBotRepository(ctrl)
+		d := NewDispatcher(zap.NewNop(), repo, &fakeWSSender{})
+
+		b := &model.Bot{ID: uuid.NewV3(uuid.Nil, "b"), Mode: model.BotModeHTTP, PostURL: "http://127.0.0.1:0"}
+		log, resBody := d.Test(b, Ping, []byte(`{}`))
+		assert.Equal(t, -1, log.Code)
+		assert.NotEmpty(t, log.Error)
+		assert.Empty(t, resBody)
+	})
+
+	t.Run("websocket", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockBotRepository(ctrl)
+		ws := &fakeWSSender{}
+		d := NewDispatcher(zap.NewNop(), repo, ws)
+
+		b := &model.Bot{ID: uuid.NewV3(uuid.Nil, "b"), BotUserID: uuid.NewV3(uuid.Nil, "bu"), Mode: model.BotModeWebSocket}
+		log, resBody := d.Test(b, Ping, []byte(`{}`))
+		assert.Equal(t, 0, log.Code)
+		assert.Equal(t, model.BotModeWebSocket, log.Mode)
+		assert.Empty(t, resBody)
+		assert.Equal(t, []uuid.UUID{b.BotUserID}, ws.sent)
+	})
+}
+
+func TestSign(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	ts := strconv.FormatInt(now.Unix(), 10)
+	body := []byte(`{"a":"b"}`)
+	mac := func(secret string) string {
+		return hex.EncodeToString(hmac.SHA256([]byte(ts+"."+string(body)), secret))
+	}
+
+	t.Run("current secret only", func(t *testing.T) {
+		t.Parallel()
+		b := &model.Bot{SigningSecret: "new"}
+		assert.Equal(t, "t="+ts+",v1="+mac("new"), sign(b, now, body))
+	})
+
+	t.Run("in grace period", func(t *testing.T) {
+		t.Parallel()
+		expiresAt := now.Add(time.Hour)
+		b := &model.Bot{SigningSecret: "new", PreviousSigningSecret: "old", PreviousSigningSecretExpiresAt: &expiresAt}
+		assert.Equal(t, "t="+ts+",v1="+mac("new")+",v1="+mac("old"), sign(b, now, body))
+	})
+
+	t.Run("after grace period", func(t *testing.T) {
+		t.Parallel()
+		expiresAt := now.Add(-time.Hour)
+		b := &model.Bot{SigningSecret: "new", PreviousSigningSecret: "old", PreviousSigningSecretExpiresAt: &expiresAt}
+		assert.Equal(t, "t="+ts+",v1="+mac("new"), sign(b, now, body))
+	})
+}
@@ -11,8 +11,16 @@ const (
 	Left model.BotEventType = "LEFT"
 	// MessageCreated メッセージ作成イベント
 	MessageCreated model.BotEventType = "MESSAGE_CREATED"
+	// MessageUpdated メッセージ編集イベント
+	MessageUpdated model.BotEventType = "MESSAGE_UPDATED"
 	// MessageDeleted メッセージ削除イベント
 	MessageDeleted model.BotEventType = "MESSAGE_DELETED"
+	// MessageStamped メッセージスタンプ追加イベント
+	MessageStamped model.BotEventType = "MESSAGE_STAMPED"
+	// MessageUnstamped メッセージスタンプ削除イベント
+	MessageUnstamped model.BotEventType = "MESSAGE_UNSTAMPED"
+	// MessagePinned メッセージピン留めイベント
+	MessagePinned model.BotEventType = "MESSAGE_PINNED"
 	// MentionMessageCreated メンションメッセージ作成イベント
 	MentionMessageCreated model.BotEventType = "MENTION_MESSAGE_CREATED"
 	// DirectMessageCreated ダイレクトメッセージ作成イベント
@@ -21,14 +29,34 @@ const (
 	ChannelCreated model.BotEventType = "CHANNEL_CREATED"
 	// ChannelTopicChanged チャンネルトピック変更イベント
 	ChannelTopicChanged model.BotEventType = "CHANNEL_TOPIC_CHANGED"
+	// ChannelUpdated チャンネル変更イベント
+	ChannelUpdated model.BotEventType = "CHANNEL_UPDATED"
+	// ChannelDeleted チャンネル削除イベント
+	ChannelDeleted model.BotEventType = "CHANNEL_DELETED"
 	// UserCreated ユーザー作成イベント
 	UserCreated model.BotEventType = "USER_CREATED"
+	// UserUpdated ユーザー情報変更イベント
+	UserUpdated model.BotEventType = "USER_UPDATED"
+	// UserGroupMemberAdded ユーザーグループメンバー追加イベント
+	UserGroupMemberAdded model.BotEventType = "USER_GROUP_MEMBER_ADDED"
+	// UserGroupMemberRemoved ユーザーグループメンバー削除イベント
+	UserGroupMemberRemoved model.BotEventType = "USER_GROUP_MEMBER_REMOVED"
 	// StampCreated スタンプ作成イベント
 	StampCreated model.BotEventType = "STAMP_CREATED"
+	// StampUpdated スタンプ変更イベント
+	StampUpdated model.BotEventType = "STAMP_UPDATED"
+	// StampDeleted スタンプ削除イベント
+	StampDeleted model.BotEventType = "STAMP_DELETED"
 	// TagAdded タグ追加イベント
 	TagAdded model.BotEventType = "TAG_ADDED"
 	// TagRemoved タグ削除イベント
 	TagRemoved model.BotEventType = "TAG_REMOVED"
+	// BotStateChanged BOT状態変更イベント
+	BotStateChanged model.BotEventType = "BOT_STATE_CHANGED"
+	// CommandInvoked スラッシュコマンド実行イベント
+	CommandInvoked model.BotEventType = "COMMAND_INVOKED"
+	// Interaction メッセージコンポーネント操作イベント
+	Interaction model.BotEventType = "INTERACTION"
 )
 
 var Types model.BotEventTypes
@@ -40,15 +68,29 @@ func init() {
 		Joined,
 		Left,
 		MessageCreated,
+		MessageUpdated,
 		MessageDeleted,
+		MessageStamped,
+		MessageUnstamped,
+		MessagePinned,
 		MentionMessageCreated,
 		DirectMessageCreated,
 		ChannelCreated,
 		ChannelTopicChanged,
+		ChannelUpdated,
+		ChannelDeleted,
 		UserCreated,
+		UserUpdated,
+		UserGroupMemberAdded,
+		UserGroupMemberRemoved,
 		StampCreated,
+		StampUpdated,
+		StampDeleted,
 		TagAdded,
 		TagRemoved,
+		BotStateChanged,
+		CommandInvoked,
+		Interaction,
 	} {
 		Types[t] = struct{}{}
 	}
@@ -5,6 +5,7 @@
 package mock_event
 
 import (
+	uuid "github.com/gofrs/uuid"
 	gomock "github.com/golang/mock/gomock"
 	model "github.com/traPtitech/traQ/model"
 	reflect "reflect"
@@ -46,3 +47,55 @@ func (mr *MockDispatcherMockRecorder) Send(b, event, body interface{}) *gomock.C
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockDispatcher)(nil).Send), b, event, body)
 }
+
+// Test mocks base method
+func (m *MockDispatcher) Test(b *model.Bot, ev model.BotEventType, body []byte) (*model.BotEventLog, string) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Test", b, ev, body)
+	ret0, _ := ret[0].(*model.BotEventLog)
+	ret1, _ := ret[1].(string)
+	return ret0, ret1
+}
+
+// Test indicates an expected call of Test
+func (mr *MockDispatcherMockRecorder) Test(b, ev, body interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Test", reflect.TypeOf((*MockDispatcher)(nil).Test), b, ev, body)
+}
+
+// MockWSSender is a mock of WSSender interface
+type MockWSSender struct {
+	ctrl     *gomock.Controller
+	recorder *MockWSSenderMockRecorder
+}
+
+// MockWSSenderMockRecorder is the mock recorder for MockWSSender
+type MockWSSenderMockRecorder struct {
+	mock *MockWSSender
+}
+
+// NewMockWSSender creates a new mock instance
+func NewMockWSSender(ctrl *gomock.Controller) *MockWSSender {
+	mock := &MockWSSender{ctrl: ctrl}
+	mock.recorder = &MockWSSenderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockWSSender) EXPECT() *MockWSSenderMockRecorder {
+	return m.recorder
+}
+
+// WriteMessage mocks base method
+func (m *MockWSSender) WriteMessage(botUserID uuid.UUID, ev model.BotEventType, reqID uuid.UUID, body []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteMessage", botUserID, ev, reqID, body)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WriteMessage indicates an expected call of WriteMessage
+func (mr *MockWSSenderMockRecorder) WriteMessage(botUserID, ev, reqID, body interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteMessage", reflect.TypeOf((*MockWSSender)(nil).WriteMessage), botUserID, ev, reqID, body)
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/gofrs/uuid"
 	"github.com/traPtitech/traQ/model"
 	"github.com/traPtitech/traQ/utils/message"
+	"github.com/traPtitech/traQ/utils/optional"
 	"time"
 )
 
@@ -22,6 +23,7 @@ type Message struct {
 	ID        uuid.UUID               `json:"id"`
 	User      User                    `json:"user"`
 	ChannelID uuid.UUID               `json:"channelId"`
+	ParentID  optional.UUID           `json:"parentId"`
 	Text      string                  `json:"text"`
 	PlainText string                  `json:"plainText"`
 	Embedded  []*message.EmbeddedInfo `json:"embedded"`
@@ -34,6 +36,7 @@ func MakeMessage(message *model.Message, user model.UserInfo, embedded []*messag
 		ID:        message.ID,
 		User:      MakeUser(user),
 		ChannelID: message.ChannelID,
+		ParentID:  message.ParentID,
 		Text:      message.Text,
 		PlainText: plain,
 		Embedded:  embedded,
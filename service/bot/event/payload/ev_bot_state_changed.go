@@ -0,0 +1,19 @@
+package payload
+
+import (
+	"github.com/traPtitech/traQ/model"
+	"time"
+)
+
+// BotStateChanged BOT_STATE_CHANGEDイベントペイロード
+type BotStateChanged struct {
+	Base
+	State model.BotState `json:"state"`
+}
+
+func MakeBotStateChanged(et time.Time, state model.BotState) *BotStateChanged {
+	return &BotStateChanged{
+		Base:  MakeBase(et),
+		State: state,
+	}
+}
@@ -0,0 +1,19 @@
+package payload
+
+import (
+	"github.com/gofrs/uuid"
+	"time"
+)
+
+// ChannelDeleted CHANNEL_DELETEDイベントペイロード
+type ChannelDeleted struct {
+	Base
+	ChannelID uuid.UUID `json:"channelId"`
+}
+
+func MakeChannelDeleted(et time.Time, channelID uuid.UUID) *ChannelDeleted {
+	return &ChannelDeleted{
+		Base:      MakeBase(et),
+		ChannelID: channelID,
+	}
+}
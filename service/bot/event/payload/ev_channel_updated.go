@@ -0,0 +1,19 @@
+package payload
+
+import (
+	"github.com/traPtitech/traQ/model"
+	"time"
+)
+
+// ChannelUpdated CHANNEL_UPDATEDイベントペイロード
+type ChannelUpdated struct {
+	Base
+	Channel Channel `json:"channel"`
+}
+
+func MakeChannelUpdated(et time.Time, ch *model.Channel, chPath string, chCreator model.UserInfo) *ChannelUpdated {
+	return &ChannelUpdated{
+		Base:    MakeBase(et),
+		Channel: MakeChannel(ch, chPath, chCreator),
+	}
+}
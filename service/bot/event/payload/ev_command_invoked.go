@@ -0,0 +1,36 @@
+package payload
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/traPtitech/traQ/model"
+	"time"
+)
+
+// CommandInvoked COMMAND_INVOKEDイベントペイロード
+type CommandInvoked struct {
+	Base
+	Command   Command           `json:"command"`
+	Args      map[string]string `json:"args"`
+	Text      string            `json:"text"`
+	User      User              `json:"user"`
+	ChannelID uuid.UUID         `json:"channelId"`
+}
+
+type Command struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+}
+
+func MakeCommandInvoked(et time.Time, cmd *model.BotCommand, args map[string]string, text string, user model.UserInfo, channelID uuid.UUID) *CommandInvoked {
+	return &CommandInvoked{
+		Base: MakeBase(et),
+		Command: Command{
+			ID:   cmd.ID,
+			Name: cmd.Name,
+		},
+		Args:      args,
+		Text:      text,
+		User:      MakeUser(user),
+		ChannelID: channelID,
+	}
+}
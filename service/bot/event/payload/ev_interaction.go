@@ -0,0 +1,36 @@
+package payload
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/traPtitech/traQ/model"
+	"time"
+)
+
+// Interaction INTERACTIONイベントペイロード
+type Interaction struct {
+	Base
+	MessageID uuid.UUID            `json:"messageId"`
+	ChannelID uuid.UUID            `json:"channelId"`
+	Component InteractionComponent `json:"component"`
+	Value     string               `json:"value"`
+	User      User                 `json:"user"`
+}
+
+type InteractionComponent struct {
+	ID   string                     `json:"id"`
+	Type model.MessageComponentType `json:"type"`
+}
+
+func MakeInteraction(et time.Time, m *model.Message, c *model.MessageComponent, value string, user model.UserInfo) *Interaction {
+	return &Interaction{
+		Base:      MakeBase(et),
+		MessageID: m.ID,
+		ChannelID: m.ChannelID,
+		Component: InteractionComponent{
+			ID:   c.ID,
+			Type: c.Type,
+		},
+		Value: value,
+		User:  MakeUser(user),
+	}
+}
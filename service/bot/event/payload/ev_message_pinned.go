@@ -0,0 +1,21 @@
+package payload
+
+import (
+	"github.com/gofrs/uuid"
+	"time"
+)
+
+// MessagePinned MESSAGE_PINNEDイベントペイロード
+type MessagePinned struct {
+	Base
+	MessageID uuid.UUID `json:"messageId"`
+	ChannelID uuid.UUID `json:"channelId"`
+}
+
+func MakeMessagePinned(et time.Time, messageID, channelID uuid.UUID) *MessagePinned {
+	return &MessagePinned{
+		Base:      MakeBase(et),
+		MessageID: messageID,
+		ChannelID: channelID,
+	}
+}
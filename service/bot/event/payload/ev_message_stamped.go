@@ -0,0 +1,30 @@
+package payload
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/traPtitech/traQ/model"
+	"time"
+)
+
+// MessageStamped MESSAGE_STAMPEDイベントペイロード
+type MessageStamped struct {
+	Base
+	MessageID uuid.UUID `json:"messageId"`
+	ChannelID uuid.UUID `json:"channelId"`
+	StampID   uuid.UUID `json:"stampId"`
+	StampName string    `json:"stampName"`
+	User      User      `json:"user"`
+	Count     int       `json:"count"`
+}
+
+func MakeMessageStamped(et time.Time, m *model.Message, stamp *model.Stamp, user model.UserInfo, count int) *MessageStamped {
+	return &MessageStamped{
+		Base:      MakeBase(et),
+		MessageID: m.ID,
+		ChannelID: m.ChannelID,
+		StampID:   stamp.ID,
+		StampName: stamp.Name,
+		User:      MakeUser(user),
+		Count:     count,
+	}
+}
@@ -0,0 +1,28 @@
+package payload
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/traPtitech/traQ/model"
+	"time"
+)
+
+// MessageUnstamped MESSAGE_UNSTAMPEDイベントペイロード
+type MessageUnstamped struct {
+	Base
+	MessageID uuid.UUID `json:"messageId"`
+	ChannelID uuid.UUID `json:"channelId"`
+	StampID   uuid.UUID `json:"stampId"`
+	StampName string    `json:"stampName"`
+	User      User      `json:"user"`
+}
+
+func MakeMessageUnstamped(et time.Time, m *model.Message, stamp *model.Stamp, user model.UserInfo) *MessageUnstamped {
+	return &MessageUnstamped{
+		Base:      MakeBase(et),
+		MessageID: m.ID,
+		ChannelID: m.ChannelID,
+		StampID:   stamp.ID,
+		StampName: stamp.Name,
+		User:      MakeUser(user),
+	}
+}
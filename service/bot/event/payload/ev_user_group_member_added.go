@@ -0,0 +1,22 @@
+package payload
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/traPtitech/traQ/model"
+	"time"
+)
+
+// UserGroupMemberAdded USER_GROUP_MEMBER_ADDEDイベントペイロード
+type UserGroupMemberAdded struct {
+	Base
+	GroupID uuid.UUID `json:"groupId"`
+	User    User      `json:"user"`
+}
+
+func MakeUserGroupMemberAdded(et time.Time, groupID uuid.UUID, user model.UserInfo) *UserGroupMemberAdded {
+	return &UserGroupMemberAdded{
+		Base:    MakeBase(et),
+		GroupID: groupID,
+		User:    MakeUser(user),
+	}
+}
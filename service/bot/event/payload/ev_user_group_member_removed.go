@@ -0,0 +1,22 @@
+package payload
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/traPtitech/traQ/model"
+	"time"
+)
+
+// UserGroupMemberRemoved USER_GROUP_MEMBER_REMOVEDイベントペイロード
+type UserGroupMemberRemoved struct {
+	Base
+	GroupID uuid.UUID `json:"groupId"`
+	User    User      `json:"user"`
+}
+
+func MakeUserGroupMemberRemoved(et time.Time, groupID uuid.UUID, user model.UserInfo) *UserGroupMemberRemoved {
+	return &UserGroupMemberRemoved{
+		Base:    MakeBase(et),
+		GroupID: groupID,
+		User:    MakeUser(user),
+	}
+}
@@ -0,0 +1,19 @@
+package payload
+
+import (
+	"github.com/traPtitech/traQ/model"
+	"time"
+)
+
+// UserUpdated USER_UPDATEDイベントペイロード
+type UserUpdated struct {
+	Base
+	User User `json:"user"`
+}
+
+func MakeUserUpdated(et time.Time, user model.UserInfo) *UserUpdated {
+	return &UserUpdated{
+		Base: MakeBase(et),
+		User: MakeUser(user),
+	}
+}
@@ -0,0 +1,258 @@
+package event
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/service/cluster"
+	"go.uber.org/zap"
+	"sync"
+	"time"
+)
+
+const (
+	// MaxAttempts 1つのイベントの最大送信試行回数
+	//
+	// 連続でこの回数送信に失敗した場合、イベントはDead Letterとなり、BOTは一時停止されます。
+	MaxAttempts = 10
+
+	queuePollInterval = 5 * time.Second
+	queueBatchSize    = 100
+	retryBaseInterval = 1 * time.Second
+	retryMaxInterval  = 5 * time.Minute
+	// queueLeaseDuration 送信中のイベントのリース期間
+	//
+	// 送信のタイムアウトより十分長くしてください。送信中にノードが停止した場合、期限切れ後に再送されます。
+	queueLeaseDuration = 1 * time.Minute
+)
+
+// Queue 永続化されたBotイベント送信キュー
+//
+// イベントはDBに保存され、BOT毎に作成順で1つずつ送信されます。
+// 送信に失敗したイベントは指数バックオフで再送され、後続のイベントはその間待機します。
+// 送信するイベントはDB上でリースされ、送信に成功した後に削除されるため、複数のノードから同じイベントが送信されることはありません。
+// HTTPモードのBOTへはクラスタのリーダーノードが、WebSocketモードのBOTへは接続を保持しているノードが送信します。
+type Queue struct {
+	repo repository.BotRepository
+	d    Dispatcher
+	ws   WSSender
+	bus  *cluster.Bus
+	l    *zap.Logger
+
+	notify chan struct{}
+	stop   chan struct{}
+	wg     sync.WaitGroup
+
+	// inflight このノードで送信処理中のBOT ノード間の排他はDB上のリースで行います
+	inflight   map[uuid.UUID]struct{}
+	inflightMu sync.Mutex
+	started    bool
+}
+
+// NewQueue Queueを生成します
+func NewQueue(logger *zap.Logger, repo repository.BotRepository, d Dispatcher, ws WSSender, bus *cluster.Bus) *Queue {
+	return &Queue{
+		repo:     repo,
+		d:        d,
+		ws:       ws,
+		bus:      bus,
+		l:        logger.Named("bot.queue"),
+		notify:   make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+		inflight: map[uuid.UUID]struct{}{},
+	}
+}
+
+// Enqueue 複数のBOTへのイベントを送信キューに追加します
+func (q *Queue) Enqueue(ev model.BotEventType, payload interface{}, targets []*model.Bot) error {
+	if len(targets) == 0 {
+		return nil
+	}
+	buf, release, err := makePayloadJSON(&payload)
+	if err != nil {
+		return err
+	}
+	body := string(buf)
+	release()
+
+	now := time.Now()
+	items := make([]*model.BotEventQueueItem, 0, len(targets))
+	done := make(map[uuid.UUID]bool, len(targets))
+	for _, bot := range targets {
+		if !done[bot.ID] {
+			done[bot.ID] = true
+			items = append(items, &model.BotEventQueueItem{
+				ID:            uuid.Must(uuid.NewV4()),
+				BotID:         bot.ID,
+				Event:         ev,
+				Body:          body,
+				NextAttemptAt: now,
+			})
+		}
+	}
+	if err := q.repo.EnqueueBotEvents(items); err != nil {
+		return err
+	}
+	q.wakeup()
+	return nil
+}
+
+// Start キューの処理を開始します
+func (q *Queue) Start() {
+	if q.started {
+		return
+	}
+	q.started = true
+
+	q.wg.Add(1)
+	go func() {
+		defer q.wg.Done()
+		ticker := time.NewTicker(queuePollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-q.stop:
+				return
+			case <-ticker.C:
+			case <-q.notify:
+			}
+			q.dispatchAll()
+		}
+	}()
+}
+
+// Stop キューの処理を停止します
+//
+// 送信中のイベントの処理が終わるまで待機します。キューに残ったイベントは次回起動時に送信されます。
+func (q *Queue) Stop() {
+	if !q.started {
+		return
+	}
+	close(q.stop)
+	q.wg.Wait()
+}
+
+func (q *Queue) wakeup() {
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (q *Queue) dispatchAll() {
+	ids, err := q.repo.GetBotIDsWithQueuedEvents()
+	if err != nil {
+		q.l.Error("failed to get queued bots", zap.Error(err))
+		return
+	}
+	for _, id := range ids {
+		if !q.acquire(id) {
+			continue // 既に送信中
+		}
+		q.wg.Add(1)
+		go func(id uuid.UUID) {
+			defer q.wg.Done()
+			more := q.deliver(id)
+			q.release(id)
+			if more {
+				q.wakeup()
+			}
+		}(id)
+	}
+}
+
+func (q *Queue) acquire(botID uuid.UUID) bool {
+	q.inflightMu.Lock()
+	defer q.inflightMu.Unlock()
+	if _, ok := q.inflight[botID]; ok {
+		return false
+	}
+	q.inflight[botID] = struct{}{}
+	return true
+}
+
+func (q *Queue) release(botID uuid.UUID) {
+	q.inflightMu.Lock()
+	defer q.inflightMu.Unlock()
+	delete(q.inflight, botID)
+}
+
+// deliver 指定したBOTのキューのイベントを先頭から送信します
+//
+// 一度に送信する数の上限に達し、まだイベントが残っている可能性がある場合にtrueを返します。
+func (q *Queue) deliver(botID uuid.UUID) (more bool) {
+	b, err := q.repo.GetBotByID(botID)
+	if err != nil {
+		if err != repository.ErrNotFound {
+			q.l.Error("failed to get bot", zap.Error(err), zap.Stringer("botId", botID))
+		}
+		return false
+	}
+	if b.State != model.BotActive || !q.isResponsible(b) {
+		return false
+	}
+
+	for i := 0; i < queueBatchSize; i++ {
+		select {
+		case <-q.stop:
+			return false
+		default:
+		}
+
+		item, err := q.repo.LeaseBotEventQueueHead(botID, time.Now().Add(queueLeaseDuration))
+		if err != nil {
+			if err != repository.ErrNotFound {
+				q.l.Error("failed to lease queued event", zap.Error(err), zap.Stringer("botId", botID))
+			}
+			return false // キューが空、再送待ち、または他のノードが送信中
+		}
+
+		if q.d.Send(b, item.Event, []byte(item.Body)) {
+			if err := q.repo.DeleteBotEventQueueItem(item.ID); err != nil {
+				q.l.Error("failed to delete queued event", zap.Error(err), zap.Stringer("id", item.ID))
+				return false
+			}
+			continue
+		}
+
+		attempts := item.Attempts + 1
+		dead := attempts >= MaxAttempts
+		if err := q.repo.UpdateBotEventQueueItemAttempts(item.ID, attempts, time.Now().Add(retryInterval(attempts)), dead); err != nil {
+			q.l.Error("failed to update queued event", zap.Error(err), zap.Stringer("id", item.ID))
+			return false
+		}
+		if dead {
+			q.l.Info("bot is paused due to consecutive delivery failures", zap.Stringer("botId", botID), zap.Stringer("id", item.ID))
+			if err := q.repo.ChangeBotState(botID, model.BotPaused); err != nil {
+				q.l.Error("failed to pause bot", zap.Error(err), zap.Stringer("botId", botID))
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// isResponsible このノードが指定したBOTへのイベントを送信するかどうか
+//
+// WebSocketモードのBOTが接続していない間は、どのノードからも送信されずにキューに残ります。
+func (q *Queue) isResponsible(b *model.Bot) bool {
+	if b.Mode == model.BotModeWebSocket {
+		return q.ws.IsConnected(b.BotUserID)
+	}
+	return q.bus.IsLeader()
+}
+
+// retryInterval attempts回目の送信に失敗した後、次に再送するまでの間隔
+func retryInterval(attempts int) time.Duration {
+	if attempts < 1 {
+		return 0
+	}
+	d := retryBaseInterval
+	for i := 1; i < attempts; i++ {
+		d *= 2
+		if d >= retryMaxInterval {
+			return retryMaxInterval
+		}
+	}
+	return d
+}
@@ -0,0 +1,191 @@
+package event
+
+import (
+	"errors"
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/repository/mock_repository"
+	"github.com/traPtitech/traQ/service/bot/event/mock_event"
+	"github.com/traPtitech/traQ/service/bot/event/payload"
+	"github.com/traPtitech/traQ/service/cluster"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+func TestRetryInterval(t *testing.T) {
+	t.Parallel()
+
+	assert.EqualValues(t, 0, retryInterval(0))
+	assert.EqualValues(t, 1*time.Second, retryInterval(1))
+	assert.EqualValues(t, 2*time.Second, retryInterval(2))
+	assert.EqualValues(t, 4*time.Second, retryInterval(3))
+	assert.EqualValues(t, retryMaxInterval, retryInterval(MaxAttempts))
+	assert.EqualValues(t, retryMaxInterval, retryInterval(100))
+}
+
+func TestQueue_Enqueue(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no target", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockBotRepository(ctrl)
+		q := NewQueue(zap.NewNop(), repo, mock_event.NewMockDispatcher(ctrl), &fakeWSSender{}, cluster.NewStandaloneBus())
+
+		assert.NoError(t, q.Enqueue(Ping, payload.MakePing(time.Now()), nil))
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockBotRepository(ctrl)
+		q := NewQueue(zap.NewNop(), repo, mock_event.NewMockDispatcher(ctrl), &fakeWSSender{}, cluster.NewStandaloneBus())
+
+		b1 := &model.Bot{ID: uuid.NewV3(uuid.Nil, "b1")}
+		b2 := &model.Bot{ID: uuid.NewV3(uuid.Nil, "b2")}
+		repo.EXPECT().
+			EnqueueBotEvents(gomock.Any()).
+			DoAndReturn(func(items []*model.BotEventQueueItem) error {
+				if assert.Len(t, items, 2) {
+					assert.Equal(t, b1.ID, items[0].BotID)
+					assert.Equal(t, b2.ID, items[1].BotID)
+					for _, item := range items {
+						assert.NotEqual(t, uuid.Nil, item.ID)
+						assert.Equal(t, Ping, item.Event)
+						assert.NotEmpty(t, item.Body)
+						assert.False(t, item.Dead)
+					}
+				}
+				return nil
+			}).
+			Times(1)
+
+		assert.NoError(t, q.Enqueue(Ping, payload.MakePing(time.Now()), []*model.Bot{b1, b2, b1}))
+	})
+}
+
+func TestQueue_deliver(t *testing.T) {
+	t.Parallel()
+
+	b := &model.Bot{ID: uuid.NewV3(uuid.Nil, "b"), State: model.BotActive}
+	newItem := func(name string, attempts int, next time.Time) *model.BotEventQueueItem {
+		return &model.BotEventQueueItem{
+			ID:            uuid.NewV3(uuid.Nil, name),
+			BotID:         b.ID,
+			Event:         Ping,
+			Body:          `{}`,
+			Attempts:      attempts,
+			NextAttemptAt: next,
+		}
+	}
+
+	t.Run("in order until empty", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockBotRepository(ctrl)
+		d := mock_event.NewMockDispatcher(ctrl)
+		q := NewQueue(zap.NewNop(), repo, d, &fakeWSSender{}, cluster.NewStandaloneBus())
+
+		i1 := newItem("i1", 0, time.Now())
+		i2 := newItem("i2", 0, time.Now())
+		repo.EXPECT().GetBotByID(b.ID).Return(b, nil)
+		gomock.InOrder(
+			repo.EXPECT().LeaseBotEventQueueHead(b.ID, gomock.Any()).Return(i1, nil),
+			d.EXPECT().Send(b, Ping, []byte(i1.Body)).Return(true),
+			repo.EXPECT().DeleteBotEventQueueItem(i1.ID).Return(nil),
+			repo.EXPECT().LeaseBotEventQueueHead(b.ID, gomock.Any()).Return(i2, nil),
+			d.EXPECT().Send(b, Ping, []byte(i2.Body)).Return(true),
+			repo.EXPECT().DeleteBotEventQueueItem(i2.ID).Return(nil),
+			repo.EXPECT().LeaseBotEventQueueHead(b.ID, gomock.Any()).Return(nil, repository.ErrNotFound),
+		)
+
+		assert.False(t, q.deliver(b.ID))
+	})
+
+	t.Run("waiting for retry or leased", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockBotRepository(ctrl)
+		d := mock_event.NewMockDispatcher(ctrl)
+		q := NewQueue(zap.NewNop(), repo, d, &fakeWSSender{}, cluster.NewStandaloneBus())
+
+		repo.EXPECT().GetBotByID(b.ID).Return(b, nil)
+		repo.EXPECT().
+			LeaseBotEventQueueHead(b.ID, gomock.Any()).
+			DoAndReturn(func(_ uuid.UUID, until time.Time) (*model.BotEventQueueItem, error) {
+				assert.True(t, until.After(time.Now()))
+				return nil, repository.ErrNotFound
+			})
+
+		assert.False(t, q.deliver(b.ID))
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockBotRepository(ctrl)
+		d := mock_event.NewMockDispatcher(ctrl)
+		q := NewQueue(zap.NewNop(), repo, d, &fakeWSSender{}, cluster.NewStandaloneBus())
+
+		i1 := newItem("i1", 2, time.Now())
+		repo.EXPECT().GetBotByID(b.ID).Return(b, nil)
+		repo.EXPECT().LeaseBotEventQueueHead(b.ID, gomock.Any()).Return(i1, nil)
+		d.EXPECT().Send(b, Ping, []byte(i1.Body)).Return(false)
+		repo.EXPECT().
+			UpdateBotEventQueueItemAttempts(i1.ID, 3, gomock.Any(), false).
+			DoAndReturn(func(_ uuid.UUID, _ int, next time.Time, _ bool) error {
+				assert.True(t, next.After(time.Now()))
+				return nil
+			})
+
+		assert.False(t, q.deliver(b.ID))
+	})
+
+	t.Run("dead", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockBotRepository(ctrl)
+		d := mock_event.NewMockDispatcher(ctrl)
+		q := NewQueue(zap.NewNop(), repo, d, &fakeWSSender{}, cluster.NewStandaloneBus())
+
+		i1 := newItem("i1", MaxAttempts-1, time.Now())
+		repo.EXPECT().GetBotByID(b.ID).Return(b, nil)
+		repo.EXPECT().LeaseBotEventQueueHead(b.ID, gomock.Any()).Return(i1, nil)
+		d.EXPECT().Send(b, Ping, []byte(i1.Body)).Return(false)
+		repo.EXPECT().UpdateBotEventQueueItemAttempts(i1.ID, MaxAttempts, gomock.Any(), true).Return(nil)
+		repo.EXPECT().ChangeBotState(b.ID, model.BotPaused).Return(nil)
+
+		assert.False(t, q.deliver(b.ID))
+	})
+
+	t.Run("websocket bot not connected", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockBotRepository(ctrl)
+		d := mock_event.NewMockDispatcher(ctrl)
+		q := NewQueue(zap.NewNop(), repo, d, &fakeWSSender{err: errors.New("not connected")}, cluster.NewStandaloneBus())
+
+		wsBot := &model.Bot{ID: b.ID, Mode: model.BotModeWebSocket, State: model.BotActive}
+		repo.EXPECT().GetBotByID(b.ID).Return(wsBot, nil)
+
+		// 接続を保持しているノードがないので送信されない
+		assert.False(t, q.deliver(b.ID))
+	})
+
+	t.Run("inactive bot", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockBotRepository(ctrl)
+		d := mock_event.NewMockDispatcher(ctrl)
+		q := NewQueue(zap.NewNop(), repo, d, &fakeWSSender{}, cluster.NewStandaloneBus())
+
+		paused := &model.Bot{ID: b.ID, State: model.BotPaused}
+		repo.EXPECT().GetBotByID(b.ID).Return(paused, nil)
+
+		assert.False(t, q.deliver(b.ID))
+	})
+}
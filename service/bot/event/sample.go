@@ -0,0 +1,125 @@
+package event
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gofrs/uuid"
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/service/bot/event/payload"
+	"github.com/traPtitech/traQ/utils/message"
+	"time"
+)
+
+// ErrUnknownEventType 未知のイベントタイプです
+var ErrUnknownEventType = errors.New("unknown event type")
+
+// sampleID サンプルペイロード用の固定UUIDを生成します
+func sampleID(name string) uuid.UUID {
+	return uuid.NewV5(uuid.Nil, "traq-bot-sample-"+name)
+}
+
+// MakeSamplePayload テスト送信用のサンプルペイロードを生成します
+//
+// ペイロードは実際のイベントと同じ payload.Make* 関数で生成され、userが操作者として用いられます。
+// 実際に送信されるイベントタイプとペイロードを返します。
+// MENTION_MESSAGE_CREATEDはBOTへのメンションを含むMESSAGE_CREATEDとして送信されます。
+// 未知のイベントタイプの場合、ErrUnknownEventTypeを返します。
+func MakeSamplePayload(et time.Time, ev model.BotEventType, b *model.Bot, user model.UserInfo) (model.BotEventType, interface{}, error) {
+	ch := &model.Channel{
+		ID:        sampleID("channel"),
+		Name:      "sample",
+		Topic:     "sample topic",
+		IsPublic:  true,
+		IsVisible: true,
+		CreatorID: user.GetID(),
+		UpdaterID: user.GetID(),
+		CreatedAt: et,
+		UpdatedAt: et,
+	}
+	const chPath = "sample"
+	m := &model.Message{
+		ID:        sampleID("message"),
+		UserID:    user.GetID(),
+		ChannelID: ch.ID,
+		Text:      "This is a sample message.",
+		CreatedAt: et,
+		UpdatedAt: et,
+	}
+	stamp := &model.Stamp{
+		ID:        sampleID("stamp"),
+		Name:      "sample",
+		CreatorID: user.GetID(),
+		FileID:    sampleID("stamp-file"),
+		CreatedAt: et,
+		UpdatedAt: et,
+	}
+	tag := &model.Tag{
+		ID:        sampleID("tag"),
+		Name:      "sample",
+		CreatedAt: et,
+		UpdatedAt: et,
+	}
+
+	switch ev {
+	case Ping:
+		return ev, payload.MakePing(et), nil
+	case Joined:
+		return ev, payload.MakeJoined(et, ch, chPath, user), nil
+	case Left:
+		return ev, payload.MakeLeft(et, ch, chPath, user), nil
+	case MessageCreated:
+		return ev, payload.MakeMessageCreated(et, m, user, message.Parse(m.Text)), nil
+	case MentionMessageCreated:
+		m.Text = fmt.Sprintf(`!{"type":"user","raw":"@BOT","id":"%s"} This is a sample message.`, b.BotUserID)
+		return MessageCreated, payload.MakeMessageCreated(et, m, user, message.Parse(m.Text)), nil
+	case MessageUpdated:
+		return ev, payload.MakeMessageUpdated(et, m, user, message.Parse(m.Text)), nil
+	case MessageDeleted:
+		return ev, payload.MakeMessageDeleted(et, m), nil
+	case MessageStamped:
+		return ev, payload.MakeMessageStamped(et, m, stamp, user, 1), nil
+	case MessageUnstamped:
+		return ev, payload.MakeMessageUnstamped(et, m, stamp, user), nil
+	case MessagePinned:
+		return ev, payload.MakeMessagePinned(et, m.ID, m.ChannelID), nil
+	case DirectMessageCreated:
+		m.ChannelID = sampleID("dm-channel")
+		return ev, payload.MakeDirectMessageCreated(et, m, user, message.Parse(m.Text)), nil
+	case ChannelCreated:
+		return ev, payload.MakeChannelCreated(et, ch, chPath, user), nil
+	case ChannelTopicChanged:
+		return ev, payload.MakeChannelTopicChanged(et, ch, chPath, user, ch.Topic, user), nil
+	case ChannelUpdated:
+		return ev, payload.MakeChannelUpdated(et, ch, chPath, user), nil
+	case ChannelDeleted:
+		return ev, payload.MakeChannelDeleted(et, ch.ID), nil
+	case UserCreated:
+		return ev, payload.MakeUserCreated(et, user), nil
+	case UserUpdated:
+		return ev, payload.MakeUserUpdated(et, user), nil
+	case UserGroupMemberAdded:
+		return ev, payload.MakeUserGroupMemberAdded(et, sampleID("group"), user), nil
+	case UserGroupMemberRemoved:
+		return ev, payload.MakeUserGroupMemberRemoved(et, sampleID("group"), user), nil
+	case StampCreated:
+		return ev, payload.MakeStampCreated(et, stamp, user), nil
+	case StampUpdated:
+		return ev, payload.MakeStampUpdated(et, stamp, user), nil
+	case StampDeleted:
+		return ev, payload.MakeStampDeleted(et, stamp.ID), nil
+	case TagAdded:
+		return ev, payload.MakeTagAdded(et, tag), nil
+	case TagRemoved:
+		return ev, payload.MakeTagRemoved(et, tag), nil
+	case BotStateChanged:
+		return ev, payload.MakeBotStateChanged(et, b.State), nil
+	case CommandInvoked:
+		cmd := &model.BotCommand{ID: sampleID("command"), BotID: b.ID, Name: "sample"}
+		return ev, payload.MakeCommandInvoked(et, cmd, map[string]string{"arg": "value"}, "/sample value", user, ch.ID), nil
+	case Interaction:
+		c := &model.MessageComponent{ID: "sample", Type: model.MessageComponentTypeButton, Label: "Sample"}
+		return ev, payload.MakeInteraction(et, m, c, "sample", user), nil
+	default:
+		return "", nil, ErrUnknownEventType
+	}
+}
@@ -0,0 +1,62 @@
+package event
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/service/bot/event/payload"
+	"testing"
+	"time"
+)
+
+func TestMakeSamplePayload(t *testing.T) {
+	t.Parallel()
+
+	b := &model.Bot{ID: uuid.NewV3(uuid.Nil, "b"), BotUserID: uuid.NewV3(uuid.Nil, "bu"), State: model.BotActive}
+	u := &model.User{ID: uuid.NewV3(uuid.Nil, "u"), Name: "testman"}
+	et := time.Now()
+
+	t.Run("all event types", func(t *testing.T) {
+		t.Parallel()
+
+		for ev := range Types {
+			sent, p, err := MakeSamplePayload(et, ev, b, u)
+			if assert.NoError(t, err, ev) {
+				assert.NotNil(t, p, ev)
+				assert.True(t, Types.Contains(sent), ev)
+			}
+		}
+	})
+
+	t.Run("message created", func(t *testing.T) {
+		t.Parallel()
+
+		sent, p, err := MakeSamplePayload(et, MessageCreated, b, u)
+		if assert.NoError(t, err) {
+			assert.Equal(t, MessageCreated, sent)
+			if pl, ok := p.(*payload.MessageCreated); assert.True(t, ok) {
+				assert.Equal(t, u.ID, pl.Message.User.ID)
+				assert.Equal(t, et, pl.EventTime)
+			}
+		}
+	})
+
+	t.Run("mention message created", func(t *testing.T) {
+		t.Parallel()
+
+		sent, p, err := MakeSamplePayload(et, MentionMessageCreated, b, u)
+		if assert.NoError(t, err) {
+			assert.Equal(t, MessageCreated, sent)
+			if pl, ok := p.(*payload.MessageCreated); assert.True(t, ok) && assert.Len(t, pl.Message.Embedded, 1) {
+				assert.Equal(t, b.BotUserID.String(), pl.Message.Embedded[0].ID)
+			}
+		}
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, err := MakeSamplePayload(et, "UNKNOWN", b, u)
+		assert.Equal(t, ErrUnknownEventType, err)
+	})
+}
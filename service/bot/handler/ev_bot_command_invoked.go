@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"fmt"
+	"github.com/gofrs/uuid"
+	"github.com/leandro-lugaresi/hub"
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/service/bot/event"
+	"github.com/traPtitech/traQ/service/bot/event/payload"
+	"time"
+)
+
+func BotCommandInvoked(ctx Context, datetime time.Time, _ string, fields hub.Fields) error {
+	botID := fields["bot_id"].(uuid.UUID)
+	cmd := fields["command"].(*model.BotCommand)
+	userID := fields["user_id"].(uuid.UUID)
+	channelID := fields["channel_id"].(uuid.UUID)
+	args := fields["args"].(map[string]string)
+	text := fields["text"].(string)
+
+	bot, err := ctx.GetBot(botID)
+	if err != nil {
+		return fmt.Errorf("failed to GetBot: %w", err)
+	}
+	if bot == nil {
+		return nil
+	}
+
+	user, err := ctx.R().GetUser(userID, false)
+	if err != nil {
+		return fmt.Errorf("failed to GetUser: %w", err)
+	}
+
+	if err := ctx.Unicast(
+		event.CommandInvoked,
+		payload.MakeCommandInvoked(datetime, cmd, args, text, user, channelID),
+		bot,
+	); err != nil {
+		return fmt.Errorf("failed to unicast: %w", err)
+	}
+	return nil
+}
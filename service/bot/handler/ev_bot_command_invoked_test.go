@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+	"github.com/leandro-lugaresi/hub"
+	"github.com/stretchr/testify/assert"
+	intevent "github.com/traPtitech/traQ/event"
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/service/bot/event"
+	"github.com/traPtitech/traQ/service/bot/event/payload"
+	"testing"
+	"time"
+)
+
+func TestBotCommandInvoked(t *testing.T) {
+	t.Parallel()
+
+	b := &model.Bot{
+		ID:              uuid.NewV3(uuid.Nil, "b"),
+		BotUserID:       uuid.NewV3(uuid.Nil, "bu"),
+		SubscribeEvents: model.BotEventTypes{},
+		State:           model.BotActive,
+	}
+	user := &model.User{
+		ID:   uuid.NewV3(uuid.Nil, "u"),
+		Name: "testman",
+	}
+	cmd := &model.BotCommand{
+		ID:    uuid.NewV3(uuid.Nil, "cmd"),
+		BotID: b.ID,
+		Name:  "deploy",
+		Arguments: model.BotCommandArguments{
+			{Name: "env", Required: true},
+		},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		handlerCtx, _, repo := setup(t, ctrl)
+		registerBot(t, handlerCtx, b)
+		registerUser(repo, user)
+
+		chID := uuid.NewV3(uuid.Nil, "c")
+		args := map[string]string{"env": "production"}
+		et := time.Now()
+
+		expectUnicast(handlerCtx, event.CommandInvoked, payload.MakeCommandInvoked(et, cmd, args, "production", user, chID), b)
+		assert.NoError(t, BotCommandInvoked(handlerCtx, et, intevent.BotCommandInvoked, hub.Fields{
+			"bot_id":     b.ID,
+			"command":    cmd,
+			"user_id":    user.ID,
+			"channel_id": chID,
+			"args":       args,
+			"text":       "production",
+		}))
+	})
+}
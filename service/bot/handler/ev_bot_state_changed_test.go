@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+	"github.com/leandro-lugaresi/hub"
+	"github.com/stretchr/testify/assert"
+	intevent "github.com/traPtitech/traQ/event"
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/service/bot/event"
+	"github.com/traPtitech/traQ/service/bot/event/payload"
+	"testing"
+	"time"
+)
+
+func TestBotStateChanged(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		handlerCtx, _, _ := setup(t, ctrl)
+
+		b := &model.Bot{
+			ID:              uuid.NewV3(uuid.Nil, "b"),
+			BotUserID:       uuid.NewV3(uuid.Nil, "bu"),
+			SubscribeEvents: model.BotEventTypesFromArray([]string{event.BotStateChanged.String()}),
+			State:           model.BotActive,
+		}
+		registerBot(t, handlerCtx, b)
+		et := time.Now()
+
+		expectUnicast(handlerCtx, event.BotStateChanged, payload.MakeBotStateChanged(et, model.BotActive), b)
+		assert.NoError(t, BotStateChanged(handlerCtx, et, intevent.BotStateChanged, hub.Fields{
+			"bot_id": b.ID,
+			"state":  model.BotActive,
+		}))
+	})
+
+	t.Run("not subscribed", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		handlerCtx, _, _ := setup(t, ctrl)
+
+		b := &model.Bot{
+			ID:              uuid.NewV3(uuid.Nil, "b"),
+			BotUserID:       uuid.NewV3(uuid.Nil, "bu"),
+			SubscribeEvents: model.BotEventTypes{},
+			State:           model.BotActive,
+		}
+		registerBot(t, handlerCtx, b)
+
+		assert.NoError(t, BotStateChanged(handlerCtx, time.Now(), intevent.BotStateChanged, hub.Fields{
+			"bot_id": b.ID,
+			"state":  model.BotActive,
+		}))
+	})
+}
@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"fmt"
+	"github.com/gofrs/uuid"
+	"github.com/leandro-lugaresi/hub"
+	"github.com/traPtitech/traQ/service/bot/event"
+	"github.com/traPtitech/traQ/service/bot/event/payload"
+	"time"
+)
+
+func ChannelDeleted(ctx Context, datetime time.Time, _ string, fields hub.Fields) error {
+	chID := fields["channel_id"].(uuid.UUID)
+	private := fields["private"].(bool)
+	if private {
+		return nil // プライベートチャンネルは無視
+	}
+
+	bots, err := ctx.GetBots(event.ChannelDeleted)
+	if err != nil {
+		return fmt.Errorf("failed to GetBots: %w", err)
+	}
+	if len(bots) == 0 {
+		return nil
+	}
+
+	if err := ctx.Multicast(
+		event.ChannelDeleted,
+		payload.MakeChannelDeleted(datetime, chID),
+		bots,
+	); err != nil {
+		return fmt.Errorf("failed to multicast: %w", err)
+	}
+	return nil
+}
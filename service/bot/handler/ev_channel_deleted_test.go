@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+	"github.com/leandro-lugaresi/hub"
+	"github.com/stretchr/testify/assert"
+	intevent "github.com/traPtitech/traQ/event"
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/service/bot/event"
+	"github.com/traPtitech/traQ/service/bot/event/payload"
+	"testing"
+	"time"
+)
+
+func TestChannelDeleted(t *testing.T) {
+	t.Parallel()
+
+	b := &model.Bot{
+		ID:              uuid.NewV3(uuid.Nil, "b"),
+		BotUserID:       uuid.NewV3(uuid.Nil, "bu"),
+		SubscribeEvents: model.BotEventTypesFromArray([]string{event.ChannelDeleted.String()}),
+		State:           model.BotActive,
+	}
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		handlerCtx, _, _ := setup(t, ctrl)
+		registerBot(t, handlerCtx, b)
+
+		chID := uuid.NewV3(uuid.Nil, "c")
+		et := time.Now()
+
+		expectMulticast(handlerCtx, event.ChannelDeleted, payload.MakeChannelDeleted(et, chID), []*model.Bot{b})
+		assert.NoError(t, ChannelDeleted(handlerCtx, et, intevent.ChannelDeleted, hub.Fields{
+			"channel_id": chID,
+			"private":    false,
+		}))
+	})
+
+	t.Run("private channel", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		handlerCtx, _, _ := setup(t, ctrl)
+		registerBot(t, handlerCtx, b)
+
+		assert.NoError(t, ChannelDeleted(handlerCtx, time.Now(), intevent.ChannelDeleted, hub.Fields{
+			"channel_id": uuid.NewV3(uuid.Nil, "pc"),
+			"private":    true,
+		}))
+	})
+}
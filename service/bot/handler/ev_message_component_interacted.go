@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"fmt"
+	"github.com/gofrs/uuid"
+	"github.com/leandro-lugaresi/hub"
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/service/bot/event"
+	"github.com/traPtitech/traQ/service/bot/event/payload"
+	"time"
+)
+
+func MessageComponentInteracted(ctx Context, datetime time.Time, _ string, fields hub.Fields) error {
+	m := fields["message"].(*model.Message)
+	botID := fields["bot_id"].(uuid.UUID)
+	component := fields["component"].(*model.MessageComponent)
+	value := fields["value"].(string)
+	userID := fields["user_id"].(uuid.UUID)
+
+	bot, err := ctx.GetBot(botID)
+	if err != nil {
+		return fmt.Errorf("failed to GetBot: %w", err)
+	}
+	if bot == nil {
+		return nil
+	}
+
+	user, err := ctx.R().GetUser(userID, false)
+	if err != nil {
+		return fmt.Errorf("failed to GetUser: %w", err)
+	}
+
+	if err := ctx.Unicast(
+		event.Interaction,
+		payload.MakeInteraction(datetime, m, component, value, user),
+		bot,
+	); err != nil {
+		return fmt.Errorf("failed to unicast: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+	"github.com/leandro-lugaresi/hub"
+	"github.com/stretchr/testify/assert"
+	intevent "github.com/traPtitech/traQ/event"
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/service/bot/event"
+	"github.com/traPtitech/traQ/service/bot/event/payload"
+	"testing"
+	"time"
+)
+
+func TestMessageComponentInteracted(t *testing.T) {
+	t.Parallel()
+
+	b := &model.Bot{
+		ID:              uuid.NewV3(uuid.Nil, "b"),
+		BotUserID:       uuid.NewV3(uuid.Nil, "bu"),
+		SubscribeEvents: model.BotEventTypes{},
+		State:           model.BotActive,
+	}
+	user := &model.User{
+		ID:   uuid.NewV3(uuid.Nil, "u"),
+		Name: "testman",
+	}
+	m := &model.Message{
+		ID:        uuid.NewV3(uuid.Nil, "m"),
+		UserID:    b.BotUserID,
+		ChannelID: uuid.NewV3(uuid.Nil, "c"),
+		Text:      "deploy?",
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	component := &model.MessageComponent{
+		ID:   "env",
+		Type: model.MessageComponentTypeSelect,
+		Options: []model.MessageComponentOption{
+			{Label: "本番", Value: "production"},
+		},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		handlerCtx, _, repo := setup(t, ctrl)
+		registerBot(t, handlerCtx, b)
+		registerUser(repo, user)
+		et := time.Now()
+
+		expectUnicast(handlerCtx, event.Interaction, payload.MakeInteraction(et, m, component, "production", user), b)
+		assert.NoError(t, MessageComponentInteracted(handlerCtx, et, intevent.MessageComponentInteracted, hub.Fields{
+			"message_id": m.ID,
+			"message":    m,
+			"bot_id":     b.ID,
+			"component":  component,
+			"value":      "production",
+			"user_id":    user.ID,
+		}))
+	})
+}
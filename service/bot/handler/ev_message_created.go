@@ -56,27 +56,43 @@ func MessageCreated(ctx Context, datetime time.Time, _ string, fields hub.Fields
 			return fmt.Errorf("failed to unicast: %w", err)
 		}
 	} else {
-		// 購読BOT
-		bots, err := ctx.GetChannelBots(m.ChannelID, event.MessageCreated)
-		if err != nil {
-			return fmt.Errorf("failed to GetChannelBots: %w", err)
-		}
+		var bots []*model.Bot
+		if ch.IsPrivateChannel() {
+			// プライベートチャンネルはメンバーのBOTのみ
+			mentioned := make(map[uuid.UUID]bool)
+			for _, uid := range parsed.Mentions {
+				mentioned[uid] = true
+			}
+			bots, err = getPrivateChannelMemberBots(ctx, ch.ID, func(b *model.Bot) bool {
+				return b.SubscribeEvents.Contains(event.MessageCreated) ||
+					(mentioned[b.BotUserID] && b.SubscribeEvents.Contains(event.MentionMessageCreated))
+			})
+			if err != nil {
+				return err
+			}
+		} else {
+			// 購読BOT
+			bots, err = ctx.GetChannelBots(m.ChannelID, event.MessageCreated)
+			if err != nil {
+				return fmt.Errorf("failed to GetChannelBots: %w", err)
+			}
 
-		// メンションBOT
-		done := make(map[uuid.UUID]bool)
-		for _, uid := range parsed.Mentions {
-			if !done[uid] {
-				done[uid] = true
-				b, err := ctx.GetBotByBotUserID(uid)
-				if err != nil {
-					ctx.L().Error("failed to GetBotByBotUserID", zap.Error(err))
-					continue
-				}
-				if b == nil {
-					continue
-				}
-				if b.SubscribeEvents.Contains(event.MentionMessageCreated) {
-					bots = append(bots, b)
+			// メンションBOT
+			done := make(map[uuid.UUID]bool)
+			for _, uid := range parsed.Mentions {
+				if !done[uid] {
+					done[uid] = true
+					b, err := ctx.GetBotByBotUserID(uid)
+					if err != nil {
+						ctx.L().Error("failed to GetBotByBotUserID", zap.Error(err))
+						continue
+					}
+					if b == nil {
+						continue
+					}
+					if b.SubscribeEvents.Contains(event.MentionMessageCreated) {
+						bots = append(bots, b)
+					}
 				}
 			}
 		}
@@ -97,6 +113,43 @@ func MessageCreated(ctx Context, datetime time.Time, _ string, fields hub.Fields
 	return nil
 }
 
+// getPrivateChannelMemberBots プライベートチャンネルのメンバーのうち、filterを満たすBOTを取得します
+func getPrivateChannelMemberBots(ctx Context, channelID uuid.UUID, filter func(b *model.Bot) bool) ([]*model.Bot, error) {
+	members, err := ctx.CM().GetPrivateChannelMembers(channelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to GetPrivateChannelMembers: %w", err)
+	}
+
+	bots := make([]*model.Bot, 0)
+	for _, uid := range members {
+		b, err := ctx.GetBotByBotUserID(uid)
+		if err != nil {
+			return nil, fmt.Errorf("failed to GetBotByBotUserID: %w", err)
+		}
+		if b != nil && filter(b) {
+			bots = append(bots, b)
+		}
+	}
+	return bots, nil
+}
+
+// getChannelEventBots チャンネルに紐づくイベントの送信先となるBOTを取得します
+//
+// プライベートチャンネルはメンバーのBOTのみ、公開チャンネルは参加しているBOTが対象です。
+func getChannelEventBots(ctx Context, ch *model.Channel, ev model.BotEventType) ([]*model.Bot, error) {
+	if ch.IsPrivateChannel() {
+		return getPrivateChannelMemberBots(ctx, ch.ID, func(b *model.Bot) bool {
+			return b.SubscribeEvents.Contains(ev)
+		})
+	}
+
+	bots, err := ctx.GetChannelBots(ch.ID, ev)
+	if err != nil {
+		return nil, fmt.Errorf("failed to GetChannelBots: %w", err)
+	}
+	return bots, nil
+}
+
 func filterBotUserIDNotEquals(bots []*model.Bot, id uuid.UUID) []*model.Bot {
 	result := make([]*model.Bot, 0, len(bots))
 	for _, bot := range bots {
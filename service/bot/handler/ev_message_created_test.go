@@ -97,4 +97,48 @@ func TestMessageCreated(t *testing.T) {
 			"parse_result": message.Parse(m.Text),
 		}))
 	})
+
+	t.Run("success (private message, only member bots)", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		handlerCtx, cm, repo := setup(t, ctrl)
+		registerBot(t, handlerCtx, b)
+
+		pch := &model.Channel{
+			ID:       uuid.NewV3(uuid.Nil, "pc"),
+			Name:     "private",
+			ParentID: uuid.FromStringOrNil(model.PrivateChannelRootID),
+		}
+		m := &model.Message{
+			ID:        uuid.NewV3(uuid.Nil, "m"),
+			UserID:    uuid.NewV3(uuid.Nil, "u"),
+			ChannelID: pch.ID,
+			Text:      "test message",
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		}
+		parsed := message.Parse(m.Text)
+		mu := &model.User{
+			ID:   m.UserID,
+			Name: "testman",
+		}
+		registerUser(repo, mu)
+		registerChannel(cm, pch)
+		handlerCtx.EXPECT().
+			GetBotByBotUserID(m.UserID).
+			Return(nil, nil).
+			AnyTimes()
+		cm.EXPECT().
+			GetPrivateChannelMembers(pch.ID).
+			Return([]uuid.UUID{m.UserID, b.BotUserID}, nil).
+			Times(1)
+		et := time.Now()
+
+		expectMulticast(handlerCtx, event.MessageCreated, payload.MakeMessageCreated(et, m, mu, parsed), []*model.Bot{b})
+		assert.NoError(t, MessageCreated(handlerCtx, et, intevent.MessageCreated, hub.Fields{
+			"message_id":   m.ID,
+			"message":      m,
+			"parse_result": parsed,
+		}))
+	})
 }
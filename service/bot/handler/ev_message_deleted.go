@@ -20,9 +20,20 @@ func MessageDeleted(ctx Context, datetime time.Time, _ string, fields hub.Fields
 	if ch.IsDMChannel() {
 
 	} else {
-		bots, err := ctx.GetChannelBots(m.ChannelID, event.MessageDeleted)
-		if err != nil {
-			return fmt.Errorf("failed to GetChannelBots: %w", err)
+		var bots []*model.Bot
+		if ch.IsPrivateChannel() {
+			// プライベートチャンネルはメンバーのBOTのみ
+			bots, err = getPrivateChannelMemberBots(ctx, ch.ID, func(b *model.Bot) bool {
+				return b.SubscribeEvents.Contains(event.MessageDeleted)
+			})
+			if err != nil {
+				return err
+			}
+		} else {
+			bots, err = ctx.GetChannelBots(m.ChannelID, event.MessageDeleted)
+			if err != nil {
+				return fmt.Errorf("failed to GetChannelBots: %w", err)
+			}
 		}
 
 		if err := ctx.Multicast(
@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"fmt"
+	"github.com/gofrs/uuid"
+	"github.com/leandro-lugaresi/hub"
+	"github.com/traPtitech/traQ/service/bot/event"
+	"github.com/traPtitech/traQ/service/bot/event/payload"
+	"time"
+)
+
+func MessagePinned(ctx Context, datetime time.Time, _ string, fields hub.Fields) error {
+	mID := fields["message_id"].(uuid.UUID)
+	chID := fields["channel_id"].(uuid.UUID)
+
+	ch, err := ctx.CM().GetChannel(chID)
+	if err != nil {
+		return fmt.Errorf("failed to GetChannel: %w", err)
+	}
+	if ch.IsDMChannel() {
+		return nil
+	}
+
+	bots, err := getChannelEventBots(ctx, ch, event.MessagePinned)
+	if err != nil {
+		return err
+	}
+	if len(bots) == 0 {
+		return nil
+	}
+
+	if err := ctx.Multicast(
+		event.MessagePinned,
+		payload.MakeMessagePinned(datetime, mID, chID),
+		bots,
+	); err != nil {
+		return fmt.Errorf("failed to multicast: %w", err)
+	}
+	return nil
+}
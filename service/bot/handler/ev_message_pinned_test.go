@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+	"github.com/leandro-lugaresi/hub"
+	"github.com/stretchr/testify/assert"
+	intevent "github.com/traPtitech/traQ/event"
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/service/bot/event"
+	"github.com/traPtitech/traQ/service/bot/event/payload"
+	"testing"
+	"time"
+)
+
+func TestMessagePinned(t *testing.T) {
+	t.Parallel()
+
+	b := &model.Bot{
+		ID:              uuid.NewV3(uuid.Nil, "b"),
+		BotUserID:       uuid.NewV3(uuid.Nil, "bu"),
+		SubscribeEvents: model.BotEventTypesFromArray([]string{event.MessagePinned.String()}),
+		State:           model.BotActive,
+	}
+	ch := &model.Channel{
+		ID:       uuid.NewV3(uuid.Nil, "c"),
+		Name:     "test",
+		IsPublic: true,
+	}
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		handlerCtx, cm, _ := setup(t, ctrl)
+		registerBot(t, handlerCtx, b)
+		registerChannel(cm, ch)
+
+		handlerCtx.EXPECT().
+			GetChannelBots(ch.ID, event.MessagePinned).
+			Return([]*model.Bot{b}, nil).
+			AnyTimes()
+
+		mID := uuid.NewV3(uuid.Nil, "m")
+		et := time.Now()
+
+		expectMulticast(handlerCtx, event.MessagePinned, payload.MakeMessagePinned(et, mID, ch.ID), []*model.Bot{b})
+		assert.NoError(t, MessagePinned(handlerCtx, et, intevent.MessagePinned, hub.Fields{
+			"message_id": mID,
+			"channel_id": ch.ID,
+		}))
+	})
+}
@@ -0,0 +1,13 @@
+package handler
+
+import (
+	"github.com/leandro-lugaresi/hub"
+	"time"
+)
+
+// MessageReplied スレッドへの返信をMESSAGE_CREATEDイベントとして送信します
+//
+// 送信先はチャンネルへの投稿と同じで、ペイロードのmessage.parentIdに返信先のメッセージのIDが設定されます。
+func MessageReplied(ctx Context, datetime time.Time, ev string, fields hub.Fields) error {
+	return MessageCreated(ctx, datetime, ev, fields)
+}
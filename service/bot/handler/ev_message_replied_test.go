@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"fmt"
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+	"github.com/leandro-lugaresi/hub"
+	"github.com/stretchr/testify/assert"
+	intevent "github.com/traPtitech/traQ/event"
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/service/bot/event"
+	"github.com/traPtitech/traQ/service/bot/event/payload"
+	"github.com/traPtitech/traQ/utils/message"
+	"github.com/traPtitech/traQ/utils/optional"
+	"testing"
+	"time"
+)
+
+func TestMessageReplied(t *testing.T) {
+	t.Parallel()
+
+	b := &model.Bot{
+		ID:              uuid.NewV3(uuid.Nil, "b"),
+		BotUserID:       uuid.NewV3(uuid.Nil, "bu"),
+		SubscribeEvents: model.BotEventTypesFromArray([]string{event.MentionMessageCreated.String()}),
+		State:           model.BotActive,
+	}
+	ch := &model.Channel{
+		ID:       uuid.NewV3(uuid.Nil, "c"),
+		Name:     "test",
+		IsPublic: true,
+	}
+
+	t.Run("success (mentioned in reply)", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		handlerCtx, cm, repo := setup(t, ctrl)
+		registerBot(t, handlerCtx, b)
+
+		m := &model.Message{
+			ID:        uuid.NewV3(uuid.Nil, "m"),
+			UserID:    uuid.NewV3(uuid.Nil, "u"),
+			ChannelID: ch.ID,
+			ParentID:  optional.UUIDFrom(uuid.NewV3(uuid.Nil, "p")),
+			Text:      fmt.Sprintf(`!{"type":"user","raw":"@BOT_TEST","id":"%s"}`, b.BotUserID),
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		}
+		parsed := message.Parse(m.Text)
+		mu := &model.User{
+			ID:   m.UserID,
+			Name: "testman",
+		}
+		registerUser(repo, mu)
+		registerChannel(cm, ch)
+		et := time.Now()
+
+		handlerCtx.EXPECT().
+			GetChannelBots(m.ChannelID, event.MessageCreated).
+			Return([]*model.Bot{}, nil).
+			AnyTimes()
+
+		p := payload.MakeMessageCreated(et, m, mu, parsed)
+		assert.Equal(t, m.ParentID, p.Message.ParentID)
+		expectMulticast(handlerCtx, event.MessageCreated, p, []*model.Bot{b})
+		assert.NoError(t, MessageReplied(handlerCtx, et, intevent.MessageReplied, hub.Fields{
+			"message_id":   m.ID,
+			"message":      m,
+			"parent_id":    m.ParentID.UUID,
+			"parse_result": parsed,
+		}))
+	})
+}
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+	"github.com/leandro-lugaresi/hub"
+	"github.com/stretchr/testify/assert"
+	intevent "github.com/traPtitech/traQ/event"
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/service/bot/event"
+	"github.com/traPtitech/traQ/service/bot/event/payload"
+	"testing"
+	"time"
+)
+
+func TestMessageStamped(t *testing.T) {
+	t.Parallel()
+
+	b := &model.Bot{
+		ID:              uuid.NewV3(uuid.Nil, "b"),
+		BotUserID:       uuid.NewV3(uuid.Nil, "bu"),
+		SubscribeEvents: model.BotEventTypesFromArray([]string{event.MessageStamped.String()}),
+		State:           model.BotActive,
+	}
+	user := &model.User{
+		ID:   uuid.NewV3(uuid.Nil, "u"),
+		Name: "testman",
+	}
+	ch := &model.Channel{
+		ID:       uuid.NewV3(uuid.Nil, "c"),
+		Name:     "test",
+		IsPublic: true,
+	}
+	m := &model.Message{
+		ID:        uuid.NewV3(uuid.Nil, "m"),
+		UserID:    user.ID,
+		ChannelID: ch.ID,
+		Text:      "test message",
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	stamp := &model.Stamp{
+		ID:     uuid.NewV3(uuid.Nil, "s"),
+		Name:   "test",
+		FileID: uuid.NewV3(uuid.Nil, "f"),
+	}
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		handlerCtx, cm, repo := setup(t, ctrl)
+		registerBot(t, handlerCtx, b)
+		registerUser(repo, user)
+		registerChannel(cm, ch)
+		registerMessage(repo, m)
+		registerStamp(repo, stamp)
+
+		handlerCtx.EXPECT().
+			GetChannelBots(ch.ID, event.MessageStamped).
+			Return([]*model.Bot{b}, nil).
+			AnyTimes()
+
+		et := time.Now()
+
+		expectMulticast(handlerCtx, event.MessageStamped, payload.MakeMessageStamped(et, m, stamp, user, 2), []*model.Bot{b})
+		assert.NoError(t, MessageStamped(handlerCtx, et, intevent.MessageStamped, hub.Fields{
+			"message_id": m.ID,
+			"user_id":    user.ID,
+			"stamp_id":   stamp.ID,
+			"count":      2,
+			"created_at": et,
+		}))
+	})
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"fmt"
+	"github.com/gofrs/uuid"
+	"github.com/leandro-lugaresi/hub"
+	"github.com/traPtitech/traQ/service/bot/event"
+	"github.com/traPtitech/traQ/service/bot/event/payload"
+	"time"
+)
+
+func MessageUnstamped(ctx Context, datetime time.Time, _ string, fields hub.Fields) error {
+	mID := fields["message_id"].(uuid.UUID)
+	userID := fields["user_id"].(uuid.UUID)
+	stampID := fields["stamp_id"].(uuid.UUID)
+
+	m, err := ctx.R().GetMessageByID(mID)
+	if err != nil {
+		return fmt.Errorf("failed to GetMessageByID: %w", err)
+	}
+
+	ch, err := ctx.CM().GetChannel(m.ChannelID)
+	if err != nil {
+		return fmt.Errorf("failed to GetChannel: %w", err)
+	}
+	if ch.IsDMChannel() {
+		return nil
+	}
+
+	bots, err := getChannelEventBots(ctx, ch, event.MessageUnstamped)
+	if err != nil {
+		return err
+	}
+	bots = filterBotUserIDNotEquals(bots, userID)
+	if len(bots) == 0 {
+		return nil
+	}
+
+	stamp, err := ctx.R().GetStamp(stampID)
+	if err != nil {
+		return fmt.Errorf("failed to GetStamp: %w", err)
+	}
+
+	user, err := ctx.R().GetUser(userID, false)
+	if err != nil {
+		return fmt.Errorf("failed to GetUser: %w", err)
+	}
+
+	if err := ctx.Multicast(
+		event.MessageUnstamped,
+		payload.MakeMessageUnstamped(datetime, m, stamp, user),
+		bots,
+	); err != nil {
+		return fmt.Errorf("failed to multicast: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"fmt"
+	"github.com/leandro-lugaresi/hub"
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/service/bot/event"
+	"github.com/traPtitech/traQ/service/bot/event/payload"
+	"github.com/traPtitech/traQ/utils/message"
+	"time"
+)
+
+func MessageUpdated(ctx Context, datetime time.Time, _ string, fields hub.Fields) error {
+	m := fields["message"].(*model.Message)
+	if m.Hidden {
+		// 非表示にされたメッセージの内容はBOTに送信しない
+		return nil
+	}
+
+	ch, err := ctx.CM().GetChannel(m.ChannelID)
+	if err != nil {
+		return fmt.Errorf("failed to GetChannel: %w", err)
+	}
+	if ch.IsDMChannel() {
+		return nil
+	}
+
+	bots, err := getChannelEventBots(ctx, ch, event.MessageUpdated)
+	if err != nil {
+		return err
+	}
+	bots = filterBotUserIDNotEquals(bots, m.UserID)
+	if len(bots) == 0 {
+		return nil
+	}
+
+	user, err := ctx.R().GetUser(m.UserID, false)
+	if err != nil {
+		return fmt.Errorf("failed to GetUser: %w", err)
+	}
+
+	if err := ctx.Multicast(
+		event.MessageUpdated,
+		payload.MakeMessageUpdated(datetime, m, user, message.Parse(m.Text)),
+		bots,
+	); err != nil {
+		return fmt.Errorf("failed to multicast: %w", err)
+	}
+	return nil
+}
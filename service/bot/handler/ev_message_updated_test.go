@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+	"github.com/leandro-lugaresi/hub"
+	"github.com/stretchr/testify/assert"
+	intevent "github.com/traPtitech/traQ/event"
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/service/bot/event"
+	"github.com/traPtitech/traQ/service/bot/event/payload"
+	"github.com/traPtitech/traQ/utils/message"
+	"testing"
+	"time"
+)
+
+func TestMessageUpdated(t *testing.T) {
+	t.Parallel()
+
+	b := &model.Bot{
+		ID:              uuid.NewV3(uuid.Nil, "b"),
+		BotUserID:       uuid.NewV3(uuid.Nil, "bu"),
+		SubscribeEvents: model.BotEventTypesFromArray([]string{event.MessageUpdated.String()}),
+		State:           model.BotActive,
+	}
+	user := &model.User{
+		ID:   uuid.NewV3(uuid.Nil, "u"),
+		Name: "testman",
+	}
+	ch := &model.Channel{
+		ID:       uuid.NewV3(uuid.Nil, "c"),
+		Name:     "test",
+		IsPublic: true,
+	}
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		handlerCtx, cm, repo := setup(t, ctrl)
+		registerBot(t, handlerCtx, b)
+		registerUser(repo, user)
+		registerChannel(cm, ch)
+
+		m := &model.Message{
+			ID:        uuid.NewV3(uuid.Nil, "m"),
+			UserID:    user.ID,
+			ChannelID: ch.ID,
+			Text:      "updated message",
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		}
+		et := time.Now()
+
+		handlerCtx.EXPECT().
+			GetChannelBots(ch.ID, event.MessageUpdated).
+			Return([]*model.Bot{b}, nil).
+			AnyTimes()
+
+		expectMulticast(handlerCtx, event.MessageUpdated, payload.MakeMessageUpdated(et, m, user, message.Parse(m.Text)), []*model.Bot{b})
+		assert.NoError(t, MessageUpdated(handlerCtx, et, intevent.MessageUpdated, hub.Fields{
+			"message_id": m.ID,
+			"message":    m,
+		}))
+	})
+
+	t.Run("self", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		handlerCtx, cm, _ := setup(t, ctrl)
+		registerBot(t, handlerCtx, b)
+		registerChannel(cm, ch)
+
+		m := &model.Message{
+			ID:        uuid.NewV3(uuid.Nil, "m"),
+			UserID:    b.BotUserID,
+			ChannelID: ch.ID,
+			Text:      "updated message",
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		}
+
+		handlerCtx.EXPECT().
+			GetChannelBots(ch.ID, event.MessageUpdated).
+			Return([]*model.Bot{b}, nil).
+			AnyTimes()
+
+		assert.NoError(t, MessageUpdated(handlerCtx, time.Now(), intevent.MessageUpdated, hub.Fields{
+			"message_id": m.ID,
+			"message":    m,
+		}))
+	})
+	t.Run("hidden", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		handlerCtx, cm, repo := setup(t, ctrl)
+		registerBot(t, handlerCtx, b)
+		registerUser(repo, user)
+		registerChannel(cm, ch)
+
+		m := &model.Message{
+			ID:        uuid.NewV3(uuid.Nil, "m"),
+			UserID:    user.ID,
+			ChannelID: ch.ID,
+			Text:      "hidden message",
+			Hidden:    true,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		}
+
+		handlerCtx.EXPECT().
+			GetChannelBots(ch.ID, event.MessageUpdated).
+			Return([]*model.Bot{b}, nil).
+			AnyTimes()
+
+		assert.NoError(t, MessageUpdated(handlerCtx, time.Now(), intevent.MessageUpdated, hub.Fields{
+			"message_id": m.ID,
+			"message":    m,
+		}))
+	})
+}
@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+	"github.com/leandro-lugaresi/hub"
+	"github.com/stretchr/testify/assert"
+	intevent "github.com/traPtitech/traQ/event"
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/service/bot/event"
+	"github.com/traPtitech/traQ/service/bot/event/payload"
+	"testing"
+	"time"
+)
+
+func TestUserGroupMemberRemoved(t *testing.T) {
+	t.Parallel()
+
+	b := &model.Bot{
+		ID:              uuid.NewV3(uuid.Nil, "b"),
+		BotUserID:       uuid.NewV3(uuid.Nil, "bu"),
+		SubscribeEvents: model.BotEventTypesFromArray([]string{event.UserGroupMemberRemoved.String()}),
+		State:           model.BotActive,
+	}
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		handlerCtx, _, repo := setup(t, ctrl)
+		registerBot(t, handlerCtx, b)
+
+		user := &model.User{
+			ID:   uuid.NewV3(uuid.Nil, "u"),
+			Name: "testman",
+		}
+		registerUser(repo, user)
+		groupID := uuid.NewV3(uuid.Nil, "g")
+		et := time.Now()
+
+		expectMulticast(handlerCtx, event.UserGroupMemberRemoved, payload.MakeUserGroupMemberRemoved(et, groupID, user), []*model.Bot{b})
+		assert.NoError(t, UserGroupMemberRemoved(handlerCtx, et, intevent.UserGroupMemberRemoved, hub.Fields{
+			"group_id": groupID,
+			"user_id":  user.ID,
+		}))
+	})
+}
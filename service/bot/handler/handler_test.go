@@ -15,6 +15,8 @@ type Repo struct {
 	*mock_repository.MockTagRepository
 	*mock_repository.MockUserRepository
 	*mock_repository.MockBotRepository
+	*mock_repository.MockMessageRepository
+	*mock_repository.MockStampRepository
 	testutils.EmptyTestRepository
 }
 
@@ -23,9 +25,11 @@ func setup(t *testing.T, ctrl *gomock.Controller) (*mock_handler.MockContext, *m
 	cm := mock_channel.NewMockManager(ctrl)
 
 	repo := &Repo{
-		MockTagRepository:  mock_repository.NewMockTagRepository(ctrl),
-		MockUserRepository: mock_repository.NewMockUserRepository(ctrl),
-		MockBotRepository:  mock_repository.NewMockBotRepository(ctrl),
+		MockTagRepository:     mock_repository.NewMockTagRepository(ctrl),
+		MockUserRepository:    mock_repository.NewMockUserRepository(ctrl),
+		MockBotRepository:     mock_repository.NewMockBotRepository(ctrl),
+		MockMessageRepository: mock_repository.NewMockMessageRepository(ctrl),
+		MockStampRepository:   mock_repository.NewMockStampRepository(ctrl),
 	}
 
 	handlerCtx.EXPECT().
@@ -81,6 +85,20 @@ func registerTag(repo *Repo, t *model.Tag) {
 		AnyTimes()
 }
 
+func registerMessage(repo *Repo, m *model.Message) {
+	repo.MockMessageRepository.EXPECT().
+		GetMessageByID(m.ID).
+		Return(m, nil).
+		AnyTimes()
+}
+
+func registerStamp(repo *Repo, s *model.Stamp) {
+	repo.MockStampRepository.EXPECT().
+		GetStamp(s.ID).
+		Return(s, nil).
+		AnyTimes()
+}
+
 func expectMulticast(handlerCtx *mock_handler.MockContext, ev model.BotEventType, payload interface{}, targets []*model.Bot) {
 	handlerCtx.EXPECT().
 		Multicast(ev, payload, targets).
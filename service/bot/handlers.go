@@ -10,15 +10,30 @@ import (
 type eventHandler func(ctx handler.Context, datetime time.Time, event string, fields hub.Fields) error
 
 var eventHandlerSet = map[string]eventHandler{
-	intevent.BotJoined:           handler.BotJoined,
-	intevent.BotLeft:             handler.BotLeft,
-	intevent.BotPingRequest:      handler.BotPingRequest,
-	intevent.MessageCreated:      handler.MessageCreated,
-	intevent.MessageDeleted:      handler.MessageDeleted,
-	intevent.UserCreated:         handler.UserCreated,
-	intevent.ChannelCreated:      handler.ChannelCreated,
-	intevent.ChannelTopicUpdated: handler.ChannelTopicUpdated,
-	intevent.StampCreated:        handler.StampCreated,
-	intevent.UserTagAdded:        handler.UserTagAdded,
-	intevent.UserTagRemoved:      handler.UserTagRemoved,
+	intevent.BotJoined:                  handler.BotJoined,
+	intevent.BotLeft:                    handler.BotLeft,
+	intevent.BotPingRequest:             handler.BotPingRequest,
+	intevent.BotStateChanged:            handler.BotStateChanged,
+	intevent.BotCommandInvoked:          handler.BotCommandInvoked,
+	intevent.MessageCreated:             handler.MessageCreated,
+	intevent.MessageReplied:             handler.MessageReplied,
+	intevent.MessageUpdated:             handler.MessageUpdated,
+	intevent.MessageDeleted:             handler.MessageDeleted,
+	intevent.MessageStamped:             handler.MessageStamped,
+	intevent.MessageUnstamped:           handler.MessageUnstamped,
+	intevent.MessagePinned:              handler.MessagePinned,
+	intevent.MessageComponentInteracted: handler.MessageComponentInteracted,
+	intevent.UserCreated:                handler.UserCreated,
+	intevent.UserUpdated:                handler.UserUpdated,
+	intevent.ChannelCreated:             handler.ChannelCreated,
+	intevent.ChannelUpdated:             handler.ChannelUpdated,
+	intevent.ChannelTopicUpdated:        handler.ChannelTopicUpdated,
+	intevent.ChannelDeleted:             handler.ChannelDeleted,
+	intevent.StampCreated:               handler.StampCreated,
+	intevent.StampUpdated:               handler.StampUpdated,
+	intevent.StampDeleted:               handler.StampDeleted,
+	intevent.UserTagAdded:               handler.UserTagAdded,
+	intevent.UserTagRemoved:             handler.UserTagRemoved,
+	intevent.UserGroupMemberAdded:       handler.UserGroupMemberAdded,
+	intevent.UserGroupMemberRemoved:     handler.UserGroupMemberRemoved,
 }
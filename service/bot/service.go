@@ -1,6 +1,9 @@
 package bot
 
-import "context"
+import (
+	"context"
+	"github.com/traPtitech/traQ/model"
+)
 
 // Service BOTサービス
 type Service interface {
@@ -8,4 +11,10 @@ type Service interface {
 	Start()
 	// Shutdown BOTサービスをシャットダウンします
 	Shutdown(ctx context.Context) error
+	// TestEvent 指定したBOTにサンプルペイロードのテストイベントを送信します
+	//
+	// BOTの状態に関わらず送信し、結果をイベントログと同じ形式とレスポンスボディで返します。結果は記録されません。
+	// userはサンプルペイロードの操作者として用いられます。
+	// 未知のイベントタイプの場合、event.ErrUnknownEventTypeを返します。
+	TestEvent(b *model.Bot, ev model.BotEventType, user model.UserInfo) (log *model.BotEventLog, resBody string, err error)
 }
@@ -7,7 +7,9 @@ import (
 	"github.com/traPtitech/traQ/model"
 	"github.com/traPtitech/traQ/repository"
 	"github.com/traPtitech/traQ/service/bot/event"
+	botws "github.com/traPtitech/traQ/service/bot/ws"
 	"github.com/traPtitech/traQ/service/channel"
+	"github.com/traPtitech/traQ/service/cluster"
 	"go.uber.org/zap"
 	"sync"
 	"time"
@@ -18,6 +20,7 @@ type serviceImpl struct {
 	cm         channel.Manager
 	logger     *zap.Logger
 	dispatcher event.Dispatcher
+	queue      *event.Queue
 	hub        *hub.Hub
 
 	sub     hub.Subscription
@@ -26,14 +29,15 @@ type serviceImpl struct {
 }
 
 // NewService ボットサービスを生成します
-func NewService(repo repository.Repository, cm channel.Manager, hub *hub.Hub, logger *zap.Logger) Service {
+func NewService(repo repository.Repository, cm channel.Manager, hub *hub.Hub, ws *botws.Streamer, bus *cluster.Bus, logger *zap.Logger) Service {
 	p := &serviceImpl{
 		repo:       repo,
 		cm:         cm,
 		logger:     logger.Named("bot"),
 		hub:        hub,
-		dispatcher: event.NewDispatcher(logger, repo),
+		dispatcher: event.NewDispatcher(logger, repo, ws),
 	}
+	p.queue = event.NewQueue(logger, repo, p.dispatcher, ws, bus)
 	return p
 }
 
@@ -42,6 +46,7 @@ func (p *serviceImpl) Start() {
 		return
 	}
 	p.started = true
+	p.queue.Start()
 
 	events := make([]string, 0, len(eventHandlerSet))
 	for k := range eventHandlerSet {
@@ -73,10 +78,19 @@ func (p *serviceImpl) Shutdown(ctx context.Context) error {
 	}
 	p.hub.Unsubscribe(p.sub)
 	p.wg.Wait()
+	p.queue.Stop()
 	p.logger.Info("bot service shutdown")
 	return nil
 }
 
+func (p *serviceImpl) TestEvent(b *model.Bot, ev model.BotEventType, user model.UserInfo) (*model.BotEventLog, string, error) {
+	sent, payload, err := event.MakeSamplePayload(time.Now(), ev, b, user)
+	if err != nil {
+		return nil, "", err
+	}
+	return event.Test(p.dispatcher, sent, payload, b)
+}
+
 func (p *serviceImpl) CM() channel.Manager {
 	return p.cm
 }
@@ -94,11 +108,14 @@ func (p *serviceImpl) D() event.Dispatcher {
 }
 
 func (p *serviceImpl) Unicast(ev model.BotEventType, payload interface{}, target *model.Bot) error {
-	return event.Unicast(p.dispatcher, ev, payload, target)
+	if target == nil {
+		return nil
+	}
+	return p.queue.Enqueue(ev, payload, []*model.Bot{target})
 }
 
 func (p *serviceImpl) Multicast(ev model.BotEventType, payload interface{}, targets []*model.Bot) error {
-	return event.Multicast(p.dispatcher, ev, payload, targets)
+	return p.queue.Enqueue(ev, payload, targets)
 }
 
 func (p *serviceImpl) GetBot(id uuid.UUID) (*model.Bot, error) {
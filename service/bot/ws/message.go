@@ -0,0 +1,25 @@
+package ws
+
+import (
+	"bytes"
+	"github.com/gofrs/uuid"
+	"github.com/traPtitech/traQ/model"
+)
+
+// makeEventMessage BOTに送信するイベントメッセージを生成します
+//
+// 	{"type":"MESSAGE_CREATED","reqId":"...","body":{...}}
+//
+// bodyはHTTPモードでPOSTされるものと同じペイロードJSONです。
+func makeEventMessage(ev model.BotEventType, reqID uuid.UUID, body []byte) []byte {
+	var buf bytes.Buffer
+	buf.Grow(len(body) + 80)
+	buf.WriteString(`{"type":"`)
+	buf.WriteString(ev.String())
+	buf.WriteString(`","reqId":"`)
+	buf.WriteString(reqID.String())
+	buf.WriteString(`","body":`)
+	buf.Write(bytes.TrimSpace(body))
+	buf.WriteString("}")
+	return buf.Bytes()
+}
@@ -0,0 +1,18 @@
+package ws
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/traPtitech/traQ/model"
+	"testing"
+)
+
+func TestMakeEventMessage(t *testing.T) {
+	t.Parallel()
+
+	reqID := uuid.Must(uuid.FromString("2c8a3e2b-4a4c-4d3c-9a5e-7b2a0c1d2e3f"))
+	assert.JSONEq(t,
+		`{"type":"PING","reqId":"2c8a3e2b-4a4c-4d3c-9a5e-7b2a0c1d2e3f","body":{"eventTime":"2020-01-01T00:00:00Z"}}`,
+		string(makeEventMessage(model.BotEventType("PING"), reqID, []byte("{\"eventTime\":\"2020-01-01T00:00:00Z\"}\n"))),
+	)
+}
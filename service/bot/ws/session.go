@@ -0,0 +1,104 @@
+package ws
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/gorilla/websocket"
+	"sync"
+	"time"
+)
+
+type rawMessage struct {
+	t    int
+	data []byte
+	// done 指定されている場合、接続への書き込み結果が送られます
+	done chan error
+}
+
+type session struct {
+	botUserID uuid.UUID
+	conn      *websocket.Conn
+	open      bool
+	send      chan *rawMessage
+	sync.RWMutex
+}
+
+func (s *session) readLoop() {
+	s.conn.SetReadLimit(maxReadMessageSize)
+	_ = s.conn.SetReadDeadline(time.Now().Add(pongWait))
+	s.conn.SetPongHandler(func(string) error {
+		_ = s.conn.SetReadDeadline(time.Now().Add(pongWait))
+		return nil
+	})
+
+	for {
+		t, _, err := s.conn.ReadMessage()
+		if err != nil {
+			break
+		}
+
+		// BOTからのメッセージは現在受け付けていない
+		if t == websocket.BinaryMessage {
+			_ = s.writeMessage(&rawMessage{t: websocket.CloseMessage, data: websocket.FormatCloseMessage(websocket.CloseUnsupportedData, "binary message is not supported.")})
+			break
+		}
+	}
+}
+
+func (s *session) writeLoop() {
+	ticker := time.NewTicker(pingPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case msg, ok := <-s.send:
+			if !ok {
+				return
+			}
+
+			err := s.write(msg.t, msg.data)
+			if msg.done != nil {
+				msg.done <- err
+			}
+			if err != nil {
+				return
+			}
+
+			if msg.t == websocket.CloseMessage {
+				return
+			}
+
+		case <-ticker.C:
+			_ = s.write(websocket.PingMessage, []byte{})
+		}
+	}
+}
+
+func (s *session) writeMessage(msg *rawMessage) error {
+	s.RLock()
+	defer s.RUnlock()
+	if !s.open {
+		return ErrAlreadyClosed
+	}
+
+	select {
+	case s.send <- msg:
+	default:
+		return ErrBufferIsFull
+	}
+	return nil
+}
+
+func (s *session) write(messageType int, data []byte) error {
+	_ = s.conn.SetWriteDeadline(time.Now().Add(writeWait))
+	return s.conn.WriteMessage(messageType, data)
+}
+
+func (s *session) close() {
+	s.Lock()
+	defer s.Unlock()
+	if s.open {
+		s.open = false
+		s.conn.Close()
+		close(s.send)
+	}
+}
@@ -0,0 +1,160 @@
+package ws
+
+import (
+	"errors"
+	"github.com/gofrs/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/router/extension"
+	"go.uber.org/zap"
+	"net/http"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrAlreadyClosed 既に閉じられています
+	ErrAlreadyClosed = errors.New("already closed")
+	// ErrBufferIsFull 送信バッファが溢れました
+	ErrBufferIsFull = errors.New("buffer is full")
+	// ErrNotConnected BOTが接続していません
+	ErrNotConnected = errors.New("bot is not connected")
+	// ErrWriteTimeout 接続への書き込みが時間内に完了しませんでした
+	ErrWriteTimeout = errors.New("write timeout")
+
+	wsConnectionCounter = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "traq",
+		Name:      "bot_ws_connections",
+	})
+)
+
+// Streamer WebSocketモードのBOTへのイベントストリーマー
+//
+// BOTユーザー毎に1つの接続のみを保持し、新しい接続があった場合は古い接続を閉じます。
+type Streamer struct {
+	logger   *zap.Logger
+	sessions map[uuid.UUID]*session
+	open     bool
+	mu       sync.RWMutex
+}
+
+// NewStreamer Streamerを生成します
+func NewStreamer(logger *zap.Logger) *Streamer {
+	return &Streamer{
+		logger:   logger.Named("bot.ws"),
+		sessions: map[uuid.UUID]*session{},
+		open:     true,
+	}
+}
+
+// WriteMessage 指定したBOTユーザーの接続にイベントを書き込みます
+//
+// 送信バッファに積むだけでなく、接続への書き込みが完了するまで待機します。
+// BOTが接続していない場合、ErrNotConnectedを返します。
+// 書き込みが時間内に完了しなかった場合、ErrWriteTimeoutを返します。
+func (s *Streamer) WriteMessage(botUserID uuid.UUID, ev model.BotEventType, reqID uuid.UUID, body []byte) error {
+	s.mu.RLock()
+	session, ok := s.sessions[botUserID]
+	s.mu.RUnlock()
+	if !ok {
+		return ErrNotConnected
+	}
+
+	done := make(chan error, 1)
+	if err := session.writeMessage(&rawMessage{
+		t:    websocket.TextMessage,
+		data: makeEventMessage(ev, reqID, body),
+		done: done,
+	}); err != nil {
+		return err
+	}
+
+	t := time.NewTimer(writeWait)
+	defer t.Stop()
+	select {
+	case err := <-done:
+		return err
+	case <-t.C:
+		// 書き込み前に接続が閉じられた場合もここに来る
+		return ErrWriteTimeout
+	}
+}
+
+// IsConnected 指定したBOTユーザーが接続しているかどうか
+func (s *Streamer) IsConnected(botUserID uuid.UUID) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.sessions[botUserID]
+	return ok
+}
+
+// ServeHTTP http.Handlerインターフェイスの実装
+//
+// リクエストのコンテキストにBOTユーザーのIDが設定されている必要があります。
+func (s *Streamer) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	if s.IsClosed() {
+		http.Error(rw, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+		return
+	}
+
+	conn, err := upgrader.Upgrade(rw, r, rw.Header())
+	if err != nil {
+		return
+	}
+
+	session := &session{
+		botUserID: r.Context().Value(extension.CtxUserIDKey).(uuid.UUID),
+		conn:      conn,
+		open:      true,
+		send:      make(chan *rawMessage, messageBufferSize),
+	}
+
+	s.mu.Lock()
+	if old, ok := s.sessions[session.botUserID]; ok {
+		_ = old.writeMessage(&rawMessage{t: websocket.CloseMessage, data: websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "another connection has been established.")})
+	}
+	s.sessions[session.botUserID] = session
+	s.mu.Unlock()
+	wsConnectionCounter.Inc()
+	s.logger.Info("bot connected", zap.Stringer("botUserId", session.botUserID))
+
+	go session.writeLoop()
+	session.readLoop()
+
+	s.mu.Lock()
+	if s.sessions[session.botUserID] == session {
+		delete(s.sessions, session.botUserID)
+	}
+	s.mu.Unlock()
+	wsConnectionCounter.Dec()
+	session.close()
+	s.logger.Info("bot disconnected", zap.Stringer("botUserId", session.botUserID))
+}
+
+// IsClosed ストリーマーが停止しているかどうか
+func (s *Streamer) IsClosed() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return !s.open
+}
+
+// Close ストリーマーを停止します
+func (s *Streamer) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.open {
+		return ErrAlreadyClosed
+	}
+	m := &rawMessage{
+		t:    websocket.CloseMessage,
+		data: websocket.FormatCloseMessage(websocket.CloseServiceRestart, "Server is stopping..."),
+	}
+	for id, session := range s.sessions {
+		_ = session.writeMessage(m)
+		delete(s.sessions, id)
+	}
+	s.open = false
+	return nil
+}
@@ -0,0 +1,46 @@
+package ws
+
+import (
+	"context"
+	"github.com/gofrs/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/router/extension"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStreamer_WriteMessage(t *testing.T) {
+	t.Parallel()
+
+	botUserID := uuid.NewV3(uuid.Nil, "bu")
+	reqID := uuid.NewV3(uuid.Nil, "r")
+	s := NewStreamer(zap.NewNop())
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		s.ServeHTTP(rw, r.WithContext(context.WithValue(r.Context(), extension.CtxUserIDKey, botUserID)))
+	}))
+	defer server.Close()
+	defer s.Close()
+
+	assert.EqualError(t, s.WriteMessage(botUserID, model.BotEventType("PING"), reqID, []byte(`{}`)), ErrNotConnected.Error())
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	require.Eventually(t, func() bool { return s.IsConnected(botUserID) }, time.Second, 10*time.Millisecond)
+
+	// 接続への書き込みが完了してから返る
+	if assert.NoError(t, s.WriteMessage(botUserID, model.BotEventType("PING"), reqID, []byte(`{}`))) {
+		_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+		_, b, err := conn.ReadMessage()
+		if assert.NoError(t, err) {
+			assert.Equal(t, string(makeEventMessage(model.BotEventType("PING"), reqID, []byte(`{}`))), string(b))
+		}
+	}
+}
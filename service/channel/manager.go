@@ -6,6 +6,7 @@ import (
 	"github.com/gofrs/uuid"
 	"github.com/traPtitech/traQ/model"
 	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/utils/set"
 )
 
 var (
@@ -17,6 +18,9 @@ var (
 	ErrChannelArchived      = errors.New("channel archived")
 	ErrForcedNotification   = errors.New("forced notification channel")
 	ErrInvalidChannel       = errors.New("invalid channel")
+	ErrAlreadyMember        = errors.New("already a member of the channel")
+	ErrNotMember            = errors.New("not a member of the channel")
+	ErrChannelNotEmpty      = errors.New("channel is not empty")
 )
 
 type Manager interface {
@@ -24,6 +28,8 @@ type Manager interface {
 	CreatePublicChannel(name string, parent, creatorID uuid.UUID) (*model.Channel, error)
 	UpdateChannel(id uuid.UUID, args repository.UpdateChannelArgs) error
 	PublicChannelTree() Tree
+	MergeChannel(fromID, toID, operatorID uuid.UUID) error
+	DeleteChannel(id, operatorID uuid.UUID) error
 
 	ChangeChannelSubscriptions(channelID uuid.UUID, subscriptions map[uuid.UUID]model.ChannelSubscribeLevel, keepOffLevel bool, updaterID uuid.UUID) error
 
@@ -31,6 +37,12 @@ type Manager interface {
 	GetDMChannelMembers(id uuid.UUID) ([]uuid.UUID, error)
 	GetDMChannelMapping(userID uuid.UUID) (map[uuid.UUID]uuid.UUID, error)
 
+	CreatePrivateChannel(name string, creatorID uuid.UUID, members set.UUID) (*model.Channel, error)
+	GetPrivateChannelMembers(id uuid.UUID) ([]uuid.UUID, error)
+	GetUserPrivateChannels(userID uuid.UUID) ([]*model.Channel, error)
+	AddPrivateChannelMember(channelID, userID, operatorID uuid.UUID) error
+	RemovePrivateChannelMember(channelID, userID, operatorID uuid.UUID) error
+
 	IsChannelAccessibleToUser(userID, channelID uuid.UUID) (bool, error)
 	IsPublicChannel(id uuid.UUID) bool
 
@@ -5,6 +5,8 @@ import (
 	"github.com/gofrs/uuid"
 	"github.com/traPtitech/traQ/model"
 	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/service/cluster"
+	"github.com/traPtitech/traQ/utils/optional"
 	"github.com/traPtitech/traQ/utils/random"
 	"github.com/traPtitech/traQ/utils/set"
 	"github.com/traPtitech/traQ/utils/validator"
@@ -13,9 +15,13 @@ import (
 	"time"
 )
 
+// clusterTopic 公開チャンネルツリーの変更を通知するトピック
+const clusterTopic = "channel.tree.changed"
+
 var (
-	dmChannelRootUUID  = uuid.Must(uuid.FromString(model.DirectMessageChannelRootID))
-	pubChannelRootUUID = uuid.Nil
+	dmChannelRootUUID      = uuid.Must(uuid.FromString(model.DirectMessageChannelRootID))
+	privateChannelRootUUID = uuid.Must(uuid.FromString(model.PrivateChannelRootID))
+	pubChannelRootUUID     = uuid.Nil
 )
 
 type managerImpl struct {
@@ -23,11 +29,12 @@ type managerImpl struct {
 	L *zap.Logger
 	T *treeImpl
 	P sync.WaitGroup
+	B *cluster.Bus
 
 	MaxChannelDepth int
 }
 
-func InitChannelManager(repo repository.ChannelRepository, logger *zap.Logger) (Manager, error) {
+func InitChannelManager(repo repository.ChannelRepository, bus *cluster.Bus, logger *zap.Logger) (Manager, error) {
 	channels, err := repo.GetPublicChannels()
 	if err != nil {
 		return nil, fmt.Errorf("failed to init channel.Manager: %w", err)
@@ -36,6 +43,7 @@ func InitChannelManager(repo repository.ChannelRepository, logger *zap.Logger) (
 	m := &managerImpl{
 		R:               repo,
 		L:               logger.Named("channel_manager"),
+		B:               bus,
 		MaxChannelDepth: 5,
 	}
 	m.T, err = makeChannelTree(channels)
@@ -43,6 +51,14 @@ func InitChannelManager(repo repository.ChannelRepository, logger *zap.Logger) (
 		return nil, fmt.Errorf("failed to init channel.Manager: %w", err)
 	}
 
+	// 他ノードで公開チャンネルツリーが変更された場合は再構築する
+	bus.Subscribe(clusterTopic, func(string, []byte) {
+		m.T.Lock()
+		defer m.T.Unlock()
+		if err := m.reloadTree(); err != nil {
+			m.L.Error("failed to reload public channel tree", zap.Error(err))
+		}
+	})
 	return m, nil
 }
 
@@ -105,6 +121,7 @@ func (m *managerImpl) CreatePublicChannel(name string, parent, creatorID uuid.UU
 		return nil, fmt.Errorf("failed to CreateChannel: %w", err)
 	}
 	m.T.add(ch)
+	m.B.Publish(clusterTopic, nil)
 	if parent != pubChannelRootUUID {
 		// ロギング
 		m.recordChannelEvent(ch.ParentID, model.ChannelEventChildCreated, model.ChannelEventDetail{
@@ -125,6 +142,11 @@ func (m *managerImpl) UpdateChannel(id uuid.UUID, args repository.UpdateChannelA
 	m.T.Lock()
 	defer m.T.Unlock()
 
+	public := m.T.isChannelPresent(id)
+	if !public && (args.Parent.Valid || args.ForcedNotification.Valid) {
+		return ErrInvalidChannel // 公開チャンネル以外は親チャンネル・強制通知を変更できない
+	}
+
 	eventRecords := map[model.ChannelEventType]model.ChannelEventDetail{}
 	if args.Topic.Valid && ch.Topic != args.Topic.String {
 		eventRecords[model.ChannelEventTopicChanged] = model.ChannelEventDetail{
@@ -167,6 +189,11 @@ func (m *managerImpl) UpdateChannel(id uuid.UUID, args repository.UpdateChannelA
 			if m.T.isChildPresent(n, p) {
 				return ErrChannelNameConflicts
 			}
+			if !public && args.Name.Valid {
+				if err := m.checkPrivateChannelNameConflicts(n, args.UpdaterID, id); err != nil {
+					return err
+				}
+			}
 		}
 
 		if args.Name.Valid {
@@ -208,13 +235,19 @@ func (m *managerImpl) UpdateChannel(id uuid.UUID, args repository.UpdateChannelA
 
 	ch, err = m.R.UpdateChannel(id, args)
 	if err != nil {
+		if err == repository.ErrAlreadyExists {
+			return ErrChannelNameConflicts
+		}
 		return fmt.Errorf("failed to UpdateChannel: %w", err)
 	}
 
-	if args.Name.Valid || args.Parent.Valid {
-		m.T.move(id, args.Parent, args.Name)
+	if public {
+		if args.Name.Valid || args.Parent.Valid {
+			m.T.move(id, args.Parent, args.Name)
+		}
+		m.T.update(id, ch)
+		m.B.Publish(clusterTopic, nil)
 	}
-	m.T.update(id, ch)
 
 	updated := time.Now()
 	for eventType, detail := range eventRecords {
@@ -227,6 +260,95 @@ func (m *managerImpl) PublicChannelTree() Tree {
 	return m.T
 }
 
+func (m *managerImpl) MergeChannel(fromID, toID, operatorID uuid.UUID) error {
+	m.T.Lock()
+	defer m.T.Unlock()
+
+	if !m.T.isChannelPresent(fromID) || !m.T.isChannelPresent(toID) {
+		return ErrChannelNotFound
+	}
+	if fromID == toID {
+		return ErrInvalidChannel
+	}
+	for _, id := range m.T.getAscendantIDs(toID) {
+		if id == fromID {
+			return ErrInvalidChannel // 子孫チャンネルには統合できない
+		}
+	}
+
+	// 子チャンネルの移動先を検証
+	children := m.T.getChildrenIDs(fromID)
+	toDepth := len(m.T.getAscendantIDs(toID)) + 1
+	for _, cid := range children {
+		if m.T.isChildPresent(m.T.nodes[cid].name, toID) {
+			return ErrChannelNameConflicts
+		}
+		if toDepth+m.T.getChannelDepth(cid) > m.MaxChannelDepth {
+			return ErrTooDeepChannel
+		}
+	}
+
+	fromPath := m.T.getChannelPath(fromID)
+	if err := m.R.MergeChannel(fromID, toID); err != nil {
+		return fmt.Errorf("failed to MergeChannel: %w", err)
+	}
+
+	for _, cid := range children {
+		m.T.move(cid, optional.UUIDFrom(toID), optional.String{})
+	}
+	m.T.remove(fromID)
+	m.B.Publish(clusterTopic, nil)
+
+	now := time.Now()
+	for _, cid := range children {
+		m.recordChannelEvent(cid, model.ChannelEventParentChanged, model.ChannelEventDetail{
+			"userId": operatorID,
+			"before": fromID,
+			"after":  toID,
+		}, now)
+	}
+	m.recordChannelEvent(toID, model.ChannelEventMerged, model.ChannelEventDetail{
+		"userId":    operatorID,
+		"channelId": fromID,
+		"name":      fromPath,
+	}, now)
+	m.L.Info(fmt.Sprintf("channel #%s was merged into #%s", fromPath, m.T.getChannelPath(toID)), zap.Stringer("cid", fromID), zap.Stringer("to", toID))
+	return nil
+}
+
+func (m *managerImpl) DeleteChannel(id, operatorID uuid.UUID) error {
+	m.T.Lock()
+	defer m.T.Unlock()
+
+	if !m.T.isChannelPresent(id) {
+		return ErrChannelNotFound
+	}
+	if len(m.T.getChildrenIDs(id)) > 0 {
+		return ErrChannelNotEmpty
+	}
+
+	n := m.T.nodes[id]
+	path := m.T.getChannelPath(id)
+	if err := m.R.DeleteChannel(id); err != nil {
+		if err == repository.ErrForbidden {
+			return ErrChannelNotEmpty
+		}
+		return fmt.Errorf("failed to DeleteChannel: %w", err)
+	}
+	m.T.remove(id)
+	m.B.Publish(clusterTopic, nil)
+
+	if n.parent != nil {
+		m.recordChannelEvent(n.parent.id, model.ChannelEventChildDeleted, model.ChannelEventDetail{
+			"userId":    operatorID,
+			"channelId": id,
+			"name":      n.name,
+		}, time.Now())
+	}
+	m.L.Info(fmt.Sprintf("channel #%s was deleted", path), zap.Stringer("cid", id))
+	return nil
+}
+
 func (m *managerImpl) ChangeChannelSubscriptions(channelID uuid.UUID, subscriptions map[uuid.UUID]model.ChannelSubscribeLevel, keepOffLevel bool, updaterID uuid.UUID) error {
 	if !m.IsPublicChannel(channelID) {
 		return ErrInvalidChannel
@@ -305,12 +427,127 @@ func (m *managerImpl) GetDMChannelMapping(userID uuid.UUID) (map[uuid.UUID]uuid.
 	return result, nil
 }
 
+func (m *managerImpl) CreatePrivateChannel(name string, creatorID uuid.UUID, members set.UUID) (*model.Channel, error) {
+	// チャンネル名の制約を確認
+	if !validator.ChannelRegex.MatchString(name) {
+		return nil, ErrInvalidChannelName
+	}
+
+	// 作成者が参加しているプライベートチャンネルとの名前の重複を確認
+	if err := m.checkPrivateChannelNameConflicts(name, creatorID, uuid.Nil); err != nil {
+		return nil, err
+	}
+
+	members = members.Clone()
+	members.Add(creatorID)
+
+	ch, err := m.R.CreateChannel(model.Channel{
+		Name:      name,
+		ParentID:  privateChannelRootUUID,
+		CreatorID: creatorID,
+		UpdaterID: creatorID,
+		IsVisible: true,
+	}, members, false)
+	if err != nil {
+		if err == repository.ErrAlreadyExists {
+			return nil, ErrChannelNameConflicts
+		}
+		return nil, fmt.Errorf("failed to CreateChannel: %w", err)
+	}
+	ch.ChildrenID = make([]uuid.UUID, 0)
+	m.L.Info(fmt.Sprintf("private channel %s was created", ch.Name), zap.Stringer("cid", ch.ID))
+	return ch, nil
+}
+
+// checkPrivateChannelNameConflicts userIDが参加している他のプライベートチャンネルに同名のチャンネルが無いか確認します
+//
+// プライベートチャンネル名はDB上では一意ではないため、本人が参加しているチャンネルとの重複のみを検出します。
+func (m *managerImpl) checkPrivateChannelNameConflicts(name string, userID, exceptID uuid.UUID) error {
+	channels, err := m.R.GetPrivateChannelsByUser(userID)
+	if err != nil {
+		return fmt.Errorf("failed to GetPrivateChannelsByUser: %w", err)
+	}
+	for _, ch := range channels {
+		if ch.ID != exceptID && ch.Name == name {
+			return ErrChannelNameConflicts
+		}
+	}
+	return nil
+}
+
+func (m *managerImpl) GetPrivateChannelMembers(id uuid.UUID) ([]uuid.UUID, error) {
+	members, err := m.R.GetPrivateChannelMemberIDs(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to GetPrivateChannelMembers: %w", err)
+	}
+	return members, nil
+}
+
+func (m *managerImpl) GetUserPrivateChannels(userID uuid.UUID) ([]*model.Channel, error) {
+	channels, err := m.R.GetPrivateChannelsByUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to GetUserPrivateChannels: %w", err)
+	}
+	for _, ch := range channels {
+		ch.ChildrenID = make([]uuid.UUID, 0)
+	}
+	return channels, nil
+}
+
+func (m *managerImpl) AddPrivateChannelMember(channelID, userID, operatorID uuid.UUID) error {
+	if err := m.checkPrivateChannel(channelID); err != nil {
+		return err
+	}
+
+	if err := m.R.AddPrivateChannelMember(channelID, userID); err != nil {
+		if err == repository.ErrAlreadyExists {
+			return ErrAlreadyMember
+		}
+		return fmt.Errorf("failed to AddPrivateChannelMember: %w", err)
+	}
+	m.recordChannelEvent(channelID, model.ChannelEventMemberAdded, model.ChannelEventDetail{
+		"userId":   operatorID,
+		"memberId": userID,
+	}, time.Now())
+	return nil
+}
+
+func (m *managerImpl) RemovePrivateChannelMember(channelID, userID, operatorID uuid.UUID) error {
+	if err := m.checkPrivateChannel(channelID); err != nil {
+		return err
+	}
+
+	if err := m.R.RemovePrivateChannelMember(channelID, userID); err != nil {
+		if err == repository.ErrNotFound {
+			return ErrNotMember
+		}
+		return fmt.Errorf("failed to RemovePrivateChannelMember: %w", err)
+	}
+	m.recordChannelEvent(channelID, model.ChannelEventMemberRemoved, model.ChannelEventDetail{
+		"userId":   operatorID,
+		"memberId": userID,
+	}, time.Now())
+	return nil
+}
+
+// checkPrivateChannel 指定したチャンネルがDM以外のプライベートチャンネルかどうかを確認します
+func (m *managerImpl) checkPrivateChannel(id uuid.UUID) error {
+	ch, err := m.GetChannel(id)
+	if err != nil {
+		return err
+	}
+	if !ch.IsPrivateChannel() {
+		return ErrInvalidChannel
+	}
+	return nil
+}
+
 func (m *managerImpl) IsChannelAccessibleToUser(userID, channelID uuid.UUID) (bool, error) {
 	if m.T.IsChannelPresent(channelID) {
 		return true, nil // 公開チャンネルは全員アクセス可能
 	}
 
-	// DMチャンネル
+	// DM・プライベートチャンネル
 	members, err := m.R.GetPrivateChannelMemberIDs(channelID)
 	if err != nil {
 		return false, fmt.Errorf("failed to IsChannelAccessibleToUser: %w", err)
@@ -327,6 +564,20 @@ func (m *managerImpl) IsPublicChannel(id uuid.UUID) bool {
 	return m.T.IsChannelPresent(id)
 }
 
+// reloadTree 公開チャンネルツリーをデータベースから再構築します。m.Tのロックが必要です
+func (m *managerImpl) reloadTree() error {
+	channels, err := m.R.GetPublicChannels()
+	if err != nil {
+		return err
+	}
+	tree, err := makeChannelTree(channels)
+	if err != nil {
+		return err
+	}
+	m.T.replace(tree)
+	return nil
+}
+
 func (m *managerImpl) Wait() {
 	m.P.Wait()
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/traPtitech/traQ/model"
 	"github.com/traPtitech/traQ/repository"
 	"github.com/traPtitech/traQ/repository/mock_repository"
+	"github.com/traPtitech/traQ/service/cluster"
 	"github.com/traPtitech/traQ/utils/optional"
 	"github.com/traPtitech/traQ/utils/random"
 	"github.com/traPtitech/traQ/utils/set"
@@ -23,6 +24,7 @@ func initCM(t *testing.T, repo repository.ChannelRepository) *managerImpl {
 		R:               repo,
 		L:               zap.NewNop(),
 		T:               makeTestChannelTree(t),
+		B:               cluster.NewStandaloneBus(),
 		MaxChannelDepth: 5,
 	}
 }
@@ -41,7 +43,7 @@ func TestInitChannelManager(t *testing.T) {
 			Return(nil, mockErr).
 			Times(1)
 
-		_, err := InitChannelManager(repo, zap.NewNop())
+		_, err := InitChannelManager(repo, cluster.NewStandaloneBus(), zap.NewNop())
 		if assert.Error(t, err) {
 			assert.Equal(t, mockErr, errors.Unwrap(err))
 		}
@@ -61,7 +63,7 @@ func TestInitChannelManager(t *testing.T) {
 			}, nil).
 			Times(1)
 
-		_, err := InitChannelManager(repo, zap.NewNop())
+		_, err := InitChannelManager(repo, cluster.NewStandaloneBus(), zap.NewNop())
 		assert.Error(t, err)
 	})
 
@@ -75,7 +77,7 @@ func TestInitChannelManager(t *testing.T) {
 			Return([]*model.Channel{}, nil).
 			Times(1)
 
-		m, err := InitChannelManager(repo, zap.NewNop())
+		m, err := InitChannelManager(repo, cluster.NewStandaloneBus(), zap.NewNop())
 		if assert.NoError(t, err) {
 			assert.NotNil(t, m)
 		}
@@ -884,6 +886,304 @@ func TestManagerImpl_GetDMChannelMapping(t *testing.T) {
 	})
 }
 
+func TestManagerImpl_CreatePrivateChannel(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ErrInvalidChannelName", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockChannelRepository(ctrl)
+		cm := initCM(t, repo)
+
+		_, err := cm.CreatePrivateChannel("ああああ", cA, set.UUID{})
+		assert.EqualError(t, err, ErrInvalidChannelName.Error())
+	})
+
+	t.Run("ErrChannelNameConflicts", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockChannelRepository(ctrl)
+		cm := initCM(t, repo)
+
+		// 作成者が参加しているプライベートチャンネルとのみ重複を確認する
+		repo.EXPECT().
+			GetPrivateChannelsByUser(cA).
+			Return([]*model.Channel{{ID: uuid.Must(uuid.NewV4()), Name: "private", ParentID: privateChannelRootUUID}}, nil).
+			Times(1)
+
+		_, err := cm.CreatePrivateChannel("private", cA, set.UUID{})
+		assert.EqualError(t, err, ErrChannelNameConflicts.Error())
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockChannelRepository(ctrl)
+		cm := initCM(t, repo)
+
+		creator := uuid.Must(uuid.NewV4())
+		member := uuid.Must(uuid.NewV4())
+		members := set.UUIDSetFromArray([]uuid.UUID{member})
+		expected := &model.Channel{
+			ID:        uuid.Must(uuid.NewV4()),
+			Name:      "private",
+			ParentID:  privateChannelRootUUID,
+			CreatorID: creator,
+			UpdaterID: creator,
+			IsVisible: true,
+		}
+
+		repo.EXPECT().
+			GetPrivateChannelsByUser(creator).
+			Return([]*model.Channel{{ID: uuid.Must(uuid.NewV4()), Name: "other", ParentID: privateChannelRootUUID}}, nil).
+			Times(1)
+		repo.EXPECT().
+			CreateChannel(model.Channel{
+				Name:      "private",
+				ParentID:  privateChannelRootUUID,
+				CreatorID: creator,
+				UpdaterID: creator,
+				IsVisible: true,
+			}, set.UUIDSetFromArray([]uuid.UUID{creator, member}), false).
+			Return(expected, nil).
+			Times(1)
+
+		ch, err := cm.CreatePrivateChannel("private", creator, members)
+		if assert.NoError(t, err) {
+			assert.Equal(t, expected, ch)
+			assert.False(t, cm.PublicChannelTree().IsChannelPresent(ch.ID))
+			assert.Len(t, members, 1) // 引数は変更されない
+		}
+	})
+}
+
+func TestManagerImpl_AddPrivateChannelMember(t *testing.T) {
+	t.Parallel()
+
+	private := &model.Channel{
+		ID:        uuid.Must(uuid.NewV4()),
+		Name:      "private",
+		ParentID:  privateChannelRootUUID,
+		IsVisible: true,
+	}
+
+	t.Run("ErrInvalidChannel", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockChannelRepository(ctrl)
+		cm := initCM(t, repo)
+
+		err := cm.AddPrivateChannelMember(cA, uuid.Must(uuid.NewV4()), uuid.Nil)
+		assert.EqualError(t, err, ErrInvalidChannel.Error())
+	})
+
+	t.Run("ErrAlreadyMember", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockChannelRepository(ctrl)
+		cm := initCM(t, repo)
+
+		user := uuid.Must(uuid.NewV4())
+		repo.EXPECT().GetChannel(private.ID).Return(private, nil).Times(1)
+		repo.EXPECT().AddPrivateChannelMember(private.ID, user).Return(repository.ErrAlreadyExists).Times(1)
+
+		err := cm.AddPrivateChannelMember(private.ID, user, uuid.Nil)
+		assert.EqualError(t, err, ErrAlreadyMember.Error())
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockChannelRepository(ctrl)
+		cm := initCM(t, repo)
+
+		user := uuid.Must(uuid.NewV4())
+		operator := uuid.Must(uuid.NewV4())
+		repo.EXPECT().GetChannel(private.ID).Return(private, nil).Times(1)
+		repo.EXPECT().AddPrivateChannelMember(private.ID, user).Return(nil).Times(1)
+		repo.EXPECT().
+			RecordChannelEvent(private.ID, model.ChannelEventMemberAdded, model.ChannelEventDetail{
+				"userId":   operator,
+				"memberId": user,
+			}, gomock.Any()).
+			Return(nil).
+			Times(1)
+
+		assert.NoError(t, cm.AddPrivateChannelMember(private.ID, user, operator))
+		cm.P.Wait()
+	})
+}
+
+func TestManagerImpl_RemovePrivateChannelMember(t *testing.T) {
+	t.Parallel()
+
+	private := &model.Channel{
+		ID:        uuid.Must(uuid.NewV4()),
+		Name:      "private",
+		ParentID:  privateChannelRootUUID,
+		IsVisible: true,
+	}
+
+	t.Run("ErrNotMember", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockChannelRepository(ctrl)
+		cm := initCM(t, repo)
+
+		user := uuid.Must(uuid.NewV4())
+		repo.EXPECT().GetChannel(private.ID).Return(private, nil).Times(1)
+		repo.EXPECT().RemovePrivateChannelMember(private.ID, user).Return(repository.ErrNotFound).Times(1)
+
+		err := cm.RemovePrivateChannelMember(private.ID, user, user)
+		assert.EqualError(t, err, ErrNotMember.Error())
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockChannelRepository(ctrl)
+		cm := initCM(t, repo)
+
+		user := uuid.Must(uuid.NewV4())
+		repo.EXPECT().GetChannel(private.ID).Return(private, nil).Times(1)
+		repo.EXPECT().RemovePrivateChannelMember(private.ID, user).Return(nil).Times(1)
+		repo.EXPECT().
+			RecordChannelEvent(private.ID, model.ChannelEventMemberRemoved, model.ChannelEventDetail{
+				"userId":   user,
+				"memberId": user,
+			}, gomock.Any()).
+			Return(nil).
+			Times(1)
+
+		assert.NoError(t, cm.RemovePrivateChannelMember(private.ID, user, user))
+		cm.P.Wait()
+	})
+}
+
+func TestManagerImpl_MergeChannel(t *testing.T) {
+	t.Parallel()
+
+	operator := uuid.Must(uuid.NewV4())
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockChannelRepository(ctrl)
+		cm := initCM(t, repo)
+
+		assert.EqualError(t, cm.MergeChannel(cA, cNotFound, operator), ErrChannelNotFound.Error())
+	})
+
+	t.Run("into descendant", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockChannelRepository(ctrl)
+		cm := initCM(t, repo)
+
+		assert.EqualError(t, cm.MergeChannel(cA, cABC, operator), ErrInvalidChannel.Error())
+		assert.EqualError(t, cm.MergeChannel(cA, cA, operator), ErrInvalidChannel.Error())
+	})
+
+	t.Run("child name conflicts", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockChannelRepository(ctrl)
+		cm := initCM(t, repo)
+
+		assert.EqualError(t, cm.MergeChannel(cABB, cAB, operator), ErrChannelNameConflicts.Error())
+	})
+
+	t.Run("too deep", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockChannelRepository(ctrl)
+		cm := initCM(t, repo)
+
+		assert.EqualError(t, cm.MergeChannel(cEF, cABCD, operator), ErrTooDeepChannel.Error())
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockChannelRepository(ctrl)
+		cm := initCM(t, repo)
+
+		repo.EXPECT().MergeChannel(cABF, cAD).Return(nil).Times(1)
+		repo.EXPECT().
+			RecordChannelEvent(cABFA, model.ChannelEventParentChanged, model.ChannelEventDetail{
+				"userId": operator,
+				"before": cABF,
+				"after":  cAD,
+			}, gomock.Any()).
+			Return(nil).
+			Times(1)
+		repo.EXPECT().
+			RecordChannelEvent(cAD, model.ChannelEventMerged, model.ChannelEventDetail{
+				"userId":    operator,
+				"channelId": cABF,
+				"name":      "a/b/f",
+			}, gomock.Any()).
+			Return(nil).
+			Times(1)
+
+		if assert.NoError(t, cm.MergeChannel(cABF, cAD, operator)) {
+			assert.False(t, cm.T.IsChannelPresent(cABF))
+			assert.ElementsMatch(t, cm.T.GetChildrenIDs(cAD), []uuid.UUID{cABFA})
+			assert.Equal(t, "a/d/a", cm.T.GetChannelPath(cABFA))
+		}
+		cm.P.Wait()
+	})
+}
+
+func TestManagerImpl_DeleteChannel(t *testing.T) {
+	t.Parallel()
+
+	operator := uuid.Must(uuid.NewV4())
+
+	t.Run("has children", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockChannelRepository(ctrl)
+		cm := initCM(t, repo)
+
+		assert.EqualError(t, cm.DeleteChannel(cAB, operator), ErrChannelNotEmpty.Error())
+	})
+
+	t.Run("has messages", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockChannelRepository(ctrl)
+		cm := initCM(t, repo)
+
+		repo.EXPECT().DeleteChannel(cABCD).Return(repository.ErrForbidden).Times(1)
+
+		assert.EqualError(t, cm.DeleteChannel(cABCD, operator), ErrChannelNotEmpty.Error())
+		assert.True(t, cm.T.IsChannelPresent(cABCD))
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockChannelRepository(ctrl)
+		cm := initCM(t, repo)
+
+		repo.EXPECT().DeleteChannel(cABCD).Return(nil).Times(1)
+		repo.EXPECT().
+			RecordChannelEvent(cABC, model.ChannelEventChildDeleted, model.ChannelEventDetail{
+				"userId":    operator,
+				"channelId": cABCD,
+				"name":      "d",
+			}, gomock.Any()).
+			Return(nil).
+			Times(1)
+
+		if assert.NoError(t, cm.DeleteChannel(cABCD, operator)) {
+			assert.False(t, cm.T.IsChannelPresent(cABCD))
+		}
+		cm.P.Wait()
+	})
+}
+
 func TestManagerImpl_IsChannelAccessibleToUser(t *testing.T) {
 	t.Parallel()
 
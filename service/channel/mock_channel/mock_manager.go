@@ -10,6 +10,7 @@ import (
 	model "github.com/traPtitech/traQ/model"
 	repository "github.com/traPtitech/traQ/repository"
 	channel "github.com/traPtitech/traQ/service/channel"
+	set "github.com/traPtitech/traQ/utils/set"
 	reflect "reflect"
 )
 
@@ -94,6 +95,34 @@ func (mr *MockManagerMockRecorder) PublicChannelTree() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublicChannelTree", reflect.TypeOf((*MockManager)(nil).PublicChannelTree))
 }
 
+// MergeChannel mocks base method
+func (m *MockManager) MergeChannel(fromID, toID, operatorID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeChannel", fromID, toID, operatorID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MergeChannel indicates an expected call of MergeChannel
+func (mr *MockManagerMockRecorder) MergeChannel(fromID, toID, operatorID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeChannel", reflect.TypeOf((*MockManager)(nil).MergeChannel), fromID, toID, operatorID)
+}
+
+// DeleteChannel mocks base method
+func (m *MockManager) DeleteChannel(id, operatorID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteChannel", id, operatorID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteChannel indicates an expected call of DeleteChannel
+func (mr *MockManagerMockRecorder) DeleteChannel(id, operatorID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteChannel", reflect.TypeOf((*MockManager)(nil).DeleteChannel), id, operatorID)
+}
+
 // ChangeChannelSubscriptions mocks base method
 func (m *MockManager) ChangeChannelSubscriptions(channelID uuid.UUID, subscriptions map[uuid.UUID]model.ChannelSubscribeLevel, keepOffLevel bool, updaterID uuid.UUID) error {
 	m.ctrl.T.Helper()
@@ -153,6 +182,79 @@ func (mr *MockManagerMockRecorder) GetDMChannelMapping(userID interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDMChannelMapping", reflect.TypeOf((*MockManager)(nil).GetDMChannelMapping), userID)
 }
 
+// CreatePrivateChannel mocks base method
+func (m *MockManager) CreatePrivateChannel(name string, creatorID uuid.UUID, members set.UUID) (*model.Channel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePrivateChannel", name, creatorID, members)
+	ret0, _ := ret[0].(*model.Channel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePrivateChannel indicates an expected call of CreatePrivateChannel
+func (mr *MockManagerMockRecorder) CreatePrivateChannel(name, creatorID, members interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePrivateChannel", reflect.TypeOf((*MockManager)(nil).CreatePrivateChannel), name, creatorID, members)
+}
+
+// GetPrivateChannelMembers mocks base method
+func (m *MockManager) GetPrivateChannelMembers(id uuid.UUID) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPrivateChannelMembers", id)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPrivateChannelMembers indicates an expected call of GetPrivateChannelMembers
+func (mr *MockManagerMockRecorder) GetPrivateChannelMembers(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPrivateChannelMembers", reflect.TypeOf((*MockManager)(nil).GetPrivateChannelMembers), id)
+}
+
+// GetUserPrivateChannels mocks base method
+func (m *MockManager) GetUserPrivateChannels(userID uuid.UUID) ([]*model.Channel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserPrivateChannels", userID)
+	ret0, _ := ret[0].([]*model.Channel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserPrivateChannels indicates an expected call of GetUserPrivateChannels
+func (mr *MockManagerMockRecorder) GetUserPrivateChannels(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserPrivateChannels", reflect.TypeOf((*MockManager)(nil).GetUserPrivateChannels), userID)
+}
+
+// AddPrivateChannelMember mocks base method
+func (m *MockManager) AddPrivateChannelMember(channelID, userID, operatorID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddPrivateChannelMember", channelID, userID, operatorID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddPrivateChannelMember indicates an expected call of AddPrivateChannelMember
+func (mr *MockManagerMockRecorder) AddPrivateChannelMember(channelID, userID, operatorID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddPrivateChannelMember", reflect.TypeOf((*MockManager)(nil).AddPrivateChannelMember), channelID, userID, operatorID)
+}
+
+// RemovePrivateChannelMember mocks base method
+func (m *MockManager) RemovePrivateChannelMember(channelID, userID, operatorID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemovePrivateChannelMember", channelID, userID, operatorID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemovePrivateChannelMember indicates an expected call of RemovePrivateChannelMember
+func (mr *MockManagerMockRecorder) RemovePrivateChannelMember(channelID, userID, operatorID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemovePrivateChannelMember", reflect.TypeOf((*MockManager)(nil).RemovePrivateChannelMember), channelID, userID, operatorID)
+}
+
 // IsChannelAccessibleToUser mocks base method
 func (m *MockManager) IsChannelAccessibleToUser(userID, channelID uuid.UUID) (bool, error) {
 	m.ctrl.T.Helper()
@@ -180,6 +180,14 @@ func makeChannelTree(channels []*model.Channel) (*treeImpl, error) {
 	return ct, nil
 }
 
+// replace ツリーの内容を置き換えます。ctのロックが必要です
+func (ct *treeImpl) replace(other *treeImpl) {
+	ct.nodes = other.nodes
+	ct.roots = other.roots
+	ct.paths = other.paths
+	ct.json = other.json
+}
+
 func (ct *treeImpl) add(ch *model.Channel) {
 	n := &channelNode{
 		id:        ch.ID,
@@ -231,6 +239,7 @@ func (ct *treeImpl) move(id uuid.UUID, newParent optional.UUID, newName optional
 			if !ok {
 				panic("assert !ok = false")
 			}
+			delete(ct.roots, n.id)
 			n.parent = p
 			p.children[n.id] = n
 		}
@@ -239,6 +248,25 @@ func (ct *treeImpl) move(id uuid.UUID, newParent optional.UUID, newName optional
 	ct.regenerateJSON()
 }
 
+func (ct *treeImpl) remove(id uuid.UUID) {
+	n, ok := ct.nodes[id]
+	if !ok {
+		panic("assert !ok = false")
+	}
+	if len(n.children) > 0 {
+		panic("assert len(n.children) > 0 = false")
+	}
+
+	if n.parent != nil {
+		delete(n.parent.children, n.id)
+	} else {
+		delete(ct.roots, n.id)
+	}
+	delete(ct.nodes, n.id)
+	delete(ct.paths, n.id)
+	ct.regenerateJSON()
+}
+
 func (ct *treeImpl) update(id uuid.UUID, ch *model.Channel) {
 	n, ok := ct.nodes[id]
 	if !ok {
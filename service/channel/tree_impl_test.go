@@ -186,3 +186,25 @@ func TestChannelTreeImpl_IsArchivedChannel(t *testing.T) {
 	assert.False(t, tree.IsArchivedChannel(cA))
 	assert.False(t, tree.IsArchivedChannel(uuid.Nil))
 }
+
+func TestChannelTreeImpl_remove(t *testing.T) {
+	t.Parallel()
+	tree := makeTestChannelTree(t)
+
+	tree.remove(cABCD)
+	assert.False(t, tree.IsChannelPresent(cABCD))
+	assert.ElementsMatch(t, tree.GetChildrenIDs(cABC), []uuid.UUID{cABCE})
+	assert.Equal(t, "", tree.GetChannelPath(cABCD))
+
+	tree.remove(cEK)
+	tree.remove(cEFGJ)
+	tree.remove(cEFGHI)
+	tree.remove(cEFGH)
+	tree.remove(cEFG)
+	tree.remove(cEF)
+	tree.remove(cE)
+	assert.ElementsMatch(t, tree.GetChildrenIDs(uuid.Nil), []uuid.UUID{cA})
+
+	assert.Panics(t, func() { tree.remove(cA) })
+	assert.Panics(t, func() { tree.remove(cNotFound) })
+}
@@ -0,0 +1,281 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/traPtitech/traQ/utils/random"
+	"go.uber.org/zap"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	topicHello     = "cluster.hello"
+	topicHeartbeat = "cluster.heartbeat"
+	topicBye       = "cluster.bye"
+
+	heartbeatInterval = 5 * time.Second
+	peerTimeout       = 3 * heartbeatInterval
+)
+
+// Handler 他ノードから受信したメッセージのハンドラ
+type Handler func(node string, body []byte)
+
+// NodeHandler ノードの参加・離脱のハンドラ
+type NodeHandler func(node string)
+
+// Bus ノード間イベントバス
+//
+// 各サービスはプロセス内に保持している状態の変化を指定したトピックで他ノードに送信し、
+// 他ノードから受信した状態とマージします。
+// 受信したメッセージはhubには流さないため、通知やBOTイベントなどの副作用は発生元のノードでのみ起こります。
+type Bus struct {
+	id        string
+	transport Transport
+	logger    *zap.Logger
+
+	handlers map[string][]Handler
+	joined   []NodeHandler
+	left     []NodeHandler
+	peers    map[string]time.Time
+	mu       sync.RWMutex
+
+	started bool
+	closed  bool
+	stop    chan struct{}
+	wg      sync.WaitGroup
+}
+
+// NewBus ノード間イベントバスを生成します
+func NewBus(transport Transport, logger *zap.Logger) *Bus {
+	return &Bus{
+		id:        random.AlphaNumeric(20),
+		transport: transport,
+		logger:    logger.Named("cluster"),
+		handlers:  map[string][]Handler{},
+		peers:     map[string]time.Time{},
+		stop:      make(chan struct{}),
+	}
+}
+
+// NewStandaloneBus 他ノードと接続しないノード間イベントバスを生成します
+func NewStandaloneBus() *Bus {
+	return NewBus(NewMemoryNetwork().NewTransport(), zap.NewNop())
+}
+
+// NodeID 自ノードのIDを返します
+func (b *Bus) NodeID() string {
+	return b.id
+}
+
+// Subscribe 指定したトピックのハンドラを登録します
+//
+// ハンドラは受信ループ内で同期的に呼び出されます。Startより前に登録してください。
+func (b *Bus) Subscribe(topic string, h Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[topic] = append(b.handlers[topic], h)
+}
+
+// OnNodeJoined ノードの参加を検知した時のハンドラを登録します
+//
+// 新しいノードに自ノードの状態を送信するために用います。
+func (b *Bus) OnNodeJoined(h NodeHandler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.joined = append(b.joined, h)
+}
+
+// OnNodeLeft ノードの離脱を検知した時のハンドラを登録します
+//
+// 離脱したノード由来の状態を破棄するために用います。
+func (b *Bus) OnNodeLeft(h NodeHandler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.left = append(b.left, h)
+}
+
+// Publish 他の全ノードにメッセージを送信します
+//
+// 配信はベストエフォートで、失敗してもエラーは返しません。
+func (b *Bus) Publish(topic string, body interface{}) {
+	b.mu.RLock()
+	closed := b.closed
+	b.mu.RUnlock()
+	if closed {
+		return
+	}
+
+	var raw json.RawMessage
+	if body != nil {
+		var err error
+		raw, err = json.Marshal(body)
+		if err != nil {
+			b.logger.Error("failed to encode a cluster message", zap.String("topic", topic), zap.Error(err))
+			return
+		}
+	}
+	if err := b.transport.Send(&Message{Node: b.id, Topic: topic, Body: raw}); err != nil && err != ErrClosed {
+		b.logger.Warn("failed to send a cluster message", zap.String("topic", topic), zap.Error(err))
+	}
+}
+
+// Nodes 生存している他ノードのIDを返します
+func (b *Bus) Nodes() []string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	nodes := make([]string, 0, len(b.peers))
+	for n := range b.peers {
+		nodes = append(nodes, n)
+	}
+	sort.Strings(nodes)
+	return nodes
+}
+
+// IsLeader 自ノードがリーダーかどうかを返します
+//
+// 生存しているノードのうちIDが最小のノードがリーダーになります。
+// 離脱したノードの後始末など、クラスタ全体で一度だけ行いたい処理に用います。
+func (b *Bus) IsLeader() bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for n := range b.peers {
+		if n < b.id {
+			return false
+		}
+	}
+	return true
+}
+
+// Start 受信とハートビートを開始します
+func (b *Bus) Start() {
+	b.mu.Lock()
+	if b.started {
+		b.mu.Unlock()
+		return
+	}
+	b.started = true
+	b.mu.Unlock()
+
+	b.wg.Add(2)
+	go b.receiveLoop()
+	go b.heartbeatLoop()
+	b.Publish(topicHello, nil)
+	b.logger.Info("cluster bus started", zap.String("node", b.id))
+}
+
+// Shutdown 他ノードに離脱を通知して停止します
+func (b *Bus) Shutdown(ctx context.Context) error {
+	b.Publish(topicBye, nil)
+
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	started := b.started
+	b.mu.Unlock()
+
+	if started {
+		close(b.stop)
+	}
+	err := b.transport.Close()
+	if err == ErrClosed {
+		err = nil
+	}
+
+	done := make(chan struct{})
+	go func() {
+		b.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (b *Bus) receiveLoop() {
+	defer b.wg.Done()
+	for m := range b.transport.Receive() {
+		b.handle(m)
+	}
+}
+
+func (b *Bus) heartbeatLoop() {
+	defer b.wg.Done()
+	t := time.NewTicker(heartbeatInterval)
+	defer t.Stop()
+	for {
+		select {
+		case now := <-t.C:
+			b.Publish(topicHeartbeat, nil)
+			b.expirePeers(now)
+		case <-b.stop:
+			return
+		}
+	}
+}
+
+func (b *Bus) handle(m *Message) {
+	if m.Node == b.id {
+		return
+	}
+
+	if m.Topic == topicBye {
+		b.removePeer(m.Node)
+		return
+	}
+
+	b.mu.Lock()
+	_, known := b.peers[m.Node]
+	b.peers[m.Node] = time.Now()
+	joined := b.joined
+	handlers := b.handlers[m.Topic]
+	b.mu.Unlock()
+
+	if !known {
+		b.logger.Info("cluster node joined", zap.String("node", m.Node))
+		for _, h := range joined {
+			h(m.Node)
+		}
+	}
+	for _, h := range handlers {
+		h(m.Node, m.Body)
+	}
+}
+
+func (b *Bus) expirePeers(now time.Time) {
+	b.mu.RLock()
+	var expired []string
+	for n, last := range b.peers {
+		if now.Sub(last) > peerTimeout {
+			expired = append(expired, n)
+		}
+	}
+	b.mu.RUnlock()
+
+	for _, n := range expired {
+		b.removePeer(n)
+	}
+}
+
+func (b *Bus) removePeer(node string) {
+	b.mu.Lock()
+	if _, ok := b.peers[node]; !ok {
+		b.mu.Unlock()
+		return
+	}
+	delete(b.peers, node)
+	left := b.left
+	b.mu.Unlock()
+
+	b.logger.Info("cluster node left", zap.String("node", node))
+	for _, h := range left {
+		h(node)
+	}
+}
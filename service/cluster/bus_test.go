@@ -0,0 +1,119 @@
+package cluster
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recorder struct {
+	bodies []string
+	nodes  []string
+	mu     sync.Mutex
+}
+
+func (r *recorder) handle(node string, body []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nodes = append(r.nodes, node)
+	r.bodies = append(r.bodies, string(body))
+}
+
+func (r *recorder) handleNode(node string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nodes = append(r.nodes, node)
+}
+
+func (r *recorder) len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.nodes)
+}
+
+func newTestBuses(t *testing.T, n int) []*Bus {
+	t.Helper()
+	network := NewMemoryNetwork()
+	buses := make([]*Bus, n)
+	for i := range buses {
+		buses[i] = NewBus(network.NewTransport(), zap.NewNop())
+	}
+	t.Cleanup(func() {
+		for _, b := range buses {
+			_ = b.Shutdown(context.Background())
+		}
+	})
+	return buses
+}
+
+func TestBus_Publish(t *testing.T) {
+	t.Parallel()
+
+	buses := newTestBuses(t, 3)
+	recs := make([]*recorder, len(buses))
+	for i, b := range buses {
+		recs[i] = &recorder{}
+		b.Subscribe("test", recs[i].handle)
+	}
+	for _, b := range buses {
+		b.Start()
+	}
+
+	buses[0].Publish("test", map[string]int{"a": 1})
+	buses[0].Publish("other", nil)
+
+	for _, i := range []int{1, 2} {
+		rec := recs[i]
+		assert.Eventually(t, func() bool { return rec.len() == 1 }, time.Second, 10*time.Millisecond)
+		rec.mu.Lock()
+		assert.Equal(t, []string{buses[0].NodeID()}, rec.nodes)
+		assert.Equal(t, []string{`{"a":1}`}, rec.bodies)
+		rec.mu.Unlock()
+	}
+	assert.Equal(t, 0, recs[0].len(), "a node must not receive its own messages")
+}
+
+func TestBus_Nodes(t *testing.T) {
+	t.Parallel()
+
+	buses := newTestBuses(t, 2)
+	joined := &recorder{}
+	left := &recorder{}
+	buses[0].OnNodeJoined(joined.handleNode)
+	buses[0].OnNodeLeft(left.handleNode)
+	for _, b := range buses {
+		b.Start()
+	}
+
+	require.Eventually(t, func() bool { return joined.len() == 1 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{buses[1].NodeID()}, buses[0].Nodes())
+	assert.Equal(t, buses[0].NodeID() < buses[1].NodeID(), buses[0].IsLeader())
+	assert.Equal(t, buses[1].NodeID() < buses[0].NodeID(), buses[1].IsLeader())
+
+	require.NoError(t, buses[1].Shutdown(context.Background()))
+	require.Eventually(t, func() bool { return left.len() == 1 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{buses[1].NodeID()}, left.nodes)
+	assert.Empty(t, buses[0].Nodes())
+	assert.True(t, buses[0].IsLeader())
+}
+
+func TestBus_expirePeers(t *testing.T) {
+	t.Parallel()
+
+	b := NewStandaloneBus()
+	left := &recorder{}
+	b.OnNodeLeft(left.handleNode)
+
+	now := time.Now()
+	b.handle(&Message{Node: "alive", Topic: topicHeartbeat})
+	b.handle(&Message{Node: "dead", Topic: topicHeartbeat})
+	b.peers["dead"] = now.Add(-peerTimeout - time.Second)
+
+	b.expirePeers(now)
+	assert.Equal(t, []string{"alive"}, b.Nodes())
+	assert.Equal(t, []string{"dead"}, left.nodes)
+}
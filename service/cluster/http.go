@@ -0,0 +1,245 @@
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/traPtitech/traQ/utils/hmac"
+	"go.uber.org/zap"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// HTTPTransportPath ノード間メッセージの受信パス
+	HTTPTransportPath = "/cluster/messages"
+	// headerSignature ノード間メッセージの署名ヘッダー
+	headerSignature = "X-TRAQ-Cluster-Signature"
+
+	httpTransportBufferSize = 1024
+	httpTransportMaxBody    = 4 << 20 // 4MiB
+	httpTransportTimeout    = 5 * time.Second
+	// httpTransportSignatureTolerance 署名のタイムスタンプと受信時刻の許容差
+	httpTransportSignatureTolerance = 30 * time.Second
+)
+
+// HTTPTransportConfig HTTP転送路設定
+type HTTPTransportConfig struct {
+	// ListenAddr 他ノードからのメッセージを待ち受けるアドレス
+	ListenAddr string
+	// Peers 他ノードのベースURL
+	//
+	// 自ノードが含まれていても構いません。自ノードが送信したメッセージは無視されます。
+	Peers []string
+	// Secret メッセージ署名用の共有シークレット
+	Secret string
+}
+
+// HTTPTransport HTTPによるノード間の転送路
+//
+// メッセージはタイムスタンプ付きのHMAC-SHA256で署名され、ピアごとのキューから順にPOSTされます。
+// 署名のタイムスタンプが受信時刻から大きく離れているメッセージは、リプレイを防ぐため拒否されます。
+type HTTPTransport struct {
+	secret string
+	logger *zap.Logger
+	client *http.Client
+	server *http.Server
+	peers  []*httpPeer
+	recv   chan *Message
+	closed bool
+	mu     sync.RWMutex
+	wg     sync.WaitGroup
+}
+
+type httpPeer struct {
+	url         string
+	queue       chan []byte
+	unreachable bool
+}
+
+// NewHTTPTransport HTTP転送路を生成し、待ち受けを開始します
+func NewHTTPTransport(c HTTPTransportConfig, logger *zap.Logger) (*HTTPTransport, error) {
+	if len(c.Secret) == 0 {
+		return nil, errors.New("cluster secret is required")
+	}
+
+	t := &HTTPTransport{
+		secret: c.Secret,
+		logger: logger.Named("cluster_http"),
+		client: &http.Client{
+			Timeout: httpTransportTimeout,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		recv: make(chan *Message, httpTransportBufferSize),
+	}
+	for _, u := range c.Peers {
+		if !strings.HasPrefix(u, "http://") && !strings.HasPrefix(u, "https://") {
+			return nil, fmt.Errorf("invalid cluster peer url: %s", u)
+		}
+		t.peers = append(t.peers, &httpPeer{
+			url:   strings.TrimRight(u, "/") + HTTPTransportPath,
+			queue: make(chan []byte, httpTransportBufferSize),
+		})
+	}
+
+	if len(c.ListenAddr) > 0 {
+		l, err := net.Listen("tcp", c.ListenAddr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to listen cluster address: %w", err)
+		}
+		mux := http.NewServeMux()
+		mux.Handle(HTTPTransportPath, t)
+		t.server = &http.Server{Handler: mux}
+		go func() {
+			if err := t.server.Serve(l); err != nil && err != http.ErrServerClosed {
+				t.logger.Error("cluster server stopped", zap.Error(err))
+			}
+		}()
+	}
+
+	for _, p := range t.peers {
+		t.wg.Add(1)
+		go t.sendLoop(p)
+	}
+	return t, nil
+}
+
+// Send implements Transport interface.
+func (t *HTTPTransport) Send(m *Message) error {
+	b, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.closed {
+		return ErrClosed
+	}
+	for _, p := range t.peers {
+		select {
+		case p.queue <- b:
+		default:
+			t.logger.Warn("discard a message because the peer's queue is full", zap.String("peer", p.url), zap.String("topic", m.Topic))
+		}
+	}
+	return nil
+}
+
+func (t *HTTPTransport) sendLoop(p *httpPeer) {
+	defer t.wg.Done()
+	for b := range p.queue {
+		err := t.post(p.url, b)
+		if err != nil && !p.unreachable {
+			t.logger.Warn("cluster peer became unreachable", zap.String("peer", p.url), zap.Error(err))
+		} else if err == nil && p.unreachable {
+			t.logger.Info("cluster peer became reachable", zap.String("peer", p.url))
+		}
+		p.unreachable = err != nil
+	}
+}
+
+func (t *HTTPTransport) post(url string, b []byte) error {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(headerSignature, hmac.SignTimestamp(time.Now(), b, t.secret))
+
+	res, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	_, _ = io.Copy(ioutil.Discard, res.Body)
+	_ = res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("unexpected status code: %d", res.StatusCode)
+	}
+	return nil
+}
+
+// ServeHTTP 他ノードからのメッセージを受け付けます
+func (t *HTTPTransport) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(rw, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(rw, r.Body, httpTransportMaxBody))
+	if err != nil {
+		http.Error(rw, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+		return
+	}
+	if err := hmac.VerifyTimestamp(r.Header.Get(headerSignature), body, time.Now(), httpTransportSignatureTolerance, t.secret); err != nil {
+		http.Error(rw, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	var m Message
+	if err := json.Unmarshal(body, &m); err != nil || len(m.Node) == 0 || len(m.Topic) == 0 {
+		http.Error(rw, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.closed {
+		http.Error(rw, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+		return
+	}
+	select {
+	case t.recv <- &m:
+		rw.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(rw, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+	}
+}
+
+// Receive implements Transport interface.
+func (t *HTTPTransport) Receive() <-chan *Message {
+	return t.recv
+}
+
+// Close implements Transport interface.
+//
+// キューに残っているメッセージの送信を一定時間待ってから閉じます。
+func (t *HTTPTransport) Close() error {
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		return ErrClosed
+	}
+	t.closed = true
+	for _, p := range t.peers {
+		close(p.queue)
+	}
+	close(t.recv)
+	t.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		t.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(httpTransportTimeout):
+		t.logger.Warn("some messages may not be delivered to the peers")
+	}
+	if t.server != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), httpTransportTimeout)
+		defer cancel()
+		return t.server.Shutdown(ctx)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package cluster
+
+import (
+	"bytes"
+	"encoding/hex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traPtitech/traQ/utils/hmac"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNewHTTPTransport(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no secret", func(t *testing.T) {
+		t.Parallel()
+		_, err := NewHTTPTransport(HTTPTransportConfig{}, zap.NewNop())
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid peer", func(t *testing.T) {
+		t.Parallel()
+		_, err := NewHTTPTransport(HTTPTransportConfig{Secret: "secret", Peers: []string{"traq-1:3010"}}, zap.NewNop())
+		assert.Error(t, err)
+	})
+}
+
+func TestHTTPTransport(t *testing.T) {
+	t.Parallel()
+
+	receiver, err := NewHTTPTransport(HTTPTransportConfig{Secret: "secret"}, zap.NewNop())
+	require.NoError(t, err)
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	sender, err := NewHTTPTransport(HTTPTransportConfig{Secret: "secret", Peers: []string{server.URL}}, zap.NewNop())
+	require.NoError(t, err)
+
+	require.NoError(t, sender.Send(&Message{Node: "node1", Topic: "test", Body: []byte(`{"a":1}`)}))
+	select {
+	case m := <-receiver.Receive():
+		assert.Equal(t, "node1", m.Node)
+		assert.Equal(t, "test", m.Topic)
+		assert.JSONEq(t, `{"a":1}`, string(m.Body))
+	case <-time.After(time.Second):
+		t.Fatal("message was not delivered")
+	}
+
+	require.NoError(t, sender.Close())
+	assert.Equal(t, ErrClosed, sender.Send(&Message{Node: "node1", Topic: "test"}))
+	require.NoError(t, receiver.Close())
+}
+
+func TestHTTPTransport_ServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	tr, err := NewHTTPTransport(HTTPTransportConfig{Secret: "secret"}, zap.NewNop())
+	require.NoError(t, err)
+	defer tr.Close()
+
+	post := func(body []byte, signature string) int {
+		req := httptest.NewRequest(http.MethodPost, HTTPTransportPath, bytes.NewReader(body))
+		req.Header.Set(headerSignature, signature)
+		rec := httptest.NewRecorder()
+		tr.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	body := []byte(`{"node":"a","topic":"b"}`)
+
+	assert.Equal(t, http.StatusUnauthorized, post(body, hmac.SignTimestamp(time.Now(), body, "wrong")))
+	assert.Equal(t, http.StatusUnauthorized, post(body, hex.EncodeToString(hmac.SHA256(body, "secret"))))
+	assert.Equal(t, http.StatusUnauthorized, post(body, hmac.SignTimestamp(time.Now().Add(-time.Minute), body, "secret")))
+	assert.Equal(t, http.StatusBadRequest, post([]byte(`{"node":"a"}`), hmac.SignTimestamp(time.Now(), []byte(`{"node":"a"}`), "secret")))
+	assert.Equal(t, http.StatusNoContent, post(body, hmac.SignTimestamp(time.Now(), body, "secret")))
+}
@@ -0,0 +1,94 @@
+package cluster
+
+import (
+	"sync"
+)
+
+const memoryTransportBufferSize = 1024
+
+// MemoryNetwork プロセス内のノード間ネットワーク
+//
+// 単一ノードでの動作やテストに用います。
+type MemoryNetwork struct {
+	transports map[*memoryTransport]struct{}
+	mu         sync.RWMutex
+}
+
+type memoryTransport struct {
+	network *MemoryNetwork
+	recv    chan *Message
+	closed  bool
+	mu      sync.RWMutex
+}
+
+// NewMemoryNetwork プロセス内のノード間ネットワークを生成します
+func NewMemoryNetwork() *MemoryNetwork {
+	return &MemoryNetwork{
+		transports: map[*memoryTransport]struct{}{},
+	}
+}
+
+// NewTransport ネットワークに参加する転送路を生成します
+func (n *MemoryNetwork) NewTransport() Transport {
+	t := &memoryTransport{
+		network: n,
+		recv:    make(chan *Message, memoryTransportBufferSize),
+	}
+	n.mu.Lock()
+	n.transports[t] = struct{}{}
+	n.mu.Unlock()
+	return t
+}
+
+// Send implements Transport interface.
+func (t *memoryTransport) Send(m *Message) error {
+	t.mu.RLock()
+	closed := t.closed
+	t.mu.RUnlock()
+	if closed {
+		return ErrClosed
+	}
+
+	t.network.mu.RLock()
+	defer t.network.mu.RUnlock()
+	for dst := range t.network.transports {
+		if dst != t {
+			dst.deliver(m)
+		}
+	}
+	return nil
+}
+
+func (t *memoryTransport) deliver(m *Message) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.closed {
+		return
+	}
+	select {
+	case t.recv <- m:
+	default:
+		// バッファが溢れたので破棄
+	}
+}
+
+// Receive implements Transport interface.
+func (t *memoryTransport) Receive() <-chan *Message {
+	return t.recv
+}
+
+// Close implements Transport interface.
+func (t *memoryTransport) Close() error {
+	t.network.mu.Lock()
+	delete(t.network.transports, t)
+	t.network.mu.Unlock()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return ErrClosed
+	}
+	t.closed = true
+	close(t.recv)
+	return nil
+}
@@ -0,0 +1,32 @@
+package cluster
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// ErrClosed 転送路が閉じられています
+var ErrClosed = errors.New("transport closed")
+
+// Message ノード間で送受信されるメッセージ
+type Message struct {
+	// Node 送信元ノードID
+	Node string `json:"node"`
+	// Topic トピック
+	Topic string `json:"topic"`
+	// Body 本文
+	Body json.RawMessage `json:"body,omitempty"`
+}
+
+// Transport ノード間のメッセージ転送路
+type Transport interface {
+	// Send 他の全ノードにメッセージを送信します
+	//
+	// 配信はベストエフォートで、宛先が詰まっている場合は破棄されることがあります。
+	// 同一ノードへの送信順序は保たれます。
+	Send(m *Message) error
+	// Receive 他ノードから受信したメッセージのチャネルを返します
+	Receive() <-chan *Message
+	// Close 転送路を閉じます
+	Close() error
+}
@@ -35,9 +35,16 @@ func NewChannelCounter(db *gorm.DB, hub *hub.Hub) (ChannelCounter, error) {
 	}
 	channelsCounter.Add(float64(counter.count))
 	go func() {
-		for e := range hub.Subscribe(1, event.ChannelCreated).Receiver {
-			if e.Fields["channel"].(*model.Channel).IsPublic {
-				counter.inc()
+		for e := range hub.Subscribe(1, event.ChannelCreated, event.ChannelDeleted).Receiver {
+			switch e.Topic() {
+			case event.ChannelCreated:
+				if e.Fields["channel"].(*model.Channel).IsPublic {
+					counter.inc()
+				}
+			case event.ChannelDeleted:
+				if !e.Fields["private"].(bool) {
+					counter.dec()
+				}
 			}
 		}
 	}()
@@ -56,3 +63,9 @@ func (c *channelCounterImpl) inc() {
 	c.Unlock()
 	channelsCounter.Inc()
 }
+
+func (c *channelCounterImpl) dec() {
+	c.Lock()
+	c.count--
+	c.Unlock()
+}
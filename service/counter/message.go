@@ -37,7 +37,8 @@ func NewMessageCounter(db *gorm.DB, hub *hub.Hub) (MessageCounter, error) {
 	}
 	messagesCounter.Add(float64(counter.count))
 	go func() {
-		for range hub.Subscribe(1, event.MessageCreated).Receiver {
+		// スレッドへの返信もメッセージとして数える
+		for range hub.Subscribe(1, event.MessageCreated, event.MessageReplied).Receiver {
 			counter.inc()
 		}
 	}()
@@ -1,33 +1,52 @@
 package counter
 
 import (
+	"encoding/json"
 	"github.com/gofrs/uuid"
 	"github.com/leandro-lugaresi/hub"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/traPtitech/traQ/event"
+	"github.com/traPtitech/traQ/service/cluster"
 	"sync"
 	"time"
 )
 
+const onlineClusterTopic = "counter.online"
+
 var onlineUsersCounter = promauto.NewGauge(prometheus.GaugeOpts{
 	Namespace: "traq",
 	Name:      "online_users",
 })
 
 // OnlineCounter オンラインユーザーカウンター
+//
+// いずれかのノードに接続しているユーザーをオンラインとして扱います。
 type OnlineCounter struct {
 	hub          *hub.Hub
+	cluster      *cluster.Bus
 	counters     map[uuid.UUID]*counter
+	remote       map[string]map[uuid.UUID]struct{} // nodeID -> オンラインのユーザー
 	countersLock sync.Mutex
 }
 
+// onlineClusterMessage ノード間で送受信するオンライン状態の変化
+type onlineClusterMessage struct {
+	Online  []uuid.UUID `json:"online,omitempty"`
+	Offline []uuid.UUID `json:"offline,omitempty"`
+}
+
 // NewOnlineCounter オンラインユーザーカウンターを生成します
-func NewOnlineCounter(hub *hub.Hub) *OnlineCounter {
+func NewOnlineCounter(hub *hub.Hub, bus *cluster.Bus) *OnlineCounter {
 	oc := &OnlineCounter{
 		hub:      hub,
+		cluster:  bus,
 		counters: map[uuid.UUID]*counter{},
+		remote:   map[string]map[uuid.UUID]struct{}{},
 	}
+	bus.Subscribe(onlineClusterTopic, oc.handleRemote)
+	bus.OnNodeJoined(oc.sendSnapshot)
+	bus.OnNodeLeft(oc.removeNode)
 	go func() {
 		for e := range hub.Subscribe(8, event.SSEConnected, event.SSEDisconnected, event.WSConnected, event.WSDisconnected).Receiver {
 			switch e.Topic() {
@@ -56,6 +75,11 @@ func (oc *OnlineCounter) inc(userID uuid.UUID) (toOnline bool) {
 	toOnline = c.inc()
 	if toOnline {
 		onlineUsersCounter.Inc()
+		oc.cluster.Publish(onlineClusterTopic, &onlineClusterMessage{Online: []uuid.UUID{userID}})
+		if oc.isOnlineRemote(userID) {
+			// 他ノードで既にオンライン
+			return
+		}
 		oc.hub.Publish(hub.Message{
 			Name: event.UserOnline,
 			Fields: hub.Fields{
@@ -80,6 +104,11 @@ func (oc *OnlineCounter) dec(userID uuid.UUID) (toOffline bool) {
 	toOffline = c.dec()
 	if toOffline {
 		onlineUsersCounter.Dec()
+		oc.cluster.Publish(onlineClusterTopic, &onlineClusterMessage{Offline: []uuid.UUID{userID}})
+		if oc.isOnlineRemote(userID) {
+			// 他ノードでまだオンライン
+			return
+		}
 		oc.hub.Publish(hub.Message{
 			Name: event.UserOffline,
 			Fields: hub.Fields{
@@ -95,28 +124,114 @@ func (oc *OnlineCounter) dec(userID uuid.UUID) (toOffline bool) {
 func (oc *OnlineCounter) IsOnline(userID uuid.UUID) bool {
 	oc.countersLock.Lock()
 	c, ok := oc.counters[userID]
-	if !ok {
-		oc.countersLock.Unlock()
-		return false
-	}
 	oc.countersLock.Unlock()
-
-	return c.isOnline()
+	if ok && c.isOnline() {
+		return true
+	}
+	return oc.isOnlineRemote(userID)
 }
 
 // GetOnlineUserIDs オンラインなユーザーのUUIDの配列を取得します
 func (oc *OnlineCounter) GetOnlineUserIDs() []uuid.UUID {
 	oc.countersLock.Lock()
 	users := make([]uuid.UUID, 0, len(oc.counters))
+	added := make(map[uuid.UUID]struct{}, len(oc.counters))
 	for u, c := range oc.counters {
 		if c.isOnline() {
 			users = append(users, u)
+			added[u] = struct{}{}
+		}
+	}
+	for _, us := range oc.remote {
+		for u := range us {
+			if _, ok := added[u]; !ok {
+				users = append(users, u)
+				added[u] = struct{}{}
+			}
 		}
 	}
 	oc.countersLock.Unlock()
 	return users
 }
 
+// isOnlineRemote 指定したユーザーが他ノードでオンラインかどうかを取得します
+func (oc *OnlineCounter) isOnlineRemote(userID uuid.UUID) bool {
+	oc.countersLock.Lock()
+	defer oc.countersLock.Unlock()
+	for _, us := range oc.remote {
+		if _, ok := us[userID]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// handleRemote 他ノードのオンライン状態の変化を反映します
+//
+// オンライン・オフラインイベントは発生元のノードで発行されるため、ここでは発行しません。
+func (oc *OnlineCounter) handleRemote(node string, body []byte) {
+	var m onlineClusterMessage
+	if err := json.Unmarshal(body, &m); err != nil {
+		return
+	}
+
+	oc.countersLock.Lock()
+	defer oc.countersLock.Unlock()
+	us, ok := oc.remote[node]
+	if !ok {
+		us = map[uuid.UUID]struct{}{}
+		oc.remote[node] = us
+	}
+	for _, u := range m.Online {
+		us[u] = struct{}{}
+	}
+	for _, u := range m.Offline {
+		delete(us, u)
+	}
+}
+
+// sendSnapshot 自ノードでオンラインな全ユーザーを送信します
+func (oc *OnlineCounter) sendSnapshot(string) {
+	oc.countersLock.Lock()
+	users := make([]uuid.UUID, 0, len(oc.counters))
+	for u, c := range oc.counters {
+		if c.isOnline() {
+			users = append(users, u)
+		}
+	}
+	oc.countersLock.Unlock()
+	if len(users) > 0 {
+		oc.cluster.Publish(onlineClusterTopic, &onlineClusterMessage{Online: users})
+	}
+}
+
+// removeNode 離脱したノードのオンライン状態を破棄します
+//
+// 離脱によりオフラインになったユーザーのイベントはリーダーノードが発行します。
+func (oc *OnlineCounter) removeNode(node string) {
+	oc.countersLock.Lock()
+	us := oc.remote[node]
+	delete(oc.remote, node)
+	oc.countersLock.Unlock()
+
+	if !oc.cluster.IsLeader() {
+		return
+	}
+	now := time.Now()
+	for u := range us {
+		if oc.IsOnline(u) {
+			continue
+		}
+		oc.hub.Publish(hub.Message{
+			Name: event.UserOffline,
+			Fields: hub.Fields{
+				"user_id":  u,
+				"datetime": now,
+			},
+		})
+	}
+}
+
 type counter struct {
 	sync.RWMutex
 	userID      uuid.UUID
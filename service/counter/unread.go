@@ -1,12 +1,14 @@
 package counter
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/gofrs/uuid"
 	"github.com/jinzhu/gorm"
 	"github.com/leandro-lugaresi/hub"
 	"github.com/traPtitech/traQ/event"
 	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/service/cluster"
 	"sync"
 )
 
@@ -20,6 +22,8 @@ type UnreadMessageCounter interface {
 	GetChanges(reset bool) map[uuid.UUID]int
 }
 
+const unreadClusterTopic = "counter.unread"
+
 type unreadMessageCounterImpl struct {
 	counters map[uuid.UUID]int
 	changed  map[uuid.UUID]struct{}
@@ -27,7 +31,9 @@ type unreadMessageCounterImpl struct {
 }
 
 // NewUnreadMessageCounter 未読メッセージ数カウンタを生成します
-func NewUnreadMessageCounter(db *gorm.DB, hub *hub.Hub) (UnreadMessageCounter, error) {
+//
+// 自ノードで発生した未読数の増減は他ノードに送信され、他ノードのカウンタにも反映されます。
+func NewUnreadMessageCounter(db *gorm.DB, hub *hub.Hub, bus *cluster.Bus) (UnreadMessageCounter, error) {
 	type count struct {
 		UserID uuid.UUID
 		Count  int
@@ -45,28 +51,35 @@ func NewUnreadMessageCounter(db *gorm.DB, hub *hub.Hub) (UnreadMessageCounter, e
 		impl.changed[c.UserID] = struct{}{}
 	}
 
+	bus.Subscribe(unreadClusterTopic, func(_ string, body []byte) {
+		var deltas map[uuid.UUID]int
+		if err := json.Unmarshal(body, &deltas); err != nil {
+			return
+		}
+		impl.apply(deltas)
+	})
 	go func() {
 		for e := range hub.Subscribe(8, event.MessageUnread, event.ChannelRead, event.MessageDeleted).Receiver {
+			deltas := map[uuid.UUID]int{}
 			switch e.Topic() {
 			case event.MessageUnread:
-				impl.Inc(e.Fields["user_id"].(uuid.UUID), 1)
+				deltas[e.Fields["user_id"].(uuid.UUID)]++
 			case event.ChannelRead:
-				impl.Dec(e.Fields["user_id"].(uuid.UUID), e.Fields["read_messages_num"].(int))
+				deltas[e.Fields["user_id"].(uuid.UUID)] -= e.Fields["read_messages_num"].(int)
 			case event.MessageDeleted:
-				impl.DecMultiple(e.Fields["deleted_unreads"].([]*model.Unread))
+				for _, unread := range e.Fields["deleted_unreads"].([]*model.Unread) {
+					deltas[unread.UserID]--
+				}
+			}
+			if len(deltas) > 0 {
+				impl.apply(deltas)
+				bus.Publish(unreadClusterTopic, deltas)
 			}
 		}
 	}()
 	return impl, nil
 }
 
-func (c *unreadMessageCounterImpl) Inc(userID uuid.UUID, n int) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	c.counters[userID] += n
-	c.changed[userID] = struct{}{}
-}
-
 func (c *unreadMessageCounterImpl) Get(userID uuid.UUID) int {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
@@ -86,17 +99,17 @@ func (c *unreadMessageCounterImpl) GetChanges(reset bool) map[uuid.UUID]int {
 	return changes
 }
 
-func (c *unreadMessageCounterImpl) Dec(userID uuid.UUID, n int) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	c.dec(userID, n)
-}
-
-func (c *unreadMessageCounterImpl) DecMultiple(unreads []*model.Unread) {
+// apply ユーザーごとの未読数の増減を反映します
+func (c *unreadMessageCounterImpl) apply(deltas map[uuid.UUID]int) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	for _, unread := range unreads {
-		c.dec(unread.UserID, 1)
+	for userID, n := range deltas {
+		if n > 0 {
+			c.counters[userID] += n
+			c.changed[userID] = struct{}{}
+		} else if n < 0 {
+			c.dec(userID, -n)
+		}
 	}
 }
 
@@ -0,0 +1,125 @@
+package digest
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Mail 送信するメール
+type Mail struct {
+	// To 宛先メールアドレス
+	To string
+	// Subject 件名
+	Subject string
+	// Body 本文 (text/plain)
+	Body string
+	// Headers 追加のヘッダー
+	Headers map[string]string
+}
+
+// Mailer メール送信クライアント
+type Mailer interface {
+	// Send メールを送信します
+	Send(m *Mail) error
+}
+
+// SMTPConfig SMTPサーバー設定
+type SMTPConfig struct {
+	// Host SMTPサーバーのホスト
+	Host string
+	// Port SMTPサーバーのポート
+	Port int
+	// Username 認証ユーザー名 (空の場合は認証しない)
+	Username string
+	// Password 認証パスワード
+	Password string
+	// From 送信元メールアドレス
+	From string
+}
+
+type smtpMailer struct {
+	c    SMTPConfig
+	from *mail.Address
+}
+
+// NewSMTPMailer SMTPでメールを送信するクライアントを生成します
+//
+// サーバーがSTARTTLSに対応している場合は自動的に使用します。
+func NewSMTPMailer(c SMTPConfig) (Mailer, error) {
+	if len(c.Host) == 0 {
+		return nil, errors.New("smtp host is required")
+	}
+	from, err := mail.ParseAddress(c.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid from address: %w", err)
+	}
+	return &smtpMailer{c: c, from: from}, nil
+}
+
+func (m *smtpMailer) Send(mm *Mail) error {
+	to, err := mail.ParseAddress(mm.To)
+	if err != nil {
+		return fmt.Errorf("invalid to address: %w", err)
+	}
+	msg, err := buildMessage(m.from, to, mm, time.Now())
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if len(m.c.Username) > 0 {
+		auth = smtp.PlainAuth("", m.c.Username, m.c.Password, m.c.Host)
+	}
+	addr := net.JoinHostPort(m.c.Host, strconv.Itoa(m.c.Port))
+	return smtp.SendMail(addr, auth, m.from.Address, []string{to.Address}, msg)
+}
+
+// buildMessage RFC 5322形式のメッセージを生成します
+func buildMessage(from, to *mail.Address, m *Mail, now time.Time) ([]byte, error) {
+	headers := map[string]string{
+		"From":                      from.String(),
+		"To":                        to.String(),
+		"Subject":                   mime.BEncoding.Encode("UTF-8", m.Subject),
+		"Date":                      now.Format(time.RFC1123Z),
+		"MIME-Version":              "1.0",
+		"Content-Type":              "text/plain; charset=UTF-8",
+		"Content-Transfer-Encoding": "base64",
+	}
+	for k, v := range m.Headers {
+		headers[k] = v
+	}
+
+	keys := make([]string, 0, len(headers))
+	for k, v := range headers {
+		// ヘッダーインジェクション対策
+		if strings.ContainsAny(k, "\r\n:") || strings.ContainsAny(v, "\r\n") {
+			return nil, fmt.Errorf("invalid header: %s", k)
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	for _, k := range keys {
+		buf.WriteString(k + ": " + headers[k] + "\r\n")
+	}
+	buf.WriteString("\r\n")
+
+	body := base64.StdEncoding.EncodeToString([]byte(m.Body))
+	for len(body) > 76 {
+		buf.WriteString(body[:76] + "\r\n")
+		body = body[76:]
+	}
+	buf.WriteString(body + "\r\n")
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,158 @@
+package digest
+
+import (
+	"bufio"
+	"encoding/base64"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"mime"
+	"net"
+	"net/mail"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testSMTPServer 受信したメールを記録するだけのSMTPサーバー
+type testSMTPServer struct {
+	l        net.Listener
+	received chan string
+}
+
+func newTestSMTPServer(t *testing.T) *testSMTPServer {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := &testSMTPServer{l: l, received: make(chan string, 10)}
+	go s.serve()
+	t.Cleanup(func() { _ = l.Close() })
+	return s
+}
+
+func (s *testSMTPServer) port() int {
+	return s.l.Addr().(*net.TCPAddr).Port
+}
+
+func (s *testSMTPServer) serve() {
+	for {
+		conn, err := s.l.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *testSMTPServer) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+
+	reply("220 localhost ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(cmd, "DATA"):
+			reply("354 go ahead")
+			var data strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				data.WriteString(l)
+			}
+			s.received <- data.String()
+			reply("250 ok")
+		case strings.HasPrefix(cmd, "QUIT"):
+			reply("221 bye")
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+}
+
+func TestNewSMTPMailer(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewSMTPMailer(SMTPConfig{From: "traq@example.com"})
+	assert.Error(t, err)
+	_, err = NewSMTPMailer(SMTPConfig{Host: "localhost", From: "invalid"})
+	assert.Error(t, err)
+}
+
+func TestSMTPMailer_Send(t *testing.T) {
+	t.Parallel()
+
+	s := newTestSMTPServer(t)
+	m, err := NewSMTPMailer(SMTPConfig{Host: "127.0.0.1", Port: s.port(), From: "traQ <traq@example.com>"})
+	require.NoError(t, err)
+
+	t.Run("invalid to", func(t *testing.T) {
+		t.Parallel()
+		assert.Error(t, m.Send(&Mail{To: "user@example.com\r\nBcc: evil@example.com", Subject: "test"}))
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		require.NoError(t, m.Send(&Mail{
+			To:      "user@example.com",
+			Subject: "未読があります",
+			Body:    "本文",
+			Headers: map[string]string{"List-Unsubscribe": "<https://example.com/unsubscribe>"},
+		}))
+
+		select {
+		case data := <-s.received:
+			msg, err := mail.ReadMessage(strings.NewReader(data))
+			require.NoError(t, err)
+			assert.Equal(t, `"traQ" <traq@example.com>`, msg.Header.Get("From"))
+			assert.Equal(t, "<user@example.com>", msg.Header.Get("To"))
+			assert.Equal(t, "<https://example.com/unsubscribe>", msg.Header.Get("List-Unsubscribe"))
+			subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+			require.NoError(t, err)
+			assert.Equal(t, "未読があります", subject)
+
+			b := new(strings.Builder)
+			_, _ = bufio.NewReader(msg.Body).WriteTo(b)
+			body, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(b.String(), "\r\n", ""))
+			require.NoError(t, err)
+			assert.Equal(t, "本文", string(body))
+		case <-time.After(time.Second):
+			t.Fatal("mail was not received")
+		}
+	})
+}
+
+func TestBuildMessage(t *testing.T) {
+	t.Parallel()
+
+	from := &mail.Address{Address: "traq@example.com"}
+	to := &mail.Address{Address: "user@example.com"}
+
+	t.Run("header injection", func(t *testing.T) {
+		t.Parallel()
+		_, err := buildMessage(from, to, &Mail{Headers: map[string]string{"X-Test": "a\r\nBcc: evil@example.com"}}, time.Now())
+		assert.Error(t, err)
+	})
+
+	t.Run("long body", func(t *testing.T) {
+		t.Parallel()
+		msg, err := buildMessage(from, to, &Mail{Subject: "test", Body: strings.Repeat("あ", 100)}, time.Now())
+		require.NoError(t, err)
+		for _, l := range strings.Split(string(msg), "\r\n") {
+			assert.LessOrEqual(t, len(l), 998, "line "+strconv.Quote(l)+" is too long")
+		}
+	})
+}
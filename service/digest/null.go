@@ -0,0 +1,25 @@
+package digest
+
+import (
+	"context"
+	"github.com/traPtitech/traQ/model"
+)
+
+var nullS = &nullService{}
+
+type nullService struct{}
+
+// NewNullService 何もしないダイジェストメール配信サービスを返します
+func NewNullService() Service {
+	return nullS
+}
+
+func (*nullService) Start() {}
+
+func (*nullService) Shutdown(context.Context) error {
+	return nil
+}
+
+func (*nullService) SendVerificationMail(*model.UserDigestSetting) error {
+	return nil
+}
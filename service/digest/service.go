@@ -0,0 +1,337 @@
+package digest
+
+import (
+	"context"
+	"github.com/gofrs/uuid"
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/service/channel"
+	"github.com/traPtitech/traQ/service/cluster"
+	"github.com/traPtitech/traQ/service/counter"
+	"github.com/traPtitech/traQ/service/variable"
+	"github.com/traPtitech/traQ/utils/optional"
+	"go.uber.org/zap"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// pollInterval 配信予定のダイジェストを確認する間隔
+	pollInterval = 10 * time.Minute
+	// batchSize 一度に処理する設定の最大数
+	batchSize = 100
+	// maxRetryBackoff 送信に失敗した場合の再試行間隔の上限
+	maxRetryBackoff = 24 * time.Hour
+	// UnsubscribePath 配信停止APIのパス
+	UnsubscribePath = "/api/v3/public/digest/unsubscribe"
+	// VerifyPath メールアドレス確認APIのパス
+	VerifyPath = "/api/v3/public/digest/verify"
+)
+
+// Service ダイジェストメール配信サービス
+type Service interface {
+	// Start ダイジェストメールの配信を開始します
+	Start()
+	// Shutdown ダイジェストメールの配信を停止します
+	Shutdown(ctx context.Context) error
+	// SendVerificationMail 設定のメールアドレスに確認メールを送信します
+	//
+	// 確認済み、またはメールアドレスが設定されていない場合は何もしません。
+	SendVerificationMail(setting *model.UserDigestSetting) error
+}
+
+type serviceImpl struct {
+	repo   repository.Repository
+	cm     channel.Manager
+	oc     *counter.OnlineCounter
+	bus    *cluster.Bus
+	mailer Mailer
+	origin string
+	logger *zap.Logger
+
+	started bool
+	stop    chan struct{}
+	wg      sync.WaitGroup
+}
+
+// NewService ダイジェストメール配信サービスを生成します
+//
+// 複数ノードで動作している場合、リーダーノードのみが配信します。
+func NewService(repo repository.Repository, cm channel.Manager, oc *counter.OnlineCounter, bus *cluster.Bus, mailer Mailer, origin variable.ServerOriginString, logger *zap.Logger) Service {
+	return &serviceImpl{
+		repo:   repo,
+		cm:     cm,
+		oc:     oc,
+		bus:    bus,
+		mailer: mailer,
+		origin: string(origin),
+		logger: logger.Named("digest"),
+		stop:   make(chan struct{}),
+	}
+}
+
+func (s *serviceImpl) Start() {
+	if s.started {
+		return
+	}
+	s.started = true
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		t := time.NewTicker(pollInterval)
+		defer t.Stop()
+
+		for {
+			select {
+			case now := <-t.C:
+				if s.bus.IsLeader() {
+					s.sendDueDigests(now)
+				}
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+	s.logger.Info("digest service started")
+}
+
+func (s *serviceImpl) Shutdown(ctx context.Context) error {
+	if !s.started {
+		return nil
+	}
+	close(s.stop)
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		s.logger.Info("digest service shutdown")
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// sendDueDigests 配信間隔が経過したユーザーにダイジェストを送信します
+func (s *serviceImpl) sendDueDigests(now time.Time) {
+	for _, f := range []model.DigestFrequency{model.DigestFrequencyDaily, model.DigestFrequencyWeekly} {
+		for {
+			settings, err := s.repo.GetDueUserDigestSettings(f, now, batchSize)
+			if err != nil {
+				s.logger.Error("failed to GetDueUserDigestSettings", zap.Error(err))
+				return
+			}
+
+			for _, setting := range settings {
+				select {
+				case <-s.stop:
+					return
+				default:
+				}
+				if err := s.send(setting, now); err != nil {
+					// このユーザーは再試行間隔が経過するまで除外し、他のユーザーへの送信を続ける
+					s.logger.Error("failed to send digest", zap.Error(err), zap.Stringer("userId", setting.UserID))
+					if err := s.repo.SetUserDigestRetryAt(setting.UserID, now.Add(retryBackoff(setting.FailedAttempts))); err != nil {
+						s.logger.Error("failed to SetUserDigestRetryAt", zap.Error(err), zap.Stringer("userId", setting.UserID))
+						return
+					}
+				}
+			}
+
+			if len(settings) < batchSize {
+				break
+			}
+		}
+	}
+}
+
+// send 1人のユーザーにダイジェストを送信します
+//
+// 送信すべき未読が無い場合は送信せずに、最終配信日時のみを更新します。
+func (s *serviceImpl) send(setting *model.UserDigestSetting, now time.Time) error {
+	mail, err := s.build(setting)
+	if err != nil {
+		return err
+	}
+	if mail != nil {
+		if err := s.mailer.Send(mail); err != nil {
+			return err
+		}
+		digestSendCounter.Inc()
+	}
+	return s.repo.SetUserDigestSentAt(setting.UserID, now)
+}
+
+func (s *serviceImpl) SendVerificationMail(setting *model.UserDigestSetting) error {
+	if setting.IsEmailVerified() || len(setting.Email) == 0 || len(setting.VerificationToken) == 0 {
+		return nil
+	}
+	subject, body, err := renderVerification(&verificationData{
+		Origin:    s.origin,
+		VerifyURL: s.origin + VerifyPath + "?token=" + url.QueryEscape(setting.VerificationToken),
+	})
+	if err != nil {
+		return err
+	}
+	return s.mailer.Send(&Mail{
+		To:      setting.Email,
+		Subject: subject,
+		Body:    body,
+	})
+}
+
+// retryBackoff 送信失敗回数から次の再試行までの間隔を返します
+func retryBackoff(failedAttempts int) time.Duration {
+	d := pollInterval
+	for i := 0; i < failedAttempts && d < maxRetryBackoff; i++ {
+		d *= 2
+	}
+	if d > maxRetryBackoff {
+		return maxRetryBackoff
+	}
+	return d
+}
+
+// build ダイジェストメールを生成します
+//
+// 送信する必要がない場合はnilを返します。
+func (s *serviceImpl) build(setting *model.UserDigestSetting) (*Mail, error) {
+	user, err := s.repo.GetUser(setting.UserID, false)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if !user.IsActive() || user.IsBot() || s.oc.IsOnline(user.GetID()) {
+		return nil, nil
+	}
+
+	unreads, err := s.repo.GetUserUnreadChannels(user.GetID())
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(unreads, func(i, j int) bool { return unreads[i].UpdatedAt.After(unreads[j].UpdatedAt) })
+	data := &digestData{
+		DisplayName:    user.GetResponseDisplayName(),
+		Period:         periodLabel(setting.Frequency),
+		Origin:         s.origin,
+		UnsubscribeURL: s.origin + UnsubscribePath + "?token=" + url.QueryEscape(setting.UnsubscribeToken),
+	}
+	for _, u := range unreads {
+		// 前回のダイジェスト以降に更新された通知対象の未読のみ
+		if !u.Noticeable || (setting.LastSentAt != nil && !u.UpdatedAt.After(*setting.LastSentAt)) {
+			continue
+		}
+		ch, err := s.describeChannel(user.GetID(), u)
+		if err != nil {
+			return nil, err
+		}
+		if ch == nil {
+			continue
+		}
+		data.Channels = append(data.Channels, ch)
+		data.Total += u.Count
+	}
+	if len(data.Channels) == 0 {
+		return nil, nil
+	}
+
+	subject, body, err := render(data)
+	if err != nil {
+		return nil, err
+	}
+	return &Mail{
+		To:      setting.Email,
+		Subject: subject,
+		Body:    body,
+		Headers: map[string]string{
+			"List-Unsubscribe":      "<" + data.UnsubscribeURL + ">",
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		},
+	}, nil
+}
+
+// describeChannel 未読チャンネルの表示名とURLを返します
+//
+// 既に存在しないチャンネルの場合はnilを返します。
+func (s *serviceImpl) describeChannel(userID uuid.UUID, u *repository.UserUnreadChannel) (*digestChannel, error) {
+	if s.cm.IsPublicChannel(u.ChannelID) {
+		path := s.cm.PublicChannelTree().GetChannelPath(u.ChannelID)
+		return &digestChannel{
+			Name:  "#" + path,
+			URL:   s.origin + "/channels/" + path,
+			Count: u.Count,
+		}, nil
+	}
+
+	ch, err := s.cm.GetChannel(u.ChannelID)
+	if err != nil {
+		if err == channel.ErrChannelNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if !ch.IsDMChannel() {
+		// プライベートチャンネルはパスで開けないため、最初の未読メッセージへのリンクにする
+		messages, _, err := s.repo.GetMessages(repository.MessagesQuery{
+			Channel:        ch.ID,
+			Since:          optional.TimeFrom(u.Since),
+			Inclusive:      true,
+			Limit:          1,
+			Asc:            true,
+			DisablePreload: true,
+		})
+		if err != nil {
+			return nil, err
+		}
+		link := s.origin
+		if len(messages) > 0 {
+			link += "/messages/" + messages[0].ID.String()
+		}
+		return &digestChannel{
+			Name:  ch.Name,
+			URL:   link,
+			Count: u.Count,
+		}, nil
+	}
+
+	// DM
+	members, err := s.cm.GetDMChannelMembers(ch.ID)
+	if err != nil {
+		return nil, err
+	}
+	other := userID
+	for _, m := range members {
+		if m != userID {
+			other = m
+		}
+	}
+	otherUser, err := s.repo.GetUser(other, false)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &digestChannel{
+		Name:  "@" + otherUser.GetName(),
+		URL:   s.origin + "/users/" + url.PathEscape(otherUser.GetName()),
+		Count: u.Count,
+	}, nil
+}
+
+func periodLabel(f model.DigestFrequency) string {
+	switch f {
+	case model.DigestFrequencyWeekly:
+		return "この1週間"
+	default:
+		return "この1日"
+	}
+}
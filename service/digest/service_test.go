@@ -0,0 +1,259 @@
+package digest
+
+import (
+	"errors"
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+	"github.com/leandro-lugaresi/hub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/service/channel/mock_channel"
+	"github.com/traPtitech/traQ/service/cluster"
+	"github.com/traPtitech/traQ/service/counter"
+	"github.com/traPtitech/traQ/testutils"
+	"go.uber.org/zap"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testRepository struct {
+	testutils.EmptyTestRepository
+	users    map[uuid.UUID]*model.User
+	unreads  map[uuid.UUID][]*repository.UserUnreadChannel
+	settings []*model.UserDigestSetting
+	sentAt   map[uuid.UUID]time.Time
+	retryAt  map[uuid.UUID]time.Time
+	messages map[uuid.UUID][]*model.Message
+}
+
+func (r *testRepository) GetUser(id uuid.UUID, _ bool) (model.UserInfo, error) {
+	u, ok := r.users[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return u, nil
+}
+
+func (r *testRepository) GetUserUnreadChannels(userID uuid.UUID) ([]*repository.UserUnreadChannel, error) {
+	return r.unreads[userID], nil
+}
+
+func (r *testRepository) GetDueUserDigestSettings(frequency model.DigestFrequency, now time.Time, _ int) ([]*model.UserDigestSetting, error) {
+	var res []*model.UserDigestSetting
+	sentBefore := now.Add(-frequency.Interval())
+	for _, s := range r.settings {
+		if s.Frequency != frequency || !s.IsEmailVerified() {
+			continue
+		}
+		if t, ok := r.sentAt[s.UserID]; ok && t.After(sentBefore) {
+			continue
+		}
+		if t, ok := r.retryAt[s.UserID]; ok && t.After(now) {
+			continue
+		}
+		if s.LastSentAt == nil || !s.LastSentAt.After(sentBefore) {
+			res = append(res, s)
+		}
+	}
+	return res, nil
+}
+
+func (r *testRepository) GetMessages(query repository.MessagesQuery) ([]*model.Message, bool, error) {
+	var res []*model.Message
+	for _, m := range r.messages[query.Channel] {
+		if !m.CreatedAt.Before(query.Since.Time) {
+			res = append(res, m)
+		}
+	}
+	if len(res) > query.Limit {
+		return res[:query.Limit], true, nil
+	}
+	return res, false, nil
+}
+
+func (r *testRepository) SetUserDigestSentAt(userID uuid.UUID, sentAt time.Time) error {
+	r.sentAt[userID] = sentAt
+	return nil
+}
+
+func (r *testRepository) SetUserDigestRetryAt(userID uuid.UUID, retryAt time.Time) error {
+	r.retryAt[userID] = retryAt
+	return nil
+}
+
+type testMailer struct {
+	mails []*Mail
+	// fail このアドレス宛ての送信を失敗させる
+	fail string
+	mu   sync.Mutex
+}
+
+func (m *testMailer) Send(mail *Mail) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if mail.To == m.fail {
+		return errors.New("failed to send")
+	}
+	m.mails = append(m.mails, mail)
+	return nil
+}
+
+func TestService_sendDueDigests(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+	lastSent := now.Add(-25 * time.Hour)
+	user := &model.User{ID: uuid.Must(uuid.NewV4()), Name: "user", DisplayName: "ユーザー", Status: model.UserAccountStatusActive}
+	other := &model.User{ID: uuid.Must(uuid.NewV4()), Name: "other", Status: model.UserAccountStatusActive}
+	idle := &model.User{ID: uuid.Must(uuid.NewV4()), Name: "idle", Status: model.UserAccountStatusActive}
+	publicCh := uuid.Must(uuid.NewV4())
+	oldCh := uuid.Must(uuid.NewV4())
+	dmCh := uuid.Must(uuid.NewV4())
+	privateCh := uuid.Must(uuid.NewV4())
+	quietCh := uuid.Must(uuid.NewV4())
+	firstUnread := &model.Message{ID: uuid.Must(uuid.NewV4()), ChannelID: privateCh, CreatedAt: now.Add(-3 * time.Hour)}
+
+	repo := &testRepository{
+		users: map[uuid.UUID]*model.User{user.ID: user, other.ID: other, idle.ID: idle},
+		unreads: map[uuid.UUID][]*repository.UserUnreadChannel{
+			user.ID: {
+				{ChannelID: publicCh, Count: 3, Noticeable: true, UpdatedAt: now.Add(-2 * time.Hour)},
+				{ChannelID: dmCh, Count: 1, Noticeable: true, UpdatedAt: now.Add(-1 * time.Hour)},
+				{ChannelID: privateCh, Count: 2, Noticeable: true, Since: firstUnread.CreatedAt, UpdatedAt: now.Add(-3 * time.Hour)},
+				{ChannelID: oldCh, Count: 5, Noticeable: true, UpdatedAt: now.Add(-48 * time.Hour)},
+				{ChannelID: quietCh, Count: 10, Noticeable: false, UpdatedAt: now},
+			},
+			idle.ID: {
+				{ChannelID: quietCh, Count: 10, Noticeable: false, UpdatedAt: now},
+			},
+		},
+		settings: []*model.UserDigestSetting{
+			{UserID: user.ID, Email: "user@example.com", Frequency: model.DigestFrequencyDaily, UnsubscribeToken: "token", EmailVerifiedAt: &lastSent, LastSentAt: &lastSent},
+			{UserID: idle.ID, Email: "idle@example.com", Frequency: model.DigestFrequencyDaily, UnsubscribeToken: "token2", EmailVerifiedAt: &lastSent, LastSentAt: &lastSent},
+			// 未確認のアドレスには送信しない
+			{UserID: other.ID, Email: "other@example.com", Frequency: model.DigestFrequencyDaily, UnsubscribeToken: "token3", VerificationToken: "verify", LastSentAt: &lastSent},
+		},
+		sentAt:   map[uuid.UUID]time.Time{},
+		retryAt:  map[uuid.UUID]time.Time{},
+		messages: map[uuid.UUID][]*model.Message{privateCh: {firstUnread}},
+	}
+
+	tree := mock_channel.NewMockTree(ctrl)
+	tree.EXPECT().GetChannelPath(publicCh).Return("general/random").AnyTimes()
+	cm := mock_channel.NewMockManager(ctrl)
+	cm.EXPECT().PublicChannelTree().Return(tree).AnyTimes()
+	cm.EXPECT().IsPublicChannel(publicCh).Return(true).AnyTimes()
+	cm.EXPECT().IsPublicChannel(dmCh).Return(false).AnyTimes()
+	cm.EXPECT().GetChannel(dmCh).Return(&model.Channel{ID: dmCh, ParentID: uuid.FromStringOrNil(model.DirectMessageChannelRootID)}, nil).AnyTimes()
+	cm.EXPECT().GetDMChannelMembers(dmCh).Return([]uuid.UUID{user.ID, other.ID}, nil).AnyTimes()
+	cm.EXPECT().IsPublicChannel(privateCh).Return(false).AnyTimes()
+	cm.EXPECT().GetChannel(privateCh).Return(&model.Channel{ID: privateCh, Name: "secret", ParentID: uuid.FromStringOrNil(model.PrivateChannelRootID)}, nil).AnyTimes()
+
+	mailer := &testMailer{}
+	s := NewService(repo, cm, counter.NewOnlineCounter(hub.New(), cluster.NewStandaloneBus()), cluster.NewStandaloneBus(), mailer, "https://traq.example.com", zap.NewNop()).(*serviceImpl)
+	s.sendDueDigests(now)
+
+	require.Len(t, mailer.mails, 1)
+	m := mailer.mails[0]
+	assert.Equal(t, "user@example.com", m.To)
+	assert.Equal(t, "[traQ] この1日の未読通知が6件あります", m.Subject)
+	assert.Equal(t, "<https://traq.example.com/api/v3/public/digest/unsubscribe?token=token>", m.Headers["List-Unsubscribe"])
+	assert.Contains(t, m.Body, "ユーザー さん")
+	assert.Contains(t, m.Body, "@other (1件)\n  https://traq.example.com/users/other\n")
+	assert.Contains(t, m.Body, "#general/random (3件)\n  https://traq.example.com/channels/general/random\n")
+	assert.Contains(t, m.Body, "secret (2件)\n  https://traq.example.com/messages/"+firstUnread.ID.String()+"\n")
+	assert.True(t, strings.Index(m.Body, "@other") < strings.Index(m.Body, "#general/random"), "channels should be sorted by update time")
+	assert.NotContains(t, m.Body, "(5件)")
+	assert.NotContains(t, m.Body, "(10件)")
+
+	// 送信しなかったユーザーも最終配信日時が更新される
+	assert.Equal(t, now, repo.sentAt[user.ID])
+	assert.Equal(t, now, repo.sentAt[idle.ID])
+	assert.NotContains(t, repo.sentAt, other.ID)
+
+	// 次の配信間隔までは送信されない
+	s.sendDueDigests(now.Add(time.Hour))
+	assert.Len(t, mailer.mails, 1)
+}
+
+func TestService_sendDueDigests_SendFailure(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+	lastSent := now.Add(-25 * time.Hour)
+	failing := &model.User{ID: uuid.Must(uuid.NewV4()), Name: "failing", Status: model.UserAccountStatusActive}
+	user := &model.User{ID: uuid.Must(uuid.NewV4()), Name: "user", Status: model.UserAccountStatusActive}
+	ch := uuid.Must(uuid.NewV4())
+	unreads := []*repository.UserUnreadChannel{{ChannelID: ch, Count: 1, Noticeable: true, UpdatedAt: now.Add(-time.Hour)}}
+
+	failed := &model.UserDigestSetting{UserID: failing.ID, Email: "failing@example.com", Frequency: model.DigestFrequencyDaily, EmailVerifiedAt: &lastSent, LastSentAt: &lastSent, FailedAttempts: 2}
+	repo := &testRepository{
+		users:   map[uuid.UUID]*model.User{failing.ID: failing, user.ID: user},
+		unreads: map[uuid.UUID][]*repository.UserUnreadChannel{failing.ID: unreads, user.ID: unreads},
+		settings: []*model.UserDigestSetting{
+			failed,
+			{UserID: user.ID, Email: "user@example.com", Frequency: model.DigestFrequencyDaily, EmailVerifiedAt: &lastSent, LastSentAt: &lastSent},
+		},
+		sentAt:  map[uuid.UUID]time.Time{},
+		retryAt: map[uuid.UUID]time.Time{},
+	}
+
+	tree := mock_channel.NewMockTree(ctrl)
+	tree.EXPECT().GetChannelPath(ch).Return("general").AnyTimes()
+	cm := mock_channel.NewMockManager(ctrl)
+	cm.EXPECT().PublicChannelTree().Return(tree).AnyTimes()
+	cm.EXPECT().IsPublicChannel(ch).Return(true).AnyTimes()
+
+	mailer := &testMailer{fail: "failing@example.com"}
+	s := NewService(repo, cm, counter.NewOnlineCounter(hub.New(), cluster.NewStandaloneBus()), cluster.NewStandaloneBus(), mailer, "https://traq.example.com", zap.NewNop()).(*serviceImpl)
+	s.sendDueDigests(now)
+
+	// 失敗したユーザーの後も送信を続ける
+	require.Len(t, mailer.mails, 1)
+	assert.Equal(t, "user@example.com", mailer.mails[0].To)
+	assert.NotContains(t, repo.sentAt, failing.ID)
+	assert.Equal(t, now.Add(40*time.Minute), repo.retryAt[failing.ID])
+
+	// 再試行間隔が経過するまでは再送しない
+	mailer.fail = ""
+	s.sendDueDigests(now.Add(30 * time.Minute))
+	assert.Len(t, mailer.mails, 1)
+	s.sendDueDigests(now.Add(40 * time.Minute))
+	if assert.Len(t, mailer.mails, 2) {
+		assert.Equal(t, "failing@example.com", mailer.mails[1].To)
+	}
+}
+
+func TestService_SendVerificationMail(t *testing.T) {
+	t.Parallel()
+
+	mailer := &testMailer{}
+	s := NewService(&testRepository{}, nil, nil, cluster.NewStandaloneBus(), mailer, "https://traq.example.com", zap.NewNop())
+
+	verifiedAt := time.Now()
+	require.NoError(t, s.SendVerificationMail(&model.UserDigestSetting{Email: "verified@example.com", EmailVerifiedAt: &verifiedAt}))
+	require.NoError(t, s.SendVerificationMail(&model.UserDigestSetting{Frequency: model.DigestFrequencyNone}))
+	assert.Empty(t, mailer.mails)
+
+	require.NoError(t, s.SendVerificationMail(&model.UserDigestSetting{Email: "test@example.com", VerificationToken: "token"}))
+	require.Len(t, mailer.mails, 1)
+	assert.Equal(t, "test@example.com", mailer.mails[0].To)
+	assert.Contains(t, mailer.mails[0].Body, "https://traq.example.com/api/v3/public/digest/verify?token=token")
+}
+
+func TestRetryBackoff(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 10*time.Minute, retryBackoff(0))
+	assert.Equal(t, 20*time.Minute, retryBackoff(1))
+	assert.Equal(t, 80*time.Minute, retryBackoff(3))
+	assert.Equal(t, 24*time.Hour, retryBackoff(100))
+}
@@ -0,0 +1,83 @@
+package digest
+
+import (
+	"strings"
+	"text/template"
+)
+
+// digestChannel ダイジェストに載せるチャンネル
+type digestChannel struct {
+	// Name 表示名 (#general/random, @user, プライベートチャンネル名)
+	Name string
+	// URL チャンネルのURL
+	URL string
+	// Count 未読メッセージ数
+	Count int
+}
+
+// digestData ダイジェストメールのテンプレートデータ
+type digestData struct {
+	DisplayName    string
+	Period         string
+	Total          int
+	Channels       []*digestChannel
+	Origin         string
+	UnsubscribeURL string
+}
+
+// verificationData メールアドレス確認メールのテンプレートデータ
+type verificationData struct {
+	Origin    string
+	VerifyURL string
+}
+
+var (
+	subjectTemplate = template.Must(template.New("subject").Parse(
+		`[traQ] {{.Period}}の未読通知が{{.Total}}件あります`,
+	))
+	bodyTemplate = template.Must(template.New("body").Parse(`{{.DisplayName}} さん
+
+{{.Period}}に、通知対象の未読メッセージが{{.Total}}件届いています。
+
+{{range .Channels -}}
+{{.Name}} ({{.Count}}件)
+  {{.URL}}
+{{end}}
+traQ: {{.Origin}}
+
+--
+このメールはダイジェストメール設定に基づいて送信されています。
+配信停止: {{.UnsubscribeURL}}
+`))
+	verificationSubject  = "[traQ] ダイジェストメールの送信先アドレスの確認"
+	verificationTemplate = template.Must(template.New("verification").Parse(`traQ のダイジェストメールの送信先としてこのアドレスが登録されました。
+
+以下のリンクを開いてアドレスを確認すると、ダイジェストメールの配信が始まります。
+{{.VerifyURL}}
+
+心当たりが無い場合は、このメールを破棄してください。確認されない限り、このアドレスにダイジェストメールは送信されません。
+
+traQ: {{.Origin}}
+`))
+)
+
+// render ダイジェストメールの件名と本文を生成します
+func render(data *digestData) (subject, body string, err error) {
+	var sb, bb strings.Builder
+	if err := subjectTemplate.Execute(&sb, data); err != nil {
+		return "", "", err
+	}
+	if err := bodyTemplate.Execute(&bb, data); err != nil {
+		return "", "", err
+	}
+	return sb.String(), bb.String(), nil
+}
+
+// renderVerification メールアドレス確認メールの件名と本文を生成します
+func renderVerification(data *verificationData) (subject, body string, err error) {
+	var bb strings.Builder
+	if err := verificationTemplate.Execute(&bb, data); err != nil {
+		return "", "", err
+	}
+	return verificationSubject, bb.String(), nil
+}
@@ -0,0 +1,11 @@
+package digest
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var digestSendCounter = promauto.NewCounter(prometheus.CounterOpts{
+	Namespace: "traq",
+	Name:      "digest_mail_send_count_total",
+})
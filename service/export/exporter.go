@@ -0,0 +1,131 @@
+package export
+
+import (
+	"errors"
+	"github.com/gofrs/uuid"
+	"github.com/traPtitech/traQ/utils/optional"
+	"io"
+	"time"
+)
+
+const (
+	// FormatVersion エクスポートアーカイブのフォーマットバージョン
+	FormatVersion = 1
+
+	defaultBatchSize = 1000
+)
+
+// ErrInvalidPeriod 期間の指定が不正です
+var ErrInvalidPeriod = errors.New("invalid period")
+
+// Exporter データエクスポーター
+//
+// アーカイブは以下のファイルを含むZIPとして出力されます。
+//
+// 	manifest.json  エクスポート条件
+// 	channels.jsonl チャンネル(1行1チャンネル)
+// 	messages.jsonl スタンプ・編集履歴付きメッセージ(1行1メッセージ)
+// 	files.jsonl    ファイルメタデータ(1行1ファイル)
+// 	files/{id}     ファイル本体(IncludeFilesが有効な場合のみ)
+type Exporter interface {
+	// Export 指定した条件でデータをZIPアーカイブとしてwに逐次書き出します
+	//
+	// 成功した場合、nilを返します。
+	// SinceとUntilの前後関係が不正な場合、ErrInvalidPeriodを返します。
+	// 書き出し途中でエラーが発生した場合、wには不完全なアーカイブが書き出されています。
+	Export(w io.Writer, opts Options) error
+}
+
+// Options エクスポート条件
+type Options struct {
+	// UserID 指定した場合、そのユーザーのDMと、そのユーザーがアクセス可能なチャンネルへの本人の投稿のみを出力します
+	//
+	// 非表示のメッセージの本文と編集履歴は出力しません。
+	UserID optional.UUID
+	// IncludeFiles ファイル本体を含めるかどうか
+	IncludeFiles bool
+	// Since この日時以降に作成されたメッセージ・ファイルのみを出力します
+	Since optional.Time
+	// Until この日時以前に作成されたメッセージ・ファイルのみを出力します
+	Until optional.Time
+}
+
+// Validate 条件を検証します
+func (o Options) Validate() error {
+	if o.Since.Valid && o.Until.Valid && o.Since.Time.After(o.Until.Time) {
+		return ErrInvalidPeriod
+	}
+	return nil
+}
+
+// Manifest manifest.jsonの内容
+type Manifest struct {
+	Version      int           `json:"version"`
+	ExportedAt   time.Time     `json:"exportedAt"`
+	UserID       optional.UUID `json:"userId"`
+	IncludeFiles bool          `json:"includeFiles"`
+	Since        optional.Time `json:"since"`
+	Until        optional.Time `json:"until"`
+}
+
+// Channel channels.jsonlの1行
+type Channel struct {
+	ID        uuid.UUID   `json:"id"`
+	Name      string      `json:"name"`
+	ParentID  uuid.UUID   `json:"parentId"`
+	Topic     string      `json:"topic"`
+	Type      string      `json:"type"`
+	Archived  bool        `json:"archived"`
+	Force     bool        `json:"force"`
+	Members   []uuid.UUID `json:"members,omitempty"`
+	CreatorID uuid.UUID   `json:"creatorId"`
+	CreatedAt time.Time   `json:"createdAt"`
+	UpdatedAt time.Time   `json:"updatedAt"`
+}
+
+const (
+	channelTypePublic  = "public"
+	channelTypePrivate = "private"
+	channelTypeDM      = "dm"
+)
+
+// Message messages.jsonlの1行
+type Message struct {
+	ID        uuid.UUID      `json:"id"`
+	UserID    uuid.UUID      `json:"userId"`
+	ChannelID uuid.UUID      `json:"channelId"`
+	ParentID  optional.UUID  `json:"parentId"`
+	Content   string         `json:"content"`
+	Stamps    []MessageStamp `json:"stamps"`
+	History   []MessageEdit  `json:"history"`
+	CreatedAt time.Time      `json:"createdAt"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+}
+
+// MessageStamp メッセージに押されたスタンプ
+type MessageStamp struct {
+	StampID   uuid.UUID `json:"stampId"`
+	UserID    uuid.UUID `json:"userId"`
+	Count     int       `json:"count"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// MessageEdit メッセージの編集前の内容
+type MessageEdit struct {
+	Content  string    `json:"content"`
+	DateTime time.Time `json:"dateTime"`
+}
+
+// File files.jsonlの1行
+type File struct {
+	ID        uuid.UUID     `json:"id"`
+	Name      string        `json:"name"`
+	Mime      string        `json:"mime"`
+	Size      int64         `json:"size"`
+	MD5       string        `json:"md5"`
+	ChannelID optional.UUID `json:"channelId"`
+	CreatorID optional.UUID `json:"creatorId"`
+	Path      string        `json:"path,omitempty"`
+	CreatedAt time.Time     `json:"createdAt"`
+}
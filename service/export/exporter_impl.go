@@ -0,0 +1,396 @@
+package export
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"github.com/gofrs/uuid"
+	"github.com/jinzhu/gorm"
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/utils/storage"
+	"go.uber.org/zap"
+	"io"
+	"time"
+)
+
+var (
+	dmChannelRootUUID      = uuid.Must(uuid.FromString(model.DirectMessageChannelRootID))
+	privateChannelRootUUID = uuid.Must(uuid.FromString(model.PrivateChannelRootID))
+)
+
+type exporterImpl struct {
+	db        *gorm.DB
+	fs        storage.FileStorage
+	logger    *zap.Logger
+	batchSize int
+}
+
+// NewExporter Exporterを生成します
+func NewExporter(db *gorm.DB, fs storage.FileStorage, logger *zap.Logger) Exporter {
+	return &exporterImpl{
+		db:        db,
+		fs:        fs,
+		logger:    logger.Named("export"),
+		batchSize: defaultBatchSize,
+	}
+}
+
+// scope エクスポート対象の範囲
+type scope struct {
+	opts Options
+	// channels 出力するチャンネル
+	channels []*model.Channel
+	// dmIDs ユーザーモードでの対象ユーザーのDMチャンネルのID
+	dmIDs []uuid.UUID
+	// accessibleIDs ユーザーモードでの対象ユーザーがアクセス可能なチャンネルのID
+	accessibleIDs []uuid.UUID
+}
+
+// Export implements Exporter interface.
+func (e *exporterImpl) Export(w io.Writer, opts Options) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+
+	s, err := e.resolveScope(opts)
+	if err != nil {
+		return err
+	}
+
+	zw := zip.NewWriter(w)
+	if err := writeJSON(zw, "manifest.json", &Manifest{
+		Version:      FormatVersion,
+		ExportedAt:   time.Now(),
+		UserID:       opts.UserID,
+		IncludeFiles: opts.IncludeFiles,
+		Since:        opts.Since,
+		Until:        opts.Until,
+	}); err != nil {
+		return err
+	}
+	if err := e.writeChannels(zw, s); err != nil {
+		return err
+	}
+	if err := e.writeMessages(zw, s); err != nil {
+		return err
+	}
+	if err := e.writeFiles(zw, s); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// resolveScope エクスポート対象のチャンネルを求めます
+func (e *exporterImpl) resolveScope(opts Options) (*scope, error) {
+	s := &scope{opts: opts}
+	if !opts.UserID.Valid {
+		if err := e.db.Unscoped().Order("created_at, id").Find(&s.channels).Error; err != nil {
+			return nil, fmt.Errorf("failed to fetch channels: %w", err)
+		}
+		return s, nil
+	}
+
+	// ユーザーがメンバーのDM・プライベートチャンネル
+	var joined []*model.Channel
+	if err := e.db.
+		Where("id IN ?", e.db.Model(&model.UsersPrivateChannel{}).Select("channel_id").Where("user_id = ?", opts.UserID.UUID).SubQuery()).
+		Order("created_at, id").
+		Find(&joined).
+		Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch private channels: %w", err)
+	}
+	// ユーザーが投稿したことのある公開チャンネル
+	var posted []*model.Channel
+	if err := e.db.
+		Where("is_public = TRUE AND id IN ?", e.db.Model(&model.Message{}).Select("DISTINCT channel_id").Where("user_id = ?", opts.UserID.UUID).SubQuery()).
+		Order("created_at, id").
+		Find(&posted).
+		Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch channels: %w", err)
+	}
+
+	s.channels = append(joined, posted...)
+	for _, ch := range s.channels {
+		s.accessibleIDs = append(s.accessibleIDs, ch.ID)
+		if ch.IsDMChannel() {
+			s.dmIDs = append(s.dmIDs, ch.ID)
+		}
+	}
+	return s, nil
+}
+
+func (e *exporterImpl) writeChannels(zw *zip.Writer, s *scope) error {
+	w, err := zw.Create("channels.jsonl")
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+
+	for _, ch := range s.channels {
+		c := &Channel{
+			ID:        ch.ID,
+			Name:      ch.Name,
+			ParentID:  ch.ParentID,
+			Topic:     ch.Topic,
+			Type:      channelTypePublic,
+			Archived:  ch.IsArchived(),
+			Force:     ch.IsForced,
+			CreatorID: ch.CreatorID,
+			CreatedAt: ch.CreatedAt,
+			UpdatedAt: ch.UpdatedAt,
+		}
+		if !ch.IsPublic {
+			switch ch.ParentID {
+			case dmChannelRootUUID:
+				c.Type = channelTypeDM
+			case privateChannelRootUUID:
+				c.Type = channelTypePrivate
+			}
+			if err := e.db.Model(&model.UsersPrivateChannel{}).Where("channel_id = ?", ch.ID).Pluck("user_id", &c.Members).Error; err != nil {
+				return fmt.Errorf("failed to fetch channel members: %w", err)
+			}
+		}
+		if err := enc.Encode(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// filterMessages メッセージの対象範囲を絞り込みます
+func (s *scope) filterMessages(tx *gorm.DB) *gorm.DB {
+	if s.opts.UserID.Valid {
+		// 自分のDMの全メッセージと、アクセス可能なチャンネルへの自分の投稿
+		if len(s.accessibleIDs) == 0 {
+			return tx.Where("1 = 0")
+		}
+		if len(s.dmIDs) > 0 {
+			tx = tx.Where("channel_id IN (?) OR (user_id = ? AND channel_id IN (?))", s.dmIDs, s.opts.UserID.UUID, s.accessibleIDs)
+		} else {
+			tx = tx.Where("user_id = ? AND channel_id IN (?)", s.opts.UserID.UUID, s.accessibleIDs)
+		}
+	}
+	return s.filterPeriod(tx)
+}
+
+// filterFiles ファイルの対象範囲を絞り込みます
+func (s *scope) filterFiles(tx *gorm.DB) *gorm.DB {
+	tx = tx.Where("type = ?", model.FileTypeUserFile)
+	if s.opts.UserID.Valid {
+		// 自分がアップロードしたファイルと、自分のDMにアップロードされたファイル
+		if len(s.dmIDs) > 0 {
+			tx = tx.Where("creator_id = ? OR channel_id IN (?)", s.opts.UserID.UUID, s.dmIDs)
+		} else {
+			tx = tx.Where("creator_id = ?", s.opts.UserID.UUID)
+		}
+	}
+	return s.filterPeriod(tx)
+}
+
+func (s *scope) filterPeriod(tx *gorm.DB) *gorm.DB {
+	if s.opts.Since.Valid {
+		tx = tx.Where("created_at >= ?", s.opts.Since.Time)
+	}
+	if s.opts.Until.Valid {
+		tx = tx.Where("created_at <= ?", s.opts.Until.Time)
+	}
+	return tx
+}
+
+// hiddenMessages 本文を出力しないメッセージを求めます
+//
+// ユーザーモードでは、非表示のメッセージの本文と編集履歴はAPIと同様に出力しません。
+func (s *scope) hiddenMessages(messages []*model.Message) map[uuid.UUID]bool {
+	hidden := map[uuid.UUID]bool{}
+	if !s.opts.UserID.Valid {
+		return hidden
+	}
+	for _, m := range messages {
+		if m.Hidden {
+			hidden[m.ID] = true
+		}
+	}
+	return hidden
+}
+
+func (e *exporterImpl) writeMessages(zw *zip.Writer, s *scope) error {
+	w, err := zw.Create("messages.jsonl")
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+
+	var last *model.Message
+	for {
+		tx := s.filterMessages(e.db).Order("created_at, id").Limit(e.batchSize)
+		if last != nil {
+			tx = tx.Where("created_at > ? OR (created_at = ? AND id > ?)", last.CreatedAt, last.CreatedAt, last.ID)
+		}
+		var messages []*model.Message
+		if err := tx.Find(&messages).Error; err != nil {
+			return fmt.Errorf("failed to fetch messages: %w", err)
+		}
+		if len(messages) == 0 {
+			return nil
+		}
+
+		ids := make([]uuid.UUID, len(messages))
+		for i, m := range messages {
+			ids[i] = m.ID
+		}
+		var stamps []*model.MessageStamp
+		if err := e.db.Where("message_id IN (?)", ids).Order("created_at").Find(&stamps).Error; err != nil {
+			return fmt.Errorf("failed to fetch message stamps: %w", err)
+		}
+		var archived []*model.ArchivedMessage
+		if err := e.db.Where("message_id IN (?)", ids).Order("date_time").Find(&archived).Error; err != nil {
+			return fmt.Errorf("failed to fetch archived messages: %w", err)
+		}
+
+		hidden := s.hiddenMessages(messages)
+		out := make(map[uuid.UUID]*Message, len(messages))
+		for _, m := range messages {
+			out[m.ID] = &Message{
+				ID:        m.ID,
+				UserID:    m.UserID,
+				ChannelID: m.ChannelID,
+				ParentID:  m.ParentID,
+				Content:   m.Text,
+				Stamps:    []MessageStamp{},
+				History:   []MessageEdit{},
+				CreatedAt: m.CreatedAt,
+				UpdatedAt: m.UpdatedAt,
+			}
+			if hidden[m.ID] {
+				out[m.ID].Content = ""
+			}
+		}
+		for _, st := range stamps {
+			m := out[st.MessageID]
+			m.Stamps = append(m.Stamps, MessageStamp{
+				StampID:   st.StampID,
+				UserID:    st.UserID,
+				Count:     st.Count,
+				CreatedAt: st.CreatedAt,
+				UpdatedAt: st.UpdatedAt,
+			})
+		}
+		for _, am := range archived {
+			if hidden[am.MessageID] {
+				continue
+			}
+			m := out[am.MessageID]
+			m.History = append(m.History, MessageEdit{
+				Content:  am.Text,
+				DateTime: am.DateTime,
+			})
+		}
+
+		for _, m := range messages {
+			if err := enc.Encode(out[m.ID]); err != nil {
+				return err
+			}
+		}
+		last = messages[len(messages)-1]
+	}
+}
+
+// eachFiles 対象のファイルをバッチ毎に列挙します
+func (e *exporterImpl) eachFiles(s *scope, f func(files []*model.FileMeta) error) error {
+	var last *model.FileMeta
+	for {
+		tx := s.filterFiles(e.db).Order("created_at, id").Limit(e.batchSize)
+		if last != nil {
+			tx = tx.Where("created_at > ? OR (created_at = ? AND id > ?)", last.CreatedAt, last.CreatedAt, last.ID)
+		}
+		var files []*model.FileMeta
+		if err := tx.Find(&files).Error; err != nil {
+			return fmt.Errorf("failed to fetch files: %w", err)
+		}
+		if len(files) == 0 {
+			return nil
+		}
+		if err := f(files); err != nil {
+			return err
+		}
+		last = files[len(files)-1]
+	}
+}
+
+func (e *exporterImpl) writeFiles(zw *zip.Writer, s *scope) error {
+	w, err := zw.Create("files.jsonl")
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+
+	err = e.eachFiles(s, func(files []*model.FileMeta) error {
+		for _, f := range files {
+			out := &File{
+				ID:        f.ID,
+				Name:      f.Name,
+				Mime:      f.Mime,
+				Size:      f.Size,
+				MD5:       f.Hash,
+				ChannelID: f.ChannelID,
+				CreatorID: f.CreatorID,
+				CreatedAt: f.CreatedAt,
+			}
+			if s.opts.IncludeFiles {
+				out.Path = filePath(f.ID)
+			}
+			if err := enc.Encode(out); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil || !s.opts.IncludeFiles {
+		return err
+	}
+
+	// ファイル本体
+	return e.eachFiles(s, func(files []*model.FileMeta) error {
+		for _, f := range files {
+			if err := e.writeFileBlob(zw, f); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (e *exporterImpl) writeFileBlob(zw *zip.Writer, f *model.FileMeta) error {
+	r, err := e.fs.OpenFileByKey(f.ID.String(), f.Type)
+	if err != nil {
+		if err == storage.ErrFileNotFound {
+			e.logger.Warn("file blob not found", zap.Stringer("fileId", f.ID))
+			return nil
+		}
+		return fmt.Errorf("failed to open file %s: %w", f.ID, err)
+	}
+	defer r.Close()
+
+	w, err := zw.CreateHeader(&zip.FileHeader{
+		Name:     filePath(f.ID),
+		Method:   zip.Store, // 多くは圧縮済みの形式なので無圧縮で格納
+		Modified: f.CreatedAt,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, r)
+	return err
+}
+
+func writeJSON(zw *zip.Writer, name string, v interface{}) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	return json.NewEncoder(w).Encode(v)
+}
+
+func filePath(id uuid.UUID) string {
+	return "files/" + id.String()
+}
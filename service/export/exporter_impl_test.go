@@ -0,0 +1,199 @@
+package export
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	stdjson "encoding/json"
+	"fmt"
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/gofrs/uuid"
+	"github.com/jinzhu/gorm"
+	"github.com/leandro-lugaresi/hub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traPtitech/traQ/migration"
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/service/rbac/role"
+	"github.com/traPtitech/traQ/utils/optional"
+	"github.com/traPtitech/traQ/utils/random"
+	"github.com/traPtitech/traQ/utils/set"
+	"go.uber.org/zap"
+	"os"
+	"testing"
+)
+
+const dbPrefix = "traq-test-export-"
+
+var (
+	testDB   *gorm.DB
+	testRepo repository.Repository
+)
+
+func TestMain(m *testing.M) {
+	user := getEnvOrDefault("MARIADB_USERNAME", "root")
+	pass := getEnvOrDefault("MARIADB_PASSWORD", "password")
+	host := getEnvOrDefault("MARIADB_HOSTNAME", "127.0.0.1")
+	port := getEnvOrDefault("MARIADB_PORT", "3306")
+	if err := migration.CreateDatabasesIfNotExists("mysql", fmt.Sprintf("%s:%s@tcp(%s:%s)/?charset=utf8mb4&parseTime=true", user, pass, host, port), dbPrefix, "common"); err != nil {
+		panic(err)
+	}
+
+	db, err := gorm.Open("mysql", fmt.Sprintf("%s:%s@tcp(%s:%s)/%scommon?charset=utf8mb4&parseTime=true", user, pass, host, port, dbPrefix))
+	if err != nil {
+		panic(err)
+	}
+	db.DB().SetMaxOpenConns(20)
+	if err := migration.DropAll(db); err != nil {
+		panic(err)
+	}
+	repo, err := repository.NewGormRepository(db, hub.New(), zap.NewNop())
+	if err != nil {
+		panic(err)
+	}
+	if _, err := repo.Sync(); err != nil {
+		panic(err)
+	}
+	testDB = db
+	testRepo = repo
+
+	code := m.Run()
+	_ = db.Close()
+	os.Exit(code)
+}
+
+func getEnvOrDefault(env string, def string) string {
+	s := os.Getenv(env)
+	if len(s) == 0 {
+		return def
+	}
+	return s
+}
+
+func mustMakeUser(t *testing.T) uuid.UUID {
+	t.Helper()
+	u, err := testRepo.CreateUser(repository.CreateUserArgs{Name: random.AlphaNumeric(32), Role: role.User, IconFileID: uuid.Must(uuid.NewV4())})
+	require.NoError(t, err)
+	return u.GetID()
+}
+
+func mustMakeChannel(t *testing.T, parentID uuid.UUID, members []uuid.UUID, dm bool) uuid.UUID {
+	t.Helper()
+	ch, err := testRepo.CreateChannel(model.Channel{
+		Name:      random.AlphaNumeric(20),
+		ParentID:  parentID,
+		IsVisible: true,
+	}, set.UUIDSetFromArray(members), dm)
+	require.NoError(t, err)
+	return ch.ID
+}
+
+func mustMakeMessage(t *testing.T, userID, channelID uuid.UUID) uuid.UUID {
+	t.Helper()
+	m, err := testRepo.CreateMessage(userID, channelID, "popopo")
+	require.NoError(t, err)
+	return m.ID
+}
+
+func TestExporterImpl_resolveScope(t *testing.T) {
+	t.Parallel()
+	e := NewExporter(testDB, nil, zap.NewNop()).(*exporterImpl)
+
+	user := mustMakeUser(t)
+	other := mustMakeUser(t)
+	third := mustMakeUser(t)
+
+	public := mustMakeChannel(t, uuid.Nil, nil, false)
+	otherPublic := mustMakeChannel(t, uuid.Nil, nil, false)
+	dm := mustMakeChannel(t, uuid.Nil, []uuid.UUID{user, other}, true)
+	otherDM := mustMakeChannel(t, uuid.Nil, []uuid.UUID{other, third}, true)
+	private := mustMakeChannel(t, privateChannelRootUUID, []uuid.UUID{user, other}, false)
+	otherPrivate := mustMakeChannel(t, privateChannelRootUUID, []uuid.UUID{other, third}, false)
+
+	userPublic := mustMakeMessage(t, user, public)
+	mustMakeMessage(t, other, public)
+	mustMakeMessage(t, other, otherPublic)
+	userDM := mustMakeMessage(t, user, dm)
+	otherInDM := mustMakeMessage(t, other, dm)
+	mustMakeMessage(t, other, otherDM)
+	userPrivate := mustMakeMessage(t, user, private)
+	mustMakeMessage(t, other, private)
+	mustMakeMessage(t, other, otherPrivate)
+
+	s, err := e.resolveScope(Options{UserID: optional.UUIDFrom(user)})
+	require.NoError(t, err)
+
+	channelIDs := make([]uuid.UUID, len(s.channels))
+	for i, ch := range s.channels {
+		channelIDs[i] = ch.ID
+	}
+	assert.ElementsMatch(t, []uuid.UUID{public, dm, private}, channelIDs)
+	assert.ElementsMatch(t, []uuid.UUID{dm}, s.dmIDs)
+	assert.ElementsMatch(t, []uuid.UUID{public, dm, private}, s.accessibleIDs)
+
+	// 自分のDMの全メッセージと自分の投稿のみで、他人のプライベートチャンネルへの投稿は含まない
+	var messageIDs []uuid.UUID
+	require.NoError(t, s.filterMessages(testDB.Model(&model.Message{})).Pluck("id", &messageIDs).Error)
+	assert.ElementsMatch(t, []uuid.UUID{userPublic, userDM, otherInDM, userPrivate}, messageIDs)
+}
+
+func TestExporterImpl_Export(t *testing.T) {
+	t.Parallel()
+	e := NewExporter(testDB, nil, zap.NewNop())
+
+	user := mustMakeUser(t)
+	other := mustMakeUser(t)
+	dm := mustMakeChannel(t, uuid.Nil, []uuid.UUID{user, other}, true)
+	hidden := mustMakeMessage(t, other, dm)
+	require.NoError(t, testRepo.UpdateMessage(hidden, "edited"))
+	require.NoError(t, testRepo.SetMessageHidden(hidden, true))
+	visible := mustMakeMessage(t, other, dm)
+
+	readMessages := func(t *testing.T, opts Options) map[uuid.UUID]*Message {
+		t.Helper()
+		var buf bytes.Buffer
+		require.NoError(t, e.Export(&buf, opts))
+		zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		require.NoError(t, err)
+
+		messages := map[uuid.UUID]*Message{}
+		for _, f := range zr.File {
+			if f.Name != "messages.jsonl" {
+				continue
+			}
+			r, err := f.Open()
+			require.NoError(t, err)
+			sc := bufio.NewScanner(r)
+			for sc.Scan() {
+				var m Message
+				require.NoError(t, stdjson.Unmarshal(sc.Bytes(), &m))
+				messages[m.ID] = &m
+			}
+			require.NoError(t, sc.Err())
+			_ = r.Close()
+		}
+		return messages
+	}
+
+	t.Run("user mode", func(t *testing.T) {
+		t.Parallel()
+		messages := readMessages(t, Options{UserID: optional.UUIDFrom(user)})
+		if assert.Contains(t, messages, hidden) {
+			assert.Empty(t, messages[hidden].Content)
+			assert.Empty(t, messages[hidden].History)
+		}
+		if assert.Contains(t, messages, visible) {
+			assert.Equal(t, "popopo", messages[visible].Content)
+		}
+	})
+
+	t.Run("full", func(t *testing.T) {
+		t.Parallel()
+		messages := readMessages(t, Options{})
+		if assert.Contains(t, messages, hidden) {
+			assert.Equal(t, "edited", messages[hidden].Content)
+			assert.Len(t, messages[hidden].History, 1)
+		}
+	})
+}
@@ -0,0 +1,37 @@
+package export
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/traPtitech/traQ/utils/optional"
+	"testing"
+	"time"
+)
+
+func TestOptions_Validate(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	cases := []struct {
+		name  string
+		opts  Options
+		valid bool
+	}{
+		{"empty", Options{}, true},
+		{"since only", Options{Since: optional.TimeFrom(now)}, true},
+		{"until only", Options{Until: optional.TimeFrom(now)}, true},
+		{"since < until", Options{Since: optional.TimeFrom(now), Until: optional.TimeFrom(now.Add(time.Hour))}, true},
+		{"since = until", Options{Since: optional.TimeFrom(now), Until: optional.TimeFrom(now)}, true},
+		{"since > until", Options{Since: optional.TimeFrom(now.Add(time.Hour)), Until: optional.TimeFrom(now)}, false},
+	}
+	for _, cc := range cases {
+		cc := cc
+		t.Run(cc.name, func(t *testing.T) {
+			t.Parallel()
+			if cc.valid {
+				assert.NoError(t, cc.opts.Validate())
+			} else {
+				assert.EqualError(t, cc.opts.Validate(), ErrInvalidPeriod.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package fcm
+
+import (
+	"github.com/traPtitech/traQ/utils/set"
+)
+
+type compositeClient []Client
+
+// NewCompositeClient 複数のクライアントに同じ通知を送信するクライアントを生成します
+func NewCompositeClient(clients ...Client) Client {
+	return compositeClient(clients)
+}
+
+func (c compositeClient) Send(targetUserIDs set.UUID, payload *Payload, withUnreadCount bool) {
+	for _, client := range c {
+		client.Send(targetUserIDs, payload, withUnreadCount)
+	}
+}
+
+func (c compositeClient) Close() {
+	for _, client := range c {
+		client.Close()
+	}
+}
@@ -25,6 +25,10 @@ var (
 		Namespace: "firebase",
 		Name:      "fcm_batch_request_count_total",
 	}, []string{"result"})
+	webPushSendCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "traq",
+		Name:      "webpush_send_count_total",
+	}, []string{"result"})
 	messageTTL       = messageTTLSeconds * time.Second
 	messageTTLString = strconv.Itoa(messageTTLSeconds)
 
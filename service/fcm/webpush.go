@@ -0,0 +1,187 @@
+package fcm
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/service/counter"
+	"github.com/traPtitech/traQ/utils/set"
+	"github.com/traPtitech/traQ/utils/webpush"
+	"go.uber.org/zap"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	webPushWorkers   = 4
+	webPushQueueSize = 1000
+	webPushTimeout   = 10 * time.Second
+)
+
+type webPushMessage struct {
+	sub  *model.WebPushSubscription
+	data []byte
+}
+
+type webPushClient struct {
+	vapid         *webpush.VAPID
+	repo          repository.Repository
+	logger        *zap.Logger
+	unreadCounter counter.UnreadMessageCounter
+	client        *http.Client
+	queue         chan *webPushMessage
+	closed        bool
+	mu            sync.RWMutex
+	wg            sync.WaitGroup
+}
+
+// NewWebPushClient 標準Web Push(RFC 8030, 8291, 8292)で通知を送信するクライアントを生成します
+func NewWebPushClient(repo repository.Repository, logger *zap.Logger, unreadCounter counter.UnreadMessageCounter, vapid *webpush.VAPID) Client {
+	c := &webPushClient{
+		vapid:         vapid,
+		repo:          repo,
+		logger:        logger.Named("webpush"),
+		unreadCounter: unreadCounter,
+		client: &http.Client{
+			Timeout: webPushTimeout,
+			// 購読エンドポイントはクライアントが指定するため、リダイレクトで内部のURLに誘導されないようにする
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		queue: make(chan *webPushMessage, webPushQueueSize),
+	}
+	c.wg.Add(webPushWorkers)
+	for i := 0; i < webPushWorkers; i++ {
+		go c.worker()
+	}
+	return c
+}
+
+func (c *webPushClient) Close() {
+	c.mu.Lock()
+	if !c.closed {
+		c.closed = true
+		close(c.queue)
+	}
+	c.mu.Unlock()
+	c.wg.Wait()
+}
+
+func (c *webPushClient) Send(targetUserIDs set.UUID, payload *Payload, withUnreadCount bool) {
+	_ = c.send(targetUserIDs, payload, withUnreadCount)
+}
+
+func (c *webPushClient) send(targetUserIDs set.UUID, p *Payload, withUnreadCount bool) error {
+	logger := c.logger.With(zap.Reflect("payload", p))
+
+	subsMap, err := c.repo.GetWebPushSubscriptions(targetUserIDs)
+	if err != nil {
+		logger.Error("failed to GetWebPushSubscriptions", zap.Error(err), zap.Strings("target_user_ids", targetUserIDs.StringArray()))
+		return err
+	}
+	if len(subsMap) == 0 {
+		return nil
+	}
+
+	var messages []*webPushMessage
+	for uid, subs := range subsMap {
+		data := map[string]string{
+			"type":  p.Type,
+			"title": p.Title,
+			"body":  p.Body,
+			"path":  p.Path,
+			"tag":   p.Tag,
+			"icon":  p.Icon,
+		}
+		if p.Image.Valid {
+			data["image"] = p.Image.String
+		}
+		if withUnreadCount {
+			data["unread"] = strconv.Itoa(c.unreadCounter.Get(uid))
+		}
+		b, err := json.Marshal(data)
+		if err != nil {
+			return err
+		}
+		for _, sub := range subs {
+			messages = append(messages, &webPushMessage{sub: sub, data: b})
+		}
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.closed {
+		return errors.New("web push client has already been closed")
+	}
+	for _, m := range messages {
+		c.queue <- m
+	}
+	return nil
+}
+
+func (c *webPushClient) worker() {
+	defer c.wg.Done()
+	for m := range c.queue {
+		gone, err := c.sendOne(m)
+		if err != nil {
+			webPushSendCounter.WithLabelValues("error").Inc()
+			c.logger.Warn("webpush: "+err.Error(), zap.String("endpoint", m.sub.Endpoint))
+			continue
+		}
+		if gone {
+			webPushSendCounter.WithLabelValues("gone").Inc()
+			if err := c.repo.DeleteWebPushSubscriptions([]string{m.sub.Endpoint}); err != nil {
+				c.logger.Error("failed to DeleteWebPushSubscriptions", zap.Error(err), zap.String("endpoint", m.sub.Endpoint))
+			}
+			continue
+		}
+		webPushSendCounter.WithLabelValues("ok").Inc()
+	}
+}
+
+// sendOne 1つの購読にメッセージを送信します
+//
+// 購読が既に無効になっていた場合はgone=trueを返します。
+func (c *webPushClient) sendOne(m *webPushMessage) (gone bool, err error) {
+	body, err := webpush.Encrypt(m.sub.P256dh, m.sub.Auth, m.data)
+	if err != nil {
+		if err == webpush.ErrInvalidKey {
+			return true, nil
+		}
+		return false, err
+	}
+	authorization, err := c.vapid.Authorization(m.sub.Endpoint, time.Now())
+	if err != nil {
+		return false, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, m.sub.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Authorization", authorization)
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("TTL", messageTTLString)
+	req.Header.Set("Urgency", notificationPriority)
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer res.Body.Close()
+
+	switch {
+	case res.StatusCode == http.StatusNotFound || res.StatusCode == http.StatusGone:
+		return true, nil
+	case res.StatusCode >= 300:
+		return false, errors.New("push service responded " + res.Status)
+	default:
+		return false, nil
+	}
+}
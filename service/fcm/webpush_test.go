@@ -0,0 +1,126 @@
+package fcm
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/testutils"
+	"github.com/traPtitech/traQ/utils/set"
+	"github.com/traPtitech/traQ/utils/webpush"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type webPushTestRepository struct {
+	testutils.EmptyTestRepository
+	subs    map[uuid.UUID][]*model.WebPushSubscription
+	deleted []string
+	mu      sync.Mutex
+}
+
+func (r *webPushTestRepository) GetWebPushSubscriptions(userIDs set.UUID) (map[uuid.UUID][]*model.WebPushSubscription, error) {
+	res := map[uuid.UUID][]*model.WebPushSubscription{}
+	for id := range userIDs {
+		if subs, ok := r.subs[id]; ok {
+			res[id] = subs
+		}
+	}
+	return res, nil
+}
+
+func (r *webPushTestRepository) DeleteWebPushSubscriptions(endpoints []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.deleted = append(r.deleted, endpoints...)
+	return nil
+}
+
+type unreadCounter int
+
+func (c unreadCounter) Get(uuid.UUID) int { return int(c) }
+
+func (c unreadCounter) GetChanges(bool) map[uuid.UUID]int { return nil }
+
+func newTestSubscription(t *testing.T, userID uuid.UUID, endpoint string) *model.WebPushSubscription {
+	t.Helper()
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	auth := make([]byte, 16)
+	_, _ = rand.Read(auth)
+	return &model.WebPushSubscription{
+		ID:       uuid.Must(uuid.NewV4()),
+		UserID:   userID,
+		Endpoint: endpoint,
+		P256dh:   base64.RawURLEncoding.EncodeToString(elliptic.Marshal(elliptic.P256(), priv.X, priv.Y)),
+		Auth:     base64.RawURLEncoding.EncodeToString(auth),
+	}
+}
+
+func TestWebPushClient_Send(t *testing.T) {
+	t.Parallel()
+
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalECPrivateKey(priv)
+	require.NoError(t, err)
+	vapid, err := webpush.NewVAPID(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), "mailto:admin@example.com")
+	require.NoError(t, err)
+
+	var (
+		requests []*http.Request
+		mu       sync.Mutex
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r)
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/gone":
+			w.WriteHeader(http.StatusGone)
+			return
+		case "/redirect":
+			http.Redirect(w, r, "/internal", http.StatusTemporaryRedirect)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	user1 := uuid.Must(uuid.NewV4())
+	user2 := uuid.Must(uuid.NewV4())
+	user3 := uuid.Must(uuid.NewV4())
+	repo := &webPushTestRepository{subs: map[uuid.UUID][]*model.WebPushSubscription{
+		user1: {newTestSubscription(t, user1, server.URL+"/ok")},
+		user2: {newTestSubscription(t, user2, server.URL+"/gone")},
+		user3: {newTestSubscription(t, user3, server.URL+"/redirect")},
+	}}
+
+	c := NewCompositeClient(NewNullClient(), NewWebPushClient(repo, zap.NewNop(), unreadCounter(3), vapid))
+	c.Send(set.UUIDSetFromArray([]uuid.UUID{user1, user2, user3}), &Payload{Type: "new_message", Title: "title", Body: "body"}, true)
+	c.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	// リダイレクトは追従しない
+	require.Len(t, requests, 3)
+	for _, r := range requests {
+		assert.NotEqual(t, "/internal", r.URL.Path)
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "aes128gcm", r.Header.Get("Content-Encoding"))
+		assert.Equal(t, messageTTLString, r.Header.Get("TTL"))
+		assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"), "vapid t="))
+		assert.True(t, strings.HasSuffix(r.Header.Get("Authorization"), ", k="+vapid.PublicKey()))
+	}
+	assert.Equal(t, []string{server.URL + "/gone"}, repo.deleted)
+}
@@ -23,45 +23,49 @@ import (
 type eventHandler func(ns *Service, ev hub.Message)
 
 var handlerMap = map[string]eventHandler{
-	event.MessageCreated:            messageCreatedHandler,
-	event.MessageUpdated:            messageUpdatedHandler,
-	event.MessageDeleted:            messageDeletedHandler,
-	event.MessagePinned:             messagePinnedHandler,
-	event.MessageUnpinned:           messageUnpinnedHandler,
-	event.MessageStamped:            messageStampedHandler,
-	event.MessageUnstamped:          messageUnstampedHandler,
-	event.ChannelCreated:            channelCreatedHandler,
-	event.ChannelUpdated:            channelUpdatedHandler,
-	event.ChannelDeleted:            channelDeletedHandler,
-	event.ChannelStared:             channelStaredHandler,
-	event.ChannelUnstared:           channelUnstaredHandler,
-	event.ChannelRead:               channelReadHandler,
-	event.ChannelViewersChanged:     channelViewersChangedHandler,
-	event.ChannelSubscribersChanged: channelSubscribersChangedHandler,
-	event.UserCreated:               userCreatedHandler,
-	event.UserUpdated:               userUpdatedHandler,
-	event.UserIconUpdated:           userIconUpdatedHandler,
-	event.UserOnline:                userOnlineHandler,
-	event.UserOffline:               userOfflineHandler,
-	event.UserTagAdded:              userTagUpdatedHandler,
-	event.UserTagRemoved:            userTagUpdatedHandler,
-	event.UserTagUpdated:            userTagUpdatedHandler,
-	event.UserGroupCreated:          userGroupCreatedHandler,
-	event.UserGroupDeleted:          userGroupDeletedHandler,
-	event.UserGroupMemberAdded:      userGroupUpdatedHandler,
-	event.UserGroupMemberRemoved:    userGroupUpdatedHandler,
-	event.StampCreated:              stampCreatedHandler,
-	event.StampUpdated:              stampUpdatedHandler,
-	event.StampDeleted:              stampDeletedHandler,
-	event.StampPaletteCreated:       stampPaletteCreatedHandler,
-	event.StampPaletteUpdated:       stampPaletteUpdatedHandler,
-	event.StampPaletteDeleted:       stampPaletteDeletedHandler,
-	event.UserWebRTCv3StateChanged:  userWebRTCv3StateChangedHandler,
-	event.ClipFolderCreated:         clipFolderCreatedHandler,
-	event.ClipFolderUpdated:         clipFolderUpdatedHandler,
-	event.ClipFolderDeleted:         clipFolderDeletedHandler,
-	event.ClipFolderMessageDeleted:  clipFolderMessageDeletedHandler,
-	event.ClipFolderMessageAdded:    clipFolderMessageAddedHandler,
+	event.MessageCreated:                 messageCreatedHandler,
+	event.MessageReplied:                 messageRepliedHandler,
+	event.MessageUpdated:                 messageUpdatedHandler,
+	event.MessageDeleted:                 messageDeletedHandler,
+	event.MessagePinned:                  messagePinnedHandler,
+	event.MessageUnpinned:                messageUnpinnedHandler,
+	event.MessageStamped:                 messageStampedHandler,
+	event.MessageUnstamped:               messageUnstampedHandler,
+	event.ChannelCreated:                 channelCreatedHandler,
+	event.ChannelUpdated:                 channelUpdatedHandler,
+	event.ChannelDeleted:                 channelDeletedHandler,
+	event.ChannelStared:                  channelStaredHandler,
+	event.ChannelUnstared:                channelUnstaredHandler,
+	event.ChannelRead:                    channelReadHandler,
+	event.ChannelViewersChanged:          channelViewersChangedHandler,
+	event.ChannelSubscribersChanged:      channelSubscribersChangedHandler,
+	event.ChannelMemberAdded:             channelMemberAddedHandler,
+	event.ChannelMemberRemoved:           channelMemberRemovedHandler,
+	event.UserCreated:                    userCreatedHandler,
+	event.UserUpdated:                    userUpdatedHandler,
+	event.UserIconUpdated:                userIconUpdatedHandler,
+	event.UserOnline:                     userOnlineHandler,
+	event.UserOffline:                    userOfflineHandler,
+	event.UserTagAdded:                   userTagUpdatedHandler,
+	event.UserTagRemoved:                 userTagUpdatedHandler,
+	event.UserTagUpdated:                 userTagUpdatedHandler,
+	event.UserNotificationSettingUpdated: userNotificationSettingUpdatedHandler,
+	event.UserGroupCreated:               userGroupCreatedHandler,
+	event.UserGroupDeleted:               userGroupDeletedHandler,
+	event.UserGroupMemberAdded:           userGroupUpdatedHandler,
+	event.UserGroupMemberRemoved:         userGroupUpdatedHandler,
+	event.StampCreated:                   stampCreatedHandler,
+	event.StampUpdated:                   stampUpdatedHandler,
+	event.StampDeleted:                   stampDeletedHandler,
+	event.StampPaletteCreated:            stampPaletteCreatedHandler,
+	event.StampPaletteUpdated:            stampPaletteUpdatedHandler,
+	event.StampPaletteDeleted:            stampPaletteDeletedHandler,
+	event.UserWebRTCv3StateChanged:       userWebRTCv3StateChangedHandler,
+	event.ClipFolderCreated:              clipFolderCreatedHandler,
+	event.ClipFolderUpdated:              clipFolderUpdatedHandler,
+	event.ClipFolderDeleted:              clipFolderDeletedHandler,
+	event.ClipFolderMessageDeleted:       clipFolderMessageDeletedHandler,
+	event.ClipFolderMessageAdded:         clipFolderMessageAddedHandler,
 }
 
 func messageCreatedHandler(ns *Service, ev hub.Message) {
@@ -71,7 +75,7 @@ func messageCreatedHandler(ns *Service, ev hub.Message) {
 
 	chTree := ns.cm.PublicChannelTree()
 	chID := m.ChannelID
-	isDM := !chTree.IsChannelPresent(chID)
+	isDM := !chTree.IsChannelPresent(chID) // DM・プライベートチャンネル
 	forceNotify := chTree.IsForceChannel(chID)
 
 	// 投稿ユーザー情報を取得
@@ -81,6 +85,15 @@ func messageCreatedHandler(ns *Service, ev hub.Message) {
 		return
 	}
 
+	var privateCh *model.Channel
+	if isDM {
+		privateCh, err = getPrivateChannel(ns, chID)
+		if err != nil {
+			logger.Error("failed to GetChannel", zap.Error(err), zap.Stringer("channelId", chID)) // 失敗
+			return
+		}
+	}
+
 	fcmPayload := &fcm.Payload{
 		Type: "new_message",
 		Icon: fmt.Sprintf("%s/api/v3/public/icon/%s", ns.origin, strings.ReplaceAll(mUser.GetName(), "#", "%23")),
@@ -99,13 +112,19 @@ func messageCreatedHandler(ns *Service, ev hub.Message) {
 	noticeable := set.UUID{}    // noticeableな未読追加対象のユーザー
 
 	// メッセージボディ作成
-	if !isDM {
+	switch {
+	case !isDM:
 		// 公開チャンネル
 		path := chTree.GetChannelPath(chID)
 		fcmPayload.Title = "#" + path
 		fcmPayload.Path = "/channels/" + path
 		fcmPayload.SetBodyWithEllipsis(mUser.GetResponseDisplayName() + ": " + parsed.OneLine())
-	} else {
+	case privateCh != nil:
+		// プライベートチャンネル
+		fcmPayload.Title = privateCh.Name
+		fcmPayload.Path = "/messages/" + m.ID.String()
+		fcmPayload.SetBodyWithEllipsis(mUser.GetResponseDisplayName() + ": " + parsed.OneLine())
+	default:
 		// DM
 		fcmPayload.Title = "@" + mUser.GetResponseDisplayName()
 		fcmPayload.Path = "/users/" + mUser.GetName()
@@ -131,7 +150,7 @@ func messageCreatedHandler(ns *Service, ev hub.Message) {
 		markedUsers.Add(users...)
 		noticeable.Add(users...)
 
-	case isDM: // DM
+	case isDM: // DM・プライベートチャンネル
 		users, err := ns.repo.GetUserIDs(q.CMemberOf(chID))
 		if err != nil {
 			logger.Error("failed to GetPrivateChannelMemberIDs", zap.Error(err), zap.Stringer("channelId", m.ChannelID)) // 失敗
@@ -157,31 +176,14 @@ func messageCreatedHandler(ns *Service, ev hub.Message) {
 		}
 		markedUsers.Add(mark...)
 
-		// ユーザーグループ・メンションユーザー取得
-		for _, uid := range parsed.Mentions {
-			user, err := ns.repo.GetUser(uid, false)
-			if err != nil {
-				logger.Error("failed to GetUser", zap.Error(err), zap.Stringer("userId", uid)) // 失敗
-				continue
-			}
-			// 凍結ユーザーの除外
-			if !user.IsActive() {
-				continue
-			}
-			notifiedUsers.Add(uid)
-			markedUsers.Add(uid)
-			noticeable.Add(uid)
-		}
-		for _, gid := range parsed.GroupMentions {
-			gs, err := ns.repo.GetUserIDs(q.GMemberOf(gid))
-			if err != nil {
-				logger.Error("failed to GetUserGroupMemberIDs", zap.Error(err), zap.Stringer("groupId", gid)) // 失敗
-				return
-			}
-			notifiedUsers.Add(gs...)
-			markedUsers.Add(gs...)
-			noticeable.Add(gs...)
+		// メンション・グループメンション・キーワード通知ユーザー取得
+		mentioned, muted, err := getMentionTargets(ns, q, parsed, logger)
+		if err != nil {
+			return
 		}
+		notifiedUsers.Plus(mentioned)
+		markedUsers.Plus(mentioned, muted) // ミュートしているユーザーは未読のみ追加
+		noticeable.Plus(mentioned)
 	}
 
 	// チャンネル閲覧者取得
@@ -202,20 +204,121 @@ func messageCreatedHandler(ns *Service, ev hub.Message) {
 	}
 
 	// WS送信
-	var targetFunc ws.TargetFunc
+	var target ws.Target
 	if isDM {
-		targetFunc = ws.TargetUserSets(notifiedUsers)
+		target = ws.Or(
+			ws.TargetUserSets(notifiedUsers),
+			ws.TargetChannelViewers(chID), // チャンネルのメッセージストリーム購読者
+		)
 	} else {
-		targetFunc = ws.Or(
+		target = ws.Or(
 			ws.TargetUserSets(notifiedUsers, viewers),
+			ws.TargetChannelViewers(chID), // チャンネルのメッセージストリーム購読者
 			ws.TargetTimelineStreamingEnabled(),
 		)
 	}
-	go ns.ws.WriteMessage(ssePayload.EventType, ssePayload.Payload, targetFunc)
+	go ns.ws.WriteMessage(ssePayload.EventType, ssePayload.Payload, target)
 
 	// FCM送信
 	targets := notifiedUsers.Clone()
 	targets.Remove(m.UserID)
+	if err := excludeDoNotDisturbUsers(ns, targets); err != nil {
+		logger.Error("failed to GetUserNotificationSettings", zap.Error(err)) // 失敗
+	}
+	ns.fcm.Send(targets, fcmPayload, true)
+}
+
+func messageRepliedHandler(ns *Service, ev hub.Message) {
+	m := ev.Fields["message"].(*model.Message)
+	parentID := ev.Fields["parent_id"].(uuid.UUID)
+	parsed := ev.Fields["parse_result"].(*message.ParseResult)
+	logger := ns.logger.With(zap.Stringer("messageId", m.ID), zap.Stringer("parentId", parentID))
+
+	chTree := ns.cm.PublicChannelTree()
+	chID := m.ChannelID
+	isDM := !chTree.IsChannelPresent(chID)
+
+	// 投稿ユーザー情報を取得
+	mUser, err := ns.repo.GetUser(m.UserID, false)
+	if err != nil {
+		logger.Error("failed to GetUser", zap.Error(err), zap.Stringer("userId", m.UserID)) // 失敗
+		return
+	}
+
+	fcmPayload := &fcm.Payload{
+		Type: "new_message",
+		Icon: fmt.Sprintf("%s/api/v3/public/icon/%s", ns.origin, strings.ReplaceAll(mUser.GetName(), "#", "%23")),
+		Tag:  "t:" + parentID.String(),
+		Path: "/messages/" + parentID.String(),
+	}
+	ssePayload := &sse.EventData{
+		EventType: "MESSAGE_CREATED",
+		Payload: map[string]interface{}{
+			"id":       m.ID,
+			"parentId": parentID,
+		},
+	}
+
+	// メッセージボディ作成
+	if !isDM {
+		fcmPayload.Title = "#" + chTree.GetChannelPath(chID) + " のスレッド"
+	} else if privateCh, err := getPrivateChannel(ns, chID); err != nil {
+		logger.Error("failed to GetChannel", zap.Error(err), zap.Stringer("channelId", chID)) // 失敗
+		return
+	} else if privateCh != nil {
+		fcmPayload.Title = privateCh.Name + " のスレッド"
+	} else {
+		fcmPayload.Title = "@" + mUser.GetResponseDisplayName() + " のスレッド"
+	}
+	fcmPayload.SetBodyWithEllipsis(mUser.GetResponseDisplayName() + ": " + parsed.OneLine())
+
+	// 対象者計算 (スレッド参加者とメンションされたユーザー)
+	q := repository.UsersQuery{}.Active().NotBot()
+	participants, err := ns.repo.GetUserIDs(q.ThreadParticipantOf(parentID))
+	if err != nil {
+		logger.Error("failed to GetUserIDs", zap.Error(err)) // 失敗
+		return
+	}
+	notifiedUsers := set.UUIDSetFromArray(participants)
+	markedUsers := notifiedUsers.Clone()
+	if !isDM {
+		mentioned, muted, err := getMentionTargets(ns, q, parsed, logger)
+		if err != nil {
+			return
+		}
+		notifiedUsers.Plus(mentioned)
+		markedUsers.Plus(mentioned, muted) // ミュートしているユーザーは未読のみ追加
+	}
+	notifiedUsers.Remove(m.UserID)
+	markedUsers.Remove(m.UserID)
+
+	// チャンネル閲覧者取得
+	viewers := set.UUID{}
+	for uid, swt := range ns.vm.GetChannelViewers(chID) {
+		viewers.Add(uid)
+		if swt.State > viewer.StateNone {
+			markedUsers.Remove(uid) // 閲覧中ユーザーは未読管理から外す
+		}
+	}
+
+	// 未読追加
+	for id := range markedUsers {
+		if err := ns.repo.SetMessageUnread(id, m.ID, notifiedUsers.Contains(id)); err != nil {
+			logger.Error("failed to SetMessageUnread", zap.Error(err), zap.Stringer("user_id", id)) // 失敗
+		}
+	}
+
+	// WS送信
+	go ns.ws.WriteMessage(ssePayload.EventType, ssePayload.Payload, ws.Or(
+		ws.TargetUserSets(notifiedUsers, viewers),
+		ws.TargetChannelViewers(chID), // チャンネルのメッセージストリーム購読者
+	))
+
+	// FCM送信
+	targets := notifiedUsers.Clone()
+	if err := excludeDoNotDisturbUsers(ns, targets); err != nil {
+		logger.Error("failed to GetUserNotificationSettings", zap.Error(err)) // 失敗
+	}
 	ns.fcm.Send(targets, fcmPayload, true)
 }
 
@@ -228,19 +331,19 @@ func messageUpdatedHandler(ns *Service, ev hub.Message) {
 		},
 	}
 
-	var targetFunc ws.TargetFunc
+	var target ws.Target
 	if ns.cm.IsPublicChannel(cid) {
 		// 公開チャンネル
-		targetFunc = ws.Or(
+		target = ws.Or(
 			ws.TargetChannelViewers(cid),
 			ws.TargetTimelineStreamingEnabled(),
 		)
 	} else {
 		// DM
-		targetFunc = ws.TargetChannelViewers(cid)
+		target = ws.TargetChannelViewers(cid)
 	}
 
-	go ns.ws.WriteMessage(ssePayload.EventType, ssePayload.Payload, targetFunc)
+	go ns.ws.WriteMessage(ssePayload.EventType, ssePayload.Payload, target)
 }
 
 func messageDeletedHandler(ns *Service, ev hub.Message) {
@@ -252,19 +355,19 @@ func messageDeletedHandler(ns *Service, ev hub.Message) {
 		},
 	}
 
-	var targetFunc ws.TargetFunc
+	var target ws.Target
 	if ns.cm.IsPublicChannel(cid) {
 		// 公開チャンネル
-		targetFunc = ws.Or(
+		target = ws.Or(
 			ws.TargetChannelViewers(cid),
 			ws.TargetTimelineStreamingEnabled(),
 		)
 	} else {
 		// DM
-		targetFunc = ws.TargetChannelViewers(cid)
+		target = ws.TargetChannelViewers(cid)
 	}
 
-	go ns.ws.WriteMessage(ssePayload.EventType, ssePayload.Payload, targetFunc)
+	go ns.ws.WriteMessage(ssePayload.EventType, ssePayload.Payload, target)
 }
 
 func messagePinnedHandler(ns *Service, ev hub.Message) {
@@ -338,6 +441,32 @@ func channelDeletedHandler(ns *Service, ev hub.Message) {
 	})
 }
 
+func channelMemberAddedHandler(ns *Service, ev hub.Message) {
+	channelHandler(ns, ev, &sse.EventData{
+		EventType: "CHANNEL_UPDATED",
+		Payload: map[string]interface{}{
+			"id": ev.Fields["channel_id"].(uuid.UUID),
+		},
+	})
+}
+
+func channelMemberRemovedHandler(ns *Service, ev hub.Message) {
+	cid := ev.Fields["channel_id"].(uuid.UUID)
+	channelHandler(ns, ev, &sse.EventData{
+		EventType: "CHANNEL_UPDATED",
+		Payload: map[string]interface{}{
+			"id": cid,
+		},
+	})
+	// 削除されたユーザーからはチャンネルが見えなくなる
+	userMulticast(ns, ev.Fields["user_id"].(uuid.UUID), &sse.EventData{
+		EventType: "CHANNEL_DELETED",
+		Payload: map[string]interface{}{
+			"id": cid,
+		},
+	})
+}
+
 func channelStaredHandler(ns *Service, ev hub.Message) {
 	userMulticast(ns, ev.Fields["user_id"].(uuid.UUID), &sse.EventData{
 		EventType: "CHANNEL_STARED",
@@ -440,6 +569,11 @@ func userTagUpdatedHandler(ns *Service, ev hub.Message) {
 	})
 }
 
+func userNotificationSettingUpdatedHandler(ns *Service, _ hub.Message) {
+	ns.purgeKeywordSettings()
+	ns.bus.Publish(clusterTopic, nil)
+}
+
 func userGroupCreatedHandler(ns *Service, ev hub.Message) {
 	broadcast(ns, &sse.EventData{
 		EventType: "USER_GROUP_CREATED",
@@ -600,6 +734,111 @@ func channelHandler(ns *Service, ev hub.Message, ssePayload *sse.EventData) {
 	}
 }
 
+// getPrivateChannel 指定したチャンネルがDM以外のプライベートチャンネルの場合はそれを返します
+func getPrivateChannel(ns *Service, cid uuid.UUID) (*model.Channel, error) {
+	ch, err := ns.cm.GetChannel(cid)
+	if err != nil {
+		return nil, err
+	}
+	if !ch.IsPrivateChannel() {
+		return nil, nil
+	}
+	return ch, nil
+}
+
+// getGroupMentionTargets グループメンションの通知対象ユーザーを取得します
+//
+// グループへのメンションをミュートしているメンバーはtargetsではなくmutedに含まれます。
+// getMentionTargets メンション・グループメンション・キーワード通知の対象ユーザーを取得します
+//
+// グループメンションをミュートしているユーザーはmutedとして、通知対象とは別に返します。
+// 取得に失敗した場合はエラーをログに出力して返します。
+func getMentionTargets(ns *Service, q repository.UsersQuery, parsed *message.ParseResult, logger *zap.Logger) (notified, muted set.UUID, err error) {
+	notified = set.UUID{}
+	muted = set.UUID{}
+
+	for _, uid := range parsed.Mentions {
+		user, err := ns.repo.GetUser(uid, false)
+		if err != nil {
+			logger.Error("failed to GetUser", zap.Error(err), zap.Stringer("userId", uid)) // 失敗
+			continue
+		}
+		// 凍結ユーザーの除外
+		if !user.IsActive() {
+			continue
+		}
+		notified.Add(uid)
+	}
+	for _, gid := range parsed.GroupMentions {
+		gs, gm, err := getGroupMentionTargets(ns, q, gid)
+		if err != nil {
+			logger.Error("failed to GetUserGroupMemberIDs", zap.Error(err), zap.Stringer("groupId", gid)) // 失敗
+			return nil, nil, err
+		}
+		notified.Add(gs...)
+		muted.Add(gm...)
+	}
+
+	keywordSettings, err := ns.getKeywordSettings()
+	if err != nil {
+		logger.Error("failed to GetKeywordNotificationSettings", zap.Error(err)) // 失敗
+		return nil, nil, err
+	}
+	for _, s := range keywordSettings {
+		if notified.Contains(s.UserID) || !s.Keywords.Match(parsed.PlainText) {
+			continue
+		}
+		user, err := ns.repo.GetUser(s.UserID, false)
+		if err != nil {
+			logger.Error("failed to GetUser", zap.Error(err), zap.Stringer("userId", s.UserID)) // 失敗
+			continue
+		}
+		// 凍結ユーザー・BOTの除外
+		if !user.IsActive() || user.IsBot() {
+			continue
+		}
+		notified.Add(s.UserID)
+	}
+	return notified, muted, nil
+}
+
+func getGroupMentionTargets(ns *Service, q repository.UsersQuery, gid uuid.UUID) (targets, muted []uuid.UUID, err error) {
+	members, err := ns.repo.GetUserIDs(q.GMemberOf(gid))
+	if err != nil {
+		return nil, nil, err
+	}
+	settings, err := ns.repo.GetUserNotificationSettings(set.UUIDSetFromArray(members))
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, uid := range members {
+		if s, ok := settings[uid]; ok && s.IsGroupMentionMuted(gid) {
+			muted = append(muted, uid)
+		} else {
+			targets = append(targets, uid)
+		}
+	}
+	return targets, muted, nil
+}
+
+// excludeDoNotDisturbUsers おやすみモード・スヌーズ中のユーザーをプッシュ通知の対象から除外します
+func excludeDoNotDisturbUsers(ns *Service, targets set.UUID) error {
+	if len(targets) == 0 {
+		return nil
+	}
+	settings, err := ns.repo.GetUserNotificationSettings(targets)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for uid, s := range settings {
+		if s.IsDoNotDisturb(now) {
+			targets.Remove(uid)
+		}
+	}
+	return nil
+}
+
 func channelViewerMulticast(ns *Service, cid uuid.UUID, ssePayload *sse.EventData) {
 	go ns.ws.WriteMessage(ssePayload.EventType, ssePayload.Payload, ws.TargetChannelViewers(cid))
 }
@@ -2,16 +2,22 @@ package notification
 
 import (
 	"github.com/leandro-lugaresi/hub"
+	"github.com/traPtitech/traQ/model"
 	"github.com/traPtitech/traQ/repository"
 	"github.com/traPtitech/traQ/service/channel"
+	"github.com/traPtitech/traQ/service/cluster"
 	"github.com/traPtitech/traQ/service/fcm"
 	"github.com/traPtitech/traQ/service/file"
 	"github.com/traPtitech/traQ/service/variable"
 	"github.com/traPtitech/traQ/service/viewer"
 	"github.com/traPtitech/traQ/service/ws"
 	"go.uber.org/zap"
+	"sync"
 )
 
+// clusterTopic 通知設定の変更を他ノードに通知するトピック
+const clusterTopic = "notification.setting_changed"
+
 // Service 通知サービス
 type Service struct {
 	repo   repository.Repository
@@ -22,11 +28,17 @@ type Service struct {
 	fcm    fcm.Client
 	ws     *ws.Streamer
 	vm     *viewer.Manager
+	bus    *cluster.Bus
 	origin string
+
+	keywordSettings     []*model.UserNotificationSetting
+	keywordSettingsLock sync.RWMutex
 }
 
 // NewService 通知サービスを作成して起動します
-func NewService(repo repository.Repository, cm channel.Manager, fm file.Manager, hub *hub.Hub, logger *zap.Logger, fcm fcm.Client, ws *ws.Streamer, vm *viewer.Manager, origin variable.ServerOriginString) *Service {
+//
+// キーワード通知設定はメモリ上にキャッシュされ、通知設定が変更されるとbusを通じて全ノードで破棄されます。
+func NewService(repo repository.Repository, cm channel.Manager, fm file.Manager, hub *hub.Hub, logger *zap.Logger, fcm fcm.Client, ws *ws.Streamer, vm *viewer.Manager, bus *cluster.Bus, origin variable.ServerOriginString) *Service {
 	service := &Service{
 		repo:   repo,
 		cm:     cm,
@@ -36,8 +48,12 @@ func NewService(repo repository.Repository, cm channel.Manager, fm file.Manager,
 		fcm:    fcm,
 		ws:     ws,
 		vm:     vm,
+		bus:    bus,
 		origin: string(origin),
 	}
+	bus.Subscribe(clusterTopic, func(string, []byte) {
+		service.purgeKeywordSettings()
+	})
 	go func() {
 		topics := make([]string, 0, len(handlerMap))
 		for k := range handlerMap {
@@ -52,3 +68,34 @@ func NewService(repo repository.Repository, cm channel.Manager, fm file.Manager,
 	}()
 	return service
 }
+
+// getKeywordSettings キーワードが設定されている通知設定を全て取得します
+//
+// 結果はキャッシュされ、いずれかのユーザーの通知設定が変更されるまで再利用されます。
+func (ns *Service) getKeywordSettings() ([]*model.UserNotificationSetting, error) {
+	ns.keywordSettingsLock.RLock()
+	settings := ns.keywordSettings
+	ns.keywordSettingsLock.RUnlock()
+	if settings != nil {
+		return settings, nil
+	}
+
+	ns.keywordSettingsLock.Lock()
+	defer ns.keywordSettingsLock.Unlock()
+	if ns.keywordSettings != nil {
+		return ns.keywordSettings, nil
+	}
+	settings, err := ns.repo.GetKeywordNotificationSettings()
+	if err != nil {
+		return nil, err
+	}
+	ns.keywordSettings = settings
+	return settings, nil
+}
+
+// purgeKeywordSettings キーワード通知設定のキャッシュを破棄します
+func (ns *Service) purgeKeywordSettings() {
+	ns.keywordSettingsLock.Lock()
+	ns.keywordSettings = nil
+	ns.keywordSettingsLock.Unlock()
+}
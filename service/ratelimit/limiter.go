@@ -0,0 +1,104 @@
+//go:generate mockgen -source=$GOFILE -destination=mock_$GOPACKAGE/mock_$GOFILE
+package ratelimit
+
+import (
+	"errors"
+	"github.com/gofrs/uuid"
+	"time"
+)
+
+var (
+	// ErrInvalidAction 不正な操作です
+	ErrInvalidAction = errors.New("invalid action")
+	// ErrInvalidLimit 不正な制限値です
+	ErrInvalidLimit = errors.New("invalid limit")
+)
+
+// Action レート制限の対象となる操作
+type Action string
+
+const (
+	// PostMessage メッセージ投稿
+	PostMessage Action = "post_message"
+	// AddStamp スタンプ押下
+	AddStamp Action = "add_stamp"
+	// UploadFile ファイルアップロード
+	UploadFile Action = "upload_file"
+)
+
+// Actions 全ての操作
+var Actions = []Action{PostMessage, AddStamp, UploadFile}
+
+// Valid 有効な操作かどうか
+func (a Action) Valid() bool {
+	for _, v := range Actions {
+		if a == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Subject レート制限の対象の種類
+type Subject int
+
+const (
+	// Bot BOTユーザー
+	Bot Subject = iota
+	// Webhook Webhook
+	Webhook
+)
+
+// Limit トークンバケットによる制限値
+type Limit struct {
+	// Rate 1秒あたりに補充されるトークン数
+	Rate float64 `json:"rate"`
+	// Burst バケットの容量
+	//
+	// 0以下の場合は制限しません。
+	Burst int `json:"burst"`
+}
+
+// Unlimited 制限しないかどうか
+func (l Limit) Unlimited() bool {
+	return l.Burst <= 0
+}
+
+// Valid 有効な制限値かどうか
+func (l Limit) Valid() bool {
+	return l.Unlimited() || l.Rate > 0
+}
+
+// Config レート制限設定
+type Config struct {
+	// Bot BOTユーザーに対するデフォルトの制限値
+	Bot map[Action]Limit
+	// Webhook Webhookに対するデフォルトの制限値
+	Webhook map[Action]Limit
+}
+
+// Limiter BOT・Webhookの操作のレート制限器
+//
+// 制限はノード毎に適用されます。N台のノードで動作している場合、全体では最大でN倍の操作が許可されます。
+type Limiter interface {
+	// Allow 指定したユーザーの操作を1回分消費します
+	//
+	// 制限を超えている場合、falseと再試行可能になるまでの時間を返します。
+	// userIDはBOTまたはWebhookのBOTユーザーのIDです。
+	Allow(subject Subject, userID uuid.UUID, action Action) (ok bool, retryAfter time.Duration)
+	// GetLimit 指定したユーザーの操作に適用される制限値を返します
+	//
+	// 上書き設定が存在する場合はその値とtrueを、存在しない場合はデフォルトの値とfalseを返します。
+	GetLimit(subject Subject, userID uuid.UUID, action Action) (limit Limit, overridden bool)
+	// SetOverride 指定したユーザーの操作の制限値を上書きします
+	//
+	// 不正な操作を指定した場合、ErrInvalidActionを返します。
+	// 不正な制限値を指定した場合、ErrInvalidLimitを返します。
+	// DBによるエラーを返すことがあります。
+	SetOverride(userID uuid.UUID, action Action, limit Limit) error
+	// DeleteOverride 指定したユーザーの操作の制限値の上書き設定を削除します
+	//
+	// 上書き設定が存在しない場合、repository.ErrNotFoundを返します。
+	// DBによるエラーを返すことがあります。
+	DeleteOverride(userID uuid.UUID, action Action) error
+}
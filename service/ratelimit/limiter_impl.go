@@ -0,0 +1,188 @@
+package ratelimit
+
+import (
+	"encoding/json"
+	"github.com/gofrs/uuid"
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/service/cluster"
+	"math"
+	"sync"
+	"time"
+)
+
+// clusterTopic 上書き設定の変更を他ノードに通知するトピック
+const clusterTopic = "ratelimit.override"
+
+// sweepInterval 満タンになったトークンバケットを破棄する間隔
+const sweepInterval = time.Minute
+
+// clusterMessage 上書き設定の変更
+type clusterMessage struct {
+	UserID uuid.UUID `json:"userId"`
+	Action Action    `json:"action"`
+	// Limit 新しい制限値 nilの場合は上書き設定の削除
+	Limit *Limit `json:"limit"`
+}
+
+type key struct {
+	userID uuid.UUID
+	action Action
+}
+
+// bucket トークンバケット
+type bucket struct {
+	tokens float64
+	last   time.Time
+	// full バケットが満タンになる時刻
+	full time.Time
+}
+
+// take トークンを1つ取り出します
+func (b *bucket) take(l Limit, now time.Time) (bool, time.Duration) {
+	b.tokens = math.Min(float64(l.Burst), b.tokens+now.Sub(b.last).Seconds()*l.Rate)
+	b.last = now
+	ok := b.tokens >= 1
+	if ok {
+		b.tokens--
+	}
+	b.full = now.Add(time.Duration(math.Ceil((float64(l.Burst) - b.tokens) / l.Rate * float64(time.Second))))
+	if ok {
+		return true, 0
+	}
+	return false, time.Duration(math.Ceil((1 - b.tokens) / l.Rate * float64(time.Second)))
+}
+
+type limiterImpl struct {
+	repo      repository.RateLimitRepository
+	bus       *cluster.Bus
+	c         Config
+	now       func() time.Time
+	mu        sync.Mutex
+	buckets   map[key]*bucket
+	overrides map[key]Limit
+	lastSweep time.Time
+}
+
+// NewLimiter レート制限器を生成します
+//
+// DBに保存されている上書き設定を読み込みます。上書き設定の変更はbusを通じて他ノードと共有されます。
+// トークンバケットはノード毎に保持されるため、制限はノード毎に適用されます。
+// 満タンになったトークンバケットは破棄されるため、長期間操作を行わないユーザーのバケットは保持されません。
+func NewLimiter(repo repository.RateLimitRepository, c Config, bus *cluster.Bus) (Limiter, error) {
+	overrides, err := repo.GetRateLimitOverrides()
+	if err != nil {
+		return nil, err
+	}
+	l := &limiterImpl{
+		repo:      repo,
+		bus:       bus,
+		c:         c,
+		now:       time.Now,
+		buckets:   map[key]*bucket{},
+		overrides: map[key]Limit{},
+		lastSweep: time.Now(),
+	}
+	for _, o := range overrides {
+		l.overrides[key{userID: o.UserID, action: Action(o.Action)}] = Limit{Rate: o.Rate, Burst: o.Burst}
+	}
+	bus.Subscribe(clusterTopic, func(_ string, body []byte) {
+		var m clusterMessage
+		if err := json.Unmarshal(body, &m); err != nil {
+			return
+		}
+		l.setOverride(m.UserID, m.Action, m.Limit)
+	})
+	return l, nil
+}
+
+func (l *limiterImpl) Allow(subject Subject, userID uuid.UUID, action Action) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	k := key{userID: userID, action: action}
+	limit, _ := l.getLimit(subject, k)
+	if limit.Unlimited() {
+		return true, 0
+	}
+
+	now := l.now()
+	if now.Sub(l.lastSweep) >= sweepInterval {
+		l.sweep(now)
+	}
+	b, ok := l.buckets[k]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), last: now}
+		l.buckets[k] = b
+	}
+	return b.take(limit, now)
+}
+
+// sweep 満タンになったトークンバケットを破棄します
+//
+// 満タンのバケットは新規に作成したバケットと同じ状態のため、破棄しても制限に影響しません。
+func (l *limiterImpl) sweep(now time.Time) {
+	for k, b := range l.buckets {
+		if !now.Before(b.full) {
+			delete(l.buckets, k)
+		}
+	}
+	l.lastSweep = now
+}
+
+func (l *limiterImpl) GetLimit(subject Subject, userID uuid.UUID, action Action) (Limit, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.getLimit(subject, key{userID: userID, action: action})
+}
+
+func (l *limiterImpl) getLimit(subject Subject, k key) (Limit, bool) {
+	if limit, ok := l.overrides[k]; ok {
+		return limit, true
+	}
+	switch subject {
+	case Bot:
+		return l.c.Bot[k.action], false
+	case Webhook:
+		return l.c.Webhook[k.action], false
+	default:
+		return Limit{}, false
+	}
+}
+
+func (l *limiterImpl) SetOverride(userID uuid.UUID, action Action, limit Limit) error {
+	if !action.Valid() {
+		return ErrInvalidAction
+	}
+	if !limit.Valid() {
+		return ErrInvalidLimit
+	}
+	if err := l.repo.SetRateLimitOverride(userID, string(action), limit.Rate, limit.Burst); err != nil {
+		return err
+	}
+
+	l.setOverride(userID, action, &limit)
+	l.bus.Publish(clusterTopic, &clusterMessage{UserID: userID, Action: action, Limit: &limit})
+	return nil
+}
+
+func (l *limiterImpl) DeleteOverride(userID uuid.UUID, action Action) error {
+	if err := l.repo.DeleteRateLimitOverride(userID, string(action)); err != nil {
+		return err
+	}
+
+	l.setOverride(userID, action, nil)
+	l.bus.Publish(clusterTopic, &clusterMessage{UserID: userID, Action: action})
+	return nil
+}
+
+// setOverride プロセス内の上書き設定を更新します limitがnilの場合は削除します
+func (l *limiterImpl) setOverride(userID uuid.UUID, action Action, limit *Limit) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	k := key{userID: userID, action: action}
+	if limit == nil {
+		delete(l.overrides, k)
+	} else {
+		l.overrides[k] = *limit
+	}
+}
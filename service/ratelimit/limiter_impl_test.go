@@ -0,0 +1,239 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/repository/mock_repository"
+	"github.com/traPtitech/traQ/service/cluster"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+func newTestLimiter(t *testing.T, overrides []*model.RateLimitOverride) (*limiterImpl, *mock_repository.MockRateLimitRepository, *time.Time) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	repo := mock_repository.NewMockRateLimitRepository(ctrl)
+	repo.EXPECT().GetRateLimitOverrides().Return(overrides, nil).Times(1)
+
+	l, err := NewLimiter(repo, Config{
+		Bot: map[Action]Limit{
+			PostMessage: {Rate: 1, Burst: 2},
+			AddStamp:    {Rate: 0, Burst: 0},
+		},
+		Webhook: map[Action]Limit{
+			PostMessage: {Rate: 0.5, Burst: 1},
+		},
+	}, cluster.NewStandaloneBus())
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	now := time.Now()
+	impl := l.(*limiterImpl)
+	impl.now = func() time.Time { return now }
+	return impl, repo, &now
+}
+
+func TestNewLimiter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("repository error", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockRateLimitRepository(ctrl)
+		repo.EXPECT().GetRateLimitOverrides().Return(nil, errors.New("error")).Times(1)
+
+		_, err := NewLimiter(repo, Config{}, cluster.NewStandaloneBus())
+		assert.Error(t, err)
+	})
+
+	t.Run("load overrides", func(t *testing.T) {
+		t.Parallel()
+		uid := uuid.NewV3(uuid.Nil, "u")
+		l, _, _ := newTestLimiter(t, []*model.RateLimitOverride{{UserID: uid, Action: "post_message", Rate: 3, Burst: 30}})
+
+		limit, overridden := l.GetLimit(Bot, uid, PostMessage)
+		assert.True(t, overridden)
+		assert.Equal(t, Limit{Rate: 3, Burst: 30}, limit)
+	})
+}
+
+func TestLimiterImpl_Allow(t *testing.T) {
+	t.Parallel()
+
+	t.Run("bot", func(t *testing.T) {
+		t.Parallel()
+		l, _, now := newTestLimiter(t, nil)
+		uid := uuid.NewV3(uuid.Nil, "bot")
+
+		ok, _ := l.Allow(Bot, uid, PostMessage)
+		assert.True(t, ok)
+		ok, _ = l.Allow(Bot, uid, PostMessage)
+		assert.True(t, ok)
+		ok, retryAfter := l.Allow(Bot, uid, PostMessage)
+		assert.False(t, ok)
+		assert.Equal(t, time.Second, retryAfter)
+
+		// 他のユーザーには影響しない
+		ok, _ = l.Allow(Bot, uuid.NewV3(uuid.Nil, "other"), PostMessage)
+		assert.True(t, ok)
+
+		// トークンの補充
+		*now = now.Add(time.Second)
+		ok, _ = l.Allow(Bot, uid, PostMessage)
+		assert.True(t, ok)
+		ok, _ = l.Allow(Bot, uid, PostMessage)
+		assert.False(t, ok)
+	})
+
+	t.Run("webhook", func(t *testing.T) {
+		t.Parallel()
+		l, _, _ := newTestLimiter(t, nil)
+		uid := uuid.NewV3(uuid.Nil, "webhook")
+
+		ok, _ := l.Allow(Webhook, uid, PostMessage)
+		assert.True(t, ok)
+		ok, retryAfter := l.Allow(Webhook, uid, PostMessage)
+		assert.False(t, ok)
+		assert.Equal(t, 2*time.Second, retryAfter)
+	})
+
+	t.Run("evict idle buckets", func(t *testing.T) {
+		t.Parallel()
+		l, _, now := newTestLimiter(t, nil)
+		idle := uuid.NewV3(uuid.Nil, "idle")
+		active := uuid.NewV3(uuid.Nil, "active")
+
+		l.Allow(Bot, idle, PostMessage)
+		*now = now.Add(sweepInterval - time.Second)
+		l.Allow(Bot, active, PostMessage)
+		l.Allow(Bot, active, PostMessage)
+		assert.Len(t, l.buckets, 2)
+
+		// 満タンになったバケットのみ破棄される
+		*now = now.Add(time.Second)
+		ok, _ := l.Allow(Webhook, uuid.NewV3(uuid.Nil, "webhook"), PostMessage)
+		assert.True(t, ok)
+		assert.NotContains(t, l.buckets, key{userID: idle, action: PostMessage})
+		assert.Contains(t, l.buckets, key{userID: active, action: PostMessage})
+
+		// 破棄されていないバケットは状態を保持している
+		ok, _ = l.Allow(Bot, active, PostMessage)
+		assert.True(t, ok)
+		ok, _ = l.Allow(Bot, active, PostMessage)
+		assert.False(t, ok)
+	})
+
+	t.Run("unlimited", func(t *testing.T) {
+		t.Parallel()
+		l, _, _ := newTestLimiter(t, nil)
+		uid := uuid.NewV3(uuid.Nil, "bot")
+
+		for i := 0; i < 100; i++ {
+			ok, _ := l.Allow(Bot, uid, AddStamp)
+			assert.True(t, ok)
+			ok, _ = l.Allow(Bot, uid, UploadFile)
+			assert.True(t, ok)
+		}
+	})
+}
+
+func TestLimiterImpl_SetOverride(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid action", func(t *testing.T) {
+		t.Parallel()
+		l, _, _ := newTestLimiter(t, nil)
+		assert.Equal(t, ErrInvalidAction, l.SetOverride(uuid.NewV3(uuid.Nil, "bot"), "invalid", Limit{Rate: 1, Burst: 1}))
+	})
+
+	t.Run("invalid limit", func(t *testing.T) {
+		t.Parallel()
+		l, _, _ := newTestLimiter(t, nil)
+		assert.Equal(t, ErrInvalidLimit, l.SetOverride(uuid.NewV3(uuid.Nil, "bot"), PostMessage, Limit{Rate: 0, Burst: 1}))
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		l, repo, _ := newTestLimiter(t, nil)
+		uid := uuid.NewV3(uuid.Nil, "bot")
+		repo.EXPECT().SetRateLimitOverride(uid, "post_message", float64(0), 0).Return(nil).Times(1)
+
+		if assert.NoError(t, l.SetOverride(uid, PostMessage, Limit{})) {
+			limit, overridden := l.GetLimit(Bot, uid, PostMessage)
+			assert.True(t, overridden)
+			assert.True(t, limit.Unlimited())
+			for i := 0; i < 10; i++ {
+				ok, _ := l.Allow(Bot, uid, PostMessage)
+				assert.True(t, ok)
+			}
+		}
+	})
+}
+
+func TestLimiterImpl_DeleteOverride(t *testing.T) {
+	t.Parallel()
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+		l, repo, _ := newTestLimiter(t, nil)
+		uid := uuid.NewV3(uuid.Nil, "bot")
+		repo.EXPECT().DeleteRateLimitOverride(uid, "post_message").Return(repository.ErrNotFound).Times(1)
+
+		assert.Equal(t, repository.ErrNotFound, l.DeleteOverride(uid, PostMessage))
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		uid := uuid.NewV3(uuid.Nil, "bot")
+		l, repo, _ := newTestLimiter(t, []*model.RateLimitOverride{{UserID: uid, Action: "post_message", Rate: 3, Burst: 30}})
+		repo.EXPECT().DeleteRateLimitOverride(uid, "post_message").Return(nil).Times(1)
+
+		if assert.NoError(t, l.DeleteOverride(uid, PostMessage)) {
+			limit, overridden := l.GetLimit(Bot, uid, PostMessage)
+			assert.False(t, overridden)
+			assert.Equal(t, Limit{Rate: 1, Burst: 2}, limit)
+		}
+	})
+}
+
+func TestLimiterImpl_Cluster(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	repo := mock_repository.NewMockRateLimitRepository(ctrl)
+	repo.EXPECT().GetRateLimitOverrides().Return(nil, nil).Times(2)
+
+	network := cluster.NewMemoryNetwork()
+	limiters := make([]Limiter, 2)
+	for i := range limiters {
+		bus := cluster.NewBus(network.NewTransport(), zap.NewNop())
+		l, err := NewLimiter(repo, Config{}, bus)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		bus.Start()
+		defer bus.Shutdown(context.Background())
+		limiters[i] = l
+	}
+
+	// 他ノードでの上書き設定の変更が反映される
+	uid := uuid.NewV3(uuid.Nil, "u")
+	repo.EXPECT().SetRateLimitOverride(uid, "post_message", 3.0, 30).Return(nil).Times(1)
+	assert.NoError(t, limiters[0].SetOverride(uid, PostMessage, Limit{Rate: 3, Burst: 30}))
+	assert.Eventually(t, func() bool {
+		limit, overridden := limiters[1].GetLimit(Bot, uid, PostMessage)
+		return overridden && limit == Limit{Rate: 3, Burst: 30}
+	}, time.Second, 10*time.Millisecond)
+
+	repo.EXPECT().DeleteRateLimitOverride(uid, "post_message").Return(nil).Times(1)
+	assert.NoError(t, limiters[0].DeleteOverride(uid, PostMessage))
+	assert.Eventually(t, func() bool {
+		_, overridden := limiters[1].GetLimit(Bot, uid, PostMessage)
+		return !overridden
+	}, time.Second, 10*time.Millisecond)
+}
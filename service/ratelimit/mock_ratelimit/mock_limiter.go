@@ -0,0 +1,94 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: limiter.go
+
+// Package mock_ratelimit is a generated GoMock package.
+package mock_ratelimit
+
+import (
+	uuid "github.com/gofrs/uuid"
+	gomock "github.com/golang/mock/gomock"
+	ratelimit "github.com/traPtitech/traQ/service/ratelimit"
+	reflect "reflect"
+	time "time"
+)
+
+// MockLimiter is a mock of Limiter interface
+type MockLimiter struct {
+	ctrl     *gomock.Controller
+	recorder *MockLimiterMockRecorder
+}
+
+// MockLimiterMockRecorder is the mock recorder for MockLimiter
+type MockLimiterMockRecorder struct {
+	mock *MockLimiter
+}
+
+// NewMockLimiter creates a new mock instance
+func NewMockLimiter(ctrl *gomock.Controller) *MockLimiter {
+	mock := &MockLimiter{ctrl: ctrl}
+	mock.recorder = &MockLimiterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockLimiter) EXPECT() *MockLimiterMockRecorder {
+	return m.recorder
+}
+
+// Allow mocks base method
+func (m *MockLimiter) Allow(subject ratelimit.Subject, userID uuid.UUID, action ratelimit.Action) (bool, time.Duration) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Allow", subject, userID, action)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(time.Duration)
+	return ret0, ret1
+}
+
+// Allow indicates an expected call of Allow
+func (mr *MockLimiterMockRecorder) Allow(subject, userID, action interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Allow", reflect.TypeOf((*MockLimiter)(nil).Allow), subject, userID, action)
+}
+
+// GetLimit mocks base method
+func (m *MockLimiter) GetLimit(subject ratelimit.Subject, userID uuid.UUID, action ratelimit.Action) (ratelimit.Limit, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLimit", subject, userID, action)
+	ret0, _ := ret[0].(ratelimit.Limit)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// GetLimit indicates an expected call of GetLimit
+func (mr *MockLimiterMockRecorder) GetLimit(subject, userID, action interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLimit", reflect.TypeOf((*MockLimiter)(nil).GetLimit), subject, userID, action)
+}
+
+// SetOverride mocks base method
+func (m *MockLimiter) SetOverride(userID uuid.UUID, action ratelimit.Action, limit ratelimit.Limit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetOverride", userID, action, limit)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetOverride indicates an expected call of SetOverride
+func (mr *MockLimiterMockRecorder) SetOverride(userID, action, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetOverride", reflect.TypeOf((*MockLimiter)(nil).SetOverride), userID, action, limit)
+}
+
+// DeleteOverride mocks base method
+func (m *MockLimiter) DeleteOverride(userID uuid.UUID, action ratelimit.Action) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOverride", userID, action)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOverride indicates an expected call of DeleteOverride
+func (mr *MockLimiterMockRecorder) DeleteOverride(userID, action interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOverride", reflect.TypeOf((*MockLimiter)(nil).DeleteOverride), userID, action)
+}
@@ -27,4 +27,8 @@ const (
 	BotActionJoinChannel = Permission("bot_action_join_channel")
 	// BotActionLeaveChannel BOTアクション実行権限：チャンネル退出
 	BotActionLeaveChannel = Permission("bot_action_leave_channel")
+	// ConnectBotStream BOTイベントストリームへの接続権限
+	ConnectBotStream = Permission("connect_bot_stream")
+	// ManageBotCommand BOTのスラッシュコマンド管理権限
+	ManageBotCommand = Permission("manage_bot_command")
 )
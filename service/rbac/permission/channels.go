@@ -9,10 +9,16 @@ const (
 	EditChannel = Permission("edit_channel")
 	// DeleteChannel チャンネル削除権限
 	DeleteChannel = Permission("delete_channel")
+	// MergeChannel チャンネル統合権限
+	MergeChannel = Permission("merge_channel")
 	// ChangeParentChannel 親チャンネル変更権限
 	ChangeParentChannel = Permission("change_parent_channel")
 	// EditChannelTopic チャンネルトピック変更権限
 	EditChannelTopic = Permission("edit_channel_topic")
+	// ManageChannelRole チャンネルロール管理権限
+	ManageChannelRole = Permission("manage_channel_role")
+	// EditPrivateChannelMember プライベートチャンネルメンバー編集権限
+	EditPrivateChannelMember = Permission("edit_private_channel_member")
 	// GetChannelStar チャンネルスター取得権限
 	GetChannelStar = Permission("get_channel_star")
 	// EditChannelStar チャンネルスター編集権限
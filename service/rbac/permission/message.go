@@ -9,12 +9,18 @@ const (
 	EditMessage = Permission("edit_message")
 	// DeleteMessage メッセージ削除権限
 	DeleteMessage = Permission("delete_message")
+	// DeleteOthersMessage 他人のメッセージ削除権限
+	DeleteOthersMessage = Permission("delete_others_message")
 	// ReportMessage メッセージ通報権限
 	ReportMessage = Permission("report_message")
 	// GetMessageReports メッセージ通報取得権限
 	GetMessageReports = Permission("get_message_reports")
+	// HandleMessageReports メッセージ通報対応権限
+	HandleMessageReports = Permission("handle_message_reports")
 	// CreateMessagePin ピン留め作成権限
 	CreateMessagePin = Permission("create_message_pin")
 	// DeleteMessagePin ピン留め削除権限
 	DeleteMessagePin = Permission("delete_message_pin")
+	// InteractMessageComponent メッセージコンポーネント操作権限
+	InteractMessageComponent = Permission("interact_message_component")
 )
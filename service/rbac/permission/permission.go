@@ -59,13 +59,18 @@ var List = []Permission{
 
 	BotActionJoinChannel,
 	BotActionLeaveChannel,
+	ConnectBotStream,
+	ManageBotCommand,
 
 	CreateChannel,
 	GetChannel,
 	EditChannel,
 	DeleteChannel,
+	MergeChannel,
 	ChangeParentChannel,
 	EditChannelTopic,
+	ManageChannelRole,
+	EditPrivateChannelMember,
 
 	GetMyTokens,
 	RevokeMyToken,
@@ -83,8 +88,10 @@ var List = []Permission{
 	PostMessage,
 	EditMessage,
 	DeleteMessage,
+	DeleteOthersMessage,
 	ReportMessage,
 	GetMessageReports,
+	HandleMessageReports,
 
 	GetChannelSubscription,
 	EditChannelSubscription,
@@ -93,6 +100,7 @@ var List = []Permission{
 
 	CreateMessagePin,
 	DeleteMessagePin,
+	InteractMessageComponent,
 
 	GetMySessions,
 	DeleteMySessions,
@@ -121,6 +129,7 @@ var List = []Permission{
 	EditMe,
 	ChangeMyIcon,
 	ChangeMyPassword,
+	ExportMyData,
 	EditOtherUsers,
 	GetUserQRCode,
 	GetUserGroup,
@@ -13,6 +13,8 @@ const (
 	ChangeMyIcon = Permission("change_my_icon")
 	// ChangeMyPassword 自ユーザーパスワード変更権限
 	ChangeMyPassword = Permission("change_my_password")
+	// ExportMyData 自ユーザーのデータのエクスポート権限
+	ExportMyData = Permission("export_my_data")
 	// EditOtherUsers 他ユーザー情報変更権限
 	EditOtherUsers = Permission("edit_other_users")
 	// GetUserQRCode ユーザーQRコード取得権限
@@ -1,6 +1,9 @@
 package rbac
 
-import "github.com/traPtitech/traQ/service/rbac/permission"
+import (
+	"github.com/gofrs/uuid"
+	"github.com/traPtitech/traQ/service/rbac/permission"
+)
 
 // RBAC Role-based Access Controllerインターフェース
 type RBAC interface {
@@ -12,4 +15,8 @@ type RBAC interface {
 	IsAnyGranted(roles []string, perm permission.Permission) bool
 	// GetGrantedPermissions 指定したロールに与えられている全ての権限を取得します
 	GetGrantedPermissions(role string) []permission.Permission
+	// IsChannelGranted 指定したユーザーのチャンネルロールで、指定したチャンネルでの指定した権限が許可されているかどうか
+	//
+	// 指定したチャンネルとその祖先チャンネルに設定されているチャンネルロールが考慮されます。
+	IsChannelGranted(userID, channelID uuid.UUID, perm permission.Permission) bool
 }
@@ -2,28 +2,82 @@ package rbac
 
 import (
 	"fmt"
+	"github.com/gofrs/uuid"
 	"github.com/jinzhu/gorm"
+	"github.com/leandro-lugaresi/hub"
+	"github.com/traPtitech/traQ/event"
 	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/service/channel"
+	"github.com/traPtitech/traQ/service/cluster"
 	"github.com/traPtitech/traQ/service/rbac/permission"
 	"github.com/traPtitech/traQ/service/rbac/role"
+	"go.uber.org/zap"
 	"sync"
 )
 
+// clusterTopic ロール情報の変更を他ノードに通知するトピック
+const clusterTopic = "rbac.changed"
+
 type rbacImpl struct {
 	roles      role.Roles
 	rolesMutex sync.RWMutex
 	db         *gorm.DB
+	cm         channel.Manager
+	logger     *zap.Logger
+
+	channelRoleDefs   role.Roles
+	channelRoles      map[uuid.UUID]map[uuid.UUID]string // channelID -> userID -> role
+	channelRolesMutex sync.RWMutex
 }
 
 // New RBACを初期化
-func New(db *gorm.DB) (RBAC, error) {
+//
+// ユーザーロールが作成・更新・削除された場合、ロール情報を再読み込みします。
+// チャンネルロールはイベントを購読して最新に保たれます。
+// いずれの変更もbusを通じて他ノードに通知され、通知を受けたノードはデータベースから再読み込みします。
+func New(db *gorm.DB, hub *hub.Hub, cm channel.Manager, bus *cluster.Bus, logger *zap.Logger) (RBAC, error) {
 	rbac := &rbacImpl{
-		roles: role.Roles{},
-		db:    db,
+		roles:           role.Roles{},
+		db:              db,
+		cm:              cm,
+		logger:          logger.Named("rbac"),
+		channelRoleDefs: role.GetChannelRoles(),
+		channelRoles:    map[uuid.UUID]map[uuid.UUID]string{},
 	}
 	if err := rbac.reload(); err != nil {
 		return nil, fmt.Errorf("failed to init rbac: %w", err)
 	}
+	if err := rbac.loadChannelRoles(); err != nil {
+		return nil, fmt.Errorf("failed to init rbac: %w", err)
+	}
+	bus.Subscribe(clusterTopic, func(string, []byte) {
+		if err := rbac.reload(); err != nil {
+			rbac.logger.Error("failed to reload roles", zap.Error(err))
+		}
+		if err := rbac.loadChannelRoles(); err != nil {
+			rbac.logger.Error("failed to reload channel roles", zap.Error(err))
+		}
+	})
+	sub := hub.Subscribe(10, event.UserRoleCreated, event.UserRoleUpdated, event.UserRoleDeleted, event.ChannelRoleUpdated, event.ChannelRoleDeleted, event.ChannelMerged)
+	go func() {
+		for ev := range sub.Receiver {
+			bus.Publish(clusterTopic, nil)
+			switch ev.Topic() {
+			case event.ChannelRoleUpdated:
+				rbac.setChannelRole(ev.Fields["channel_id"].(uuid.UUID), ev.Fields["user_id"].(uuid.UUID), ev.Fields["role"].(string))
+			case event.ChannelRoleDeleted:
+				rbac.setChannelRole(ev.Fields["channel_id"].(uuid.UUID), ev.Fields["user_id"].(uuid.UUID), "")
+			case event.ChannelMerged:
+				if err := rbac.loadChannelRoles(); err != nil {
+					rbac.logger.Error("failed to reload channel roles", zap.Error(err))
+				}
+			default:
+				if err := rbac.reload(); err != nil {
+					rbac.logger.Error("failed to reload roles", zap.Error(err))
+				}
+			}
+		}
+	}()
 	return rbac, nil
 }
 
@@ -93,7 +147,7 @@ func (r *rbacImpl) reload() error {
 		}
 	}
 
-	// TODO 木の循環検知
+	// 循環する継承関係はリポジトリで作成時に弾かれる
 
 	result := role.Roles{}
 	for _, v := range roles {
@@ -118,6 +172,55 @@ func (r *rbacImpl) GetGrantedPermissions(roleName string) []permission.Permissio
 	return nil
 }
 
+func (r *rbacImpl) IsChannelGranted(userID, channelID uuid.UUID, perm permission.Permission) bool {
+	ids := append([]uuid.UUID{channelID}, r.cm.PublicChannelTree().GetAscendantIDs(channelID)...)
+
+	r.channelRolesMutex.RLock()
+	defer r.channelRolesMutex.RUnlock()
+	for _, id := range ids {
+		if roleName, ok := r.channelRoles[id][userID]; ok && r.channelRoleDefs.HasAndIsGranted(roleName, perm) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *rbacImpl) loadChannelRoles() error {
+	var crs []*model.ChannelRole
+	if err := r.db.Find(&crs).Error; err != nil {
+		return err
+	}
+	channelRoles := map[uuid.UUID]map[uuid.UUID]string{}
+	for _, cr := range crs {
+		users, ok := channelRoles[cr.ChannelID]
+		if !ok {
+			users = map[uuid.UUID]string{}
+			channelRoles[cr.ChannelID] = users
+		}
+		users[cr.UserID] = cr.Role
+	}
+	r.channelRolesMutex.Lock()
+	r.channelRoles = channelRoles
+	r.channelRolesMutex.Unlock()
+	return nil
+}
+
+// setChannelRole チャンネルロールのキャッシュを更新します roleが空の場合は削除します
+func (r *rbacImpl) setChannelRole(channelID, userID uuid.UUID, roleName string) {
+	r.channelRolesMutex.Lock()
+	defer r.channelRolesMutex.Unlock()
+	if len(roleName) == 0 {
+		delete(r.channelRoles[channelID], userID)
+		return
+	}
+	users, ok := r.channelRoles[channelID]
+	if !ok {
+		users = map[uuid.UUID]string{}
+		r.channelRoles[channelID] = users
+	}
+	users[userID] = roleName
+}
+
 type roleImpl struct {
 	name         string
 	oauth2       bool
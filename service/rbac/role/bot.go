@@ -37,4 +37,6 @@ var botPerms = []permission.Permission{
 	permission.DeleteFile,
 	permission.BotActionJoinChannel,
 	permission.BotActionLeaveChannel,
+	permission.ConnectBotStream,
+	permission.ManageBotCommand,
 }
@@ -0,0 +1,42 @@
+package role
+
+import (
+	"github.com/traPtitech/traQ/service/rbac/permission"
+)
+
+const (
+	// ChannelOwner チャンネルオーナーロール
+	ChannelOwner = "channel_owner"
+	// ChannelModerator チャンネルモデレーターロール
+	ChannelModerator = "channel_moderator"
+)
+
+var channelModeratorPerms = []permission.Permission{
+	permission.EditChannelTopic,
+	permission.CreateMessagePin,
+	permission.DeleteMessagePin,
+	permission.DeleteOthersMessage,
+	permission.EditChannelSubscription,
+}
+
+var channelOwnerPerms = append([]permission.Permission{
+	permission.ManageChannelRole,
+}, channelModeratorPerms...)
+
+// GetChannelRoles チャンネルロールのRolesを返します
+//
+// チャンネルロールは特定のチャンネルの部分木内でのみ有効なロールです。
+func GetChannelRoles() Roles {
+	return Roles{
+		ChannelOwner: &systemRole{
+			name:        ChannelOwner,
+			oauth2Scope: false,
+			permissions: permission.PermissionsFromArray(channelOwnerPerms),
+		},
+		ChannelModerator: &systemRole{
+			name:        ChannelModerator,
+			oauth2Scope: false,
+			permissions: permission.PermissionsFromArray(channelModeratorPerms),
+		},
+	}
+}
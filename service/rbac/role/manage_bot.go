@@ -21,6 +21,7 @@ var manageBotPerms = []permission.Permission{
 	permission.DeleteBot,
 	permission.BotActionJoinChannel,
 	permission.BotActionLeaveChannel,
+	permission.ManageBotCommand,
 	permission.GetClients,
 	permission.CreateClient,
 	permission.EditMyClient,
@@ -20,6 +20,7 @@ var readPerms = []permission.Permission{
 	permission.GetUserGroup,
 	permission.GetStamp,
 	permission.GetMyStampHistory,
+	permission.ExportMyData,
 	permission.DownloadFile,
 	permission.GetWebhook,
 	permission.GetBot,
@@ -29,6 +29,7 @@ var userPerms = []permission.Permission{
 	permission.DeleteBot,
 	permission.BotActionJoinChannel,
 	permission.BotActionLeaveChannel,
+	permission.ManageBotCommand,
 	permission.WebRTC,
 }
 
@@ -9,6 +9,7 @@ const Write = "write"
 
 var writePerms = []permission.Permission{
 	permission.CreateChannel,
+	permission.EditPrivateChannelMember,
 	permission.EditChannelTopic,
 	permission.PostMessage,
 	permission.EditMessage,
@@ -16,6 +17,7 @@ var writePerms = []permission.Permission{
 	permission.ReportMessage,
 	permission.CreateMessagePin,
 	permission.DeleteMessagePin,
+	permission.InteractMessageComponent,
 	permission.EditChannelSubscription,
 	permission.RegisterFCMDevice,
 	permission.EditMe,
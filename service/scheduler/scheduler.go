@@ -0,0 +1,11 @@
+package scheduler
+
+import "context"
+
+// Scheduler 予約投稿メッセージ配信サービス
+type Scheduler interface {
+	// Start 予約投稿メッセージの配信を開始します
+	Start()
+	// Shutdown 予約投稿メッセージの配信を停止します
+	Shutdown(ctx context.Context) error
+}
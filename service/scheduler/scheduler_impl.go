@@ -0,0 +1,192 @@
+package scheduler
+
+import (
+	"context"
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/service/channel"
+	"github.com/traPtitech/traQ/service/cluster"
+	"github.com/traPtitech/traQ/utils/optional"
+	"go.uber.org/zap"
+	"sync"
+	"time"
+)
+
+const (
+	// pollInterval 配信予定のメッセージを確認する間隔
+	pollInterval = 10 * time.Second
+	// batchSize 一度に配信するメッセージの最大数
+	batchSize = 100
+)
+
+type schedulerImpl struct {
+	repo   repository.Repository
+	cm     channel.Manager
+	bus    *cluster.Bus
+	logger *zap.Logger
+
+	started bool
+	stop    chan struct{}
+	wg      sync.WaitGroup
+}
+
+// NewScheduler 予約投稿メッセージ配信サービスを生成します
+//
+// 配信はクラスタのリーダーノードでのみ行われます。
+func NewScheduler(repo repository.Repository, cm channel.Manager, bus *cluster.Bus, logger *zap.Logger) Scheduler {
+	return &schedulerImpl{
+		repo:   repo,
+		cm:     cm,
+		bus:    bus,
+		logger: logger.Named("scheduler"),
+		stop:   make(chan struct{}),
+	}
+}
+
+func (s *schedulerImpl) Start() {
+	if s.started {
+		return
+	}
+	s.started = true
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		t := time.NewTicker(pollInterval)
+		defer t.Stop()
+
+		s.deliverDueMessages() // 停止中に配信予定日時を過ぎたメッセージを配信
+		for {
+			select {
+			case <-t.C:
+				s.deliverDueMessages()
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+	s.logger.Info("scheduler started")
+}
+
+func (s *schedulerImpl) Shutdown(ctx context.Context) error {
+	if !s.started {
+		return nil
+	}
+	close(s.stop)
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		s.logger.Info("scheduler shutdown")
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// deliverDueMessages 配信予定日時を過ぎたメッセージを配信します
+func (s *schedulerImpl) deliverDueMessages() {
+	if !s.bus.IsLeader() {
+		return
+	}
+	for {
+		messages, err := s.repo.GetScheduledMessages(repository.ScheduledMessagesQuery{
+			Until: optional.TimeFrom(time.Now()),
+			Limit: batchSize,
+		})
+		if err != nil {
+			s.logger.Error("failed to GetScheduledMessages", zap.Error(err))
+			return
+		}
+
+		failed := false
+		for _, m := range messages {
+			select {
+			case <-s.stop:
+				return
+			default:
+			}
+			if err := s.deliver(m); err != nil {
+				// 削除されずに残るので次回に再試行される
+				s.logger.Error("failed to deliver scheduled message", zap.Error(err), zap.Stringer("scheduledMessageId", m.ID))
+				failed = true
+			}
+		}
+
+		if failed || len(messages) < batchSize {
+			return
+		}
+	}
+}
+
+// deliver 予約投稿メッセージを投稿します
+//
+// 投稿時点でチャンネルに投稿できなくなっている場合は、投稿せずに予約を破棄します。
+func (s *schedulerImpl) deliver(m *model.ScheduledMessage) error {
+	logger := s.logger.With(zap.Stringer("scheduledMessageId", m.ID), zap.Stringer("userId", m.UserID), zap.Stringer("channelId", m.ChannelID))
+
+	if reason, err := s.checkDeliverable(m); err != nil {
+		return err
+	} else if len(reason) > 0 {
+		logger.Info("scheduled message was discarded: " + reason)
+		return s.discard(m)
+	}
+
+	if _, err := s.repo.DeliverScheduledMessage(m.ID); err != nil {
+		if err == repository.ErrNotFound {
+			// 既に配信済みか、取り消されている
+			return nil
+		}
+		return err
+	}
+	logger.Info("scheduled message was delivered")
+	return nil
+}
+
+// checkDeliverable 予約投稿メッセージを投稿できるかどうかを確認します
+//
+// 投稿できない場合はその理由を返します。
+func (s *schedulerImpl) checkDeliverable(m *model.ScheduledMessage) (reason string, err error) {
+	ch, err := s.cm.GetChannel(m.ChannelID)
+	if err != nil {
+		if err == channel.ErrChannelNotFound {
+			return "channel not found", nil
+		}
+		return "", err
+	}
+	if ch.IsArchived() {
+		return "channel archived", nil
+	}
+
+	user, err := s.repo.GetUser(m.UserID, false)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return "user not found", nil
+		}
+		return "", err
+	}
+	if !user.IsActive() {
+		return "user deactivated", nil
+	}
+
+	ok, err := s.cm.IsChannelAccessibleToUser(m.UserID, m.ChannelID)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "channel inaccessible", nil
+	}
+	return "", nil
+}
+
+// discard 予約投稿メッセージを削除します
+func (s *schedulerImpl) discard(m *model.ScheduledMessage) error {
+	if err := s.repo.DeleteScheduledMessage(m.ID); err != nil && err != repository.ErrNotFound {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package search
+
+import (
+	"errors"
+	"github.com/gofrs/uuid"
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/utils/message"
+	"github.com/traPtitech/traQ/utils/optional"
+	"time"
+)
+
+var (
+	// ErrEmptyQuery 検索条件が指定されていない
+	ErrEmptyQuery = errors.New("empty query")
+)
+
+// Engine メッセージ全文検索エンジン
+type Engine interface {
+	// Do 検索を実行します
+	//
+	// 結果はメッセージの作成日時の降順で返され、各ヒットにはメッセージが設定されます。
+	// q.UserIDのユーザーがアクセスできないチャンネルのメッセージと、既に削除されたメッセージは含まれません。
+	Do(q *Query) (*Result, error)
+	// Index 指定したドキュメントをインデックスに追加・更新します
+	Index(docs ...*Document) error
+	// Delete 指定したメッセージをインデックスから削除します
+	Delete(messageIDs ...uuid.UUID) error
+	// Reset インデックスを全て削除します
+	Reset() error
+	// Close 検索エンジンを終了します
+	Close() error
+}
+
+// pinIndexer ピン留め状態を自前で保持する検索エンジン
+//
+// 実装しているEngineにはピン留め・ピン外しのイベントが通知されます。
+type pinIndexer interface {
+	// SetPinned メッセージのピン留め状態を設定します
+	SetPinned(messageID uuid.UUID, pinned bool) error
+}
+
+// channelMerger チャンネル情報を自前で保持する検索エンジン
+//
+// 実装しているEngineにはチャンネル統合のイベントが通知されます。
+type channelMerger interface {
+	// MergeChannel 統合元チャンネルのメッセージを統合先チャンネルのものとして扱うようにします
+	MergeChannel(fromID, toID uuid.UUID) error
+}
+
+// Query 検索クエリ
+type Query struct {
+	// UserID 検索を行うユーザーのID
+	UserID uuid.UUID
+	// Words 全て含まれている必要がある語句(フレーズを含む)
+	Words []string
+	// From 投稿者のユーザーID
+	From optional.UUID
+	// In 投稿先のチャンネルID
+	In optional.UUID
+	// Before この日時より前に投稿されたメッセージ
+	Before optional.Time
+	// After この日時より後に投稿されたメッセージ
+	After optional.Time
+	// HasFile ファイルが添付されているメッセージのみ
+	HasFile bool
+	// IsPinned ピン留めされているメッセージのみ
+	IsPinned bool
+	Limit    int
+	Offset   int
+}
+
+// IsEmpty 検索条件が一つも指定されていないかどうか
+func (q *Query) IsEmpty() bool {
+	return len(q.Words) == 0 && !q.From.Valid && !q.In.Valid && !q.Before.Valid && !q.After.Valid && !q.HasFile && !q.IsPinned
+}
+
+// Result 検索結果
+type Result struct {
+	// TotalHits ヒットした総件数
+	TotalHits int
+	// Hits ヒットしたメッセージ
+	Hits []*Hit
+}
+
+// Hit 検索にヒットしたメッセージ
+type Hit struct {
+	MessageID uuid.UUID
+	ChannelID uuid.UUID
+	CreatedAt time.Time
+	Message   *model.Message `gorm:"-"`
+}
+
+// hydrate ヒットしたメッセージを取得して設定します
+//
+// インデックスへの反映前に削除されたメッセージはヒットから除外し、そのIDを返します。
+func hydrate(repo repository.MessageRepository, hits []*Hit) ([]*Hit, []uuid.UUID, error) {
+	if len(hits) == 0 {
+		return hits, nil, nil
+	}
+	ids := make([]uuid.UUID, len(hits))
+	for i, h := range hits {
+		ids[i] = h.MessageID
+	}
+	messages, err := repo.GetMessagesByIDs(ids)
+	if err != nil {
+		return nil, nil, err
+	}
+	byID := make(map[uuid.UUID]*model.Message, len(messages))
+	for _, m := range messages {
+		byID[m.ID] = m
+	}
+
+	res := make([]*Hit, 0, len(hits))
+	var deleted []uuid.UUID
+	for _, h := range hits {
+		m, ok := byID[h.MessageID]
+		if !ok {
+			deleted = append(deleted, h.MessageID)
+			continue
+		}
+		h.Message = m
+		res = append(res, h)
+	}
+	return res, deleted, nil
+}
+
+// Document インデックスに格納するメッセージのドキュメント
+type Document struct {
+	MessageID uuid.UUID
+	ChannelID uuid.UUID
+	UserID    uuid.UUID
+	PlainText string
+	HasFile   bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewDocument メッセージからドキュメントを生成します
+//
+// parsedがnilの場合はメッセージ本文をパースします。
+func NewDocument(m *model.Message, parsed *message.ParseResult) *Document {
+	if parsed == nil {
+		parsed = message.Parse(m.Text)
+	}
+	if m.Hidden {
+		// 非表示のメッセージは本文で検索できないようにする
+		parsed = &message.ParseResult{}
+	}
+	return &Document{
+		MessageID: m.ID,
+		ChannelID: m.ChannelID,
+		UserID:    m.UserID,
+		PlainText: parsed.PlainText,
+		HasFile:   len(parsed.Attachments) > 0,
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+	}
+}
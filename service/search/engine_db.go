@@ -0,0 +1,141 @@
+package search
+
+import (
+	"fmt"
+	"github.com/gofrs/uuid"
+	"github.com/jinzhu/gorm"
+	"github.com/leandro-lugaresi/hub"
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/utils/gormutil"
+	"go.uber.org/zap"
+	"strings"
+)
+
+// dbEngine データベース(MariaDB)のテーブルをインデックスとして用いる検索エンジン
+type dbEngine struct {
+	db     *gorm.DB
+	repo   repository.MessageRepository
+	logger *zap.Logger
+}
+
+// NewDBEngine データベースを用いる検索エンジンを生成します
+//
+// インデックスはメッセージイベントを購読して更新されます。
+func NewDBEngine(db *gorm.DB, repo repository.MessageRepository, hub *hub.Hub, logger *zap.Logger) (Engine, error) {
+	e := &dbEngine{
+		db:     db,
+		repo:   repo,
+		logger: logger.Named("search"),
+	}
+	startIndexer(e, hub, nil, e.logger) // インデックスはDBで共有されるため中継しない
+	return e, nil
+}
+
+// Do implements Engine interface.
+func (e *dbEngine) Do(q *Query) (*Result, error) {
+	if q.IsEmpty() {
+		return nil, ErrEmptyQuery
+	}
+
+	tx := e.db.
+		Table("messages_search_index idx").
+		Joins("INNER JOIN channels c ON c.id = idx.channel_id").
+		Joins("INNER JOIN messages m ON m.id = idx.message_id").
+		Where("c.deleted_at IS NULL AND m.deleted_at IS NULL").
+		Where("c.is_public = TRUE OR idx.channel_id IN (SELECT upc.channel_id FROM users_private_channels upc WHERE upc.user_id = ?)", q.UserID)
+
+	for _, w := range q.Words {
+		tx = tx.Where("idx.plain_text LIKE ?", "%"+escapeLike(w)+"%")
+	}
+	if q.From.Valid {
+		tx = tx.Where("idx.user_id = ?", q.From.UUID)
+	}
+	if q.In.Valid {
+		tx = tx.Where("idx.channel_id = ?", q.In.UUID)
+	}
+	if q.Before.Valid {
+		tx = tx.Where("idx.created_at < ?", q.Before.Time)
+	}
+	if q.After.Valid {
+		tx = tx.Where("idx.created_at > ?", q.After.Time)
+	}
+	if q.HasFile {
+		tx = tx.Where("idx.has_file = TRUE")
+	}
+	if q.IsPinned {
+		tx = tx.Where("EXISTS (SELECT 1 FROM pins p WHERE p.message_id = idx.message_id)")
+	}
+
+	total, err := gormutil.Count(tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count search hits: %w", err)
+	}
+
+	hits := make([]*Hit, 0)
+	err = tx.
+		Select("idx.message_id, idx.channel_id, idx.created_at").
+		Order("idx.created_at DESC").
+		Scopes(gormutil.LimitAndOffset(q.Limit, q.Offset)).
+		Scan(&hits).
+		Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to search messages: %w", err)
+	}
+
+	hits, deleted, err := hydrate(e.repo, hits)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch messages: %w", err)
+	}
+	return &Result{TotalHits: total - len(deleted), Hits: hits}, nil
+}
+
+// Index implements Engine interface.
+func (e *dbEngine) Index(docs ...*Document) error {
+	return e.db.Transaction(func(tx *gorm.DB) error {
+		for _, doc := range docs {
+			err := tx.
+				Set("gorm:insert_option", "ON DUPLICATE KEY UPDATE channel_id = VALUES(channel_id), plain_text = VALUES(plain_text), has_file = VALUES(has_file), updated_at = VALUES(updated_at)").
+				Create(&model.MessageSearchIndex{
+					MessageID: doc.MessageID,
+					ChannelID: doc.ChannelID,
+					UserID:    doc.UserID,
+					PlainText: doc.PlainText,
+					HasFile:   doc.HasFile,
+					CreatedAt: doc.CreatedAt,
+					UpdatedAt: doc.UpdatedAt,
+				}).
+				Error
+			if err != nil {
+				if gormutil.IsMySQLForeignKeyConstraintFailsError(err) {
+					continue // 既にメッセージが削除されている
+				}
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Delete implements Engine interface.
+func (e *dbEngine) Delete(messageIDs ...uuid.UUID) error {
+	if len(messageIDs) == 0 {
+		return nil
+	}
+	return e.db.Where("message_id IN (?)", messageIDs).Delete(&model.MessageSearchIndex{}).Error
+}
+
+// Reset implements Engine interface.
+func (e *dbEngine) Reset() error {
+	return e.db.Delete(&model.MessageSearchIndex{}).Error
+}
+
+// Close implements Engine interface.
+func (e *dbEngine) Close() error {
+	return nil
+}
+
+// escapeLike LIKE句のワイルドカード文字をエスケープします
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,414 @@
+package search
+
+import (
+	"encoding/gob"
+	"fmt"
+	"github.com/gofrs/uuid"
+	"github.com/leandro-lugaresi/hub"
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/service/channel"
+	"github.com/traPtitech/traQ/service/cluster"
+	"github.com/traPtitech/traQ/utils/set"
+	"go.uber.org/zap"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// memorySaveInterval インデックスファイルの定期保存間隔
+const memorySaveInterval = 5 * time.Minute
+
+// memoryEngine プロセス内の転置インデックスを用いる検索エンジン
+//
+// 本文はn-gram(bi-gram)に分割してインデックスされるため、分かち書きされない日本語でも検索できます。
+// インデックスはファイルに保存され、起動時に読み込まれます。
+type memoryEngine struct {
+	cm     channel.Manager
+	repo   repository.MessageRepository
+	file   string
+	logger *zap.Logger
+
+	mu       sync.RWMutex
+	docs     map[uuid.UUID]*memoryDocument
+	postings map[string]set.UUID
+	pinned   set.UUID
+	dirty    bool
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+// memoryDocument 正規化済みの本文を保持するドキュメント
+type memoryDocument struct {
+	*Document
+	text string
+}
+
+// memorySnapshot インデックスファイルの内容
+type memorySnapshot struct {
+	Docs   []*Document
+	Pinned []uuid.UUID
+}
+
+// NewMemoryEngine プロセス内の転置インデックスを用いる検索エンジンを生成します
+//
+// fileが空でない場合、インデックスをfileから読み込み、定期的及び終了時にfileへ保存します。
+// インデックスはメッセージイベントを購読して更新され、busを通じて他ノードの変更も反映されます。
+func NewMemoryEngine(file string, cm channel.Manager, repo repository.MessageRepository, hub *hub.Hub, bus *cluster.Bus, logger *zap.Logger) (Engine, error) {
+	e := &memoryEngine{
+		cm:       cm,
+		repo:     repo,
+		file:     file,
+		logger:   logger.Named("search"),
+		docs:     map[uuid.UUID]*memoryDocument{},
+		postings: map[string]set.UUID{},
+		pinned:   set.UUID{},
+		closed:   make(chan struct{}),
+	}
+	if err := e.load(); err != nil {
+		return nil, err
+	}
+	if len(e.file) > 0 {
+		go e.saveLoop()
+	}
+	startIndexer(e, hub, bus, e.logger)
+	return e, nil
+}
+
+// Do implements Engine interface.
+func (e *memoryEngine) Do(q *Query) (*Result, error) {
+	if q.IsEmpty() {
+		return nil, ErrEmptyQuery
+	}
+
+	terms := make([]string, 0, len(q.Words))
+	for _, w := range q.Words {
+		if t := normalize(w); len(t) > 0 {
+			terms = append(terms, t)
+		}
+	}
+
+	e.mu.RLock()
+	candidates := e.candidates(terms)
+	matched := make([]*Document, 0)
+	for _, doc := range candidates {
+		if e.match(doc, q, terms) {
+			matched = append(matched, doc.Document)
+		}
+	}
+	e.mu.RUnlock()
+
+	// アクセス可能なチャンネルのメッセージのみに絞り込む
+	accessible := map[uuid.UUID]bool{}
+	hits := make([]*Hit, 0)
+	for _, doc := range matched {
+		ok, checked := accessible[doc.ChannelID]
+		if !checked {
+			var err error
+			ok, err = e.cm.IsChannelAccessibleToUser(q.UserID, doc.ChannelID)
+			if err != nil {
+				return nil, err
+			}
+			accessible[doc.ChannelID] = ok
+		}
+		if ok {
+			hits = append(hits, &Hit{MessageID: doc.MessageID, ChannelID: doc.ChannelID, CreatedAt: doc.CreatedAt})
+		}
+	}
+
+	sort.Slice(hits, func(i, j int) bool { return hits[i].CreatedAt.After(hits[j].CreatedAt) })
+	total := len(hits)
+	if q.Offset > 0 {
+		if q.Offset >= len(hits) {
+			hits = hits[:0]
+		} else {
+			hits = hits[q.Offset:]
+		}
+	}
+	if q.Limit > 0 && len(hits) > q.Limit {
+		hits = hits[:q.Limit]
+	}
+
+	hits, deleted, err := hydrate(e.repo, hits)
+	if err != nil {
+		return nil, err
+	}
+	if len(deleted) > 0 {
+		// 削除イベントを取りこぼしたメッセージをインデックスから取り除く
+		if err := e.Delete(deleted...); err != nil {
+			return nil, err
+		}
+	}
+	return &Result{TotalHits: total - len(deleted), Hits: hits}, nil
+}
+
+// candidates 語句を含む可能性のあるドキュメントを転置インデックスから求めます
+//
+// e.muのロックを取得してから呼び出す必要があります。
+func (e *memoryEngine) candidates(terms []string) []*memoryDocument {
+	var ids set.UUID
+	for _, t := range terms {
+		grams := ngrams(t)
+		if len(grams) == 0 {
+			continue // n文字未満の語句は全件走査で確認する
+		}
+		for _, g := range grams {
+			posting := e.postings[g]
+			if ids == nil {
+				ids = posting.Clone()
+			} else {
+				for id := range ids {
+					if !posting.Contains(id) {
+						ids.Remove(id)
+					}
+				}
+			}
+			if len(ids) == 0 {
+				return nil
+			}
+		}
+	}
+
+	if ids == nil {
+		result := make([]*memoryDocument, 0, len(e.docs))
+		for _, doc := range e.docs {
+			result = append(result, doc)
+		}
+		return result
+	}
+	result := make([]*memoryDocument, 0, len(ids))
+	for id := range ids {
+		result = append(result, e.docs[id])
+	}
+	return result
+}
+
+// match ドキュメントがクエリの条件を満たすかどうか
+//
+// e.muのロックを取得してから呼び出す必要があります。
+func (e *memoryEngine) match(doc *memoryDocument, q *Query, terms []string) bool {
+	if q.From.Valid && doc.UserID != q.From.UUID {
+		return false
+	}
+	if q.In.Valid && doc.ChannelID != q.In.UUID {
+		return false
+	}
+	if q.Before.Valid && !doc.CreatedAt.Before(q.Before.Time) {
+		return false
+	}
+	if q.After.Valid && !doc.CreatedAt.After(q.After.Time) {
+		return false
+	}
+	if q.HasFile && !doc.HasFile {
+		return false
+	}
+	if q.IsPinned && !e.pinned.Contains(doc.MessageID) {
+		return false
+	}
+	for _, t := range terms {
+		// n-gramの積集合は偽陽性を含むので本文を直接確認する
+		if !strings.Contains(doc.text, t) {
+			return false
+		}
+	}
+	return true
+}
+
+// Index implements Engine interface.
+func (e *memoryEngine) Index(docs ...*Document) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, doc := range docs {
+		e.remove(doc.MessageID)
+		md := &memoryDocument{Document: doc, text: normalize(doc.PlainText)}
+		e.docs[doc.MessageID] = md
+		for _, g := range ngrams(md.text) {
+			posting, ok := e.postings[g]
+			if !ok {
+				posting = set.UUID{}
+				e.postings[g] = posting
+			}
+			posting.Add(doc.MessageID)
+		}
+	}
+	e.dirty = true
+	return nil
+}
+
+// Delete implements Engine interface.
+func (e *memoryEngine) Delete(messageIDs ...uuid.UUID) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, id := range messageIDs {
+		e.remove(id)
+		e.pinned.Remove(id)
+	}
+	e.dirty = true
+	return nil
+}
+
+// SetPinned implements pinIndexer interface.
+func (e *memoryEngine) SetPinned(messageID uuid.UUID, pinned bool) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if pinned {
+		e.pinned.Add(messageID)
+	} else {
+		e.pinned.Remove(messageID)
+	}
+	e.dirty = true
+	return nil
+}
+
+// MergeChannel implements channelMerger interface.
+func (e *memoryEngine) MergeChannel(fromID, toID uuid.UUID) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, doc := range e.docs {
+		if doc.ChannelID == fromID {
+			doc.ChannelID = toID
+		}
+	}
+	e.dirty = true
+	return nil
+}
+
+// Reset implements Engine interface.
+func (e *memoryEngine) Reset() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.docs = map[uuid.UUID]*memoryDocument{}
+	e.postings = map[string]set.UUID{}
+	e.pinned = set.UUID{}
+	e.dirty = true
+	return nil
+}
+
+// Close implements Engine interface.
+func (e *memoryEngine) Close() (err error) {
+	e.closeOnce.Do(func() {
+		close(e.closed)
+		err = e.save()
+	})
+	return
+}
+
+// remove ドキュメントを転置インデックスから取り除きます
+//
+// e.muのロックを取得してから呼び出す必要があります。
+func (e *memoryEngine) remove(messageID uuid.UUID) {
+	doc, ok := e.docs[messageID]
+	if !ok {
+		return
+	}
+	for _, g := range ngrams(doc.text) {
+		posting := e.postings[g]
+		posting.Remove(messageID)
+		if len(posting) == 0 {
+			delete(e.postings, g)
+		}
+	}
+	delete(e.docs, messageID)
+}
+
+// saveLoop インデックスを定期的にファイルに保存します
+func (e *memoryEngine) saveLoop() {
+	t := time.NewTicker(memorySaveInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			if err := e.save(); err != nil {
+				e.logger.Error("failed to save search index", zap.Error(err))
+			}
+		case <-e.closed:
+			return
+		}
+	}
+}
+
+// load インデックスをファイルから読み込みます
+func (e *memoryEngine) load() error {
+	if len(e.file) == 0 {
+		return nil
+	}
+	f, err := os.Open(e.file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to open search index file: %w", err)
+	}
+	defer f.Close()
+
+	var snapshot memorySnapshot
+	if err := gob.NewDecoder(f).Decode(&snapshot); err != nil {
+		return fmt.Errorf("failed to decode search index file: %w", err)
+	}
+	if err := e.Index(snapshot.Docs...); err != nil {
+		return err
+	}
+	e.mu.Lock()
+	e.pinned.Add(snapshot.Pinned...)
+	e.dirty = false
+	e.mu.Unlock()
+	e.logger.Info(fmt.Sprintf("loaded %d documents from search index file", len(snapshot.Docs)))
+	return nil
+}
+
+// save 変更があればインデックスをファイルに保存します
+func (e *memoryEngine) save() error {
+	if len(e.file) == 0 {
+		return nil
+	}
+
+	e.mu.Lock()
+	if !e.dirty {
+		e.mu.Unlock()
+		return nil
+	}
+	snapshot := memorySnapshot{
+		Docs:   make([]*Document, 0, len(e.docs)),
+		Pinned: e.pinned.Array(),
+	}
+	for _, doc := range e.docs {
+		snapshot.Docs = append(snapshot.Docs, doc.Document)
+	}
+	e.dirty = false
+	e.mu.Unlock()
+
+	if err := writeSnapshot(e.file, &snapshot); err != nil {
+		e.mu.Lock()
+		e.dirty = true // 次回に再試行する
+		e.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// writeSnapshot インデックスをファイルに書き込みます
+func writeSnapshot(file string, snapshot *memorySnapshot) error {
+	// 書き込み途中で終了しても壊れないように一時ファイルに書き込んでから置き換える
+	tmp, err := ioutil.TempFile(filepath.Dir(file), filepath.Base(file)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create search index file: %w", err)
+	}
+	if err := gob.NewEncoder(tmp).Encode(snapshot); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to encode search index: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write search index file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), file); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to replace search index file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,322 @@
+package search
+
+import (
+	"context"
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+	"github.com/leandro-lugaresi/hub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traPtitech/traQ/event"
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/repository/mock_repository"
+	"github.com/traPtitech/traQ/service/channel/mock_channel"
+	"github.com/traPtitech/traQ/service/cluster"
+	"github.com/traPtitech/traQ/utils/message"
+	"github.com/traPtitech/traQ/utils/optional"
+	"github.com/traPtitech/traQ/utils/set"
+	"go.uber.org/zap"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestDocument(channelID, userID uuid.UUID, text string, createdAt time.Time) *Document {
+	return &Document{
+		MessageID: uuid.Must(uuid.NewV4()),
+		ChannelID: channelID,
+		UserID:    userID,
+		PlainText: text,
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	}
+}
+
+// mockMessageRepository deleted以外のメッセージが全て存在するメッセージリポジトリのモックを生成します
+func mockMessageRepository(ctrl *gomock.Controller, deleted ...uuid.UUID) *mock_repository.MockMessageRepository {
+	d := set.UUIDSetFromArray(deleted)
+	repo := mock_repository.NewMockMessageRepository(ctrl)
+	repo.EXPECT().GetMessagesByIDs(gomock.Any()).DoAndReturn(func(ids []uuid.UUID) ([]*model.Message, error) {
+		messages := make([]*model.Message, 0, len(ids))
+		for _, id := range ids {
+			if !d.Contains(id) {
+				messages = append(messages, &model.Message{ID: id})
+			}
+		}
+		return messages, nil
+	}).AnyTimes()
+	return repo
+}
+
+func hitIDs(r *Result) []uuid.UUID {
+	ids := make([]uuid.UUID, len(r.Hits))
+	for i, h := range r.Hits {
+		ids[i] = h.MessageID
+	}
+	return ids
+}
+
+func TestMemoryEngine_Do(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userID := uuid.Must(uuid.NewV4())
+	otherUserID := uuid.Must(uuid.NewV4())
+	publicCh := uuid.Must(uuid.NewV4())
+	privateCh := uuid.Must(uuid.NewV4())
+
+	cm := mock_channel.NewMockManager(ctrl)
+	cm.EXPECT().IsChannelAccessibleToUser(userID, publicCh).Return(true, nil).AnyTimes()
+	cm.EXPECT().IsChannelAccessibleToUser(userID, privateCh).Return(false, nil).AnyTimes()
+
+	e, err := NewMemoryEngine("", cm, mockMessageRepository(ctrl), hub.New(), cluster.NewStandaloneBus(), zap.NewNop())
+	require.NoError(t, err)
+	defer e.Close()
+
+	now := time.Now()
+	d1 := newTestDocument(publicCh, userID, "今日はいい天気。元気はない", now.Add(-3*time.Hour))
+	d2 := newTestDocument(publicCh, otherUserID, "明日の天気は雨らしい", now.Add(-2*time.Hour))
+	d3 := newTestDocument(privateCh, otherUserID, "天気の話は秘密", now.Add(-1*time.Hour))
+	d4 := newTestDocument(publicCh, otherUserID, "Hello ＷＯＲＬＤ", now)
+	d4.HasFile = true
+	require.NoError(t, e.Index(d1, d2, d3, d4))
+
+	t.Run("empty query", func(t *testing.T) {
+		t.Parallel()
+		_, err := e.Do(&Query{UserID: userID})
+		assert.EqualError(t, err, ErrEmptyQuery.Error())
+	})
+
+	t.Run("japanese word", func(t *testing.T) {
+		t.Parallel()
+		r, err := e.Do(&Query{UserID: userID, Words: []string{"天気"}})
+		if assert.NoError(t, err) {
+			assert.Equal(t, 2, r.TotalHits)
+			assert.Equal(t, []uuid.UUID{d2.MessageID, d1.MessageID}, hitIDs(r))
+		}
+	})
+
+	t.Run("single rune word", func(t *testing.T) {
+		t.Parallel()
+		r, err := e.Do(&Query{UserID: userID, Words: []string{"雨"}})
+		if assert.NoError(t, err) {
+			assert.Equal(t, []uuid.UUID{d2.MessageID}, hitIDs(r))
+		}
+	})
+
+	t.Run("normalized word", func(t *testing.T) {
+		t.Parallel()
+		r, err := e.Do(&Query{UserID: userID, Words: []string{"world"}})
+		if assert.NoError(t, err) {
+			assert.Equal(t, []uuid.UUID{d4.MessageID}, hitIDs(r))
+		}
+	})
+
+	t.Run("no false positive", func(t *testing.T) {
+		t.Parallel()
+		// d1は"天気は"の全bi-gramを含むが連続していない
+		r, err := e.Do(&Query{UserID: userID, Words: []string{"天気は"}})
+		if assert.NoError(t, err) {
+			assert.Equal(t, []uuid.UUID{d2.MessageID}, hitIDs(r))
+		}
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		t.Parallel()
+		r, err := e.Do(&Query{UserID: userID, Words: []string{"天気"}, From: optional.UUIDFrom(userID)})
+		if assert.NoError(t, err) {
+			assert.Equal(t, []uuid.UUID{d1.MessageID}, hitIDs(r))
+		}
+		r, err = e.Do(&Query{UserID: userID, HasFile: true})
+		if assert.NoError(t, err) {
+			assert.Equal(t, []uuid.UUID{d4.MessageID}, hitIDs(r))
+		}
+		r, err = e.Do(&Query{UserID: userID, After: optional.TimeFrom(now.Add(-150 * time.Minute)), In: optional.UUIDFrom(publicCh)})
+		if assert.NoError(t, err) {
+			assert.Equal(t, []uuid.UUID{d4.MessageID, d2.MessageID}, hitIDs(r))
+		}
+	})
+
+	t.Run("limit and offset", func(t *testing.T) {
+		t.Parallel()
+		r, err := e.Do(&Query{UserID: userID, In: optional.UUIDFrom(publicCh), Limit: 1, Offset: 1})
+		if assert.NoError(t, err) {
+			assert.Equal(t, 3, r.TotalHits)
+			assert.Equal(t, []uuid.UUID{d2.MessageID}, hitIDs(r))
+		}
+	})
+}
+
+func TestMemoryEngine_IndexAndDelete(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userID := uuid.Must(uuid.NewV4())
+	channelID := uuid.Must(uuid.NewV4())
+	cm := mock_channel.NewMockManager(ctrl)
+	cm.EXPECT().IsChannelAccessibleToUser(userID, channelID).Return(true, nil).AnyTimes()
+
+	e, err := NewMemoryEngine("", cm, mockMessageRepository(ctrl), hub.New(), cluster.NewStandaloneBus(), zap.NewNop())
+	require.NoError(t, err)
+	defer e.Close()
+
+	doc := newTestDocument(channelID, userID, "りんごを食べた", time.Now())
+	require.NoError(t, e.Index(doc))
+
+	// 更新
+	updated := *doc
+	updated.PlainText = "みかんを食べた"
+	require.NoError(t, e.Index(&updated))
+	r, err := e.Do(&Query{UserID: userID, Words: []string{"りんご"}})
+	if assert.NoError(t, err) {
+		assert.Equal(t, 0, r.TotalHits)
+	}
+	r, err = e.Do(&Query{UserID: userID, Words: []string{"みかん"}})
+	if assert.NoError(t, err) {
+		assert.Equal(t, 1, r.TotalHits)
+	}
+
+	// ピン留め
+	require.NoError(t, e.(pinIndexer).SetPinned(doc.MessageID, true))
+	r, err = e.Do(&Query{UserID: userID, IsPinned: true})
+	if assert.NoError(t, err) {
+		assert.Equal(t, 1, r.TotalHits)
+	}
+
+	// チャンネル統合
+	mergedCh := uuid.Must(uuid.NewV4())
+	cm.EXPECT().IsChannelAccessibleToUser(userID, mergedCh).Return(true, nil).AnyTimes()
+	require.NoError(t, e.(channelMerger).MergeChannel(doc.ChannelID, mergedCh))
+	r, err = e.Do(&Query{UserID: userID, In: optional.UUIDFrom(mergedCh)})
+	if assert.NoError(t, err) {
+		assert.Equal(t, 1, r.TotalHits)
+	}
+	doc.ChannelID = mergedCh
+
+	// 削除
+	require.NoError(t, e.Delete(doc.MessageID))
+	r, err = e.Do(&Query{UserID: userID, Words: []string{"みかん"}})
+	if assert.NoError(t, err) {
+		assert.Equal(t, 0, r.TotalHits)
+	}
+	r, err = e.Do(&Query{UserID: userID, IsPinned: true})
+	if assert.NoError(t, err) {
+		assert.Equal(t, 0, r.TotalHits)
+	}
+}
+
+func TestMemoryEngine_DoDeletedMessage(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userID := uuid.Must(uuid.NewV4())
+	channelID := uuid.Must(uuid.NewV4())
+	cm := mock_channel.NewMockManager(ctrl)
+	cm.EXPECT().IsChannelAccessibleToUser(userID, channelID).Return(true, nil).AnyTimes()
+
+	now := time.Now()
+	alive := newTestDocument(channelID, userID, "残っているメッセージ", now.Add(-time.Hour))
+	deleted := newTestDocument(channelID, userID, "削除されたメッセージ", now)
+
+	e, err := NewMemoryEngine("", cm, mockMessageRepository(ctrl, deleted.MessageID), hub.New(), cluster.NewStandaloneBus(), zap.NewNop())
+	require.NoError(t, err)
+	defer e.Close()
+	require.NoError(t, e.Index(alive, deleted))
+
+	// 削除イベントを取りこぼしたメッセージはヒットせず、総件数にも含まれない
+	r, err := e.Do(&Query{UserID: userID, Words: []string{"メッセージ"}})
+	if assert.NoError(t, err) {
+		assert.Equal(t, 1, r.TotalHits)
+		assert.Equal(t, []uuid.UUID{alive.MessageID}, hitIDs(r))
+		assert.Equal(t, alive.MessageID, r.Hits[0].Message.ID)
+	}
+	r, err = e.Do(&Query{UserID: userID, Words: []string{"削除"}})
+	if assert.NoError(t, err) {
+		assert.Equal(t, 0, r.TotalHits)
+	}
+}
+
+func TestMemoryEngine_Persistence(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userID := uuid.Must(uuid.NewV4())
+	channelID := uuid.Must(uuid.NewV4())
+	cm := mock_channel.NewMockManager(ctrl)
+	cm.EXPECT().IsChannelAccessibleToUser(userID, channelID).Return(true, nil).AnyTimes()
+
+	dir, err := ioutil.TempDir("", "traq-search")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "search.idx")
+
+	e, err := NewMemoryEngine(file, cm, mockMessageRepository(ctrl), hub.New(), cluster.NewStandaloneBus(), zap.NewNop())
+	require.NoError(t, err)
+	doc := newTestDocument(channelID, userID, "保存されるメッセージ", time.Now())
+	require.NoError(t, e.Index(doc))
+	require.NoError(t, e.(pinIndexer).SetPinned(doc.MessageID, true))
+	require.NoError(t, e.Close())
+
+	e, err = NewMemoryEngine(file, cm, mockMessageRepository(ctrl), hub.New(), cluster.NewStandaloneBus(), zap.NewNop())
+	require.NoError(t, err)
+	defer e.Close()
+	r, err := e.Do(&Query{UserID: userID, Words: []string{"メッセージ"}, IsPinned: true})
+	if assert.NoError(t, err) {
+		assert.Equal(t, []uuid.UUID{doc.MessageID}, hitIDs(r))
+	}
+
+	require.NoError(t, e.Reset())
+	r, err = e.Do(&Query{UserID: userID, Words: []string{"メッセージ"}})
+	if assert.NoError(t, err) {
+		assert.Equal(t, 0, r.TotalHits)
+	}
+}
+
+func TestMemoryEngine_Cluster(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userID := uuid.Must(uuid.NewV4())
+	channelID := uuid.Must(uuid.NewV4())
+	cm := mock_channel.NewMockManager(ctrl)
+	cm.EXPECT().IsChannelAccessibleToUser(userID, channelID).Return(true, nil).AnyTimes()
+
+	network := cluster.NewMemoryNetwork()
+	hubs := []*hub.Hub{hub.New(), hub.New()}
+	engines := make([]Engine, len(hubs))
+	for i, h := range hubs {
+		bus := cluster.NewBus(network.NewTransport(), zap.NewNop())
+		e, err := NewMemoryEngine("", cm, mockMessageRepository(ctrl), h, bus, zap.NewNop())
+		require.NoError(t, err)
+		bus.Start()
+		engines[i] = e
+		defer e.Close()
+		defer bus.Shutdown(context.Background())
+	}
+
+	// ノード0で投稿されたメッセージがノード1でも検索できる
+	m := &model.Message{ID: uuid.Must(uuid.NewV4()), UserID: userID, ChannelID: channelID, Text: "クラスタの検索", CreatedAt: time.Now()}
+	hubs[0].Publish(hub.Message{Name: event.MessageCreated, Fields: hub.Fields{"message_id": m.ID, "message": m, "parse_result": message.Parse(m.Text)}})
+	assert.Eventually(t, func() bool {
+		r, err := engines[1].Do(&Query{UserID: userID, Words: []string{"検索"}})
+		return err == nil && r.TotalHits == 1
+	}, time.Second, 10*time.Millisecond)
+
+	hubs[0].Publish(hub.Message{Name: event.MessageDeleted, Fields: hub.Fields{"message_id": m.ID, "message": m}})
+	assert.Eventually(t, func() bool {
+		r, err := engines[1].Do(&Query{UserID: userID, Words: []string{"検索"}})
+		return err == nil && r.TotalHits == 0
+	}, time.Second, 10*time.Millisecond)
+}
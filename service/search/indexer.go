@@ -0,0 +1,105 @@
+package search
+
+import (
+	"encoding/json"
+	"github.com/gofrs/uuid"
+	"github.com/leandro-lugaresi/hub"
+	"github.com/traPtitech/traQ/event"
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/service/cluster"
+	"github.com/traPtitech/traQ/utils/message"
+	"go.uber.org/zap"
+)
+
+// clusterTopic インデックスの変更を他ノードに中継するトピック
+const clusterTopic = "search.index"
+
+// indexOp インデックスへの変更操作
+type indexOp struct {
+	Topic     string    `json:"topic"`
+	Document  *Document `json:"document,omitempty"`
+	MessageID uuid.UUID `json:"messageId,omitempty"`
+	ChannelID uuid.UUID `json:"channelId,omitempty"`
+	ToID      uuid.UUID `json:"toId,omitempty"`
+}
+
+// startIndexer メッセージイベントを購読し、インデックスを最新に保ちます
+//
+// busがnilでない場合、自ノードで発生したイベントによる変更を他ノードに中継し、他ノードからの変更を適用します。
+// インデックスをプロセス内に保持するエンジンは、他ノードで投稿されたメッセージもこれによって検索できるようになります。
+func startIndexer(e Engine, h *hub.Hub, bus *cluster.Bus, logger *zap.Logger) {
+	topics := []string{event.MessageCreated, event.MessageReplied, event.MessageUpdated, event.MessageDeleted}
+	if _, ok := e.(pinIndexer); ok {
+		topics = append(topics, event.MessagePinned, event.MessageUnpinned)
+	}
+	if _, ok := e.(channelMerger); ok {
+		topics = append(topics, event.ChannelMerged)
+	}
+
+	if bus != nil {
+		bus.Subscribe(clusterTopic, func(_ string, body []byte) {
+			var op indexOp
+			if err := json.Unmarshal(body, &op); err != nil {
+				logger.Error("failed to decode index operation", zap.Error(err))
+				return
+			}
+			applyIndexOp(e, &op, logger)
+		})
+	}
+
+	sub := h.Subscribe(100, topics...)
+	go func() {
+		for ev := range sub.Receiver {
+			op := &indexOp{Topic: ev.Topic()}
+			switch ev.Topic() {
+			case event.MessageCreated, event.MessageReplied:
+				m := ev.Fields["message"].(*model.Message)
+				op.Document = NewDocument(m, ev.Fields["parse_result"].(*message.ParseResult))
+			case event.MessageUpdated:
+				op.Document = NewDocument(ev.Fields["message"].(*model.Message), nil)
+			case event.MessageDeleted, event.MessagePinned, event.MessageUnpinned:
+				op.MessageID = ev.Fields["message_id"].(uuid.UUID)
+			case event.ChannelMerged:
+				op.ChannelID = ev.Fields["channel_id"].(uuid.UUID)
+				op.ToID = ev.Fields["to_channel_id"].(uuid.UUID)
+			}
+			applyIndexOp(e, op, logger)
+			if bus != nil {
+				bus.Publish(clusterTopic, op)
+			}
+		}
+	}()
+}
+
+// applyIndexOp インデックスへの変更操作を適用します
+func applyIndexOp(e Engine, op *indexOp, logger *zap.Logger) {
+	switch op.Topic {
+	case event.MessageCreated, event.MessageReplied, event.MessageUpdated:
+		if op.Document == nil {
+			return
+		}
+		if err := e.Index(op.Document); err != nil {
+			logger.Error("failed to index message", zap.Error(err), zap.Stringer("messageId", op.Document.MessageID))
+		}
+	case event.MessageDeleted:
+		if err := e.Delete(op.MessageID); err != nil {
+			logger.Error("failed to delete message from index", zap.Error(err), zap.Stringer("messageId", op.MessageID))
+		}
+	case event.MessagePinned, event.MessageUnpinned:
+		pi, ok := e.(pinIndexer)
+		if !ok {
+			return
+		}
+		if err := pi.SetPinned(op.MessageID, op.Topic == event.MessagePinned); err != nil {
+			logger.Error("failed to update pinned state in index", zap.Error(err), zap.Stringer("messageId", op.MessageID))
+		}
+	case event.ChannelMerged:
+		cm, ok := e.(channelMerger)
+		if !ok {
+			return
+		}
+		if err := cm.MergeChannel(op.ChannelID, op.ToID); err != nil {
+			logger.Error("failed to merge channel in index", zap.Error(err), zap.Stringer("channelId", op.ChannelID), zap.Stringer("toChannelId", op.ToID))
+		}
+	}
+}
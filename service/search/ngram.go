@@ -0,0 +1,40 @@
+package search
+
+import (
+	"golang.org/x/text/unicode/norm"
+	"strings"
+	"unicode"
+)
+
+// ngramSize インデックスに用いるn-gramのn
+const ngramSize = 2
+
+// normalize 検索用に文字列を正規化します
+//
+// NFKC正規化(全角英数字・半角カナの統一)を行い、英字を小文字に揃えます。
+// 連続する空白文字は一つの半角スペースにまとめます。
+func normalize(s string) string {
+	s = strings.ToLower(norm.NFKC.String(s))
+	return strings.Join(strings.FieldsFunc(s, unicode.IsSpace), " ")
+}
+
+// ngrams 正規化済みの文字列からn-gramを重複なく列挙します
+//
+// 文字列がn文字未満の場合はnilを返します。
+func ngrams(s string) []string {
+	runes := []rune(s)
+	if len(runes) < ngramSize {
+		return nil
+	}
+	seen := make(map[string]struct{}, len(runes))
+	result := make([]string, 0, len(runes))
+	for i := 0; i+ngramSize <= len(runes); i++ {
+		g := string(runes[i : i+ngramSize])
+		if _, ok := seen[g]; ok {
+			continue
+		}
+		seen[g] = struct{}{}
+		result = append(result, g)
+	}
+	return result
+}
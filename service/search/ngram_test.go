@@ -0,0 +1,33 @@
+package search
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"Hello World", "hello world"},
+		{"ＴＲＡＱ１２３", "traq123"},
+		{"ﾄﾗｯｸ", "トラック"},
+		{"  a \n\t b  ", "a b"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, normalize(tt.in), tt.in)
+	}
+}
+
+func TestNgrams(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, ngrams(""))
+	assert.Nil(t, ngrams("あ"))
+	assert.Equal(t, []string{"とら", "らっ", "っく"}, ngrams("とらっく"))
+	assert.Equal(t, []string{"ああ"}, ngrams("あああ"))
+}
@@ -0,0 +1,124 @@
+package search
+
+import (
+	"fmt"
+	"github.com/traPtitech/traQ/utils/optional"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ParsedQuery 検索文字列をパースした結果
+type ParsedQuery struct {
+	// Words 語句・フレーズ
+	Words []string
+	// From from:で指定されたユーザー名
+	From string
+	// In in:で指定されたチャンネルパス
+	In string
+	// Before before:で指定された日時
+	Before optional.Time
+	// After after:で指定された日時
+	After optional.Time
+	// HasFile has:fileが指定されたかどうか
+	HasFile bool
+	// IsPinned is:pinnedが指定されたかどうか
+	IsPinned bool
+}
+
+// ParseQuery 検索文字列をパースします
+//
+// 空白区切りの語句、ダブルクォートで囲まれたフレーズ及び以下の演算子に対応しています。
+//
+//	from:ユーザー名
+//	in:チャンネルパス
+//	before:日付(YYYY-MM-DD)または日時(RFC3339)
+//	after:日付(YYYY-MM-DD)または日時(RFC3339)
+//	has:file
+//	is:pinned
+func ParseQuery(s string) (*ParsedQuery, error) {
+	q := &ParsedQuery{}
+	for _, token := range tokenizeQuery(s) {
+		if token.quoted {
+			q.Words = append(q.Words, token.value)
+			continue
+		}
+
+		i := strings.IndexRune(token.value, ':')
+		if i <= 0 || i == len(token.value)-1 {
+			q.Words = append(q.Words, token.value)
+			continue
+		}
+		op, arg := strings.ToLower(token.value[:i]), token.value[i+1:]
+		switch op {
+		case "from":
+			q.From = strings.TrimPrefix(arg, "@")
+		case "in":
+			q.In = strings.TrimPrefix(arg, "#")
+		case "before":
+			t, err := parseQueryTime(arg)
+			if err != nil {
+				return nil, fmt.Errorf("invalid before: %s", arg)
+			}
+			q.Before = optional.TimeFrom(t)
+		case "after":
+			t, err := parseQueryTime(arg)
+			if err != nil {
+				return nil, fmt.Errorf("invalid after: %s", arg)
+			}
+			q.After = optional.TimeFrom(t)
+		case "has":
+			if strings.ToLower(arg) != "file" {
+				return nil, fmt.Errorf("unsupported has: %s", arg)
+			}
+			q.HasFile = true
+		case "is":
+			if strings.ToLower(arg) != "pinned" {
+				return nil, fmt.Errorf("unsupported is: %s", arg)
+			}
+			q.IsPinned = true
+		default:
+			q.Words = append(q.Words, token.value)
+		}
+	}
+	return q, nil
+}
+
+type queryToken struct {
+	value  string
+	quoted bool
+}
+
+func tokenizeQuery(s string) []queryToken {
+	var (
+		tokens []queryToken
+		buf    strings.Builder
+		quoted bool
+	)
+	flush := func(isPhrase bool) {
+		if v := strings.TrimSpace(buf.String()); len(v) > 0 {
+			tokens = append(tokens, queryToken{value: v, quoted: isPhrase})
+		}
+		buf.Reset()
+	}
+	for _, r := range s {
+		switch {
+		case r == '"':
+			flush(quoted)
+			quoted = !quoted
+		case unicode.IsSpace(r) && !quoted:
+			flush(false)
+		default:
+			buf.WriteRune(r)
+		}
+	}
+	flush(quoted)
+	return tokens
+}
+
+func parseQueryTime(s string) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
@@ -0,0 +1,70 @@
+package search
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestParseQuery(t *testing.T) {
+	t.Parallel()
+
+	t.Run("words and phrases", func(t *testing.T) {
+		t.Parallel()
+		q, err := ParseQuery(`hello  "good morning" traQ`)
+		if assert.NoError(t, err) {
+			assert.Equal(t, []string{"hello", "good morning", "traQ"}, q.Words)
+		}
+	})
+
+	t.Run("operators", func(t *testing.T) {
+		t.Parallel()
+		q, err := ParseQuery(`from:@takashi in:#general/random before:2020-06-01 after:2020-05-01 has:file is:pinned テスト`)
+		if assert.NoError(t, err) {
+			assert.Equal(t, []string{"テスト"}, q.Words)
+			assert.Equal(t, "takashi", q.From)
+			assert.Equal(t, "general/random", q.In)
+			assert.True(t, q.HasFile)
+			assert.True(t, q.IsPinned)
+			if assert.True(t, q.Before.Valid) {
+				assert.Equal(t, time.Date(2020, 6, 1, 0, 0, 0, 0, time.Local), q.Before.Time)
+			}
+			if assert.True(t, q.After.Valid) {
+				assert.Equal(t, time.Date(2020, 5, 1, 0, 0, 0, 0, time.Local), q.After.Time)
+			}
+		}
+	})
+
+	t.Run("RFC3339 time", func(t *testing.T) {
+		t.Parallel()
+		q, err := ParseQuery(`before:2020-06-01T12:00:00Z`)
+		if assert.NoError(t, err) && assert.True(t, q.Before.Valid) {
+			assert.True(t, time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC).Equal(q.Before.Time))
+		}
+	})
+
+	t.Run("quoted operator is a phrase", func(t *testing.T) {
+		t.Parallel()
+		q, err := ParseQuery(`"from:takashi"`)
+		if assert.NoError(t, err) {
+			assert.Equal(t, []string{"from:takashi"}, q.Words)
+			assert.Empty(t, q.From)
+		}
+	})
+
+	t.Run("unknown operator is a word", func(t *testing.T) {
+		t.Parallel()
+		q, err := ParseQuery(`http://example.com a:`)
+		if assert.NoError(t, err) {
+			assert.Equal(t, []string{"http://example.com", "a:"}, q.Words)
+		}
+	})
+
+	t.Run("invalid operator values", func(t *testing.T) {
+		t.Parallel()
+		for _, s := range []string{"before:yesterday", "after:2020/01/01", "has:image", "is:starred"} {
+			_, err := ParseQuery(s)
+			assert.Error(t, err, s)
+		}
+	})
+}
@@ -0,0 +1,72 @@
+package search
+
+import (
+	"fmt"
+	"github.com/gofrs/uuid"
+	"github.com/jinzhu/gorm"
+	"github.com/traPtitech/traQ/model"
+)
+
+// Reindex messagesテーブルからインデックスを再構築します
+//
+// 既存のインデックスは全て削除されます。メッセージはbatchSize件ずつ読み込まれ、
+// 1バッチ処理する毎にprogressが処理済み件数と総件数を引数に呼び出されます。
+func Reindex(e Engine, db *gorm.DB, batchSize int, progress func(done, total int)) error {
+	if batchSize <= 0 {
+		return fmt.Errorf("invalid batch size: %d", batchSize)
+	}
+
+	var total int
+	if err := db.Model(&model.Message{}).Count(&total).Error; err != nil {
+		return fmt.Errorf("failed to count messages: %w", err)
+	}
+	if err := e.Reset(); err != nil {
+		return fmt.Errorf("failed to reset search index: %w", err)
+	}
+	pi, indexPin := e.(pinIndexer)
+
+	done := 0
+	var last *model.Message
+	for {
+		tx := db.Order("created_at, id").Limit(batchSize)
+		if last != nil {
+			tx = tx.Where("created_at > ? OR (created_at = ? AND id > ?)", last.CreatedAt, last.CreatedAt, last.ID)
+		}
+		var messages []*model.Message
+		if err := tx.Find(&messages).Error; err != nil {
+			return fmt.Errorf("failed to fetch messages: %w", err)
+		}
+		if len(messages) == 0 {
+			break
+		}
+
+		docs := make([]*Document, len(messages))
+		ids := make([]uuid.UUID, len(messages))
+		for i, m := range messages {
+			docs[i] = NewDocument(m, nil)
+			ids[i] = m.ID
+		}
+		if err := e.Index(docs...); err != nil {
+			return fmt.Errorf("failed to index messages: %w", err)
+		}
+
+		if indexPin {
+			var pinned []uuid.UUID
+			if err := db.Model(&model.Pin{}).Where("message_id IN (?)", ids).Pluck("message_id", &pinned).Error; err != nil {
+				return fmt.Errorf("failed to fetch pins: %w", err)
+			}
+			for _, id := range pinned {
+				if err := pi.SetPinned(id, true); err != nil {
+					return fmt.Errorf("failed to index pins: %w", err)
+				}
+			}
+		}
+
+		done += len(messages)
+		last = messages[len(messages)-1]
+		if progress != nil {
+			progress(done, total)
+		}
+	}
+	return nil
+}
@@ -2,31 +2,47 @@ package service
 
 import (
 	"github.com/traPtitech/traQ/service/bot"
+	botws "github.com/traPtitech/traQ/service/bot/ws"
 	"github.com/traPtitech/traQ/service/channel"
+	"github.com/traPtitech/traQ/service/cluster"
 	"github.com/traPtitech/traQ/service/counter"
+	"github.com/traPtitech/traQ/service/digest"
+	"github.com/traPtitech/traQ/service/export"
 	"github.com/traPtitech/traQ/service/fcm"
 	"github.com/traPtitech/traQ/service/file"
 	"github.com/traPtitech/traQ/service/imaging"
 	"github.com/traPtitech/traQ/service/notification"
+	"github.com/traPtitech/traQ/service/ratelimit"
 	"github.com/traPtitech/traQ/service/rbac"
+	"github.com/traPtitech/traQ/service/scheduler"
+	"github.com/traPtitech/traQ/service/search"
 	"github.com/traPtitech/traQ/service/viewer"
+	"github.com/traPtitech/traQ/service/webhook"
 	"github.com/traPtitech/traQ/service/webrtcv3"
 	"github.com/traPtitech/traQ/service/ws"
 )
 
 type Services struct {
 	BOT                  bot.Service
+	BotWS                *botws.Streamer
 	ChannelManager       channel.Manager
+	Cluster              *cluster.Bus
 	OnlineCounter        *counter.OnlineCounter
 	UnreadMessageCounter counter.UnreadMessageCounter
 	MessageCounter       counter.MessageCounter
 	ChannelCounter       counter.ChannelCounter
+	Digest               digest.Service
+	Exporter             export.Exporter
 	FCM                  fcm.Client
 	FileManager          file.Manager
 	Imaging              imaging.Processor
 	Notification         *notification.Service
 	RBAC                 rbac.RBAC
+	RateLimiter          ratelimit.Limiter
+	Scheduler            scheduler.Scheduler
+	Search               search.Engine
 	ViewerManager        *viewer.Manager
+	Webhook              webhook.Service
 	WebRTCv3             *webrtcv3.Manager
 	WS                   *ws.Streamer
 }
@@ -8,17 +8,24 @@ import (
 
 var ProviderSet = wire.NewSet(wire.FieldsOf(new(*Services),
 	"BOT",
+	"BotWS",
 	"ChannelManager",
+	"Cluster",
 	"OnlineCounter",
 	"UnreadMessageCounter",
 	"MessageCounter",
 	"ChannelCounter",
+	"Exporter",
 	"FCM",
 	"FileManager",
 	"Imaging",
 	"Notification",
 	"RBAC",
+	"RateLimiter",
+	"Scheduler",
+	"Search",
 	"ViewerManager",
+	"Webhook",
 	"WebRTCv3",
 	"WS",
 ))
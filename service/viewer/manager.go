@@ -1,21 +1,35 @@
 package viewer
 
 import (
+	"encoding/json"
 	"github.com/gofrs/uuid"
 	"github.com/leandro-lugaresi/hub"
 	"github.com/traPtitech/traQ/event"
+	"github.com/traPtitech/traQ/service/cluster"
 	"sync"
 	"time"
 )
 
+const clusterTopic = "viewer.changed"
+
 // Manager チャンネル閲覧者マネージャ
+//
+// 他ノードの閲覧者状態はノード間イベントバスを通じて受信し、自ノードの状態とマージして扱います。
 type Manager struct {
 	hub      *hub.Hub
+	cluster  *cluster.Bus
 	channels map[uuid.UUID]map[*viewer]struct{}
 	viewers  map[interface{}]*viewer
+	remote   map[string]map[uuid.UUID]map[uuid.UUID]StateWithTime // nodeID -> channelID -> userID -> state
 	mu       sync.RWMutex
 }
 
+// clusterMessage ノード間で送受信する閲覧者状態
+type clusterMessage struct {
+	ChannelID uuid.UUID                   `json:"channelId"`
+	Viewers   map[uuid.UUID]StateWithTime `json:"viewers"`
+}
+
 type viewer struct {
 	key       interface{}
 	userID    uuid.UUID
@@ -24,12 +38,17 @@ type viewer struct {
 }
 
 // NewManager チャンネル閲覧者マネージャーを生成します
-func NewManager(hub *hub.Hub) *Manager {
+func NewManager(hub *hub.Hub, bus *cluster.Bus) *Manager {
 	vm := &Manager{
 		hub:      hub,
+		cluster:  bus,
 		channels: map[uuid.UUID]map[*viewer]struct{}{},
 		viewers:  map[interface{}]*viewer{},
+		remote:   map[string]map[uuid.UUID]map[uuid.UUID]StateWithTime{},
 	}
+	bus.Subscribe(clusterTopic, vm.handleRemote)
+	bus.OnNodeJoined(vm.sendSnapshot)
+	bus.OnNodeLeft(vm.removeNode)
 
 	go func() {
 		for range time.NewTicker(5 * time.Minute).C {
@@ -45,7 +64,7 @@ func NewManager(hub *hub.Hub) *Manager {
 func (vm *Manager) GetChannelViewers(channelID uuid.UUID) map[uuid.UUID]StateWithTime {
 	vm.mu.RLock()
 	defer vm.mu.RUnlock()
-	return calculateChannelViewers(vm.channels[channelID])
+	return vm.mergedChannelViewers(channelID)
 }
 
 // SetViewer 指定したキーのチャンネル閲覧者状態を設定します
@@ -80,13 +99,7 @@ func (vm *Manager) SetViewer(key interface{}, userID uuid.UUID, channelID uuid.U
 				Time:  time.Now(),
 			}
 
-			vm.hub.Publish(hub.Message{
-				Name: event.ChannelViewersChanged,
-				Fields: hub.Fields{
-					"channel_id": oldC,
-					"viewers":    calculateChannelViewers(old),
-				},
-			})
+			vm.notifyChanged(oldC)
 		}
 	} else {
 		v = &viewer{
@@ -102,13 +115,7 @@ func (vm *Manager) SetViewer(key interface{}, userID uuid.UUID, channelID uuid.U
 	}
 
 	cv[v] = struct{}{}
-	vm.hub.Publish(hub.Message{
-		Name: event.ChannelViewersChanged,
-		Fields: hub.Fields{
-			"channel_id": channelID,
-			"viewers":    calculateChannelViewers(cv),
-		},
-	})
+	vm.notifyChanged(channelID)
 }
 
 // RemoveViewer 指定したキーのチャンネル閲覧者状態を削除します
@@ -124,16 +131,95 @@ func (vm *Manager) RemoveViewer(key interface{}) {
 	cv := vm.channels[v.channelID]
 	delete(vm.viewers, key)
 	delete(cv, v)
+	vm.notifyChanged(v.channelID)
+}
 
+// notifyChanged 指定したチャンネルの閲覧者状態の変化を通知します。vm.muのロックが必要です
+func (vm *Manager) notifyChanged(channelID uuid.UUID) {
+	vm.cluster.Publish(clusterTopic, &clusterMessage{
+		ChannelID: channelID,
+		Viewers:   calculateChannelViewers(vm.channels[channelID]),
+	})
 	vm.hub.Publish(hub.Message{
 		Name: event.ChannelViewersChanged,
 		Fields: hub.Fields{
-			"channel_id": v.channelID,
-			"viewers":    calculateChannelViewers(cv),
+			"channel_id": channelID,
+			"viewers":    vm.mergedChannelViewers(channelID),
 		},
 	})
 }
 
+// mergedChannelViewers 全ノードの閲覧者状態をマージします。vm.muのロックが必要です
+func (vm *Manager) mergedChannelViewers(channelID uuid.UUID) map[uuid.UUID]StateWithTime {
+	result := calculateChannelViewers(vm.channels[channelID])
+	for _, channels := range vm.remote {
+		for userID, state := range channels[channelID] {
+			if s, ok := result[userID]; ok && s.State > state.State {
+				continue
+			}
+			result[userID] = state
+		}
+	}
+	return result
+}
+
+// handleRemote 他ノードの閲覧者状態を反映します
+//
+// 変化の通知は発生元のノードで行われるため、ここでは行いません。
+func (vm *Manager) handleRemote(node string, body []byte) {
+	var m clusterMessage
+	if err := json.Unmarshal(body, &m); err != nil {
+		return
+	}
+
+	vm.mu.Lock()
+	defer vm.mu.Unlock()
+	channels, ok := vm.remote[node]
+	if !ok {
+		channels = map[uuid.UUID]map[uuid.UUID]StateWithTime{}
+		vm.remote[node] = channels
+	}
+	if len(m.Viewers) == 0 {
+		delete(channels, m.ChannelID)
+	} else {
+		channels[m.ChannelID] = m.Viewers
+	}
+}
+
+// sendSnapshot 自ノードの全閲覧者状態を送信します
+func (vm *Manager) sendSnapshot(string) {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+	for channelID, cv := range vm.channels {
+		if len(cv) > 0 {
+			vm.cluster.Publish(clusterTopic, &clusterMessage{
+				ChannelID: channelID,
+				Viewers:   calculateChannelViewers(cv),
+			})
+		}
+	}
+}
+
+// removeNode 離脱したノードの閲覧者状態を破棄します
+func (vm *Manager) removeNode(node string) {
+	vm.mu.Lock()
+	defer vm.mu.Unlock()
+	channels := vm.remote[node]
+	delete(vm.remote, node)
+	if !vm.cluster.IsLeader() {
+		return
+	}
+	for channelID := range channels {
+		vm.hub.Publish(hub.Message{
+			Name: event.ChannelViewersChanged,
+			Fields: hub.Fields{
+				"channel_id": channelID,
+				"viewers":    vm.mergedChannelViewers(channelID),
+			},
+		})
+	}
+}
+
 // 5分に１回呼び出される。チャンネルマップのお掃除
 func (vm *Manager) gc() {
 	for cid, cv := range vm.channels {
@@ -0,0 +1,52 @@
+package viewer
+
+import (
+	"context"
+	"github.com/gofrs/uuid"
+	"github.com/leandro-lugaresi/hub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traPtitech/traQ/service/cluster"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+func TestManager_Cluster(t *testing.T) {
+	t.Parallel()
+
+	network := cluster.NewMemoryNetwork()
+	bus1 := cluster.NewBus(network.NewTransport(), zap.NewNop())
+	bus2 := cluster.NewBus(network.NewTransport(), zap.NewNop())
+	vm1 := NewManager(hub.New(), bus1)
+	vm2 := NewManager(hub.New(), bus2)
+
+	channelID := uuid.Must(uuid.NewV4())
+	user1 := uuid.Must(uuid.NewV4())
+	user2 := uuid.Must(uuid.NewV4())
+
+	// 参加前の状態はスナップショットとして送信される
+	vm1.SetViewer("a", user1, channelID, StateEditing)
+	bus1.Start()
+	bus2.Start()
+	require.Eventually(t, func() bool { return len(vm2.GetChannelViewers(channelID)) == 1 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, StateEditing, vm2.GetChannelViewers(channelID)[user1].State)
+
+	// 同じユーザーは状態の強い方が優先される
+	vm2.SetViewer("b", user1, channelID, StateNone)
+	vm2.SetViewer("c", user2, channelID, StateMonitoring)
+	require.Eventually(t, func() bool { return len(vm1.GetChannelViewers(channelID)) == 2 }, time.Second, 10*time.Millisecond)
+	for _, vm := range []*Manager{vm1, vm2} {
+		viewers := vm.GetChannelViewers(channelID)
+		assert.Equal(t, StateEditing, viewers[user1].State)
+		assert.Equal(t, StateMonitoring, viewers[user2].State)
+	}
+
+	vm1.RemoveViewer("a")
+	require.Eventually(t, func() bool { return vm2.GetChannelViewers(channelID)[user1].State == StateNone }, time.Second, 10*time.Millisecond)
+
+	// 離脱したノードの状態は破棄される
+	require.NoError(t, bus2.Shutdown(context.Background()))
+	require.Eventually(t, func() bool { return len(vm1.GetChannelViewers(channelID)) == 0 }, time.Second, 10*time.Millisecond)
+	require.NoError(t, bus1.Shutdown(context.Background()))
+}
@@ -47,3 +47,13 @@ func init() {
 		stringViewStates[k] = v
 	}
 }
+
+// UnmarshalJSON encoding/json.Unmarshaler 実装
+func (s *State) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := jsoniter.ConfigFastest.Unmarshal(data, &str); err != nil {
+		return err
+	}
+	*s = StateFromString(str)
+	return nil
+}
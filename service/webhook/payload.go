@@ -0,0 +1,41 @@
+package webhook
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/service/bot/event/payload"
+	"github.com/traPtitech/traQ/utils/message"
+	"time"
+)
+
+// Payload Outgoing Webhookの送信ペイロード
+type Payload struct {
+	payload.Base
+	OutgoingWebhookID uuid.UUID       `json:"outgoingWebhookId"`
+	WebhookID         uuid.UUID       `json:"webhookId"`
+	TriggerType       string          `json:"triggerType"`
+	Trigger           string          `json:"trigger"`
+	MatchedText       string          `json:"matchedText"`
+	Message           payload.Message `json:"message"`
+}
+
+// MakePayload Outgoing Webhookの送信ペイロードを生成します
+func MakePayload(et time.Time, w *model.OutgoingWebhook, matched string, m *model.Message, user model.UserInfo, parsed *message.ParseResult) *Payload {
+	embedded, _ := message.ExtractEmbedding(m.Text)
+	return &Payload{
+		Base:              payload.MakeBase(et),
+		OutgoingWebhookID: w.ID,
+		WebhookID:         w.WebhookID,
+		TriggerType:       w.TriggerType.String(),
+		Trigger:           w.Trigger,
+		MatchedText:       matched,
+		Message:           payload.MakeMessage(m, user, embedded, parsed.PlainText),
+	}
+}
+
+// Response Outgoing Webhookのレスポンス
+//
+// Textが空でない場合、トリガーとなったメッセージにWebhookユーザーとして返信します。
+type Response struct {
+	Text string `json:"text"`
+}
@@ -0,0 +1,11 @@
+package webhook
+
+import "context"
+
+// Service Outgoing Webhook配信サービス
+type Service interface {
+	// Start Outgoing Webhookの配信を開始します
+	Start()
+	// Shutdown Outgoing Webhookの配信を停止します
+	Shutdown(ctx context.Context) error
+}
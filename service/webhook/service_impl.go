@@ -0,0 +1,275 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gofrs/uuid"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/labstack/echo/v4"
+	"github.com/leandro-lugaresi/hub"
+	"github.com/traPtitech/traQ/event"
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/service/channel"
+	"github.com/traPtitech/traQ/service/ratelimit"
+	"github.com/traPtitech/traQ/utils/hmac"
+	"github.com/traPtitech/traQ/utils/message"
+	"go.uber.org/zap"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	headerWebhookRequestID = "X-TRAQ-WEBHOOK-REQUEST-ID"
+	headerWebhookSignature = "X-TRAQ-WEBHOOK-SIGNATURE"
+	headerUserAgent        = "User-Agent"
+	ua                     = "traQ_Outgoing_Webhook/1.0"
+
+	// maxResponseSize 読み込むレスポンスボディの最大サイズ
+	maxResponseSize = 1 << 20 // 1MB
+	// maxReplyLength 返信するメッセージ本文の最大文字数 (通常のメッセージ投稿と同じ)
+	maxReplyLength = 10000
+)
+
+// compiledTrigger コンパイル済みの正規表現トリガー
+type compiledTrigger struct {
+	trigger string
+	re      *regexp.Regexp
+}
+
+type serviceImpl struct {
+	repo    repository.Repository
+	cm      channel.Manager
+	limiter ratelimit.Limiter
+	hub     *hub.Hub
+	logger  *zap.Logger
+	client  http.Client
+
+	triggersLock sync.Mutex
+	triggers     map[uuid.UUID]*compiledTrigger
+
+	sub     hub.Subscription
+	wg      sync.WaitGroup
+	started bool
+}
+
+// NewService Outgoing Webhook配信サービスを生成します
+func NewService(repo repository.Repository, cm channel.Manager, limiter ratelimit.Limiter, hub *hub.Hub, logger *zap.Logger) Service {
+	return &serviceImpl{
+		repo:     repo,
+		cm:       cm,
+		limiter:  limiter,
+		hub:      hub,
+		logger:   logger.Named("webhook"),
+		triggers: map[uuid.UUID]*compiledTrigger{},
+		client: http.Client{
+			Jar:     nil,
+			Timeout: 5 * time.Second,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+func (s *serviceImpl) Start() {
+	if s.started {
+		return
+	}
+	s.started = true
+
+	s.sub = s.hub.Subscribe(100, event.MessageCreated, event.MessageReplied)
+	go func() {
+		for ev := range s.sub.Receiver {
+			m, ok := ev.Fields["message"].(*model.Message)
+			if !ok {
+				continue
+			}
+			parsed, ok := ev.Fields["parse_result"].(*message.ParseResult)
+			if !ok {
+				parsed = message.Parse(m.Text)
+			}
+			s.wg.Add(1)
+			go func() {
+				defer s.wg.Done()
+				if err := s.processMessage(time.Now(), m, parsed); err != nil {
+					s.logger.Error("an error occurred while processing outgoing webhooks", zap.Error(err), zap.Stringer("messageID", m.ID))
+				}
+			}()
+		}
+	}()
+	s.logger.Info("webhook service started")
+}
+
+func (s *serviceImpl) Shutdown(ctx context.Context) error {
+	if !s.started {
+		return nil
+	}
+	s.hub.Unsubscribe(s.sub)
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		s.logger.Info("webhook service shutdown")
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// processMessage 投稿されたメッセージにマッチするOutgoing Webhookに送信します
+func (s *serviceImpl) processMessage(et time.Time, m *model.Message, parsed *message.ParseResult) error {
+	hooks, err := s.repo.GetOutgoingWebhooksByChannelID(m.ChannelID)
+	if err != nil {
+		return err
+	}
+	if len(hooks) == 0 {
+		return nil
+	}
+
+	user, err := s.repo.GetUser(m.UserID, false)
+	if err != nil {
+		return err
+	}
+	// BOT・Webhookのメッセージには反応しない (応答のループを防ぐため)
+	if user.IsBot() {
+		return nil
+	}
+
+	for _, hook := range hooks {
+		var re *regexp.Regexp
+		if hook.TriggerType == model.OutgoingWebhookTriggerRegex {
+			re = s.triggerRegexp(hook)
+		}
+		matched, ok := hook.Match(re, m.Text)
+		if !ok {
+			continue
+		}
+		if err := s.send(et, hook, matched, m, user, parsed); err != nil {
+			s.logger.Warn("failed to send outgoing webhook", zap.Error(err), zap.Stringer("outgoingWebhookID", hook.ID))
+		}
+	}
+	return nil
+}
+
+// send Outgoing Webhookにメッセージを送信し、レスポンスの本文を返信します
+func (s *serviceImpl) send(et time.Time, hook *model.OutgoingWebhook, matched string, m *model.Message, user model.UserInfo, parsed *message.ParseResult) error {
+	body, err := jsoniter.ConfigFastest.Marshal(MakePayload(et, hook, matched, m, user, parsed))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set(headerUserAgent, ua)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+	req.Header.Set(headerWebhookRequestID, hook.ID.String()+"/"+m.ID.String())
+	req.Header.Set(headerWebhookSignature, hmac.SignTimestamp(time.Now(), body, hook.Secret))
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return &statusError{code: res.StatusCode}
+	}
+
+	text, err := readResponseText(res)
+	if err != nil || len(text) == 0 {
+		return err
+	}
+	if utf8.RuneCountInString(text) > maxReplyLength {
+		return fmt.Errorf("reply text is longer than %d characters", maxReplyLength)
+	}
+
+	// レスポンスを待つ間に変更されている可能性があるため、投稿前に再確認する
+	if _, err := s.repo.GetOutgoingWebhook(hook.ID); err != nil {
+		return err
+	}
+	w, err := s.repo.GetWebhook(hook.WebhookID)
+	if err != nil {
+		return err
+	}
+	if s.cm.PublicChannelTree().IsArchivedChannel(m.ChannelID) {
+		return fmt.Errorf("channel %s has been archived", m.ChannelID)
+	}
+	if ok, _ := s.limiter.Allow(ratelimit.Webhook, w.GetBotUserID(), ratelimit.PostMessage); !ok {
+		return errRateLimited
+	}
+	_, err = s.repo.CreateReply(w.GetBotUserID(), m.ID, text)
+	return err
+}
+
+// triggerRegexp Outgoing Webhookの正規表現トリガーのコンパイル済みの正規表現を返します
+//
+// コンパイル結果はトリガーが変更されるまでキャッシュされます。
+// 不正な正規表現の場合は警告を出力し、nilを返します。
+func (s *serviceImpl) triggerRegexp(hook *model.OutgoingWebhook) *regexp.Regexp {
+	s.triggersLock.Lock()
+	defer s.triggersLock.Unlock()
+
+	if c, ok := s.triggers[hook.ID]; ok && c.trigger == hook.Trigger {
+		return c.re
+	}
+	re, err := regexp.Compile(hook.Trigger)
+	if err != nil {
+		s.logger.Warn("invalid outgoing webhook trigger", zap.Error(err), zap.Stringer("outgoingWebhookID", hook.ID))
+	}
+	s.triggers[hook.ID] = &compiledTrigger{trigger: hook.Trigger, re: re}
+	return re
+}
+
+// readResponseText レスポンスから返信する本文を読み取ります
+//
+// application/jsonの場合はtextフィールド、text/plainの場合はボディ全体を本文とします。
+func readResponseText(res *http.Response) (string, error) {
+	if res.StatusCode == http.StatusNoContent {
+		return "", nil
+	}
+	b, err := ioutil.ReadAll(io.LimitReader(res.Body, maxResponseSize))
+	if err != nil {
+		return "", err
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(res.Header.Get(echo.HeaderContentType))
+	switch mediaType {
+	case echo.MIMEApplicationJSON:
+		var r Response
+		if err := jsoniter.ConfigFastest.Unmarshal(b, &r); err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(r.Text), nil
+	case echo.MIMETextPlain:
+		return strings.TrimSpace(string(b)), nil
+	default:
+		return "", nil
+	}
+}
+
+// errRateLimited Webhookのレート制限を超えたため返信しなかった
+var errRateLimited = errors.New("webhook rate limit exceeded")
+
+type statusError struct {
+	code int
+}
+
+func (e *statusError) Error() string {
+	return "unexpected status code: " + strconv.Itoa(e.code)
+}
@@ -0,0 +1,348 @@
+package webhook
+
+import (
+	"encoding/hex"
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/repository/mock_repository"
+	"github.com/traPtitech/traQ/service/channel/mock_channel"
+	"github.com/traPtitech/traQ/service/ratelimit"
+	"github.com/traPtitech/traQ/service/ratelimit/mock_ratelimit"
+	"github.com/traPtitech/traQ/testutils"
+	"github.com/traPtitech/traQ/utils/hmac"
+	"github.com/traPtitech/traQ/utils/message"
+	"go.uber.org/zap"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type Repo struct {
+	*mock_repository.MockUserRepository
+	*mock_repository.MockMessageRepository
+	*mock_repository.MockWebhookRepository
+	*mock_repository.MockOutgoingWebhookRepository
+	testutils.EmptyTestRepository
+}
+
+func setup(t *testing.T, ctrl *gomock.Controller) (*serviceImpl, *Repo, *mock_channel.MockTree, *mock_ratelimit.MockLimiter) {
+	t.Helper()
+	repo := &Repo{
+		MockUserRepository:            mock_repository.NewMockUserRepository(ctrl),
+		MockMessageRepository:         mock_repository.NewMockMessageRepository(ctrl),
+		MockWebhookRepository:         mock_repository.NewMockWebhookRepository(ctrl),
+		MockOutgoingWebhookRepository: mock_repository.NewMockOutgoingWebhookRepository(ctrl),
+	}
+	tree := mock_channel.NewMockTree(ctrl)
+	cm := mock_channel.NewMockManager(ctrl)
+	cm.EXPECT().PublicChannelTree().Return(tree).AnyTimes()
+	limiter := mock_ratelimit.NewMockLimiter(ctrl)
+	return NewService(repo, cm, limiter, nil, zap.NewNop()).(*serviceImpl), repo, tree, limiter
+}
+
+func TestServiceImpl_processMessage(t *testing.T) {
+	t.Parallel()
+
+	wb := &model.WebhookBot{
+		ID:        uuid.NewV3(uuid.Nil, "w"),
+		BotUserID: uuid.NewV3(uuid.Nil, "wu"),
+	}
+	u := &model.User{ID: uuid.NewV3(uuid.Nil, "u"), Name: "testman"}
+	bu := &model.User{ID: uuid.NewV3(uuid.Nil, "bu"), Name: "BOT_test", Bot: true}
+	channelID := uuid.NewV3(uuid.Nil, "c")
+
+	newHook := func(url string) *model.OutgoingWebhook {
+		return &model.OutgoingWebhook{
+			ID:          uuid.Must(uuid.NewV4()),
+			WebhookID:   wb.ID,
+			ChannelID:   channelID,
+			TriggerType: model.OutgoingWebhookTriggerRegex,
+			Trigger:     `^!ping\b`,
+			URL:         url,
+			Secret:      "secret",
+		}
+	}
+	newMessage := func(userID uuid.UUID, text string) *model.Message {
+		return &model.Message{
+			ID:        uuid.Must(uuid.NewV4()),
+			UserID:    userID,
+			ChannelID: channelID,
+			Text:      text,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		}
+	}
+
+	t.Run("matched, replied", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		s, repo, tree, limiter := setup(t, ctrl)
+
+		var received Payload
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body)
+			sig := r.Header.Get(headerWebhookSignature)
+			parts := strings.SplitN(sig, ",", 2)
+			if assert.Len(t, parts, 2) {
+				ts := strings.TrimPrefix(parts[0], "t=")
+				assert.Equal(t, "v1="+hex.EncodeToString(hmac.SHA256([]byte(ts+"."+string(body)), "secret")), parts[1])
+			}
+			assert.NoError(t, jsoniter.ConfigFastest.Unmarshal(body, &received))
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"text":"pong"}`))
+		}))
+		defer ts.Close()
+
+		hook := newHook(ts.URL)
+		m := newMessage(u.ID, "!ping please")
+		repo.MockOutgoingWebhookRepository.EXPECT().GetOutgoingWebhooksByChannelID(channelID).Return([]*model.OutgoingWebhook{hook}, nil)
+		repo.MockUserRepository.EXPECT().GetUser(u.ID, false).Return(u, nil)
+		repo.MockOutgoingWebhookRepository.EXPECT().GetOutgoingWebhook(hook.ID).Return(hook, nil)
+		repo.MockWebhookRepository.EXPECT().GetWebhook(wb.ID).Return(wb, nil)
+		tree.EXPECT().IsArchivedChannel(channelID).Return(false)
+		limiter.EXPECT().Allow(ratelimit.Webhook, wb.BotUserID, ratelimit.PostMessage).Return(true, time.Duration(0))
+		repo.MockMessageRepository.EXPECT().CreateReply(wb.BotUserID, m.ID, "pong").Return(&model.Message{}, nil)
+
+		require.NoError(t, s.processMessage(time.Now(), m, message.Parse(m.Text)))
+		assert.Equal(t, hook.ID, received.OutgoingWebhookID)
+		assert.Equal(t, "!ping", received.MatchedText)
+		assert.Equal(t, m.ID, received.Message.ID)
+		assert.Equal(t, "testman", received.Message.User.Name)
+	})
+
+	t.Run("matched, no reply", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		s, repo, _, _ := setup(t, ctrl)
+
+		called := false
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer ts.Close()
+
+		m := newMessage(u.ID, "!ping")
+		repo.MockOutgoingWebhookRepository.EXPECT().GetOutgoingWebhooksByChannelID(channelID).Return([]*model.OutgoingWebhook{newHook(ts.URL)}, nil)
+		repo.MockUserRepository.EXPECT().GetUser(u.ID, false).Return(u, nil)
+
+		require.NoError(t, s.processMessage(time.Now(), m, message.Parse(m.Text)))
+		assert.True(t, called)
+	})
+
+	t.Run("not matched", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		s, repo, _, _ := setup(t, ctrl)
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t.Error("unexpected request")
+		}))
+		defer ts.Close()
+
+		m := newMessage(u.ID, "ping!")
+		repo.MockOutgoingWebhookRepository.EXPECT().GetOutgoingWebhooksByChannelID(channelID).Return([]*model.OutgoingWebhook{newHook(ts.URL)}, nil)
+		repo.MockUserRepository.EXPECT().GetUser(u.ID, false).Return(u, nil)
+
+		require.NoError(t, s.processMessage(time.Now(), m, message.Parse(m.Text)))
+	})
+
+	t.Run("invalid regex", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		s, repo, _, _ := setup(t, ctrl)
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t.Error("unexpected request")
+		}))
+		defer ts.Close()
+
+		hook := newHook(ts.URL)
+		hook.Trigger = `(`
+		m := newMessage(u.ID, "(")
+		repo.MockOutgoingWebhookRepository.EXPECT().GetOutgoingWebhooksByChannelID(channelID).Return([]*model.OutgoingWebhook{hook}, nil)
+		repo.MockUserRepository.EXPECT().GetUser(u.ID, false).Return(u, nil)
+
+		require.NoError(t, s.processMessage(time.Now(), m, message.Parse(m.Text)))
+	})
+
+	t.Run("bot message", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		s, repo, _, _ := setup(t, ctrl)
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t.Error("unexpected request")
+		}))
+		defer ts.Close()
+
+		m := newMessage(bu.ID, "!ping")
+		repo.MockOutgoingWebhookRepository.EXPECT().GetOutgoingWebhooksByChannelID(channelID).Return([]*model.OutgoingWebhook{newHook(ts.URL)}, nil)
+		repo.MockUserRepository.EXPECT().GetUser(bu.ID, false).Return(bu, nil)
+
+		require.NoError(t, s.processMessage(time.Now(), m, message.Parse(m.Text)))
+	})
+
+	t.Run("no hooks", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		s, repo, _, _ := setup(t, ctrl)
+
+		m := newMessage(u.ID, "!ping")
+		repo.MockOutgoingWebhookRepository.EXPECT().GetOutgoingWebhooksByChannelID(channelID).Return([]*model.OutgoingWebhook{}, nil)
+
+		require.NoError(t, s.processMessage(time.Now(), m, message.Parse(m.Text)))
+	})
+}
+
+func TestServiceImpl_send(t *testing.T) {
+	t.Parallel()
+
+	wb := &model.WebhookBot{
+		ID:        uuid.NewV3(uuid.Nil, "w"),
+		BotUserID: uuid.NewV3(uuid.Nil, "wu"),
+	}
+	u := &model.User{ID: uuid.NewV3(uuid.Nil, "u"), Name: "testman"}
+	channelID := uuid.NewV3(uuid.Nil, "c")
+	m := &model.Message{
+		ID:        uuid.NewV3(uuid.Nil, "m"),
+		UserID:    u.ID,
+		ChannelID: channelID,
+		Text:      "!ping",
+	}
+
+	newServer := func(text string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/plain")
+			_, _ = w.Write([]byte(text))
+		}))
+	}
+	newHook := func(url string) *model.OutgoingWebhook {
+		return &model.OutgoingWebhook{
+			ID:          uuid.Must(uuid.NewV4()),
+			WebhookID:   wb.ID,
+			ChannelID:   channelID,
+			TriggerType: model.OutgoingWebhookTriggerKeyword,
+			Trigger:     "!ping",
+			URL:         url,
+			Secret:      "secret",
+		}
+	}
+
+	t.Run("too long reply", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		s, _, _, _ := setup(t, ctrl)
+		ts := newServer(strings.Repeat("あ", maxReplyLength+1))
+		defer ts.Close()
+
+		assert.Error(t, s.send(time.Now(), newHook(ts.URL), "!ping", m, u, message.Parse(m.Text)))
+	})
+
+	t.Run("outgoing webhook deleted", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		s, repo, _, _ := setup(t, ctrl)
+		ts := newServer("pong")
+		defer ts.Close()
+
+		hook := newHook(ts.URL)
+		repo.MockOutgoingWebhookRepository.EXPECT().GetOutgoingWebhook(hook.ID).Return(nil, repository.ErrNotFound)
+
+		assert.Equal(t, repository.ErrNotFound, s.send(time.Now(), hook, "!ping", m, u, message.Parse(m.Text)))
+	})
+
+	t.Run("archived channel", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		s, repo, tree, _ := setup(t, ctrl)
+		ts := newServer("pong")
+		defer ts.Close()
+
+		hook := newHook(ts.URL)
+		repo.MockOutgoingWebhookRepository.EXPECT().GetOutgoingWebhook(hook.ID).Return(hook, nil)
+		repo.MockWebhookRepository.EXPECT().GetWebhook(wb.ID).Return(wb, nil)
+		tree.EXPECT().IsArchivedChannel(channelID).Return(true)
+
+		assert.Error(t, s.send(time.Now(), hook, "!ping", m, u, message.Parse(m.Text)))
+	})
+
+	t.Run("rate limited", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		s, repo, tree, limiter := setup(t, ctrl)
+		ts := newServer("pong")
+		defer ts.Close()
+
+		hook := newHook(ts.URL)
+		repo.MockOutgoingWebhookRepository.EXPECT().GetOutgoingWebhook(hook.ID).Return(hook, nil)
+		repo.MockWebhookRepository.EXPECT().GetWebhook(wb.ID).Return(wb, nil)
+		tree.EXPECT().IsArchivedChannel(channelID).Return(false)
+		limiter.EXPECT().Allow(ratelimit.Webhook, wb.BotUserID, ratelimit.PostMessage).Return(false, time.Second)
+
+		assert.Equal(t, errRateLimited, s.send(time.Now(), hook, "!ping", m, u, message.Parse(m.Text)))
+	})
+}
+
+func TestServiceImpl_triggerRegexp(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	s, _, _, _ := setup(t, ctrl)
+
+	hook := &model.OutgoingWebhook{ID: uuid.NewV3(uuid.Nil, "o"), TriggerType: model.OutgoingWebhookTriggerRegex, Trigger: `^!ping`}
+	re := s.triggerRegexp(hook)
+	if assert.NotNil(t, re) {
+		assert.Same(t, re, s.triggerRegexp(hook))
+	}
+
+	// トリガーが変更された場合は再コンパイルされる
+	hook.Trigger = `^!pong`
+	if re2 := s.triggerRegexp(hook); assert.NotNil(t, re2) {
+		assert.Equal(t, `^!pong`, re2.String())
+	}
+
+	hook.Trigger = `(`
+	assert.Nil(t, s.triggerRegexp(hook))
+}
+
+func TestReadResponseText(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name        string
+		code        int
+		contentType string
+		body        string
+		text        string
+	}{
+		{"json", http.StatusOK, "application/json; charset=utf-8", `{"text":" hello "}`, "hello"},
+		{"plain", http.StatusOK, "text/plain", "hello\n", "hello"},
+		{"no content", http.StatusNoContent, "", "", ""},
+		{"other", http.StatusOK, "text/html", "<p>hello</p>", ""},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			rec := httptest.NewRecorder()
+			if len(c.contentType) > 0 {
+				rec.Header().Set("Content-Type", c.contentType)
+			}
+			rec.WriteHeader(c.code)
+			_, _ = rec.WriteString(c.body)
+
+			text, err := readResponseText(rec.Result())
+			if assert.NoError(t, err) {
+				assert.Equal(t, c.text, text)
+			}
+		})
+	}
+}
@@ -1,15 +1,19 @@
 package webrtcv3
 
 import (
+	"encoding/json"
 	"errors"
 	"github.com/gofrs/uuid"
 	"github.com/leandro-lugaresi/hub"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/traPtitech/traQ/event"
+	"github.com/traPtitech/traQ/service/cluster"
 	"sync"
 )
 
+const clusterTopic = "webrtcv3.state"
+
 var (
 	ErrOccupied             = errors.New("connection has already existed")
 	webrtcUsingUsersCounter = promauto.NewGauge(prometheus.GaugeOpts{
@@ -23,28 +27,72 @@ var (
 )
 
 // Manager WebRTCマネージャー
+//
+// 他ノードで接続しているユーザーの状態はノード間イベントバスを通じて受信し、自ノードの状態とマージして扱います。
 type Manager struct {
 	eventbus      *hub.Hub
+	cluster       *cluster.Bus
 	userStates    map[uuid.UUID]*userState
 	channelStates map[uuid.UUID]*channelState
+	remoteStates  map[string]map[uuid.UUID]*userState // nodeID -> userID -> state
 	statesLock    sync.RWMutex
 }
 
+// clusterMessage ノード間で送受信するユーザー状態
+type clusterMessage struct {
+	UserID    uuid.UUID         `json:"userId"`
+	ChannelID uuid.UUID         `json:"channelId"`
+	Sessions  map[string]string `json:"sessions"`
+}
+
 // NewManager WebRTCマネージャーを生成します
-func NewManager(eventbus *hub.Hub) *Manager {
+func NewManager(eventbus *hub.Hub, bus *cluster.Bus) *Manager {
 	manager := &Manager{
 		eventbus:      eventbus,
+		cluster:       bus,
 		userStates:    map[uuid.UUID]*userState{},
 		channelStates: map[uuid.UUID]*channelState{},
+		remoteStates:  map[string]map[uuid.UUID]*userState{},
 	}
+	bus.Subscribe(clusterTopic, manager.handleRemote)
+	bus.OnNodeJoined(manager.sendSnapshot)
+	bus.OnNodeLeft(manager.removeNode)
 	return manager
 }
 
-// IterateStates 全状態をイテレートします
+// IterateStates 全ノードの全状態をイテレートします
 func (m *Manager) IterateStates(f func(state ChannelState)) {
 	m.statesLock.RLock()
 	defer m.statesLock.RUnlock()
-	for _, state := range m.channelStates {
+	if len(m.remoteStates) == 0 {
+		for _, state := range m.channelStates {
+			f(state)
+		}
+		return
+	}
+
+	merged := make(map[uuid.UUID]*channelState, len(m.channelStates))
+	for id, cs := range m.channelStates {
+		users := make(map[uuid.UUID]*userState, len(cs.users))
+		for userID, us := range cs.users {
+			users[userID] = us
+		}
+		merged[id] = &channelState{channelID: id, users: users}
+	}
+	for _, states := range m.remoteStates {
+		for userID, us := range states {
+			if _, ok := m.userStates[userID]; ok {
+				continue
+			}
+			cs, ok := merged[us.channelID]
+			if !ok {
+				cs = &channelState{channelID: us.channelID, users: map[uuid.UUID]*userState{}}
+				merged[us.channelID] = cs
+			}
+			cs.setUser(us)
+		}
+	}
+	for _, state := range merged {
 		f(state)
 	}
 }
@@ -58,6 +106,11 @@ func (m *Manager) SetState(connKey string, user, channel uuid.UUID, sessions map
 	m.statesLock.Lock()
 	defer m.statesLock.Unlock()
 
+	if m.isRemoteUser(user) {
+		// 別のノードのコネクションでロック中
+		return ErrOccupied
+	}
+
 	us, ok := m.userStates[user]
 	if !ok {
 		us = &userState{
@@ -86,6 +139,11 @@ func (m *Manager) SetState(connKey string, user, channel uuid.UUID, sessions map
 	us.channelID = channel
 	cs.setUser(us)
 
+	m.cluster.Publish(clusterTopic, &clusterMessage{
+		UserID:    us.userID,
+		ChannelID: us.channelID,
+		Sessions:  us.sessions,
+	})
 	m.eventbus.Publish(hub.Message{
 		Name: event.UserWebRTCv3StateChanged,
 		Fields: hub.Fields{
@@ -120,6 +178,10 @@ func (m *Manager) ResetState(connKey string, user uuid.UUID) error {
 		webrtcUsingChannelsCounter.Dec()
 	}
 
+	m.cluster.Publish(clusterTopic, &clusterMessage{
+		UserID:    us.userID,
+		ChannelID: us.channelID,
+	})
 	m.eventbus.Publish(hub.Message{
 		Name: event.UserWebRTCv3StateChanged,
 		Fields: hub.Fields{
@@ -130,3 +192,81 @@ func (m *Manager) ResetState(connKey string, user uuid.UUID) error {
 	})
 	return nil
 }
+
+// isRemoteUser 指定したユーザーの状態が他ノードにあるかどうか。m.statesLockのロックが必要です
+func (m *Manager) isRemoteUser(user uuid.UUID) bool {
+	for _, states := range m.remoteStates {
+		if _, ok := states[user]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// handleRemote 他ノードのユーザー状態を反映します
+//
+// 状態変化のイベントは発生元のノードで発行されるため、ここでは発行しません。
+func (m *Manager) handleRemote(node string, body []byte) {
+	var msg clusterMessage
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return
+	}
+
+	m.statesLock.Lock()
+	defer m.statesLock.Unlock()
+	states, ok := m.remoteStates[node]
+	if !ok {
+		states = map[uuid.UUID]*userState{}
+		m.remoteStates[node] = states
+	}
+	us := &userState{
+		userID:    msg.UserID,
+		channelID: msg.ChannelID,
+		sessions:  msg.Sessions,
+	}
+	if us.valid() {
+		states[us.userID] = us
+	} else {
+		delete(states, us.userID)
+	}
+}
+
+// sendSnapshot 自ノードの全ユーザー状態を送信します
+func (m *Manager) sendSnapshot(string) {
+	m.statesLock.RLock()
+	defer m.statesLock.RUnlock()
+	for _, us := range m.userStates {
+		m.cluster.Publish(clusterTopic, &clusterMessage{
+			UserID:    us.userID,
+			ChannelID: us.channelID,
+			Sessions:  us.sessions,
+		})
+	}
+}
+
+// removeNode 離脱したノードのユーザー状態を破棄します
+//
+// 離脱により状態が無くなったユーザーのイベントはリーダーノードが発行します。
+func (m *Manager) removeNode(node string) {
+	m.statesLock.Lock()
+	defer m.statesLock.Unlock()
+	states := m.remoteStates[node]
+	delete(m.remoteStates, node)
+
+	if !m.cluster.IsLeader() {
+		return
+	}
+	for _, us := range states {
+		if _, ok := m.userStates[us.userID]; ok || m.isRemoteUser(us.userID) {
+			continue
+		}
+		m.eventbus.Publish(hub.Message{
+			Name: event.UserWebRTCv3StateChanged,
+			Fields: hub.Fields{
+				"user_id":    us.userID,
+				"channel_id": us.channelID,
+				"sessions":   map[string]string{},
+			},
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package ws
+
+import (
+	"errors"
+	"github.com/gofrs/uuid"
+	"github.com/traPtitech/traQ/service/viewer"
+	"github.com/traPtitech/traQ/service/webrtcv3"
+	"go.uber.org/zap"
+)
+
+var (
+	// errChannelNotAccessible チャンネルにアクセスする権限がありません
+	errChannelNotAccessible = errors.New("channel is not accessible")
+	// errRTCStateLocked WebRTCの状態が別のコネクションでロックされています
+	errRTCStateLocked = errors.New("your webrtc state is locked by another ws connection")
+	// errTooManySubscriptions 購読できるチャンネル数の上限に達しています
+	errTooManySubscriptions = errors.New("too many subscriptions")
+	// errInternal サーバー内部エラー
+	errInternal = errors.New("internal error")
+)
+
+// checkChannelAccess セッションのユーザーが指定したチャンネルにアクセスできるかを確認します
+func (s *session) checkChannelAccess(channelID uuid.UUID) error {
+	ok, err := s.streamer.cm.IsChannelAccessibleToUser(s.userID, channelID)
+	if err != nil {
+		s.streamer.logger.Error("failed to IsChannelAccessibleToUser", zap.Error(err), zap.Stringer("channelId", channelID))
+		return errInternal
+	}
+	if !ok {
+		return errChannelNotAccessible
+	}
+	return nil
+}
+
+// updateViewState チャンネル閲覧状態を更新します
+func (s *session) updateViewState(channelID uuid.UUID, state viewer.State) error {
+	if err := s.checkChannelAccess(channelID); err != nil {
+		return err
+	}
+	s.setViewState(channelID, state)
+	s.streamer.vm.SetViewer(s, s.userID, channelID, state)
+	return nil
+}
+
+// resetViewState チャンネル閲覧状態をリセットします
+func (s *session) resetViewState() {
+	s.setViewState(uuid.Nil, 0)
+	s.streamer.vm.RemoveViewer(s)
+}
+
+// updateRTCState WebRTCの状態を更新します
+func (s *session) updateRTCState(channelID uuid.UUID, sessions map[string]string) error {
+	if err := s.checkChannelAccess(channelID); err != nil {
+		return err
+	}
+	if err := s.streamer.webrtc.SetState(s.key, s.userID, channelID, sessions); err != nil {
+		if err == webrtcv3.ErrOccupied {
+			return errRTCStateLocked
+		}
+		return err
+	}
+	return nil
+}
+
+// resetRTCState WebRTCの状態をリセットします
+func (s *session) resetRTCState() error {
+	if err := s.streamer.webrtc.ResetState(s.key, s.userID); err != nil {
+		if err == webrtcv3.ErrOccupied {
+			return errRTCStateLocked
+		}
+		return err
+	}
+	return nil
+}
+
+// subscribeChannel チャンネルのメッセージストリームを購読します
+func (s *session) subscribeChannel(channelID uuid.UUID) error {
+	if err := s.checkChannelAccess(channelID); err != nil {
+		return err
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	if !s.subscriptions.Contains(channelID) && len(s.subscriptions) >= maxSubscriptions {
+		return errTooManySubscriptions
+	}
+	s.subscriptions.Add(channelID)
+	return nil
+}
+
+// unsubscribeChannel チャンネルのメッセージストリームの購読を解除します
+func (s *session) unsubscribeChannel(channelID uuid.UUID) {
+	s.Lock()
+	defer s.Unlock()
+	s.subscriptions.Remove(channelID)
+}
+
+// revokeChannelAccess チャンネルへのアクセス権を失ったセッションの購読と閲覧状態を解除します
+func (s *session) revokeChannelAccess(channelID uuid.UUID) {
+	s.unsubscribeChannel(channelID)
+	if cid, _ := s.ViewState(); cid == channelID {
+		s.resetViewState()
+	}
+}
@@ -8,11 +8,13 @@ import (
 )
 
 const (
-	writeWait          = 10 * time.Second
-	pongWait           = 60 * time.Second
-	pingPeriod         = (pongWait * 9) / 10
-	maxReadMessageSize = 1 << 9 // 512B
-	messageBufferSize  = 256
+	writeWait            = 10 * time.Second
+	pongWait             = 60 * time.Second
+	pingPeriod           = (pongWait * 9) / 10
+	maxReadMessageSize   = 1 << 9  // 512B
+	maxReadMessageSizeV2 = 1 << 12 // 4KB
+	messageBufferSize    = 256
+	maxSubscriptions     = 100
 )
 
 var (
@@ -21,5 +23,6 @@ var (
 		ReadBufferSize:  1024,
 		WriteBufferSize: 1024,
 		CheckOrigin:     func(r *http.Request) bool { return true },
+		Subprotocols:    []string{ProtocolV2},
 	}
 )
@@ -23,8 +23,7 @@ Command:
 
 		if str := strings.ToLower(args[1]); str == "null" || str == "" {
 			// viewstate:null
-			s.setViewState(uuid.Nil, 0)
-			s.streamer.vm.RemoveViewer(s)
+			s.resetViewState()
 			break
 		}
 
@@ -41,10 +40,9 @@ Command:
 			break
 		}
 
-		// TODO channelのアクセスチェック
-
-		s.setViewState(cid, viewer.StateFromString(args[2]))
-		s.streamer.vm.SetViewer(s, s.userID, s.viewState.channelID, s.viewState.state)
+		if err := s.updateViewState(cid, viewer.StateFromString(args[2])); err != nil {
+			s.sendErrorMessage(fmt.Sprintf("%s: %s", err, args[1]))
+		}
 
 	case "rtcstate":
 		// rtcstate:{チャンネルID}:({状態}:{セッションID})*
@@ -57,9 +55,9 @@ Command:
 		// {チャンネルID} or null
 		if str := strings.ToLower(args[1]); str == "null" || str == "" {
 			// リセット
-			if s.streamer.webrtc.ResetState(s.Key(), s.UserID()) != nil {
+			if err := s.resetRTCState(); err != nil {
 				// 別のコネクションでロック中
-				s.sendErrorMessage("your webrtc state is locked by another ws connection")
+				s.sendErrorMessage(err.Error())
 			}
 			break
 		}
@@ -78,9 +76,9 @@ Command:
 		}
 		if str := strings.ToLower(args[2]); str == "null" || str == "" {
 			// リセット
-			if s.streamer.webrtc.ResetState(s.Key(), s.UserID()) != nil {
+			if err := s.resetRTCState(); err != nil {
 				// 別のコネクションでロック中
-				s.sendErrorMessage("your webrtc state is locked by another ws connection")
+				s.sendErrorMessage(err.Error())
 			}
 			break
 		}
@@ -102,7 +100,10 @@ Command:
 			sessions[session] = state
 		}
 
-		_ = s.streamer.webrtc.SetState(s.Key(), s.UserID(), cid, sessions)
+		if err := s.updateRTCState(cid, sessions); err != nil {
+			// アクセス権限が無い、或いは別のノードのコネクションでロック中
+			s.sendErrorMessage(err.Error())
+		}
 
 	case "timeline_streaming":
 		// timeline_streaming:(on|off|true|false)
@@ -0,0 +1,203 @@
+package ws
+
+import (
+	stdjson "encoding/json"
+	"github.com/gofrs/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/traPtitech/traQ/service/viewer"
+	"strings"
+)
+
+// ProtocolV2 JSONコマンドプロトコルのサブプロトコル名
+//
+// 接続時に Sec-WebSocket-Protocol でこのサブプロトコルを指定しなかった場合は、従来のテキストコマンドプロトコルになります。
+// サーバーから送信されるイベントメッセージの形式はどちらのプロトコルでも同じです。
+const ProtocolV2 = "traq.v2"
+
+const (
+	// replyTypeAck コマンド成功応答のメッセージタイプ
+	replyTypeAck = "ACK"
+	// replyTypeError コマンド失敗応答のメッセージタイプ
+	replyTypeError = "ERROR"
+)
+
+// v2プロトコルのエラーコード
+const (
+	errorCodeInvalidCommand       = "invalid_command"
+	errorCodeUnknownCommand       = "unknown_command"
+	errorCodeInvalidArgs          = "invalid_args"
+	errorCodeForbidden            = "forbidden"
+	errorCodeConflict             = "conflict"
+	errorCodeTooManySubscriptions = "too_many_subscriptions"
+	errorCodeInternalError        = "internal_error"
+)
+
+// commandV2 v2プロトコルのクライアントからのコマンド
+type commandV2 struct {
+	// ID リクエストID 応答メッセージに同じIDが付与されます
+	ID string `json:"id"`
+	// Type コマンド名
+	Type string `json:"type"`
+	// Body コマンド引数
+	Body stdjson.RawMessage `json:"body"`
+}
+
+// replyV2 v2プロトコルのコマンドに対する応答
+type replyV2 struct {
+	Type string       `json:"type"`
+	ID   string       `json:"id"`
+	Body *replyBodyV2 `json:"body,omitempty"`
+}
+
+// replyBodyV2 v2プロトコルのエラー応答の内容
+type replyBodyV2 struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// viewStateBodyV2 viewstateコマンドの引数
+type viewStateBodyV2 struct {
+	// ChannelID 閲覧チャンネルID nullの場合は閲覧状態をリセットします
+	ChannelID *uuid.UUID   `json:"channelId"`
+	State     viewer.State `json:"state"`
+}
+
+// rtcStateBodyV2 rtcstateコマンドの引数
+type rtcStateBodyV2 struct {
+	// ChannelID 接続チャンネルID nullの場合は状態をリセットします
+	ChannelID *uuid.UUID `json:"channelId"`
+	Sessions  []struct {
+		State     string `json:"state"`
+		SessionID string `json:"sessionId"`
+	} `json:"sessions"`
+}
+
+// timelineStreamingBodyV2 timeline_streamingコマンドの引数
+type timelineStreamingBodyV2 struct {
+	Enabled bool `json:"enabled"`
+}
+
+// channelBodyV2 subscribe, unsubscribeコマンドの引数
+type channelBodyV2 struct {
+	ChannelID uuid.UUID `json:"channelId"`
+}
+
+func (s *session) commandHandlerV2(data []byte) {
+	var cmd commandV2
+	if err := stdjson.Unmarshal(data, &cmd); err != nil {
+		s.sendErrorV2("", errorCodeInvalidCommand, "invalid command format")
+		return
+	}
+	if len(cmd.ID) == 0 {
+		s.sendErrorV2("", errorCodeInvalidCommand, "id is required")
+		return
+	}
+
+	switch strings.ToLower(cmd.Type) {
+	case "viewstate":
+		var body viewStateBodyV2
+		if !s.bindBodyV2(&cmd, &body) {
+			return
+		}
+		if body.ChannelID == nil || *body.ChannelID == uuid.Nil {
+			s.resetViewState()
+			s.sendAckV2(cmd.ID)
+			return
+		}
+		s.sendResultV2(cmd.ID, s.updateViewState(*body.ChannelID, body.State))
+
+	case "rtcstate":
+		var body rtcStateBodyV2
+		if !s.bindBodyV2(&cmd, &body) {
+			return
+		}
+		if body.ChannelID == nil || *body.ChannelID == uuid.Nil || len(body.Sessions) == 0 {
+			s.sendResultV2(cmd.ID, s.resetRTCState())
+			return
+		}
+		sessions := make(map[string]string, len(body.Sessions))
+		for _, v := range body.Sessions {
+			if len(v.State) == 0 || len(v.SessionID) == 0 {
+				s.sendErrorV2(cmd.ID, errorCodeInvalidArgs, "state and sessionId are required")
+				return
+			}
+			sessions[v.SessionID] = v.State
+		}
+		s.sendResultV2(cmd.ID, s.updateRTCState(*body.ChannelID, sessions))
+
+	case "timeline_streaming":
+		var body timelineStreamingBodyV2
+		if !s.bindBodyV2(&cmd, &body) {
+			return
+		}
+		s.setTimelineStreaming(body.Enabled)
+		s.sendAckV2(cmd.ID)
+
+	case "subscribe":
+		var body channelBodyV2
+		if !s.bindBodyV2(&cmd, &body) {
+			return
+		}
+		if body.ChannelID == uuid.Nil {
+			s.sendErrorV2(cmd.ID, errorCodeInvalidArgs, "channelId is required")
+			return
+		}
+		s.sendResultV2(cmd.ID, s.subscribeChannel(body.ChannelID))
+
+	case "unsubscribe":
+		var body channelBodyV2
+		if !s.bindBodyV2(&cmd, &body) {
+			return
+		}
+		s.unsubscribeChannel(body.ChannelID)
+		s.sendAckV2(cmd.ID)
+
+	default:
+		s.sendErrorV2(cmd.ID, errorCodeUnknownCommand, "unknown command: "+cmd.Type)
+	}
+}
+
+// bindBodyV2 コマンド引数をデコードします。失敗した場合はエラーを応答してfalseを返します
+func (s *session) bindBodyV2(cmd *commandV2, v interface{}) bool {
+	if len(cmd.Body) == 0 {
+		s.sendErrorV2(cmd.ID, errorCodeInvalidArgs, "body is required")
+		return false
+	}
+	if err := stdjson.Unmarshal(cmd.Body, v); err != nil {
+		s.sendErrorV2(cmd.ID, errorCodeInvalidArgs, "invalid body: "+err.Error())
+		return false
+	}
+	return true
+}
+
+// sendResultV2 コマンドの実行結果を応答します
+func (s *session) sendResultV2(id string, err error) {
+	switch err {
+	case nil:
+		s.sendAckV2(id)
+	case errChannelNotAccessible:
+		s.sendErrorV2(id, errorCodeForbidden, err.Error())
+	case errRTCStateLocked:
+		s.sendErrorV2(id, errorCodeConflict, err.Error())
+	case errTooManySubscriptions:
+		s.sendErrorV2(id, errorCodeTooManySubscriptions, err.Error())
+	default:
+		s.sendErrorV2(id, errorCodeInternalError, errInternal.Error())
+	}
+}
+
+func (s *session) sendAckV2(id string) {
+	s.writeReplyV2(&replyV2{Type: replyTypeAck, ID: id})
+}
+
+func (s *session) sendErrorV2(id, code, message string) {
+	s.writeReplyV2(&replyV2{Type: replyTypeError, ID: id, Body: &replyBodyV2{Code: code, Message: message}})
+}
+
+func (s *session) writeReplyV2(r *replyV2) {
+	b, _ := json.Marshal(r)
+	_ = s.writeMessage(&rawMessage{
+		t:    websocket.TextMessage,
+		data: b,
+	})
+}
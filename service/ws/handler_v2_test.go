@@ -0,0 +1,201 @@
+package ws
+
+import (
+	"context"
+	stdjson "encoding/json"
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/websocket"
+	"github.com/leandro-lugaresi/hub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traPtitech/traQ/event"
+	"github.com/traPtitech/traQ/router/extension"
+	"github.com/traPtitech/traQ/service/channel/mock_channel"
+	"github.com/traPtitech/traQ/service/cluster"
+	"github.com/traPtitech/traQ/service/viewer"
+	"github.com/traPtitech/traQ/service/webrtcv3"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func setupStreamer(t *testing.T, userID uuid.UUID, accessible, forbidden uuid.UUID) (*Streamer, string) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	cm := mock_channel.NewMockManager(ctrl)
+	cm.EXPECT().IsChannelAccessibleToUser(userID, accessible).Return(true, nil).AnyTimes()
+	cm.EXPECT().IsChannelAccessibleToUser(userID, forbidden).Return(false, nil).AnyTimes()
+
+	h := hub.New()
+	bus := cluster.NewStandaloneBus()
+	s := NewStreamer(h, viewer.NewManager(h, bus), webrtcv3.NewManager(h, bus), cm, bus, zap.NewNop())
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		s.ServeHTTP(rw, r.WithContext(context.WithValue(r.Context(), extension.CtxUserIDKey, userID)))
+	}))
+	t.Cleanup(func() {
+		_ = s.Close()
+		server.Close()
+	})
+	return s, "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+func dial(t *testing.T, url string, subprotocols ...string) *websocket.Conn {
+	t.Helper()
+	d := websocket.Dialer{Subprotocols: subprotocols}
+	conn, _, err := d.Dial(url, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func readJSON(t *testing.T, conn *websocket.Conn) map[string]interface{} {
+	t.Helper()
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	_, b, err := conn.ReadMessage()
+	require.NoError(t, err)
+	var v map[string]interface{}
+	require.NoError(t, stdjson.Unmarshal(b, &v))
+	return v
+}
+
+func TestSession_commandHandlerV2(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.Must(uuid.NewV4())
+	accessible := uuid.Must(uuid.NewV4())
+	forbidden := uuid.Must(uuid.NewV4())
+	s, url := setupStreamer(t, userID, accessible, forbidden)
+
+	conn := dial(t, url, ProtocolV2)
+	require.Equal(t, ProtocolV2, conn.Subprotocol())
+
+	send := func(v string) map[string]interface{} {
+		t.Helper()
+		require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(v)))
+		return readJSON(t, conn)
+	}
+	assertError := func(res map[string]interface{}, id, code string) {
+		t.Helper()
+		assert.Equal(t, "ERROR", res["type"])
+		assert.Equal(t, id, res["id"])
+		if body, ok := res["body"].(map[string]interface{}); assert.True(t, ok) {
+			assert.Equal(t, code, body["code"])
+		}
+	}
+
+	assertError(send(`viewstate`), "", "invalid_command")
+	assertError(send(`{"type":"viewstate","body":{}}`), "", "invalid_command")
+	assertError(send(`{"id":"1","type":"unknown"}`), "1", "unknown_command")
+	assertError(send(`{"id":"2","type":"viewstate"}`), "2", "invalid_args")
+	assertError(send(`{"id":"3","type":"viewstate","body":{"channelId":"invalid"}}`), "3", "invalid_args")
+	assertError(send(`{"id":"4","type":"viewstate","body":{"channelId":"`+forbidden.String()+`","state":"monitoring"}}`), "4", "forbidden")
+	assertError(send(`{"id":"5","type":"subscribe","body":{"channelId":"`+forbidden.String()+`"}}`), "5", "forbidden")
+
+	assert.Equal(t, map[string]interface{}{"type": "ACK", "id": "6"}, send(`{"id":"6","type":"viewstate","body":{"channelId":"`+accessible.String()+`","state":"monitoring"}}`))
+	assert.Equal(t, viewer.StateMonitoring, s.vm.GetChannelViewers(accessible)[userID].State)
+	assert.Equal(t, map[string]interface{}{"type": "ACK", "id": "7"}, send(`{"id":"7","type":"viewstate","body":{"channelId":null}}`))
+	assert.Empty(t, s.vm.GetChannelViewers(accessible))
+	assert.Equal(t, map[string]interface{}{"type": "ACK", "id": "8"}, send(`{"id":"8","type":"timeline_streaming","body":{"enabled":true}}`))
+
+	// 購読したチャンネルのメッセージを受信できる
+	assert.Equal(t, map[string]interface{}{"type": "ACK", "id": "9"}, send(`{"id":"9","type":"subscribe","body":{"channelId":"`+accessible.String()+`"}}`))
+	s.WriteMessage("MESSAGE_CREATED", struct {
+		ID string `json:"id"`
+	}{ID: "message"}, TargetChannelViewers(accessible))
+	assert.Equal(t, map[string]interface{}{"type": "MESSAGE_CREATED", "body": map[string]interface{}{"id": "message"}}, readJSON(t, conn))
+
+	assert.Equal(t, map[string]interface{}{"type": "ACK", "id": "10"}, send(`{"id":"10","type":"unsubscribe","body":{"channelId":"`+accessible.String()+`"}}`))
+	s.mu.RLock()
+	for session := range s.sessions {
+		assert.False(t, session.IsSubscribing(accessible))
+	}
+	s.mu.RUnlock()
+}
+
+func TestStreamer_revokeChannelAccess(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.Must(uuid.NewV4())
+	accessible := uuid.Must(uuid.NewV4())
+	forbidden := uuid.Must(uuid.NewV4())
+	s, url := setupStreamer(t, userID, accessible, forbidden)
+
+	conn := dial(t, url, ProtocolV2)
+	send := func(v string) map[string]interface{} {
+		t.Helper()
+		require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(v)))
+		return readJSON(t, conn)
+	}
+	assert.Equal(t, map[string]interface{}{"type": "ACK", "id": "1"}, send(`{"id":"1","type":"viewstate","body":{"channelId":"`+accessible.String()+`","state":"monitoring"}}`))
+	assert.Equal(t, map[string]interface{}{"type": "ACK", "id": "2"}, send(`{"id":"2","type":"subscribe","body":{"channelId":"`+accessible.String()+`"}}`))
+
+	// 他のユーザーの削除は影響しない
+	s.hub.Publish(hub.Message{
+		Name: event.ChannelMemberRemoved,
+		Fields: hub.Fields{
+			"channel_id": accessible,
+			"user_id":    uuid.Must(uuid.NewV4()),
+			"private":    true,
+		},
+	})
+	s.hub.Publish(hub.Message{
+		Name: event.ChannelMemberRemoved,
+		Fields: hub.Fields{
+			"channel_id": accessible,
+			"user_id":    userID,
+			"private":    true,
+		},
+	})
+
+	assert.Eventually(t, func() bool {
+		return len(s.vm.GetChannelViewers(accessible)) == 0
+	}, time.Second, 10*time.Millisecond)
+	s.mu.RLock()
+	for session := range s.sessions {
+		assert.False(t, session.IsSubscribing(accessible))
+		cid, _ := session.ViewState()
+		assert.Equal(t, uuid.Nil, cid)
+	}
+	s.mu.RUnlock()
+
+	// 削除後はメッセージを受信しない
+	s.WriteMessage("MESSAGE_CREATED", struct {
+		ID string `json:"id"`
+	}{ID: "message"}, TargetChannelViewers(accessible))
+	_ = conn.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+	_, _, err := conn.ReadMessage()
+	assert.Error(t, err)
+}
+
+func TestSession_commandHandler(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.Must(uuid.NewV4())
+	accessible := uuid.Must(uuid.NewV4())
+	forbidden := uuid.Must(uuid.NewV4())
+	s, url := setupStreamer(t, userID, accessible, forbidden)
+
+	// サブプロトコルを指定しない場合は従来のテキストプロトコル
+	conn := dial(t, url)
+	require.Empty(t, conn.Subprotocol())
+
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte("viewstate:"+forbidden.String()+":monitoring")))
+	res := readJSON(t, conn)
+	assert.Equal(t, "ERROR", res["type"])
+	assert.Contains(t, res["body"], "channel is not accessible")
+	assert.Empty(t, s.vm.GetChannelViewers(forbidden))
+
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte("unknown")))
+	assert.Equal(t, map[string]interface{}{"type": "ERROR", "body": "unknown command: unknown"}, readJSON(t, conn))
+
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte("viewstate:"+accessible.String()+":monitoring")))
+	assert.Eventually(t, func() bool {
+		return s.vm.GetChannelViewers(accessible)[userID].State == viewer.StateMonitoring
+	}, time.Second, 10*time.Millisecond)
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/gofrs/uuid"
 	"github.com/gorilla/websocket"
 	"github.com/traPtitech/traQ/service/viewer"
+	"github.com/traPtitech/traQ/utils/set"
 	"net/http"
 	"sync"
 	"time"
@@ -18,6 +19,8 @@ type Session interface {
 	ViewState() (channelID uuid.UUID, state viewer.State)
 	// TimelineStreaming このセッションのタイムラインストリーミングが有効かどうか
 	TimelineStreaming() bool
+	// IsSubscribing このセッションが指定したチャンネルのメッセージストリームを購読しているかどうか
+	IsSubscribing(channelID uuid.UUID) bool
 }
 
 type session struct {
@@ -29,17 +32,23 @@ type session struct {
 		state     viewer.State
 	}
 	enabledTimelineStreaming bool
+	subscriptions            set.UUID
 	sync.RWMutex
 
 	req      *http.Request
 	conn     *websocket.Conn
+	protocol string
 	open     bool
 	streamer *Streamer
 	send     chan *rawMessage
 }
 
 func (s *session) readLoop() {
-	s.conn.SetReadLimit(maxReadMessageSize)
+	if s.protocol == ProtocolV2 {
+		s.conn.SetReadLimit(maxReadMessageSizeV2)
+	} else {
+		s.conn.SetReadLimit(maxReadMessageSize)
+	}
 	_ = s.conn.SetReadDeadline(time.Now().Add(pongWait))
 	s.conn.SetPongHandler(func(string) error {
 		_ = s.conn.SetReadDeadline(time.Now().Add(pongWait))
@@ -53,7 +62,11 @@ func (s *session) readLoop() {
 		}
 
 		if t == websocket.TextMessage {
-			s.commandHandler(string(m))
+			if s.protocol == ProtocolV2 {
+				s.commandHandlerV2(m)
+			} else {
+				s.commandHandler(string(m))
+			}
 		}
 
 		if t == websocket.BinaryMessage {
@@ -148,6 +161,13 @@ func (s *session) TimelineStreaming() bool {
 	return s.enabledTimelineStreaming
 }
 
+// IsSubscribing implements Session interface.
+func (s *session) IsSubscribing(channelID uuid.UUID) bool {
+	s.RLock()
+	defer s.RUnlock()
+	return s.subscriptions.Contains(channelID)
+}
+
 func (s *session) setViewState(cid uuid.UUID, state viewer.State) {
 	s.Lock()
 	defer s.Unlock()
@@ -1,6 +1,7 @@
 package ws
 
 import (
+	stdjson "encoding/json"
 	"errors"
 	"github.com/gofrs/uuid"
 	"github.com/gorilla/websocket"
@@ -9,14 +10,23 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/traPtitech/traQ/event"
 	"github.com/traPtitech/traQ/router/extension"
+	"github.com/traPtitech/traQ/service/channel"
+	"github.com/traPtitech/traQ/service/cluster"
 	"github.com/traPtitech/traQ/service/viewer"
 	"github.com/traPtitech/traQ/service/webrtcv3"
 	"github.com/traPtitech/traQ/utils/random"
+	"github.com/traPtitech/traQ/utils/set"
 	"go.uber.org/zap"
 	"net/http"
 	"sync"
 )
 
+const (
+	clusterTopic = "ws.message"
+	// revokeClusterTopic チャンネルへのアクセス権の喪失を他ノードに通知するトピック
+	revokeClusterTopic = "ws.revoke"
+)
+
 var (
 	// ErrAlreadyClosed 既に閉じられています
 	ErrAlreadyClosed = errors.New("already closed")
@@ -30,10 +40,14 @@ var (
 )
 
 // Streamer WebSocketストリーマー
+//
+// 書き込まれたメッセージはノード間イベントバスを通じて他ノードのセッションにも送信されます。
 type Streamer struct {
 	hub        *hub.Hub
 	vm         *viewer.Manager
 	webrtc     *webrtcv3.Manager
+	cm         channel.Manager
+	cluster    *cluster.Bus
 	logger     *zap.Logger
 	sessions   map[*session]struct{}
 	register   chan *session
@@ -43,12 +57,28 @@ type Streamer struct {
 	mu         sync.RWMutex
 }
 
+// clusterMessage ノード間で送受信するメッセージ
+type clusterMessage struct {
+	Data   stdjson.RawMessage `json:"data"`
+	Target Target             `json:"target"`
+}
+
+// revokeMessage ノード間で送受信するアクセス権の喪失
+type revokeMessage struct {
+	ChannelID uuid.UUID `json:"channelId"`
+	UserID    uuid.UUID `json:"userId"`
+}
+
 // NewStreamer WebSocketストリーマーを生成し起動します
-func NewStreamer(hub *hub.Hub, vm *viewer.Manager, webrtc *webrtcv3.Manager, logger *zap.Logger) *Streamer {
+//
+// プライベートチャンネルからメンバーが削除された場合、全ノードのそのユーザーのセッションの購読と閲覧状態を解除します。
+func NewStreamer(hub *hub.Hub, vm *viewer.Manager, webrtc *webrtcv3.Manager, cm channel.Manager, bus *cluster.Bus, logger *zap.Logger) *Streamer {
 	h := &Streamer{
 		hub:        hub,
 		vm:         vm,
 		webrtc:     webrtc,
+		cm:         cm,
+		cluster:    bus,
 		logger:     logger.Named("ws"),
 		sessions:   make(map[*session]struct{}),
 		register:   make(chan *session),
@@ -57,6 +87,19 @@ func NewStreamer(hub *hub.Hub, vm *viewer.Manager, webrtc *webrtcv3.Manager, log
 		open:       true,
 	}
 
+	bus.Subscribe(clusterTopic, h.handleRemote)
+	bus.Subscribe(revokeClusterTopic, h.handleRemoteRevoke)
+	sub := hub.Subscribe(10, event.ChannelMemberRemoved)
+	go func() {
+		for ev := range sub.Receiver {
+			m := &revokeMessage{
+				ChannelID: ev.Fields["channel_id"].(uuid.UUID),
+				UserID:    ev.Fields["user_id"].(uuid.UUID),
+			}
+			bus.Publish(revokeClusterTopic, m)
+			h.revokeChannelAccess(m.ChannelID, m.UserID)
+		}
+	}()
 	go h.run()
 	return h
 }
@@ -94,19 +137,29 @@ func (s *Streamer) run() {
 	}
 }
 
-// WriteMessage 指定したセッションにメッセージを書き込みます
-func (s *Streamer) WriteMessage(t string, body interface{}, targetFunc TargetFunc) {
+// WriteMessage 全ノードの指定したセッションにメッセージを書き込みます
+func (s *Streamer) WriteMessage(t string, body interface{}, target Target) {
+	data := makeMessage(t, body).toJSON()
+	s.cluster.Publish(clusterTopic, &clusterMessage{
+		Data:   data,
+		Target: target,
+	})
+	s.writeMessage(data, target)
+}
+
+// writeMessage 自ノードの指定したセッションにメッセージを書き込みます
+func (s *Streamer) writeMessage(data []byte, target Target) {
 	m := &rawMessage{
 		t:    websocket.TextMessage,
-		data: makeMessage(t, body).toJSON(),
+		data: data,
 	}
 	s.mu.RLock()
 	for session := range s.sessions {
-		if targetFunc(session) {
+		if target.Match(session) {
 			if err := session.writeMessage(m); err != nil {
 				if err == ErrBufferIsFull {
 					s.logger.Warn("Discard a message because the session's buffer is full.",
-						zap.String("type", t), zap.Any("body", body),
+						zap.ByteString("message", data),
 						zap.Stringer("userID", session.userID))
 					continue
 				}
@@ -116,6 +169,35 @@ func (s *Streamer) WriteMessage(t string, body interface{}, targetFunc TargetFun
 	s.mu.RUnlock()
 }
 
+// handleRemote 他ノードで書き込まれたメッセージを自ノードのセッションに書き込みます
+func (s *Streamer) handleRemote(_ string, body []byte) {
+	var m clusterMessage
+	if err := stdjson.Unmarshal(body, &m); err != nil {
+		return
+	}
+	s.writeMessage(m.Data, m.Target)
+}
+
+// revokeChannelAccess 自ノードの指定したユーザーのセッションのチャンネルの購読と閲覧状態を解除します
+func (s *Streamer) revokeChannelAccess(channelID, userID uuid.UUID) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for session := range s.sessions {
+		if session.userID == userID {
+			session.revokeChannelAccess(channelID)
+		}
+	}
+}
+
+// handleRemoteRevoke 他ノードから通知されたアクセス権の喪失を自ノードのセッションに反映します
+func (s *Streamer) handleRemoteRevoke(_ string, body []byte) {
+	var m revokeMessage
+	if err := stdjson.Unmarshal(body, &m); err != nil {
+		return
+	}
+	s.revokeChannelAccess(m.ChannelID, m.UserID)
+}
+
 // ServeHTTP http.Handlerインターフェイスの実装
 func (s *Streamer) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
 	if s.IsClosed() {
@@ -129,13 +211,15 @@ func (s *Streamer) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	session := &session{
-		key:      random.AlphaNumeric(20),
-		req:      r,
-		conn:     conn,
-		open:     true,
-		streamer: s,
-		send:     make(chan *rawMessage, messageBufferSize),
-		userID:   r.Context().Value(extension.CtxUserIDKey).(uuid.UUID),
+		key:           random.AlphaNumeric(20),
+		req:           r,
+		conn:          conn,
+		protocol:      conn.Subprotocol(),
+		open:          true,
+		streamer:      s,
+		send:          make(chan *rawMessage, messageBufferSize),
+		userID:        r.Context().Value(extension.CtxUserIDKey).(uuid.UUID),
+		subscriptions: set.UUID{},
 	}
 
 	s.register <- session
@@ -0,0 +1,89 @@
+package ws
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/traPtitech/traQ/utils/set"
+)
+
+// Target メッセージ送信対象
+//
+// 他ノードのセッションにも送信できるように、JSONにシリアライズ可能な条件で表します。
+// いずれかの条件に該当するセッションが送信対象になります。
+type Target struct {
+	// All 全セッション
+	All bool `json:"all,omitempty"`
+	// Users 指定したユーザーのセッション
+	Users set.UUID `json:"users,omitempty"`
+	// ChannelViewers 指定したチャンネルを閲覧、或いはメッセージストリームを購読しているセッション
+	ChannelViewers set.UUID `json:"channelViewers,omitempty"`
+	// TimelineStreaming タイムラインストリーミングが有効なセッション
+	TimelineStreaming bool `json:"timelineStreaming,omitempty"`
+}
+
+// Match 指定したセッションが送信対象かどうか
+func (t Target) Match(s Session) bool {
+	if t.All {
+		return true
+	}
+	if t.Users.Contains(s.UserID()) {
+		return true
+	}
+	if len(t.ChannelViewers) > 0 {
+		if c, _ := s.ViewState(); t.ChannelViewers.Contains(c) {
+			return true
+		}
+		for c := range t.ChannelViewers {
+			if s.IsSubscribing(c) {
+				return true
+			}
+		}
+	}
+	return t.TimelineStreaming && s.TimelineStreaming()
+}
+
+// TargetAll 全セッションを対象に送信します
+func TargetAll() Target {
+	return Target{All: true}
+}
+
+// TargetUsers 指定したユーザーを対象に送信します
+func TargetUsers(userID ...uuid.UUID) Target {
+	return Target{Users: set.UUIDSetFromArray(userID)}
+}
+
+// TargetUserSets 指定したユーザーを対象に送信します
+func TargetUserSets(sets ...set.UUID) Target {
+	return Target{Users: set.UnionUUIDSets(sets...)}
+}
+
+// TargetChannelViewers 指定したチャンネルの閲覧者、及びメッセージストリームの購読者を対象に送信します
+func TargetChannelViewers(channelID uuid.UUID) Target {
+	return Target{ChannelViewers: set.UUIDSetFromArray([]uuid.UUID{channelID})}
+}
+
+// TargetTimelineStreamingEnabled タイムラインストリーミングが有効なコネクションを対象に送信します
+func TargetTimelineStreamingEnabled() Target {
+	return Target{TimelineStreaming: true}
+}
+
+// Or いずれかのTargetの条件に該当する対象に送信します
+func Or(targets ...Target) Target {
+	var result Target
+	for _, t := range targets {
+		result.All = result.All || t.All
+		result.TimelineStreaming = result.TimelineStreaming || t.TimelineStreaming
+		if len(t.Users) > 0 {
+			if result.Users == nil {
+				result.Users = set.UUID{}
+			}
+			result.Users.Plus(t.Users)
+		}
+		if len(t.ChannelViewers) > 0 {
+			if result.ChannelViewers == nil {
+				result.ChannelViewers = set.UUID{}
+			}
+			result.ChannelViewers.Plus(t.ChannelViewers)
+		}
+	}
+	return result
+}
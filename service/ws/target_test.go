@@ -0,0 +1,76 @@
+package ws
+
+import (
+	stdjson "encoding/json"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traPtitech/traQ/service/viewer"
+	"github.com/traPtitech/traQ/utils/set"
+	"testing"
+)
+
+type testSession struct {
+	userID            uuid.UUID
+	channelID         uuid.UUID
+	timelineStreaming bool
+	subscriptions     set.UUID
+}
+
+func (s *testSession) Key() string { return "" }
+
+func (s *testSession) UserID() uuid.UUID { return s.userID }
+
+func (s *testSession) ViewState() (uuid.UUID, viewer.State) {
+	return s.channelID, viewer.StateMonitoring
+}
+
+func (s *testSession) TimelineStreaming() bool { return s.timelineStreaming }
+
+func (s *testSession) IsSubscribing(channelID uuid.UUID) bool { return s.subscriptions.Contains(channelID) }
+
+func TestTarget_Match(t *testing.T) {
+	t.Parallel()
+
+	user := uuid.Must(uuid.NewV4())
+	channel := uuid.Must(uuid.NewV4())
+	other := uuid.Must(uuid.NewV4())
+
+	s := &testSession{userID: user, channelID: channel}
+	ts := &testSession{userID: other, timelineStreaming: true}
+	ss := &testSession{userID: other, subscriptions: set.UUIDSetFromArray([]uuid.UUID{channel})}
+
+	tests := []struct {
+		name   string
+		target Target
+		s      Session
+		want   bool
+	}{
+		{"all", TargetAll(), s, true},
+		{"users", TargetUsers(user), s, true},
+		{"other users", TargetUsers(other), s, false},
+		{"channel viewers", TargetChannelViewers(channel), s, true},
+		{"other channel viewers", TargetChannelViewers(other), s, false},
+		{"channel subscribers", TargetChannelViewers(channel), ss, true},
+		{"other channel subscribers", TargetChannelViewers(other), ss, false},
+		{"timeline streaming", TargetTimelineStreamingEnabled(), ts, true},
+		{"timeline streaming disabled", TargetTimelineStreamingEnabled(), s, false},
+		{"or", Or(TargetUsers(other), TargetTimelineStreamingEnabled()), ts, true},
+		{"or", Or(TargetUsers(other), TargetChannelViewers(other)), s, false},
+		{"empty", Target{}, s, false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, tt.target.Match(tt.s))
+
+			// ノード間で転送しても条件が変わらない
+			b, err := stdjson.Marshal(tt.target)
+			require.NoError(t, err)
+			var decoded Target
+			require.NoError(t, stdjson.Unmarshal(b, &decoded))
+			assert.Equal(t, tt.want, decoded.Match(tt.s))
+		})
+	}
+}
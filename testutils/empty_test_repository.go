@@ -21,6 +21,14 @@ type EmptyTestRepository struct {
 	repository.OAuth2Repository
 	repository.BotRepository
 	repository.ClipRepository
+	repository.ScheduledMessageRepository
+	repository.UserRoleRepository
+	repository.ChannelRoleRepository
+	repository.RateLimitRepository
+	repository.OutgoingWebhookRepository
+	repository.WebPushSubscriptionRepository
+	repository.UserDigestSettingRepository
+	repository.UserNotificationSettingRepository
 }
 
 func (*EmptyTestRepository) Sync() (init bool, err error) {
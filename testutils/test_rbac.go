@@ -1,6 +1,7 @@
 package testutils
 
 import (
+	"github.com/gofrs/uuid"
 	"github.com/traPtitech/traQ/service/rbac"
 	"github.com/traPtitech/traQ/service/rbac/permission"
 	"github.com/traPtitech/traQ/service/rbac/role"
@@ -49,3 +50,7 @@ func (rbac *rbacImpl) GetGrantedPermissions(roleName string) []permission.Permis
 	}
 	return nil
 }
+
+func (rbac *rbacImpl) IsChannelGranted(userID, channelID uuid.UUID, perm permission.Permission) bool {
+	return false
+}
@@ -51,6 +51,8 @@ type TestRepository struct {
 	FilesACLLock              sync.RWMutex
 	Webhooks                  map[uuid.UUID]model.WebhookBot
 	WebhooksLock              sync.RWMutex
+	RateLimitOverrides        map[uuid.UUID]map[string]model.RateLimitOverride
+	RateLimitOverridesLock    sync.RWMutex
 }
 
 func (repo *TestRepository) GetPublicChannels() ([]*model.Channel, error) {
@@ -106,6 +108,50 @@ func (repo *TestRepository) GetBotEventLogs(uuid.UUID, int, int) ([]*model.BotEv
 	panic("implement me")
 }
 
+func (repo *TestRepository) EnqueueBotEvents([]*model.BotEventQueueItem) error {
+	panic("implement me")
+}
+
+func (repo *TestRepository) GetBotIDsWithQueuedEvents() ([]uuid.UUID, error) {
+	panic("implement me")
+}
+
+func (repo *TestRepository) LeaseBotEventQueueHead(uuid.UUID, time.Time) (*model.BotEventQueueItem, error) {
+	panic("implement me")
+}
+
+func (repo *TestRepository) DeleteBotEventQueueItem(uuid.UUID) error {
+	panic("implement me")
+}
+
+func (repo *TestRepository) UpdateBotEventQueueItemAttempts(uuid.UUID, int, time.Time, bool) error {
+	panic("implement me")
+}
+
+func (repo *TestRepository) GetDeadBotEvents(uuid.UUID, int, int) ([]*model.BotEventQueueItem, error) {
+	panic("implement me")
+}
+
+func (repo *TestRepository) ReplayDeadBotEvents(uuid.UUID, []uuid.UUID) (int, error) {
+	panic("implement me")
+}
+
+func (repo *TestRepository) SetBotCommands(uuid.UUID, []*model.BotCommand) ([]*model.BotCommand, error) {
+	panic("implement me")
+}
+
+func (repo *TestRepository) GetBotCommands(uuid.UUID) ([]*model.BotCommand, error) {
+	panic("implement me")
+}
+
+func (repo *TestRepository) GetAllBotCommands() ([]*model.BotCommand, error) {
+	panic("implement me")
+}
+
+func (repo *TestRepository) GetBotCommandsByName(string) ([]*model.BotCommand, error) {
+	panic("implement me")
+}
+
 func (repo *TestRepository) WriteBotEventLog(*model.BotEventLog) error {
 	panic("implement me")
 }
@@ -114,6 +160,10 @@ func (repo *TestRepository) ReissueBotTokens(uuid.UUID) (*model.Bot, error) {
 	panic("implement me")
 }
 
+func (repo *TestRepository) RotateBotSigningSecret(uuid.UUID, time.Duration) (*model.Bot, error) {
+	panic("implement me")
+}
+
 func (repo *TestRepository) CreateStampPalette(string, string, model.UUIDs, uuid.UUID) (*model.StampPalette, error) {
 	panic("implement me")
 }
@@ -155,6 +205,7 @@ func NewTestRepository() *TestRepository {
 		Files:                 map[uuid.UUID]model.FileMeta{},
 		FilesACL:              map[uuid.UUID]map[uuid.UUID]bool{},
 		Webhooks:              map[uuid.UUID]model.WebhookBot{},
+		RateLimitOverrides:    map[uuid.UUID]map[string]model.RateLimitOverride{},
 	}
 	_, _ = r.CreateUser(repository.CreateUserArgs{Name: "traq", Password: "traq", Role: role.Admin})
 	return r
@@ -793,6 +844,17 @@ func (repo *TestRepository) CreateChannel(ch model.Channel, privateMembers set.U
 	ch.CreatedAt = time.Now()
 	ch.UpdatedAt = time.Now()
 	ch.DeletedAt = nil
+	if len(privateMembers) > 0 {
+		ch.IsPublic = false
+		ch.IsForced = false
+		repo.PrivateChannelMembersLock.Lock()
+		members := map[uuid.UUID]bool{}
+		for uid := range privateMembers {
+			members[uid] = true
+		}
+		repo.PrivateChannelMembers[ch.ID] = members
+		repo.PrivateChannelMembersLock.Unlock()
+	}
 	repo.ChannelsLock.Lock()
 	repo.Channels[ch.ID] = ch
 	repo.ChannelsLock.Unlock()
@@ -861,6 +923,146 @@ func (repo *TestRepository) GetPrivateChannelMemberIDs(channelID uuid.UUID) ([]u
 	return result, nil
 }
 
+func (repo *TestRepository) GetPrivateChannelsByUser(userID uuid.UUID) ([]*model.Channel, error) {
+	result := make([]*model.Channel, 0)
+	repo.PrivateChannelMembersLock.RLock()
+	defer repo.PrivateChannelMembersLock.RUnlock()
+	repo.ChannelsLock.RLock()
+	defer repo.ChannelsLock.RUnlock()
+	for cid, members := range repo.PrivateChannelMembers {
+		ch, ok := repo.Channels[cid]
+		if ok && members[userID] && ch.IsPrivateChannel() {
+			result = append(result, &ch)
+		}
+	}
+	return result, nil
+}
+
+func (repo *TestRepository) AddPrivateChannelMember(channelID, userID uuid.UUID) error {
+	if channelID == uuid.Nil || userID == uuid.Nil {
+		return repository.ErrNilID
+	}
+	repo.PrivateChannelMembersLock.Lock()
+	defer repo.PrivateChannelMembersLock.Unlock()
+	members, ok := repo.PrivateChannelMembers[channelID]
+	if !ok {
+		members = map[uuid.UUID]bool{}
+		repo.PrivateChannelMembers[channelID] = members
+	}
+	if members[userID] {
+		return repository.ErrAlreadyExists
+	}
+	members[userID] = true
+	return nil
+}
+
+func (repo *TestRepository) RemovePrivateChannelMember(channelID, userID uuid.UUID) error {
+	if channelID == uuid.Nil || userID == uuid.Nil {
+		return repository.ErrNilID
+	}
+	repo.PrivateChannelMembersLock.Lock()
+	defer repo.PrivateChannelMembersLock.Unlock()
+	if !repo.PrivateChannelMembers[channelID][userID] {
+		return repository.ErrNotFound
+	}
+	delete(repo.PrivateChannelMembers[channelID], userID)
+	return nil
+}
+
+func (repo *TestRepository) MergeChannel(fromID, toID uuid.UUID) error {
+	if fromID == uuid.Nil || toID == uuid.Nil {
+		return repository.ErrNilID
+	}
+	if fromID == toID {
+		return repository.ArgError("toID", "cannot merge a channel into itself")
+	}
+	repo.ChannelsLock.Lock()
+	defer repo.ChannelsLock.Unlock()
+	if _, ok := repo.Channels[fromID]; !ok {
+		return repository.ErrNotFound
+	}
+	if _, ok := repo.Channels[toID]; !ok {
+		return repository.ErrNotFound
+	}
+	for id, ch := range repo.Channels {
+		if ch.ParentID == fromID {
+			ch.ParentID = toID
+			repo.Channels[id] = ch
+		}
+	}
+	delete(repo.Channels, fromID)
+
+	repo.MessagesLock.Lock()
+	for id, m := range repo.Messages {
+		if m.ChannelID == fromID {
+			m.ChannelID = toID
+			repo.Messages[id] = m
+		}
+	}
+	repo.MessagesLock.Unlock()
+
+	repo.ChannelSubscribesLock.Lock()
+	if from, ok := repo.ChannelSubscribes[fromID]; ok {
+		to, ok := repo.ChannelSubscribes[toID]
+		if !ok {
+			to = make(map[uuid.UUID]model.ChannelSubscribeLevel)
+			repo.ChannelSubscribes[toID] = to
+		}
+		for uid, level := range from {
+			if level > to[uid] {
+				to[uid] = level
+			}
+		}
+		delete(repo.ChannelSubscribes, fromID)
+	}
+	repo.ChannelSubscribesLock.Unlock()
+
+	repo.StarsLock.Lock()
+	for _, chMap := range repo.Stars {
+		if chMap[fromID] {
+			delete(chMap, fromID)
+			chMap[toID] = true
+		}
+	}
+	repo.StarsLock.Unlock()
+
+	repo.WebhooksLock.Lock()
+	for id, w := range repo.Webhooks {
+		if w.ChannelID == fromID {
+			w.ChannelID = toID
+			repo.Webhooks[id] = w
+		}
+	}
+	repo.WebhooksLock.Unlock()
+	return nil
+}
+
+func (repo *TestRepository) DeleteChannel(channelID uuid.UUID) error {
+	if channelID == uuid.Nil {
+		return repository.ErrNilID
+	}
+	repo.ChannelsLock.Lock()
+	defer repo.ChannelsLock.Unlock()
+	if _, ok := repo.Channels[channelID]; !ok {
+		return repository.ErrNotFound
+	}
+	for _, ch := range repo.Channels {
+		if ch.ParentID == channelID {
+			return repository.ErrForbidden
+		}
+	}
+	repo.MessagesLock.RLock()
+	for _, m := range repo.Messages {
+		if m.ChannelID == channelID {
+			repo.MessagesLock.RUnlock()
+			return repository.ErrForbidden
+		}
+	}
+	repo.MessagesLock.RUnlock()
+	delete(repo.Channels, channelID)
+	return nil
+}
+
 func (repo *TestRepository) ChangeChannelSubscription(channelID uuid.UUID, args repository.ChangeChannelSubscriptionArgs) (on []uuid.UUID, off []uuid.UUID, err error) {
 	if channelID == uuid.Nil {
 		return nil, nil, repository.ErrNilID
@@ -986,6 +1188,37 @@ func (repo *TestRepository) CreateMessage(userID, channelID uuid.UUID, text stri
 	return m, nil
 }
 
+func (repo *TestRepository) CreateReply(userID, parentID uuid.UUID, text string) (*model.Message, error) {
+	if userID == uuid.Nil || parentID == uuid.Nil {
+		return nil, repository.ErrNilID
+	}
+
+	repo.MessagesLock.Lock()
+	defer repo.MessagesLock.Unlock()
+	parent, ok := repo.Messages[parentID]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	if parent.ParentID.Valid {
+		parent = repo.Messages[parent.ParentID.UUID]
+	}
+
+	m := &model.Message{
+		ID:        uuid.Must(uuid.NewV4()),
+		UserID:    userID,
+		ChannelID: parent.ChannelID,
+		ParentID:  optional.UUIDFrom(parent.ID),
+		Text:      text,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+		Stamps:    make([]model.MessageStamp, 0),
+	}
+	parent.ReplyCount++
+	repo.Messages[parent.ID] = parent
+	repo.Messages[m.ID] = *m
+	return m, nil
+}
+
 func (repo *TestRepository) UpdateMessage(messageID uuid.UUID, text string) error {
 	if messageID == uuid.Nil {
 		return repository.ErrNilID
@@ -1003,6 +1236,58 @@ func (repo *TestRepository) UpdateMessage(messageID uuid.UUID, text string) erro
 	return nil
 }
 
+func (repo *TestRepository) SetMessageHidden(messageID uuid.UUID, hidden bool) error {
+	if messageID == uuid.Nil {
+		return repository.ErrNilID
+	}
+
+	repo.MessagesLock.Lock()
+	defer repo.MessagesLock.Unlock()
+	m, ok := repo.Messages[messageID]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	m.Hidden = hidden
+	repo.Messages[messageID] = m
+	return nil
+}
+
+func (repo *TestRepository) SetMessageComponents(messageID uuid.UUID, components model.MessageComponents) error {
+	if messageID == uuid.Nil {
+		return repository.ErrNilID
+	}
+
+	repo.MessagesLock.Lock()
+	defer repo.MessagesLock.Unlock()
+	m, ok := repo.Messages[messageID]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	if len(components) == 0 {
+		m.Components = nil
+	} else {
+		m.Components = &model.MessageComponentSet{MessageID: messageID, Components: components}
+	}
+	repo.Messages[messageID] = m
+	return nil
+}
+
+func (repo *TestRepository) SetMessageAuthorOverride(messageID uuid.UUID, displayName string, iconFileID optional.UUID) error {
+	if messageID == uuid.Nil {
+		return repository.ErrNilID
+	}
+
+	repo.MessagesLock.Lock()
+	defer repo.MessagesLock.Unlock()
+	m, ok := repo.Messages[messageID]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	m.AuthorOverride = &model.MessageAuthorOverride{MessageID: messageID, DisplayName: displayName, IconFileID: iconFileID, CreatedAt: time.Now()}
+	repo.Messages[messageID] = m
+	return nil
+}
+
 func (repo *TestRepository) DeleteMessage(messageID uuid.UUID) error {
 	if messageID == uuid.Nil {
 		return repository.ErrNilID
@@ -1017,6 +1302,10 @@ func (repo *TestRepository) DeleteMessage(messageID uuid.UUID) error {
 	return nil
 }
 
+func (repo *TestRepository) GetMessagesByIDs(messageIDs []uuid.UUID) ([]*model.Message, error) {
+	panic("implement me")
+}
+
 func (repo *TestRepository) GetMessageByID(messageID uuid.UUID) (*model.Message, error) {
 	repo.MessagesLock.RLock()
 	m, ok := repo.Messages[messageID]
@@ -1067,6 +1356,20 @@ func (repo *TestRepository) GetMessages(query repository.MessagesQuery) (message
 	}
 	repo.MessagesLock.RUnlock()
 
+	if query.Parent != uuid.Nil || query.ExcludeReplies {
+		filtered := make([]*model.Message, 0, len(tmp))
+		for _, v := range tmp {
+			if query.Parent != uuid.Nil && v.ParentID.UUID != query.Parent {
+				continue
+			}
+			if query.ExcludeReplies && v.ParentID.Valid {
+				continue
+			}
+			filtered = append(filtered, v)
+		}
+		tmp = filtered
+	}
+
 	sort.Slice(tmp, func(i, j int) bool {
 		return tmp[i].CreatedAt.After(tmp[j].CreatedAt)
 	})
@@ -1208,7 +1511,11 @@ func (repo *TestRepository) CreateMessageReport(uuid.UUID, uuid.UUID, string) er
 	panic("implement me")
 }
 
-func (repo *TestRepository) GetMessageReports(int, int) ([]*model.MessageReport, error) {
+func (repo *TestRepository) GetMessageReport(uuid.UUID) (*model.MessageReport, error) {
+	panic("implement me")
+}
+
+func (repo *TestRepository) GetMessageReports(repository.MessageReportsQuery) ([]*model.MessageReport, bool, error) {
 	panic("implement me")
 }
 
@@ -1220,6 +1527,10 @@ func (repo *TestRepository) GetMessageReportsByReporterID(uuid.UUID) ([]*model.M
 	return []*model.MessageReport{}, nil
 }
 
+func (repo *TestRepository) HandleMessageReport(uuid.UUID, repository.HandleMessageReportArgs) error {
+	panic("implement me")
+}
+
 func (repo *TestRepository) AddStampToMessage(uuid.UUID, uuid.UUID, uuid.UUID, int) (ms *model.MessageStamp, err error) {
 	panic("implement me")
 }
@@ -1330,6 +1641,66 @@ func (repo *TestRepository) GetDeviceTokens(set.UUID) (tokens map[uuid.UUID][]st
 	panic("implement me")
 }
 
+func (repo *TestRepository) RegisterWebPushSubscription(uuid.UUID, string, string, string) error {
+	panic("implement me")
+}
+
+func (repo *TestRepository) GetWebPushSubscriptions(set.UUID) (map[uuid.UUID][]*model.WebPushSubscription, error) {
+	panic("implement me")
+}
+
+func (repo *TestRepository) DeleteWebPushSubscription(uuid.UUID, string) error {
+	panic("implement me")
+}
+
+func (repo *TestRepository) DeleteWebPushSubscriptions([]string) error {
+	panic("implement me")
+}
+
+func (repo *TestRepository) GetUserDigestSetting(uuid.UUID) (*model.UserDigestSetting, error) {
+	panic("implement me")
+}
+
+func (repo *TestRepository) UpdateUserDigestSetting(uuid.UUID, repository.UpdateUserDigestSettingArgs) error {
+	panic("implement me")
+}
+
+func (repo *TestRepository) GetDueUserDigestSettings(model.DigestFrequency, time.Time, int) ([]*model.UserDigestSetting, error) {
+	panic("implement me")
+}
+
+func (repo *TestRepository) SetUserDigestSentAt(uuid.UUID, time.Time) error {
+	panic("implement me")
+}
+
+func (repo *TestRepository) SetUserDigestRetryAt(uuid.UUID, time.Time) error {
+	panic("implement me")
+}
+
+func (repo *TestRepository) UnsubscribeUserDigest(string) error {
+	panic("implement me")
+}
+
+func (repo *TestRepository) VerifyUserDigestEmail(string) error {
+	panic("implement me")
+}
+
+func (repo *TestRepository) GetUserNotificationSetting(uuid.UUID) (*model.UserNotificationSetting, error) {
+	panic("implement me")
+}
+
+func (repo *TestRepository) GetUserNotificationSettings(set.UUID) (map[uuid.UUID]*model.UserNotificationSetting, error) {
+	panic("implement me")
+}
+
+func (repo *TestRepository) GetKeywordNotificationSettings() ([]*model.UserNotificationSetting, error) {
+	panic("implement me")
+}
+
+func (repo *TestRepository) UpdateUserNotificationSetting(uuid.UUID, repository.UpdateUserNotificationSettingArgs) error {
+	panic("implement me")
+}
+
 func (repo *TestRepository) GetFileMeta(fileID uuid.UUID) (*model.FileMeta, error) {
 	if fileID == uuid.Nil {
 		return nil, repository.ErrNotFound
@@ -1658,7 +2029,7 @@ func (repo *TestRepository) DeleteTokenByClient(string) error {
 	panic("implement me")
 }
 
-func (repo *TestRepository) CreateBot(string, string, string, uuid.UUID, uuid.UUID, string) (*model.Bot, error) {
+func (repo *TestRepository) CreateBot(string, string, string, uuid.UUID, uuid.UUID, model.BotMode, string) (*model.Bot, error) {
 	panic("implement me")
 }
 
@@ -1730,6 +2101,140 @@ func (repo *TestRepository) GetMessageClips(uuid.UUID, uuid.UUID) ([]*model.Clip
 	panic("implement me")
 }
 
+func (repo *TestRepository) CreateScheduledMessage(uuid.UUID, uuid.UUID, string, time.Time) (*model.ScheduledMessage, error) {
+	panic("implement me")
+}
+
+func (repo *TestRepository) UpdateScheduledMessage(uuid.UUID, repository.UpdateScheduledMessageArgs) error {
+	panic("implement me")
+}
+
+func (repo *TestRepository) GetScheduledMessage(uuid.UUID) (*model.ScheduledMessage, error) {
+	panic("implement me")
+}
+
+func (repo *TestRepository) GetScheduledMessages(repository.ScheduledMessagesQuery) ([]*model.ScheduledMessage, error) {
+	panic("implement me")
+}
+
+func (repo *TestRepository) DeleteScheduledMessage(uuid.UUID) error {
+	panic("implement me")
+}
+
+func (repo *TestRepository) DeliverScheduledMessage(uuid.UUID) (*model.Message, error) {
+	panic("implement me")
+}
+
 func (repo *TestRepository) GetFileMetas(repository.FilesQuery) (result []*model.FileMeta, more bool, err error) {
 	panic("implement me")
 }
+
+func (repo *TestRepository) CreateUserRole(string, bool, []string, []string) (*model.UserRole, error) {
+	panic("implement me")
+}
+
+func (repo *TestRepository) GetUserRoles() ([]*model.UserRole, error) {
+	panic("implement me")
+}
+
+func (repo *TestRepository) GetUserRole(string) (*model.UserRole, error) {
+	panic("implement me")
+}
+
+func (repo *TestRepository) UpdateUserRole(string, repository.UpdateUserRoleArgs) error {
+	panic("implement me")
+}
+
+func (repo *TestRepository) DeleteUserRole(string) error {
+	panic("implement me")
+}
+
+func (repo *TestRepository) SetChannelRole(uuid.UUID, uuid.UUID, string) error {
+	panic("implement me")
+}
+
+func (repo *TestRepository) DeleteChannelRole(uuid.UUID, uuid.UUID) error {
+	panic("implement me")
+}
+
+func (repo *TestRepository) GetChannelRoles(uuid.UUID) ([]*model.ChannelRole, error) {
+	panic("implement me")
+}
+
+func (repo *TestRepository) SetRateLimitOverride(userID uuid.UUID, action string, rate float64, burst int) error {
+	if userID == uuid.Nil {
+		return repository.ErrNilID
+	}
+	if len(action) == 0 {
+		return repository.ArgError("action", "Action is empty")
+	}
+	repo.RateLimitOverridesLock.Lock()
+	defer repo.RateLimitOverridesLock.Unlock()
+	overrides, ok := repo.RateLimitOverrides[userID]
+	if !ok {
+		overrides = map[string]model.RateLimitOverride{}
+		repo.RateLimitOverrides[userID] = overrides
+	}
+	o, ok := overrides[action]
+	if !ok {
+		o = model.RateLimitOverride{UserID: userID, Action: action, CreatedAt: time.Now()}
+	}
+	o.Rate = rate
+	o.Burst = burst
+	o.UpdatedAt = time.Now()
+	overrides[action] = o
+	return nil
+}
+
+func (repo *TestRepository) DeleteRateLimitOverride(userID uuid.UUID, action string) error {
+	if userID == uuid.Nil {
+		return repository.ErrNilID
+	}
+	if len(action) == 0 {
+		return repository.ArgError("action", "Action is empty")
+	}
+	repo.RateLimitOverridesLock.Lock()
+	defer repo.RateLimitOverridesLock.Unlock()
+	if _, ok := repo.RateLimitOverrides[userID][action]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(repo.RateLimitOverrides[userID], action)
+	return nil
+}
+
+func (repo *TestRepository) GetRateLimitOverrides() ([]*model.RateLimitOverride, error) {
+	repo.RateLimitOverridesLock.RLock()
+	defer repo.RateLimitOverridesLock.RUnlock()
+	result := make([]*model.RateLimitOverride, 0)
+	for _, overrides := range repo.RateLimitOverrides {
+		for _, o := range overrides {
+			o := o
+			result = append(result, &o)
+		}
+	}
+	return result, nil
+}
+
+func (repo *TestRepository) CreateOutgoingWebhook(webhookID, channelID uuid.UUID, triggerType model.OutgoingWebhookTriggerType, trigger, url string, creatorID uuid.UUID) (*model.OutgoingWebhook, error) {
+	panic("implement me")
+}
+
+func (repo *TestRepository) UpdateOutgoingWebhook(id uuid.UUID, args repository.UpdateOutgoingWebhookArgs) error {
+	panic("implement me")
+}
+
+func (repo *TestRepository) DeleteOutgoingWebhook(id uuid.UUID) error {
+	panic("implement me")
+}
+
+func (repo *TestRepository) GetOutgoingWebhook(id uuid.UUID) (*model.OutgoingWebhook, error) {
+	panic("implement me")
+}
+
+func (repo *TestRepository) GetOutgoingWebhooksByWebhookID(webhookID uuid.UUID) ([]*model.OutgoingWebhook, error) {
+	panic("implement me")
+}
+
+func (repo *TestRepository) GetOutgoingWebhooksByChannelID(channelID uuid.UUID) ([]*model.OutgoingWebhook, error) {
+	panic("implement me")
+}
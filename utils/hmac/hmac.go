@@ -4,18 +4,104 @@ import (
 	"crypto/hmac"
 	"crypto/sha1"
 	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // SHA1 HMAC-SHA-1を計算します
 func SHA1(data []byte, secret string) []byte {
-	mac := hmac.New(sha1.New, []byte(secret))
+	mac := NewSHA1(secret)
 	_, _ = mac.Write(data)
 	return mac.Sum(nil)
 }
 
 // SHA256 HMAC-SHA-256を計算します
 func SHA256(data []byte, secret string) []byte {
-	mac := hmac.New(sha256.New, []byte(secret))
+	mac := NewSHA256(secret)
 	_, _ = mac.Write(data)
 	return mac.Sum(nil)
 }
+
+// NewSHA1 HMAC-SHA-1を逐次計算するhash.Hashを返します
+func NewSHA1(secret string) hash.Hash {
+	return hmac.New(sha1.New, []byte(secret))
+}
+
+// NewSHA256 HMAC-SHA-256を逐次計算するhash.Hashを返します
+func NewSHA256(secret string) hash.Hash {
+	return hmac.New(sha256.New, []byte(secret))
+}
+
+var (
+	// ErrInvalidSignature 署名が不正です
+	ErrInvalidSignature = errors.New("invalid signature")
+	// ErrSignatureExpired 署名のタイムスタンプが許容範囲外です
+	ErrSignatureExpired = errors.New("signature timestamp is out of tolerance")
+)
+
+// SignTimestamp タイムスタンプ付きのリクエストボディの署名を生成します
+//
+// 値は "t=<UNIX時刻>,v1=<署名>" の形式で、署名は "<UNIX時刻>.<ボディ>" のHMAC-SHA256を16進数表記したものです。
+// secretsが複数指定された場合は、それぞれの署名を v1 として併記します。
+func SignTimestamp(t time.Time, body []byte, secrets ...string) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	data := make([]byte, 0, len(ts)+1+len(body))
+	data = append(data, ts...)
+	data = append(data, '.')
+	data = append(data, body...)
+
+	elems := []string{"t=" + ts}
+	for _, secret := range secrets {
+		elems = append(elems, "v1="+hex.EncodeToString(SHA256(data, secret)))
+	}
+	return strings.Join(elems, ",")
+}
+
+// VerifyTimestamp SignTimestampで生成された署名を検証します
+//
+// いずれかの v1 がsecretによる署名と一致し、タイムスタンプがnowの前後tolerance以内の場合にnilを返します。
+// 署名が一致しない場合はErrInvalidSignatureを、タイムスタンプが範囲外の場合はErrSignatureExpiredを返します。
+func VerifyTimestamp(signature string, body []byte, now time.Time, tolerance time.Duration, secret string) error {
+	var (
+		ts   string
+		sigs [][]byte
+	)
+	for _, elem := range strings.Split(signature, ",") {
+		kv := strings.SplitN(elem, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "t":
+			ts = kv[1]
+		case "v1":
+			if sig, err := hex.DecodeString(kv[1]); err == nil {
+				sigs = append(sigs, sig)
+			}
+		}
+	}
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	data := make([]byte, 0, len(ts)+1+len(body))
+	data = append(data, ts...)
+	data = append(data, '.')
+	data = append(data, body...)
+	expected := SHA256(data, secret)
+
+	for _, sig := range sigs {
+		if hmac.Equal(expected, sig) {
+			if d := now.Sub(time.Unix(unix, 0)); d > tolerance || d < -tolerance {
+				return ErrSignatureExpired
+			}
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
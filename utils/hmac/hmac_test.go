@@ -5,6 +5,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestCalcSHA1Signature(t *testing.T) {
@@ -93,6 +94,39 @@ func TestCalcSHA256Signature(t *testing.T) {
 	}
 }
 
+func TestSignTimestamp(t *testing.T) {
+	t.Parallel()
+
+	body := []byte(`{"a":"b"}`)
+	at := time.Unix(1577836800, 0)
+	mac := func(secret string) string {
+		return hex.EncodeToString(SHA256([]byte("1577836800."+string(body)), secret))
+	}
+
+	assert.Equal(t, "t=1577836800", SignTimestamp(at, body))
+	assert.Equal(t, "t=1577836800,v1="+mac("new"), SignTimestamp(at, body, "new"))
+	assert.Equal(t, "t=1577836800,v1="+mac("new")+",v1="+mac("old"), SignTimestamp(at, body, "new", "old"))
+}
+
+func TestVerifyTimestamp(t *testing.T) {
+	t.Parallel()
+
+	body := []byte(`{"a":"b"}`)
+	at := time.Unix(1577836800, 0)
+	tolerance := 30 * time.Second
+
+	assert.NoError(t, VerifyTimestamp(SignTimestamp(at, body, "secret"), body, at, tolerance, "secret"))
+	assert.NoError(t, VerifyTimestamp(SignTimestamp(at, body, "old", "secret"), body, at.Add(tolerance), tolerance, "secret"))
+	assert.NoError(t, VerifyTimestamp(SignTimestamp(at, body, "secret"), body, at.Add(-tolerance), tolerance, "secret"))
+	assert.Equal(t, ErrSignatureExpired, VerifyTimestamp(SignTimestamp(at, body, "secret"), body, at.Add(tolerance+time.Second), tolerance, "secret"))
+	assert.Equal(t, ErrSignatureExpired, VerifyTimestamp(SignTimestamp(at, body, "secret"), body, at.Add(-tolerance-time.Second), tolerance, "secret"))
+	assert.Equal(t, ErrInvalidSignature, VerifyTimestamp(SignTimestamp(at, body, "wrong"), body, at, tolerance, "secret"))
+	assert.Equal(t, ErrInvalidSignature, VerifyTimestamp(SignTimestamp(at, body, "secret"), []byte(`{"a":"c"}`), at, tolerance, "secret"))
+	assert.Equal(t, ErrInvalidSignature, VerifyTimestamp(SignTimestamp(at, body), body, at, tolerance, "secret"))
+	assert.Equal(t, ErrInvalidSignature, VerifyTimestamp("v1="+hex.EncodeToString(SHA256(body, "secret")), body, at, tolerance, "secret"))
+	assert.Equal(t, ErrInvalidSignature, VerifyTimestamp("", body, at, tolerance, "secret"))
+}
+
 func mustHexDecode(h string) []byte {
 	b, _ := hex.DecodeString(h)
 	return b
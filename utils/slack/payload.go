@@ -0,0 +1,188 @@
+package slack
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Payload Slack互換Incoming Webhookのペイロード
+//
+// https://api.slack.com/messaging/webhooks
+type Payload struct {
+	Text        string       `json:"text"`
+	Username    string       `json:"username"`
+	IconEmoji   string       `json:"icon_emoji"`
+	IconURL     string       `json:"icon_url"`
+	Channel     string       `json:"channel"`
+	Attachments []Attachment `json:"attachments"`
+	Blocks      []Block      `json:"blocks"`
+}
+
+// Attachment Slackのメッセージアタッチメント
+type Attachment struct {
+	Fallback   string  `json:"fallback"`
+	Color      string  `json:"color"`
+	Pretext    string  `json:"pretext"`
+	AuthorName string  `json:"author_name"`
+	AuthorLink string  `json:"author_link"`
+	Title      string  `json:"title"`
+	TitleLink  string  `json:"title_link"`
+	Text       string  `json:"text"`
+	Fields     []Field `json:"fields"`
+	Footer     string  `json:"footer"`
+}
+
+// Field Slackのメッセージアタッチメントのフィールド
+type Field struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+	Short bool   `json:"short"`
+}
+
+// Block SlackのBlock Kitのブロック
+//
+// section, header, divider, contextブロックのテキストのみを扱います。
+type Block struct {
+	Type     string       `json:"type"`
+	Text     *TextObject  `json:"text"`
+	Fields   []TextObject `json:"fields"`
+	Elements []TextObject `json:"elements"`
+}
+
+// TextObject SlackのBlock Kitのテキストオブジェクト
+type TextObject struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// IconStampName icon_emojiで指定されたスタンプ名を返します
+func (p *Payload) IconStampName() string {
+	return strings.Trim(p.IconEmoji, ":")
+}
+
+// Markdown ペイロードをtraQのメッセージ本文に変換します
+//
+// blocksが指定されている場合はtextの代わりにblocksを用います。
+func (p *Payload) Markdown() string {
+	var parts []string
+	if len(p.Blocks) > 0 {
+		for _, b := range p.Blocks {
+			if s := b.markdown(); len(s) > 0 {
+				parts = append(parts, s)
+			}
+		}
+	} else if len(p.Text) > 0 {
+		parts = append(parts, ConvertMrkdwn(p.Text))
+	}
+	for _, a := range p.Attachments {
+		if s := a.markdown(); len(s) > 0 {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+func (b *Block) markdown() string {
+	switch b.Type {
+	case "header":
+		if b.Text != nil {
+			return "## " + b.Text.markdown()
+		}
+	case "divider":
+		return "---"
+	case "section":
+		var lines []string
+		if b.Text != nil {
+			lines = append(lines, b.Text.markdown())
+		}
+		for _, f := range b.Fields {
+			lines = append(lines, f.markdown())
+		}
+		return strings.Join(lines, "\n")
+	case "context":
+		var elems []string
+		for _, e := range b.Elements {
+			if len(e.Text) > 0 {
+				elems = append(elems, e.markdown())
+			}
+		}
+		return strings.Join(elems, " ")
+	}
+	return ""
+}
+
+func (t *TextObject) markdown() string {
+	if t.Type == "mrkdwn" {
+		return ConvertMrkdwn(t.Text)
+	}
+	return t.Text
+}
+
+func (a *Attachment) markdown() string {
+	var lines []string
+	if len(a.Pretext) > 0 {
+		lines = append(lines, ConvertMrkdwn(a.Pretext))
+	}
+	if len(a.AuthorName) > 0 {
+		lines = append(lines, link(a.AuthorName, a.AuthorLink))
+	}
+	if len(a.Title) > 0 {
+		lines = append(lines, "**"+link(a.Title, a.TitleLink)+"**")
+	}
+	if len(a.Text) > 0 {
+		lines = append(lines, ConvertMrkdwn(a.Text))
+	}
+	for _, f := range a.Fields {
+		if len(f.Title) > 0 {
+			lines = append(lines, "**"+f.Title+"**")
+		}
+		if len(f.Value) > 0 {
+			lines = append(lines, ConvertMrkdwn(f.Value))
+		}
+	}
+	if len(a.Footer) > 0 {
+		lines = append(lines, ConvertMrkdwn(a.Footer))
+	}
+	if len(lines) == 0 && len(a.Fallback) > 0 {
+		lines = append(lines, a.Fallback)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func link(text, url string) string {
+	if len(url) == 0 {
+		return text
+	}
+	return "[" + text + "](" + url + ")"
+}
+
+var (
+	mrkdwnLinkRegex   = regexp.MustCompile(`<([^<>|]+)(?:\|([^<>]+))?>`)
+	mrkdwnBoldRegex   = regexp.MustCompile(`(^|[\s(_~])\*([^*\n]+)\*`)
+	mrkdwnStrikeRegex = regexp.MustCompile(`(^|[\s(_*])~([^~\n]+)~`)
+	htmlEntities      = strings.NewReplacer("&lt;", "<", "&gt;", ">", "&amp;", "&")
+)
+
+// ConvertMrkdwn Slackのmrkdwn記法をtraQのMarkdownに変換します
+func ConvertMrkdwn(s string) string {
+	s = mrkdwnLinkRegex.ReplaceAllStringFunc(s, func(m string) string {
+		sub := mrkdwnLinkRegex.FindStringSubmatch(m)
+		target, label := sub[1], sub[2]
+		switch {
+		case strings.HasPrefix(target, "http://"), strings.HasPrefix(target, "https://"), strings.HasPrefix(target, "mailto:"):
+			if len(label) == 0 {
+				return target
+			}
+			return "[" + label + "](" + target + ")"
+		case len(label) > 0:
+			return label
+		case strings.HasPrefix(target, "!"):
+			return "@" + target[1:]
+		default:
+			return target
+		}
+	})
+	s = mrkdwnBoldRegex.ReplaceAllString(s, "$1**$2**")
+	s = mrkdwnStrikeRegex.ReplaceAllString(s, "$1~~$2~~")
+	return htmlEntities.Replace(s)
+}
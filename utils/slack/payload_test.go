@@ -0,0 +1,76 @@
+package slack
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestConvertMrkdwn(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		in  string
+		out string
+	}{
+		{"plain text", "plain text"},
+		{"<https://example.com|example>", "[example](https://example.com)"},
+		{"see <https://example.com>", "see https://example.com"},
+		{"<!here> deploy", "@here deploy"},
+		{"<!subteam^S123|@team> hi", "@team hi"},
+		{"*bold* and ~strike~ and _italic_", "**bold** and ~~strike~~ and _italic_"},
+		{"a*b*c", "a*b*c"},
+		{"1 &lt; 2 &amp;&amp; 3 &gt; 2", "1 < 2 && 3 > 2"},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.out, ConvertMrkdwn(c.in), c.in)
+	}
+}
+
+func TestPayload_IconStampName(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "ghost", (&Payload{IconEmoji: ":ghost:"}).IconStampName())
+	assert.Equal(t, "", (&Payload{}).IconStampName())
+}
+
+func TestPayload_Markdown(t *testing.T) {
+	t.Parallel()
+
+	t.Run("text", func(t *testing.T) {
+		t.Parallel()
+		p := &Payload{Text: "*Build* <https://ci.example.com/1|#1> passed"}
+		assert.Equal(t, "**Build** [#1](https://ci.example.com/1) passed", p.Markdown())
+	})
+
+	t.Run("attachments", func(t *testing.T) {
+		t.Parallel()
+		p := &Payload{
+			Text: "alert",
+			Attachments: []Attachment{
+				{
+					Title:     "CPU usage",
+					TitleLink: "https://grafana.example.com/d/1",
+					Text:      "usage is *high*",
+					Fields:    []Field{{Title: "host", Value: "web-1"}},
+				},
+				{Fallback: "fallback only"},
+			},
+		}
+		assert.Equal(t, "alert\n\n**[CPU usage](https://grafana.example.com/d/1)**\nusage is **high**\n**host**\nweb-1\n\nfallback only", p.Markdown())
+	})
+
+	t.Run("blocks", func(t *testing.T) {
+		t.Parallel()
+		p := &Payload{
+			Text: "ignored",
+			Blocks: []Block{
+				{Type: "header", Text: &TextObject{Type: "plain_text", Text: "Deploy"}},
+				{Type: "section", Text: &TextObject{Type: "mrkdwn", Text: "*done*"}, Fields: []TextObject{{Type: "plain_text", Text: "env: prod"}}},
+				{Type: "divider"},
+				{Type: "context", Elements: []TextObject{{Type: "mrkdwn", Text: "by ci"}, {Type: "image"}}},
+				{Type: "actions"},
+			},
+		}
+		assert.Equal(t, "## Deploy\n\n**done**\nenv: prod\n\n---\n\nby ci", p.Markdown())
+	})
+}
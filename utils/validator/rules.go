@@ -112,3 +112,25 @@ var ClipFolderNameRuleRequired = append([]vd.Rule{
 var ClipFolderDescriptionRule = []vd.Rule{
 	vd.RuneLength(0, 1000),
 }
+
+// UserRoleNameRule ユーザーロール名バリデーションルール
+var UserRoleNameRule = []vd.Rule{
+	vd.Match(UserRoleNameRegex).Error("must contain [a-zA-Z0-9_] only"),
+	vd.RuneLength(1, 30),
+}
+
+// UserRoleNameRuleRequired ユーザーロール名バリデーションルール with Required
+var UserRoleNameRuleRequired = append([]vd.Rule{
+	vd.Required,
+}, UserRoleNameRule...)
+
+// BotCommandNameRule Botのスラッシュコマンド名バリデーションルール
+var BotCommandNameRule = []vd.Rule{
+	vd.Match(BotCommandNameRegex).Error("must contain [a-zA-Z0-9_-] only"),
+	vd.RuneLength(1, 32),
+}
+
+// BotCommandNameRuleRequired Botのスラッシュコマンド名バリデーションルール with Required
+var BotCommandNameRuleRequired = append([]vd.Rule{
+	vd.Required,
+}, BotCommandNameRule...)
@@ -19,6 +19,8 @@ var (
 	PKCERegex = regexp.MustCompile("^[a-zA-Z0-9~._-]{43,128}$")
 	// UserRoleNameRegex ユーザーロール名の正規表現
 	UserRoleNameRegex = regexp.MustCompile(`^[a-zA-Z0-9_]{1,30}$`)
+	// BotCommandNameRegex Botのスラッシュコマンド名の正規表現
+	BotCommandNameRegex = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,32}$`)
 )
 
 // NotInternalURL 内部ネットワーク宛のURLでない
@@ -0,0 +1,136 @@
+package webpush
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"golang.org/x/crypto/hkdf"
+	"io"
+	"strings"
+)
+
+const (
+	saltSize   = 16
+	authSize   = 16
+	keySize    = 65
+	tagSize    = 16
+	recordSize = 4096
+	headerSize = saltSize + 4 + 1 + keySize
+
+	// MaxPayloadSize 暗号化できるペイロードの最大バイト数
+	MaxPayloadSize = recordSize - headerSize - tagSize - 1
+)
+
+var (
+	// ErrInvalidKey 購読の鍵が不正です
+	ErrInvalidKey = errors.New("invalid subscription key")
+	// ErrPayloadTooLarge ペイロードが大きすぎます
+	ErrPayloadTooLarge = errors.New("payload too large")
+)
+
+// Encrypt RFC 8291に従って、購読の鍵でペイロードをaes128gcmで暗号化します
+//
+// p256dh, authはブラウザのPushSubscriptionが返すBase64URLエンコードされた鍵です。
+// 返り値はそのままプッシュサービスに送信するリクエストボディになります。
+func Encrypt(p256dh, auth string, payload []byte) ([]byte, error) {
+	uaPublic, err := decodeBase64URL(p256dh)
+	if err != nil || len(uaPublic) != keySize {
+		return nil, ErrInvalidKey
+	}
+	authSecret, err := decodeBase64URL(auth)
+	if err != nil || len(authSecret) != authSize {
+		return nil, ErrInvalidKey
+	}
+	if len(payload) > MaxPayloadSize {
+		return nil, ErrPayloadTooLarge
+	}
+
+	salt := make([]byte, saltSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, err
+	}
+	asPrivate, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return encrypt(uaPublic, authSecret, asPrivate, salt, payload)
+}
+
+func encrypt(uaPublic, authSecret []byte, asPrivate *ecdsa.PrivateKey, salt, payload []byte) ([]byte, error) {
+	asPublic := elliptic.Marshal(elliptic.P256(), asPrivate.X, asPrivate.Y)
+	secret, err := sharedSecret(asPrivate.D.Bytes(), uaPublic)
+	if err != nil {
+		return nil, err
+	}
+	cek, nonce, err := deriveKey(secret, uaPublic, asPublic, authSecret, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	// 単一レコードなので末尾に区切り文字0x02を付ける
+	plaintext := make([]byte, len(payload)+1)
+	copy(plaintext, payload)
+	plaintext[len(payload)] = 0x02
+
+	body := make([]byte, headerSize, headerSize+len(plaintext)+tagSize)
+	copy(body, salt)
+	binary.BigEndian.PutUint32(body[saltSize:], recordSize)
+	body[saltSize+4] = keySize
+	copy(body[saltSize+5:], asPublic)
+	return gcm.Seal(body, nonce, plaintext, nil), nil
+}
+
+// sharedSecret P-256上のECDHで共有鍵を計算します
+func sharedSecret(priv, pub []byte) ([]byte, error) {
+	curve := elliptic.P256()
+	x, y := elliptic.Unmarshal(curve, pub)
+	if x == nil {
+		return nil, ErrInvalidKey
+	}
+	sx, _ := curve.ScalarMult(x, y, priv)
+	secret := make([]byte, 32)
+	sx.FillBytes(secret)
+	return secret, nil
+}
+
+// deriveKey ECDHの共有鍵からコンテンツ暗号鍵とnonceを導出します
+func deriveKey(secret, uaPublic, asPublic, authSecret, salt []byte) (cek, nonce []byte, err error) {
+	keyInfo := make([]byte, 0, 14+2*keySize)
+	keyInfo = append(keyInfo, "WebPush: info\x00"...)
+	keyInfo = append(keyInfo, uaPublic...)
+	keyInfo = append(keyInfo, asPublic...)
+	ikm := make([]byte, 32)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, secret, authSecret, keyInfo), ikm); err != nil {
+		return nil, nil, err
+	}
+
+	prk := hkdf.Extract(sha256.New, ikm, salt)
+	cek = make([]byte, 16)
+	if _, err := io.ReadFull(hkdf.Expand(sha256.New, prk, []byte("Content-Encoding: aes128gcm\x00")), cek); err != nil {
+		return nil, nil, err
+	}
+	nonce = make([]byte, 12)
+	if _, err := io.ReadFull(hkdf.Expand(sha256.New, prk, []byte("Content-Encoding: nonce\x00")), nonce); err != nil {
+		return nil, nil, err
+	}
+	return cek, nonce, nil
+}
+
+func decodeBase64URL(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
@@ -0,0 +1,95 @@
+package webpush
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func mustDecode(s string) []byte {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func TestEncrypt_RFC8291(t *testing.T) {
+	t.Parallel()
+
+	// test vector from https://tools.ietf.org/html/rfc8291#section-5
+	asPrivate := &ecdsa.PrivateKey{D: new(big.Int).SetBytes(mustDecode("yfWPiYE-n46HLnH0KqZOF1fJJU3MYrct3AELtAQ-oRw"))}
+	asPrivate.Curve = elliptic.P256()
+	asPrivate.X, asPrivate.Y = asPrivate.Curve.ScalarBaseMult(asPrivate.D.Bytes())
+
+	body, err := encrypt(
+		mustDecode("BCVxsr7N_eNgVRqvHtD0zTZsEc6-VV-JvLexhqUzORcxaOzi6-AYWXvTBHm4bjyPjs7Vd8pZGH6SRpkNtoIAiw4"),
+		mustDecode("BTBZMqHH6r4Tts7J_aSIgg"),
+		asPrivate,
+		mustDecode("DGv6ra1nlYgDCS1FRnbzlw"),
+		[]byte("When I grow up, I want to be a watermelon"),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "DGv6ra1nlYgDCS1FRnbzlwAAEABBBP4z9KsN6nGRTbVYI_c7VJSPQTBtkgcy27mlmlMoZIIgDll6e3vCYLocInmYWAmS6TlzAC8wEqKK6PBru3jl7A_yl95bQpu6cVPTpK4Mqgkf1CXztLVBSt2Ks3oZwbuwXPXLWyouBWLVWGNWQexSgSxsj_Qulcy4a-fN", base64.RawURLEncoding.EncodeToString(body))
+}
+
+func TestEncrypt(t *testing.T) {
+	t.Parallel()
+
+	uaPrivate, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	uaPublic := elliptic.Marshal(elliptic.P256(), uaPrivate.X, uaPrivate.Y)
+	authSecret := make([]byte, authSize)
+	_, _ = rand.Read(authSecret)
+	p256dh := base64.RawURLEncoding.EncodeToString(uaPublic)
+	auth := base64.URLEncoding.EncodeToString(authSecret) // パディング付きも受け付ける
+
+	t.Run("invalid key", func(t *testing.T) {
+		t.Parallel()
+		_, err := Encrypt("invalid", auth, []byte("test"))
+		assert.Equal(t, ErrInvalidKey, err)
+		_, err = Encrypt(p256dh, "", []byte("test"))
+		assert.Equal(t, ErrInvalidKey, err)
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		t.Parallel()
+		_, err := Encrypt(p256dh, auth, make([]byte, MaxPayloadSize+1))
+		assert.Equal(t, ErrPayloadTooLarge, err)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		payload := []byte(strings.Repeat("a", MaxPayloadSize))
+		body, err := Encrypt(p256dh, auth, payload)
+		require.NoError(t, err)
+		assert.Len(t, body, recordSize)
+
+		// ブラウザ側と同じ手順で復号できる
+		salt := body[:saltSize]
+		assert.EqualValues(t, recordSize, binary.BigEndian.Uint32(body[saltSize:]))
+		require.EqualValues(t, keySize, body[saltSize+4])
+		asPublic := body[saltSize+5 : headerSize]
+
+		secret, err := sharedSecret(uaPrivate.D.Bytes(), asPublic)
+		require.NoError(t, err)
+		cek, nonce, err := deriveKey(secret, uaPublic, asPublic, authSecret, salt)
+		require.NoError(t, err)
+		block, err := aes.NewCipher(cek)
+		require.NoError(t, err)
+		gcm, err := cipher.NewGCM(block)
+		require.NoError(t, err)
+		plaintext, err := gcm.Open(nil, nonce, body[headerSize:], nil)
+		require.NoError(t, err)
+		assert.Equal(t, append(payload, 0x02), plaintext)
+	})
+}
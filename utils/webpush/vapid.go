@@ -0,0 +1,65 @@
+package webpush
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/dgrijalva/jwt-go"
+	"net/url"
+	"time"
+)
+
+// vapidTokenExpiration VAPIDトークンの有効期間 (最大24時間)
+const vapidTokenExpiration = 12 * time.Hour
+
+// VAPID RFC 8292 Voluntary Application Server Identification
+type VAPID struct {
+	priv    *ecdsa.PrivateKey
+	subject string
+}
+
+// NewVAPID PEM形式のP-256秘密鍵からVAPIDを生成します
+//
+// subjectはプッシュサービスがアプリケーションサーバーの運営者に連絡するための
+// mailto:またはhttps:のURIです。
+func NewVAPID(privPEM []byte, subject string) (*VAPID, error) {
+	priv, err := jwt.ParseECPrivateKeyFromPEM(bytes.TrimSpace(privPEM))
+	if err != nil {
+		return nil, err
+	}
+	if priv.Curve != elliptic.P256() {
+		return nil, errors.New("vapid key must be on P-256 curve")
+	}
+	return &VAPID{priv: priv, subject: subject}, nil
+}
+
+// PublicKey Base64URLエンコードされた非圧縮形式の公開鍵を返します
+//
+// ブラウザのPushManager.subscribeのapplicationServerKeyに指定する値です。
+func (v *VAPID) PublicKey() string {
+	return base64.RawURLEncoding.EncodeToString(elliptic.Marshal(v.priv.Curve, v.priv.X, v.priv.Y))
+}
+
+// Authorization 指定したエンドポイントへのリクエストに付けるAuthorizationヘッダーの値を生成します
+func (v *VAPID) Authorization(endpoint string, now time.Time) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", err
+	}
+	if len(u.Scheme) == 0 || len(u.Host) == 0 {
+		return "", fmt.Errorf("invalid endpoint: %s", endpoint)
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.StandardClaims{
+		Audience:  u.Scheme + "://" + u.Host,
+		ExpiresAt: now.Add(vapidTokenExpiration).Unix(),
+		Subject:   v.subject,
+	}).SignedString(v.priv)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("vapid t=%s, k=%s", token, v.PublicKey()), nil
+}
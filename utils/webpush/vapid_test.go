@@ -0,0 +1,66 @@
+package webpush
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVAPID(t *testing.T) {
+	t.Parallel()
+
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalECPrivateKey(priv)
+	require.NoError(t, err)
+	privPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})
+
+	t.Run("invalid key", func(t *testing.T) {
+		t.Parallel()
+		_, err := NewVAPID([]byte("invalid"), "mailto:admin@example.com")
+		assert.Error(t, err)
+	})
+
+	v, err := NewVAPID(privPEM, "mailto:admin@example.com")
+	require.NoError(t, err)
+
+	pub, err := base64.RawURLEncoding.DecodeString(v.PublicKey())
+	require.NoError(t, err)
+	assert.Equal(t, elliptic.Marshal(elliptic.P256(), priv.X, priv.Y), pub)
+
+	t.Run("invalid endpoint", func(t *testing.T) {
+		t.Parallel()
+		_, err := v.Authorization("/push", time.Now())
+		assert.Error(t, err)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		now := time.Now()
+		header, err := v.Authorization("https://push.example.com/send/abcdef?x=y", now)
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(header, "vapid t="))
+
+		parts := strings.SplitN(strings.TrimPrefix(header, "vapid t="), ", k=", 2)
+		require.Len(t, parts, 2)
+		assert.Equal(t, v.PublicKey(), parts[1])
+
+		var claims jwt.StandardClaims
+		_, err = jwt.ParseWithClaims(parts[0], &claims, func(token *jwt.Token) (interface{}, error) {
+			return &priv.PublicKey, nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "https://push.example.com", claims.Audience)
+		assert.Equal(t, "mailto:admin@example.com", claims.Subject)
+		assert.Equal(t, now.Add(vapidTokenExpiration).Unix(), claims.ExpiresAt)
+	})
+}